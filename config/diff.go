@@ -0,0 +1,265 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RedactedValue 敏感字段在变更记录中的占位值
+const RedactedValue = "******"
+
+// Change 一条字段级别的变更记录
+// 实用场景: 配置热更新回调中记录“到底改了什么”，敏感字段（`secret:"true"`）的值会被脱敏
+type Change struct {
+	Path     string      // 字段路径，如 "db.addr"、"servers[0].host"
+	Old      interface{} // 旧值，新增字段时为 nil
+	New      interface{} // 新值，删除字段时为 nil
+	Redacted bool        // 是否已脱敏
+}
+
+// String 输出便于日志记录的变更描述
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Path, c.Old, c.New)
+}
+
+// Diff 比较两份配置（通常为同类型结构体或其指针），返回字段级变更列表
+// 路径优先使用 yaml/json tag 名称；带 `secret:"true"` tag 的字段与 Secret 类型的值会被替换为 RedactedValue
+// 使用示例：
+//
+//	for _, c := range config.Diff(oldCfg, newCfg) {
+//		log.Printf("config changed: %s", c)
+//	}
+func Diff(old, new interface{}) []Change {
+	var changes []Change
+	diffValue(&changes, "", reflect.ValueOf(old), reflect.ValueOf(new), false)
+	return changes
+}
+
+// diffValue 递归比较两个值
+func diffValue(changes *[]Change, path string, a, b reflect.Value, secret bool) {
+	a, b = indirect(a), indirect(b)
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() || b.IsValid() {
+			diffAddRemove(changes, path, a, b, secret)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		// 类型不同无法逐字段比较，任一侧含敏感字段时整体脱敏
+		addChange(changes, path, a, b, secret || containsSecret(a) || containsSecret(b))
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Struct:
+		if !hasExportedField(a.Type()) {
+			break
+		}
+		for i := 0; i < a.NumField(); i++ {
+			f := a.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			diffValue(changes, joinPath(path, fieldName(f)), a.Field(i), b.Field(i), secret || isSecretField(f))
+		}
+		return
+	case reflect.Map:
+		keys := make(map[string]reflect.Value)
+		for _, k := range a.MapKeys() {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		for _, k := range b.MapKeys() {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			k := keys[name]
+			diffValue(changes, joinPath(path, name), a.MapIndex(k), b.MapIndex(k), secret)
+		}
+		return
+	case reflect.Slice, reflect.Array:
+		n := a.Len()
+		if b.Len() > n {
+			n = b.Len()
+		}
+		for i := 0; i < n; i++ {
+			var av, bv reflect.Value
+			if i < a.Len() {
+				av = a.Index(i)
+			}
+			if i < b.Len() {
+				bv = b.Index(i)
+			}
+			diffValue(changes, fmt.Sprintf("%s[%d]", path, i), av, bv, secret)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		addChange(changes, path, a, b, secret)
+	}
+}
+
+// diffAddRemove 处理整体新增或删除的值（a、b 恰有一个无效）：结构体、map、切片逐项展开，
+// 使其中的敏感字段同样经过脱敏，而不是把整个复合值原样输出
+func diffAddRemove(changes *[]Change, path string, a, b reflect.Value, secret bool) {
+	v := a
+	if !v.IsValid() {
+		v = b
+	}
+	pair := func(x reflect.Value) (reflect.Value, reflect.Value) {
+		if a.IsValid() {
+			return x, reflect.Value{}
+		}
+		return reflect.Value{}, x
+	}
+	if secret || v.Type() == secretType {
+		addChange(changes, path, a, b, true)
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if !hasExportedField(v.Type()) {
+			break
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			x, y := pair(v.Field(i))
+			diffValue(changes, joinPath(path, fieldName(f)), x, y, isSecretField(f))
+		}
+		return
+	case reflect.Map:
+		if v.Len() == 0 {
+			break
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface()) })
+		for _, k := range keys {
+			x, y := pair(v.MapIndex(k))
+			diffValue(changes, joinPath(path, fmt.Sprint(k.Interface())), x, y, false)
+		}
+		return
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			break
+		}
+		for i := 0; i < v.Len(); i++ {
+			x, y := pair(v.Index(i))
+			diffValue(changes, fmt.Sprintf("%s[%d]", path, i), x, y, false)
+		}
+		return
+	}
+	addChange(changes, path, a, b, false)
+}
+
+// containsSecret 判断值中是否含有敏感字段或非空的 Secret
+func containsSecret(v reflect.Value) bool {
+	v = indirect(v)
+	if !v.IsValid() {
+		return false
+	}
+	if v.Type() == secretType {
+		return !v.IsZero()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.IsExported() && ((isSecretField(f) && !v.Field(i).IsZero()) || containsSecret(v.Field(i))) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if containsSecret(iter.Value()) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if containsSecret(v.Index(i)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addChange 追加变更记录，必要时脱敏（Secret 类型的值总是脱敏）
+func addChange(changes *[]Change, path string, a, b reflect.Value, secret bool) {
+	c := Change{Path: path, Old: valueOf(a), New: valueOf(b)}
+	if secret || (a.IsValid() && a.Type() == secretType) || (b.IsValid() && b.Type() == secretType) {
+		c.Redacted = true
+		if c.Old != nil {
+			c.Old = RedactedValue
+		}
+		if c.New != nil {
+			c.New = RedactedValue
+		}
+	}
+	*changes = append(*changes, c)
+}
+
+// indirect 解引用指针与接口，nil 返回零值 Value
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// valueOf 取出可比较的值，无效值返回 nil
+func valueOf(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// hasExportedField 判断结构体是否有导出字段（time.Time 等按整体比较）
+func hasExportedField(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldName 取字段路径名：yaml tag > json tag > 字段名
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"yaml", "json"} {
+		if tag := f.Tag.Get(key); tag != "" {
+			if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+				return name
+			}
+		}
+	}
+	return f.Name
+}
+
+// isSecretField 判断字段是否标记为敏感
+func isSecretField(f reflect.StructField) bool {
+	return f.Tag.Get("secret") == "true"
+}
+
+// joinPath 拼接字段路径
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
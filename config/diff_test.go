@@ -0,0 +1,104 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type diffDB struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password" secret:"true"`
+}
+
+type diffConfig struct {
+	Name    string            `yaml:"name"`
+	Timeout time.Duration     `yaml:"timeout"`
+	DB      *diffDB           `yaml:"db"`
+	Hosts   []string          `yaml:"hosts"`
+	Labels  map[string]string `yaml:"labels"`
+}
+
+func TestDiff(t *testing.T) {
+	old := diffConfig{
+		Name:    "app",
+		Timeout: time.Second,
+		DB:      &diffDB{Addr: "127.0.0.1:3306", Password: "old-pass"},
+		Hosts:   []string{"a", "b"},
+		Labels:  map[string]string{"env": "dev"},
+	}
+	cur := diffConfig{
+		Name:    "app",
+		Timeout: 2 * time.Second,
+		DB:      &diffDB{Addr: "127.0.0.1:3306", Password: "new-pass"},
+		Hosts:   []string{"a", "c", "d"},
+		Labels:  map[string]string{"env": "prod", "zone": "sh"},
+	}
+
+	changes := Diff(&old, &cur)
+	got := make(map[string]Change)
+	for _, c := range changes {
+		got[c.Path] = c
+	}
+
+	want := []string{"timeout", "db.password", "hosts[1]", "hosts[2]", "labels.env", "labels.zone"}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %v", len(want), changes)
+	}
+	for _, p := range want {
+		if _, ok := got[p]; !ok {
+			t.Errorf("missing change for %s", p)
+		}
+	}
+
+	pw := got["db.password"]
+	if !pw.Redacted || pw.Old != RedactedValue || pw.New != RedactedValue {
+		t.Errorf("password should be redacted: %+v", pw)
+	}
+	if c := got["hosts[2]"]; c.Old != nil || c.New != "d" {
+		t.Errorf("unexpected added element: %+v", c)
+	}
+	if c := got["labels.zone"]; c.Old != nil || c.New != "sh" {
+		t.Errorf("unexpected added map key: %+v", c)
+	}
+}
+
+func TestDiff_NoChange(t *testing.T) {
+	cfg := diffConfig{Name: "app", DB: &diffDB{Addr: "x"}}
+	if changes := Diff(cfg, cfg); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %v", changes)
+	}
+}
+
+func TestDiff_AddedCompositeRedacted(t *testing.T) {
+	type cluster struct {
+		DBs map[string]*diffDB `yaml:"dbs"`
+		Any interface{}        `yaml:"any"`
+	}
+	old := cluster{DBs: map[string]*diffDB{}, Any: "x"}
+	cur := cluster{
+		DBs: map[string]*diffDB{"main": {Addr: "10.0.0.1:3306", Password: "p@ss"}},
+		Any: diffDB{Addr: "a", Password: "q"},
+	}
+	for _, c := range append(Diff(old, cur), Diff(cur, old)...) {
+		if c.Old == "p@ss" || c.New == "p@ss" || c.Old == "q" || c.New == "q" {
+			t.Errorf("secret leaked: %+v", c)
+		}
+		if s := c.String(); strings.Contains(s, "p@ss") {
+			t.Errorf("secret leaked in %q", s)
+		}
+	}
+	got := make(map[string]Change)
+	for _, c := range Diff(old, cur) {
+		got[c.Path] = c
+	}
+	if c := got["dbs.main.addr"]; c.New != "10.0.0.1:3306" || c.Old != nil {
+		t.Errorf("unexpected addr change: %+v", c)
+	}
+	if c := got["dbs.main.password"]; !c.Redacted || c.New != RedactedValue {
+		t.Errorf("password should be redacted: %+v", c)
+	}
+	if c := got["any"]; !c.Redacted {
+		t.Errorf("type change with nested secret should be redacted: %+v", c)
+	}
+}
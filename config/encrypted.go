@@ -0,0 +1,276 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// encryptedHeader 整文件加密格式的首行标识，其后为 base64(nonce || ciphertext || tag)
+const encryptedHeader = "$GOUTILS;AES256_GCM;v1"
+
+// ErrUnknownEncryptedFormat 文件既不是整文件 AES-GCM 格式，也不是 SOPS 格式
+var ErrUnknownEncryptedFormat = errors.New("config: unknown encrypted format")
+
+// KeySource 解密密钥来源，便于对接环境变量、密钥文件或 KMS
+type KeySource func() ([]byte, error)
+
+// StaticKey 直接使用给定的密钥（多用于测试）
+func StaticKey(key []byte) KeySource {
+	return func() ([]byte, error) { return key, nil }
+}
+
+// EnvKey 从环境变量读取密钥，值以 "base64:" 开头时按 base64 解码，否则按原始字节使用
+func EnvKey(name string) KeySource {
+	return func() ([]byte, error) {
+		v := os.Getenv(name)
+		if v == "" {
+			return nil, fmt.Errorf("config: env %s is empty", name)
+		}
+		return decodeKey(v)
+	}
+}
+
+// FileKey 从文件读取密钥（例如 k8s secret 挂载的文件），首尾空白会被去除，编码规则同 EnvKey
+func FileKey(path string) KeySource {
+	return func() ([]byte, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read key file: %w", err)
+		}
+		return decodeKey(strings.TrimSpace(string(data)))
+	}
+}
+
+// KMSKey 使用 KMS 解密数据密钥（信封加密）。decrypt 由使用方对接具体云厂商的 KMS SDK 实现，
+// encryptedKey 为 KMS 加密后的数据密钥密文
+func KMSKey(decrypt func(ciphertext []byte) ([]byte, error), encryptedKey []byte) KeySource {
+	return func() ([]byte, error) {
+		if decrypt == nil {
+			return nil, errors.New("config: kms decrypt func must not be nil")
+		}
+		key, err := decrypt(encryptedKey)
+		if err != nil {
+			return nil, fmt.Errorf("kms decrypt: %w", err)
+		}
+		return key, nil
+	}
+}
+
+// keyBase64Prefix 标记密钥为 base64 编码。不做自动探测，避免恰好是合法 base64 的原始密钥被误解码
+const keyBase64Prefix = "base64:"
+
+// decodeKey 带 "base64:" 前缀时按 base64 解码，否则按原始字节处理
+func decodeKey(v string) ([]byte, error) {
+	if rest, ok := strings.CutPrefix(v, keyBase64Prefix); ok {
+		b, err := base64.StdEncoding.DecodeString(rest)
+		if err != nil {
+			return nil, fmt.Errorf("config: decode base64 key: %w", err)
+		}
+		return b, nil
+	}
+	return []byte(v), nil
+}
+
+// LoadEncryptedYAML 从文件加载加密的 YAML，解密后解析到 out（结构体指针）
+// 支持两种格式：
+//   - 本库 EncryptYAML 生成的整文件 AES-GCM 格式
+//   - SOPS 生成的按值加密格式（ENC[AES256_GCM,...]），key 为 SOPS 的数据密钥，不校验 sops MAC
+//
+// 使用示例：
+//
+//	var cfg AppConfig
+//	err := LoadEncryptedYAML("config.enc.yaml", EnvKey("CONFIG_KEY"), &cfg)
+func LoadEncryptedYAML(path string, key KeySource, out interface{}) error {
+	if out == nil {
+		return fmt.Errorf("out must not be nil")
+	}
+	if key == nil {
+		return fmt.Errorf("key source must not be nil")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+	k, err := key()
+	if err != nil {
+		return err
+	}
+	plain, err := DecryptYAML(data, k)
+	if err != nil {
+		return err
+	}
+	return ParseYAML(plain, out)
+}
+
+// EncryptYAML 使用 AES-GCM 加密整份 YAML 内容，key 长度需为 16/24/32 字节
+func EncryptYAML(plain, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key, 0)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, plain, nil)
+	return []byte(encryptedHeader + "\n" + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// DecryptYAML 解密 EncryptYAML 或 SOPS 生成的内容，返回明文 YAML
+func DecryptYAML(data, key []byte) ([]byte, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, encryptedHeader) {
+		return decryptWhole(strings.TrimPrefix(trimmed, encryptedHeader), key)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, ErrUnknownEncryptedFormat
+	}
+	if len(doc.Content) == 0 || !isSOPS(doc.Content[0]) {
+		return nil, ErrUnknownEncryptedFormat
+	}
+	return decryptSOPS(&doc, key)
+}
+
+// decryptWhole 解密整文件格式
+func decryptWhole(payload string, key []byte) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(payload))
+	if err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+	gcm, err := newGCM(key, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("config: ciphertext too short")
+	}
+	nonce, ct := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ct, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return plain, nil
+}
+
+// newGCM 创建 AES-GCM，nonceSize 为 0 时使用标准 12 字节
+func newGCM(key []byte, nonceSize int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("new cipher: %w", err)
+	}
+	if nonceSize == 0 {
+		return cipher.NewGCM(block)
+	}
+	return cipher.NewGCMWithNonceSize(block, nonceSize)
+}
+
+// isSOPS 判断根节点是否包含 sops 元数据
+func isSOPS(root *yaml.Node) bool {
+	if root.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "sops" {
+			return true
+		}
+	}
+	return false
+}
+
+// sopsValueRe 匹配 SOPS 加密值 ENC[AES256_GCM,data:...,iv:...,tag:...,type:...]
+var sopsValueRe = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.*),iv:(.*),tag:(.*),type:(.*)\]$`)
+
+// decryptSOPS 遍历 YAML 树，逐个解密 ENC[...] 值，并移除 sops 元数据
+func decryptSOPS(doc *yaml.Node, key []byte) ([]byte, error) {
+	root := doc.Content[0]
+	content := make([]*yaml.Node, 0, len(root.Content))
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "sops" {
+			continue
+		}
+		content = append(content, root.Content[i], root.Content[i+1])
+	}
+	root.Content = content
+
+	if err := walkSOPS(root, nil, key); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
+
+// walkSOPS 递归解密，路径规则与 SOPS 一致：映射键逐级拼接，列表元素沿用父路径
+func walkSOPS(n *yaml.Node, path []string, key []byte) error {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			p := append(append([]string(nil), path...), n.Content[i].Value)
+			if err := walkSOPS(n.Content[i+1], p, key); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for _, c := range n.Content {
+			if err := walkSOPS(c, path, key); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		m := sopsValueRe.FindStringSubmatch(n.Value)
+		if m == nil {
+			return nil
+		}
+		plain, err := decryptSOPSValue(m[1], m[2], m[3], strings.Join(path, ":")+":", key)
+		if err != nil {
+			return fmt.Errorf("decrypt %s: %w", strings.Join(path, "."), err)
+		}
+		n.Value = plain
+		n.Style = 0
+		switch m[4] {
+		case "int":
+			n.Tag = "!!int"
+		case "float":
+			n.Tag = "!!float"
+		case "bool":
+			n.Tag = "!!bool"
+		default:
+			n.Tag = "!!str"
+		}
+	}
+	return nil
+}
+
+// decryptSOPSValue 解密单个 SOPS 值，additional data 为键路径
+func decryptSOPSValue(data, iv, tag, aad string, key []byte) (string, error) {
+	ct, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(iv)
+	if err != nil {
+		return "", err
+	}
+	t, err := base64.StdEncoding.DecodeString(tag)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key, len(nonce))
+	if err != nil {
+		return "", err
+	}
+	plain, err := gcm.Open(nil, nonce, append(ct, t...), []byte(aad))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestLoadEncryptedYAML_AESGCM(t *testing.T) {
+	enc, err := EncryptYAML([]byte("name: secret-app\nport: 8443\nenabled: true\n"), testKey)
+	if err != nil {
+		t.Fatalf("EncryptYAML: %v", err)
+	}
+	p := filepath.Join(t.TempDir(), "cfg.enc.yaml")
+	if err := os.WriteFile(p, enc, 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	t.Setenv("TEST_CONFIG_KEY", "base64:"+base64.StdEncoding.EncodeToString(testKey))
+	var cfg appConfig
+	if err := LoadEncryptedYAML(p, EnvKey("TEST_CONFIG_KEY"), &cfg); err != nil {
+		t.Fatalf("LoadEncryptedYAML: %v", err)
+	}
+	if cfg.Name != "secret-app" || cfg.Port != 8443 || !cfg.Enabled {
+		t.Fatalf("unexpected cfg: %+v", cfg)
+	}
+
+	wrong := []byte("ffffffffffffffffffffffffffffffff")
+	if err := LoadEncryptedYAML(p, StaticKey(wrong), &cfg); err == nil {
+		t.Fatal("expected error with wrong key")
+	}
+}
+
+// sopsValue 按 SOPS 的格式加密单个值
+func sopsValue(t *testing.T, plain, aad, typ string) string {
+	t.Helper()
+	block, _ := aes.NewCipher(testKey)
+	gcm, _ := cipher.NewGCMWithNonceSize(block, 32)
+	iv := make([]byte, 32)
+	_, _ = rand.Read(iv)
+	sealed := gcm.Seal(nil, iv, []byte(plain), []byte(aad))
+	data, tag := sealed[:len(sealed)-16], sealed[len(sealed)-16:]
+	b64 := base64.StdEncoding.EncodeToString
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:%s]", b64(data), b64(iv), b64(tag), typ)
+}
+
+func TestDecryptYAML_SOPS(t *testing.T) {
+	doc := fmt.Sprintf("name: %s\nport: %s\nenabled: true\nsops:\n    version: 3.8.1\n",
+		sopsValue(t, "sops-app", "name:", "str"),
+		sopsValue(t, "9000", "port:", "int"),
+	)
+
+	kms := func(ciphertext []byte) ([]byte, error) { return testKey, nil }
+	plain, err := DecryptYAML([]byte(doc), mustKey(t, KMSKey(kms, []byte("encrypted-data-key"))))
+	if err != nil {
+		t.Fatalf("DecryptYAML: %v", err)
+	}
+	var cfg appConfig
+	if err := ParseYAML(plain, &cfg); err != nil {
+		t.Fatalf("ParseYAML: %v", err)
+	}
+	if cfg.Name != "sops-app" || cfg.Port != 9000 || !cfg.Enabled {
+		t.Fatalf("unexpected cfg: %+v", cfg)
+	}
+}
+
+func TestDecryptYAML_Unknown(t *testing.T) {
+	if _, err := DecryptYAML([]byte("name: plain\n"), testKey); err != ErrUnknownEncryptedFormat {
+		t.Fatalf("expected ErrUnknownEncryptedFormat, got %v", err)
+	}
+}
+
+func TestFileKey_Encoding(t *testing.T) {
+	dir := t.TempDir()
+	// testKey 本身恰好是合法的 base64，未带前缀时必须按原始字节使用
+	raw := filepath.Join(dir, "raw.key")
+	if err := os.WriteFile(raw, append(testKey, '\n'), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if got := mustKey(t, FileKey(raw)); string(got) != string(testKey) {
+		t.Fatalf("raw key = %q, want %q", got, testKey)
+	}
+
+	enc := filepath.Join(dir, "b64.key")
+	if err := os.WriteFile(enc, []byte("base64:"+base64.StdEncoding.EncodeToString(testKey)), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if got := mustKey(t, FileKey(enc)); string(got) != string(testKey) {
+		t.Fatalf("base64 key = %q, want %q", got, testKey)
+	}
+
+	bad := filepath.Join(dir, "bad.key")
+	if err := os.WriteFile(bad, []byte("base64:!!!"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if _, err := FileKey(bad)(); err == nil {
+		t.Fatal("expected error for invalid base64 key")
+	}
+}
+
+func mustKey(t *testing.T, ks KeySource) []byte {
+	t.Helper()
+	k, err := ks()
+	if err != nil {
+		t.Fatalf("key source: %v", err)
+	}
+	return k
+}
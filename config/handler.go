@@ -0,0 +1,103 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Snapshot 将配置转换为便于输出的键值树：键使用 yaml/json tag 名称，
+// 带 `secret:"true"` tag 的字段与 Secret 类型的非空值替换为 RedactedValue，time.Duration 输出为 "5s" 形式
+func Snapshot(v interface{}) interface{} {
+	return snapshotValue(reflect.ValueOf(v), false)
+}
+
+func snapshotValue(v reflect.Value, secret bool) interface{} {
+	v = indirect(v)
+	if !v.IsValid() {
+		return nil
+	}
+	if secret || v.Type() == secretType {
+		if v.IsZero() {
+			return valueOf(v)
+		}
+		return RedactedValue
+	}
+	if d, ok := valueOf(v).(time.Duration); ok {
+		return d.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if !hasExportedField(v.Type()) {
+			return valueOf(v)
+		}
+		out := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() || strings.Split(f.Tag.Get("yaml"), ",")[0] == "-" {
+				continue
+			}
+			out[fieldName(f)] = snapshotValue(v.Field(i), isSecretField(f))
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = snapshotValue(iter.Value(), false)
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = snapshotValue(v.Index(i), false)
+		}
+		return out
+	}
+	return valueOf(v)
+}
+
+// Status 配置运行时状态，由 Handler 输出
+type Status struct {
+	Path     string      `json:"path"`
+	LoadedAt time.Time   `json:"loaded_at"`
+	OK       bool        `json:"ok"`
+	Error    string      `json:"error,omitempty"` // 最近一次加载失败的原因，此时 Config 仍为旧配置
+	Config   interface{} `json:"config"`
+}
+
+// Status 返回当前生效配置的快照（已脱敏）与最近一次加载状态
+func (w *Watcher[T]) Status() Status {
+	loadedAt, err := w.LastReload()
+	s := Status{Path: w.path, LoadedAt: loadedAt, OK: err == nil, Config: Snapshot(w.Current())}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	return s
+}
+
+// Handler 返回以 JSON 输出 Status 的 http.Handler，用于线上排查“当前到底用的是什么配置”；
+// 敏感字段已脱敏，但仍建议挂载在内部管理端口或加鉴权
+// 使用示例：
+//
+//	mux.Handle("/debug/config", w.Handler())
+func (w *Watcher[T]) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+		rw.Header().Set("Cache-Control", "no-store")
+		enc := json.NewEncoder(rw)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(w.Status())
+	})
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type handlerDB struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password" secret:"true"`
+	Token    Secret `yaml:"token"`
+}
+
+type handlerConfig struct {
+	Name    string            `yaml:"name"`
+	Timeout time.Duration     `yaml:"timeout"`
+	DB      *handlerDB        `yaml:"db"`
+	Hosts   []string          `yaml:"hosts"`
+	Labels  map[string]string `yaml:"labels"`
+	Ignored string            `yaml:"-"`
+}
+
+func TestWatcher_Handler(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "cfg.yaml")
+	content := "name: app\ntimeout: 3s\ndb:\n  addr: 127.0.0.1:3306\n  password: pass1\n  token: tok1\nhosts: [a]\nlabels:\n  env: prod\n"
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	w, err := NewWatcher[handlerConfig](p)
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	w.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("unexpected response: %d %v", rec.Code, rec.Header())
+	}
+	body := rec.Body.String()
+	if strings.Contains(body, "pass1") || strings.Contains(body, "tok1") {
+		t.Fatalf("secret leaked: %s", body)
+	}
+	var got struct {
+		Path     string    `json:"path"`
+		LoadedAt time.Time `json:"loaded_at"`
+		OK       bool      `json:"ok"`
+		Error    string    `json:"error"`
+		Config   struct {
+			Name    string            `json:"name"`
+			Timeout string            `json:"timeout"`
+			DB      map[string]string `json:"db"`
+			Hosts   []string          `json:"hosts"`
+			Labels  map[string]string `json:"labels"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if got.Path != p || !got.OK || got.LoadedAt.IsZero() || got.Config.Name != "app" || got.Config.Timeout != "3s" ||
+		got.Config.DB["addr"] != "127.0.0.1:3306" || got.Config.DB["password"] != RedactedValue ||
+		got.Config.DB["token"] != RedactedValue || got.Config.Labels["env"] != "prod" || len(got.Config.Hosts) != 1 {
+		t.Fatalf("unexpected status: %+v", got)
+	}
+	if strings.Contains(body, "Ignored") {
+		t.Fatalf("yaml:\"-\" field should be skipped: %s", body)
+	}
+
+	// 加载失败时输出错误，配置仍为旧值
+	if err := os.WriteFile(p, []byte("name: [\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	_, _ = w.Reload()
+	s := w.Status()
+	if s.OK || s.Error == "" {
+		t.Fatalf("expected error status: %+v", s)
+	}
+
+	// 错误的修改还原为已加载的内容后恢复为 ok
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if changed, err := w.Reload(); changed || err != nil {
+		t.Fatalf("Reload after revert = %v, %v", changed, err)
+	}
+	if s := w.Status(); !s.OK || s.Error != "" {
+		t.Fatalf("expected ok status after revert: %+v", s)
+	}
+
+	rec = httptest.NewRecorder()
+	w.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/config", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected code: %d", rec.Code)
+	}
+}
+
+func TestSnapshot_Empty(t *testing.T) {
+	s := Snapshot(handlerConfig{}).(map[string]interface{})
+	if s["db"] != nil || s["hosts"] != nil {
+		t.Fatalf("unexpected snapshot: %v", s)
+	}
+	if Snapshot(nil) != nil {
+		t.Fatal("nil snapshot should be nil")
+	}
+}
@@ -0,0 +1,380 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Provider 配置来源，Load 返回嵌套的键值树（与 YAML 解析到 map[string]interface{} 的结构一致）
+type Provider interface {
+	Name() string
+	Load(ctx context.Context) (map[string]interface{}, error)
+}
+
+// providerFunc 由函数实现的 Provider
+type providerFunc struct {
+	name    string
+	load    func(ctx context.Context) (map[string]interface{}, error)
+	untyped bool
+}
+
+func (p *providerFunc) Name() string { return p.name }
+
+// untypedText 见 untypedProvider
+func (p *providerFunc) untypedText() bool { return p.untyped }
+
+// untypedProvider 值为无类型文本的来源（环境变量、命令行参数），Decode 时按目标字段类型解析；
+// 其余来源的字符串是 YAML 中明确的字符串，按字符串解析
+type untypedProvider interface {
+	untypedText() bool
+}
+
+func (p *providerFunc) Load(ctx context.Context) (map[string]interface{}, error) {
+	return p.load(ctx)
+}
+
+// ProviderFunc 用函数构造 Provider，便于接入自定义来源；返回的树与 YAML 解析结果同样按值的类型解析，
+// 字符串只能解析到字符串字段
+func ProviderFunc(name string, load func(ctx context.Context) (map[string]interface{}, error)) Provider {
+	return &providerFunc{name: name, load: load}
+}
+
+// Defaults 默认值来源，v 为带 yaml tag 的结构体（通常是填好默认值的配置结构体）或 map
+func Defaults(v interface{}) Provider {
+	return ProviderFunc("defaults", func(context.Context) (map[string]interface{}, error) {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("marshal defaults: %w", err)
+		}
+		return parseTree(data)
+	})
+}
+
+// File YAML 文件来源，文件不存在时报错
+func File(path string) Provider {
+	return fileProvider(path, LoadYAML, false)
+}
+
+// OptionalFile YAML 文件来源，文件不存在时视为空（如 config.local.yaml）
+func OptionalFile(path string) Provider {
+	return fileProvider(path, LoadYAML, true)
+}
+
+// EncryptedFile 加密 YAML 文件来源，格式见 LoadEncryptedYAML
+func EncryptedFile(path string, key KeySource) Provider {
+	return fileProvider(path, func(path string, out interface{}) error {
+		return LoadEncryptedYAML(path, key, out)
+	}, false)
+}
+
+func fileProvider(path string, loader Loader, optional bool) Provider {
+	return ProviderFunc("file:"+path, func(context.Context) (map[string]interface{}, error) {
+		tree := map[string]interface{}{}
+		if err := loader(path, &tree); err != nil {
+			if optional && errors.Is(err, fs.ErrNotExist) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return tree, nil
+	})
+}
+
+// Env 环境变量来源，只读取以 prefix_ 开头的变量；层级之间用双下划线分隔，键名转为小写，
+// 值保留原始字符串，Decode 时按目标字段类型解析：
+//
+//	APP_PORT=8080          -> port: 8080
+//	APP_DB__HOST=10.0.0.1  -> db.host: 10.0.0.1
+//	APP_DB__MAX_IDLE=10    -> db.max_idle: 10
+func Env(prefix string) Provider {
+	prefix = strings.TrimSuffix(prefix, "_") + "_"
+	return &providerFunc{name: "env:" + prefix, untyped: true, load: func(context.Context) (map[string]interface{}, error) {
+		tree := map[string]interface{}{}
+		for _, kv := range os.Environ() {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok || !strings.HasPrefix(k, prefix) || len(k) == len(prefix) {
+				continue
+			}
+			path := strings.Split(strings.ToLower(strings.TrimPrefix(k, prefix)), "__")
+			setPath(tree, path, v)
+		}
+		return tree, nil
+	}}
+}
+
+// Flags 命令行参数来源，只读取显式设置过的参数，参数名即路径（如 -db.host=10.0.0.1），
+// 需在 fs.Parse 之后调用 Chain.Load
+func Flags(fs *flag.FlagSet) Provider {
+	return &providerFunc{name: "flags", untyped: true, load: func(context.Context) (map[string]interface{}, error) {
+		tree := map[string]interface{}{}
+		fs.Visit(func(f *flag.Flag) {
+			setPath(tree, strings.Split(f.Name, "."), f.Value.String())
+		})
+		return tree, nil
+	}}
+}
+
+// Remote 远程配置来源（配置中心、etcd、Nacos 等），fetch 返回 YAML 或 JSON 文本
+func Remote(name string, fetch func(ctx context.Context) ([]byte, error)) Provider {
+	return ProviderFunc("remote:"+name, func(ctx context.Context) (map[string]interface{}, error) {
+		data, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return parseTree(data)
+	})
+}
+
+// Chain 按优先级合并多个配置来源，后面的来源覆盖前面的，推荐顺序：
+// defaults < file < env < flags < remote；同时记录每个配置项来自哪个来源，
+// 用于排查“这个值到底是哪里来的”；列表默认整体覆盖，可通过 SetMerge、MergeTags 改为追加或按 key 合并
+// 使用示例：
+//
+//	c := config.NewChain(
+//		config.Defaults(defaultConfig),
+//		config.File("config.yaml"),
+//		config.Env("APP"),
+//		config.Flags(flag.CommandLine),
+//	)
+//	if err := c.Load(ctx); err != nil { ... }
+//	var cfg AppConfig
+//	err := c.Decode(&cfg)
+//	c.Origin("db.host") // "env:APP_"
+type Chain struct {
+	providers []Provider
+
+	mu      sync.RWMutex
+	tree    map[string]interface{}
+	origins map[string][]string      // 叶子路径 -> 提供过该值的来源（按优先级从低到高）
+	merges  map[string]MergeStrategy // 列表路径 -> 合并策略，见 SetMerge、MergeTags
+	untyped map[string]bool          // 值为无类型文本的来源名称，见 untypedProvider
+}
+
+// NewChain 创建配置链，providers 按优先级从低到高排列
+func NewChain(providers ...Provider) *Chain {
+	untyped := map[string]bool{}
+	for _, p := range providers {
+		if u, ok := p.(untypedProvider); ok && u.untypedText() {
+			untyped[p.Name()] = true
+		}
+	}
+	return &Chain{providers: providers, tree: map[string]interface{}{}, origins: map[string][]string{}, merges: map[string]MergeStrategy{}, untyped: untyped}
+}
+
+// Providers 返回来源名称（按优先级从低到高）
+func (c *Chain) Providers() []string {
+	names := make([]string, len(c.providers))
+	for i, p := range c.providers {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// Load 依次加载所有来源并合并，任一来源失败时返回错误并保留上一次的结果
+func (c *Chain) Load(ctx context.Context) error {
+	tree := map[string]interface{}{}
+	origins := map[string][]string{}
+	c.mu.RLock()
+	merges := make(map[string]MergeStrategy, len(c.merges))
+	for path, s := range c.merges {
+		merges[path] = s
+	}
+	c.mu.RUnlock()
+	for _, p := range c.providers {
+		layer, err := p.Load(ctx)
+		if err != nil {
+			return fmt.Errorf("config: load %s: %w", p.Name(), err)
+		}
+		mergeTree(tree, layer, "", p.Name(), origins, merges)
+	}
+	c.mu.Lock()
+	c.tree, c.origins = tree, origins
+	c.mu.Unlock()
+	return nil
+}
+
+// Decode 将合并后的配置解析到 out（结构体指针）；环境变量、命令行参数的值按普通 YAML 标量处理，
+// 因此其中的 "8080"、"true"、"5s" 可以解析到 int、bool、time.Duration 字段；
+// 文件等来源中的字符串保持字符串，引号中的 "null"、"true"、"" 不会被重新解释
+func (c *Chain) Decode(out interface{}) error {
+	if out == nil {
+		return fmt.Errorf("out must not be nil")
+	}
+	c.mu.RLock()
+	node, err := toNode(c.tree, "", func(path string) bool {
+		sources := c.origins[path]
+		return len(sources) > 0 && c.untyped[sources[len(sources)-1]]
+	})
+	c.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	return node.Decode(out)
+}
+
+// Get 按路径（如 "db.host"）读取合并后的值，路径指向子树时返回 map
+func (c *Chain) Get(path string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var cur interface{} = c.tree
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// Origin 返回最终生效的值来自哪个来源，路径不存在或指向子树时返回空串
+func (c *Chain) Origin(path string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if sources := c.origins[path]; len(sources) > 0 {
+		return sources[len(sources)-1]
+	}
+	return ""
+}
+
+// Sources 返回所有提供过该值的来源（按优先级从低到高），最后一个即生效的来源
+func (c *Chain) Sources(path string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.origins[path]...)
+}
+
+// Origins 返回全部叶子路径与其生效来源
+func (c *Chain) Origins() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make(map[string]string, len(c.origins))
+	for path, sources := range c.origins {
+		out[path] = sources[len(sources)-1]
+	}
+	return out
+}
+
+// Paths 返回全部叶子路径（已排序）
+func (c *Chain) Paths() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	paths := make([]string, 0, len(c.origins))
+	for path := range c.origins {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// mergeTree 将 src 深度合并到 dst：map 递归合并，列表按 merges 中该路径的策略合并（默认整体覆盖），
+// 其余值整体覆盖，并记录叶子来源（列表视为叶子）
+func mergeTree(dst, src map[string]interface{}, prefix, source string, origins map[string][]string, merges map[string]MergeStrategy) {
+	for k, v := range src {
+		path := joinPath(prefix, k)
+		if sm, ok := v.(map[string]interface{}); ok {
+			dm, ok := dst[k].(map[string]interface{})
+			if !ok {
+				// 原来是叶子值，被子树整体替换
+				dropOrigins(origins, path)
+				dm = map[string]interface{}{}
+				dst[k] = dm
+			}
+			mergeTree(dm, sm, path, source, origins, merges)
+			continue
+		}
+		if _, ok := dst[k].(map[string]interface{}); ok {
+			// 原来是子树，被叶子值整体替换
+			dropOrigins(origins, path)
+		}
+		if s, ok := merges[path]; ok {
+			v = mergeList(dst[k], v, path, s, merges)
+		}
+		dst[k] = v
+		origins[path] = append(origins[path], source)
+	}
+}
+
+// dropOrigins 删除 path 及其子路径的来源记录
+func dropOrigins(origins map[string][]string, path string) {
+	for p := range origins {
+		if p == path || strings.HasPrefix(p, path+".") {
+			delete(origins, p)
+		}
+	}
+}
+
+// setPath 按路径写入嵌套 map
+func setPath(tree map[string]interface{}, path []string, v interface{}) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := tree[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			tree[key] = next
+		}
+		tree = next
+	}
+	tree[path[len(path)-1]] = v
+}
+
+// toNode 将键值树转换为 yaml.Node：untyped 返回 true 的路径上的字符串生成不带标签的普通标量，由解析目标决定类型，
+// 其余字符串带 !!str 标签，按字符串解析
+func toNode(v interface{}, path string, untyped func(path string) bool) (*yaml.Node, error) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range keys {
+			child, err := toNode(x[k], joinPath(path, k), untyped)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k}, child)
+		}
+		return n, nil
+	case []interface{}:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range x {
+			child, err := toNode(item, path, untyped)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, child)
+		}
+		return n, nil
+	case string:
+		if untyped(path) {
+			return &yaml.Node{Kind: yaml.ScalarNode, Value: x}, nil
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: x}, nil
+	}
+	n := &yaml.Node{}
+	if err := n.Encode(v); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// parseTree 解析 YAML/JSON 文本为键值树
+func parseTree(data []byte) (map[string]interface{}, error) {
+	tree := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type chainDB struct {
+	Host    string `yaml:"host"`
+	Port    int    `yaml:"port"`
+	MaxIdle int    `yaml:"max_idle"`
+}
+
+type chainConfig struct {
+	Name    string        `yaml:"name"`
+	Debug   bool          `yaml:"debug"`
+	Timeout time.Duration `yaml:"timeout"`
+	Code    string        `yaml:"code"`
+	DB      chainDB       `yaml:"db"`
+	Hosts   []string      `yaml:"hosts"`
+}
+
+func TestChain_Priority(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "cfg.yaml")
+	if err := os.WriteFile(p, []byte("name: app\ndb:\n  host: file-host\n  port: 3306\nhosts: [a, b]\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	t.Setenv("CHAINTEST_DB__HOST", "env-host")
+	t.Setenv("CHAINTEST_DB__MAX_IDLE", "10")
+	t.Setenv("CHAINTEST_TIMEOUT", "5s")
+	t.Setenv("CHAINTEST_CODE", "0123")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("debug", false, "")
+	fs.String("db.port", "3306", "")
+	if err := fs.Parse([]string{"-debug"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	remote := Remote("center", func(context.Context) ([]byte, error) {
+		return []byte(`{"db": {"port": 3307}}`), nil
+	})
+
+	c := NewChain(
+		Defaults(chainConfig{Name: "default", Timeout: time.Second, DB: chainDB{Host: "localhost", MaxIdle: 2}}),
+		File(p),
+		OptionalFile(filepath.Join(t.TempDir(), "missing.yaml")),
+		Env("CHAINTEST"),
+		Flags(fs),
+		remote,
+	)
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var cfg chainConfig
+	if err := c.Decode(&cfg); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := chainConfig{
+		Name:    "app",
+		Debug:   true,
+		Timeout: 5 * time.Second,
+		Code:    "0123",
+		DB:      chainDB{Host: "env-host", Port: 3307, MaxIdle: 10},
+		Hosts:   []string{"a", "b"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("unexpected cfg: %+v", cfg)
+	}
+
+	origins := map[string]string{
+		"name":        "file:" + p,
+		"db.host":     "env:CHAINTEST_",
+		"db.port":     "remote:center",
+		"db.max_idle": "env:CHAINTEST_",
+		"debug":       "flags",
+		"timeout":     "env:CHAINTEST_",
+		"hosts":       "file:" + p,
+	}
+	for path, want := range origins {
+		if got := c.Origin(path); got != want {
+			t.Errorf("Origin(%q) = %q, want %q", path, got, want)
+		}
+	}
+	if got := c.Sources("db.host"); !reflect.DeepEqual(got, []string{"defaults", "file:" + p, "env:CHAINTEST_"}) {
+		t.Errorf("Sources(db.host) = %v", got)
+	}
+	if c.Origin("db") != "" || c.Origin("missing") != "" {
+		t.Error("subtree and missing path should have no origin")
+	}
+	if v, ok := c.Get("db.host"); !ok || v != "env-host" {
+		t.Errorf("Get(db.host) = %v, %v", v, ok)
+	}
+	if _, ok := c.Get("db.host.x"); ok {
+		t.Error("Get should fail below a leaf")
+	}
+}
+
+// TestChain_QuotedStrings 文件中引号括起的字符串原样解析，环境变量中的文本仍按目标字段类型解析
+func TestChain_QuotedStrings(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "cfg.yaml")
+	if err := os.WriteFile(p, []byte("name: \"null\"\ncode: \"true\"\nhosts: [\"~\", \"\", \"0x10\"]\ndb:\n  host: \"\"\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	t.Setenv("QUOTETEST_DB__PORT", "3306")
+	t.Setenv("QUOTETEST_DEBUG", "true")
+
+	c := NewChain(Defaults(chainConfig{DB: chainDB{Host: "localhost"}}), File(p), Env("QUOTETEST"))
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var cfg chainConfig
+	if err := c.Decode(&cfg); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := chainConfig{
+		Name:  "null",
+		Code:  "true",
+		Debug: true,
+		DB:    chainDB{Port: 3306},
+		Hosts: []string{"~", "", "0x10"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("unexpected cfg: %+v", cfg)
+	}
+}
+
+func TestChain_ReplaceSubtree(t *testing.T) {
+	c := NewChain(
+		ProviderFunc("a", func(context.Context) (map[string]interface{}, error) {
+			return map[string]interface{}{"db": map[string]interface{}{"host": "h"}, "dbx": 1}, nil
+		}),
+		ProviderFunc("b", func(context.Context) (map[string]interface{}, error) {
+			return map[string]interface{}{"db": "dsn"}, nil
+		}),
+	)
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := c.Paths(); !reflect.DeepEqual(got, []string{"db", "dbx"}) {
+		t.Fatalf("Paths = %v", got)
+	}
+	if got := c.Origins(); !reflect.DeepEqual(got, map[string]string{"db": "b", "dbx": "a"}) {
+		t.Fatalf("Origins = %v", got)
+	}
+}
+
+func TestChain_LoadError(t *testing.T) {
+	fail := false
+	c := NewChain(ProviderFunc("remote", func(context.Context) (map[string]interface{}, error) {
+		if fail {
+			return nil, errors.New("unavailable")
+		}
+		return map[string]interface{}{"name": "ok"}, nil
+	}))
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	fail = true
+	if err := c.Load(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if v, _ := c.Get("name"); v != "ok" {
+		t.Fatalf("previous result should be kept, got %v", v)
+	}
+	if err := NewChain(File(filepath.Join(t.TempDir(), "missing.yaml"))).Load(context.Background()); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
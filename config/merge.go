@@ -0,0 +1,217 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// mergeMode 列表合并方式
+type mergeMode int
+
+const (
+	mergeReplace mergeMode = iota
+	mergeAppend
+	mergeByKey
+)
+
+// MergeStrategy 多个来源中同一路径的列表（YAML 序列）如何合并，未设置时为 MergeReplace
+type MergeStrategy struct {
+	mode mergeMode
+	key  string
+}
+
+var (
+	// MergeReplace 后面的来源整体替换前面的列表（默认）
+	MergeReplace = MergeStrategy{mode: mergeReplace}
+	// MergeAppend 后面来源的元素追加到前面列表的末尾
+	MergeAppend = MergeStrategy{mode: mergeAppend}
+)
+
+// MergeByKey 按元素（map）中 key 字段的值合并：值相同的元素深度合并（后面的来源覆盖前面的），
+// 其余元素按出现顺序追加；不是 map 或缺少 key 字段的元素直接追加
+func MergeByKey(key string) MergeStrategy {
+	return MergeStrategy{mode: mergeByKey, key: key}
+}
+
+// String 返回与 merge tag 相同的写法
+func (s MergeStrategy) String() string {
+	switch s.mode {
+	case mergeAppend:
+		return "append"
+	case mergeByKey:
+		return "key=" + s.key
+	default:
+		return "replace"
+	}
+}
+
+// parseMergeStrategy 解析 merge tag：replace、append、key=<字段名>
+func parseMergeStrategy(tag string) (MergeStrategy, error) {
+	switch {
+	case tag == "replace":
+		return MergeReplace, nil
+	case tag == "append":
+		return MergeAppend, nil
+	case strings.HasPrefix(tag, "key="):
+		if key := strings.TrimPrefix(tag, "key="); key != "" {
+			return MergeByKey(key), nil
+		}
+	}
+	return MergeStrategy{}, fmt.Errorf("config: invalid merge tag %q", tag)
+}
+
+// SetMerge 设置 path（如 "gateway.endpoints"）处列表的合并策略，在下一次 Load 时生效；
+// 路径与 Get 相同，按 key 合并的列表元素内部的列表路径为 "列表路径.字段名"，不含下标
+// 使用示例：
+//
+//	c.SetMerge("plugins", config.MergeAppend)
+//	c.SetMerge("upstreams", config.MergeByKey("name"))
+func (c *Chain) SetMerge(path string, s MergeStrategy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.merges[path] = s
+}
+
+// MergeTags 从结构体的 merge tag 读取列表合并策略，路径按 yaml tag 计算（未设置时为小写字段名），
+// 支持嵌套结构体、指针与 inline；tag 取值为 replace、append 或 key=<字段名>
+// 使用示例：
+//
+//	type AppConfig struct {
+//		Plugins   []string   `yaml:"plugins" merge:"append"`
+//		Upstreams []Upstream `yaml:"upstreams" merge:"key=name"`
+//	}
+//	c := config.NewChain(config.File("base.yaml"), config.File("prod.yaml"))
+//	if err := c.MergeTags(AppConfig{}); err != nil { ... }
+//	err := c.Load(ctx)
+func (c *Chain) MergeTags(v interface{}) error {
+	merges := map[string]MergeStrategy{}
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("config: MergeTags requires a struct, got %T", v)
+	}
+	if err := collectMergeTags(t, "", merges, map[reflect.Type]bool{}); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for path, s := range merges {
+		c.merges[path] = s
+	}
+	return nil
+}
+
+// collectMergeTags 递归收集结构体字段上的 merge tag，visiting 防止自引用类型无限递归
+func collectMergeTags(t reflect.Type, prefix string, merges map[string]MergeStrategy, visiting map[reflect.Type]bool) error {
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		path := prefix
+		if !strings.Contains(opts, "inline") {
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			path = joinPath(prefix, name)
+		}
+		if tag, ok := f.Tag.Lookup("merge"); ok {
+			s, err := parseMergeStrategy(tag)
+			if err != nil {
+				return fmt.Errorf("%w (field %s.%s)", err, t.Name(), f.Name)
+			}
+			merges[path] = s
+		}
+		// 列表元素内部的路径不含下标，与 mergeList 保持一致
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr || ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			if err := collectMergeTags(ft, path, merges, visiting); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mergeList 按策略合并同一路径上的两个值，只有两边都是列表时策略才生效，否则 src 替换 dst
+func mergeList(dst, src interface{}, path string, s MergeStrategy, merges map[string]MergeStrategy) interface{} {
+	dl, ok := dst.([]interface{})
+	if !ok {
+		return src
+	}
+	sl, ok := src.([]interface{})
+	if !ok {
+		return src
+	}
+	switch s.mode {
+	case mergeAppend:
+		return append(append(make([]interface{}, 0, len(dl)+len(sl)), dl...), sl...)
+	case mergeByKey:
+		out := append(make([]interface{}, 0, len(dl)+len(sl)), dl...)
+		index := map[string]int{}
+		for i, item := range out {
+			if id, ok := itemKey(item, s.key); ok {
+				index[id] = i
+			}
+		}
+		for _, item := range sl {
+			id, ok := itemKey(item, s.key)
+			if !ok {
+				out = append(out, item)
+				continue
+			}
+			if i, found := index[id]; found {
+				// 复制后再合并，避免修改来源返回的数据
+				merged := cloneTree(out[i].(map[string]interface{}))
+				mergeTree(merged, item.(map[string]interface{}), path, "", map[string][]string{}, merges)
+				out[i] = merged
+				continue
+			}
+			index[id] = len(out)
+			out = append(out, item)
+		}
+		return out
+	}
+	return src
+}
+
+// itemKey 取 map 元素中 key 字段的值（统一转为字符串比较）
+func itemKey(item interface{}, key string) (string, bool) {
+	m, ok := item.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	v, ok := m[key]
+	if !ok || v == nil {
+		return "", false
+	}
+	return fmt.Sprint(v), true
+}
+
+// cloneTree 深拷贝键值树中的 map，列表与标量共享
+func cloneTree(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if sub, ok := v.(map[string]interface{}); ok {
+			v = cloneTree(sub)
+		}
+		out[k] = v
+	}
+	return out
+}
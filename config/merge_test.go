@@ -0,0 +1,142 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type mergeEndpoint struct {
+	Name   string   `yaml:"name"`
+	URL    string   `yaml:"url"`
+	Weight int      `yaml:"weight"`
+	Tags   []string `yaml:"tags" merge:"append"`
+}
+
+type mergeGateway struct {
+	Endpoints []mergeEndpoint `yaml:"endpoints" merge:"key=name"`
+}
+
+type mergeConfig struct {
+	Plugins []string      `yaml:"plugins" merge:"append"`
+	Hosts   []string      `yaml:"hosts"`
+	Gateway *mergeGateway `yaml:"gateway"`
+	Extra   mergeExtra    `yaml:",inline"`
+}
+
+type mergeExtra struct {
+	Admins []string `merge:"replace"`
+}
+
+func writeYAML(t *testing.T, name, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	return p
+}
+
+func TestChain_MergeTags(t *testing.T) {
+	base := writeYAML(t, "base.yaml", `
+plugins: [auth, metrics]
+hosts: [a, b]
+gateway:
+  endpoints:
+    - {name: api, url: "http://api", weight: 1, tags: [v1]}
+    - {name: web, url: "http://web", weight: 1}
+`)
+	prod := writeYAML(t, "prod.yaml", `
+plugins: [audit]
+hosts: [c]
+gateway:
+  endpoints:
+    - {name: api, weight: 5, tags: [prod]}
+    - {name: admin, url: "http://admin"}
+`)
+	c := NewChain(File(base), File(prod))
+	if err := c.MergeTags(&mergeConfig{}); err != nil {
+		t.Fatalf("MergeTags: %v", err)
+	}
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var cfg mergeConfig
+	if err := c.Decode(&cfg); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := mergeConfig{
+		Plugins: []string{"auth", "metrics", "audit"},
+		Hosts:   []string{"c"},
+		Gateway: &mergeGateway{Endpoints: []mergeEndpoint{
+			{Name: "api", URL: "http://api", Weight: 5, Tags: []string{"v1", "prod"}},
+			{Name: "web", URL: "http://web", Weight: 1},
+			{Name: "admin", URL: "http://admin"},
+		}},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("unexpected cfg: %+v %+v", cfg, cfg.Gateway)
+	}
+	if got := c.Sources("plugins"); !reflect.DeepEqual(got, []string{"file:" + base, "file:" + prod}) {
+		t.Errorf("Sources(plugins) = %v", got)
+	}
+	if c.merges["admins"] != MergeReplace {
+		t.Errorf("inline field strategy = %v", c.merges["admins"])
+	}
+
+	// 重复加载不会叠加上一次的结果，也不会修改来源数据
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if v, _ := c.Get("plugins"); len(v.([]interface{})) != 3 {
+		t.Errorf("plugins after reload = %v", v)
+	}
+}
+
+func TestChain_SetMerge(t *testing.T) {
+	layer := func(name string, tree map[string]interface{}) Provider {
+		return ProviderFunc(name, func(context.Context) (map[string]interface{}, error) { return tree, nil })
+	}
+	first := []interface{}{map[string]interface{}{"id": 1, "opts": map[string]interface{}{"a": 1}}, "raw"}
+	c := NewChain(
+		layer("a", map[string]interface{}{"items": first, "scalar": "x"}),
+		layer("b", map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": 1, "opts": map[string]interface{}{"b": 2}}, "raw"}, "scalar": []interface{}{"y"}}),
+	)
+	c.SetMerge("items", MergeByKey("id"))
+	c.SetMerge("scalar", MergeAppend)
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	items, _ := c.Get("items")
+	want := []interface{}{map[string]interface{}{"id": 1, "opts": map[string]interface{}{"a": 1, "b": 2}}, "raw", "raw"}
+	if !reflect.DeepEqual(items, want) {
+		t.Fatalf("items = %v", items)
+	}
+	if !reflect.DeepEqual(first[0], map[string]interface{}{"id": 1, "opts": map[string]interface{}{"a": 1}}) {
+		t.Errorf("source data modified: %v", first[0])
+	}
+	// 只有两边都是列表时策略才生效
+	if v, _ := c.Get("scalar"); !reflect.DeepEqual(v, []interface{}{"y"}) {
+		t.Errorf("scalar = %v", v)
+	}
+}
+
+func TestMergeTags_Invalid(t *testing.T) {
+	type bad struct {
+		Items []string `yaml:"items" merge:"key="`
+	}
+	if err := NewChain().MergeTags(bad{}); err == nil {
+		t.Fatal("expected error for invalid tag")
+	}
+	if err := NewChain().MergeTags(1); err == nil {
+		t.Fatal("expected error for non-struct")
+	}
+	for tag, want := range map[string]string{"append": "append", "replace": "replace", "key=id": "key=id"} {
+		s, err := parseMergeStrategy(tag)
+		if err != nil || s.String() != want {
+			t.Errorf("parseMergeStrategy(%q) = %v, %v", tag, s, err)
+		}
+	}
+}
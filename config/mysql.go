@@ -0,0 +1,43 @@
+package config
+
+import "time"
+
+// MySQL 各服务共用的 MySQL 配置结构，时长字段使用 "5m"、"30s" 形式的字符串，
+// 配合 mysqlx.FromConfig 一行完成连接池初始化
+// 使用示例（YAML）：
+//
+//	mysql:
+//	  addr: 127.0.0.1:3306
+//	  user: app
+//	  dbname: orders
+//	  max_open_conns: 50
+//	  conn_max_lifetime: 5m
+//	  ping_timeout: 3s
+//
+// 配置文件不做 ${VAR} 形式的变量展开，密码建议通过 Env 来源注入（NewChain(File(...), Env("APP"))）：
+//
+//	APP_MYSQL__PASSWORD=xxx
+type MySQL struct {
+	User     string            `yaml:"user"`
+	Password Secret            `yaml:"password"`
+	Net      string            `yaml:"net"` // tcp（默认）或 unix
+	Addr     string            `yaml:"addr"`
+	DBName   string            `yaml:"dbname"`
+	Params   map[string]string `yaml:"params"` // 额外 DSN 参数
+
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+
+	PingTimeout  time.Duration `yaml:"ping_timeout"`
+	WarmupConns  int           `yaml:"warmup_conns"`
+	KillOnCancel bool          `yaml:"kill_on_cancel"`
+	KillTimeout  time.Duration `yaml:"kill_timeout"`
+
+	SessionVars map[string]string `yaml:"session_vars"` // 每个新连接设置的会话变量，如 time_zone: "+08:00"
+	ConnAttrs   map[string]string `yaml:"conn_attrs"`   // 连接属性，如 program_name: order-svc
+
+	SlowThreshold    time.Duration `yaml:"slow_threshold"`     // 超过该耗时的语句自动 EXPLAIN 并记录日志，建议只在预发环境开启
+	ExplainPerMinute int           `yaml:"explain_per_minute"` // 每分钟最多执行 EXPLAIN 的次数，0 表示 6 次
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMySQL_ParseYAML(t *testing.T) {
+	data := []byte(`
+addr: db.internal:3306
+user: app
+password: p@ss
+dbname: orders
+params:
+  timeout: 2s
+max_open_conns: 50
+conn_max_lifetime: 5m
+ping_timeout: 3s
+kill_on_cancel: true
+`)
+	var cfg MySQL
+	if err := ParseYAML(data, &cfg); err != nil {
+		t.Fatalf("ParseYAML: %v", err)
+	}
+	if cfg.Addr != "db.internal:3306" || cfg.Password.Reveal() != "p@ss" || cfg.MaxOpenConns != 50 || !cfg.KillOnCancel {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.ConnMaxLifetime != 5*time.Minute || cfg.PingTimeout != 3*time.Second || cfg.Params["timeout"] != "2s" {
+		t.Errorf("unexpected durations: %v %v", cfg.ConnMaxLifetime, cfg.PingTimeout)
+	}
+	if s := fmt.Sprintf("%+v", cfg); strings.Contains(s, "p@ss") {
+		t.Errorf("password leaked: %s", s)
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Redis 各服务共用的 Redis Cluster 配置结构，时长字段使用 "5m"、"30s" 形式的字符串，
+// 配合 rediscluster.FromConfig 一行完成客户端初始化
+// 使用示例（YAML）：
+//
+//	redis:
+//	  addrs: [10.0.0.1:6379, 10.0.0.2:6379, 10.0.0.3:6379]
+//	  pool_size: 100
+//	  read_timeout: 200ms
+//	  command_timeouts:
+//	    read: 50ms
+//	  tls:
+//	    enabled: true
+//	    ca_file: /etc/redis/ca.pem
+//
+// 配置文件不做 ${VAR} 形式的变量展开，密码建议通过 Env 来源注入（NewChain(File(...), Env("APP"))）：
+//
+//	APP_REDIS__PASSWORD=xxx
+type Redis struct {
+	Addrs    []string `yaml:"addrs"`
+	Username string   `yaml:"username"`
+	Password Secret   `yaml:"password"`
+
+	DialTimeout  time.Duration `yaml:"dial_timeout"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	PoolSize     int           `yaml:"pool_size"`
+	MinIdleConns int           `yaml:"min_idle_conns"`
+
+	PingTimeout     time.Duration            `yaml:"ping_timeout"`
+	CommandTimeouts map[string]time.Duration `yaml:"command_timeouts"` // 命令名或 read/write 分类 -> 超时
+
+	TLS TLS `yaml:"tls"`
+}
+
+// TLS 客户端 TLS 配置，证书与私钥使用 PEM 文件路径
+type TLS struct {
+	Enabled            bool   `yaml:"enabled"`
+	CAFile             string `yaml:"ca_file"`   // 为空时使用系统根证书
+	CertFile           string `yaml:"cert_file"` // 双向认证的客户端证书，需与 key_file 同时设置
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`          // 为空时使用连接地址中的主机名
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"` // 跳过证书校验，只应在测试环境使用
+	MinVersion         string `yaml:"min_version"`          // "1.2"（默认）或 "1.3"
+}
+
+// tlsVersions MinVersion 支持的取值
+var tlsVersions = map[string]uint16{
+	"":    tls.VersionTLS12,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Build 读取证书文件并生成 *tls.Config，未启用时返回 nil
+func (t TLS) Build() (*tls.Config, error) {
+	if !t.Enabled {
+		return nil, nil
+	}
+	version, ok := tlsVersions[t.MinVersion]
+	if !ok {
+		return nil, fmt.Errorf("config: unsupported tls min_version %q", t.MinVersion)
+	}
+	cfg := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+		MinVersion:         version,
+	}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("config: read tls ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("config: no certificates found in %s", t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return nil, errors.New("config: tls cert_file and key_file must be set together")
+	}
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("config: load tls key pair: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
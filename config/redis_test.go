@@ -0,0 +1,58 @@
+package config
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRedis_ParseYAML(t *testing.T) {
+	data := []byte(`
+addrs: [10.0.0.1:6379, 10.0.0.2:6379]
+password: s3cret
+pool_size: 20
+read_timeout: 200ms
+command_timeouts:
+  read: 50ms
+  hgetall: 1s
+tls:
+  enabled: true
+  server_name: redis.internal
+  min_version: "1.3"
+`)
+	var cfg Redis
+	if err := ParseYAML(data, &cfg); err != nil {
+		t.Fatalf("ParseYAML: %v", err)
+	}
+	if len(cfg.Addrs) != 2 || cfg.Password.Reveal() != "s3cret" || cfg.PoolSize != 20 || cfg.ReadTimeout != 200*time.Millisecond {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.CommandTimeouts["read"] != 50*time.Millisecond || cfg.CommandTimeouts["hgetall"] != time.Second {
+		t.Errorf("unexpected command timeouts: %v", cfg.CommandTimeouts)
+	}
+	tc, err := cfg.TLS.Build()
+	if err != nil || tc == nil || tc.ServerName != "redis.internal" || tc.MinVersion != tls.VersionTLS13 {
+		t.Fatalf("unexpected tls config: %+v %v", tc, err)
+	}
+}
+
+func TestTLS_Build(t *testing.T) {
+	if tc, err := (TLS{}).Build(); tc != nil || err != nil {
+		t.Errorf("disabled tls should build nil, got %v %v", tc, err)
+	}
+	if _, err := (TLS{Enabled: true, CertFile: "client.pem"}).Build(); err == nil {
+		t.Error("expected error when key_file is missing")
+	}
+	if _, err := (TLS{Enabled: true, MinVersion: "1.0"}).Build(); err == nil {
+		t.Error("expected error for unsupported min_version")
+	}
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(ca, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (TLS{Enabled: true, CAFile: ca}).Build(); err == nil {
+		t.Error("expected error for invalid ca_file")
+	}
+}
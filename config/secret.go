@@ -0,0 +1,50 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+// Secret 敏感字符串（密码、密钥、token），按普通字符串从 YAML/JSON 解析，
+// 但通过 fmt、日志或 JSON 输出时显示为 RedactedValue，只能通过 Reveal 取得原值；
+// YAML 序列化保留原值，以便配置文件的生成与转换
+// 使用示例：
+//
+//	type DBConfig struct {
+//		Addr     string        `yaml:"addr"`
+//		Password config.Secret `yaml:"password"`
+//	}
+//	dsn := fmt.Sprintf("%s:%s@tcp(%s)/app", user, cfg.Password.Reveal(), cfg.Addr)
+type Secret string
+
+// secretType Diff 中识别 Secret 字段
+var secretType = reflect.TypeOf(Secret(""))
+
+// Reveal 返回原始值
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// IsEmpty 判断是否未设置
+func (s Secret) IsEmpty() bool {
+	return s == ""
+}
+
+// String 实现 fmt.Stringer，非空时返回 RedactedValue
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return RedactedValue
+}
+
+// GoString 实现 fmt.GoStringer，避免 %#v 输出原值
+func (s Secret) GoString() string {
+	return "config.Secret(" + strconv.Quote(s.String()) + ")"
+}
+
+// MarshalJSON 实现 json.Marshaler，输出脱敏后的值
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
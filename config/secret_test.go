@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type secretConfig struct {
+	User     string `yaml:"user" json:"user"`
+	Password Secret `yaml:"password" json:"password"`
+}
+
+func TestSecret(t *testing.T) {
+	var cfg secretConfig
+	if err := ParseYAML([]byte("user: root\npassword: p@ss\n"), &cfg); err != nil {
+		t.Fatalf("ParseYAML: %v", err)
+	}
+	if cfg.Password.Reveal() != "p@ss" {
+		t.Fatalf("unexpected password: %q", cfg.Password.Reveal())
+	}
+
+	for _, s := range []string{
+		fmt.Sprint(cfg.Password),
+		fmt.Sprintf("%s %v %q", cfg.Password, cfg, cfg.Password),
+		fmt.Sprintf("%+v", cfg),
+		fmt.Sprintf("%#v", cfg),
+	} {
+		if strings.Contains(s, "p@ss") {
+			t.Fatalf("secret leaked: %s", s)
+		}
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	if string(data) != `{"user":"root","password":"******"}` {
+		t.Fatalf("unexpected json: %s", data)
+	}
+	var fromJSON secretConfig
+	if err := json.Unmarshal([]byte(`{"password":"x"}`), &fromJSON); err != nil || fromJSON.Password.Reveal() != "x" {
+		t.Fatalf("json.Unmarshal: %v %q", err, fromJSON.Password.Reveal())
+	}
+
+	// YAML 保留原值
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("yaml.Marshal: %v", err)
+	}
+	if !strings.Contains(string(out), "p@ss") {
+		t.Fatalf("yaml should keep the value: %s", out)
+	}
+
+	if Secret("").String() != "" || !Secret("").IsEmpty() {
+		t.Fatal("empty secret should print as empty")
+	}
+}
+
+func TestDiff_SecretType(t *testing.T) {
+	changes := Diff(secretConfig{Password: "old"}, secretConfig{Password: "new"})
+	if len(changes) != 1 || !changes[0].Redacted || changes[0].Old != RedactedValue || changes[0].New != RedactedValue {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+}
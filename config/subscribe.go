@@ -0,0 +1,117 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrPathNotFound 配置路径不存在
+var ErrPathNotFound = errors.New("config: path not found")
+
+// OnChange 订阅配置子树，仅当 path 对应的部分发生变化时回调，其他部分的变更不会触发；
+// path 使用 yaml tag 名称以 "." 分隔（如 "logger"、"db.redis"），注册时校验路径是否存在；
+// S 可以是子树本身的类型，也可以是字段结构兼容的其他类型（按 YAML 转换）
+// 使用示例：
+//
+//	err := config.OnChange(w, "logger", func(sub logger.Config) {
+//		logger.Reconfigure(sub)
+//	})
+func OnChange[S, T any](w *Watcher[T], path string, fn func(sub S)) error {
+	if fn == nil {
+		return nil
+	}
+	if _, err := Sub[S](w.Current(), path); err != nil {
+		return err
+	}
+	w.OnReload(func(old, new *T) {
+		ov, _ := lookupPath(reflect.ValueOf(old), path)
+		nv, err := lookupPath(reflect.ValueOf(new), path)
+		if err != nil {
+			return
+		}
+		if ov.IsValid() && reflect.DeepEqual(ov.Interface(), nv.Interface()) {
+			return
+		}
+		sub, err := convertValue[S](nv)
+		if err != nil {
+			w.fail(fmt.Errorf("config: convert %s: %w", path, err))
+			return
+		}
+		fn(sub)
+	})
+	return nil
+}
+
+// Sub 从配置（结构体、map 或其指针）中取出 path 对应的子树并转换为 S
+func Sub[S any](cfg interface{}, path string) (S, error) {
+	var zero S
+	v, err := lookupPath(reflect.ValueOf(cfg), path)
+	if err != nil {
+		return zero, err
+	}
+	sub, err := convertValue[S](v)
+	if err != nil {
+		return zero, fmt.Errorf("config: convert %s: %w", path, err)
+	}
+	return sub, nil
+}
+
+// lookupPath 按 yaml 字段名逐级查找结构体字段或 map 键，path 为空时返回 v 本身
+func lookupPath(v reflect.Value, path string) (reflect.Value, error) {
+	if path == "" {
+		return indirect(v), nil
+	}
+	for _, key := range strings.Split(path, ".") {
+		v = indirect(v)
+		if !v.IsValid() {
+			return reflect.Value{}, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+		}
+		next, ok := child(v, key)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+		}
+		v = next
+	}
+	return v, nil
+}
+
+// child 取结构体字段或 map 键对应的值
+func child(v reflect.Value, key string) (reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.IsExported() && fieldName(f) == key {
+				return v.Field(i), true
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+		mv := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+		return mv, mv.IsValid()
+	}
+	return reflect.Value{}, false
+}
+
+// convertValue 将值转换为 S：类型可直接赋值时直接返回，否则经 YAML 转换
+func convertValue[S any](v reflect.Value) (S, error) {
+	var out S
+	if !v.IsValid() {
+		return out, nil
+	}
+	if s, ok := v.Interface().(S); ok {
+		return s, nil
+	}
+	data, err := yaml.Marshal(v.Interface())
+	if err != nil {
+		return out, err
+	}
+	err = yaml.Unmarshal(data, &out)
+	return out, err
+}
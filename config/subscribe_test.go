@@ -0,0 +1,94 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type subLogger struct {
+	Level string `yaml:"level"`
+}
+
+type subRedis struct {
+	Addr string `yaml:"addr"`
+	DB   int    `yaml:"db"`
+}
+
+type subConfig struct {
+	Logger subLogger            `yaml:"logger"`
+	Redis  *subRedis            `yaml:"redis"`
+	Labels map[string]string    `yaml:"labels"`
+	Extra  map[string]subLogger `yaml:"extra"`
+}
+
+// redisView 与 subRedis 字段兼容的独立类型
+type redisView struct {
+	Addr string `yaml:"addr"`
+}
+
+func TestOnChange(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "cfg.yaml")
+	write := func(s string) {
+		t.Helper()
+		if err := os.WriteFile(p, []byte(s), 0o644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+	write("logger:\n  level: info\nredis:\n  addr: a:6379\n")
+	w, err := NewWatcher[subConfig](p)
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+
+	var loggerCalls, redisCalls []string
+	if err := OnChange(w, "logger", func(sub subLogger) { loggerCalls = append(loggerCalls, sub.Level) }); err != nil {
+		t.Fatalf("OnChange logger: %v", err)
+	}
+	if err := OnChange(w, "redis", func(sub redisView) { redisCalls = append(redisCalls, sub.Addr) }); err != nil {
+		t.Fatalf("OnChange redis: %v", err)
+	}
+	if err := OnChange(w, "redis.missing", func(sub string) {}); !errors.Is(err, ErrPathNotFound) {
+		t.Fatalf("expected ErrPathNotFound, got %v", err)
+	}
+
+	// 只改 redis，logger 订阅不触发
+	write("logger:\n  level: info\nredis:\n  addr: b:6379\n")
+	if _, err := w.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	write("logger:\n  level: debug\nredis:\n  addr: b:6379\n")
+	if _, err := w.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if len(loggerCalls) != 1 || loggerCalls[0] != "debug" {
+		t.Fatalf("unexpected logger calls: %v", loggerCalls)
+	}
+	if len(redisCalls) != 1 || redisCalls[0] != "b:6379" {
+		t.Fatalf("unexpected redis calls: %v", redisCalls)
+	}
+}
+
+func TestSub(t *testing.T) {
+	cfg := &subConfig{
+		Logger: subLogger{Level: "warn"},
+		Labels: map[string]string{"env": "prod"},
+		Extra:  map[string]subLogger{"audit": {Level: "error"}},
+	}
+	if l, err := Sub[subLogger](cfg, "logger"); err != nil || l.Level != "warn" {
+		t.Fatalf("Sub logger = %+v, %v", l, err)
+	}
+	if v, err := Sub[string](cfg, "labels.env"); err != nil || v != "prod" {
+		t.Fatalf("Sub labels.env = %q, %v", v, err)
+	}
+	if v, err := Sub[string](cfg, "extra.audit.level"); err != nil || v != "error" {
+		t.Fatalf("Sub extra.audit.level = %q, %v", v, err)
+	}
+	if _, err := Sub[string](cfg, "redis.addr"); !errors.Is(err, ErrPathNotFound) {
+		t.Fatalf("expected ErrPathNotFound through nil pointer, got %v", err)
+	}
+	if r, err := Sub[*subRedis](cfg, "redis"); err != nil || r != nil {
+		t.Fatalf("Sub redis = %v, %v", r, err)
+	}
+}
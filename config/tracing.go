@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// 采样策略，取值与 OTEL_TRACES_SAMPLER 环境变量一致
+const (
+	SamplerAlwaysOn                = "always_on"
+	SamplerAlwaysOff               = "always_off"
+	SamplerTraceIDRatio            = "traceidratio"
+	SamplerParentBasedAlwaysOn     = "parentbased_always_on"
+	SamplerParentBasedTraceIDRatio = "parentbased_traceidratio"
+)
+
+// Tracing 链路追踪配置，配合 otelx.Init 一行完成 OTLP 导出器、资源属性与采样器的初始化
+// 使用示例（YAML）：
+//
+//	tracing:
+//	  enabled: true
+//	  service_name: order-svc
+//	  environment: prod
+//	  endpoint: otel-collector:4318
+//	  insecure: true
+//	  sampler: parentbased_traceidratio
+//	  sample_ratio: 0.1
+type Tracing struct {
+	Enabled        bool              `yaml:"enabled"`
+	ServiceName    string            `yaml:"service_name"`
+	ServiceVersion string            `yaml:"service_version"`
+	Environment    string            `yaml:"environment"` // 写入 deployment.environment 属性
+	Attributes     map[string]string `yaml:"attributes"`  // 额外的资源属性，如 region: cn-east
+
+	Endpoint string            `yaml:"endpoint"` // OTLP/HTTP 地址，host:port 或完整 URL，默认 localhost:4318
+	Insecure bool              `yaml:"insecure"` // 使用 HTTP 而非 HTTPS
+	Headers  map[string]Secret `yaml:"headers"`  // 导出时附带的请求头，如鉴权 token
+	Timeout  time.Duration     `yaml:"timeout"`  // 单次导出超时，默认 10 秒
+	TLS      TLS               `yaml:"tls"`
+
+	Sampler     string   `yaml:"sampler"`      // 见 Sampler* 常量，默认 parentbased_traceidratio
+	SampleRatio *float64 `yaml:"sample_ratio"` // 按比例采样时的比例，未配置时为 1（全部采样），0 表示不采样
+}
+
+// Ratio 按比例采样时的比例，未配置 sample_ratio 时为 1
+func (t Tracing) Ratio() float64 {
+	if t.SampleRatio == nil {
+		return 1
+	}
+	return *t.SampleRatio
+}
+
+// Validate 检查采样配置
+func (t Tracing) Validate() error {
+	switch t.Sampler {
+	case "", SamplerAlwaysOn, SamplerAlwaysOff, SamplerTraceIDRatio, SamplerParentBasedAlwaysOn, SamplerParentBasedTraceIDRatio:
+	default:
+		return fmt.Errorf("config: unsupported tracing sampler %q", t.Sampler)
+	}
+	if r := t.Ratio(); r < 0 || r > 1 {
+		return fmt.Errorf("config: tracing sample_ratio %v out of range [0, 1]", r)
+	}
+	return nil
+}
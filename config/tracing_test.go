@@ -0,0 +1,39 @@
+package config
+
+import "testing"
+
+func TestTracing_ParseYAML(t *testing.T) {
+	data := []byte(`
+enabled: true
+service_name: order-svc
+endpoint: https://otel.example.com/v1/traces
+headers:
+  authorization: Bearer t0ken
+sampler: traceidratio
+sample_ratio: 0.25
+`)
+	var cfg Tracing
+	if err := ParseYAML(data, &cfg); err != nil {
+		t.Fatalf("ParseYAML: %v", err)
+	}
+	if !cfg.Enabled || cfg.ServiceName != "order-svc" || cfg.Ratio() != 0.25 || cfg.Headers["authorization"].Reveal() != "Bearer t0ken" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Error(err)
+	}
+	if err := (Tracing{Sampler: "sometimes"}).Validate(); err == nil {
+		t.Error("expected error for unknown sampler")
+	}
+	ratio := 1.5
+	if err := (Tracing{SampleRatio: &ratio}).Validate(); err == nil {
+		t.Error("expected error for ratio out of range")
+	}
+	// 未配置与显式配置为 0 含义不同：前者全部采样，后者不采样
+	if err := ParseYAML([]byte("sample_ratio: 0\n"), &cfg); err != nil || cfg.Ratio() != 0 {
+		t.Errorf("sample_ratio 0: ratio = %v, err = %v", cfg.Ratio(), err)
+	}
+	if r := (Tracing{}).Ratio(); r != 1 {
+		t.Errorf("unset sample_ratio: ratio = %v, want 1", r)
+	}
+}
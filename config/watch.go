@@ -0,0 +1,167 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultWatchInterval 默认轮询间隔
+const defaultWatchInterval = 5 * time.Second
+
+// Loader 从文件加载配置到 out，默认为 LoadYAML；加密配置可用
+//
+//	func(path string, out interface{}) error { return LoadEncryptedYAML(path, key, out) }
+type Loader func(path string, out interface{}) error
+
+// WatchOption Watcher 选项
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	interval time.Duration
+	loader   Loader
+}
+
+// WithWatchInterval 设置轮询间隔，默认 5 秒
+func WithWatchInterval(d time.Duration) WatchOption {
+	return func(o *watchOptions) { o.interval = d }
+}
+
+// WithLoader 设置加载函数，默认 LoadYAML
+func WithLoader(l Loader) WatchOption {
+	return func(o *watchOptions) { o.loader = l }
+}
+
+// Watcher 监听配置文件并热加载
+// 通过轮询文件内容实现（兼容 k8s ConfigMap 的软链接替换），内容变化时重新解析，
+// 解析失败时保留旧配置并记录错误；新配置生效后按注册顺序调用 OnReload 回调
+// 使用示例：
+//
+//	w, err := config.NewWatcher[AppConfig]("config.yaml")
+//	w.OnReload(func(old, new *AppConfig) {
+//		for _, c := range config.Diff(old, new) {
+//			log.Printf("config changed: %s", c)
+//		}
+//	})
+//	go w.Run(ctx)
+type Watcher[T any] struct {
+	path string
+	opts watchOptions
+
+	mu       sync.RWMutex
+	current  *T
+	raw      []byte
+	hooks    []func(old, new *T)
+	loadedAt time.Time
+	lastErr  error
+}
+
+// NewWatcher 加载一次配置并创建 Watcher，首次加载失败时返回错误
+func NewWatcher[T any](path string, opts ...WatchOption) (*Watcher[T], error) {
+	o := watchOptions{interval: defaultWatchInterval, loader: LoadYAML}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	w := &Watcher[T]{path: path, opts: o}
+	raw, cfg, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.current, w.raw, w.loadedAt = cfg, raw, time.Now()
+	return w, nil
+}
+
+// Current 返回当前生效的配置，调用方不应修改返回值
+func (w *Watcher[T]) Current() *T {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// OnReload 注册配置变更回调，回调在轮询协程中同步执行；只关心某个子树时使用 OnChange
+func (w *Watcher[T]) OnReload(fn func(old, new *T)) {
+	if fn == nil {
+		return
+	}
+	w.mu.Lock()
+	w.hooks = append(w.hooks, fn)
+	w.mu.Unlock()
+}
+
+// LastReload 返回最近一次成功加载的时间，以及最近一次加载的错误（成功时为 nil）
+func (w *Watcher[T]) LastReload() (time.Time, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.loadedAt, w.lastErr
+}
+
+// Run 按间隔轮询配置文件直到 ctx 结束
+func (w *Watcher[T]) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = w.Reload()
+		}
+	}
+}
+
+// Reload 立即检查一次配置文件，内容变化且解析成功时生效并返回 true
+func (w *Watcher[T]) Reload() (bool, error) {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return false, w.fail(fmt.Errorf("read file: %w", err))
+	}
+	w.mu.Lock()
+	same := bytes.Equal(data, w.raw)
+	if same {
+		// 错误的修改被还原为已加载的内容时，清除之前记录的错误
+		w.lastErr = nil
+	}
+	w.mu.Unlock()
+	if same {
+		return false, nil
+	}
+
+	raw, cfg, err := w.load()
+	if err != nil {
+		return false, w.fail(err)
+	}
+	w.mu.Lock()
+	old := w.current
+	w.current, w.raw, w.loadedAt, w.lastErr = cfg, raw, time.Now(), nil
+	hooks := append([]func(old, new *T){}, w.hooks...)
+	w.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn(old, cfg)
+	}
+	return true, nil
+}
+
+// load 读取并解析配置文件
+func (w *Watcher[T]) load() ([]byte, *T, error) {
+	raw, err := os.ReadFile(w.path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read file: %w", err)
+	}
+	cfg := new(T)
+	if err := w.opts.loader(w.path, cfg); err != nil {
+		return nil, nil, err
+	}
+	return raw, cfg, nil
+}
+
+// fail 记录加载错误
+func (w *Watcher[T]) fail(err error) error {
+	w.mu.Lock()
+	w.lastErr = err
+	w.mu.Unlock()
+	return err
+}
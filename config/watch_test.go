@@ -0,0 +1,59 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcher_Reload(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "cfg.yaml")
+	if err := os.WriteFile(p, []byte("name: app\nport: 8080\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	w, err := NewWatcher[appConfig](p, WithWatchInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+	if w.Current().Port != 8080 {
+		t.Fatalf("unexpected cfg: %+v", w.Current())
+	}
+
+	changed := make(chan []Change, 1)
+	w.OnReload(func(old, new *appConfig) { changed <- Diff(old, new) })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	if err := os.WriteFile(p, []byte("name: app\nport: 9090\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	select {
+	case changes := <-changed:
+		if len(changes) != 1 || changes[0].Path != "port" {
+			t.Fatalf("unexpected changes: %v", changes)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected reload")
+	}
+	if w.Current().Port != 9090 {
+		t.Fatalf("unexpected cfg: %+v", w.Current())
+	}
+
+	// 解析失败时保留旧配置
+	cancel()
+	if err := os.WriteFile(p, []byte("port: [\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if ok, err := w.Reload(); ok || err == nil {
+		t.Fatalf("expected reload error, got %v %v", ok, err)
+	}
+	if _, err := w.LastReload(); err == nil {
+		t.Fatal("expected LastReload error")
+	}
+	if w.Current().Port != 9090 {
+		t.Fatalf("config should be kept: %+v", w.Current())
+	}
+}
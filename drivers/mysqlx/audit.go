@@ -0,0 +1,297 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 审计动作
+const (
+	AuditInsert = "INSERT"
+	AuditUpdate = "UPDATE"
+	AuditDelete = "DELETE"
+)
+
+// ErrAuditTooManyRows 单次变更涉及的行数超过 AuditConfig.MaxRows
+var ErrAuditTooManyRows = errors.New("mysqlx: too many rows for audited change")
+
+// AuditConfig 数据变更审计配置
+type AuditConfig struct {
+	Table      string                           // 审计表名，默认 audit_log，表结构见 AuditTableDDL
+	PrimaryKey string                           // 被审计表的主键列，默认 id（仅支持单列主键）
+	MaxRows    int                              // 单次变更允许的最大行数，默认 1000，防止误操作全表时写入海量审计记录
+	Operator   func(ctx context.Context) string // 从 context 中取操作人，可为空
+}
+
+// Auditor 在同一事务内执行 INSERT/UPDATE/DELETE 并记录变更前后的行镜像，用于敏感表的合规审计
+// 变更前镜像通过 SELECT ... FOR UPDATE 读取，保证与实际修改的行一致；
+// 审计记录与业务修改在同一事务中提交或回滚
+type Auditor struct {
+	cfg AuditConfig
+}
+
+// NewAuditor 创建审计器
+func NewAuditor(cfg AuditConfig) *Auditor {
+	if cfg.Table == "" {
+		cfg.Table = "audit_log"
+	}
+	if cfg.PrimaryKey == "" {
+		cfg.PrimaryKey = "id"
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = 1000
+	}
+	return &Auditor{cfg: cfg}
+}
+
+// AuditTableDDL 返回审计表的建表语句
+func AuditTableDDL(table string) string {
+	if table == "" {
+		table = "audit_log"
+	}
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  table_name VARCHAR(64) NOT NULL,
+  pk VARCHAR(191) NOT NULL,
+  action VARCHAR(16) NOT NULL,
+  before_data JSON NULL,
+  after_data JSON NULL,
+  operator VARCHAR(128) NOT NULL DEFAULT '',
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  KEY idx_table_pk (table_name, pk),
+  KEY idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`, QuoteIdent(table))
+}
+
+// ErrAuditNoPrimaryKey Insert 的 row 中没有主键且表没有自增主键，无法定位插入的行
+var ErrAuditNoPrimaryKey = errors.New("mysqlx: audited insert needs the primary key in row or an auto-increment key")
+
+// Insert 插入一行并记录变更后镜像。主键优先取 row 中的值，未包含时使用自增 ID，
+// 两者都没有时返回 ErrAuditNoPrimaryKey（事务需由调用方回滚）
+func (a *Auditor) Insert(ctx context.Context, tx *sql.Tx, table string, row map[string]interface{}) (sql.Result, error) {
+	if len(row) == 0 {
+		return nil, errors.New("mysqlx: insert row must not be empty")
+	}
+	cols := sortedColumns(row)
+	quoted := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+	for i, c := range cols {
+		quoted[i] = QuoteIdent(c)
+		args[i] = row[c]
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", QuoteIdent(table), strings.Join(quoted, ", "), placeholders(len(cols)))
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	pk, ok := row[a.cfg.PrimaryKey]
+	if !ok {
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		// 非自增主键（如默认值生成的 UUID）LastInsertId 为 0，不能用来回查
+		if id == 0 {
+			return nil, ErrAuditNoPrimaryKey
+		}
+		pk = id
+	}
+	after, err := a.selectRows(ctx, tx, table, QuoteIdent(a.cfg.PrimaryKey)+" = ?", false, pk)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range after {
+		if err := a.record(ctx, tx, table, AuditInsert, r[a.cfg.PrimaryKey], nil, r); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Update 更新匹配 where 的行并记录变更前后镜像，返回受影响行数。
+// set 中包含主键时按新主键回查变更后镜像，此时 where 只能匹配一行（否则主键冲突）
+func (a *Auditor) Update(ctx context.Context, tx *sql.Tx, table string, set map[string]interface{}, where string, args ...interface{}) (int64, error) {
+	if len(set) == 0 {
+		return 0, errors.New("mysqlx: update set must not be empty")
+	}
+	if strings.TrimSpace(where) == "" {
+		return 0, ErrEmptyWhere
+	}
+	before, err := a.selectRows(ctx, tx, table, where, true, args...)
+	if err != nil || len(before) == 0 {
+		return 0, err
+	}
+
+	// 按主键更新已锁定的行，避免 where 在两次执行之间匹配到不同的行
+	pks := primaryKeys(before, a.cfg.PrimaryKey)
+	cols := sortedColumns(set)
+	assigns := make([]string, len(cols))
+	updateArgs := make([]interface{}, 0, len(cols)+len(pks))
+	for i, c := range cols {
+		assigns[i] = QuoteIdent(c) + " = ?"
+		updateArgs = append(updateArgs, set[c])
+	}
+	updateArgs = append(updateArgs, pks...)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s IN (%s)", QuoteIdent(table), strings.Join(assigns, ", "), QuoteIdent(a.cfg.PrimaryKey), placeholders(len(pks)))
+	n, err := execAffected(ctx, tx, query, updateArgs...)
+	if err != nil {
+		return 0, err
+	}
+
+	// 修改了主键时旧主键已不存在，按语句参数中的新主键回查
+	afterPKs := pks
+	newPK, pkChanged := set[a.cfg.PrimaryKey]
+	if pkChanged {
+		afterPKs = []interface{}{newPK}
+	}
+	after, err := a.selectRows(ctx, tx, table, fmt.Sprintf("%s IN (%s)", QuoteIdent(a.cfg.PrimaryKey), placeholders(len(afterPKs))), false, afterPKs...)
+	if err != nil {
+		return 0, err
+	}
+	afterByPK := make(map[string]map[string]interface{}, len(after))
+	for _, r := range after {
+		afterByPK[fmt.Sprint(r[a.cfg.PrimaryKey])] = r
+	}
+	for _, r := range before {
+		pk := r[a.cfg.PrimaryKey]
+		key := pk
+		if pkChanged {
+			key = newPK
+		}
+		if err := a.record(ctx, tx, table, AuditUpdate, pk, r, afterByPK[fmt.Sprint(key)]); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// Delete 删除匹配 where 的行并记录变更前镜像，返回受影响行数
+func (a *Auditor) Delete(ctx context.Context, tx *sql.Tx, table, where string, args ...interface{}) (int64, error) {
+	if strings.TrimSpace(where) == "" {
+		return 0, ErrEmptyWhere
+	}
+	before, err := a.selectRows(ctx, tx, table, where, true, args...)
+	if err != nil || len(before) == 0 {
+		return 0, err
+	}
+	pks := primaryKeys(before, a.cfg.PrimaryKey)
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)", QuoteIdent(table), QuoteIdent(a.cfg.PrimaryKey), placeholders(len(pks)))
+	n, err := execAffected(ctx, tx, query, pks...)
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range before {
+		if err := a.record(ctx, tx, table, AuditDelete, r[a.cfg.PrimaryKey], r, nil); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// selectRows 读取匹配行的完整镜像，forUpdate 为 true 时加行锁
+func (a *Auditor) selectRows(ctx context.Context, tx *sql.Tx, table, where string, forUpdate bool, args ...interface{}) ([]map[string]interface{}, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s LIMIT %d", QuoteIdent(table), where, a.cfg.MaxRows+1)
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var out []map[string]interface{}
+	for rows.Next() {
+		// 扫描到 interface{} 以保留 NULL（nil）与空串的区别；RawBytes 会把两者都扫描为 nil
+		values := make([]interface{}, len(cols))
+		dest := make([]interface{}, len(cols))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(cols))
+		for i, c := range cols {
+			// 文本协议返回 []byte，转为字符串便于 JSON 序列化；数值、时间等类型保持原样
+			if b, ok := values[i].([]byte); ok {
+				row[c] = string(b)
+			} else {
+				row[c] = values[i]
+			}
+		}
+		out = append(out, row)
+		if len(out) > a.cfg.MaxRows {
+			return nil, ErrAuditTooManyRows
+		}
+	}
+	return out, rows.Err()
+}
+
+// record 写入一条审计记录
+func (a *Auditor) record(ctx context.Context, tx *sql.Tx, table, action string, pk interface{}, before, after map[string]interface{}) error {
+	beforeJSON, err := marshalImage(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := marshalImage(after)
+	if err != nil {
+		return err
+	}
+	operator := ""
+	if a.cfg.Operator != nil {
+		operator = a.cfg.Operator(ctx)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (table_name, pk, action, before_data, after_data, operator) VALUES (?, ?, ?, ?, ?, ?)", QuoteIdent(a.cfg.Table))
+	_, err = tx.ExecContext(ctx, query, table, fmt.Sprint(pk), action, beforeJSON, afterJSON, operator)
+	return err
+}
+
+// marshalImage 行镜像序列化为 JSON，nil 时写入 NULL
+func marshalImage(row map[string]interface{}) (interface{}, error) {
+	if row == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// primaryKeys 提取行镜像中的主键值
+func primaryKeys(rows []map[string]interface{}, pk string) []interface{} {
+	out := make([]interface{}, len(rows))
+	for i, r := range rows {
+		out[i] = r[pk]
+	}
+	return out
+}
+
+// sortedColumns 列名排序，保证生成的 SQL 稳定
+func sortedColumns(m map[string]interface{}) []string {
+	cols := make([]string, 0, len(m))
+	for c := range m {
+		cols = append(cols, c)
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+// placeholders 生成 n 个以逗号分隔的占位符
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat("?, ", n-1) + "?"
+}
@@ -0,0 +1,82 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// newMockTx 创建 sqlmock 连接并开启事务，SQL 按全文精确匹配
+func newMockTx(t *testing.T) (*sql.Tx, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	return tx, mock
+}
+
+const auditInsertSQL = "INSERT INTO `audit_log` (table_name, pk, action, before_data, after_data, operator) VALUES (?, ?, ?, ?, ?, ?)"
+
+// TestAuditor_UpdateNullAndPKChange 镜像区分 NULL 与空串；修改主键时按新主键回查变更后镜像
+func TestAuditor_UpdateNullAndPKChange(t *testing.T) {
+	tx, mock := newMockTx(t)
+	a := NewAuditor(AuditConfig{})
+
+	mock.ExpectQuery("SELECT * FROM `user` WHERE id = ? LIMIT 1001 FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nick", "memo"}).AddRow("1", "", nil))
+	mock.ExpectExec("UPDATE `user` SET `id` = ?, `memo` = ? WHERE `id` IN (?)").WithArgs(2, "", "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT * FROM `user` WHERE `id` IN (?) LIMIT 1001").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nick", "memo"}).AddRow("2", "", ""))
+	mock.ExpectExec(auditInsertSQL).
+		WithArgs("user", "1", AuditUpdate, `{"id":"1","memo":null,"nick":""}`, `{"id":"2","memo":"","nick":""}`, "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	n, err := a.Update(context.Background(), tx, "user", map[string]interface{}{"id": 2, "memo": ""}, "id = ?", 1)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("affected = %d, want 1", n)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestAuditor_InsertPrimaryKey 主键取自 row；没有主键且非自增时返回 ErrAuditNoPrimaryKey
+func TestAuditor_InsertPrimaryKey(t *testing.T) {
+	tx, mock := newMockTx(t)
+	a := NewAuditor(AuditConfig{PrimaryKey: "code"})
+
+	mock.ExpectExec("INSERT INTO `coupon` (`code`, `value`) VALUES (?, ?)").WithArgs("00123", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT * FROM `coupon` WHERE `code` = ? LIMIT 1001").WithArgs("00123").
+		WillReturnRows(sqlmock.NewRows([]string{"code", "value"}).AddRow("00123", int64(5)))
+	mock.ExpectExec(auditInsertSQL).
+		WithArgs("coupon", "00123", AuditInsert, nil, `{"code":"00123","value":5}`, "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	if _, err := a.Insert(context.Background(), tx, "coupon", map[string]interface{}{"code": "00123", "value": 5}); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	mock.ExpectExec("INSERT INTO `coupon` (`value`) VALUES (?)").WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err := a.Insert(context.Background(), tx, "coupon", map[string]interface{}{"value": 5})
+	if !errors.Is(err, ErrAuditNoPrimaryKey) {
+		t.Fatalf("Insert without pk: err = %v, want ErrAuditNoPrimaryKey", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,190 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/qingfeng-studio/go-utils/utils/backoff"
+)
+
+const (
+	// defaultChunkPause 相邻两批之间默认的停顿时间
+	defaultChunkPause = 100 * time.Millisecond
+	// defaultChunkRetries 单批遇到死锁或锁等待超时时默认的重试次数
+	defaultChunkRetries = 3
+	// maxChunkBackoff 重试退避的上限
+	maxChunkBackoff = 10 * time.Second
+)
+
+// chunkOptions 分批处理选项
+type chunkOptions struct {
+	args    []interface{}
+	start   interface{}
+	pause   time.Duration
+	retries int
+}
+
+// ChunkOption 分批处理的函数式选项
+type ChunkOption func(*chunkOptions)
+
+// WithChunkArgs 设置查询中主键条件之前的其它占位符参数
+func WithChunkArgs(args ...interface{}) ChunkOption {
+	return func(o *chunkOptions) { o.args = append(o.args, args...) }
+}
+
+// WithChunkStart 从指定主键之后开始处理，用于中断后续跑（默认为 0）
+func WithChunkStart(key interface{}) ChunkOption {
+	return func(o *chunkOptions) { o.start = key }
+}
+
+// WithChunkPause 设置相邻两批之间的停顿时间（默认 100ms），给线上流量与主从复制留出余量
+func WithChunkPause(d time.Duration) ChunkOption {
+	return func(o *chunkOptions) { o.pause = d }
+}
+
+// WithChunkRetries 设置单批遇到死锁（1213）或锁等待超时（1205）时的重试次数（默认 3），
+// 重试间隔从停顿时间起按指数退避，最长 10 秒
+func WithChunkRetries(n int) ChunkOption {
+	return func(o *chunkOptions) { o.retries = n }
+}
+
+// ChunkedSelect 按主键范围分批遍历大表，每批调用一次 fn，批与批之间停顿一段时间，
+// 用于数据回填、迁移等不能长时间锁表的场景；每批是一条独立的短查询，不会持有跨批的锁
+// query 的第一列必须是主键，并以主键条件、ORDER BY 主键与 LIMIT 结尾，最后两个占位符依次为上一批的最大主键与 chunkSize：
+//
+//	err := mysqlx.ChunkedSelect(ctx, db,
+//		"SELECT id, email FROM users WHERE status = ? AND id > ? ORDER BY id LIMIT ?", 500,
+//		func(ctx context.Context, rows []map[string]interface{}) error {
+//			return backfill(ctx, rows) // 重试时同一批可能被处理多次，fn 需保证幂等
+//		},
+//		mysqlx.WithChunkArgs("active"))
+//
+// 行中的值为字符串（NULL 为 nil）；fn 返回错误时停止遍历并返回该错误（死锁与锁等待超时会先按 WithChunkRetries 重试）
+func ChunkedSelect(ctx context.Context, db Queryer, query string, chunkSize int, fn func(ctx context.Context, rows []map[string]interface{}) error, opts ...ChunkOption) error {
+	if chunkSize <= 0 {
+		return errors.New("mysqlx: chunk size must be positive")
+	}
+	o := chunkOptions{start: 0, pause: defaultChunkPause, retries: defaultChunkRetries}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	last := o.start
+	for {
+		var (
+			rows []map[string]interface{}
+			next interface{}
+			err  error
+		)
+		for attempt := 0; ; attempt++ {
+			rows, next, err = selectChunk(ctx, db, query, append(o.args[:len(o.args):len(o.args)], last, chunkSize))
+			if err == nil && len(rows) > 0 {
+				err = fn(ctx, rows)
+			}
+			if err == nil || attempt >= o.retries || !retryableLockError(err) {
+				break
+			}
+			if werr := backoff.Sleep(ctx, chunkBackoff(o.pause, attempt)); werr != nil {
+				return werr
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("mysqlx: chunk after key %v: %w", last, err)
+		}
+		if len(rows) < chunkSize {
+			return nil
+		}
+		last = next
+		if err := backoff.Sleep(ctx, o.pause); err != nil {
+			return err
+		}
+	}
+}
+
+// selectChunk 查询一批数据，返回行与最后一行的主键
+func selectChunk(ctx context.Context, db Queryer, query string, args []interface{}) ([]map[string]interface{}, interface{}, error) {
+	rs, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rs.Close()
+
+	cols, err := rs.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	types, err := rs.ColumnTypes()
+	if err != nil {
+		return nil, nil, err
+	}
+	intKey := integerColumn(types[0])
+	var (
+		out  []map[string]interface{}
+		last interface{}
+	)
+	for rs.Next() {
+		values := make([]sql.RawBytes, len(cols))
+		dest := make([]interface{}, len(cols))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rs.Scan(dest...); err != nil {
+			return nil, nil, err
+		}
+		if values[0] == nil {
+			return nil, nil, errors.New("mysqlx: chunk key column must not be NULL")
+		}
+		row := make(map[string]interface{}, len(cols))
+		for i, c := range cols {
+			if values[i] == nil {
+				row[c] = nil
+			} else {
+				row[c] = string(values[i])
+			}
+		}
+		out = append(out, row)
+		last = chunkKey(values[0], intKey)
+	}
+	return out, last, rs.Err()
+}
+
+// integerColumn 判断列是否为整数类型（TINYINT ~ BIGINT，含 UNSIGNED），驱动未提供类型名时按非整数处理
+func integerColumn(ct *sql.ColumnType) bool {
+	return strings.HasSuffix(strings.ToUpper(ct.DatabaseTypeName()), "INT")
+}
+
+// chunkKey 返回传入下一批查询的主键：整数列按整数传入，避免与字符串比较时按浮点数转换丢失精度；
+// 其它列（如 VARCHAR 主键 "00123"）保持字符串，否则会按数值比较导致全表转换、无法使用索引且顺序错误
+func chunkKey(b sql.RawBytes, intKey bool) interface{} {
+	s := string(b)
+	if !intKey {
+		return s
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return n
+	}
+	return s
+}
+
+// retryableLockError 判断是否为可重试的死锁或锁等待超时错误
+func retryableLockError(err error) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && (me.Number == 1213 || me.Number == 1205)
+}
+
+// chunkBackoff 第 attempt 次重试前的等待时间
+func chunkBackoff(pause time.Duration, attempt int) time.Duration {
+	if pause <= 0 {
+		pause = defaultChunkPause
+	}
+	return backoff.Exponential(pause, maxChunkBackoff).Delay(attempt, 0)
+}
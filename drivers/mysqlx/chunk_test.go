@@ -0,0 +1,95 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+)
+
+// newMockDB 创建 sqlmock 连接，SQL 按全文精确匹配
+func newMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db, mock
+}
+
+// keyRows 返回第一列类型为 typ 的结果集
+func keyRows(typ string, keys ...interface{}) *sqlmock.Rows {
+	rows := sqlmock.NewRowsWithColumnDefinition(sqlmock.NewColumn("id").OfType(typ, ""), sqlmock.NewColumn("v").OfType("VARCHAR", ""))
+	for _, k := range keys {
+		rows.AddRow(k, "x")
+	}
+	return rows
+}
+
+// collectChunks 遍历并返回各批的主键
+func collectChunks(t *testing.T, db Queryer, query string, opts ...ChunkOption) [][]interface{} {
+	t.Helper()
+	var got [][]interface{}
+	err := ChunkedSelect(context.Background(), db, query, 2, func(ctx context.Context, rows []map[string]interface{}) error {
+		var keys []interface{}
+		for _, r := range rows {
+			keys = append(keys, r["id"])
+		}
+		got = append(got, keys)
+		return nil
+	}, append([]ChunkOption{WithChunkPause(0)}, opts...)...)
+	if err != nil {
+		t.Fatalf("ChunkedSelect: %v", err)
+	}
+	return got
+}
+
+func TestChunkedSelect_IntegerKey(t *testing.T) {
+	db, mock := newMockDB(t)
+	const q = "SELECT id, v FROM t WHERE status = ? AND id > ? ORDER BY id LIMIT ?"
+	mock.ExpectQuery(q).WithArgs("active", 0, 2).WillReturnRows(keyRows("UNSIGNED BIGINT", "1", "9007199254740993"))
+	mock.ExpectQuery(q).WithArgs("active", int64(9007199254740993), 2).WillReturnRows(keyRows("UNSIGNED BIGINT", "9007199254740995"))
+
+	got := collectChunks(t, db, q, WithChunkArgs("active"))
+	if len(got) != 2 || len(got[0]) != 2 || len(got[1]) != 1 {
+		t.Fatalf("chunks = %v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestChunkedSelect_VarcharKey 字符串主键保持字符串，"00123" 不能变成 123
+func TestChunkedSelect_VarcharKey(t *testing.T) {
+	db, mock := newMockDB(t)
+	const q = "SELECT id, v FROM t WHERE id > ? ORDER BY id LIMIT ?"
+	mock.ExpectQuery(q).WithArgs("", 2).WillReturnRows(keyRows("VARCHAR", "00099", "00123"))
+	mock.ExpectQuery(q).WithArgs("00123", 2).WillReturnRows(keyRows("VARCHAR"))
+
+	got := collectChunks(t, db, q, WithChunkStart(""))
+	if len(got) != 1 || got[0][1] != "00123" {
+		t.Fatalf("chunks = %v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestChunkedSelect_RetryDeadlock 死锁时重试同一批
+func TestChunkedSelect_RetryDeadlock(t *testing.T) {
+	db, mock := newMockDB(t)
+	const q = "SELECT id, v FROM t WHERE id > ? ORDER BY id LIMIT ?"
+	mock.ExpectQuery(q).WithArgs(0, 2).WillReturnError(&mysql.MySQLError{Number: 1213, Message: "deadlock"})
+	mock.ExpectQuery(q).WithArgs(0, 2).WillReturnRows(keyRows("INT", "1"))
+
+	got := collectChunks(t, db, q)
+	if len(got) != 1 || got[0][0] != "1" {
+		t.Fatalf("chunks = %v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
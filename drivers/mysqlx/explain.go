@@ -0,0 +1,237 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultExplainPerMinute 每分钟最多执行 EXPLAIN 的默认次数
+	defaultExplainPerMinute = 6
+	// explainTimeout 单次 EXPLAIN 的超时
+	explainTimeout = 5 * time.Second
+	// explainDedupWindow 同一语句在该时间内只 EXPLAIN 一次
+	explainDedupWindow = 10 * time.Minute
+)
+
+// SlowQuery 慢查询信息，Plan 为 EXPLAIN 的结果（每行为列名 -> 值），
+// 超出频率限制或语句不支持 EXPLAIN 时为空，EXPLAIN 失败时 ExplainErr 不为空
+type SlowQuery struct {
+	Query      string
+	Args       []any
+	Duration   time.Duration
+	Plan       []map[string]string
+	ExplainErr error
+}
+
+// WithExplainSlow 开启慢查询诊断：耗时超过 threshold 的语句在独立连接上以相同参数重新执行 EXPLAIN，
+// 并将执行计划写入日志（或交给 WithSlowQueryHook），用于预发环境快速定位慢查询；
+// perMinute 为每分钟最多执行 EXPLAIN 的次数，<= 0 时为 6 次，同一语句 10 分钟内只 EXPLAIN 一次
+// 计时范围与 WithKillOnCancel 相同，为语句执行到返回结果集之前
+func WithExplainSlow(threshold time.Duration, perMinute int) Option {
+	return func(c *Config) {
+		c.SlowThreshold = threshold
+		c.ExplainPerMinute = perMinute
+	}
+}
+
+// WithSlowQueryHook 设置慢查询的处理函数，替代默认的 logger.Warn 输出，需配合 WithExplainSlow 使用；
+// fn 在后台协程中调用，ctx 保留原查询 context 中的值（traceId 等）但不受其取消影响
+func WithSlowQueryHook(fn func(ctx context.Context, q SlowQuery)) Option {
+	return func(c *Config) { c.SlowQueryHook = fn }
+}
+
+// NewExplainConnector 包装已有的 Connector，耗时超过 threshold 的语句自动 EXPLAIN 并交给 hook 处理，
+// hook 为 nil 时使用 logger.Warn 输出；用于自行通过 sql.OpenDB 创建连接池的场景，参数含义见 WithExplainSlow
+// EXPLAIN 通过同一 Connector 的独立连接池执行，返回的 Connector 会在 sql.DB 关闭时一并关闭它
+func NewExplainConnector(base driver.Connector, threshold time.Duration, perMinute int, hook func(ctx context.Context, q SlowQuery)) driver.Connector {
+	if perMinute <= 0 {
+		perMinute = defaultExplainPerMinute
+	}
+	if hook == nil {
+		hook = logSlowQuery
+	}
+	explainer := sql.OpenDB(base)
+	explainer.SetMaxOpenConns(1)
+	explainer.SetMaxIdleConns(1)
+	return &explainConnector{
+		base:      base,
+		explainer: explainer,
+		threshold: threshold,
+		perMinute: perMinute,
+		hook:      hook,
+		explained: make(map[string]time.Time),
+	}
+}
+
+// explainConnector 创建记录慢查询的连接
+type explainConnector struct {
+	base      driver.Connector
+	explainer *sql.DB
+	threshold time.Duration
+	perMinute int
+	hook      func(ctx context.Context, q SlowQuery)
+	closeOnce sync.Once
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowCount int
+	explained   map[string]time.Time // 语句 -> 上次 EXPLAIN 的时间
+}
+
+// Connect 建立连接
+func (e *explainConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := e.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &observeConn{Conn: conn, obs: e.track}, nil
+}
+
+// Driver 实现 driver.Connector
+func (e *explainConnector) Driver() driver.Driver { return e.base.Driver() }
+
+// Close 关闭用于 EXPLAIN 的连接池，sql.DB.Close 时自动调用
+func (e *explainConnector) Close() error {
+	var err error
+	e.closeOnce.Do(func() {
+		err = e.explainer.Close()
+		if c, ok := e.base.(io.Closer); ok {
+			if cerr := c.Close(); err == nil {
+				err = cerr
+			}
+		}
+	})
+	return err
+}
+
+// track 实现 stmtObserver，语句成功返回后记录耗时
+func (e *explainConnector) track(ctx context.Context, query string, args []driver.NamedValue) (context.Context, func(error)) {
+	start := time.Now()
+	return ctx, func(err error) {
+		if err == nil {
+			e.observe(ctx, query, args, start)
+		}
+	}
+}
+
+// observe 记录语句耗时，超过阈值时在后台 EXPLAIN 并调用 hook
+func (e *explainConnector) observe(ctx context.Context, query string, args []driver.NamedValue, start time.Time) {
+	d := time.Since(start)
+	if d < e.threshold || ctx.Err() != nil {
+		return
+	}
+	q := SlowQuery{Query: query, Args: namedArgs(args), Duration: d}
+	explain := explainable(query) && e.allow(query)
+	go func() {
+		ctx := context.WithoutCancel(ctx)
+		if explain {
+			q.Plan, q.ExplainErr = e.explain(ctx, q.Query, q.Args)
+		}
+		e.hook(ctx, q)
+	}()
+}
+
+// allow 按每分钟次数与同一语句的去重窗口判断是否执行 EXPLAIN
+func (e *explainConnector) allow(query string) bool {
+	now := time.Now()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if last, ok := e.explained[query]; ok && now.Sub(last) < explainDedupWindow {
+		return false
+	}
+	if now.Sub(e.windowStart) >= time.Minute {
+		e.windowStart, e.windowCount = now, 0
+	}
+	if e.windowCount >= e.perMinute {
+		return false
+	}
+	e.windowCount++
+	if len(e.explained) >= 1000 {
+		for k, t := range e.explained {
+			if now.Sub(t) >= explainDedupWindow {
+				delete(e.explained, k)
+			}
+		}
+	}
+	e.explained[query] = now
+	return true
+}
+
+// explain 在独立连接上执行 EXPLAIN，结果按列名转为字符串
+func (e *explainConnector) explain(ctx context.Context, query string, args []any) ([]map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, explainTimeout)
+	defer cancel()
+	rows, err := e.explainer.QueryContext(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("mysqlx: explain: %w", err)
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("mysqlx: explain: %w", err)
+	}
+	var plan []map[string]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(cols))
+		dest := make([]any, len(cols))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("mysqlx: explain: %w", err)
+		}
+		row := make(map[string]string, len(cols))
+		for i, c := range cols {
+			if values[i].Valid {
+				row[c] = values[i].String
+			}
+		}
+		plan = append(plan, row)
+	}
+	return plan, rows.Err()
+}
+
+// logSlowQuery 默认的慢查询输出，不记录参数以免日志中出现敏感数据
+func logSlowQuery(ctx context.Context, q SlowQuery) {
+	fields := []zap.Field{zap.String("sql", q.Query), zap.Duration("duration", q.Duration)}
+	if q.Plan != nil {
+		fields = append(fields, zap.Any("plan", q.Plan))
+	}
+	if q.ExplainErr != nil {
+		fields = append(fields, zap.NamedError("explainError", q.ExplainErr))
+	}
+	logger.Warn(ctx, "mysqlx: slow query", fields...)
+}
+
+// explainable 判断语句是否支持 EXPLAIN（只读查询与 DML）
+func explainable(query string) bool {
+	word, _, _ := strings.Cut(strings.TrimLeft(query, " \t\r\n("), " ")
+	switch strings.ToUpper(strings.TrimSpace(word)) {
+	case "SELECT", "WITH", "TABLE", "INSERT", "REPLACE", "UPDATE", "DELETE":
+		return true
+	}
+	return false
+}
+
+// namedArgs 将驱动参数还原为 database/sql 的参数
+func namedArgs(args []driver.NamedValue) []any {
+	out := make([]any, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			out[i] = sql.Named(a.Name, a.Value)
+		} else {
+			out[i] = a.Value
+		}
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// newExplainDB 基于 sqlmock 创建开启慢查询诊断的连接池，阈值 30ms，慢查询通过返回的 channel 接收
+func newExplainDB(t *testing.T, dsn string, perMinute int) (*sql.DB, sqlmock.Sqlmock, <-chan SlowQuery) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.NewWithDSN(dsn, sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("sqlmock.NewWithDSN: %v", err)
+	}
+	slow := make(chan SlowQuery, 4)
+	connector := NewExplainConnector(dsnConnector{drv: mockDB.Driver(), dsn: dsn}, 30*time.Millisecond, perMinute,
+		func(_ context.Context, q SlowQuery) { slow <- q })
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		_ = db.Close()
+		_ = mockDB.Close()
+	})
+	return db, mock, slow
+}
+
+// waitSlow 等待下一条慢查询
+func waitSlow(t *testing.T, slow <-chan SlowQuery) SlowQuery {
+	t.Helper()
+	select {
+	case q := <-slow:
+		return q
+	case <-time.After(2 * time.Second):
+		t.Fatal("slow query hook not called")
+		return SlowQuery{}
+	}
+}
+
+// TestExplainConnector_Threshold 只有超过阈值的语句才执行 EXPLAIN，并以相同参数执行
+func TestExplainConnector_Threshold(t *testing.T) {
+	db, mock, slow := newExplainDB(t, "mysqlx_explain_threshold", 0)
+	ctx := context.Background()
+
+	mock.ExpectExec("UPDATE t SET n = 1 WHERE id = ?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE t SET n = 2 WHERE id = ?").WithArgs(2).WillDelayFor(50 * time.Millisecond).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("EXPLAIN UPDATE t SET n = 2 WHERE id = ?").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "table", "type", "key"}).AddRow("1", "t", "range", nil))
+
+	if _, err := db.ExecContext(ctx, "UPDATE t SET n = 1 WHERE id = ?", 1); err != nil {
+		t.Fatalf("fast ExecContext: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "UPDATE t SET n = 2 WHERE id = ?", 2); err != nil {
+		t.Fatalf("slow ExecContext: %v", err)
+	}
+
+	q := waitSlow(t, slow)
+	if q.Query != "UPDATE t SET n = 2 WHERE id = ?" || q.Duration < 30*time.Millisecond || len(q.Args) != 1 || q.ExplainErr != nil {
+		t.Fatalf("slow query = %+v", q)
+	}
+	if len(q.Plan) != 1 || q.Plan[0]["type"] != "range" {
+		t.Errorf("plan = %v", q.Plan)
+	}
+	if _, ok := q.Plan[0]["key"]; ok {
+		t.Error("NULL column should be omitted from the plan")
+	}
+	select {
+	case q := <-slow:
+		t.Errorf("fast query reported as slow: %+v", q)
+	default:
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestExplainConnector_Limits 同一语句只 EXPLAIN 一次，超出每分钟次数或不支持 EXPLAIN 的语句只报告不 EXPLAIN
+func TestExplainConnector_Limits(t *testing.T) {
+	db, mock, slow := newExplainDB(t, "mysqlx_explain_limits", 1)
+	ctx := context.Background()
+	delay := 50 * time.Millisecond
+
+	mock.ExpectQuery("SELECT * FROM t").WillDelayFor(delay).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("EXPLAIN SELECT * FROM t").WillReturnRows(sqlmock.NewRows([]string{"id", "type"}).AddRow("1", "ALL"))
+	if err := queryClose(ctx, db, "SELECT * FROM t"); err != nil {
+		t.Fatalf("QueryContext: %v", err)
+	}
+	if q := waitSlow(t, slow); q.Plan == nil {
+		t.Fatalf("first slow query not explained: %+v", q)
+	}
+
+	for _, query := range []string{
+		"SELECT * FROM t",  // 去重窗口内
+		"SELECT * FROM t2", // 超出每分钟次数
+		"SHOW PROCESSLIST", // 不支持 EXPLAIN
+	} {
+		mock.ExpectQuery(query).WillDelayFor(delay).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		if err := queryClose(ctx, db, query); err != nil {
+			t.Fatalf("QueryContext(%s): %v", query, err)
+		}
+		if q := waitSlow(t, slow); q.Query != query || q.Plan != nil || q.ExplainErr != nil {
+			t.Errorf("slow query %s = %+v, want reported without EXPLAIN", query, q)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// queryClose 执行查询并关闭结果集
+func queryClose(ctx context.Context, db *sql.DB, query string) error {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+func TestExplainable(t *testing.T) {
+	cases := map[string]bool{
+		"SELECT 1":                             true,
+		"  (SELECT 1) UNION (SELECT 2)":        true,
+		"with x as (select 1) select * from x": true,
+		"update t set a = 1":                   true,
+		"\nDELETE FROM t":                      true,
+		"INSERT INTO t VALUES (1)":             true,
+		"SHOW TABLES":                          false,
+		"SET SESSION time_zone = '+08:00'":     false,
+		"CALL proc()":                          false,
+		"":                                     false,
+	}
+	for q, want := range cases {
+		if got := explainable(q); got != want {
+			t.Errorf("explainable(%q) = %v, want %v", q, got, want)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package mysqlx
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fixtureBatchSize 单条 INSERT 语句包含的最大行数
+const fixtureBatchSize = 100
+
+// fixtureOptions 夹具加载选项
+type fixtureOptions struct {
+	dir   string
+	clean bool
+}
+
+// FixtureOption 夹具加载的函数式选项
+type FixtureOption func(*fixtureOptions)
+
+// WithFixtureDir 指定夹具文件所在目录（默认为 fsys 根目录）
+func WithFixtureDir(dir string) FixtureOption {
+	return func(o *fixtureOptions) { o.dir = dir }
+}
+
+// WithCleanTables 加载前是否清空夹具涉及的表，默认 true
+func WithCleanTables(clean bool) FixtureOption {
+	return func(o *fixtureOptions) { o.clean = clean }
+}
+
+// LoadFixtures 从 fsys（通常是 embed.FS）加载夹具数据到数据库
+// 目录下每个 <table>.yaml / <table>.yml / <table>.json 文件对应一张表，内容为行数组：
+//
+//	# users.yaml
+//	- id: 1
+//	  name: alice
+//	- id: 2
+//	  name: bob
+//
+// 表按外键依赖顺序写入（被引用的表在前），整个过程在同一事务中完成，并临时关闭 FOREIGN_KEY_CHECKS。
+// 实用场景: 集成测试、演示环境的数据可重复初始化
+func LoadFixtures(ctx context.Context, db *sql.DB, fsys fs.FS, opts ...FixtureOption) error {
+	o := &fixtureOptions{dir: ".", clean: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	fixtures, err := readFixtures(fsys, o.dir)
+	if err != nil {
+		return err
+	}
+	if len(fixtures) == 0 {
+		return nil
+	}
+
+	// SET 为会话级变量，需固定在同一个连接上执行
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	order, err := fixtureOrder(ctx, conn, fixtures)
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 0"); err != nil {
+		return err
+	}
+	defer func() { _, _ = conn.ExecContext(context.Background(), "SET FOREIGN_KEY_CHECKS = 1") }()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := applyFixtures(ctx, tx, order, fixtures, o.clean); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// applyFixtures 先按逆序清表，再按依赖顺序插入
+func applyFixtures(ctx context.Context, tx *sql.Tx, order []string, fixtures map[string][]map[string]interface{}, clean bool) error {
+	if clean {
+		for i := len(order) - 1; i >= 0; i-- {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM "+QuoteIdent(order[i])); err != nil {
+				return fmt.Errorf("clean %s: %w", order[i], err)
+			}
+		}
+	}
+	for _, table := range order {
+		rows := fixtures[table]
+		for start := 0; start < len(rows); start += fixtureBatchSize {
+			end := start + fixtureBatchSize
+			if end > len(rows) {
+				end = len(rows)
+			}
+			query, args, err := buildInsert(table, rows[start:end])
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf("insert %s: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// readFixtures 读取目录下的所有夹具文件，返回 表名 -> 行数据
+func readFixtures(fsys fs.FS, dir string) (map[string][]map[string]interface{}, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read fixture dir: %w", err)
+	}
+	fixtures := make(map[string][]map[string]interface{})
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		ext := path.Ext(e.Name())
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var rows []map[string]interface{}
+		if ext == ".json" {
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.UseNumber()
+			err = dec.Decode(&rows)
+		} else {
+			err = yaml.Unmarshal(data, &rows)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse fixture %s: %w", e.Name(), err)
+		}
+		table := strings.TrimSuffix(e.Name(), ext)
+		fixtures[table] = append(fixtures[table], rows...)
+	}
+	return fixtures, nil
+}
+
+// fixtureOrder 根据 information_schema 中的外键关系做拓扑排序，被引用的表在前；
+// 存在环时剩余的表按名称排序（外键检查已关闭，不影响写入）
+func fixtureOrder(ctx context.Context, conn *sql.Conn, fixtures map[string][]map[string]interface{}) ([]string, error) {
+	deps := make(map[string]map[string]bool)
+	rows, err := conn.QueryContext(ctx, `SELECT TABLE_NAME, REFERENCED_TABLE_NAME FROM information_schema.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = DATABASE() AND REFERENCED_TABLE_NAME IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table, ref string
+		if err := rows.Scan(&table, &ref); err != nil {
+			return nil, err
+		}
+		if _, ok := fixtures[table]; !ok || table == ref {
+			continue
+		}
+		if _, ok := fixtures[ref]; !ok {
+			continue
+		}
+		if deps[table] == nil {
+			deps[table] = make(map[string]bool)
+		}
+		deps[table][ref] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	remaining := make([]string, 0, len(fixtures))
+	for t := range fixtures {
+		remaining = append(remaining, t)
+	}
+	sort.Strings(remaining)
+
+	done := make(map[string]bool, len(remaining))
+	order := make([]string, 0, len(remaining))
+	for len(remaining) > 0 {
+		progressed := false
+		next := remaining[:0]
+		for _, t := range remaining {
+			ready := true
+			for ref := range deps[t] {
+				if !done[ref] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				order = append(order, t)
+				done[t] = true
+				progressed = true
+			} else {
+				next = append(next, t)
+			}
+		}
+		remaining = next
+		if !progressed {
+			order = append(order, remaining...)
+			break
+		}
+	}
+	return order, nil
+}
+
+// buildInsert 构建多行 INSERT 语句，列为所有行的并集；行中缺失的列写入 DEFAULT（使用列默认值，
+// 不会因 NOT NULL 列写入 NULL 而失败），显式写为 null 的列写入 NULL
+func buildInsert(table string, rows []map[string]interface{}) (string, []interface{}, error) {
+	colSet := make(map[string]bool)
+	for _, r := range rows {
+		for c := range r {
+			colSet[c] = true
+		}
+	}
+	cols := make([]string, 0, len(colSet))
+	for c := range colSet {
+		cols = append(cols, c)
+	}
+	sort.Strings(cols)
+
+	quoted := make([]string, len(cols))
+	for i, c := range cols {
+		quoted[i] = QuoteIdent(c)
+	}
+
+	args := make([]interface{}, 0, len(rows)*len(cols))
+	values := make([]string, 0, len(rows))
+	for _, r := range rows {
+		marks := make([]string, len(cols))
+		for i, c := range cols {
+			raw, ok := r[c]
+			if !ok {
+				marks[i] = "DEFAULT"
+				continue
+			}
+			v, err := fixtureValue(raw)
+			if err != nil {
+				return "", nil, fmt.Errorf("table %s column %s: %w", table, c, err)
+			}
+			marks[i] = "?"
+			args = append(args, v)
+		}
+		values = append(values, "("+strings.Join(marks, ", ")+")")
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", QuoteIdent(table), strings.Join(quoted, ", "), strings.Join(values, ", "))
+	return query, args, nil
+}
+
+// fixtureValue 转换夹具中的值，map/slice 序列化为 JSON（对应 JSON 列）
+func fixtureValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case json.Number:
+		return val.String(), nil
+	default:
+		return val, nil
+	}
+}
@@ -0,0 +1,59 @@
+package mysqlx
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/qingfeng-studio/go-utils/config"
+)
+
+// FromConfig 根据 config 包中共用的 MySQL 配置创建连接池，options 可在配置之上再做调整
+// 使用示例：
+//
+//	var cfg struct {
+//		MySQL config.MySQL `yaml:"mysql"`
+//	}
+//	_ = config.LoadYAML("app.yaml", &cfg)
+//	db, err := mysqlx.FromConfig(cfg.MySQL)
+func FromConfig(cfg config.MySQL, options ...Option) (*sql.DB, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("mysqlx: addr is required")
+	}
+	return New(configFrom(cfg), options...)
+}
+
+// configFrom 将共用配置转换为 Config，map 均复制一份
+func configFrom(cfg config.MySQL) Config {
+	return Config{
+		User:             cfg.User,
+		Password:         cfg.Password.Reveal(),
+		Net:              cfg.Net,
+		Addr:             cfg.Addr,
+		DBName:           cfg.DBName,
+		Params:           copyMap(cfg.Params),
+		MaxOpenConns:     cfg.MaxOpenConns,
+		MaxIdleConns:     cfg.MaxIdleConns,
+		ConnMaxLifetime:  cfg.ConnMaxLifetime,
+		ConnMaxIdleTime:  cfg.ConnMaxIdleTime,
+		PingTimeout:      cfg.PingTimeout,
+		WarmupConns:      cfg.WarmupConns,
+		KillOnCancel:     cfg.KillOnCancel,
+		KillTimeout:      cfg.KillTimeout,
+		SessionVars:      copyMap(cfg.SessionVars),
+		ConnAttrs:        copyMap(cfg.ConnAttrs),
+		SlowThreshold:    cfg.SlowThreshold,
+		ExplainPerMinute: cfg.ExplainPerMinute,
+	}
+}
+
+// copyMap 复制配置中的 map，避免选项修改共享的配置对象
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+package mysqlx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/qingfeng-studio/go-utils/config"
+)
+
+func TestFromConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.MySQL
+		want string
+	}{
+		{"no addr", config.MySQL{DBName: "app"}, "addr is required"},
+		{"invalid session variable name", config.MySQL{Addr: "127.0.0.1:1", SessionVars: map[string]string{"time zone": "+08:00"}}, "invalid session variable name"},
+		{"empty session variable value", config.MySQL{Addr: "127.0.0.1:1", SessionVars: map[string]string{"time_zone": ""}}, "empty value"},
+		{"session variable value with backslash", config.MySQL{Addr: "127.0.0.1:1", SessionVars: map[string]string{"sql_mode": `a\'`}}, "invalid value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := FromConfig(tt.cfg)
+			if err == nil {
+				_ = db.Close()
+				t.Fatal("FromConfig succeeded, want error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromConfig_DSN(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   config.MySQL
+		check func(t *testing.T, c *mysql.Config)
+	}{
+		{
+			name: "defaults",
+			cfg:  config.MySQL{Addr: "127.0.0.1:3306"},
+			check: func(t *testing.T, c *mysql.Config) {
+				if c.Net != "tcp" || !c.ParseTime || c.Loc.String() != "Local" || c.Params["charset"] != "utf8mb4" || c.User != "" {
+					t.Errorf("defaults: net=%s parseTime=%v loc=%v params=%v", c.Net, c.ParseTime, c.Loc, c.Params)
+				}
+			},
+		},
+		{
+			name: "configured values override defaults",
+			cfg: config.MySQL{
+				User: "app", Password: "p@ss:word", Net: "unix", Addr: "/tmp/mysql.sock", DBName: "orders",
+				Params: map[string]string{"charset": "utf8", "parseTime": "false"},
+			},
+			check: func(t *testing.T, c *mysql.Config) {
+				if c.User != "app" || c.Passwd != "p@ss:word" || c.Net != "unix" || c.Addr != "/tmp/mysql.sock" || c.DBName != "orders" {
+					t.Errorf("connection fields: %+v", c)
+				}
+				if c.ParseTime || c.Params["charset"] != "utf8" {
+					t.Errorf("params: parseTime=%v params=%v", c.ParseTime, c.Params)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsn := BuildDSN(configFrom(tt.cfg))
+			c, err := mysql.ParseDSN(dsn)
+			if err != nil {
+				t.Fatalf("ParseDSN(%q): %v", dsn, err)
+			}
+			tt.check(t, c)
+		})
+	}
+}
+
+// TestFromConfig_Options 连接池参数生效，选项不修改共享的配置对象
+func TestFromConfig_Options(t *testing.T) {
+	cfg := config.MySQL{
+		Addr: "127.0.0.1:1", MaxOpenConns: 5, ConnMaxLifetime: time.Minute,
+		Params:      map[string]string{"timeout": "1s"},
+		SessionVars: map[string]string{"time_zone": "+08:00"},
+	}
+	db, err := FromConfig(cfg, WithParam("readTimeout", "2s"), WithSQLMode("STRICT_ALL_TABLES"), func(c *Config) { c.MaxOpenConns = 7 })
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+	defer db.Close()
+	if got := db.Stats().MaxOpenConnections; got != 7 {
+		t.Errorf("MaxOpenConnections = %d, want 7", got)
+	}
+	if len(cfg.Params) != 1 || len(cfg.SessionVars) != 1 {
+		t.Errorf("options modified the shared config: params=%v session=%v", cfg.Params, cfg.SessionVars)
+	}
+
+	db, err = FromConfig(config.MySQL{Addr: "127.0.0.1:1"})
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+	defer db.Close()
+	if got := db.Stats().MaxOpenConnections; got != 0 {
+		t.Errorf("default MaxOpenConnections = %d, want 0 (unlimited)", got)
+	}
+}
@@ -0,0 +1,148 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"time"
+)
+
+// defaultKillTimeout 发送 KILL QUERY 的默认超时
+const defaultKillTimeout = 3 * time.Second
+
+// WithKillOnCancel 查询的 context 被取消（超时或客户端断开）时，向服务端发送 KILL QUERY 终止仍在执行的语句
+// 驱动在取消时只会关闭本地连接，服务端的慢查询会继续执行直到结束；开启后每个新连接会额外执行一次 SELECT CONNECTION_ID()
+// 监听范围为语句执行到返回结果集之前（慢查询的主要耗时阶段），读取结果集期间的取消仍由驱动处理；
+// timeout 为发送 KILL 的超时，<= 0 时使用 3 秒
+func WithKillOnCancel(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.KillOnCancel = true
+		c.KillTimeout = timeout
+	}
+}
+
+// NewKillOnCancelConnector 包装已有的 Connector，使其创建的连接在 context 取消时发送 KILL QUERY，
+// 用于自行通过 sql.OpenDB 创建连接池（例如读写分离的从库）的场景；
+// KILL 通过同一 Connector 的独立连接池发送，返回的 Connector 会在 sql.DB 关闭时一并关闭它
+func NewKillOnCancelConnector(base driver.Connector, timeout time.Duration) driver.Connector {
+	if timeout <= 0 {
+		timeout = defaultKillTimeout
+	}
+	killer := sql.OpenDB(base)
+	killer.SetMaxOpenConns(2)
+	killer.SetMaxIdleConns(1)
+	return &killConnector{base: base, killer: killer, timeout: timeout}
+}
+
+// killConnector 创建可在取消时 KILL QUERY 的连接
+type killConnector struct {
+	base    driver.Connector
+	killer  *sql.DB
+	timeout time.Duration
+}
+
+// Connect 建立连接并记录服务端的连接 ID
+func (k *killConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := k.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := connectionID(ctx, conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return &observeConn{Conn: conn, obs: k.observer(id)}, nil
+}
+
+// Driver 实现 driver.Connector
+func (k *killConnector) Driver() driver.Driver { return k.base.Driver() }
+
+// Close 关闭用于发送 KILL 的连接池，sql.DB.Close 时自动调用
+func (k *killConnector) Close() error {
+	err := k.killer.Close()
+	if c, ok := k.base.(io.Closer); ok {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// kill 终止指定连接上正在执行的语句
+func (k *killConnector) kill(id uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), k.timeout)
+	defer cancel()
+	_, _ = k.killer.ExecContext(ctx, fmt.Sprintf("KILL QUERY %d", id))
+}
+
+// connectionID 在原始连接上查询 CONNECTION_ID()
+func connectionID(ctx context.Context, conn driver.Conn) (uint64, error) {
+	qc, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return 0, fmt.Errorf("mysqlx: driver connection %T does not support QueryContext", conn)
+	}
+	rows, err := qc.QueryContext(ctx, "SELECT CONNECTION_ID()", nil)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	dest := make([]driver.Value, 1)
+	if err := rows.Next(dest); err != nil {
+		return 0, fmt.Errorf("mysqlx: read connection id: %w", err)
+	}
+	var id uint64
+	switch v := dest[0].(type) {
+	case int64:
+		id = uint64(v)
+	case uint64:
+		id = v
+	case []byte:
+		_, err = fmt.Sscan(string(v), &id)
+	default:
+		err = fmt.Errorf("mysqlx: unexpected connection id type %T", v)
+	}
+	return id, err
+}
+
+// observer 返回连接 id 的 stmtObserver，语句执行期间监听 context，取消时发送 KILL QUERY
+func (k *killConnector) observer(id uint64) stmtObserver {
+	return func(ctx context.Context, _ string, _ []driver.NamedValue) (context.Context, func(error)) {
+		return ctx, k.watch(ctx, id)
+	}
+}
+
+// watch 监听 ctx，返回的 stop 需在语句返回后以语句的错误调用：stop 关闭监听并等待监听协程确认退出，
+// 因此 KILL 总是在 stop 返回前完成，避免误伤连接归还后执行的下一条语句。
+// 驱动在取消时会立即返回，监听协程可能还未来得及发送 KILL，stop 中会补发；
+// 语句已成功返回（err 为 nil）时服务端不再执行该语句，即使 ctx 随后被取消也不发送 KILL
+func (k *killConnector) watch(ctx context.Context, id uint64) (stop func(err error)) {
+	if ctx.Done() == nil {
+		return func(error) {}
+	}
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	killed := false
+	go func() {
+		defer close(exited)
+		select {
+		case <-done:
+		case <-ctx.Done():
+			select {
+			case <-done: // 语句已经结束
+			default:
+				k.kill(id)
+				killed = true
+			}
+		}
+	}()
+	return func(err error) {
+		close(done)
+		<-exited
+		if !killed && err != nil && ctx.Err() != nil {
+			k.kill(id)
+		}
+	}
+}
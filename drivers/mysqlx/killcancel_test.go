@@ -0,0 +1,97 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// newKillConn 基于 sqlmock 创建带 KILL 能力的驱动连接，连接 ID 为 42；
+// 发送 KILL 的连接池与被监听的连接共用同一个 sqlmock 连接，按顺序匹配期望
+func newKillConn(t *testing.T, dsn string) (driver.Conn, sqlmock.Sqlmock) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.NewWithDSN(dsn, sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("sqlmock.NewWithDSN: %v", err)
+	}
+	connector := NewKillOnCancelConnector(dsnConnector{drv: mockDB.Driver(), dsn: dsn}, time.Second)
+	t.Cleanup(func() {
+		_ = connector.(*killConnector).Close()
+		_ = mockDB.Close()
+	})
+
+	mock.ExpectQuery("SELECT CONNECTION_ID()").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(42)))
+	conn, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, mock
+}
+
+// TestKillOnCancel_CancelDuringQuery 语句执行中取消，返回前已发送 KILL QUERY
+func TestKillOnCancel_CancelDuringQuery(t *testing.T) {
+	conn, mock := newKillConn(t, "mysqlx_kill_during")
+	mock.ExpectQuery("SELECT SLEEP(10)").WillDelayFor(10 * time.Second).WillReturnRows(sqlmock.NewRows([]string{"r"}))
+	mock.ExpectExec("KILL QUERY 42").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := conn.(driver.QueryerContext).QueryContext(ctx, "SELECT SLEEP(10)", nil)
+	if !errors.Is(err, sqlmock.ErrCancelled) {
+		t.Fatalf("QueryContext err = %v, want canceled", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("KILL not sent before the query returned: %v", err)
+	}
+}
+
+// TestKillOnCancel_CancelAfterQuery 语句完成后才取消，不发送 KILL QUERY
+func TestKillOnCancel_CancelAfterQuery(t *testing.T) {
+	conn, mock := newKillConn(t, "mysqlx_kill_after")
+	mock.ExpectExec("UPDATE t SET n = n + 1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("KILL QUERY 42").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := conn.(driver.ExecerContext).ExecContext(ctx, "UPDATE t SET n = n + 1", nil); err != nil {
+		t.Fatalf("ExecContext: %v", err)
+	}
+	cancel()
+
+	// 监听已在返回前停止，取消不会再触发 KILL
+	time.Sleep(20 * time.Millisecond)
+	if err := mock.ExpectationsWereMet(); err == nil {
+		t.Error("KILL sent for a finished statement")
+	}
+}
+
+// TestKillOnCancel_StopAfterCancel 驱动返回时监听协程尚未观察到取消：
+// 语句已成功完成时 stop 不补发 KILL，语句因取消而失败时补发
+func TestKillOnCancel_StopAfterCancel(t *testing.T) {
+	conn, mock := newKillConn(t, "mysqlx_kill_stop")
+	obs := conn.(*observeConn).obs
+	mock.ExpectExec("KILL QUERY 42").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, stop := obs(canceledOnce{ctx}, "UPDATE t SET n = n + 1", nil)
+	stop(nil)
+	if err := mock.ExpectationsWereMet(); err == nil {
+		t.Fatal("KILL sent for a finished statement")
+	}
+
+	_, stop = obs(canceledOnce{ctx}, "UPDATE t SET n = n + 1", nil)
+	stop(context.Canceled)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("KILL not sent after a canceled statement: %v", err)
+	}
+}
+
+// canceledOnce 已取消但 Done 永不关闭的 context，模拟驱动返回时监听协程尚未观察到取消
+type canceledOnce struct{ context.Context }
+
+func (canceledOnce) Done() <-chan struct{} { return make(chan struct{}) }
@@ -0,0 +1,67 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/utils/metrics"
+)
+
+// metricsSubsystem mysqlx 指标的前缀
+const metricsSubsystem = "mysql"
+
+// WithMetrics 将语句执行次数、耗时与连接池状态记录到 r（nil 时为 metrics.Default）：
+//   - mysql_client_requests_total{client,operation,result}
+//   - mysql_client_request_duration_seconds{client,operation}
+//   - mysql_pool_*{pool}
+//
+// client 与 pool 标签为 name（为空时取 DBName），operation 为语句类型（SELECT、INSERT 等）
+func WithMetrics(r *metrics.Registry, name string) Option {
+	return func(c *Config) {
+		if r == nil {
+			r = metrics.Default
+		}
+		c.Metrics = r
+		c.MetricsName = name
+	}
+}
+
+// NewMetricsConnector 包装 Connector，记录连接上每条语句的执行次数与耗时，适用于自行 sql.OpenDB 的场景
+func NewMetricsConnector(base driver.Connector, m *metrics.ClientMetrics, name string) driver.Connector {
+	return newObserveConnector(base, func(ctx context.Context, query string, _ []driver.NamedValue) (context.Context, func(error)) {
+		start := time.Now()
+		return ctx, func(err error) {
+			if err == driver.ErrSkip {
+				return
+			}
+			m.Observe(name, statementType(query), metrics.Result(err), time.Since(start))
+		}
+	})
+}
+
+// metricsName 指标中的 client/pool 名称
+func (c Config) metricsName() string {
+	if c.MetricsName != "" {
+		return c.MetricsName
+	}
+	if c.DBName != "" {
+		return c.DBName
+	}
+	return "default"
+}
+
+// registerPoolStats 注册 db 的连接池状态
+func registerPoolStats(r *metrics.Registry, name string, db *sql.DB) error {
+	return metrics.RegisterPoolStats(r, metricsSubsystem, name, func() metrics.PoolStats {
+		s := db.Stats()
+		return metrics.PoolStats{
+			Open:         s.OpenConnections,
+			InUse:        s.InUse,
+			Idle:         s.Idle,
+			WaitCount:    s.WaitCount,
+			WaitDuration: s.WaitDuration,
+		}
+	})
+}
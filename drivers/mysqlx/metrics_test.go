@@ -0,0 +1,85 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/qingfeng-studio/go-utils/utils/metrics"
+)
+
+// dsnConnector 以 DSN 调用 driver.Open 的 Connector，用于包装 sqlmock 驱动
+type dsnConnector struct {
+	drv driver.Driver
+	dsn string
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.drv.Open(c.dsn) }
+func (c dsnConnector) Driver() driver.Driver                        { return c.drv }
+
+func TestMetricsConnector(t *testing.T) {
+	mockDB, mock, err := sqlmock.NewWithDSN("mysqlx_metrics_test", sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("sqlmock.NewWithDSN: %v", err)
+	}
+	defer mockDB.Close()
+
+	r := metrics.NewRegistry()
+	m := metrics.Must(metrics.NewClientMetrics(r, metricsSubsystem, nil))
+	db := sql.OpenDB(NewMetricsConnector(dsnConnector{drv: mockDB.Driver(), dsn: "mysqlx_metrics_test"}, m, "orders"))
+	if err := registerPoolStats(r, "orders", db); err != nil {
+		t.Fatalf("registerPoolStats: %v", err)
+	}
+
+	mock.ExpectQuery("SELECT id FROM t WHERE id = ?").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec("UPDATE t SET v = ?").WithArgs(2).WillReturnError(errors.New("boom"))
+
+	var id int
+	if err := db.QueryRow("SELECT id FROM t WHERE id = ?", 1).Scan(&id); err != nil {
+		t.Fatalf("QueryRow: %v", err)
+	}
+	if _, err := db.Exec("UPDATE t SET v = ?", 2); err == nil {
+		t.Fatal("Exec: expected error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	out := string(body)
+	for _, want := range []string{
+		`mysql_client_requests_total{client="orders",operation="SELECT",result="ok"} 1`,
+		`mysql_client_requests_total{client="orders",operation="UPDATE",result="error"} 1`,
+		`mysql_client_request_duration_seconds_count{client="orders",operation="SELECT"} 1`,
+		`mysql_pool_open_connections{pool="orders"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestStatementType(t *testing.T) {
+	for query, want := range map[string]string{
+		"select 1":                   "SELECT",
+		"\n  (SELECT a FROM t)":      "SELECT",
+		"INSERT INTO t VALUES (?)":   "INSERT",
+		"delete from t":              "DELETE",
+		"ALTER TABLE t ADD COLUMN x": "OTHER",
+		"":                           "OTHER",
+	} {
+		if got := statementType(query); got != want {
+			t.Errorf("statementType(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
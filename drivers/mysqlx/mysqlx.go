@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/go-sql-driver/mysql"
+
+	"github.com/qingfeng-studio/go-utils/utils/metrics"
 )
 
 // Config 提供最常用的 MySQL 连接配置项。作为第三方包，配置由使用方传入。
@@ -28,6 +30,29 @@ type Config struct {
 
 	// 启动探活
 	PingTimeout time.Duration // 0 表示不 Ping
+
+	// 连接预热
+	WarmupConns int // 启动时预先建立的连接数，0 表示不预热；超时取 PingTimeout，未设置时为 10 秒
+
+	// 取消时终止服务端查询
+	KillOnCancel bool          // context 取消时发送 KILL QUERY，见 WithKillOnCancel
+	KillTimeout  time.Duration // 发送 KILL 的超时，0 表示 3 秒
+
+	// 会话预设
+	SessionVars map[string]string // 每个新连接执行 SET SESSION 设置的变量，见 WithSessionVar
+	ConnAttrs   map[string]string // 连接属性，见 WithConnectionAttribute
+
+	// 慢查询诊断
+	SlowThreshold    time.Duration                          // 超过该耗时的语句自动 EXPLAIN，0 表示关闭，见 WithExplainSlow
+	ExplainPerMinute int                                    // 每分钟最多执行 EXPLAIN 的次数，0 表示 6 次
+	SlowQueryHook    func(ctx context.Context, q SlowQuery) // 慢查询处理函数，为空时使用 logger.Warn 输出
+
+	// 指标
+	Metrics     *metrics.Registry // 非空时记录语句与连接池指标，见 WithMetrics
+	MetricsName string            // 指标中的 client/pool 标签，为空时取 DBName
+
+	// 链路追踪
+	Tracing bool // 为每条语句创建 span，见 WithTracing
 }
 
 // Option 函数式选项，用于在基础 Config 上叠加修改
@@ -48,6 +73,11 @@ func WithPingTimeout(d time.Duration) Option {
 	return func(c *Config) { c.PingTimeout = d }
 }
 
+// WithWarmup 设置启动时预热的连接数
+func WithWarmup(n int) Option {
+	return func(c *Config) { c.WarmupConns = n }
+}
+
 // BuildDSN 根据配置构建 DSN 字符串（使用 go-sql-driver/mysql 的 Config 保证转义正确）
 func BuildDSN(cfg Config) string {
 	mcfg := mysql.NewConfig()
@@ -74,9 +104,16 @@ func BuildDSN(cfg Config) string {
 	if _, ok := cfg.Params["charset"]; !ok {
 		cfg.Params["charset"] = "utf8mb4"
 	}
+	if mcfg.Params == nil {
+		mcfg.Params = make(map[string]string, len(cfg.Params)) // 驱动 v1.8 起 NewConfig 不再初始化 Params
+	}
 	for k, v := range cfg.Params {
 		mcfg.Params[k] = v
 	}
+	if len(cfg.ConnAttrs) > 0 {
+		// FormatDSN 不输出 ConnectionAttributes 字段，以参数形式写入，ParseDSN 时会还原
+		mcfg.Params["connectionAttributes"] = formatConnAttrs(cfg.ConnAttrs)
+	}
 
 	return mcfg.FormatDSN()
 }
@@ -90,9 +127,43 @@ func New(base Config, options ...Option) (*sql.DB, error) {
 	}
 
 	dsn := BuildDSN(base)
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		return nil, err
+	var db *sql.DB
+	if base.KillOnCancel || len(base.SessionVars) > 0 || base.SlowThreshold > 0 || base.Metrics != nil || base.Tracing {
+		mcfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, err
+		}
+		connector, err := mysql.NewConnector(mcfg)
+		if err != nil {
+			return nil, err
+		}
+		if len(base.SessionVars) > 0 {
+			if connector, err = NewSessionConnector(connector, base.SessionVars); err != nil {
+				return nil, err
+			}
+		}
+		if base.SlowThreshold > 0 {
+			connector = NewExplainConnector(connector, base.SlowThreshold, base.ExplainPerMinute, base.SlowQueryHook)
+		}
+		if base.KillOnCancel {
+			connector = NewKillOnCancelConnector(connector, base.KillTimeout)
+		}
+		if base.Tracing {
+			connector = NewTracingConnector(connector, base.DBName)
+		}
+		if base.Metrics != nil {
+			m, err := metrics.NewClientMetrics(base.Metrics, metricsSubsystem, nil)
+			if err != nil {
+				return nil, err
+			}
+			connector = NewMetricsConnector(connector, m, base.metricsName())
+		}
+		db = sql.OpenDB(connector)
+	} else {
+		var err error
+		if db, err = sql.Open("mysql", dsn); err != nil {
+			return nil, err
+		}
 	}
 
 	// 连接池设置
@@ -109,6 +180,13 @@ func New(base Config, options ...Option) (*sql.DB, error) {
 		db.SetConnMaxIdleTime(base.ConnMaxIdleTime)
 	}
 
+	if base.Metrics != nil {
+		if err := registerPoolStats(base.Metrics, base.metricsName(), db); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+
 	// 初始化探活
 	if base.PingTimeout > 0 {
 		ctx, cancel := context.WithTimeout(context.Background(), base.PingTimeout)
@@ -119,5 +197,16 @@ func New(base Config, options ...Option) (*sql.DB, error) {
 		}
 	}
 
+	// 连接预热，失败不影响启动（后续请求会按需建连）
+	if base.WarmupConns > 0 {
+		timeout := base.PingTimeout
+		if timeout <= 0 {
+			timeout = defaultWarmupTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		_, _ = Warmup(ctx, db, base.WarmupConns)
+	}
+
 	return db, nil
 }
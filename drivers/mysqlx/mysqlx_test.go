@@ -0,0 +1,65 @@
+package mysqlx
+
+import (
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// TestBuildDSN 驱动 v1.8 起 NewConfig 不再初始化 Params，BuildDSN 不能向 nil map 写入
+func TestBuildDSN(t *testing.T) {
+	dsn := BuildDSN(Config{User: "root", Password: "p@ss:word", Addr: "127.0.0.1:3306", DBName: "app", Params: map[string]string{"timeout": "5s"}})
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("ParseDSN(%q): %v", dsn, err)
+	}
+	if cfg.User != "root" || cfg.Passwd != "p@ss:word" || cfg.Addr != "127.0.0.1:3306" || cfg.DBName != "app" || cfg.Net != "tcp" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if !cfg.ParseTime || cfg.Timeout.String() != "5s" {
+		t.Errorf("params not applied: parseTime=%v timeout=%v", cfg.ParseTime, cfg.Timeout)
+	}
+	if cfg.Params["charset"] != "utf8mb4" {
+		t.Errorf("default charset missing: %v", cfg.Params)
+	}
+
+	if _, err := mysql.ParseDSN(BuildDSN(Config{Addr: "127.0.0.1:3306"})); err != nil {
+		t.Errorf("nil Params: %v", err)
+	}
+}
+
+// TestNew 未配置 PingTimeout 时 New 不建立连接，只验证不会 panic 且连接池参数生效
+func TestNew(t *testing.T) {
+	db, err := New(Config{Addr: "127.0.0.1:1", DBName: "app"}, WithParam("timeout", "1s"), func(c *Config) { c.MaxOpenConns = 3 })
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+	if got := db.Stats().MaxOpenConnections; got != 3 {
+		t.Errorf("MaxOpenConnections = %d, want 3", got)
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	cases := []struct {
+		parts []string
+		want  string
+	}{
+		{[]string{"orders"}, "`orders`"},
+		{[]string{"shop.orders"}, "`shop`.`orders`"},
+		{[]string{"`shop`.`orders`"}, "`shop`.`orders`"},
+		{[]string{"tenant.v2", "orders"}, "`tenant.v2`.`orders`"},
+		{[]string{"a`b", "c"}, "`a``b`.`c`"},
+		{[]string{"`tenant.v2`.orders"}, "`tenant.v2`.`orders`"},
+		{[]string{"shop.`my.orders`"}, "`shop`.`my.orders`"},
+		{[]string{"`a``b`.c"}, "`a``b`.`c`"},
+		{[]string{"`a``b`", "`c`"}, "`a``b`.`c`"},
+		{[]string{"a`b"}, "`a``b`"},
+		{[]string{"shop", "orders", "id"}, "`shop`.`orders`.`id`"},
+	}
+	for _, tc := range cases {
+		if got := QuoteIdent(tc.parts...); got != tc.want {
+			t.Errorf("QuoteIdent(%q) = %s, want %s", tc.parts, got, tc.want)
+		}
+	}
+}
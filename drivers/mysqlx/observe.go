@@ -0,0 +1,187 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// stmtObserver 在语句执行前调用，返回的 context 用于执行语句，done 在语句返回（结果集读取之前）后以错误调用；
+// err 为 driver.ErrSkip 表示驱动要求改走预处理语句，该语句随后会再次经过 stmtObserver，观察方应忽略此次调用。
+// 指标、链路追踪、取消时 KILL QUERY 与慢查询 EXPLAIN 都基于它包装连接
+type stmtObserver func(ctx context.Context, query string, args []driver.NamedValue) (context.Context, func(err error))
+
+// newObserveConnector 包装 Connector，连接上执行的语句（含预处理语句）都会经过 obs
+func newObserveConnector(base driver.Connector, obs stmtObserver) driver.Connector {
+	return &observeConnector{base: base, obs: obs}
+}
+
+// observeConnector 创建经过 stmtObserver 的连接
+type observeConnector struct {
+	base driver.Connector
+	obs  stmtObserver
+}
+
+// Connect 建立连接
+func (o *observeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := o.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &observeConn{Conn: conn, obs: o.obs}, nil
+}
+
+// Driver 实现 driver.Connector
+func (o *observeConnector) Driver() driver.Driver { return o.base.Driver() }
+
+// Close 关闭底层 Connector，sql.DB.Close 时自动调用
+func (o *observeConnector) Close() error {
+	if c, ok := o.base.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// observeConn 执行语句时调用 stmtObserver 的连接
+type observeConn struct {
+	driver.Conn
+	obs stmtObserver
+}
+
+// QueryContext 实现 driver.QueryerContext
+func (c *observeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, done := c.obs(ctx, query, args)
+	rows, err := qc.QueryContext(ctx, query, args)
+	done(err)
+	return rows, err
+}
+
+// ExecContext 实现 driver.ExecerContext
+func (c *observeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, done := c.obs(ctx, query, args)
+	res, err := ec.ExecContext(ctx, query, args)
+	done(err)
+	return res, err
+}
+
+// PrepareContext 实现 driver.ConnPrepareContext
+func (c *observeConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if pc, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = pc.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &observeStmt{Stmt: stmt, query: query, obs: c.obs}, nil
+}
+
+// Prepare 实现 driver.Conn
+func (c *observeConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// BeginTx 实现 driver.ConnBeginTx
+func (c *observeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if bc, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return bc.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() // 驱动不支持 BeginTx 时的回退
+}
+
+// Ping 实现 driver.Pinger
+func (c *observeConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession 实现 driver.SessionResetter
+func (c *observeConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid 实现 driver.Validator
+func (c *observeConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+// CheckNamedValue 实现 driver.NamedValueChecker，交由底层驱动转换参数
+func (c *observeConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// observeStmt 预处理语句，执行时同样调用 stmtObserver
+type observeStmt struct {
+	driver.Stmt
+	query string
+	obs   stmtObserver
+}
+
+// ExecContext 实现 driver.StmtExecContext
+func (s *observeStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	sc, ok := s.Stmt.(driver.StmtExecContext)
+	if !ok {
+		return nil, fmt.Errorf("mysqlx: driver statement %T does not support ExecContext", s.Stmt)
+	}
+	ctx, done := s.obs(ctx, s.query, args)
+	res, err := sc.ExecContext(ctx, args)
+	done(err)
+	return res, err
+}
+
+// QueryContext 实现 driver.StmtQueryContext
+func (s *observeStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	sc, ok := s.Stmt.(driver.StmtQueryContext)
+	if !ok {
+		return nil, fmt.Errorf("mysqlx: driver statement %T does not support QueryContext", s.Stmt)
+	}
+	ctx, done := s.obs(ctx, s.query, args)
+	rows, err := sc.QueryContext(ctx, args)
+	done(err)
+	return rows, err
+}
+
+// CheckNamedValue 实现 driver.NamedValueChecker
+func (s *observeStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// statementType 语句类型（首个关键字），用作指标的 operation 标签与 span 名称，非常见语句归为 OTHER 以控制基数
+func statementType(query string) string {
+	word, _, _ := strings.Cut(strings.TrimLeft(query, " \t\r\n("), " ")
+	word = strings.ToUpper(strings.TrimSpace(word))
+	switch word {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "REPLACE", "WITH", "SET", "CALL", "SHOW", "BEGIN", "COMMIT", "ROLLBACK":
+		return word
+	}
+	return "OTHER"
+}
@@ -0,0 +1,242 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrReplicationStopped 从库复制线程未运行（Seconds_Behind_Master 为 NULL）或实例不是从库
+var ErrReplicationStopped = errors.New("mysqlx: replication is not running")
+
+// LagChecker 查询从库复制延迟
+type LagChecker func(ctx context.Context, db *sql.DB) (time.Duration, error)
+
+// SlaveStatusLag 通过 SHOW REPLICA STATUS（旧版本回退到 SHOW SLAVE STATUS）读取复制延迟，精度为秒
+func SlaveStatusLag() LagChecker {
+	return func(ctx context.Context, db *sql.DB) (time.Duration, error) {
+		lag, err := statusLag(ctx, db, "SHOW REPLICA STATUS", "Seconds_Behind_Source")
+		if err != nil && !errors.Is(err, ErrReplicationStopped) {
+			lag, err = statusLag(ctx, db, "SHOW SLAVE STATUS", "Seconds_Behind_Master")
+		}
+		return lag, err
+	}
+}
+
+// HeartbeatLag 通过心跳表（pt-heartbeat 风格，主库定期写入当前时间）计算复制延迟，精度为微秒
+func HeartbeatLag(table, column string) LagChecker {
+	query := fmt.Sprintf("SELECT TIMESTAMPDIFF(MICROSECOND, MAX(%s), NOW(6)) FROM %s", QuoteIdent(column), QuoteIdent(table))
+	return func(ctx context.Context, db *sql.DB) (time.Duration, error) {
+		var us sql.NullInt64
+		if err := db.QueryRowContext(ctx, query).Scan(&us); err != nil {
+			return 0, err
+		}
+		if !us.Valid {
+			return 0, ErrReplicationStopped
+		}
+		return time.Duration(us.Int64) * time.Microsecond, nil
+	}
+}
+
+// statusLag 执行复制状态语句并读取延迟列
+func statusLag(ctx context.Context, db *sql.DB, query, column string) (time.Duration, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+		return 0, ErrReplicationStopped
+	}
+	values := make([]sql.RawBytes, len(cols))
+	dest := make([]interface{}, len(cols))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return 0, err
+	}
+	for i, c := range cols {
+		if c != column {
+			continue
+		}
+		if values[i] == nil {
+			return 0, ErrReplicationStopped
+		}
+		secs, err := strconv.ParseInt(string(values[i]), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(secs) * time.Second, nil
+	}
+	return 0, fmt.Errorf("mysqlx: column %s not found", column)
+}
+
+// forcePrimaryKey 强制走主库的 context 标记
+type forcePrimaryKey struct{}
+
+// ForcePrimary 标记该 context 上的读请求必须走主库，用于“写后立即读”的一致性场景
+func ForcePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forcePrimaryKey{}, true)
+}
+
+// IsForcePrimary 判断 context 是否带有强制主库标记
+func IsForcePrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(forcePrimaryKey{}).(bool)
+	return v
+}
+
+// ReplicaStatus 从库的最近一次检查结果
+type ReplicaStatus struct {
+	Index     int           // 从库序号（与 NewRouter 传入顺序一致）
+	Healthy   bool          // 是否参与读路由
+	Lag       time.Duration // 最近一次检测到的延迟
+	Err       error         // 最近一次检测错误
+	CheckedAt time.Time     // 检查时间
+}
+
+// replica 从库及其健康状态
+type replica struct {
+	db     *sql.DB
+	mu     sync.RWMutex
+	status ReplicaStatus
+}
+
+// RouterOption 读写分离路由的函数式选项
+type RouterOption func(*Router)
+
+// WithMaxLag 设置可接受的最大复制延迟，超过则从读路由中摘除，默认 1s
+func WithMaxLag(d time.Duration) RouterOption { return func(r *Router) { r.maxLag = d } }
+
+// WithLagChecker 设置延迟检测方式，默认 SlaveStatusLag()
+func WithLagChecker(fn LagChecker) RouterOption { return func(r *Router) { r.checker = fn } }
+
+// WithCheckInterval 设置延迟检测间隔，默认 1s，<= 0 时使用默认值
+func WithCheckInterval(d time.Duration) RouterOption { return func(r *Router) { r.interval = d } }
+
+// defaultCheckInterval 默认的延迟检测间隔
+const defaultCheckInterval = time.Second
+
+// Router 读写分离路由：写请求走主库，读请求在健康的从库间轮询，
+// 延迟超过阈值的从库会被自动摘除，恢复后重新加入；没有可用从库时回退到主库
+type Router struct {
+	primary  *sql.DB
+	replicas []*replica
+	maxLag   time.Duration
+	checker  LagChecker
+	interval time.Duration
+
+	next uint32
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewRouter 创建读写分离路由，并启动后台延迟检测；使用完毕需调用 Close 停止检测
+// 新加入的从库在首次检测完成前视为健康
+func NewRouter(primary *sql.DB, replicas []*sql.DB, opts ...RouterOption) *Router {
+	r := &Router{
+		primary:  primary,
+		maxLag:   time.Second,
+		checker:  SlaveStatusLag(),
+		interval: defaultCheckInterval,
+		stop:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.interval <= 0 {
+		r.interval = defaultCheckInterval
+	}
+	for i, db := range replicas {
+		r.replicas = append(r.replicas, &replica{db: db, status: ReplicaStatus{Index: i, Healthy: true}})
+	}
+	if len(r.replicas) > 0 {
+		go r.loop()
+	}
+	return r
+}
+
+// Primary 返回主库，用于写请求
+func (r *Router) Primary() *sql.DB { return r.primary }
+
+// Reader 返回用于读请求的连接池：ForcePrimary 标记或无健康从库时返回主库
+func (r *Router) Reader(ctx context.Context) *sql.DB {
+	if len(r.replicas) == 0 || IsForcePrimary(ctx) {
+		return r.primary
+	}
+	// 按 uint32 取模，计数器溢出后仍为合法下标（32 位平台上转为 int 会变成负数）
+	n := uint32(len(r.replicas))
+	start := atomic.AddUint32(&r.next, 1)
+	for i := uint32(0); i < n; i++ {
+		rep := r.replicas[(start+i)%n]
+		rep.mu.RLock()
+		healthy := rep.status.Healthy
+		rep.mu.RUnlock()
+		if healthy {
+			return rep.db
+		}
+	}
+	return r.primary
+}
+
+// CheckNow 立即检测所有从库的延迟
+func (r *Router) CheckNow(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, rep := range r.replicas {
+		wg.Add(1)
+		go func(rep *replica) {
+			defer wg.Done()
+			lag, err := r.checker(ctx, rep.db)
+			rep.mu.Lock()
+			rep.status.Lag, rep.status.Err, rep.status.CheckedAt = lag, err, time.Now()
+			rep.status.Healthy = err == nil && lag <= r.maxLag
+			rep.mu.Unlock()
+		}(rep)
+	}
+	wg.Wait()
+}
+
+// Status 返回所有从库的最近检查结果
+func (r *Router) Status() []ReplicaStatus {
+	out := make([]ReplicaStatus, 0, len(r.replicas))
+	for _, rep := range r.replicas {
+		rep.mu.RLock()
+		out = append(out, rep.status)
+		rep.mu.RUnlock()
+	}
+	return out
+}
+
+// Close 停止后台延迟检测（不会关闭连接池）
+func (r *Router) Close() {
+	r.once.Do(func() { close(r.stop) })
+}
+
+// loop 定期检测从库延迟
+func (r *Router) loop() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), r.interval)
+			r.CheckNow(ctx)
+			cancel()
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// expectLag 期望一次 SHOW REPLICA STATUS，lag 为 nil 表示复制未运行
+func expectLag(mock sqlmock.Sqlmock, lag interface{}) {
+	mock.ExpectQuery("SHOW REPLICA STATUS").
+		WillReturnRows(sqlmock.NewRows([]string{"Replica_IO_Running", "Seconds_Behind_Source"}).AddRow("Yes", lag))
+}
+
+func TestRouter_Reader(t *testing.T) {
+	primary, _ := newMockDB(t)
+	r1, mock1 := newMockDB(t)
+	r2, mock2 := newMockDB(t)
+	// 检测间隔为 0 时使用默认值，不会让 time.NewTicker panic
+	r := NewRouter(primary, []*sql.DB{r1, r2}, WithCheckInterval(0), WithMaxLag(2*time.Second))
+	defer r.Close()
+	if r.interval != defaultCheckInterval {
+		t.Errorf("interval = %v, want default", r.interval)
+	}
+	ctx := context.Background()
+
+	// 轮询健康从库
+	first, second := r.Reader(ctx), r.Reader(ctx)
+	if first == second || (first != r1 && first != r2) || (second != r1 && second != r2) {
+		t.Fatalf("expected round-robin over replicas, got %p %p", first, second)
+	}
+	if r.Reader(ForcePrimary(ctx)) != primary {
+		t.Error("ForcePrimary should route to primary")
+	}
+
+	// 延迟超过阈值的从库被摘除
+	expectLag(mock1, "5")
+	expectLag(mock2, "0")
+	r.CheckNow(ctx)
+	for i := 0; i < 4; i++ {
+		if db := r.Reader(ctx); db != r2 {
+			t.Fatalf("read %d routed to %p, want healthy replica", i, db)
+		}
+	}
+	if st := r.Status(); st[0].Healthy || st[0].Lag != 5*time.Second || !st[1].Healthy {
+		t.Errorf("unexpected status %+v", st)
+	}
+
+	// 没有健康从库时回退到主库
+	expectLag(mock1, "5")
+	expectLag(mock2, nil)
+	r.CheckNow(ctx)
+	if r.Reader(ctx) != primary {
+		t.Error("expected fallback to primary")
+	}
+	if st := r.Status(); !errors.Is(st[1].Err, ErrReplicationStopped) {
+		t.Errorf("replica 2 err = %v, want ErrReplicationStopped", st[1].Err)
+	}
+	for _, m := range []sqlmock.Sqlmock{mock1, mock2} {
+		if err := m.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// TestRouter_ReaderCounterWrap 轮询计数器越过 2^31 与溢出回绕时下标仍合法
+func TestRouter_ReaderCounterWrap(t *testing.T) {
+	primary, _ := newMockDB(t)
+	r1, _ := newMockDB(t)
+	r2, _ := newMockDB(t)
+	r3, _ := newMockDB(t)
+	r := NewRouter(primary, []*sql.DB{r1, r2, r3})
+	defer r.Close()
+
+	for _, start := range []uint32{math.MaxInt32, math.MaxUint32 - 1} {
+		r.next = start
+		for i := 0; i < 4; i++ {
+			if db := r.Reader(context.Background()); db == primary {
+				t.Fatalf("counter %d: routed to primary", start)
+			}
+		}
+	}
+}
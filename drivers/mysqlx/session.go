@@ -0,0 +1,160 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithSessionVar 设置每个新连接建立后执行 SET SESSION 的会话变量（可多次调用累加），
+// 不依赖服务端默认值；数值按数字设置，其它值按字符串设置，如 WithSessionVar("sql_mode", "STRICT_ALL_TABLES")
+func WithSessionVar(name, value string) Option {
+	return func(c *Config) {
+		if c.SessionVars == nil {
+			c.SessionVars = make(map[string]string)
+		}
+		c.SessionVars[name] = value
+	}
+}
+
+// WithSQLMode 设置会话的 sql_mode，如 "STRICT_TRANS_TABLES,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO"
+func WithSQLMode(mode string) Option {
+	return WithSessionVar("sql_mode", mode)
+}
+
+// WithTimeZone 设置会话的 time_zone，如 "+08:00"（使用命名时区需服务端已加载时区表）
+func WithTimeZone(tz string) Option {
+	return WithSessionVar("time_zone", tz)
+}
+
+// WithMaxExecutionTime 设置会话的 max_execution_time，超时的只读 SELECT 由服务端中止（MySQL 5.7.8+）
+func WithMaxExecutionTime(d time.Duration) Option {
+	return WithSessionVar("max_execution_time", strconv.FormatInt(d.Milliseconds(), 10))
+}
+
+// WithIsolationLevel 设置会话默认的事务隔离级别（transaction_isolation，MySQL 5.7.20+），
+// 只支持 READ UNCOMMITTED、READ COMMITTED、REPEATABLE READ、SERIALIZABLE，其它级别在 New 时报错
+func WithIsolationLevel(level sql.IsolationLevel) Option {
+	return WithSessionVar("transaction_isolation", isolationNames[level])
+}
+
+// WithConnectionAttribute 设置连接属性（可多次调用累加），可在 performance_schema.session_connect_attrs 中查看，
+// 便于 DBA 定位连接来自哪个服务
+func WithConnectionAttribute(key, value string) Option {
+	return func(c *Config) {
+		if c.ConnAttrs == nil {
+			c.ConnAttrs = make(map[string]string)
+		}
+		c.ConnAttrs[key] = value
+	}
+}
+
+// isolationNames 隔离级别 -> transaction_isolation 的取值
+var isolationNames = map[sql.IsolationLevel]string{
+	sql.LevelReadUncommitted: "READ-UNCOMMITTED",
+	sql.LevelReadCommitted:   "READ-COMMITTED",
+	sql.LevelRepeatableRead:  "REPEATABLE-READ",
+	sql.LevelSerializable:    "SERIALIZABLE",
+}
+
+// NewSessionConnector 包装已有的 Connector，每个新连接建立后执行一次 SET SESSION 设置 vars，
+// 用于自行通过 sql.OpenDB 创建连接池（例如读写分离的从库）的场景
+func NewSessionConnector(base driver.Connector, vars map[string]string) (driver.Connector, error) {
+	stmt, err := buildSetSession(vars)
+	if err != nil {
+		return nil, err
+	}
+	return &sessionConnector{base: base, stmt: stmt}, nil
+}
+
+// sessionConnector 建立连接后设置会话变量
+type sessionConnector struct {
+	base driver.Connector
+	stmt string
+}
+
+// Connect 建立连接并执行 SET SESSION，失败时关闭连接并返回错误
+func (s *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := s.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.stmt == "" {
+		return conn, nil
+	}
+	ec, ok := conn.(driver.ExecerContext)
+	if !ok {
+		_ = conn.Close()
+		return nil, fmt.Errorf("mysqlx: driver connection %T does not support ExecContext", conn)
+	}
+	if _, err := ec.ExecContext(ctx, s.stmt, nil); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("mysqlx: set session variables: %w", err)
+	}
+	return conn, nil
+}
+
+// Driver 实现 driver.Connector
+func (s *sessionConnector) Driver() driver.Driver { return s.base.Driver() }
+
+// Close 关闭底层 Connector，sql.DB.Close 时自动调用
+func (s *sessionConnector) Close() error {
+	if c, ok := s.base.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// buildSetSession 拼接 SET SESSION 语句：变量名只允许字母、数字、下划线与点，
+// 数值原样输出，其它值作为字符串字面量（不允许反斜杠与控制字符，避免受 NO_BACKSLASH_ESCAPES 影响）
+func buildSetSession(vars map[string]string) (string, error) {
+	if len(vars) == 0 {
+		return "", nil
+	}
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	assigns := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == "" || strings.TrimFunc(name, func(r rune) bool {
+			return r == '_' || r == '.' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		}) != "" {
+			return "", fmt.Errorf("mysqlx: invalid session variable name %q", name)
+		}
+		value := vars[name]
+		if value == "" {
+			return "", fmt.Errorf("mysqlx: empty value for session variable %s", name)
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			if strings.ContainsFunc(value, func(r rune) bool { return r == '\\' || r < ' ' }) {
+				return "", fmt.Errorf("mysqlx: invalid value for session variable %s", name)
+			}
+			value = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+		}
+		assigns = append(assigns, name+" = "+value)
+	}
+	return "SET SESSION " + strings.Join(assigns, ", "), nil
+}
+
+// formatConnAttrs 按 key 排序拼接为驱动要求的 "k1:v1,k2:v2" 形式
+func formatConnAttrs(attrs map[string]string) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+":"+attrs[k])
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,135 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestBuildSetSession(t *testing.T) {
+	cases := []struct {
+		name string
+		vars map[string]string
+		want string
+		err  string
+	}{
+		{"empty", nil, "", ""},
+		{"sorted, numbers unquoted", map[string]string{"time_zone": "+08:00", "max_execution_time": "3000"},
+			"SET SESSION max_execution_time = 3000, time_zone = '+08:00'", ""},
+		{"quote escaped", map[string]string{"sql_mode": "it's"}, "SET SESSION sql_mode = 'it''s'", ""},
+		{"dotted name", map[string]string{"innodb.lock_wait_timeout": "5"}, "SET SESSION innodb.lock_wait_timeout = 5", ""},
+		{"invalid name", map[string]string{"time_zone = 1; --": "x"}, "", "invalid session variable name"},
+		{"empty value", map[string]string{"time_zone": ""}, "", "empty value"},
+		{"backslash", map[string]string{"sql_mode": `a\`}, "", "invalid value"},
+		{"control character", map[string]string{"sql_mode": "a\nb"}, "", "invalid value"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := buildSetSession(tc.vars)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("err = %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Errorf("buildSetSession = %q, %v, want %q", got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestSessionOptions(t *testing.T) {
+	var c Config
+	for _, opt := range []Option{
+		WithTimeZone("+08:00"),
+		WithSQLMode("STRICT_ALL_TABLES"),
+		WithMaxExecutionTime(1500 * time.Millisecond),
+		WithIsolationLevel(sql.LevelReadCommitted),
+	} {
+		opt(&c)
+	}
+	got, err := buildSetSession(c.SessionVars)
+	want := "SET SESSION max_execution_time = 1500, sql_mode = 'STRICT_ALL_TABLES', time_zone = '+08:00', transaction_isolation = 'READ-COMMITTED'"
+	if err != nil || got != want {
+		t.Errorf("buildSetSession = %q, %v, want %q", got, err, want)
+	}
+
+	// 不支持的隔离级别在创建时报错
+	c = Config{}
+	WithIsolationLevel(sql.LevelSnapshot)(&c)
+	if _, err := buildSetSession(c.SessionVars); err == nil {
+		t.Error("unsupported isolation level accepted")
+	}
+}
+
+// TestSessionConnector_EveryNewConn 每个新建的物理连接都执行 SET SESSION，复用连接时不再执行
+func TestSessionConnector_EveryNewConn(t *testing.T) {
+	const dsn = "mysqlx_session_test"
+	mockDB, mock, err := sqlmock.NewWithDSN(dsn, sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("sqlmock.NewWithDSN: %v", err)
+	}
+	defer mockDB.Close()
+	connector, err := NewSessionConnector(dsnConnector{drv: mockDB.Driver(), dsn: dsn}, map[string]string{"time_zone": "+08:00"})
+	if err != nil {
+		t.Fatalf("NewSessionConnector: %v", err)
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	ctx := context.Background()
+
+	const set = "SET SESSION time_zone = '+08:00'"
+	mock.ExpectExec(set).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(set).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE t SET n = 1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// 同时持有两个连接，连接池需要新建两个物理连接
+	c1, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn 1: %v", err)
+	}
+	c2, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn 2: %v", err)
+	}
+	_ = c2.Close()
+	_ = c1.Close()
+
+	// 复用空闲连接
+	if _, err := db.ExecContext(ctx, "UPDATE t SET n = 1"); err != nil {
+		t.Fatalf("ExecContext: %v", err)
+	}
+	if got := db.Stats().OpenConnections; got != 2 {
+		t.Errorf("OpenConnections = %d, want 2", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestSessionConnector_SetFails 设置失败时不返回连接
+func TestSessionConnector_SetFails(t *testing.T) {
+	const dsn = "mysqlx_session_fail_test"
+	mockDB, mock, err := sqlmock.NewWithDSN(dsn, sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("sqlmock.NewWithDSN: %v", err)
+	}
+	defer mockDB.Close()
+	connector, err := NewSessionConnector(dsnConnector{drv: mockDB.Driver(), dsn: dsn}, map[string]string{"time_zone": "Asia/Shanghai"})
+	if err != nil {
+		t.Fatalf("NewSessionConnector: %v", err)
+	}
+
+	boom := errors.New("Unknown or incorrect time zone")
+	mock.ExpectExec("SET SESSION time_zone = 'Asia/Shanghai'").WillReturnError(boom)
+	conn, err := connector.Connect(context.Background())
+	if conn != nil || !errors.Is(err, boom) || !strings.Contains(err.Error(), "set session variables") {
+		t.Fatalf("Connect = %v, %v", conn, err)
+	}
+}
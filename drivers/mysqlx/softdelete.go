@@ -0,0 +1,145 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DeletedAtColumn 软删除约定的标记列：NULL 表示未删除，非 NULL 为删除时间
+const DeletedAtColumn = "deleted_at"
+
+// ErrEmptyWhere 软删除/恢复时未指定 where 条件；确需全表操作请显式传入 "1=1"
+var ErrEmptyWhere = errors.New("mysqlx: where clause must not be empty")
+
+// Execer 可执行写语句的对象，*sql.DB、*sql.Tx、*sql.Conn 均满足
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Queryer 可执行查询的对象，*sql.DB、*sql.Tx、*sql.Conn 均满足
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// NotDeleted 为查询条件追加 deleted_at IS NULL，用于只读取未删除的数据
+// 使用示例：
+//
+//	q := "SELECT id, name FROM users WHERE " + mysqlx.NotDeleted("tenant_id = ?")
+func NotDeleted(where string) string {
+	return andCond(where, DeletedAtColumn+" IS NULL")
+}
+
+// OnlyDeleted 为查询条件追加 deleted_at IS NOT NULL，用于回收站等只看已删除数据的场景
+func OnlyDeleted(where string) string {
+	return andCond(where, DeletedAtColumn+" IS NOT NULL")
+}
+
+// SoftDelete 将匹配的未删除记录标记为已删除，返回受影响行数
+func SoftDelete(ctx context.Context, db Execer, table, where string, args ...interface{}) (int64, error) {
+	if strings.TrimSpace(where) == "" {
+		return 0, ErrEmptyWhere
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s = NOW() WHERE %s", QuoteIdent(table), DeletedAtColumn, NotDeleted(where))
+	return execAffected(ctx, db, query, args...)
+}
+
+// Restore 恢复匹配的已删除记录，返回受影响行数
+func Restore(ctx context.Context, db Execer, table, where string, args ...interface{}) (int64, error) {
+	if strings.TrimSpace(where) == "" {
+		return 0, ErrEmptyWhere
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s = NULL WHERE %s", QuoteIdent(table), DeletedAtColumn, OnlyDeleted(where))
+	return execAffected(ctx, db, query, args...)
+}
+
+// CountActive 统计匹配条件的未删除记录数，where 为空时统计全表
+func CountActive(ctx context.Context, db Queryer, table, where string, args ...interface{}) (int64, error) {
+	var n int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", QuoteIdent(table), NotDeleted(where))
+	err := db.QueryRowContext(ctx, query, args...).Scan(&n)
+	return n, err
+}
+
+// QuoteIdent 使用反引号转义标识符并以 . 连接，如 QuoteIdent(schema, table)；
+// 多个参数时每个参数都作为一个完整的标识符（可包含 .），只传一个参数时按 . 拆分，兼容 db.table 形式，
+// 此时反引号内的 . 不拆分，如 "`tenant.v2`.orders"；已用反引号包裹的部分（内部 “ 为转义）不会重复转义
+func QuoteIdent(parts ...string) string {
+	if len(parts) == 1 {
+		names := splitIdent(parts[0])
+		for i, n := range names {
+			names[i] = escapeName(n)
+		}
+		return strings.Join(names, ".")
+	}
+	quoted := make([]string, len(parts))
+	for i, p := range parts {
+		quoted[i] = quoteName(p)
+	}
+	return strings.Join(quoted, ".")
+}
+
+// quoteName 将 name 整体作为一个标识符转义，不按 . 拆分；name 已用反引号包裹时先去掉引号
+func quoteName(name string) string {
+	if len(name) >= 2 && name[0] == '`' && name[len(name)-1] == '`' {
+		name = strings.ReplaceAll(name[1:len(name)-1], "``", "`")
+	}
+	return escapeName(name)
+}
+
+// escapeName 为原始名称加反引号，名称中的反引号写成 “
+func escapeName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// splitIdent 按反引号外的 . 拆分标识符，以反引号开头的部分视为已转义，去掉引号并还原 “ 转义，
+// 其余部分中的反引号按普通字符处理
+func splitIdent(s string) []string {
+	var (
+		names  []string
+		cur    strings.Builder
+		quoted bool
+		start  = true // 处于一个部分的开头
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quoted && c == '`' && i+1 < len(s) && s[i+1] == '`':
+			cur.WriteByte('`')
+			i++
+		case quoted && c == '`':
+			quoted = false
+		case start && c == '`':
+			quoted = true
+		case !quoted && c == '.':
+			names = append(names, cur.String())
+			cur.Reset()
+			start = true
+			continue
+		default:
+			cur.WriteByte(c)
+		}
+		start = false
+	}
+	return append(names, cur.String())
+}
+
+// andCond 用 AND 连接条件，原条件加括号避免 OR 优先级问题
+func andCond(where, cond string) string {
+	if strings.TrimSpace(where) == "" {
+		return cond
+	}
+	return "(" + where + ") AND " + cond
+}
+
+// execAffected 执行语句并返回受影响行数
+func execAffected(ctx context.Context, db Execer, query string, args ...interface{}) (int64, error) {
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,85 @@
+package mysqlx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestNotDeletedAndOnlyDeleted(t *testing.T) {
+	cases := []struct {
+		where       string
+		notDeleted  string
+		onlyDeleted string
+	}{
+		{"", "deleted_at IS NULL", "deleted_at IS NOT NULL"},
+		{"  ", "deleted_at IS NULL", "deleted_at IS NOT NULL"},
+		{"id = ?", "(id = ?) AND deleted_at IS NULL", "(id = ?) AND deleted_at IS NOT NULL"},
+		{"a = 1 OR b = 2", "(a = 1 OR b = 2) AND deleted_at IS NULL", "(a = 1 OR b = 2) AND deleted_at IS NOT NULL"},
+	}
+	for _, tc := range cases {
+		if got := NotDeleted(tc.where); got != tc.notDeleted {
+			t.Errorf("NotDeleted(%q) = %s, want %s", tc.where, got, tc.notDeleted)
+		}
+		if got := OnlyDeleted(tc.where); got != tc.onlyDeleted {
+			t.Errorf("OnlyDeleted(%q) = %s, want %s", tc.where, got, tc.onlyDeleted)
+		}
+	}
+}
+
+func TestSoftDeleteAndRestore(t *testing.T) {
+	cases := []struct {
+		name  string
+		table string
+		want  string // 转义后的表名
+	}{
+		{"plain", "users", "`users`"},
+		{"schema.table", "shop.users", "`shop`.`users`"},
+		{"quoted with dot", "`tenant.v2`.users", "`tenant.v2`.`users`"},
+		{"backtick in name", "us`ers", "`us``ers`"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			ctx := context.Background()
+			mock.ExpectExec("UPDATE "+tc.want+" SET deleted_at = NOW() WHERE (id = ? OR name = ?) AND deleted_at IS NULL").
+				WithArgs(1, "a").WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec("UPDATE " + tc.want + " SET deleted_at = NULL WHERE (id = ?) AND deleted_at IS NOT NULL").
+				WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery("SELECT COUNT(*) FROM " + tc.want + " WHERE deleted_at IS NULL").
+				WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(3))
+
+			if n, err := SoftDelete(ctx, db, tc.table, "id = ? OR name = ?", 1, "a"); err != nil || n != 2 {
+				t.Fatalf("SoftDelete = %d, %v", n, err)
+			}
+			if n, err := Restore(ctx, db, tc.table, "id = ?", 1); err != nil || n != 1 {
+				t.Fatalf("Restore = %d, %v", n, err)
+			}
+			if n, err := CountActive(ctx, db, tc.table, ""); err != nil || n != 3 {
+				t.Fatalf("CountActive = %d, %v", n, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestSoftDelete_EmptyWhere 未指定条件时拒绝执行，避免误操作全表
+func TestSoftDelete_EmptyWhere(t *testing.T) {
+	db, mock := newMockDB(t)
+	ctx := context.Background()
+	for _, where := range []string{"", " \t"} {
+		if _, err := SoftDelete(ctx, db, "users", where); !errors.Is(err, ErrEmptyWhere) {
+			t.Errorf("SoftDelete(%q) err = %v, want ErrEmptyWhere", where, err)
+		}
+		if _, err := Restore(ctx, db, "users", where); !errors.Is(err, ErrEmptyWhere) {
+			t.Errorf("Restore(%q) err = %v, want ErrEmptyWhere", where, err)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
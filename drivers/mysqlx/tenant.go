@@ -0,0 +1,160 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/qingfeng-studio/go-utils/utils/ctxx"
+)
+
+var (
+	// ErrNoTenant context 中没有租户信息
+	ErrNoTenant = errors.New("mysqlx: tenant not found in context")
+	// ErrInvalidTenant 租户 ID 含有非法字符
+	ErrInvalidTenant = errors.New("mysqlx: invalid tenant id")
+)
+
+// tenantIDPattern 租户 ID 只允许字母、数字、下划线与中划线，避免拼接库名时注入
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// WithTenant 把租户 ID 放入 context，TenantDB 据此切换到对应的租户库，等同于 ctxx.WithTenantID
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return ctxx.WithTenantID(ctx, tenantID)
+}
+
+// TenantFromContext 读取 context 中的租户 ID（ctxx.WithTenantID 或 WithTenant 写入），空串视为未设置
+func TenantFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctxx.TenantID(ctx)
+	return id, ok && id != ""
+}
+
+// TenantResolver 把租户 ID 映射为库名
+type TenantResolver func(tenantID string) (string, error)
+
+// TenantSchemaPattern 按格式生成库名，如 TenantSchemaPattern("tenant_%s") 把 t1 映射为 tenant_t1
+func TenantSchemaPattern(pattern string) TenantResolver {
+	return func(tenantID string) (string, error) {
+		if !tenantIDPattern.MatchString(tenantID) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidTenant, tenantID)
+		}
+		return fmt.Sprintf(pattern, tenantID), nil
+	}
+}
+
+// TenantOption TenantDB 的函数式选项
+type TenantOption func(*TenantDB)
+
+// WithTenantResolver 自定义租户 ID 到库名的映射，默认 TenantSchemaPattern("tenant_%s")
+func WithTenantResolver(r TenantResolver) TenantOption {
+	return func(t *TenantDB) { t.resolve = r }
+}
+
+// WithDefaultSchema 设置连接归还前切回的默认库（通常为 DSN 中的 DBName）；
+// 未设置时连接使用后直接丢弃，避免带着租户库回到连接池
+func WithDefaultSchema(name string) TenantOption {
+	return func(t *TenantDB) { t.defaultSchema = name }
+}
+
+// TenantDB 库级多租户（database-per-tenant）辅助：从连接池取出连接后执行 USE 切换到租户库，
+// 使用完毕切回默认库再归还，保证连接池中的连接不会残留其它租户的库
+//
+// 使用示例：
+//
+//	tdb := mysqlx.NewTenantDB(db, mysqlx.WithDefaultSchema("app"))
+//	ctx = mysqlx.WithTenant(ctx, "t1")
+//	err := tdb.Tx(ctx, nil, func(ctx context.Context, tx *sql.Tx) error {
+//		_, err := tx.ExecContext(ctx, "UPDATE orders SET status = ? WHERE id = ?", 2, id)
+//		return err
+//	})
+type TenantDB struct {
+	db            *sql.DB
+	resolve       TenantResolver
+	defaultSchema string
+}
+
+// NewTenantDB 创建多租户辅助对象
+func NewTenantDB(db *sql.DB, opts ...TenantOption) *TenantDB {
+	t := &TenantDB{db: db, resolve: TenantSchemaPattern("tenant_%s")}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Schema 返回 context 中租户对应的库名
+func (t *TenantDB) Schema(ctx context.Context) (string, error) {
+	id, ok := TenantFromContext(ctx)
+	if !ok {
+		return "", ErrNoTenant
+	}
+	return t.resolve(id)
+}
+
+// Table 返回带租户库名前缀的表名（已转义），如 `tenant_t1`.`orders`，
+// 适用于不切换连接、在 SQL 中直接指定库名的用法
+func (t *TenantDB) Table(ctx context.Context, table string) (string, error) {
+	schema, err := t.Schema(ctx)
+	if err != nil {
+		return "", err
+	}
+	return QuoteIdent(schema, table), nil
+}
+
+// Conn 取出一个连接并切换到租户库后执行 fn，fn 返回后切回默认库并归还连接
+func (t *TenantDB) Conn(ctx context.Context, fn func(ctx context.Context, conn *sql.Conn) error) (err error) {
+	schema, err := t.Schema(ctx)
+	if err != nil {
+		return err
+	}
+	conn, err := t.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rerr := t.release(ctx, conn); err == nil {
+			err = rerr
+		}
+	}()
+	if _, err := conn.ExecContext(ctx, "USE "+quoteName(schema)); err != nil {
+		return err
+	}
+	return fn(ctx, conn)
+}
+
+// Tx 在租户库上开启事务执行 fn，fn 返回错误或 panic 时回滚，否则提交
+func (t *TenantDB) Tx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	return t.Conn(ctx, func(ctx context.Context, conn *sql.Conn) (err error) {
+		tx, err := conn.BeginTx(ctx, opts)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				_ = tx.Rollback()
+				panic(p)
+			}
+			if err != nil {
+				_ = tx.Rollback()
+				return
+			}
+			err = tx.Commit()
+		}()
+		return fn(ctx, tx)
+	})
+}
+
+// release 切回默认库后归还连接；未配置默认库或切换失败时丢弃该物理连接
+func (t *TenantDB) release(ctx context.Context, conn *sql.Conn) error {
+	if t.defaultSchema != "" {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "USE "+quoteName(t.defaultSchema)); err == nil {
+			return conn.Close()
+		}
+	}
+	// Raw 返回 ErrBadConn 时连接池会关闭该物理连接而不是复用，*sql.Conn 随之关闭，无需再 Close
+	_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	return nil
+}
@@ -0,0 +1,158 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/qingfeng-studio/go-utils/utils/ctxx"
+)
+
+// TestTenantDB_CtxxTenant ctxx.WithTenantID 写入的租户同样驱动 TenantDB 的库切换
+func TestTenantDB_CtxxTenant(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+	tdb := NewTenantDB(db, WithDefaultSchema("app"))
+
+	mock.ExpectExec("USE `tenant_t1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE orders SET status = 2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("USE `app`").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx := ctxx.WithTenantID(context.Background(), "t1")
+	if id, ok := TenantFromContext(ctx); !ok || id != "t1" {
+		t.Fatalf("TenantFromContext = %q, %v", id, ok)
+	}
+	err = tdb.Conn(ctx, func(ctx context.Context, conn *sql.Conn) error {
+		_, err := conn.ExecContext(ctx, "UPDATE orders SET status = 2")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Conn: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTenantSchemaPattern(t *testing.T) {
+	resolve := TenantSchemaPattern("tenant_%s")
+	cases := []struct {
+		id      string
+		want    string
+		invalid bool
+	}{
+		{"t1", "tenant_t1", false},
+		{"Acme_01-cn", "tenant_Acme_01-cn", false},
+		{"", "", true},
+		{"t1`; DROP DATABASE app; --", "", true},
+		{"shop.t1", "", true},
+		{"t 1", "", true},
+		{strings.Repeat("a", 65), "", true},
+	}
+	for _, tc := range cases {
+		got, err := resolve(tc.id)
+		if tc.invalid {
+			if !errors.Is(err, ErrInvalidTenant) {
+				t.Errorf("resolve(%q) err = %v, want ErrInvalidTenant", tc.id, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("resolve(%q) = %q, %v, want %q", tc.id, got, err, tc.want)
+		}
+	}
+}
+
+func TestTenantDB_Table(t *testing.T) {
+	db, _ := newMockDB(t)
+	// 自定义映射可能返回含 . 或反引号的库名，整体作为一个标识符转义
+	custom := NewTenantDB(db, WithTenantResolver(func(id string) (string, error) {
+		return map[string]string{
+			"dotted":   "tenant.v2",
+			"backtick": "te`nant",
+		}[id], nil
+	}))
+	cases := []struct {
+		name    string
+		tdb     *TenantDB
+		ctx     context.Context
+		table   string
+		want    string
+		wantErr error
+	}{
+		{"default pattern", NewTenantDB(db), WithTenant(context.Background(), "t1"), "orders", "`tenant_t1`.`orders`", nil},
+		{"dotted schema", custom, WithTenant(context.Background(), "dotted"), "orders", "`tenant.v2`.`orders`", nil},
+		{"backtick in schema", custom, WithTenant(context.Background(), "backtick"), "orders", "`te``nant`.`orders`", nil},
+		{"backtick in table", NewTenantDB(db), WithTenant(context.Background(), "t1"), "ord`ers", "`tenant_t1`.`ord``ers`", nil},
+		{"empty tenant id", NewTenantDB(db), WithTenant(context.Background(), ""), "orders", "", ErrNoTenant},
+		{"no tenant", NewTenantDB(db), context.Background(), "orders", "", ErrNoTenant},
+		{"invalid tenant id", NewTenantDB(db), WithTenant(context.Background(), "t1`"), "orders", "", ErrInvalidTenant},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.tdb.Table(tc.ctx, tc.table)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("Table err = %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Errorf("Table = %s, %v, want %s", got, err, tc.want)
+			}
+		})
+	}
+}
+
+// TestTenantDB_ConnQuotesSchema USE 语句中的库名按单个标识符转义；未设置租户时不取连接
+func TestTenantDB_ConnQuotesSchema(t *testing.T) {
+	db, mock := newMockDB(t)
+	tdb := NewTenantDB(db, WithDefaultSchema("app`main"), WithTenantResolver(func(string) (string, error) { return "tenant.v2`x", nil }))
+
+	mock.ExpectExec("USE `tenant.v2``x`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("USE `app``main`").WillReturnResult(sqlmock.NewResult(0, 0))
+	if err := tdb.Conn(WithTenant(context.Background(), "t1"), func(context.Context, *sql.Conn) error { return nil }); err != nil {
+		t.Fatalf("Conn: %v", err)
+	}
+
+	called := false
+	err := tdb.Conn(WithTenant(context.Background(), ""), func(context.Context, *sql.Conn) error { called = true; return nil })
+	if !errors.Is(err, ErrNoTenant) || called {
+		t.Fatalf("Conn with empty tenant = %v, called %v, want ErrNoTenant", err, called)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestTenantDB_TxWithoutDefaultSchema 未设置默认库时连接用完即丢弃，已提交的事务不应返回错误
+func TestTenantDB_TxWithoutDefaultSchema(t *testing.T) {
+	db, mock := newMockDB(t)
+	tdb := NewTenantDB(db)
+
+	mock.ExpectExec("USE `tenant_t1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE orders SET status = 2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := tdb.Tx(WithTenant(context.Background(), "t1"), nil, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "UPDATE orders SET status = 2")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Tx: %v", err)
+	}
+	if n := db.Stats().OpenConnections; n != 0 {
+		t.Errorf("OpenConnections = %d, want 0 (connection discarded)", n)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,43 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/qingfeng-studio/go-utils/utils/otelx"
+)
+
+// tracingComponent mysqlx 的 instrumentation scope 名称
+const tracingComponent = "mysqlx"
+
+// WithTracing 为每条语句创建客户端 span，名称为语句类型（SELECT、INSERT 等），
+// 属性含 db.system、db.name 与 db.statement（参数不写入）；需先调用 otelx.Init
+func WithTracing() Option {
+	return func(c *Config) { c.Tracing = true }
+}
+
+// NewTracingConnector 包装 Connector，为连接上每条语句创建 span，适用于自行 sql.OpenDB 的场景；
+// span 在语句返回后以开始时间补建，驱动要求改走预处理语句（driver.ErrSkip）的那次调用不产生 span
+func NewTracingConnector(base driver.Connector, dbName string) driver.Connector {
+	return newObserveConnector(base, func(ctx context.Context, query string, _ []driver.NamedValue) (context.Context, func(error)) {
+		start := time.Now()
+		return ctx, func(err error) {
+			if err == driver.ErrSkip {
+				return
+			}
+			_, span := otelx.Tracer(tracingComponent).Start(ctx, statementType(query),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithTimestamp(start),
+				trace.WithAttributes(
+					attribute.String("db.system", "mysql"),
+					attribute.String("db.name", dbName),
+					attribute.String("db.statement", query),
+				))
+			otelx.End(span, err)
+		}
+	})
+}
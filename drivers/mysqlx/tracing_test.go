@@ -0,0 +1,60 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/qingfeng-studio/go-utils/config"
+	"github.com/qingfeng-studio/go-utils/utils/otelx"
+)
+
+func TestTracingConnector(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	shutdown, err := otelx.Init(context.Background(), config.Tracing{Enabled: true, Sampler: config.SamplerAlwaysOn},
+		otelx.WithExporter(exp), otelx.WithSyncExport())
+	if err != nil {
+		t.Fatalf("otelx.Init: %v", err)
+	}
+	defer shutdown(context.Background())
+
+	mockDB, mock, err := sqlmock.NewWithDSN("mysqlx_tracing_test", sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("sqlmock.NewWithDSN: %v", err)
+	}
+	defer mockDB.Close()
+	db := sql.OpenDB(NewTracingConnector(dsnConnector{drv: mockDB.Driver(), dsn: "mysqlx_tracing_test"}, "orders"))
+
+	mock.ExpectQuery("SELECT id FROM t WHERE id = ?").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec("UPDATE t SET v = ?").WithArgs(2).WillReturnError(errors.New("boom"))
+
+	var id int
+	if err := db.QueryRow("SELECT id FROM t WHERE id = ?", 1).Scan(&id); err != nil {
+		t.Fatalf("QueryRow: %v", err)
+	}
+	if _, err := db.Exec("UPDATE t SET v = ?", 2); err == nil {
+		t.Fatal("Exec: expected error")
+	}
+
+	spans := exp.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[0].Name != "SELECT" || spans[0].Status.Code == codes.Error {
+		t.Errorf("unexpected span: %+v", spans[0])
+	}
+	if spans[1].Name != "UPDATE" || spans[1].Status.Code != codes.Error {
+		t.Errorf("unexpected span: %+v", spans[1])
+	}
+	for _, kv := range spans[0].Attributes {
+		if kv.Key == "db.statement" && kv.Value.AsString() != "SELECT id FROM t WHERE id = ?" {
+			t.Errorf("db.statement = %q", kv.Value.AsString())
+		}
+	}
+}
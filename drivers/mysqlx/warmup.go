@@ -0,0 +1,58 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultWarmupTimeout 未配置 PingTimeout 时 New 中预热的超时，避免数据库不可达时阻塞启动
+const defaultWarmupTimeout = 10 * time.Second
+
+// Warmup 预先建立 n 个连接并放回连接池，避免发布后第一波流量承担建连延迟
+// 做法是并发从池中取出 n 个连接各自 Ping，全部完成后再统一归还；
+// 注意 MaxIdleConns 需 >= n，否则多余的连接归还时会被关闭；n 超过 MaxOpenConns 时按 MaxOpenConns 预热，
+// 否则多出的协程会一直等待被其它协程占住的连接。返回成功预热的连接数
+func Warmup(ctx context.Context, db *sql.DB, n int) (int, error) {
+	if max := db.Stats().MaxOpenConnections; max > 0 && n > max {
+		n = max
+	}
+	if n <= 0 {
+		return 0, nil
+	}
+
+	var (
+		mu    sync.Mutex
+		conns []*sql.Conn
+		errs  []error
+		wg    sync.WaitGroup
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := db.Conn(ctx)
+			if err == nil {
+				if err = conn.PingContext(ctx); err != nil {
+					_ = conn.Close()
+				}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			conns = append(conns, conn)
+		}()
+	}
+	wg.Wait()
+
+	// 所有连接同时持有后再归还，确保池中确实存在 n 个物理连接
+	for _, c := range conns {
+		_ = c.Close()
+	}
+	return len(conns), errors.Join(errs...)
+}
@@ -0,0 +1,91 @@
+package mysqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingConnector 只统计建连次数的假驱动，连接不支持执行语句
+type countingConnector struct {
+	opened atomic.Int32
+}
+
+func (c *countingConnector) Connect(context.Context) (driver.Conn, error) {
+	c.opened.Add(1)
+	return fakeConn{}, nil
+}
+
+func (c *countingConnector) Driver() driver.Driver { return nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+func (fakeConn) Ping(context.Context) error          { return nil }
+
+func TestWarmup(t *testing.T) {
+	connector := &countingConnector{}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	db.SetMaxIdleConns(5)
+
+	n, err := Warmup(context.Background(), db, 4)
+	if err != nil || n != 4 {
+		t.Fatalf("Warmup = %d, %v", n, err)
+	}
+	if got := db.Stats().Idle; got != 4 {
+		t.Errorf("idle conns = %d, want 4", got)
+	}
+}
+
+// TestWarmup_CappedByMaxOpen n 超过 MaxOpenConns 时不能死锁
+func TestWarmup_CappedByMaxOpen(t *testing.T) {
+	connector := &countingConnector{}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	db.SetMaxOpenConns(2)
+
+	done := make(chan struct{})
+	var n int
+	var err error
+	go func() {
+		n, err = Warmup(context.Background(), db, 5)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Warmup deadlocked")
+	}
+	if err != nil || n != 2 {
+		t.Fatalf("Warmup = %d, %v, want 2", n, err)
+	}
+	if got := connector.opened.Load(); got != 2 {
+		t.Errorf("opened %d conns, want 2", got)
+	}
+}
+
+// TestNew_WarmupExceedsMaxOpen New 中预热数大于连接池上限且未设置 PingTimeout 时仍能返回
+func TestNew_WarmupExceedsMaxOpen(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		db, err := New(Config{Addr: "127.0.0.1:1", MaxOpenConns: 1, WarmupConns: 3, Params: map[string]string{"timeout": "100ms"}})
+		if err != nil {
+			t.Errorf("New: %v", err)
+			return
+		}
+		_ = db.Close()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("New blocked on warmup")
+	}
+}
@@ -0,0 +1,173 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SlotCount Redis Cluster 的 hash slot 总数
+const SlotCount = 16384
+
+// batchChunkSize 单条 MGET/MSET 命令包含的最大 key 数，避免单个命令过大阻塞节点
+const batchChunkSize = 500
+
+// Slot 计算 key 所属的 hash slot，规则与 Redis Cluster 一致（支持 {tag} 哈希标签）
+func Slot(key string) int {
+	if s := strings.IndexByte(key, '{'); s >= 0 {
+		if e := strings.IndexByte(key[s+1:], '}'); e > 0 {
+			key = key[s+1 : s+1+e]
+		}
+	}
+	return int(crc16(key) % SlotCount)
+}
+
+// GroupBySlot 按 slot 对 key 分组，同组的 key 可以放在同一条多 key 命令中
+func GroupBySlot(keys []string) map[int][]string {
+	groups := make(map[int][]string)
+	for _, k := range keys {
+		s := Slot(k)
+		groups[s] = append(groups[s], k)
+	}
+	return groups
+}
+
+// BatchGet 批量读取，返回存在的 key -> 值
+// key 先按 slot 分组，每组（按 batchChunkSize 切块）发一条 MGET，所有命令放进一个 pipeline，
+// 集群客户端会按节点分发并合并结果；对单机客户端同样可用
+func BatchGet(ctx context.Context, c redis.Cmdable, keys ...string) (map[string]string, error) {
+	out := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return out, nil
+	}
+
+	type chunk struct {
+		keys []string
+		cmd  *redis.SliceCmd
+	}
+	var chunks []*chunk
+	_, err := c.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for _, group := range sortedGroups(keys) {
+			for _, ks := range splitChunks(group) {
+				chunks = append(chunks, &chunk{keys: ks, cmd: p.MGet(ctx, ks...)})
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	for _, ch := range chunks {
+		vals, err := ch.cmd.Result()
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range vals {
+			if s, ok := v.(string); ok {
+				out[ch.keys[i]] = s
+			}
+		}
+	}
+	return out, nil
+}
+
+// BatchSet 批量写入；ttl 为 0 时按 slot 分组使用 MSET，否则在 pipeline 中逐个 SET EX
+func BatchSet(ctx context.Context, c redis.Cmdable, values map[string]interface{}, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+
+	_, err := c.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for _, group := range sortedGroups(keys) {
+			if ttl > 0 {
+				for _, k := range group {
+					p.Set(ctx, k, values[k], ttl)
+				}
+				continue
+			}
+			for _, ks := range splitChunks(group) {
+				pairs := make([]interface{}, 0, len(ks)*2)
+				for _, k := range ks {
+					pairs = append(pairs, k, values[k])
+				}
+				p.MSet(ctx, pairs...)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// BatchDel 批量删除，按 slot 分组发送 DEL，返回删除的 key 数量
+func BatchDel(ctx context.Context, c redis.Cmdable, keys ...string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	var cmds []*redis.IntCmd
+	_, err := c.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for _, group := range sortedGroups(keys) {
+			for _, ks := range splitChunks(group) {
+				cmds = append(cmds, p.Del(ctx, ks...))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	for _, cmd := range cmds {
+		n += cmd.Val()
+	}
+	return n, nil
+}
+
+// sortedGroups 按 slot 分组并按 slot 排序，保证命令顺序稳定
+func sortedGroups(keys []string) [][]string {
+	groups := GroupBySlot(keys)
+	slots := make([]int, 0, len(groups))
+	for s := range groups {
+		slots = append(slots, s)
+	}
+	sort.Ints(slots)
+	out := make([][]string, 0, len(slots))
+	for _, s := range slots {
+		out = append(out, groups[s])
+	}
+	return out
+}
+
+// splitChunks 将同一 slot 的 key 按 batchChunkSize 切块
+func splitChunks(keys []string) [][]string {
+	var out [][]string
+	for len(keys) > batchChunkSize {
+		out = append(out, keys[:batchChunkSize])
+		keys = keys[batchChunkSize:]
+	}
+	return append(out, keys)
+}
+
+// crc16 CRC16-CCITT (XMODEM)，Redis Cluster 使用的 slot 算法
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
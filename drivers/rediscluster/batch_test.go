@@ -0,0 +1,99 @@
+package rediscluster
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSlot(t *testing.T) {
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{"foo", 12182},
+		{"bar", 5061},
+		{"hello", 866},
+		{"123456789", 0x31C3}, // CRC16/XMODEM 的标准校验值
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := Slot(tt.key); got != tt.want {
+			t.Errorf("Slot(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestSlot_HashTag(t *testing.T) {
+	tests := []struct {
+		key, same string
+	}{
+		{"{user1000}.following", "{user1000}.followers"},
+		{"{user1000}.following", "user1000"},
+		{"foo{bar}{zap}", "bar"},  // 只取第一个 {} 中的内容
+		{"foo{{bar}}zap", "{bar"}, // 第一个 { 到其后第一个 } 之间
+		{"quota:{u1}:d:20261016", "u1"},
+	}
+	for _, tt := range tests {
+		if got, want := Slot(tt.key), Slot(tt.same); got != want {
+			t.Errorf("Slot(%q) = %d, want Slot(%q) = %d", tt.key, got, tt.same, want)
+		}
+	}
+	// 空标签与未闭合的 { 不是哈希标签，对整个 key 计算
+	for _, key := range []string{"foo{}{bar}", "foo{bar", "}foo{"} {
+		if got, want := Slot(key), int(crc16(key)%SlotCount); got != want {
+			t.Errorf("Slot(%q) = %d, want %d", key, got, want)
+		}
+	}
+}
+
+func TestSplitChunks(t *testing.T) {
+	keys := make([]string, batchChunkSize*2+1)
+	chunks := splitChunks(keys)
+	if len(chunks) != 3 || len(chunks[0]) != batchChunkSize || len(chunks[2]) != 1 {
+		t.Fatalf("chunk sizes = %d", len(chunks))
+	}
+	if got := splitChunks(nil); len(got) != 1 || len(got[0]) != 0 {
+		t.Errorf("splitChunks(nil) = %v", got)
+	}
+}
+
+func TestBatchSetGetDel(t *testing.T) {
+	mr, cli := newTestClient(t)
+	ctx := context.Background()
+
+	values := make(map[string]interface{})
+	keys := make([]string, 0, batchChunkSize+10)
+	for i := 0; i < batchChunkSize+10; i++ {
+		k := "{tag}:" + strconv.Itoa(i) // 同一 slot，触发切块
+		values[k] = strconv.Itoa(i)
+		keys = append(keys, k)
+	}
+	values["other"] = "x"
+	if err := BatchSet(ctx, cli, values, 0); err != nil {
+		t.Fatalf("BatchSet: %v", err)
+	}
+	if err := BatchSet(ctx, cli, map[string]interface{}{"ttl": "y"}, time.Minute); err != nil {
+		t.Fatalf("BatchSet ttl: %v", err)
+	}
+	if ttl := mr.TTL("ttl"); ttl != time.Minute {
+		t.Errorf("ttl = %v, want 1m", ttl)
+	}
+
+	got, err := BatchGet(ctx, cli, append(keys, "other", "missing")...)
+	if err != nil {
+		t.Fatalf("BatchGet: %v", err)
+	}
+	if len(got) != len(values) || got["{tag}:42"] != "42" || got["other"] != "x" {
+		t.Fatalf("BatchGet returned %d values", len(got))
+	}
+	if _, ok := got["missing"]; ok {
+		t.Error("missing key returned")
+	}
+
+	n, err := BatchDel(ctx, cli, append(keys, "other", "missing")...)
+	if err != nil || n != int64(len(values)) {
+		t.Fatalf("BatchDel = %d, %v, want %d", n, err, len(values))
+	}
+}
@@ -0,0 +1,186 @@
+package rediscluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrStopScan 在扫描回调中返回以提前结束扫描，Scan/ScanCluster 此时返回 nil
+var ErrStopScan = errors.New("rediscluster: stop scan")
+
+// defaultScanCount 每次 SCAN 的默认 COUNT
+const defaultScanCount = 100
+
+// GetTouch 读取 key 并刷新过期时间（GETEX EX/PX），适合滑动过期的缓存；
+// ttl <= 0 时只读取不修改过期时间，key 不存在时返回 redis.Nil（需要 Redis 6.2+）
+func GetTouch(ctx context.Context, c redis.Cmdable, key string, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		ttl = -1 // go-redis 中 0 表示 PERSIST，负数表示不带选项
+	}
+	return c.GetEx(ctx, key, ttl).Result()
+}
+
+// GetPersist 读取 key 并移除其过期时间（GETEX PERSIST）
+func GetPersist(ctx context.Context, c redis.Cmdable, key string) (string, error) {
+	return c.GetEx(ctx, key, 0).Result()
+}
+
+// GetTouchJSON 读取 key 并按 JSON 解码为 T，同时刷新过期时间，语义同 GetTouch
+func GetTouchJSON[T any](ctx context.Context, c redis.Cmdable, key string, ttl time.Duration) (T, error) {
+	var out T
+	data, err := GetTouch(ctx, c, key, ttl)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal([]byte(data), &out); err != nil {
+		return out, fmt.Errorf("decode %s: %w", key, err)
+	}
+	return out, nil
+}
+
+// GetDelJSON 原子地读取并删除 key（GETDEL），按 JSON 解码为 T，适合一次性令牌等场景
+func GetDelJSON[T any](ctx context.Context, c redis.Cmdable, key string) (T, error) {
+	var out T
+	data, err := c.GetDel(ctx, key).Bytes()
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("decode %s: %w", key, err)
+	}
+	return out, nil
+}
+
+// SetKeepTTL 覆盖 key 的值并保留原有过期时间（SET KEEPTTL），key 不存在时写入后不过期
+func SetKeepTTL(ctx context.Context, c redis.Cmdable, key string, value interface{}) error {
+	return c.Set(ctx, key, value, redis.KeepTTL).Err()
+}
+
+// ObjectFreqs 批量读取 key 的 LFU 访问频率（OBJECT FREQ），用于热 key 抽样分析；
+// 不存在的 key 不会出现在结果中。服务端 maxmemory-policy 需为 LFU 策略，否则返回错误
+func ObjectFreqs(ctx context.Context, c redis.Cmdable, keys ...string) (map[string]int64, error) {
+	out := make(map[string]int64, len(keys))
+	if len(keys) == 0 {
+		return out, nil
+	}
+	cmds := make([]*redis.IntCmd, len(keys))
+	_, err := c.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for i, k := range keys {
+			cmds[i] = p.ObjectFreq(ctx, k)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	for i, cmd := range cmds {
+		n, err := cmd.Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out[keys[i]] = n
+	}
+	return out, nil
+}
+
+// ScanOptions SCAN 参数
+type ScanOptions struct {
+	Match string // 匹配模式，空表示全部
+	Count int64  // 每次迭代的 COUNT 提示，默认 100
+	Type  string // 按类型过滤（string/list/set/zset/hash/stream），需要 Redis 6.0+
+}
+
+// Scan 使用 SCAN 迭代 key 并对每个 key 调用 fn，fn 返回 ErrStopScan 时提前结束；
+// 按 SCAN 语义同一 key 可能被回调多次；对 *redis.ClusterClient 调用时只会扫描其中一个节点，集群全量扫描请使用 ScanCluster
+func Scan(ctx context.Context, c redis.Cmdable, opts ScanOptions, fn func(key string) error) error {
+	err := scanNode(ctx, c, opts, fn)
+	if errors.Is(err, ErrStopScan) {
+		return nil
+	}
+	return err
+}
+
+// ScanCluster 在集群所有主节点上并发执行 SCAN 并汇总结果，fn 会被串行调用，无需自行加锁；
+// 任一节点出错或 fn 返回错误时停止所有节点的扫描，fn 返回 ErrStopScan 时返回 nil
+func ScanCluster(ctx context.Context, cli *redis.ClusterClient, opts ScanOptions, fn func(key string) error) error {
+	var (
+		mu      sync.Mutex
+		stopped error
+	)
+	err := cli.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		return scanNode(ctx, node, opts, func(key string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if stopped != nil {
+				return stopped
+			}
+			if err := fn(key); err != nil {
+				stopped = err
+				return err
+			}
+			return nil
+		})
+	})
+	if errors.Is(err, ErrStopScan) {
+		return nil
+	}
+	return err
+}
+
+// ScanKeys 扫描集群（或单机）中匹配的全部 key，limit > 0 时最多返回 limit 个
+func ScanKeys(ctx context.Context, c redis.Cmdable, opts ScanOptions, limit int) ([]string, error) {
+	var keys []string
+	collect := func(key string) error {
+		keys = append(keys, key)
+		if limit > 0 && len(keys) >= limit {
+			return ErrStopScan
+		}
+		return nil
+	}
+	var err error
+	if cli, ok := c.(*redis.ClusterClient); ok {
+		err = ScanCluster(ctx, cli, opts, collect)
+	} else {
+		err = Scan(ctx, c, opts, collect)
+	}
+	return keys, err
+}
+
+// scanNode 在单个节点上迭代 SCAN 直到游标归零或 fn 返回错误
+func scanNode(ctx context.Context, c redis.Cmdable, opts ScanOptions, fn func(key string) error) error {
+	count := opts.Count
+	if count <= 0 {
+		count = defaultScanCount
+	}
+	var cursor uint64
+	for {
+		var cmd *redis.ScanCmd
+		if opts.Type != "" {
+			cmd = c.ScanType(ctx, cursor, opts.Match, count, opts.Type)
+		} else {
+			cmd = c.Scan(ctx, cursor, opts.Match, count)
+		}
+		keys, next, err := cmd.Result()
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := fn(k); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
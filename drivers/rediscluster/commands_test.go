@@ -0,0 +1,132 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestGetTouchAndPersist(t *testing.T) {
+	mr, cli := newTestClient(t)
+	ctx := context.Background()
+	_ = cli.Set(ctx, "k", "v", 10*time.Second).Err()
+
+	if v, err := GetTouch(ctx, cli, "k", time.Minute); err != nil || v != "v" {
+		t.Fatalf("GetTouch = %q, %v", v, err)
+	}
+	if ttl := mr.TTL("k"); ttl != time.Minute {
+		t.Errorf("ttl after GetTouch = %v, want 1m", ttl)
+	}
+	// ttl <= 0 只读取，不修改过期时间
+	if _, err := GetTouch(ctx, cli, "k", 0); err != nil {
+		t.Fatalf("GetTouch(0): %v", err)
+	}
+	if ttl := mr.TTL("k"); ttl != time.Minute {
+		t.Errorf("ttl after GetTouch(0) = %v, want 1m", ttl)
+	}
+	if v, err := GetPersist(ctx, cli, "k"); err != nil || v != "v" {
+		t.Fatalf("GetPersist = %q, %v", v, err)
+	}
+	if ttl := mr.TTL("k"); ttl != 0 {
+		t.Errorf("ttl after GetPersist = %v, want none", ttl)
+	}
+	if _, err := GetTouch(ctx, cli, "missing", time.Minute); !errors.Is(err, redis.Nil) {
+		t.Errorf("GetTouch missing err = %v, want redis.Nil", err)
+	}
+}
+
+func TestGetTouchJSONAndGetDelJSON(t *testing.T) {
+	mr, cli := newTestClient(t)
+	ctx := context.Background()
+	type token struct {
+		UID int64 `json:"uid"`
+	}
+	_ = cli.Set(ctx, "tok", `{"uid":7}`, 10*time.Second).Err()
+	_ = cli.Set(ctx, "bad", `{`, 0).Err()
+
+	got, err := GetTouchJSON[token](ctx, cli, "tok", time.Minute)
+	if err != nil || got.UID != 7 {
+		t.Fatalf("GetTouchJSON = %+v, %v", got, err)
+	}
+	if ttl := mr.TTL("tok"); ttl != time.Minute {
+		t.Errorf("ttl = %v, want 1m", ttl)
+	}
+
+	got, err = GetDelJSON[token](ctx, cli, "tok")
+	if err != nil || got.UID != 7 {
+		t.Fatalf("GetDelJSON = %+v, %v", got, err)
+	}
+	if mr.Exists("tok") {
+		t.Error("GetDelJSON did not delete the key")
+	}
+	if _, err := GetDelJSON[token](ctx, cli, "tok"); !errors.Is(err, redis.Nil) {
+		t.Errorf("second GetDelJSON err = %v, want redis.Nil", err)
+	}
+	if _, err := GetTouchJSON[token](ctx, cli, "bad", 0); err == nil || !strings.Contains(err.Error(), "decode bad") {
+		t.Errorf("GetTouchJSON invalid err = %v", err)
+	}
+}
+
+func TestSetKeepTTL(t *testing.T) {
+	mr, cli := newTestClient(t)
+	ctx := context.Background()
+	_ = cli.Set(ctx, "k", "v1", time.Minute).Err()
+
+	if err := SetKeepTTL(ctx, cli, "k", "v2"); err != nil {
+		t.Fatalf("SetKeepTTL: %v", err)
+	}
+	if v, _ := mr.Get("k"); v != "v2" {
+		t.Errorf("value = %q, want v2", v)
+	}
+	if ttl := mr.TTL("k"); ttl != time.Minute {
+		t.Errorf("ttl = %v, want 1m", ttl)
+	}
+}
+
+func TestObjectFreqs(t *testing.T) {
+	_, cli := newTestClient(t)
+	ctx := context.Background()
+
+	if m, err := ObjectFreqs(ctx, cli); err != nil || len(m) != 0 {
+		t.Fatalf("ObjectFreqs() = %v, %v", m, err)
+	}
+	// miniredis 不支持 OBJECT FREQ（相当于未开启 LFU 的服务端），错误需要返回给调用方
+	_ = cli.Set(ctx, "k", "v", 0).Err()
+	if _, err := ObjectFreqs(ctx, cli, "k"); err == nil {
+		t.Error("ObjectFreqs should surface the server error")
+	}
+}
+
+func TestScan(t *testing.T) {
+	_, cli := newTestClient(t)
+	ctx := context.Background()
+	for _, k := range []string{"user:1", "user:2", "user:3", "order:1"} {
+		_ = cli.Set(ctx, k, "v", 0).Err()
+	}
+	_ = cli.SAdd(ctx, "user:set", "a").Err()
+
+	keys, err := ScanKeys(ctx, cli, ScanOptions{Match: "user:*"}, 0)
+	sort.Strings(keys)
+	if err != nil || strings.Join(keys, ",") != "user:1,user:2,user:3,user:set" {
+		t.Fatalf("ScanKeys = %v, %v", keys, err)
+	}
+	keys, err = ScanKeys(ctx, cli, ScanOptions{Match: "user:*", Type: "set"}, 0)
+	if err != nil || len(keys) != 1 || keys[0] != "user:set" {
+		t.Fatalf("ScanKeys type=set = %v, %v", keys, err)
+	}
+	// limit 通过 ErrStopScan 提前结束，不作为错误返回
+	keys, err = ScanKeys(ctx, cli, ScanOptions{}, 2)
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("ScanKeys limit = %v, %v", keys, err)
+	}
+
+	boom := errors.New("boom")
+	if err := Scan(ctx, cli, ScanOptions{}, func(string) error { return boom }); !errors.Is(err, boom) {
+		t.Errorf("Scan err = %v, want callback error", err)
+	}
+}
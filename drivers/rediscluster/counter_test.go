@@ -0,0 +1,111 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCounter_IncrWithinExpiry(t *testing.T) {
+	mr, cli := newTestClient(t)
+	ctx := context.Background()
+	c := NewCounter(cli, "cnt:")
+
+	for want := int64(1); want <= 3; want++ {
+		n, err := c.IncrWithin(ctx, "api", time.Hour)
+		if err != nil || n != want {
+			t.Fatalf("IncrWithin = %d, %v, want %d", n, err, want)
+		}
+	}
+	keys := mr.Keys()
+	if len(keys) != 1 {
+		t.Fatalf("keys = %v, want one window key", keys)
+	}
+	// 过期时间对齐到窗口结束，不超过窗口长度
+	if ttl := mr.TTL(keys[0]); ttl <= 0 || ttl > time.Hour {
+		t.Fatalf("ttl = %v, want (0, 1h]", ttl)
+	}
+
+	// 窗口结束后计数清零
+	mr.FastForward(time.Hour)
+	if n, err := c.IncrWithin(ctx, "api", time.Hour); err != nil || n != 1 {
+		t.Fatalf("IncrWithin after expiry = %d, %v, want 1", n, err)
+	}
+
+	if _, err := c.IncrWithin(ctx, "api", 0); err == nil {
+		t.Error("IncrWithin with zero window should fail")
+	}
+}
+
+func TestCounter_Consume(t *testing.T) {
+	mr, cli := newTestClient(t)
+	ctx := context.Background()
+	c := NewCounter(cli, "quota:")
+	c.Location = time.UTC
+
+	res, err := c.ConsumeDaily(ctx, "sms", 5, 3)
+	if err != nil || !res.Allowed || res.Used != 3 || res.Remaining() != 2 {
+		t.Fatalf("first Consume = %+v, %v", res, err)
+	}
+	now := time.Now().UTC()
+	if want := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC); !res.ResetAt.Equal(want) {
+		t.Errorf("ResetAt = %v, want %v", res.ResetAt, want)
+	}
+
+	// 超出上限时拒绝，且本次消耗不计入
+	res, err = c.ConsumeDaily(ctx, "sms", 5, 3)
+	if !errors.Is(err, ErrQuotaExceeded) || res.Allowed || res.Used != 3 {
+		t.Fatalf("overflow Consume = %+v, %v, want ErrQuotaExceeded", res, err)
+	}
+	res, err = c.ConsumeDaily(ctx, "sms", 5, 2)
+	if err != nil || !res.Allowed || res.Used != 5 || res.Remaining() != 0 {
+		t.Fatalf("exact Consume = %+v, %v", res, err)
+	}
+	if _, err := c.ConsumeDaily(ctx, "sms", 5, 1); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Consume at limit err = %v, want ErrQuotaExceeded", err)
+	}
+
+	// 日、月周期相互独立
+	if used, err := c.Usage(ctx, "sms", QuotaMonthly); err != nil || used != 0 {
+		t.Fatalf("monthly Usage = %d, %v, want 0", used, err)
+	}
+
+	// 周期结束后用量清零
+	mr.FastForward(time.Until(res.ResetAt) + time.Second)
+	if used, err := c.Usage(ctx, "sms", QuotaDaily); err != nil || used != 0 {
+		t.Fatalf("Usage after reset = %d, %v, want 0", used, err)
+	}
+
+	for _, tc := range []struct{ limit, n int64 }{{5, 0}, {5, -1}, {-1, 1}} {
+		if _, err := c.ConsumeDaily(ctx, "sms", tc.limit, tc.n); err == nil || errors.Is(err, ErrQuotaExceeded) {
+			t.Errorf("Consume(limit=%d, n=%d) err = %v, want argument error", tc.limit, tc.n, err)
+		}
+	}
+}
+
+// TestCounter_HashTagKeys 带 {tag} 的 key 在各个周期落在同一 slot，便于集群中一起操作
+func TestCounter_HashTagKeys(t *testing.T) {
+	mr, cli := newTestClient(t)
+	ctx := context.Background()
+	c := NewCounter(cli, "quota:")
+
+	if _, err := c.ConsumeDaily(ctx, "{user1000}", 10, 1); err != nil {
+		t.Fatalf("ConsumeDaily: %v", err)
+	}
+	if _, err := c.ConsumeMonthly(ctx, "{user1000}", 10, 1); err != nil {
+		t.Fatalf("ConsumeMonthly: %v", err)
+	}
+	if _, err := c.IncrWithin(ctx, "{user1000}", time.Minute); err != nil {
+		t.Fatalf("IncrWithin: %v", err)
+	}
+	keys := mr.Keys()
+	if len(keys) != 3 {
+		t.Fatalf("keys = %v, want 3", keys)
+	}
+	for _, k := range keys {
+		if Slot(k) != Slot("{user1000}") {
+			t.Errorf("key %q in slot %d, want %d", k, Slot(k), Slot("{user1000}"))
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package rediscluster
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// FromConfig 根据 config 包中共用的 Redis 配置创建集群客户端，options 可在配置之上再做调整
+// 使用示例：
+//
+//	var cfg struct {
+//		Redis config.Redis `yaml:"redis"`
+//	}
+//	_ = config.LoadYAML("app.yaml", &cfg)
+//	cli, err := rediscluster.FromConfig(cfg.Redis)
+func FromConfig(cfg config.Redis, options ...Option) (*redis.ClusterClient, error) {
+	if len(cfg.Addrs) == 0 {
+		return nil, errors.New("rediscluster: addrs is required")
+	}
+	tlsConfig, err := cfg.TLS.Build()
+	if err != nil {
+		return nil, fmt.Errorf("rediscluster: %w", err)
+	}
+	var timeouts map[string]time.Duration
+	if len(cfg.CommandTimeouts) > 0 {
+		timeouts = make(map[string]time.Duration, len(cfg.CommandTimeouts))
+		for op, d := range cfg.CommandTimeouts {
+			timeouts[strings.ToLower(op)] = d
+		}
+	}
+	return New(Config{
+		Addrs:           append([]string(nil), cfg.Addrs...),
+		Username:        cfg.Username,
+		Password:        cfg.Password.Reveal(),
+		DialTimeout:     cfg.DialTimeout,
+		ReadTimeout:     cfg.ReadTimeout,
+		WriteTimeout:    cfg.WriteTimeout,
+		PoolSize:        cfg.PoolSize,
+		MinIdleConns:    cfg.MinIdleConns,
+		PingTimeout:     cfg.PingTimeout,
+		CommandTimeouts: timeouts,
+		TLSConfig:       tlsConfig,
+	}, options...)
+}
@@ -0,0 +1,108 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/qingfeng-studio/go-utils/config"
+)
+
+func TestFromConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Redis
+		want string
+	}{
+		{"no addrs", config.Redis{}, "addrs is required"},
+		{"unsupported tls version", config.Redis{Addrs: []string{"127.0.0.1:6379"}, TLS: config.TLS{Enabled: true, MinVersion: "1.1"}}, "min_version"},
+		{"missing ca file", config.Redis{Addrs: []string{"127.0.0.1:6379"}, TLS: config.TLS{Enabled: true, CAFile: "/nonexistent/ca.pem"}}, "rediscluster: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli, err := FromConfig(tt.cfg)
+			if err == nil {
+				_ = cli.Close()
+				t.Fatal("FromConfig succeeded, want error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromConfig_Options(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Redis
+		options []Option
+		check   func(t *testing.T, pool int, dial, read, write time.Duration, password string, tls bool)
+	}{
+		{
+			name: "zero values use go-redis defaults", // DialTimeout 由各节点客户端填充默认值
+			cfg:  config.Redis{Addrs: []string{"127.0.0.1:6379"}},
+			check: func(t *testing.T, pool int, dial, read, write time.Duration, password string, tls bool) {
+				if dial != 0 || read != 3*time.Second || write != read || pool <= 0 || password != "" || tls {
+					t.Errorf("defaults: pool=%d dial=%v read=%v write=%v password=%q tls=%v", pool, dial, read, write, password, tls)
+				}
+			},
+		},
+		{
+			name: "configured values",
+			cfg: config.Redis{
+				Addrs: []string{"127.0.0.1:6379"}, Password: "s3cret", PoolSize: 7,
+				DialTimeout: time.Second, ReadTimeout: 200 * time.Millisecond, WriteTimeout: 300 * time.Millisecond,
+				TLS: config.TLS{Enabled: true, InsecureSkipVerify: true},
+			},
+			check: func(t *testing.T, pool int, dial, read, write time.Duration, password string, tls bool) {
+				if pool != 7 || dial != time.Second || read != 200*time.Millisecond || write != 300*time.Millisecond || password != "s3cret" || !tls {
+					t.Errorf("configured: pool=%d dial=%v read=%v write=%v password=%q tls=%v", pool, dial, read, write, password, tls)
+				}
+			},
+		},
+		{
+			name:    "options override config",
+			cfg:     config.Redis{Addrs: []string{"127.0.0.1:6379"}, PoolSize: 7, ReadTimeout: time.Second},
+			options: []Option{WithPoolSize(3), WithTimeouts(0, 100*time.Millisecond, 0)},
+			check: func(t *testing.T, pool int, dial, read, write time.Duration, password string, tls bool) {
+				if pool != 3 || read != 100*time.Millisecond {
+					t.Errorf("override: pool=%d read=%v", pool, read)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli, err := FromConfig(tt.cfg, tt.options...)
+			if err != nil {
+				t.Fatalf("FromConfig: %v", err)
+			}
+			defer func() { _ = cli.Close() }()
+			o := cli.Options()
+			tt.check(t, o.PoolSize, o.DialTimeout, o.ReadTimeout, o.WriteTimeout, o.Password, o.TLSConfig != nil)
+		})
+	}
+}
+
+// TestFromConfig_CommandTimeouts 配置中的命令名不区分大小写
+func TestFromConfig_CommandTimeouts(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cfg := config.Redis{Addrs: []string{mr.Addr()}, CommandTimeouts: map[string]time.Duration{"BLPOP": 50 * time.Millisecond}}
+	cli, err := FromConfig(cfg)
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+	defer func() { _ = cli.Close() }()
+
+	if err := cli.BLPop(context.Background(), 0, "queue").Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("BLPop err = %v, want context.DeadlineExceeded", err)
+	}
+	if _, ok := cfg.CommandTimeouts["blpop"]; ok {
+		t.Error("FromConfig modified the shared config map")
+	}
+}
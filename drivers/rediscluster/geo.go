@@ -0,0 +1,134 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrGeoMemberNotFound 地理位置成员不存在
+var ErrGeoMemberNotFound = errors.New("rediscluster: geo member not found")
+
+// GeoPoint 地理位置点
+type GeoPoint struct {
+	Member    string
+	Longitude float64
+	Latitude  float64
+}
+
+// GeoResult 附近查询结果
+type GeoResult struct {
+	GeoPoint
+	Distance float64 // 与查询中心的距离，单位米
+}
+
+// GeoIndex 基于 GEO 命令的地理位置索引，距离统一使用米
+type GeoIndex struct {
+	c   redis.Cmdable
+	key string
+}
+
+// NewGeoIndex 创建地理位置索引，key 为底层有序集合的 key
+func NewGeoIndex(c redis.Cmdable, key string) *GeoIndex {
+	return &GeoIndex{c: c, key: key}
+}
+
+// Add 添加或更新位置
+func (g *GeoIndex) Add(ctx context.Context, points ...GeoPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	locs := make([]*redis.GeoLocation, len(points))
+	for i, p := range points {
+		locs[i] = &redis.GeoLocation{Name: p.Member, Longitude: p.Longitude, Latitude: p.Latitude}
+	}
+	return g.c.GeoAdd(ctx, g.key, locs...).Err()
+}
+
+// Remove 移除位置
+func (g *GeoIndex) Remove(ctx context.Context, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	args := make([]interface{}, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+	return g.c.ZRem(ctx, g.key, args...).Err()
+}
+
+// Position 返回成员的坐标，成员不存在时返回 ErrGeoMemberNotFound
+func (g *GeoIndex) Position(ctx context.Context, member string) (GeoPoint, error) {
+	pos, err := g.c.GeoPos(ctx, g.key, member).Result()
+	if err != nil {
+		return GeoPoint{}, err
+	}
+	if len(pos) == 0 || pos[0] == nil {
+		return GeoPoint{}, ErrGeoMemberNotFound
+	}
+	return GeoPoint{Member: member, Longitude: pos[0].Longitude, Latitude: pos[0].Latitude}, nil
+}
+
+// Distance 返回两个成员之间的距离（米），任一成员不存在时返回 ErrGeoMemberNotFound
+func (g *GeoIndex) Distance(ctx context.Context, a, b string) (float64, error) {
+	d, err := g.c.GeoDist(ctx, g.key, a, b, "m").Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, ErrGeoMemberNotFound
+	}
+	return d, err
+}
+
+// Nearby 查询坐标 radius 米范围内的成员，按距离由近到远排序，limit <= 0 表示不限制数量
+func (g *GeoIndex) Nearby(ctx context.Context, longitude, latitude, radius float64, limit int) ([]GeoResult, error) {
+	locs, err := g.c.GeoRadius(ctx, g.key, longitude, latitude, radiusQuery(radius, limit)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return geoResults(locs), nil
+}
+
+// NearbyMember 查询某成员 radius 米范围内的其它成员，结果不包含成员自身；成员不存在时返回 ErrGeoMemberNotFound
+func (g *GeoIndex) NearbyMember(ctx context.Context, member string, radius float64, limit int) ([]GeoResult, error) {
+	count := limit
+	if limit > 0 {
+		count++ // 多取一个用于剔除自身
+	}
+	locs, err := g.c.GeoRadiusByMember(ctx, g.key, member, radiusQuery(radius, count)).Result()
+	if err != nil {
+		// 成员不存在时服务端返回错误而非空结果
+		if _, perr := g.Position(ctx, member); errors.Is(perr, ErrGeoMemberNotFound) {
+			return nil, ErrGeoMemberNotFound
+		}
+		return nil, err
+	}
+	res := geoResults(locs)
+	out := res[:0]
+	for _, r := range res {
+		if r.Member != member {
+			out = append(out, r)
+		}
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// radiusQuery 构造按距离升序、带坐标与距离的半径查询（不写入结果，go-redis 使用只读的 GEORADIUS_RO）
+func radiusQuery(radius float64, limit int) *redis.GeoRadiusQuery {
+	q := &redis.GeoRadiusQuery{Radius: radius, Unit: "m", WithCoord: true, WithDist: true, Sort: "ASC"}
+	if limit > 0 {
+		q.Count = limit
+	}
+	return q
+}
+
+// geoResults 转换查询结果
+func geoResults(locs []redis.GeoLocation) []GeoResult {
+	out := make([]GeoResult, len(locs))
+	for i, l := range locs {
+		out[i] = GeoResult{GeoPoint: GeoPoint{Member: l.Name, Longitude: l.Longitude, Latitude: l.Latitude}, Distance: l.Dist}
+	}
+	return out
+}
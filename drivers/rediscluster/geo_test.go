@@ -0,0 +1,114 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+// 与 Redis GEO 文档一致的示例坐标
+var (
+	palermo = GeoPoint{Member: "Palermo", Longitude: 13.361389, Latitude: 38.115556}
+	catania = GeoPoint{Member: "Catania", Longitude: 15.087269, Latitude: 37.502669}
+)
+
+// approx 判断两个距离的相对误差在 0.5% 以内（GEO 坐标以 52 位 geohash 存储，有精度损失）
+func approx(got, want float64) bool {
+	return math.Abs(got-want) <= want*0.005
+}
+
+func newTestGeoIndex(t *testing.T) *GeoIndex {
+	t.Helper()
+	_, cli := newTestClient(t)
+	g := NewGeoIndex(cli, "cities")
+	if err := g.Add(context.Background(), palermo, catania); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	return g
+}
+
+func TestGeoIndex_PositionAndDistance(t *testing.T) {
+	g := newTestGeoIndex(t)
+	ctx := context.Background()
+
+	p, err := g.Position(ctx, "Palermo")
+	if err != nil || math.Abs(p.Longitude-palermo.Longitude) > 1e-4 || math.Abs(p.Latitude-palermo.Latitude) > 1e-4 {
+		t.Fatalf("Position = %+v, %v", p, err)
+	}
+	if d, err := g.Distance(ctx, "Palermo", "Catania"); err != nil || !approx(d, 166274) {
+		t.Fatalf("Distance = %v, %v, want ~166274m", d, err)
+	}
+
+	if _, err := g.Position(ctx, "Rome"); !errors.Is(err, ErrGeoMemberNotFound) {
+		t.Errorf("Position missing err = %v", err)
+	}
+	if _, err := g.Distance(ctx, "Palermo", "Rome"); !errors.Is(err, ErrGeoMemberNotFound) {
+		t.Errorf("Distance missing err = %v", err)
+	}
+
+	if err := g.Remove(ctx, "Catania"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := g.Position(ctx, "Catania"); !errors.Is(err, ErrGeoMemberNotFound) {
+		t.Errorf("Position after Remove err = %v", err)
+	}
+}
+
+func TestGeoIndex_Nearby(t *testing.T) {
+	g := newTestGeoIndex(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		radius float64
+		limit  int
+		want   []string
+		dists  []float64
+	}{
+		{"both in radius sorted by distance", 200000, 0, []string{"Catania", "Palermo"}, []float64{56441, 190442}},
+		{"limit", 200000, 1, []string{"Catania"}, []float64{56441}},
+		{"only the nearest in radius", 100000, 0, []string{"Catania"}, []float64{56441}},
+		{"none in radius", 10000, 0, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := g.Nearby(ctx, 15, 37, tt.radius, tt.limit)
+			if err != nil {
+				t.Fatalf("Nearby: %v", err)
+			}
+			if len(res) != len(tt.want) {
+				t.Fatalf("Nearby = %+v, want %v", res, tt.want)
+			}
+			for i, r := range res {
+				if r.Member != tt.want[i] || !approx(r.Distance, tt.dists[i]) || r.Longitude == 0 {
+					t.Errorf("result %d = %+v, want %s at ~%vm", i, r, tt.want[i], tt.dists[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGeoIndex_NearbyMember(t *testing.T) {
+	g := newTestGeoIndex(t)
+	ctx := context.Background()
+	if err := g.Add(ctx, GeoPoint{Member: "Agrigento", Longitude: 13.583333, Latitude: 37.316667}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	// 结果不包含成员自身，limit 在剔除自身后生效
+	res, err := g.NearbyMember(ctx, "Palermo", 200000, 1)
+	if err != nil || len(res) != 1 || res[0].Member != "Agrigento" {
+		t.Fatalf("NearbyMember limit 1 = %+v, %v", res, err)
+	}
+	res, err = g.NearbyMember(ctx, "Palermo", 200000, 0)
+	if err != nil || len(res) != 2 || res[0].Member != "Agrigento" || res[1].Member != "Catania" {
+		t.Fatalf("NearbyMember = %+v, %v", res, err)
+	}
+	if res, err := g.NearbyMember(ctx, "Palermo", 1000, 0); err != nil || len(res) != 0 {
+		t.Fatalf("NearbyMember small radius = %+v, %v", res, err)
+	}
+	if _, err := g.NearbyMember(ctx, "Rome", 200000, 0); !errors.Is(err, ErrGeoMemberNotFound) {
+		t.Errorf("NearbyMember missing err = %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package rediscluster
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrInProgress 相同幂等键的请求正在处理中
+	ErrInProgress = errors.New("rediscluster: idempotent operation in progress")
+	// ErrLeaseLost 处理中标记已过期并被其它请求占用（或已被释放），本次结果不能再写入
+	ErrLeaseLost = errors.New("rediscluster: idempotency lease lost")
+)
+
+// 幂等键的值："P:" + 本次占用的随机令牌，或 "D:" + 结果
+const (
+	idemProcessingPrefix = "P:"
+	idemDonePrefix       = "D:"
+)
+
+// completeScript 仅在处理中标记仍属于本次占用时写入结果
+var completeScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+else
+	redis.call("SET", KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// releaseScript 仅在处理中标记仍属于本次占用时删除，避免误删其它请求的标记或已完成的结果
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Idempotency 幂等令牌存储（基于 SET NX），并保存处理结果以便重复请求直接返回
+// 实用场景: webhook 回调、支付回调等需要“恰好一次”处理的场景
+type Idempotency struct {
+	c      redis.Cmdable
+	prefix string
+}
+
+// NewIdempotency 创建幂等存储，prefix 为 key 前缀（如 "idem:"）
+func NewIdempotency(c redis.Cmdable, prefix string) *Idempotency {
+	return &Idempotency{c: c, prefix: prefix}
+}
+
+// Check 尝试占用幂等键：
+//   - 首次请求：返回 done=false 与本次占用的令牌，调用方执行业务后用该令牌调用 Complete（失败时调用 Release）
+//   - 已处理完成：返回 done=true 与之前保存的结果
+//   - 正在处理中：返回 ErrInProgress
+//
+// ttl 同时作为处理中标记的超时时间，防止进程崩溃后永久占用；业务耗时超过 ttl 时标记可能被其它请求接管，
+// 此时 Complete 返回 ErrLeaseLost
+func (i *Idempotency) Check(ctx context.Context, key string, ttl time.Duration) (done bool, token string, result []byte, err error) {
+	k := i.prefix + key
+	token, err = newIdemToken()
+	if err != nil {
+		return false, "", nil, err
+	}
+	ok, err := i.c.SetNX(ctx, k, idemProcessingPrefix+token, ttl).Result()
+	if err != nil {
+		return false, "", nil, err
+	}
+	if ok {
+		return false, token, nil, nil
+	}
+
+	val, err := i.c.Get(ctx, k).Result()
+	if errors.Is(err, redis.Nil) {
+		// 标记恰好过期，重新尝试占用
+		return i.Check(ctx, key, ttl)
+	}
+	if err != nil {
+		return false, "", nil, err
+	}
+	if strings.HasPrefix(val, idemDonePrefix) {
+		return true, "", []byte(strings.TrimPrefix(val, idemDonePrefix)), nil
+	}
+	return false, "", nil, ErrInProgress
+}
+
+// Complete 标记处理完成并保存结果，token 为 Check 返回的令牌，ttl 为结果保留时间；
+// ttl <= 0 表示永久保留；标记已不属于本次占用时不写入并返回 ErrLeaseLost
+func (i *Idempotency) Complete(ctx context.Context, key, token string, result []byte, ttl time.Duration) error {
+	ok, err := completeScript.Run(ctx, i.c, []string{i.prefix + key},
+		idemProcessingPrefix+token, idemDonePrefix+string(result), ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if ok == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Release 处理失败时释放处理中标记，允许后续重试；token 为 Check 返回的令牌，标记已被其它请求占用时不做任何事
+func (i *Idempotency) Release(ctx context.Context, key, token string) error {
+	return releaseScript.Run(ctx, i.c, []string{i.prefix + key}, idemProcessingPrefix+token).Err()
+}
+
+// Do 组合 Check/Complete/Release：首次请求执行 fn 并保存结果，重复请求直接返回已保存的结果
+// replayed 表示结果来自之前的处理
+func (i *Idempotency) Do(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) ([]byte, error)) (result []byte, replayed bool, err error) {
+	done, token, result, err := i.Check(ctx, key, ttl)
+	if err != nil || done {
+		return result, done, err
+	}
+	result, err = fn(ctx)
+	if err != nil {
+		_ = i.Release(ctx, key, token)
+		return nil, false, err
+	}
+	return result, false, i.Complete(ctx, key, token, result, ttl)
+}
+
+// newIdemToken 生成 128 位随机令牌，标识一次占用
+func newIdemToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
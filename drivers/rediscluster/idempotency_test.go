@@ -0,0 +1,101 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIdempotency_FirstCallAndReplay(t *testing.T) {
+	_, cli := newTestClient(t)
+	ctx := context.Background()
+	idem := NewIdempotency(cli, "idem:")
+
+	calls := 0
+	fn := func(context.Context) ([]byte, error) {
+		calls++
+		return []byte("ok"), nil
+	}
+	result, replayed, err := idem.Do(ctx, "pay-1", time.Minute, fn)
+	if err != nil || replayed || string(result) != "ok" {
+		t.Fatalf("first Do = %q, %v, %v", result, replayed, err)
+	}
+	result, replayed, err = idem.Do(ctx, "pay-1", time.Minute, fn)
+	if err != nil || !replayed || string(result) != "ok" {
+		t.Fatalf("second Do = %q, %v, %v", result, replayed, err)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+}
+
+func TestIdempotency_InProgress(t *testing.T) {
+	_, cli := newTestClient(t)
+	ctx := context.Background()
+	idem := NewIdempotency(cli, "idem:")
+
+	done, token, _, err := idem.Check(ctx, "pay-1", time.Minute)
+	if err != nil || done || token == "" {
+		t.Fatalf("Check = %v, %q, %v", done, token, err)
+	}
+	if _, _, _, err := idem.Check(ctx, "pay-1", time.Minute); !errors.Is(err, ErrInProgress) {
+		t.Fatalf("second Check err = %v, want ErrInProgress", err)
+	}
+
+	// 失败后释放，允许重试
+	if err := idem.Release(ctx, "pay-1", token); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if done, token, _, err := idem.Check(ctx, "pay-1", time.Minute); err != nil || done || token == "" {
+		t.Fatalf("Check after release = %v, %q, %v", done, token, err)
+	}
+}
+
+// TestIdempotency_TTLTakeover A 的标记过期后被 B 接管，A 的 Release/Complete 都不能影响 B
+func TestIdempotency_TTLTakeover(t *testing.T) {
+	mr, cli := newTestClient(t)
+	ctx := context.Background()
+	idem := NewIdempotency(cli, "idem:")
+
+	_, tokenA, _, err := idem.Check(ctx, "pay-1", time.Second)
+	if err != nil {
+		t.Fatalf("Check A: %v", err)
+	}
+	mr.FastForward(2 * time.Second)
+	_, tokenB, _, err := idem.Check(ctx, "pay-1", time.Minute)
+	if err != nil || tokenB == "" || tokenB == tokenA {
+		t.Fatalf("Check B = %q, %v", tokenB, err)
+	}
+
+	// A 的过期释放不能删除 B 的标记
+	if err := idem.Release(ctx, "pay-1", tokenA); err != nil {
+		t.Fatalf("stale Release: %v", err)
+	}
+	if _, _, _, err := idem.Check(ctx, "pay-1", time.Minute); !errors.Is(err, ErrInProgress) {
+		t.Fatalf("Check after stale release err = %v, want ErrInProgress", err)
+	}
+
+	// A 的过期完成不能覆盖 B 的标记
+	if err := idem.Complete(ctx, "pay-1", tokenA, []byte("a"), time.Minute); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("stale Complete err = %v, want ErrLeaseLost", err)
+	}
+	if err := idem.Complete(ctx, "pay-1", tokenB, []byte("b"), time.Minute); err != nil {
+		t.Fatalf("Complete B: %v", err)
+	}
+	done, _, result, err := idem.Check(ctx, "pay-1", time.Minute)
+	if err != nil || !done || string(result) != "b" {
+		t.Fatalf("Check after complete = %v, %q, %v", done, result, err)
+	}
+	if ttl := mr.TTL("idem:pay-1"); ttl != time.Minute {
+		t.Errorf("result ttl = %v, want 1m", ttl)
+	}
+
+	// 已完成的结果不会被迟到的 Release 删除
+	if err := idem.Release(ctx, "pay-1", tokenB); err != nil {
+		t.Fatalf("Release after complete: %v", err)
+	}
+	if !mr.Exists("idem:pay-1") {
+		t.Error("completed result deleted by Release")
+	}
+}
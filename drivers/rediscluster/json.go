@@ -0,0 +1,158 @@
+package rediscluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// GetJSON 读取 key 并按 JSON 解码为 T，key 不存在时返回 redis.Nil
+// 参数 c 可以是 *redis.ClusterClient，也可以是 *redis.Client 等任意 redis.Cmdable
+func GetJSON[T any](ctx context.Context, c redis.Cmdable, key string) (T, error) {
+	var out T
+	data, err := c.Get(ctx, key).Bytes()
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("decode %s: %w", key, err)
+	}
+	return out, nil
+}
+
+// SetJSON 将 v 按 JSON 编码后写入 key，ttl 为 0 表示不过期
+func SetJSON(ctx context.Context, c redis.Cmdable, key string, v interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", key, err)
+	}
+	return c.Set(ctx, key, data, ttl).Err()
+}
+
+// MGetJSON 批量读取多个 key，返回存在的 key -> 值
+// 集群模式下 MGET 不能跨 slot，这里改用 pipeline 逐个 GET，由客户端按节点分发
+func MGetJSON[T any](ctx context.Context, c redis.Cmdable, keys ...string) (map[string]T, error) {
+	out := make(map[string]T, len(keys))
+	if len(keys) == 0 {
+		return out, nil
+	}
+	cmds := make([]*redis.StringCmd, len(keys))
+	_, err := c.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for i, k := range keys {
+			cmds[i] = p.Get(ctx, k)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	for i, cmd := range cmds {
+		data, err := cmd.Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var v T
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", keys[i], err)
+		}
+		out[keys[i]] = v
+	}
+	return out, nil
+}
+
+// loadTimeout GetOrLoadJSON 中 loader 与回写缓存的超时；loader 与调用方的取消解耦，由多个等待者共享
+const loadTimeout = 30 * time.Second
+
+// GetOrLoadJSON 旁路缓存（cache-aside）：命中直接返回，未命中调用 loader 加载并回写缓存
+// 同一进程内相同 key（且相同 T）的并发加载会被合并，避免缓存击穿；回写失败不影响返回结果。
+// loader 在脱离调用方取消的 context 中执行（保留 context 中的值，超时 30 秒），
+// 某个调用方取消只会让它自己提前返回，不会让共享同一次加载的其它调用方失败
+// 使用示例：
+//
+//	user, err := rediscluster.GetOrLoadJSON(ctx, cli, "user:1", 10*time.Minute, func(ctx context.Context) (User, error) {
+//		return repo.FindUser(ctx, 1)
+//	})
+func GetOrLoadJSON[T any](ctx context.Context, c redis.Cmdable, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	v, err := GetJSON[T](ctx, c, key)
+	if err == nil || !errors.Is(err, redis.Nil) {
+		return v, err
+	}
+
+	// 不同调用点可能用不同的 T 读取同一个 key，类型也作为合并键的一部分
+	groupKey := key + "\x00" + reflect.TypeFor[T]().String()
+	res, err := loadGroup.do(ctx, groupKey, func() (interface{}, error) {
+		lctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+		loaded, err := loader(lctx)
+		if err != nil {
+			return loaded, err
+		}
+		_ = SetJSON(lctx, c, key, loaded, ttl)
+		return loaded, nil
+	})
+	var zero T
+	if err != nil {
+		return zero, err
+	}
+	out, ok := res.(T)
+	if !ok {
+		return zero, fmt.Errorf("load %s: unexpected result type %T", key, res)
+	}
+	return out, nil
+}
+
+// loadGroup 合并相同 key 的并发加载
+var loadGroup = &callGroup{calls: make(map[string]*call)}
+
+// call 一次进行中的加载
+type call struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+// callGroup 简化版 singleflight
+type callGroup struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// do 相同 key 同时只执行一次 fn，所有调用方等待并共享结果；fn 在独立协程中执行，
+// 调用方的 ctx 取消时该调用方立即返回 ctx.Err()，fn 继续执行并把结果交给其它调用方
+func (g *callGroup) do(ctx context.Context, key string, fn func() (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	c, ok := g.calls[key]
+	if !ok {
+		c = &call{done: make(chan struct{})}
+		g.calls[key] = c
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					c.err = fmt.Errorf("load %s: panic: %v", key, r)
+				}
+				g.mu.Lock()
+				delete(g.calls, key)
+				g.mu.Unlock()
+				close(c.done)
+			}()
+			c.val, c.err = fn()
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
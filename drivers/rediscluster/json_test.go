@@ -0,0 +1,156 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type jsonUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestJSON(t *testing.T) {
+	mr, cli := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := GetJSON[jsonUser](ctx, cli, "user:1"); !errors.Is(err, redis.Nil) {
+		t.Fatalf("expected redis.Nil, got %v", err)
+	}
+	if err := SetJSON(ctx, cli, "user:1", jsonUser{ID: 1, Name: "a"}, time.Minute); err != nil {
+		t.Fatalf("SetJSON: %v", err)
+	}
+	if ttl := mr.TTL("user:1"); ttl != time.Minute {
+		t.Errorf("ttl = %v", ttl)
+	}
+	u, err := GetJSON[jsonUser](ctx, cli, "user:1")
+	if err != nil || u.Name != "a" {
+		t.Fatalf("GetJSON = %+v, %v", u, err)
+	}
+	got, err := MGetJSON[jsonUser](ctx, cli, "user:1", "user:2")
+	if err != nil || len(got) != 1 || got["user:1"].ID != 1 {
+		t.Fatalf("MGetJSON = %v, %v", got, err)
+	}
+}
+
+func TestGetOrLoadJSON(t *testing.T) {
+	_, cli := newTestClient(t)
+	ctx := context.Background()
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context) (jsonUser, error) {
+		loads.Add(1)
+		<-release
+		return jsonUser{ID: 7, Name: "loaded"}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]jsonUser, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			u, err := GetOrLoadJSON(ctx, cli, "user:7", time.Minute, loader)
+			if err != nil {
+				t.Errorf("GetOrLoadJSON: %v", err)
+			}
+			results[i] = u
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loader called %d times, want 1", n)
+	}
+	for _, u := range results {
+		if u.Name != "loaded" {
+			t.Errorf("unexpected result %+v", u)
+		}
+	}
+	if u, err := GetJSON[jsonUser](ctx, cli, "user:7"); err != nil || u.ID != 7 {
+		t.Errorf("value not written back: %+v %v", u, err)
+	}
+}
+
+// TestGetOrLoadJSON_DifferentTypes 同一个 key 用不同类型加载不能互相影响
+func TestGetOrLoadJSON_DifferentTypes(t *testing.T) {
+	_, cli := newTestClient(t)
+	ctx := context.Background()
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		u, err := GetOrLoadJSON(ctx, cli, "shared", time.Minute, func(context.Context) (jsonUser, error) {
+			<-release
+			return jsonUser{ID: 1}, nil
+		})
+		if err != nil || u.ID != 1 {
+			t.Errorf("struct load = %+v, %v", u, err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		m, err := GetOrLoadJSON(ctx, cli, "shared", time.Minute, func(context.Context) (map[string]int, error) {
+			<-release
+			return map[string]int{"id": 1}, nil
+		})
+		if err != nil || m["id"] != 1 {
+			t.Errorf("map load = %v, %v", m, err)
+		}
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+}
+
+// TestGetOrLoadJSON_CallerCanceled 发起加载的调用方取消后，其它等待者仍拿到结果
+func TestGetOrLoadJSON_CallerCanceled(t *testing.T) {
+	_, cli := newTestClient(t)
+	release := make(chan struct{})
+	var loaderCtxErr atomic.Value
+	loader := func(ctx context.Context) (jsonUser, error) {
+		<-release
+		loaderCtxErr.Store(ctx.Err() != nil)
+		return jsonUser{ID: 9}, nil
+	}
+
+	first, cancel := context.WithCancel(context.Background())
+	firstDone := make(chan error, 1)
+	go func() {
+		_, err := GetOrLoadJSON(first, cli, "user:9", time.Minute, loader)
+		firstDone <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	secondDone := make(chan jsonUser, 1)
+	go func() {
+		u, err := GetOrLoadJSON(context.Background(), cli, "user:9", time.Minute, loader)
+		if err != nil {
+			t.Errorf("second caller: %v", err)
+		}
+		secondDone <- u
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-firstDone; !errors.Is(err, context.Canceled) {
+		t.Errorf("first caller err = %v, want context.Canceled", err)
+	}
+	close(release)
+	if u := <-secondDone; u.ID != 9 {
+		t.Errorf("second caller got %+v", u)
+	}
+	if canceled, _ := loaderCtxErr.Load().(bool); canceled {
+		t.Error("loader context should not be canceled by the first caller")
+	}
+}
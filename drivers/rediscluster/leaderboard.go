@@ -0,0 +1,155 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNotRanked 成员不在排行榜中
+var ErrNotRanked = errors.New("rediscluster: member not ranked")
+
+// RankEntry 排行榜条目
+type RankEntry struct {
+	Member string
+	Score  float64
+	Rank   int64 // 名次，从 1 开始
+}
+
+// Leaderboard 基于有序集合的排行榜，默认分数越高名次越靠前；
+// 同分成员名次不同，按有序集合的语义排列：升序榜为成员名字典序，降序榜为逆字典序；
+// 需要先到先得时可把时间编码进分数的小数部分
+type Leaderboard struct {
+	c         redis.Cmdable
+	key       string
+	Ascending bool // 为 true 时分数越低名次越靠前，如耗时榜
+}
+
+// NewLeaderboard 创建排行榜，key 为有序集合的 key
+func NewLeaderboard(c redis.Cmdable, key string) *Leaderboard {
+	return &Leaderboard{c: c, key: key}
+}
+
+// Set 设置成员分数，覆盖原有分数
+func (l *Leaderboard) Set(ctx context.Context, member string, score float64) error {
+	return l.c.ZAdd(ctx, l.key, redis.Z{Score: score, Member: member}).Err()
+}
+
+// SetBest 仅当新分数优于原分数（或成员不存在）时更新，返回是否更新，适合记录历史最好成绩（需要 Redis 6.2+）
+func (l *Leaderboard) SetBest(ctx context.Context, member string, score float64) (bool, error) {
+	args := redis.ZAddArgs{Ch: true, Members: []redis.Z{{Score: score, Member: member}}}
+	if l.Ascending {
+		args.LT = true
+	} else {
+		args.GT = true
+	}
+	n, err := l.c.ZAddArgs(ctx, l.key, args).Result()
+	return n > 0, err
+}
+
+// Incr 为成员增加分数（可为负），成员不存在时从 0 开始，返回新分数
+func (l *Leaderboard) Incr(ctx context.Context, member string, delta float64) (float64, error) {
+	return l.c.ZIncrBy(ctx, l.key, delta, member).Result()
+}
+
+// Remove 移除成员
+func (l *Leaderboard) Remove(ctx context.Context, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	args := make([]interface{}, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+	return l.c.ZRem(ctx, l.key, args...).Err()
+}
+
+// Count 返回上榜成员数
+func (l *Leaderboard) Count(ctx context.Context) (int64, error) {
+	return l.c.ZCard(ctx, l.key).Result()
+}
+
+// Rank 返回成员的名次与分数，成员不存在时返回 ErrNotRanked
+func (l *Leaderboard) Rank(ctx context.Context, member string) (RankEntry, error) {
+	var rank *redis.IntCmd
+	var score *redis.FloatCmd
+	_, err := l.c.Pipelined(ctx, func(p redis.Pipeliner) error {
+		if l.Ascending {
+			rank = p.ZRank(ctx, l.key, member)
+		} else {
+			rank = p.ZRevRank(ctx, l.key, member)
+		}
+		score = p.ZScore(ctx, l.key, member)
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		return RankEntry{}, ErrNotRanked
+	}
+	if err != nil {
+		return RankEntry{}, err
+	}
+	return RankEntry{Member: member, Score: score.Val(), Rank: rank.Val() + 1}, nil
+}
+
+// Top 分页读取排行榜，page 从 1 开始
+func (l *Leaderboard) Top(ctx context.Context, page, size int) ([]RankEntry, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size <= 0 {
+		return nil, nil
+	}
+	start := int64(page-1) * int64(size)
+	return l.rangeByRank(ctx, start, start+int64(size)-1)
+}
+
+// Around 返回成员及其前后各 n 名，用于“我的排名”附近的榜单；成员不存在时返回 ErrNotRanked
+// 名次与区间分两次读取，并发更新时结果可能有轻微偏差
+func (l *Leaderboard) Around(ctx context.Context, member string, n int) ([]RankEntry, error) {
+	var (
+		rank int64
+		err  error
+	)
+	if l.Ascending {
+		rank, err = l.c.ZRank(ctx, l.key, member).Result()
+	} else {
+		rank, err = l.c.ZRevRank(ctx, l.key, member).Result()
+	}
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotRanked
+	}
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		n = 0
+	}
+	start := rank - int64(n)
+	if start < 0 {
+		start = 0
+	}
+	return l.rangeByRank(ctx, start, rank+int64(n))
+}
+
+// rangeByRank 读取 [start, stop] 名次区间（从 0 开始）
+func (l *Leaderboard) rangeByRank(ctx context.Context, start, stop int64) ([]RankEntry, error) {
+	var (
+		zs  []redis.Z
+		err error
+	)
+	if l.Ascending {
+		zs, err = l.c.ZRangeWithScores(ctx, l.key, start, stop).Result()
+	} else {
+		zs, err = l.c.ZRevRangeWithScores(ctx, l.key, start, stop).Result()
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]RankEntry, len(zs))
+	for i, z := range zs {
+		m, _ := z.Member.(string)
+		out[i] = RankEntry{Member: m, Score: z.Score, Rank: start + int64(i) + 1}
+	}
+	return out, nil
+}
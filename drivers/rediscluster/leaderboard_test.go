@@ -0,0 +1,135 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// members 提取条目的成员名
+func members(entries []RankEntry) []string {
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.Member
+	}
+	return out
+}
+
+func newTestLeaderboard(t *testing.T, ascending bool, scores map[string]float64) *Leaderboard {
+	t.Helper()
+	_, cli := newTestClient(t)
+	l := NewLeaderboard(cli, "lb")
+	l.Ascending = ascending
+	for m, s := range scores {
+		if err := l.Set(context.Background(), m, s); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+	return l
+}
+
+// TestLeaderboard_Ties 同分成员按有序集合语义排序：升序榜为字典序，降序榜为逆字典序
+func TestLeaderboard_Ties(t *testing.T) {
+	scores := map[string]float64{"alice": 90, "bob": 90, "carol": 100, "dave": 80}
+	tests := []struct {
+		name      string
+		ascending bool
+		want      []string
+	}{
+		{"descending", false, []string{"carol", "bob", "alice", "dave"}},
+		{"ascending", true, []string{"dave", "alice", "bob", "carol"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			l := newTestLeaderboard(t, tt.ascending, scores)
+			top, err := l.Top(ctx, 1, 10)
+			if err != nil || !slices.Equal(members(top), tt.want) {
+				t.Fatalf("Top = %v, %v, want %v", members(top), err, tt.want)
+			}
+			// Rank 与 Top 的名次一致，同分成员名次不同
+			for i, m := range tt.want {
+				e, err := l.Rank(ctx, m)
+				if err != nil || e.Rank != int64(i+1) || e.Score != scores[m] || top[i].Rank != int64(i+1) {
+					t.Errorf("Rank(%s) = %+v, %v, want rank %d", m, e, err, i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestLeaderboard_RankAndPaging(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLeaderboard(t, false, map[string]float64{"a": 5, "b": 4, "c": 3, "d": 2, "e": 1})
+
+	if _, err := l.Rank(ctx, "zed"); !errors.Is(err, ErrNotRanked) {
+		t.Errorf("Rank missing err = %v", err)
+	}
+	page, err := l.Top(ctx, 2, 2)
+	if err != nil || !slices.Equal(members(page), []string{"c", "d"}) || page[0].Rank != 3 {
+		t.Fatalf("Top page 2 = %+v, %v", page, err)
+	}
+	if page, err := l.Top(ctx, 1, 0); err != nil || page != nil {
+		t.Errorf("Top size 0 = %v, %v", page, err)
+	}
+
+	around, err := l.Around(ctx, "b", 1)
+	if err != nil || !slices.Equal(members(around), []string{"a", "b", "c"}) || around[1].Rank != 2 {
+		t.Fatalf("Around = %+v, %v", around, err)
+	}
+	// 榜首附近不越界
+	if around, err := l.Around(ctx, "a", 2); err != nil || !slices.Equal(members(around), []string{"a", "b", "c"}) {
+		t.Errorf("Around top = %v, %v", members(around), err)
+	}
+	if _, err := l.Around(ctx, "zed", 1); !errors.Is(err, ErrNotRanked) {
+		t.Errorf("Around missing err = %v", err)
+	}
+
+	if s, err := l.Incr(ctx, "e", 10); err != nil || s != 11 {
+		t.Fatalf("Incr = %v, %v", s, err)
+	}
+	if e, _ := l.Rank(ctx, "e"); e.Rank != 1 {
+		t.Errorf("rank after Incr = %d, want 1", e.Rank)
+	}
+	if err := l.Remove(ctx, "e"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if n, err := l.Count(ctx); err != nil || n != 4 {
+		t.Errorf("Count = %d, %v", n, err)
+	}
+}
+
+func TestLeaderboard_SetBest(t *testing.T) {
+	tests := []struct {
+		name      string
+		ascending bool
+		better    float64
+		worse     float64
+	}{
+		{"descending keeps the highest", false, 120, 80},
+		{"ascending keeps the lowest", true, 80, 120},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			l := newTestLeaderboard(t, tt.ascending, nil)
+			if ok, err := l.SetBest(ctx, "alice", 100); err != nil || !ok {
+				t.Fatalf("SetBest new = %v, %v", ok, err)
+			}
+			if ok, err := l.SetBest(ctx, "alice", tt.worse); err != nil || ok {
+				t.Fatalf("SetBest worse = %v, %v", ok, err)
+			}
+			// 同分不算更新
+			if ok, err := l.SetBest(ctx, "alice", 100); err != nil || ok {
+				t.Fatalf("SetBest equal = %v, %v", ok, err)
+			}
+			if ok, err := l.SetBest(ctx, "alice", tt.better); err != nil || !ok {
+				t.Fatalf("SetBest better = %v, %v", ok, err)
+			}
+			if e, _ := l.Rank(ctx, "alice"); e.Score != tt.better {
+				t.Errorf("score = %v, want %v", e.Score, tt.better)
+			}
+		})
+	}
+}
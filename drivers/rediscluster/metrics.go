@@ -0,0 +1,91 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/qingfeng-studio/go-utils/utils/metrics"
+)
+
+// metricsSubsystem rediscluster 指标的前缀
+const metricsSubsystem = "redis"
+
+// WithMetrics 将命令执行次数、耗时与连接池状态记录到 r（nil 时为 metrics.Default）：
+//   - redis_client_requests_total{client,operation,result}
+//   - redis_client_request_duration_seconds{client,operation}
+//   - redis_pool_*{pool}
+//
+// client 与 pool 标签为 name（为空时为 default），operation 为小写命令名，pipeline/事务整体记为 pipeline；
+// redis.Nil 不视为错误
+func WithMetrics(r *metrics.Registry, name string) Option {
+	return func(c *Config) {
+		if r == nil {
+			r = metrics.Default
+		}
+		c.Metrics = r
+		c.MetricsName = name
+	}
+}
+
+// NewMetricsHook 返回记录命令次数与耗时的 Hook，New 在配置了 Metrics 时会自动添加
+func NewMetricsHook(m *metrics.ClientMetrics, name string) redis.Hook {
+	return &metricsHook{m: m, name: name}
+}
+
+// metricsHook 记录命令指标
+type metricsHook struct {
+	m    *metrics.ClientMetrics
+	name string
+}
+
+// DialHook 实现 redis.Hook
+func (h *metricsHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+// ProcessHook 实现 redis.Hook
+func (h *metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.observe(cmd.Name(), err, time.Since(start))
+		return err
+	}
+}
+
+// ProcessPipelineHook 实现 redis.Hook
+func (h *metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		h.observe("pipeline", err, time.Since(start))
+		return err
+	}
+}
+
+// observe 记录一次调用，redis.Nil 表示键不存在，按成功计
+func (h *metricsHook) observe(op string, err error, d time.Duration) {
+	if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	h.m.Observe(h.name, op, metrics.Result(err), d)
+}
+
+// poolStater 可读取连接池状态的客户端，*redis.Client 与 *redis.ClusterClient 均满足
+type poolStater interface {
+	PoolStats() *redis.PoolStats
+}
+
+// registerPoolStats 注册客户端的连接池状态
+func registerPoolStats(r *metrics.Registry, name string, c poolStater) error {
+	return metrics.RegisterPoolStats(r, metricsSubsystem, name, func() metrics.PoolStats {
+		s := c.PoolStats()
+		return metrics.PoolStats{
+			Open:     int(s.TotalConns),
+			InUse:    int(s.TotalConns) - int(s.IdleConns),
+			Idle:     int(s.IdleConns),
+			Timeouts: int64(s.Timeouts),
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package rediscluster
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/qingfeng-studio/go-utils/utils/metrics"
+)
+
+// newTestClient 基于 miniredis 的单机客户端
+func newTestClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	cli := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = cli.Close() })
+	return mr, cli
+}
+
+func TestMetricsHook(t *testing.T) {
+	mr, cli := newTestClient(t)
+	ctx := context.Background()
+
+	r := metrics.NewRegistry()
+	cli.AddHook(NewMetricsHook(metrics.Must(metrics.NewClientMetrics(r, metricsSubsystem, nil)), "cache"))
+	if err := registerPoolStats(r, "cache", cli); err != nil {
+		t.Fatalf("registerPoolStats: %v", err)
+	}
+
+	_ = cli.Set(ctx, "k", "v", 0).Err()
+	if err := cli.Get(ctx, "missing").Err(); err != redis.Nil {
+		t.Fatalf("Get missing: %v", err)
+	}
+	mr.SetError("boom")
+	_ = cli.Get(ctx, "k").Err()
+	mr.SetError("")
+	_, _ = cli.Pipelined(ctx, func(p redis.Pipeliner) error {
+		p.Incr(ctx, "n")
+		p.Incr(ctx, "n")
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	out := string(body)
+	for _, want := range []string{
+		`redis_client_requests_total{client="cache",operation="set",result="ok"} 1`,
+		`redis_client_requests_total{client="cache",operation="get",result="ok"} 1`,
+		`redis_client_requests_total{client="cache",operation="get",result="error"} 1`,
+		`redis_client_requests_total{client="cache",operation="pipeline",result="ok"} 1`,
+		`redis_pool_open_connections{pool="cache"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/utils/backoff"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// defaultOptimisticAttempts Optimistic 默认的最大尝试次数
+	defaultOptimisticAttempts = 10
+	// maxOptimisticBackoff 冲突重试间隔的上限
+	maxOptimisticBackoff = 100 * time.Millisecond
+)
+
+var (
+	// ErrCrossSlot 事务涉及的 key 不在同一个 hash slot，集群模式下无法 WATCH/MULTI
+	ErrCrossSlot = errors.New("rediscluster: keys in transaction must hash to the same slot")
+	// ErrTxConflict 重试次数用完后 WATCH 的 key 仍被其它客户端修改
+	ErrTxConflict = errors.New("rediscluster: optimistic transaction conflict")
+)
+
+// Watcher 支持 WATCH 事务的客户端，*redis.Client、*redis.ClusterClient 均满足
+type Watcher interface {
+	Watch(ctx context.Context, fn func(*redis.Tx) error, keys ...string) error
+}
+
+// optimisticOptions 乐观事务选项
+type optimisticOptions struct {
+	attempts int
+}
+
+// OptimisticOption 乐观事务的函数式选项
+type OptimisticOption func(*optimisticOptions)
+
+// WithMaxAttempts 设置冲突时的最大尝试次数（含首次），默认 10
+func WithMaxAttempts(n int) OptimisticOption {
+	return func(o *optimisticOptions) { o.attempts = n }
+}
+
+// Optimistic 以 WATCH/MULTI/EXEC 执行乐观事务，被其它客户端修改导致 EXEC 失败时自动重试整个 fn
+// fn 通过 tx 读取（立即执行，读取的 key 应在 keys 中），写命令加入 pipe，fn 返回后在 MULTI/EXEC 中一并提交；
+// fn 返回错误时放弃事务并原样返回该错误。执行前校验 keys 属于同一个 slot，否则返回 ErrCrossSlot；
+// 冲突重试次数用完时返回包装了 ErrTxConflict 的错误
+// 使用示例（扣减库存）：
+//
+//	err := rediscluster.Optimistic(ctx, cli, []string{"{sku:1}:stock"}, func(ctx context.Context, tx *redis.Tx, pipe redis.Pipeliner) error {
+//		n, err := tx.Get(ctx, "{sku:1}:stock").Int()
+//		if err != nil {
+//			return err
+//		}
+//		if n < qty {
+//			return ErrSoldOut
+//		}
+//		pipe.Set(ctx, "{sku:1}:stock", n-qty, 0)
+//		return nil
+//	})
+func Optimistic(ctx context.Context, c Watcher, keys []string, fn func(ctx context.Context, tx *redis.Tx, pipe redis.Pipeliner) error, opts ...OptimisticOption) error {
+	if len(keys) == 0 {
+		return errors.New("rediscluster: optimistic transaction requires at least one key")
+	}
+	slot := Slot(keys[0])
+	for _, k := range keys[1:] {
+		if Slot(k) != slot {
+			return fmt.Errorf("%w: %s and %s", ErrCrossSlot, keys[0], k)
+		}
+	}
+	o := optimisticOptions{attempts: defaultOptimisticAttempts}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.attempts <= 0 {
+		o.attempts = 1
+	}
+
+	txf := func(tx *redis.Tx) error {
+		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			return fn(ctx, tx, pipe)
+		})
+		return err
+	}
+	for attempt := 0; attempt < o.attempts; attempt++ {
+		if attempt > 0 {
+			if err := backoff.Sleep(ctx, optimisticBackoff.Delay(attempt, 0)); err != nil {
+				return err
+			}
+		}
+		err := c.Watch(ctx, txf, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("%w after %d attempts", ErrTxConflict, o.attempts)
+}
+
+// optimisticBackoff 冲突重试的等待时间，带随机抖动以错开竞争的客户端
+var optimisticBackoff = backoff.EqualJitter(backoff.Exponential(time.Millisecond, maxOptimisticBackoff))
@@ -0,0 +1,90 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// incrBy 读取 key 后在事务中写入 n+delta；conflict 返回 true 时在读取后由其它客户端修改 key，使 EXEC 失败
+func incrBy(key string, delta int, calls *int, conflict func(call int) bool, other *redis.Client) func(context.Context, *redis.Tx, redis.Pipeliner) error {
+	return func(ctx context.Context, tx *redis.Tx, pipe redis.Pipeliner) error {
+		*calls++
+		n, err := tx.Get(ctx, key).Int()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if conflict(*calls) {
+			if err := other.Set(ctx, key, n+100, 0).Err(); err != nil {
+				return err
+			}
+		}
+		pipe.Set(ctx, key, n+delta, 0)
+		return nil
+	}
+}
+
+func TestOptimistic_RetryOnConflict(t *testing.T) {
+	mr, cli := newTestClient(t)
+	other := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer other.Close()
+	ctx := context.Background()
+	mr.Set("{sku:1}:stock", "10")
+
+	calls := 0
+	err := Optimistic(ctx, cli, []string{"{sku:1}:stock"},
+		incrBy("{sku:1}:stock", -1, &calls, func(call int) bool { return call <= 2 }, other))
+	if err != nil {
+		t.Fatalf("Optimistic: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	// 前两次冲突各写入 +100，第三次在最新值上扣减
+	if v, _ := mr.Get("{sku:1}:stock"); v != strconv.Itoa(10+100+100-1) {
+		t.Errorf("stock = %s", v)
+	}
+}
+
+func TestOptimistic_Exhausted(t *testing.T) {
+	mr, cli := newTestClient(t)
+	other := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer other.Close()
+	ctx := context.Background()
+
+	calls := 0
+	err := Optimistic(ctx, cli, []string{"k"}, incrBy("k", 1, &calls, func(int) bool { return true }, other), WithMaxAttempts(3))
+	if !errors.Is(err, ErrTxConflict) {
+		t.Fatalf("err = %v, want ErrTxConflict", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if v, _ := mr.Get("k"); v != "300" {
+		t.Errorf("k = %s, want only the conflicting writes", v)
+	}
+}
+
+func TestOptimistic_Errors(t *testing.T) {
+	_, cli := newTestClient(t)
+	ctx := context.Background()
+
+	err := Optimistic(ctx, cli, []string{"{a}:1", "{b}:1"}, func(context.Context, *redis.Tx, redis.Pipeliner) error { return nil })
+	if !errors.Is(err, ErrCrossSlot) {
+		t.Errorf("cross slot: err = %v", err)
+	}
+
+	// fn 返回的错误原样返回且不重试
+	boom := errors.New("sold out")
+	calls := 0
+	err = Optimistic(ctx, cli, []string{"k"}, func(context.Context, *redis.Tx, redis.Pipeliner) error {
+		calls++
+		return boom
+	})
+	if !errors.Is(err, boom) || calls != 1 {
+		t.Errorf("fn error: err = %v, calls = %d", err, calls)
+	}
+}
@@ -2,9 +2,12 @@ package rediscluster
 
 import (
 	"context"
+	"crypto/tls"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+
+	"github.com/qingfeng-studio/go-utils/utils/metrics"
 )
 
 // Config Redis Cluster 配置
@@ -22,6 +25,19 @@ type Config struct {
 
 	// 启动探活
 	PingTimeout time.Duration // 0 表示不 Ping
+
+	// 按命令/分类的超时，见 WithCommandTimeout
+	CommandTimeouts map[string]time.Duration
+
+	// TLS 配置，nil 表示不使用 TLS
+	TLSConfig *tls.Config
+
+	// 指标
+	Metrics     *metrics.Registry // 非空时记录命令与连接池指标，见 WithMetrics
+	MetricsName string            // 指标中的 client/pool 标签，为空时为 default
+
+	// 链路追踪
+	Tracing bool // 为每条命令创建 span，见 WithTracing
 }
 
 // Option 允许对配置进行增量修改
@@ -40,11 +56,24 @@ func WithTimeouts(dial, read, write time.Duration) Option {
 // WithPingTimeout 设置初始化 Ping 的超时
 func WithPingTimeout(d time.Duration) Option { return func(c *Config) { c.PingTimeout = d } }
 
+// WithTLS 使用 TLS 连接集群节点
+func WithTLS(cfg *tls.Config) Option { return func(c *Config) { c.TLSConfig = cfg } }
+
 // New 初始化并返回 *redis.ClusterClient
 func New(base Config, options ...Option) (*redis.ClusterClient, error) {
 	for _, opt := range options {
 		opt(&base)
 	}
+	var cm *metrics.ClientMetrics
+	if base.Metrics != nil {
+		var err error
+		if cm, err = metrics.NewClientMetrics(base.Metrics, metricsSubsystem, nil); err != nil {
+			return nil, err
+		}
+		if base.MetricsName == "" {
+			base.MetricsName = "default"
+		}
+	}
 	cli := redis.NewClusterClient(&redis.ClusterOptions{
 		Addrs:        base.Addrs,
 		Username:     base.Username,
@@ -54,7 +83,24 @@ func New(base Config, options ...Option) (*redis.ClusterClient, error) {
 		WriteTimeout: base.WriteTimeout,
 		PoolSize:     base.PoolSize,
 		MinIdleConns: base.MinIdleConns,
+		TLSConfig:    base.TLSConfig,
+		// 命令超时依赖 context 截止时间中断读写
+		ContextTimeoutEnabled: len(base.CommandTimeouts) > 0,
 	})
+	if len(base.CommandTimeouts) > 0 {
+		cli.AddHook(NewTimeoutHook(base.CommandTimeouts))
+	}
+	if cm != nil {
+		// 在超时 Hook 之后添加，耗时包含命令超时的效果
+		cli.AddHook(NewMetricsHook(cm, base.MetricsName))
+		if err := registerPoolStats(base.Metrics, base.MetricsName, cli); err != nil {
+			_ = cli.Close()
+			return nil, err
+		}
+	}
+	if base.Tracing {
+		cli.AddHook(NewTracingHook())
+	}
 
 	if base.PingTimeout > 0 {
 		ctx, cancel := context.WithTimeout(context.Background(), base.PingTimeout)
@@ -0,0 +1,252 @@
+package rediscluster
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrSessionNotFound 会话不存在或已过期
+var ErrSessionNotFound = errors.New("rediscluster: session not found")
+
+// sessionRecord 存储在 Redis 中的会话结构
+type sessionRecord struct {
+	Data      json.RawMessage `json:"data"`
+	CreatedAt int64           `json:"created_at"`
+}
+
+// SessionStore 基于 Redis 的会话存储，支持滑动过期与 JSON 载荷
+// 每次 Get/Refresh 都会把过期时间重置为 ttl；设置 MaxLifetime 后，会话从创建起超过该时长即失效，
+// 适合作为 refresh token 或浏览器会话的服务端存储
+//
+// 作为 refresh token 使用示例（access token 由 utils.JWTService 签发）：
+//
+//	refresh, err := store.Create(ctx, user) // 登录
+//	newRefresh, err := store.Rotate(ctx, refresh, &user) // 刷新：旧 refresh token 立即失效
+//	access, err := jwtSvc.GenerateToken(claimsFor(user))
+//
+// 作为浏览器会话使用示例：
+//
+//	mux.Handle("/api/", store.Middleware(utils.CookieToken("sid"))(api))
+type SessionStore struct {
+	c           redis.Cmdable
+	prefix      string
+	ttl         time.Duration
+	MaxLifetime time.Duration // 绝对最长有效期，0 表示不限制
+}
+
+// NewSessionStore 创建会话存储，prefix 为 key 前缀（如 "sess:"），ttl 为空闲过期时间，必须大于 0
+func NewSessionStore(c redis.Cmdable, prefix string, ttl time.Duration) (*SessionStore, error) {
+	if ttl <= 0 {
+		return nil, errors.New("rediscluster: session ttl must be positive")
+	}
+	return &SessionStore{c: c, prefix: prefix, ttl: ttl}, nil
+}
+
+// Create 创建会话并返回随机生成的会话 ID
+func (s *SessionStore) Create(ctx context.Context, data interface{}) (string, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return "", err
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("encode session: %w", err)
+	}
+	rec := sessionRecord{Data: raw, CreatedAt: time.Now().Unix()}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+	// MaxLifetime 小于 ttl 时，创建时的过期时间同样不能超过绝对有效期
+	ok, err := s.c.SetNX(ctx, s.prefix+id, b, s.expiry(&rec)).Result()
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", errors.New("rediscluster: session id collision")
+	}
+	return id, nil
+}
+
+// Get 读取会话数据到 out，并顺延过期时间（GETEX）
+func (s *SessionStore) Get(ctx context.Context, id string, out interface{}) error {
+	rec, err := s.load(ctx, id, true)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(rec.Data, out)
+}
+
+// Refresh 仅顺延会话过期时间
+func (s *SessionStore) Refresh(ctx context.Context, id string) error {
+	_, err := s.load(ctx, id, true)
+	return err
+}
+
+// Update 替换会话数据并顺延过期时间，会话不存在时返回 ErrSessionNotFound
+func (s *SessionStore) Update(ctx context.Context, id string, data interface{}) error {
+	rec, err := s.load(ctx, id, false)
+	if err != nil {
+		return err
+	}
+	if rec.Data, err = json.Marshal(data); err != nil {
+		return fmt.Errorf("encode session: %w", err)
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	ok, err := s.c.SetXX(ctx, s.prefix+id, b, s.expiry(rec)).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// Rotate 以新 ID 替换会话并把数据读取到 out（out 为 nil 时不读取），用于 refresh token 轮换：
+// 旧 ID 立即失效，数据与创建时间保留，MaxLifetime 仍从最初创建时计算；
+// 旧 ID 不存在或已被轮换过时返回 ErrSessionNotFound，可据此识别 refresh token 被重放（需要 Redis 6.2+）
+func (s *SessionStore) Rotate(ctx context.Context, id string, out interface{}) (string, error) {
+	data, err := s.c.GetDel(ctx, s.prefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrSessionNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	var rec sessionRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return "", fmt.Errorf("decode session: %w", err)
+	}
+	exp := s.expiry(&rec)
+	if exp <= 0 {
+		return "", ErrSessionNotFound
+	}
+	if out != nil {
+		if err := json.Unmarshal(rec.Data, out); err != nil {
+			return "", err
+		}
+	}
+
+	newID, err := newSessionID()
+	if err != nil {
+		return "", err
+	}
+	ok, err := s.c.SetNX(ctx, s.prefix+newID, data, exp).Result()
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", errors.New("rediscluster: session id collision")
+	}
+	return newID, nil
+}
+
+// Destroy 删除会话（登出）
+func (s *SessionStore) Destroy(ctx context.Context, id string) error {
+	return s.c.Del(ctx, s.prefix+id).Err()
+}
+
+// sessionCtxKey 会话 ID 在 request context 中的 key
+type sessionCtxKey struct{}
+
+// Middleware 返回校验会话的 HTTP 中间件：extract 从请求中取出会话 ID（如 utils.CookieToken、utils.BearerToken），
+// 会话有效时顺延过期时间并将 ID 写入 request context（SessionIDFromContext），
+// 未携带或会话已失效时返回 401，Redis 出错时返回 503
+func (s *SessionStore) Middleware(extract func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := extract(r)
+			if id == "" {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			if err := s.Refresh(r.Context(), id); err != nil {
+				status := http.StatusServiceUnavailable
+				if errors.Is(err, ErrSessionNotFound) {
+					status = http.StatusUnauthorized
+				}
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionCtxKey{}, id)))
+		})
+	}
+}
+
+// SessionIDFromContext 返回 Middleware 写入的会话 ID，可用于 Get/Update/Destroy
+func SessionIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(sessionCtxKey{}).(string)
+	return id, ok
+}
+
+// load 读取会话，slide 为 true 时同时顺延过期时间；超过 MaxLifetime 的会话会被删除
+func (s *SessionStore) load(ctx context.Context, id string, slide bool) (*sessionRecord, error) {
+	key := s.prefix + id
+	var (
+		data []byte
+		err  error
+	)
+	if slide {
+		data, err = s.c.GetEx(ctx, key, s.ttl).Bytes()
+	} else {
+		data, err = s.c.Get(ctx, key).Bytes()
+	}
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rec sessionRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("decode session: %w", err)
+	}
+	if s.MaxLifetime > 0 {
+		if s.expiry(&rec) <= 0 {
+			_ = s.c.Del(ctx, key).Err()
+			return nil, ErrSessionNotFound
+		}
+		if slide {
+			// 滑动后的过期时间不能超过绝对有效期
+			_ = s.c.Expire(ctx, key, s.expiry(&rec)).Err()
+		}
+	}
+	return &rec, nil
+}
+
+// expiry 计算本次写入的过期时间：取空闲 ttl 与剩余绝对有效期的较小值
+func (s *SessionStore) expiry(rec *sessionRecord) time.Duration {
+	if s.MaxLifetime <= 0 {
+		return s.ttl
+	}
+	remain := time.Until(time.Unix(rec.CreatedAt, 0).Add(s.MaxLifetime))
+	if remain < s.ttl {
+		return remain
+	}
+	return s.ttl
+}
+
+// newSessionID 生成 256 位随机会话 ID
+func newSessionID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
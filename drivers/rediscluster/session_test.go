@@ -0,0 +1,183 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestSessionStore 创建 ttl 为 1 小时的会话存储
+func newTestSessionStore(t *testing.T) (*SessionStore, func(d time.Duration), func(id string) time.Duration) {
+	t.Helper()
+	mr, cli := newTestClient(t)
+	s, err := NewSessionStore(cli, "sess:", time.Hour)
+	if err != nil {
+		t.Fatalf("NewSessionStore: %v", err)
+	}
+	return s, mr.FastForward, func(id string) time.Duration { return mr.TTL("sess:" + id) }
+}
+
+func TestSessionStore_MaxLifetime(t *testing.T) {
+	mr, cli := newTestClient(t)
+	ctx := context.Background()
+	s, err := NewSessionStore(cli, "sess:", time.Hour)
+	if err != nil {
+		t.Fatalf("NewSessionStore: %v", err)
+	}
+	s.MaxLifetime = 10 * time.Minute
+
+	id, err := s.Create(ctx, map[string]int{"uid": 1})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// 创建时的过期时间即受 MaxLifetime 限制，而不是空闲 ttl
+	if ttl := mr.TTL("sess:" + id); ttl <= 0 || ttl > s.MaxLifetime {
+		t.Errorf("ttl after Create = %v, want <= %v", ttl, s.MaxLifetime)
+	}
+
+	var out map[string]int
+	if err := s.Get(ctx, id, &out); err != nil || out["uid"] != 1 {
+		t.Fatalf("Get: %v %v", out, err)
+	}
+	if ttl := mr.TTL("sess:" + id); ttl > s.MaxLifetime {
+		t.Errorf("ttl after Get = %v, want <= %v", ttl, s.MaxLifetime)
+	}
+
+	mr.FastForward(11 * time.Minute)
+	if err := s.Get(ctx, id, &out); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Get after MaxLifetime: err = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestNewSessionStore_InvalidTTL(t *testing.T) {
+	_, cli := newTestClient(t)
+	for _, ttl := range []time.Duration{0, -time.Second} {
+		if _, err := NewSessionStore(cli, "sess:", ttl); err == nil {
+			t.Errorf("NewSessionStore(ttl=%v) should fail", ttl)
+		}
+	}
+}
+
+// TestSessionStore_Slide Get 与 Refresh 把过期时间重置为 ttl，空闲超过 ttl 后会话失效
+func TestSessionStore_Slide(t *testing.T) {
+	s, forward, ttl := newTestSessionStore(t)
+	ctx := context.Background()
+	id, err := s.Create(ctx, map[string]int{"uid": 1})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	forward(40 * time.Minute)
+	var out map[string]int
+	if err := s.Get(ctx, id, &out); err != nil || out["uid"] != 1 {
+		t.Fatalf("Get: %v %v", out, err)
+	}
+	if got := ttl(id); got != time.Hour {
+		t.Errorf("ttl after Get = %v, want 1h", got)
+	}
+
+	forward(40 * time.Minute)
+	if err := s.Refresh(ctx, id); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if got := ttl(id); got != time.Hour {
+		t.Errorf("ttl after Refresh = %v, want 1h", got)
+	}
+
+	forward(61 * time.Minute)
+	if err := s.Refresh(ctx, id); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Refresh after idle ttl: err = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestSessionStore_UpdateAndDestroy(t *testing.T) {
+	s, forward, _ := newTestSessionStore(t)
+	ctx := context.Background()
+	id, err := s.Create(ctx, map[string]int{"uid": 1})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := s.Update(ctx, id, map[string]int{"uid": 2}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	var out map[string]int
+	if err := s.Get(ctx, id, &out); err != nil || out["uid"] != 2 {
+		t.Fatalf("Get after Update: %v %v", out, err)
+	}
+
+	if err := s.Destroy(ctx, id); err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+	if err := s.Get(ctx, id, &out); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Get after Destroy: err = %v, want ErrSessionNotFound", err)
+	}
+	if err := s.Update(ctx, id, map[string]int{"uid": 3}); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Update after Destroy: err = %v, want ErrSessionNotFound", err)
+	}
+	if err := s.Update(ctx, "missing", map[string]int{"uid": 3}); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Update missing: err = %v, want ErrSessionNotFound", err)
+	}
+
+	expired, err := s.Create(ctx, map[string]int{"uid": 4})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	forward(2 * time.Hour)
+	if err := s.Update(ctx, expired, map[string]int{"uid": 5}); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Update expired: err = %v, want ErrSessionNotFound", err)
+	}
+}
+
+// TestSessionStore_Rotate 轮换后旧 ID 失效，重放旧 ID 返回 ErrSessionNotFound
+func TestSessionStore_Rotate(t *testing.T) {
+	s, _, _ := newTestSessionStore(t)
+	ctx := context.Background()
+	id, err := s.Create(ctx, map[string]int{"uid": 1})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	var out map[string]int
+	newID, err := s.Rotate(ctx, id, &out)
+	if err != nil || newID == id || out["uid"] != 1 {
+		t.Fatalf("Rotate = %q, %v, %v", newID, out, err)
+	}
+	if err := s.Get(ctx, newID, &out); err != nil || out["uid"] != 1 {
+		t.Fatalf("Get rotated: %v %v", out, err)
+	}
+	if _, err := s.Rotate(ctx, id, nil); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Rotate replayed id: err = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestSessionStore_Middleware(t *testing.T) {
+	s, _, _ := newTestSessionStore(t)
+	id, err := s.Create(context.Background(), map[string]int{"uid": 1})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	h := s.Middleware(func(r *http.Request) string { return r.Header.Get("X-Session") })(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ := SessionIDFromContext(r.Context())
+		_, _ = w.Write([]byte(got))
+	}))
+
+	for _, tc := range []struct {
+		sid  string
+		code int
+	}{{id, http.StatusOK}, {"", http.StatusUnauthorized}, {"missing", http.StatusUnauthorized}} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Session", tc.sid)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tc.code {
+			t.Errorf("session %q: code = %d, want %d", tc.sid, rec.Code, tc.code)
+		}
+		if tc.code == http.StatusOK && rec.Body.String() != id {
+			t.Errorf("SessionIDFromContext = %q, want %q", rec.Body.String(), id)
+		}
+	}
+}
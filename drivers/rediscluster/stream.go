@@ -0,0 +1,267 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+	"github.com/qingfeng-studio/go-utils/utils/backoff"
+)
+
+// consumerBackoff 读取、认领失败后的重试等待，连接恢复后重新从最短等待开始
+var consumerBackoff = backoff.FullJitter(backoff.Exponential(100*time.Millisecond, 10*time.Second))
+
+// StreamHandler 处理一条流消息，返回 nil 时自动 ACK；返回错误或 panic 时消息保持 pending，
+// 空闲超过 ClaimIdle 后被重新认领投递，投递次数达到 MaxDeliveries 后转入死信流
+type StreamHandler func(ctx context.Context, msg redis.XMessage) error
+
+// ConsumerConfig Redis Streams 消费组配置
+type ConsumerConfig struct {
+	Stream   string // 流的 key
+	Group    string // 消费组名
+	Consumer string // 消费者名，同一消费组内需唯一（通常为 hostname/pod 名）
+
+	BatchSize     int64         // 每次读取/认领的最大条数，默认 10
+	Block         time.Duration // XREADGROUP 阻塞等待时间，默认 2s
+	ClaimIdle     time.Duration // pending 消息空闲超过该时间会被认领重投，默认 1min
+	ClaimInterval time.Duration // 认领检查间隔，默认 30s
+	MaxDeliveries int64         // 最大投递次数，超过后转入死信流，默认 5
+	DeadLetter    string        // 死信流 key，默认 Stream + ":dlq"
+	StartID       string        // 消费组不存在时的起始 ID，"$" 仅消费新消息，"0" 从头消费，默认 "$"
+}
+
+// Consumer 基于 Redis Streams 消费组的消费者，提供至少一次（at-least-once）语义
+// 使用示例：
+//
+//	c := rediscluster.NewConsumer(cli, rediscluster.ConsumerConfig{Stream: "orders", Group: "billing", Consumer: host}, handle)
+//	go c.Run(ctx) // ctx 取消后处理完当前批次即返回
+type Consumer struct {
+	c       redis.Cmdable
+	cfg     ConsumerConfig
+	handler StreamHandler
+}
+
+// NewConsumer 创建消费者，未设置的配置项使用默认值
+func NewConsumer(c redis.Cmdable, cfg ConsumerConfig, handler StreamHandler) *Consumer {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 10
+	}
+	if cfg.Block <= 0 {
+		cfg.Block = 2 * time.Second
+	}
+	if cfg.ClaimIdle <= 0 {
+		cfg.ClaimIdle = time.Minute
+	}
+	if cfg.ClaimInterval <= 0 {
+		cfg.ClaimInterval = 30 * time.Second
+	}
+	if cfg.MaxDeliveries <= 0 {
+		cfg.MaxDeliveries = 5
+	}
+	if cfg.DeadLetter == "" {
+		cfg.DeadLetter = cfg.Stream + ":dlq"
+	}
+	if cfg.StartID == "" {
+		cfg.StartID = "$"
+	}
+	return &Consumer{c: c, cfg: cfg, handler: handler}
+}
+
+// Run 创建消费组（已存在则忽略）并循环消费，直到 ctx 取消；
+// 网络抖动等临时错误记录日志并退避重试，只在 ctx 结束或配置错误（handler 为空、消费组被删除、key 类型错误）时返回
+func (c *Consumer) Run(ctx context.Context) error {
+	if c.handler == nil {
+		return errors.New("rediscluster: stream handler must not be nil")
+	}
+	retry := backoff.Iter(consumerBackoff)
+	for {
+		err := c.ensureGroup(ctx)
+		if err == nil {
+			break
+		}
+		if err := c.retryWait(ctx, retry, "create group", err); err != nil {
+			return err
+		}
+	}
+
+	retry.Reset()
+	var lastClaim time.Time
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var err error
+		if time.Since(lastClaim) >= c.cfg.ClaimInterval {
+			lastClaim = time.Now()
+			err = c.ClaimPending(ctx)
+		}
+		if err == nil {
+			err = c.readNew(ctx)
+		}
+		if err == nil {
+			retry.Reset()
+			continue
+		}
+		if err := c.retryWait(ctx, retry, "consume", err); err != nil {
+			return err
+		}
+	}
+}
+
+// retryWait 处理一次消费错误：ctx 已结束或为配置错误时返回错误，否则记录日志并退避等待
+func (c *Consumer) retryWait(ctx context.Context, retry *backoff.Iterator, op string, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if isStreamConfigError(err) {
+		return fmt.Errorf("rediscluster: stream %s: %w", op, err)
+	}
+	logger.Warn(ctx, "rediscluster: stream "+op+" failed, retrying",
+		zap.String("stream", c.cfg.Stream), zap.String("group", c.cfg.Group), zap.Int("attempt", retry.Attempt()+1), zap.Error(err))
+	return retry.Wait(ctx)
+}
+
+// isStreamConfigError 重试无法恢复的错误：消费组不存在（运行中被删除）或 key 不是流
+func isStreamConfigError(err error) bool {
+	msg := err.Error()
+	return strings.HasPrefix(msg, "NOGROUP") || strings.HasPrefix(msg, "WRONGTYPE")
+}
+
+// ensureGroup 创建消费组，流不存在时一并创建
+func (c *Consumer) ensureGroup(ctx context.Context) error {
+	err := c.c.XGroupCreateMkStream(ctx, c.cfg.Stream, c.cfg.Group, c.cfg.StartID).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// readNew 读取并处理新消息
+func (c *Consumer) readNew(ctx context.Context) error {
+	streams, err := c.c.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    c.cfg.Group,
+		Consumer: c.cfg.Consumer,
+		Streams:  []string{c.cfg.Stream, ">"},
+		Count:    c.cfg.BatchSize,
+		Block:    c.cfg.Block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, s := range streams {
+		c.handle(ctx, s.Messages)
+	}
+	return nil
+}
+
+// ClaimPending 检查空闲超时的 pending 消息：超过最大投递次数的转入死信流，其余认领后重新处理
+func (c *Consumer) ClaimPending(ctx context.Context) error {
+	pending, err := c.c.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: c.cfg.Stream,
+		Group:  c.cfg.Group,
+		Idle:   c.cfg.ClaimIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  c.cfg.BatchSize,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var retry []string
+	for _, p := range pending {
+		if p.RetryCount >= c.cfg.MaxDeliveries {
+			if err := c.deadLetter(ctx, p); err != nil {
+				return err
+			}
+			continue
+		}
+		retry = append(retry, p.ID)
+	}
+	if len(retry) == 0 {
+		return nil
+	}
+
+	msgs, err := c.c.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   c.cfg.Stream,
+		Group:    c.cfg.Group,
+		Consumer: c.cfg.Consumer,
+		MinIdle:  c.cfg.ClaimIdle,
+		Messages: retry,
+	}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	c.handle(ctx, msgs)
+	return nil
+}
+
+// deadLetter 将消息复制到死信流并 ACK
+func (c *Consumer) deadLetter(ctx context.Context, p redis.XPendingExt) error {
+	msgs, err := c.c.XRangeN(ctx, c.cfg.Stream, p.ID, p.ID, 1).Result()
+	if err != nil {
+		return err
+	}
+	if len(msgs) > 0 {
+		values := make(map[string]interface{}, len(msgs[0].Values)+4)
+		for k, v := range msgs[0].Values {
+			values[k] = v
+		}
+		// 元信息最后写入，消息自带的同名字段不能覆盖
+		values["_origin_id"] = p.ID
+		values["_stream"] = c.cfg.Stream
+		values["_group"] = c.cfg.Group
+		values["_deliveries"] = p.RetryCount
+		if err := c.c.XAdd(ctx, &redis.XAddArgs{Stream: c.cfg.DeadLetter, Values: values}).Err(); err != nil {
+			return err
+		}
+	}
+	// 消息已被裁剪（XRANGE 为空）时直接 ACK，避免一直滞留在 pending 列表
+	return c.c.XAck(ctx, c.cfg.Stream, c.cfg.Group, p.ID).Err()
+}
+
+// handle 逐条处理消息，成功的批量 ACK
+func (c *Consumer) handle(ctx context.Context, msgs []redis.XMessage) {
+	acked := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		if err := c.safeHandle(ctx, m); err == nil {
+			acked = append(acked, m.ID)
+		}
+	}
+	if len(acked) > 0 {
+		// ACK 失败的消息会在空闲超时后重投，符合至少一次语义
+		_ = c.c.XAck(context.WithoutCancel(ctx), c.cfg.Stream, c.cfg.Group, acked...).Err()
+	}
+}
+
+// safeHandle 隔离 handler 的 panic
+func (c *Consumer) safeHandle(ctx context.Context, m redis.XMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("rediscluster: stream handler panic: %v", r)
+		}
+	}()
+	return c.handler(ctx, m)
+}
+
+// Publish 向流追加一条消息，maxLen > 0 时近似裁剪到该长度（MAXLEN ~）
+func Publish(ctx context.Context, c redis.Cmdable, stream string, values map[string]interface{}, maxLen int64) (string, error) {
+	args := &redis.XAddArgs{Stream: stream, Values: values}
+	if maxLen > 0 {
+		args.MaxLen = maxLen
+		args.Approx = true
+	}
+	return c.XAdd(ctx, args).Result()
+}
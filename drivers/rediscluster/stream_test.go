@@ -0,0 +1,161 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestConsumer 创建消费者并建好消费组，handler 的返回值由 fail 决定
+func newTestConsumer(t *testing.T, cli *redis.Client, cfg ConsumerConfig, fail func(redis.XMessage) bool, seen *[]redis.XMessage) *Consumer {
+	t.Helper()
+	cfg.Stream, cfg.Group, cfg.Consumer, cfg.StartID = "orders", "billing", "c1", "0"
+	c := NewConsumer(cli, cfg, func(_ context.Context, m redis.XMessage) error {
+		*seen = append(*seen, m)
+		if fail(m) {
+			return errors.New("handler failed")
+		}
+		return nil
+	})
+	if err := c.ensureGroup(context.Background()); err != nil {
+		t.Fatalf("ensureGroup: %v", err)
+	}
+	return c
+}
+
+// pendingCount 消费组中未 ACK 的消息数
+func pendingCount(t *testing.T, cli *redis.Client) int64 {
+	t.Helper()
+	p, err := cli.XPending(context.Background(), "orders", "billing").Result()
+	if err != nil {
+		t.Fatalf("XPending: %v", err)
+	}
+	return p.Count
+}
+
+func TestConsumer_ReadAndAck(t *testing.T) {
+	_, cli := newTestClient(t)
+	ctx := context.Background()
+	var seen []redis.XMessage
+	c := newTestConsumer(t, cli, ConsumerConfig{Block: 10 * time.Millisecond}, func(m redis.XMessage) bool { return m.Values["n"] == "2" }, &seen)
+
+	for _, n := range []string{"1", "2"} {
+		if _, err := Publish(ctx, cli, "orders", map[string]interface{}{"n": n}, 0); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	if err := c.readNew(ctx); err != nil {
+		t.Fatalf("readNew: %v", err)
+	}
+	if len(seen) != 2 {
+		t.Fatalf("handled %d messages, want 2", len(seen))
+	}
+	// 处理失败的消息保持 pending
+	if n := pendingCount(t, cli); n != 1 {
+		t.Errorf("pending = %d, want 1", n)
+	}
+}
+
+func TestConsumer_ClaimAndDeadLetter(t *testing.T) {
+	mr, cli := newTestClient(t)
+	ctx := context.Background()
+	now := time.Now()
+	mr.SetTime(now)
+
+	var seen []redis.XMessage
+	c := newTestConsumer(t, cli, ConsumerConfig{Block: 10 * time.Millisecond, ClaimIdle: time.Minute, MaxDeliveries: 2},
+		func(redis.XMessage) bool { return true }, &seen)
+	id, err := Publish(ctx, cli, "orders", map[string]interface{}{"n": "1", "_origin_id": "forged", "_deliveries": "0"}, 0)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := c.readNew(ctx); err != nil {
+		t.Fatalf("readNew: %v", err)
+	}
+
+	// 未达到 ClaimIdle 时不认领
+	if err := c.ClaimPending(ctx); err != nil {
+		t.Fatalf("ClaimPending: %v", err)
+	}
+	if len(seen) != 1 {
+		t.Fatalf("claimed before ClaimIdle: handled %d times", len(seen))
+	}
+
+	// 空闲超过 ClaimIdle 后认领重投（第 2 次投递）
+	mr.SetTime(now.Add(2 * time.Minute))
+	if err := c.ClaimPending(ctx); err != nil {
+		t.Fatalf("ClaimPending: %v", err)
+	}
+	if len(seen) != 2 || seen[1].ID != id {
+		t.Fatalf("expected redelivery of %s, seen %v", id, seen)
+	}
+
+	// 投递次数达到 MaxDeliveries 后转入死信流并 ACK
+	mr.SetTime(now.Add(4 * time.Minute))
+	if err := c.ClaimPending(ctx); err != nil {
+		t.Fatalf("ClaimPending: %v", err)
+	}
+	if len(seen) != 2 {
+		t.Errorf("dead-lettered message handled again")
+	}
+	if n := pendingCount(t, cli); n != 0 {
+		t.Errorf("pending = %d, want 0", n)
+	}
+	dlq, err := cli.XRange(ctx, "orders:dlq", "-", "+").Result()
+	if err != nil || len(dlq) != 1 {
+		t.Fatalf("dlq = %v, %v", dlq, err)
+	}
+	v := dlq[0].Values
+	if v["n"] != "1" || v["_origin_id"] != id || v["_deliveries"] != "2" || v["_group"] != "billing" {
+		t.Errorf("unexpected dead letter values: %v", v)
+	}
+}
+
+// TestConsumer_RunRetriesTransientErrors 临时错误不会让 Run 退出，消费组被删除时返回错误
+func TestConsumer_RunRetriesTransientErrors(t *testing.T) {
+	mr, cli := newTestClient(t)
+	handled := make(chan string, 1)
+	c := NewConsumer(cli, ConsumerConfig{Stream: "orders", Group: "billing", Consumer: "c1", StartID: "0", Block: 10 * time.Millisecond},
+		func(_ context.Context, m redis.XMessage) error {
+			handled <- m.Values["n"].(string)
+			return nil
+		})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	mr.SetError("LOADING server is loading")
+	done := make(chan error, 1)
+	go func() { done <- c.Run(ctx) }()
+
+	time.Sleep(200 * time.Millisecond)
+	mr.SetError("")
+	if _, err := Publish(ctx, cli, "orders", map[string]interface{}{"n": "1"}, 0); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	select {
+	case n := <-handled:
+		if n != "1" {
+			t.Errorf("handled %q", n)
+		}
+	case err := <-done:
+		t.Fatalf("Run returned early: %v", err)
+	case <-ctx.Done():
+		t.Fatal("message not handled after recovery")
+	}
+
+	if err := cli.XGroupDestroy(ctx, "orders", "billing").Err(); err != nil {
+		t.Fatalf("XGroupDestroy: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "NOGROUP") {
+			t.Errorf("Run err = %v, want NOGROUP", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Run did not return after the group was destroyed")
+	}
+}
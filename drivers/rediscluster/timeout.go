@@ -0,0 +1,171 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 命令分类，用于 WithCommandTimeout 按读/写整体设置超时
+const (
+	OpRead  = "read"  // 非阻塞的只读命令，如 GET/MGET/HGETALL/ZRANGE
+	OpWrite = "write" // 其余非阻塞命令
+)
+
+// readCommands 归为 OpRead 的命令
+var readCommands = map[string]bool{
+	"get": true, "mget": true, "getrange": true, "strlen": true, "exists": true, "ttl": true, "pttl": true, "type": true,
+	"hget": true, "hmget": true, "hgetall": true, "hexists": true, "hlen": true, "hkeys": true, "hvals": true, "hscan": true,
+	"lrange": true, "lindex": true, "llen": true,
+	"smembers": true, "sismember": true, "smismember": true, "scard": true, "srandmember": true, "sscan": true,
+	"zrange": true, "zrangebyscore": true, "zrevrange": true, "zrevrangebyscore": true, "zscore": true, "zmscore": true,
+	"zrank": true, "zrevrank": true, "zcard": true, "zcount": true, "zscan": true,
+	"scan": true, "xrange": true, "xrevrange": true, "xlen": true, "pfcount": true, "getbit": true, "bitcount": true,
+	"geopos": true, "geodist": true, "geosearch": true, "georadius_ro": true, "georadiusbymember_ro": true,
+}
+
+// blockingCommands 阻塞命令的耗时由调用方的阻塞参数决定，不套用分类超时（仍可按命令名单独设置）
+var blockingCommands = map[string]bool{
+	"blpop": true, "brpop": true, "blmove": true, "brpoplpush": true, "blmpop": true,
+	"bzpopmin": true, "bzpopmax": true, "bzmpop": true, "xread": true, "xreadgroup": true, "wait": true,
+}
+
+// WithCommandTimeout 为指定命令（小写命令名，如 "get"）或命令分类（OpRead/OpWrite）设置超时，
+// 比全局 ReadTimeout 更细粒度，例如缓存读取 50ms、写入 300ms；
+// 优先级：WithCallTimeout > 命令名 > 分类。超时通过 context 截止时间实现，调用方更早的截止时间不会被放宽
+func WithCommandTimeout(op string, d time.Duration) Option {
+	return func(c *Config) {
+		if c.CommandTimeouts == nil {
+			c.CommandTimeouts = make(map[string]time.Duration)
+		}
+		c.CommandTimeouts[strings.ToLower(op)] = d
+	}
+}
+
+// callTimeoutKey 单次调用超时的 context key
+type callTimeoutKey struct{}
+
+// WithCallTimeout 为单次调用设置超时，覆盖 WithCommandTimeout 的配置，仅对启用了命令超时的客户端生效；
+// 与 context.WithTimeout 不同，超时从命令真正发出时开始计算，也无需调用方管理 cancel
+//
+//	v, err := cli.Get(rediscluster.WithCallTimeout(ctx, 20*time.Millisecond), key).Result()
+func WithCallTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, d)
+}
+
+// NewTimeoutHook 返回按命令设置超时的 Hook，New 在配置了 CommandTimeouts 时会自动添加；
+// 自行创建客户端时需同时开启 ContextTimeoutEnabled，否则 go-redis 不会按 context 截止时间中断读写
+func NewTimeoutHook(timeouts map[string]time.Duration) redis.Hook {
+	m := make(map[string]time.Duration, len(timeouts))
+	for op, d := range timeouts {
+		m[strings.ToLower(op)] = d
+	}
+	return &timeoutHook{timeouts: m}
+}
+
+// timeoutHook 按命令为 context 附加截止时间
+type timeoutHook struct {
+	timeouts map[string]time.Duration
+}
+
+// DialHook 实现 redis.Hook
+func (h *timeoutHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+// ProcessHook 实现 redis.Hook
+func (h *timeoutHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if d := h.timeout(ctx, cmd); d > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, d)
+			defer cancel()
+			if err := next(ctx, cmd); err != nil {
+				return deadlineExceeded(ctx, cmd, err)
+			}
+			return nil
+		}
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook 实现 redis.Hook，pipeline/事务使用其中命令超时的最大值
+func (h *timeoutHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		var max time.Duration
+		for _, cmd := range cmds {
+			d := h.timeout(ctx, cmd)
+			if d < 0 {
+				continue
+			}
+			if d == 0 {
+				// 有命令未配置超时时不限制整个 pipeline
+				max = 0
+				break
+			}
+			if d > max {
+				max = d
+			}
+		}
+		if max > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, max)
+			defer cancel()
+			err := next(ctx, cmds)
+			for _, cmd := range cmds {
+				if cmd.Err() != nil {
+					_ = deadlineExceeded(ctx, cmd, cmd.Err())
+				}
+			}
+			if err != nil {
+				return deadlineExceeded(ctx, nil, err)
+			}
+			return nil
+		}
+		return next(ctx, cmds)
+	}
+}
+
+// deadlineExceeded 截止时间到达后 go-redis 返回的是连接读写超时（net.Error），
+// 转换为包装 context.DeadlineExceeded 的错误并写回命令，调用方可统一用 errors.Is 判断；其它错误原样返回。
+// 按截止时间判断而不是 ctx.Err()：socket 超时可能先于 context 的计时器触发，此时 ctx.Err() 仍为 nil
+func deadlineExceeded(ctx context.Context, cmd redis.Cmder, err error) error {
+	var ne net.Error
+	if !errors.As(err, &ne) || !ne.Timeout() {
+		return err
+	}
+	if dl, ok := ctx.Deadline(); !ok || time.Now().Before(dl) {
+		return err
+	}
+	if cmd == nil {
+		return fmt.Errorf("rediscluster: pipeline: %w", context.DeadlineExceeded)
+	}
+	err = fmt.Errorf("rediscluster: %s: %w", cmd.Name(), context.DeadlineExceeded)
+	cmd.SetErr(err)
+	return err
+}
+
+// timeout 计算命令的超时，0 表示不限制，负数表示不参与 pipeline 超时的计算
+func (h *timeoutHook) timeout(ctx context.Context, cmd redis.Cmder) time.Duration {
+	if d, ok := ctx.Value(callTimeoutKey{}).(time.Duration); ok {
+		return d
+	}
+	name := cmd.Name()
+	switch name {
+	case "multi", "exec":
+		return -1
+	}
+	if d, ok := h.timeouts[name]; ok {
+		return d
+	}
+	if blockingCommands[name] {
+		return 0
+	}
+	if readCommands[name] {
+		return h.timeouts[OpRead]
+	}
+	return h.timeouts[OpWrite]
+}
@@ -0,0 +1,62 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestTimeoutHook_BlockedCommand 阻塞命令超过配置的超时后返回 context.DeadlineExceeded，连接仍可继续使用
+func TestTimeoutHook_BlockedCommand(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cli, err := New(Config{Addrs: []string{mr.Addr()}}, WithCommandTimeout("blpop", 50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = cli.Close() })
+	ctx := context.Background()
+
+	start := time.Now()
+	err = cli.BLPop(ctx, 0, "queue").Err()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("BLPop err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("BLPop returned after %v", elapsed)
+	}
+
+	if err := cli.RPush(ctx, "queue", "job").Err(); err != nil {
+		t.Fatalf("RPush after timeout: %v", err)
+	}
+	if v, err := cli.BLPop(ctx, 0, "queue").Result(); err != nil || v[1] != "job" {
+		t.Fatalf("BLPop = %v, %v", v, err)
+	}
+}
+
+func TestTimeoutHook_CallTimeoutAndPipeline(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cli := redis.NewClient(&redis.Options{Addr: mr.Addr(), ContextTimeoutEnabled: true})
+	cli.AddHook(NewTimeoutHook(map[string]time.Duration{OpWrite: 50 * time.Millisecond}))
+	t.Cleanup(func() { _ = cli.Close() })
+	ctx := context.Background()
+
+	// 阻塞命令默认不套用分类超时，WithCallTimeout 可为单次调用指定
+	if err := cli.BLPop(WithCallTimeout(ctx, 50*time.Millisecond), 0, "queue").Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("BLPop with call timeout err = %v, want context.DeadlineExceeded", err)
+	}
+
+	// WithCallTimeout 同样作用于 pipeline，未完成的命令返回 context.DeadlineExceeded
+	var pop *redis.StringSliceCmd
+	_, err := cli.Pipelined(WithCallTimeout(ctx, 50*time.Millisecond), func(p redis.Pipeliner) error {
+		p.Set(ctx, "k", "v", 0)
+		pop = p.BLPop(ctx, 0, "queue")
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(pop.Err(), context.DeadlineExceeded) {
+		t.Fatalf("pipeline err = %v, BLPop err = %v, want context.DeadlineExceeded", err, pop.Err())
+	}
+}
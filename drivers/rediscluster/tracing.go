@@ -0,0 +1,62 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/qingfeng-studio/go-utils/utils/otelx"
+)
+
+// tracingComponent rediscluster 的 instrumentation scope 名称
+const tracingComponent = "rediscluster"
+
+// WithTracing 为每条命令创建客户端 span，名称为小写命令名，pipeline/事务整体为一个 pipeline span；
+// 命令参数不写入 span，redis.Nil 不视为错误；需先调用 otelx.Init
+func WithTracing() Option { return func(c *Config) { c.Tracing = true } }
+
+// NewTracingHook 返回创建 span 的 Hook，New 在配置了 Tracing 时会自动添加
+func NewTracingHook() redis.Hook { return tracingHook{} }
+
+// tracingHook 为命令创建 span
+type tracingHook struct{}
+
+// DialHook 实现 redis.Hook
+func (tracingHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+// ProcessHook 实现 redis.Hook
+func (tracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := startSpan(ctx, cmd.Name())
+		err := next(ctx, cmd)
+		endSpan(span, err)
+		return err
+	}
+}
+
+// ProcessPipelineHook 实现 redis.Hook
+func (tracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := startSpan(ctx, "pipeline", attribute.Int("db.redis.pipeline_length", len(cmds)))
+		err := next(ctx, cmds)
+		endSpan(span, err)
+		return err
+	}
+}
+
+// startSpan 开启命令 span
+func startSpan(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("db.system", "redis"), attribute.String("db.operation", op))
+	return otelx.StartClient(ctx, tracingComponent, op, attrs...)
+}
+
+// endSpan 结束 span，redis.Nil 表示键不存在，按成功处理
+func endSpan(span trace.Span, err error) {
+	if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	otelx.End(span, err)
+}
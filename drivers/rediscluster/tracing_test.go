@@ -0,0 +1,53 @@
+package rediscluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/qingfeng-studio/go-utils/config"
+	"github.com/qingfeng-studio/go-utils/utils/otelx"
+)
+
+func TestTracingHook(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	shutdown, err := otelx.Init(context.Background(), config.Tracing{Enabled: true, Sampler: config.SamplerAlwaysOn},
+		otelx.WithExporter(exp), otelx.WithSyncExport())
+	if err != nil {
+		t.Fatalf("otelx.Init: %v", err)
+	}
+	defer shutdown(context.Background())
+
+	mr, cli := newTestClient(t)
+	cli.AddHook(NewTracingHook())
+	ctx := context.Background()
+
+	if err := cli.Get(ctx, "missing").Err(); err != redis.Nil {
+		t.Fatalf("Get missing: %v", err)
+	}
+	mr.SetError("boom")
+	_ = cli.Get(ctx, "k").Err()
+	mr.SetError("")
+	_, _ = cli.Pipelined(ctx, func(p redis.Pipeliner) error {
+		p.Incr(ctx, "n")
+		p.Incr(ctx, "n")
+		return nil
+	})
+
+	want := []struct {
+		name  string
+		error bool
+	}{{"get", false}, {"get", true}, {"pipeline", false}}
+	spans := exp.GetSpans()
+	if len(spans) != len(want) {
+		t.Fatalf("got %d spans, want %d", len(spans), len(want))
+	}
+	for i, w := range want {
+		if spans[i].Name != w.name || (spans[i].Status.Code == codes.Error) != w.error {
+			t.Errorf("span %d: name = %q, status = %v, want %q error=%v", i, spans[i].Name, spans[i].Status.Code, w.name, w.error)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package httpx
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// WithBasicAuth 设置默认的 Authorization: Basic 请求头，作用范围与 WithHeader 相同，受 WithDefaultHeaderScope 限制
+func WithBasicAuth(username, password string) Option {
+	return func(o *ClientOptions) {
+		if o.Headers == nil {
+			o.Headers = make(http.Header)
+		}
+		o.Headers.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+	}
+}
+
+// WithDigestAuth 启用 HTTP Digest 认证（RFC 7616），自动完成 401 质询与应答，见 NewDigestTransport
+func WithDigestAuth(username, password string) Option {
+	return func(o *ClientOptions) {
+		o.DigestUsername = username
+		o.DigestPassword = password
+	}
+}
+
+// digestChallenge 服务端 WWW-Authenticate: Digest 质询
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string // MD5、MD5-sess、SHA-256、SHA-256-sess
+	qop       string // 为空表示 RFC 2069 兼容模式
+	nc        int    // 同一 nonce 的请求计数
+}
+
+// digestTransport 处理 Digest 认证的 RoundTripper，按 host 缓存质询，后续请求直接携带认证信息
+type digestTransport struct {
+	base     http.RoundTripper
+	username string
+	password string
+
+	mu         sync.Mutex
+	challenges map[string]*digestChallenge
+}
+
+// NewDigestTransport 返回处理 Digest 认证的 RoundTripper，base 为 nil 时使用 http.DefaultTransport
+// 收到 401 与 Digest 质询后计算应答并重发一次请求（请求体需可重放，否则直接返回 401）；
+// 质询按 host 缓存，之后的请求预先携带认证信息，nonce 失效时重新质询
+// 也可用于标准库 http.Client：
+//
+//	hc := &http.Client{Transport: httpx.NewDigestTransport(nil, "admin", "secret")}
+func NewDigestTransport(base http.RoundTripper, username, password string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &digestTransport{base: base, username: username, password: password, challenges: make(map[string]*digestChallenge)}
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *digestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	first := req
+	if auth, ok := t.authorize(host, req); ok {
+		first = req.Clone(req.Context())
+		first.Header.Set("Authorization", auth)
+	}
+	resp, err := t.base.RoundTrip(first)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	ch := parseDigestChallenge(resp.Header.Values("WWW-Authenticate"))
+	if ch == nil || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return resp, nil
+	}
+	t.mu.Lock()
+	t.challenges[host] = ch
+	t.mu.Unlock()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	auth, ok := t.authorize(host, retry)
+	if !ok {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+	retry.Header.Set("Authorization", auth)
+	return t.base.RoundTrip(retry)
+}
+
+// authorize 使用缓存的质询计算 Authorization 请求头
+func (t *digestTransport) authorize(host string, req *http.Request) (string, bool) {
+	t.mu.Lock()
+	ch, ok := t.challenges[host]
+	if !ok {
+		t.mu.Unlock()
+		return "", false
+	}
+	ch.nc++
+	c := *ch
+	t.mu.Unlock()
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", false
+	}
+	return c.authorization(t.username, t.password, req.Method, req.URL.RequestURI(), hex.EncodeToString(b)), true
+}
+
+// authorization 按 RFC 7616 计算应答并拼接 Authorization 请求头
+func (c *digestChallenge) authorization(username, password, method, uri, cnonce string) string {
+	newHash := md5.New
+	algorithm := strings.ToUpper(c.algorithm)
+	if strings.HasPrefix(algorithm, "SHA-256") {
+		newHash = sha256.New
+	}
+	h := func(s string) string { return hashHex(newHash, s) }
+
+	ha1 := h(username + ":" + c.realm + ":" + password)
+	if strings.HasSuffix(algorithm, "-SESS") {
+		ha1 = h(ha1 + ":" + c.nonce + ":" + cnonce)
+	}
+	ha2 := h(method + ":" + uri)
+	nc := fmt.Sprintf("%08x", c.nc)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `Digest username="%s", realm="%s", nonce="%s", uri="%s"`, quoteEscape(username), quoteEscape(c.realm), c.nonce, uri)
+	if c.algorithm != "" {
+		b.WriteString(", algorithm=" + c.algorithm)
+	}
+	if c.qop != "" {
+		response := h(ha1 + ":" + c.nonce + ":" + nc + ":" + cnonce + ":" + c.qop + ":" + ha2)
+		fmt.Fprintf(&b, `, response="%s", qop=%s, nc=%s, cnonce="%s"`, response, c.qop, nc, cnonce)
+	} else {
+		fmt.Fprintf(&b, `, response="%s"`, h(ha1+":"+c.nonce+":"+ha2))
+	}
+	if c.opaque != "" {
+		fmt.Fprintf(&b, `, opaque="%s"`, c.opaque)
+	}
+	return b.String()
+}
+
+// parseDigestChallenge 从 WWW-Authenticate 中选出可用的 Digest 质询，多个时优先 SHA-256；
+// 只支持 qop=auth（auth-int 需要对请求体摘要，不支持）
+func parseDigestChallenge(values []string) *digestChallenge {
+	var best *digestChallenge
+	for _, v := range values {
+		if len(v) < 7 || !strings.EqualFold(v[:7], "Digest ") {
+			continue
+		}
+		params := parseAuthParams(v[7:])
+		ch := &digestChallenge{
+			realm:     params["realm"],
+			nonce:     params["nonce"],
+			opaque:    params["opaque"],
+			algorithm: params["algorithm"],
+		}
+		switch strings.ToUpper(ch.algorithm) {
+		case "", "MD5", "MD5-SESS", "SHA-256", "SHA-256-SESS":
+		default:
+			continue
+		}
+		if qop, ok := params["qop"]; ok {
+			for _, q := range strings.Split(qop, ",") {
+				if strings.TrimSpace(q) == "auth" {
+					ch.qop = "auth"
+				}
+			}
+			if ch.qop == "" {
+				continue
+			}
+		}
+		if ch.nonce == "" {
+			continue
+		}
+		if best == nil || strings.HasPrefix(strings.ToUpper(ch.algorithm), "SHA-256") {
+			best = ch
+		}
+	}
+	return best
+}
+
+// parseAuthParams 解析 key=value, key="quoted, value" 形式的认证参数，键转为小写
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 {
+			return params
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = strings.TrimLeft(s[eq+1:], " \t")
+		var val strings.Builder
+		if strings.HasPrefix(s, `"`) {
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				val.WriteByte(s[i])
+			}
+			s = s[min(i+1, len(s)):]
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				end = len(s)
+			}
+			val.WriteString(strings.TrimSpace(s[:end]))
+			s = s[end:]
+		}
+		params[key] = val.String()
+	}
+}
+
+// hashHex 计算摘要的十六进制字符串
+func hashHex(newHash func() hash.Hash, s string) string {
+	h := newHash()
+	h.Write([]byte(s))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// quoteEscape 转义带引号参数中的 \ 与 "
+func quoteEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
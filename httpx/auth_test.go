@@ -0,0 +1,106 @@
+package httpx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_BasicAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != "admin" || p != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBasicAuth("admin", "s3cret"))
+	if _, _, err := c.Get(context.Background(), srv.URL, nil, nil); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+}
+
+func TestDigestChallenge_RFCVectors(t *testing.T) {
+	// RFC 2617 3.5
+	ch := &digestChallenge{realm: "testrealm@host.com", nonce: "dcd98b7102dd2f0e8b11d0f600bfb0c093", qop: "auth", nc: 1,
+		opaque: "5ccc069c403ebaf9f0171e9517f40e41"}
+	auth := ch.authorization("Mufasa", "Circle Of Life", "GET", "/dir/index.html", "0a4f113b")
+	if !strings.Contains(auth, `response="6629fae49393a05397450978507c4ef1"`) || !strings.Contains(auth, "nc=00000001") {
+		t.Errorf("unexpected MD5 authorization: %s", auth)
+	}
+
+	// RFC 7616 3.9.1
+	ch = &digestChallenge{realm: "http-auth@example.org", nonce: "7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v", qop: "auth", nc: 1, algorithm: "SHA-256"}
+	auth = ch.authorization("Mufasa", "Circle of Life", "GET", "/dir/index.html", "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ")
+	if !strings.Contains(auth, `response="753927fa0e85d155564e2e272a28d1802ca10daf4496794697cf8db5856cb6c1"`) {
+		t.Errorf("unexpected SHA-256 authorization: %s", auth)
+	}
+}
+
+func TestParseDigestChallenge(t *testing.T) {
+	ch := parseDigestChallenge([]string{
+		`Basic realm="x"`,
+		`Digest realm="a, b", qop="auth,auth-int", algorithm=MD5, nonce="n1", opaque="o1"`,
+		`Digest realm="a, b", qop="auth", algorithm=SHA-256, nonce="n2"`,
+	})
+	if ch == nil || ch.realm != "a, b" || ch.nonce != "n2" || ch.algorithm != "SHA-256" || ch.qop != "auth" {
+		t.Fatalf("unexpected challenge: %+v", ch)
+	}
+	if parseDigestChallenge([]string{`Digest realm="x", qop="auth-int", nonce="n"`}) != nil {
+		t.Error("auth-int only challenge should be ignored")
+	}
+}
+
+// newDigestServer 校验 Digest 应答的测试服务，记录收到质询的次数
+func newDigestServer(t *testing.T, challenges *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := parseAuthParams(strings.TrimPrefix(r.Header.Get("Authorization"), "Digest "))
+		ch := &digestChallenge{realm: "dev", nonce: "abc", qop: "auth", opaque: "op"}
+		if params["response"] != "" {
+			nc, _ := strconv.ParseInt(params["nc"], 16, 0)
+			ch.nc = int(nc)
+			want := parseAuthParams(strings.TrimPrefix(ch.authorization("admin", "pw", r.Method, params["uri"], params["cnonce"]), "Digest "))
+			if params["response"] == want["response"] && params["opaque"] == "op" {
+				body, _ := io.ReadAll(r.Body)
+				_, _ = w.Write(append([]byte("ok:"), body...))
+				return
+			}
+		}
+		challenges.Add(1)
+		w.Header().Set("WWW-Authenticate", `Digest realm="dev", qop="auth", nonce="abc", opaque="op"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+}
+
+func TestClient_DigestAuth(t *testing.T) {
+	var challenges atomic.Int32
+	srv := newDigestServer(t, &challenges)
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithDigestAuth("admin", "pw"))
+	ctx := context.Background()
+	_, body, err := c.Post(ctx, "/device/reboot?now=1", []byte("payload"), "text/plain", nil, nil)
+	if err != nil || string(body) != "ok:payload" {
+		t.Fatalf("Post: body=%q err=%v", body, err)
+	}
+	// 质询已缓存，后续请求直接携带认证信息
+	if _, _, err := c.Get(ctx, "/device/status", nil, nil); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if challenges.Load() != 1 {
+		t.Errorf("challenges = %d, want 1", challenges.Load())
+	}
+
+	// 密码错误时返回 401
+	bad := NewClient(WithBaseURL(srv.URL), WithDigestAuth("admin", "wrong"))
+	_, _, err = bad.Get(ctx, "/device/status", nil, nil)
+	if se, ok := err.(*StatusError); !ok || se.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %v", err)
+	}
+}
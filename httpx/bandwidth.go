@@ -0,0 +1,124 @@
+package httpx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithBandwidthLimit 限制客户端上传与下载的带宽（字节/秒），上传、下载分别计算，
+// 由该 Client 发出的所有请求共享额度；bytesPerSec <= 0 表示不限速
+// 注意：限速会拉长传输时间，大文件传输需同时调大 WithTimeout 或改用 context 控制超时
+func WithBandwidthLimit(bytesPerSec int64) Option {
+	return func(o *ClientOptions) { o.BandwidthLimit = bytesPerSec }
+}
+
+// bandwidthLimiter 令牌桶限速器，桶容量为 1 秒的额度，允许透支后按速率等待
+type bandwidthLimiter struct {
+	rate  float64 // 每秒字节数
+	burst int     // 单次读取的最大字节数
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newBandwidthLimiter 创建限速器
+func newBandwidthLimiter(bytesPerSec int64) *bandwidthLimiter {
+	burst := int(bytesPerSec)
+	if burst > 256*1024 {
+		burst = 256 * 1024 // 单次读取过大时速率曲线不平滑
+	}
+	return &bandwidthLimiter{rate: float64(bytesPerSec), burst: burst, tokens: float64(burst), last: time.Now()}
+}
+
+// wait 消耗 n 字节的额度，额度不足时等待
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	var d time.Duration
+	if l.tokens < 0 {
+		d = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+	return sleepCtx(ctx, d)
+}
+
+// throttledBody 按限速器读取的 body
+type throttledBody struct {
+	rc      io.ReadCloser
+	ctx     context.Context
+	limiter *bandwidthLimiter
+}
+
+// Read 每次最多读取 burst 字节，读取后等待额度
+func (b *throttledBody) Read(p []byte) (int, error) {
+	if len(p) > b.limiter.burst {
+		p = p[:b.limiter.burst]
+	}
+	n, err := b.rc.Read(p)
+	if n > 0 {
+		if werr := b.limiter.wait(b.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// Close 关闭底层 body
+func (b *throttledBody) Close() error { return b.rc.Close() }
+
+// throttleTransport 对请求体与响应体限速的 RoundTripper
+type throttleTransport struct {
+	base     http.RoundTripper
+	upload   *bandwidthLimiter
+	download *bandwidthLimiter
+}
+
+// newThrottleTransport 包装 base，base 为 nil 时使用 http.DefaultTransport
+func newThrottleTransport(base http.RoundTripper, bytesPerSec int64) *throttleTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &throttleTransport{
+		base:     base,
+		upload:   newBandwidthLimiter(bytesPerSec),
+		download: newBandwidthLimiter(bytesPerSec),
+	}
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if req.Body != nil && req.Body != http.NoBody {
+		// RoundTripper 不应修改原请求，克隆后替换 body
+		orig := req
+		req = req.Clone(ctx)
+		req.Body = &throttledBody{rc: orig.Body, ctx: ctx, limiter: t.upload}
+		if orig.GetBody != nil {
+			req.GetBody = func() (io.ReadCloser, error) {
+				rc, err := orig.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				return &throttledBody{rc: rc, ctx: ctx, limiter: t.upload}, nil
+			}
+		}
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil && resp.Body != http.NoBody {
+		resp.Body = &throttledBody{rc: resp.Body, ctx: ctx, limiter: t.download}
+	}
+	return resp, nil
+}
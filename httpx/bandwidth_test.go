@@ -0,0 +1,46 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestClient_BandwidthLimit(t *testing.T) {
+	srv := newEchoServer()
+	defer srv.Close()
+
+	const limit = 20 * 1024
+	c := NewClient(WithBaseURL(srv.URL), WithBandwidthLimit(limit))
+	payload := bytes.Repeat([]byte("a"), 30*1024)
+
+	start := time.Now()
+	_, body, err := c.Post(context.Background(), "/upload", payload, "text/plain", nil, nil)
+	if err != nil {
+		t.Fatalf("Post error: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	var echo echoPayload
+	if err := json.Unmarshal(body, &echo); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(echo.Body) != len(payload) {
+		t.Fatalf("body length = %d, want %d", len(echo.Body), len(payload))
+	}
+	// 上传与下载各超出 1 秒额度约 10KB，至少各需等待约 0.5 秒
+	if elapsed < 800*time.Millisecond {
+		t.Errorf("transfer finished too fast: %v", elapsed)
+	}
+}
+
+func TestBandwidthLimiter_ContextCanceled(t *testing.T) {
+	l := newBandwidthLimiter(1024)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx, 4096); err == nil {
+		t.Error("expected context error while waiting for bandwidth")
+	}
+}
@@ -0,0 +1,338 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultCacheEntries 响应缓存默认的最大条目数
+	defaultCacheEntries = 1000
+	// CacheStatusHeader 命中缓存时在响应头中标记 HIT（新鲜）或 STALE（过期但仍可用）
+	CacheStatusHeader = "X-Cache"
+)
+
+// CacheRule 按路径模式配置的缓存策略
+type CacheRule struct {
+	// Pattern 路径模式，path.Match 语法（如 /api/products/*），以 /** 结尾时匹配任意深度的子路径，空或 /** 匹配所有路径
+	Pattern string
+	// TTL 新鲜期，期间直接返回缓存
+	TTL time.Duration
+	// StaleWhileRevalidate 过期后的这段时间内仍直接返回旧响应，同时在后台刷新（同一地址只刷新一次）
+	StaleWhileRevalidate time.Duration
+	// StaleIfError 过期后的这段时间内，上游返回网络错误或 5xx 时返回旧响应
+	StaleIfError time.Duration
+}
+
+// WithResponseCache 为 GET 请求启用进程内响应缓存，按 rules 中第一条匹配路径的规则处理，未匹配的请求不缓存
+// 只缓存成功（< 400）且未声明 Cache-Control: no-store、未带 Set-Cookie、Vary 不为 * 的响应；
+// 请求带 Cache-Control: no-cache 时跳过缓存读取；缓存键包含完整 URL、Authorization、Cookie 以及
+// WithContextHeader/ContextWithHeaderMapping 透传的请求头（如租户、用户），响应声明 Vary 时其列出的请求头也须一致，
+// 避免不同用户、租户之间串用响应；maxEntries <= 0 时默认 1000 条
+// 使用示例：
+//
+//	c := httpx.NewClient(httpx.WithResponseCache(0, httpx.CacheRule{
+//		Pattern:              "/api/config/**",
+//		TTL:                  time.Minute,
+//		StaleWhileRevalidate: 30 * time.Second,
+//		StaleIfError:         time.Hour, // 上游故障时最多使用 1 小时前的配置
+//	}))
+func WithResponseCache(maxEntries int, rules ...CacheRule) Option {
+	return func(o *ClientOptions) {
+		o.CacheEntries = maxEntries
+		o.CacheRules = append(o.CacheRules, rules...)
+	}
+}
+
+// cacheEntry 缓存的响应
+type cacheEntry struct {
+	status   string
+	code     int
+	proto    string
+	header   http.Header
+	body     []byte
+	storedAt time.Time
+	vary     map[string]string // 响应 Vary 列出的请求头 -> 写入缓存时的请求值
+}
+
+// matches 请求在 Vary 列出的请求头上与写入缓存时一致
+func (e *cacheEntry) matches(req *http.Request) bool {
+	for name, v := range e.vary {
+		if req.Header.Get(name) != v {
+			return false
+		}
+	}
+	return true
+}
+
+// response 根据缓存构造响应，body 为副本
+func (e *cacheEntry) response(req *http.Request, status string) (*http.Response, []byte) {
+	header := e.header.Clone()
+	header.Set(CacheStatusHeader, status)
+	body := bytes.Clone(e.body)
+	return &http.Response{
+		Status:        e.status,
+		StatusCode:    e.code,
+		Proto:         e.proto,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, body
+}
+
+// responseCache 进程内响应缓存
+type responseCache struct {
+	rules      []CacheRule
+	maxEntries int
+
+	mu         sync.Mutex
+	entries    map[string]*cacheEntry
+	refreshing map[string]bool
+}
+
+// newResponseCache 创建响应缓存，没有规则时返回 nil
+func newResponseCache(maxEntries int, rules []CacheRule) *responseCache {
+	if len(rules) == 0 {
+		return nil
+	}
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheEntries
+	}
+	return &responseCache{
+		rules:      rules,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*cacheEntry),
+		refreshing: make(map[string]bool),
+	}
+}
+
+// match 返回第一条匹配请求路径的规则
+func (rc *responseCache) match(req *http.Request) (CacheRule, bool) {
+	if req.Method != http.MethodGet {
+		return CacheRule{}, false
+	}
+	p := req.URL.Path
+	if p == "" {
+		p = "/"
+	}
+	for _, r := range rc.rules {
+		if matchPath(r.Pattern, p) {
+			return r, true
+		}
+	}
+	return CacheRule{}, false
+}
+
+// matchPath 判断路径是否匹配模式，"/**" 结尾时按前缀匹配
+func matchPath(pattern, p string) bool {
+	if pattern == "" || pattern == "/**" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return p == prefix || strings.HasPrefix(p, prefix+"/")
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
+
+// cacheKey 缓存键：URL 与代表调用方身份的请求头（Authorization、Cookie、透传的 context 请求头），
+// 避免不同用户、租户的响应串用；traceId 每次都不同，不参与
+func (c *Client) cacheKey(req *http.Request) string {
+	var b strings.Builder
+	b.WriteString(req.URL.String())
+	names := []string{"Authorization", "Cookie"}
+	perRequest, _ := req.Context().Value(ctxHeaderKey{}).([]ContextHeader)
+	for _, list := range [][]ContextHeader{c.ctxHeaders, perRequest} {
+		for _, m := range list {
+			names = append(names, m.Header)
+		}
+	}
+	for _, name := range names {
+		b.WriteString("\n")
+		b.WriteString(http.CanonicalHeaderKey(name))
+		b.WriteString(": ")
+		b.WriteString(strings.Join(req.Header.Values(name), ", "))
+	}
+	return b.String()
+}
+
+// get 读取缓存，Vary 列出的请求头与当前请求不一致时视为未命中
+func (rc *responseCache) get(key string, req *http.Request) *cacheEntry {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if e := rc.entries[key]; e != nil && e.matches(req) {
+		return e
+	}
+	return nil
+}
+
+// cacheable 判断响应能否缓存：Set-Cookie 属于单个用户，Vary: * 表示每个请求都可能不同
+func cacheable(resp *http.Response) bool {
+	if resp == nil || resp.StatusCode >= 400 || strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store") {
+		return false
+	}
+	if len(resp.Header.Values("Set-Cookie")) > 0 {
+		return false
+	}
+	for _, name := range varyHeaders(resp) {
+		if name == "*" {
+			return false
+		}
+	}
+	return true
+}
+
+// varyHeaders 解析响应的 Vary 请求头列表
+func varyHeaders(resp *http.Response) []string {
+	var names []string
+	for _, v := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
+
+// store 写入可缓存的响应，条目已满时先清理完全失效的条目，仍不足时淘汰最早写入的条目
+func (rc *responseCache) store(key string, rule CacheRule, req *http.Request, resp *http.Response, body []byte) {
+	if !cacheable(resp) {
+		return
+	}
+	e := &cacheEntry{
+		status:   resp.Status,
+		code:     resp.StatusCode,
+		proto:    resp.Proto,
+		header:   resp.Header.Clone(),
+		body:     bytes.Clone(body),
+		storedAt: time.Now(),
+	}
+	if names := varyHeaders(resp); len(names) > 0 {
+		e.vary = make(map[string]string, len(names))
+		for _, name := range names {
+			e.vary[name] = req.Header.Get(name)
+		}
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if _, ok := rc.entries[key]; !ok && len(rc.entries) >= rc.maxEntries {
+		rc.evictLocked(e.storedAt)
+	}
+	rc.entries[key] = e
+}
+
+// evictLocked 清理失效条目，仍满时淘汰最早写入的条目（调用方需持有锁）
+func (rc *responseCache) evictLocked(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for k, e := range rc.entries {
+		if now.Sub(e.storedAt) > rc.maxLifetime() {
+			delete(rc.entries, k)
+			continue
+		}
+		if oldestKey == "" || e.storedAt.Before(oldest) {
+			oldestKey, oldest = k, e.storedAt
+		}
+	}
+	if len(rc.entries) >= rc.maxEntries && oldestKey != "" {
+		delete(rc.entries, oldestKey)
+	}
+}
+
+// maxLifetime 所有规则中条目可能被使用的最长时间
+func (rc *responseCache) maxLifetime() time.Duration {
+	var max time.Duration
+	for _, r := range rc.rules {
+		d := r.TTL + r.StaleWhileRevalidate
+		if s := r.TTL + r.StaleIfError; s > d {
+			d = s
+		}
+		if d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+// startRefresh 标记 key 正在后台刷新，已在刷新时返回 false
+func (rc *responseCache) startRefresh(key string) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.refreshing[key] {
+		return false
+	}
+	rc.refreshing[key] = true
+	return true
+}
+
+// endRefresh 清除刷新标记
+func (rc *responseCache) endRefresh(key string) {
+	rc.mu.Lock()
+	delete(rc.refreshing, key)
+	rc.mu.Unlock()
+}
+
+// sendCached 按缓存规则处理 GET 请求：新鲜时返回缓存，处于 stale-while-revalidate 窗口时返回旧响应并后台刷新，
+// 否则请求上游，上游失败且处于 stale-if-error 窗口时返回旧响应
+func (c *Client) sendCached(req *http.Request, rule CacheRule) (*http.Response, []byte, error) {
+	key := c.cacheKey(req)
+	var entry *cacheEntry
+	if !strings.Contains(strings.ToLower(req.Header.Get("Cache-Control")), "no-cache") {
+		entry = c.cache.get(key, req)
+	}
+	var age time.Duration
+	if entry != nil {
+		age = time.Since(entry.storedAt)
+		if age < rule.TTL {
+			resp, body := entry.response(req, "HIT")
+			return resp, body, nil
+		}
+		if age < rule.TTL+rule.StaleWhileRevalidate {
+			if c.cache.startRefresh(key) {
+				// 后台刷新不受调用方取消影响，但保留 context 中的值（traceId 等）
+				bg := req.Clone(context.WithoutCancel(req.Context()))
+				go func() {
+					defer c.cache.endRefresh(key)
+					resp, body, err := c.sendRetry(bg)
+					if err == nil {
+						c.cache.store(key, rule, bg, resp, body)
+					}
+				}()
+			}
+			resp, body := entry.response(req, "STALE")
+			return resp, body, nil
+		}
+	}
+
+	resp, body, err := c.sendRetry(req)
+	if err == nil {
+		c.cache.store(key, rule, req, resp, body)
+		return resp, body, nil
+	}
+	if entry != nil && age < rule.TTL+rule.StaleIfError && upstreamFailed(req, resp, err) {
+		stale, staleBody := entry.response(req, "STALE")
+		return stale, staleBody, nil
+	}
+	return resp, body, err
+}
+
+// upstreamFailed 判断错误是否属于上游故障（网络错误或 5xx），调用方自己取消的请求不算
+func upstreamFailed(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode >= 500
+	}
+	return resp == nil
+}
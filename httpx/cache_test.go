@@ -0,0 +1,199 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newCountingServer 每次请求返回递增的版本号，fail 为 true 时返回 503
+func newCountingServer(hits *atomic.Int32, fail *atomic.Bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		if fail != nil && fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Query().Get("nostore") != "" {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		_, _ = w.Write([]byte("v" + strconv.Itoa(int(n))))
+	}))
+}
+
+func TestClient_CacheFresh(t *testing.T) {
+	var hits atomic.Int32
+	srv := newCountingServer(&hits, nil)
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithResponseCache(0, CacheRule{Pattern: "/api/**", TTL: time.Minute}))
+	ctx := context.Background()
+
+	_, body, _ := c.Get(ctx, "/api/items/1", nil, nil)
+	resp, cached, err := c.Get(ctx, "/api/items/1", nil, nil)
+	if err != nil || string(body) != "v1" || string(cached) != "v1" || resp.Header.Get(CacheStatusHeader) != "HIT" {
+		t.Fatalf("expected cache hit: %q %q %v %v", body, cached, resp.Header, err)
+	}
+	// 不同身份、未匹配规则、no-store 与 no-cache 均不使用缓存
+	_, body, _ = c.Get(ctx, "/api/items/1", nil, http.Header{"Authorization": {"Bearer other"}})
+	if string(body) != "v2" {
+		t.Errorf("authorization should be part of cache key, got %q", body)
+	}
+	_, _, _ = c.Get(ctx, "/other", nil, nil)
+	_, _, _ = c.Get(ctx, "/other", nil, nil)
+	_, _, _ = c.Get(ctx, "/api/x", map[string]string{"nostore": "1"}, nil)
+	_, _, _ = c.Get(ctx, "/api/x", map[string]string{"nostore": "1"}, nil)
+	_, body, _ = c.Get(ctx, "/api/items/1", nil, http.Header{"Cache-Control": {"no-cache"}})
+	if hits.Load() != 7 || string(body) != "v7" {
+		t.Errorf("hits = %d, body = %q", hits.Load(), body)
+	}
+	// no-cache 请求的结果会刷新缓存
+	if _, body, _ = c.Get(ctx, "/api/items/1", nil, nil); string(body) != "v7" {
+		t.Errorf("expected refreshed entry, got %q", body)
+	}
+}
+
+func TestClient_CacheStaleWhileRevalidate(t *testing.T) {
+	var hits atomic.Int32
+	srv := newCountingServer(&hits, nil)
+	defer srv.Close()
+
+	c := NewClient(WithResponseCache(0, CacheRule{TTL: 20 * time.Millisecond, StaleWhileRevalidate: time.Minute}))
+	ctx := context.Background()
+	_, _, _ = c.Get(ctx, srv.URL, nil, nil)
+	time.Sleep(30 * time.Millisecond)
+
+	resp, body, err := c.Get(ctx, srv.URL, nil, nil)
+	if err != nil || string(body) != "v1" || resp.Header.Get(CacheStatusHeader) != "STALE" {
+		t.Fatalf("expected stale response: %q %v", body, err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, body, _ = c.Get(ctx, srv.URL, nil, nil); string(body) == "v2" {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if string(body) != "v2" || hits.Load() != 2 {
+		t.Fatalf("background refresh not applied: body=%q hits=%d", body, hits.Load())
+	}
+}
+
+func TestClient_CacheStaleIfError(t *testing.T) {
+	var hits atomic.Int32
+	var fail atomic.Bool
+	srv := newCountingServer(&hits, &fail)
+	defer srv.Close()
+
+	c := NewClient(WithResponseCache(0, CacheRule{TTL: 10 * time.Millisecond, StaleIfError: time.Minute}))
+	ctx := context.Background()
+	_, _, _ = c.Get(ctx, srv.URL, nil, nil)
+	time.Sleep(20 * time.Millisecond)
+
+	fail.Store(true)
+	resp, body, err := c.Get(ctx, srv.URL, nil, nil)
+	if err != nil || string(body) != "v1" || resp.Header.Get(CacheStatusHeader) != "STALE" {
+		t.Fatalf("expected stale response on error: %q %v", body, err)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("upstream should be tried first, hits = %d", hits.Load())
+	}
+
+	// 没有缓存时错误照常返回
+	if _, _, err := c.Get(ctx, srv.URL+"/other", nil, nil); err == nil {
+		t.Error("expected error without cached entry")
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"", "/a/b", true},
+		{"/**", "/", true},
+		{"/api/**", "/api", true},
+		{"/api/**", "/api/v1/items", true},
+		{"/api/**", "/apix", false},
+		{"/api/*/items", "/api/v1/items", true},
+		{"/api/*", "/api/v1/items", false},
+	}
+	for _, tt := range tests {
+		if got := matchPath(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchPath(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestResponseCache_Evict(t *testing.T) {
+	rc := newResponseCache(2, []CacheRule{{TTL: time.Minute}})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	for _, k := range []string{"a", "b", "c"} {
+		rc.store(k, rc.rules[0], req, resp, []byte(k))
+		time.Sleep(time.Millisecond)
+	}
+	if rc.get("a", req) != nil || rc.get("b", req) == nil || rc.get("c", req) == nil {
+		t.Error("oldest entry should be evicted")
+	}
+}
+
+type cacheTenantKey struct{}
+
+func TestClient_CacheIdentity(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		switch r.URL.Path {
+		case "/api/vary":
+			w.Header().Set("Vary", "Accept-Language")
+		case "/api/varyall":
+			w.Header().Set("Vary", "*")
+		case "/api/cookie":
+			w.Header().Set("Set-Cookie", "sid=1")
+		}
+		_, _ = w.Write([]byte(r.Header.Get("X-Tenant-Id") + r.Header.Get("Accept-Language") + strconv.Itoa(int(n))))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithContextHeader(cacheTenantKey{}, "X-Tenant-Id"),
+		WithResponseCache(0, CacheRule{Pattern: "/api/**", TTL: time.Minute}))
+	t1 := context.WithValue(context.Background(), cacheTenantKey{}, "t1")
+	t2 := context.WithValue(context.Background(), cacheTenantKey{}, "t2")
+
+	get := func(ctx context.Context, path string, h http.Header) string {
+		_, body, err := c.Get(ctx, path, nil, h)
+		if err != nil {
+			t.Fatalf("Get %s: %v", path, err)
+		}
+		return string(body)
+	}
+	if a, b := get(t1, "/api/items", nil), get(t2, "/api/items", nil); a != "t11" || b != "t22" {
+		t.Errorf("tenants should not share cache: %q %q", a, b)
+	}
+	if got := get(t1, "/api/items", nil); got != "t11" {
+		t.Errorf("expected hit for same tenant, got %q", got)
+	}
+	if a, b := get(t1, "/api/items", http.Header{"Cookie": {"sid=a"}}), get(t1, "/api/items", http.Header{"Cookie": {"sid=b"}}); a == b {
+		t.Errorf("cookie should be part of cache key: %q %q", a, b)
+	}
+
+	zh, en := http.Header{"Accept-Language": {"zh"}}, http.Header{"Accept-Language": {"en"}}
+	first := get(t1, "/api/vary", zh)
+	if got := get(t1, "/api/vary", zh); got != first {
+		t.Errorf("expected hit for same Vary value: %q %q", first, got)
+	}
+	if got := get(t1, "/api/vary", en); got == first {
+		t.Errorf("Vary header mismatch should miss: %q", got)
+	}
+
+	for _, p := range []string{"/api/varyall", "/api/cookie"} {
+		if a, b := get(t1, p, nil), get(t1, p, nil); a == b {
+			t.Errorf("%s should not be cached: %q %q", p, a, b)
+		}
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/qingfeng-studio/go-utils/utils/metrics"
 )
 
 // ClientOptions 客户端构建时的可选配置
@@ -20,6 +22,35 @@ type ClientOptions struct {
 	Timeout   time.Duration     // 请求超时时间，用于控制长请求或防止阻塞
 	Headers   http.Header       // 默认请求头，每次请求都会附加，可用于统一添加认证、User-Agent 等
 	Transport http.RoundTripper // 自定义 HTTP Transport，用于代理、TLS 配置、连接复用等
+
+	HostHeaders []HostHeader // 按 host 模式限定作用范围的默认请求头
+	HeaderScope []string     // Headers 的作用范围（host 模式），为空表示对所有请求生效
+
+	ContextHeaders []ContextHeader // context 值到请求头的映射
+	TraceIDHeader  string          // 透传 traceId 的请求头，为空表示不透传
+
+	BandwidthLimit int64 // 上传、下载各自的带宽上限（字节/秒），0 表示不限速
+
+	MaxDecompressedSize int64   // 响应解压后的大小上限（字节），0 表示不限制
+	MaxCompressionRatio float64 // 响应的压缩比上限，0 表示不限制
+
+	MaxRetries           int           // 幂等请求的最大重试次数，0 表示不重试
+	RetryBaseDelay       time.Duration // 首次重试的等待时间，之后指数增长
+	RetryMaxDelay        time.Duration // 单次重试等待的上限（含 Retry-After），默认 10s
+	RetryBudgetRatio     float64       // 重试次数占请求数的上限比例，默认 0.1，< 0 表示不限制
+	RetryBudgetMinPerSec int           // 重试预算的保底值（次/秒），默认 1
+	MaxInFlight          int           // 同时进行中的请求数上限，0 表示不限制
+
+	Name string // 客户端名称，写入 RequestInfo 与日志字段
+
+	CacheRules   []CacheRule // GET 响应缓存规则，为空表示不缓存
+	CacheEntries int         // 响应缓存的最大条目数，默认 1000
+
+	DigestUsername string // 设置后启用 Digest 认证
+	DigestPassword string
+
+	Metrics *metrics.Registry // 记录请求次数与耗时的注册表，nil 表示不记录，见 WithMetrics
+	Tracing bool              // 为每次请求尝试创建 span 并透传 traceparent，见 WithTracing
 }
 
 // Option 用于配置 ClientOptions 的函数式选项
@@ -57,6 +88,19 @@ type Client struct {
 	httpClient     *http.Client // 内部 http.Client 实例，用于发送请求
 	baseURL        string       // 基础 URL，用于拼接相对路径
 	defaultHeaders http.Header  // 默认请求头，供每次请求使用，可被 per-request headers 覆盖
+	hostHeaders    []HostHeader // 按 host 限定的默认请求头
+	headerScope    []string     // defaultHeaders 的作用范围
+
+	ctxHeaders    []ContextHeader // context 值到请求头的映射
+	traceIDHeader string          // 透传 traceId 的请求头
+
+	retry    *retryPolicy           // 重试策略，nil 表示不重试
+	maxDelay time.Duration          // 单次重试等待的上限（含 Retry-After），分页遇到 429 时同样适用
+	inFlight chan struct{}          // 并发名额，nil 表示不限制
+	name     string                 // 客户端名称
+	cache    *responseCache         // 响应缓存，nil 表示不缓存
+	metrics  *metrics.ClientMetrics // 请求指标，nil 表示不记录
+	tracing  bool                   // 是否创建 span
 }
 
 // NewClient 根据可选项创建 Client 实例
@@ -72,12 +116,42 @@ func NewClient(options ...Option) *Client {
 	if opts.Transport != nil {
 		hc.Transport = opts.Transport
 	}
+	if opts.BandwidthLimit > 0 {
+		hc.Transport = newThrottleTransport(hc.Transport, opts.BandwidthLimit)
+	}
+	if opts.MaxDecompressedSize > 0 || opts.MaxCompressionRatio > 0 {
+		// 位于限速之外，限速按实际传输的压缩字节计算
+		hc.Transport = newDecompressTransport(hc.Transport, opts.MaxDecompressedSize, opts.MaxCompressionRatio)
+	}
+	if opts.DigestUsername != "" {
+		hc.Transport = NewDigestTransport(hc.Transport, opts.DigestUsername, opts.DigestPassword)
+	}
 
-	return &Client{
+	c := &Client{
 		httpClient:     hc,
 		baseURL:        opts.BaseURL,
 		defaultHeaders: cloneHeader(opts.Headers),
+		hostHeaders:    opts.HostHeaders,
+		headerScope:    opts.HeaderScope,
+		ctxHeaders:     opts.ContextHeaders,
+		traceIDHeader:  opts.TraceIDHeader,
+		retry:          newRetryPolicy(opts),
+		maxDelay:       opts.RetryMaxDelay,
+		name:           opts.Name,
+		cache:          newResponseCache(opts.CacheEntries, opts.CacheRules),
+		tracing:        opts.Tracing,
+	}
+	hc.CheckRedirect = c.checkRedirect
+	if c.maxDelay <= 0 {
+		c.maxDelay = maxRetryDelay
+	}
+	if opts.MaxInFlight > 0 {
+		c.inFlight = make(chan struct{}, opts.MaxInFlight)
 	}
+	if opts.Metrics != nil {
+		c.metrics = newClientMetrics(opts.Metrics)
+	}
+	return c
 }
 
 // Get 发送 GET 请求，用于获取资源，支持 query 参数和自定义 headers
@@ -118,34 +192,99 @@ func (c *Client) Patch(ctx context.Context, path string, body []byte, contentTyp
 // do 执行 HTTP 请求核心逻辑，内部方法
 // 实用场景: 所有 HTTP 方法均调用此方法，实现统一的请求逻辑和错误处理
 func (c *Client) do(ctx context.Context, method, path string, body io.Reader, headers http.Header, query map[string]string, contentType string) (*http.Response, []byte, error) {
+	req, err := c.newRequest(ctx, method, path, body, headers, query, contentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.send(req)
+}
+
+// newRequest 拼接 URL、合并请求头并创建请求
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader, headers http.Header, query map[string]string, contentType string) (*http.Request, error) {
 	if ctx == nil {
-		return nil, nil, errors.New("context must not be nil")
+		return nil, errors.New("context must not be nil")
 	}
 
 	fullURL, err := c.resolveURL(path, query) // 拼接完整 URL
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, fullURL, body) // 创建请求
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	// Merge headers: defaults first, then per-request overrides
-	for k, vs := range c.defaultHeaders {
-		for _, v := range vs {
-			req.Header.Add(k, v)
+	// Merge headers: defaults first (scoped by host), then per-request overrides
+	c.applyDefaultHeaders(req)
+	addHeaders(req.Header, headers)
+	c.applyContextHeaders(req)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
+
+// send 发送请求并读取完整响应体，状态码 >= 400 时返回 *StatusError；匹配缓存规则的 GET 请求先查缓存
+func (c *Client) send(req *http.Request) (*http.Response, []byte, error) {
+	if c.cache != nil {
+		if rule, ok := c.cache.match(req); ok {
+			return c.sendCached(req, rule)
 		}
 	}
-	for k, vs := range headers {
-		for _, v := range vs {
-			req.Header.Add(k, v)
+	return c.sendRetry(req)
+}
+
+// sendRetry 发送请求，开启重试时按策略与预算重试，每次尝试的请求 context 都带有 RequestInfo
+func (c *Client) sendRetry(req *http.Request) (*http.Response, []byte, error) {
+	if c.retry == nil {
+		return c.sendOnce(c.withRequestInfo(req, 1))
+	}
+	if c.retry.budget != nil {
+		c.retry.budget.request(time.Now())
+	}
+	for attempt := 0; ; attempt++ {
+		resp, body, err := c.sendOnce(c.withRequestInfo(req, attempt+1))
+		if !c.retry.retryable(req, resp, err, attempt) {
+			return resp, body, err
+		}
+		if c.retry.budget != nil && !c.retry.budget.withdraw(time.Now()) {
+			return resp, body, err
+		}
+		if serr := sleepCtx(req.Context(), c.retry.delay(resp, attempt)); serr != nil {
+			return resp, body, err
+		}
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, nil, err
+			}
 		}
 	}
-	if contentType != "" {
-		req.Header.Set("Content-Type", contentType)
+}
+
+// sendOnce 发送一次请求，开启指标时记录本次尝试的结果与耗时，开启链路追踪时为本次尝试创建 span
+func (c *Client) sendOnce(req *http.Request) (resp *http.Response, body []byte, err error) {
+	if c.tracing {
+		var end func(*http.Response, error)
+		req, end = startSpan(req)
+		defer func() { end(resp, err) }()
+	}
+	if c.metrics == nil {
+		return c.sendAttempt(req)
+	}
+	start := time.Now()
+	resp, body, err = c.sendAttempt(req)
+	c.observe(req, err, time.Since(start))
+	return resp, body, err
+}
+
+// sendAttempt 在并发名额内发送一次请求并读取完整响应体
+func (c *Client) sendAttempt(req *http.Request) (*http.Response, []byte, error) {
+	release, err := c.acquire(req.Context())
+	if err != nil {
+		return nil, nil, err
 	}
+	defer release()
 
 	resp, err := c.httpClient.Do(req) // 执行请求
 	if err != nil {
@@ -158,11 +297,24 @@ func (c *Client) do(ctx context.Context, method, path string, body io.Reader, he
 		return resp, nil, err
 	}
 	if resp.StatusCode >= 400 {
-		return resp, respBody, fmt.Errorf("http %s %s failed: status=%d body=%s", method, fullURL, resp.StatusCode, truncate(respBody, 512))
+		return resp, respBody, &StatusError{Method: req.Method, URL: req.URL.String(), StatusCode: resp.StatusCode, Body: respBody}
 	}
 	return resp, respBody, nil
 }
 
+// StatusError 响应状态码 >= 400 时返回的错误，可通过 errors.As 获取状态码与响应体
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       []byte
+}
+
+// Error 实现 error 接口
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("http %s %s failed: status=%d body=%s", e.Method, e.URL, e.StatusCode, truncate(e.Body, 512))
+}
+
 // resolveURL 解析相对路径或绝对 URL，并拼接 query 参数
 func (c *Client) resolveURL(path string, query map[string]string) (string, error) {
 	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
@@ -0,0 +1,77 @@
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+)
+
+// DefaultTraceIDHeader 透传 traceId 时默认使用的请求头
+const DefaultTraceIDHeader = "X-Trace-Id"
+
+// ContextHeader context 中的值到请求头的映射
+type ContextHeader struct {
+	Key    interface{} // context key，例如 "tenantId" 或自定义的私有类型
+	Header string      // 请求头名称，例如 X-Tenant-Id
+}
+
+// WithContextHeader 把 context 中 key 对应的值作为请求头透传到每次出站请求（可多次调用累加）
+// 值按 fmt.Sprint 转为字符串（与 logger 读取 traceId 的方式一致），值为空时不设置；
+// 请求已带有同名请求头（per-request headers 或默认请求头）时不覆盖；
+// 作用范围与 WithHeader 相同，受 WithDefaultHeaderScope 限制，避免把租户、用户信息发给外部站点
+func WithContextHeader(key interface{}, header string) Option {
+	return func(o *ClientOptions) {
+		o.ContextHeaders = append(o.ContextHeaders, ContextHeader{Key: key, Header: header})
+	}
+}
+
+// WithTraceIDHeader 把 logger 使用的 traceId 透传到请求头，header 为空时使用 X-Trace-Id
+func WithTraceIDHeader(header string) Option {
+	return func(o *ClientOptions) {
+		if header == "" {
+			header = DefaultTraceIDHeader
+		}
+		o.TraceIDHeader = header
+	}
+}
+
+// ctxHeaderKey 存放单次调用链额外映射的 context key
+type ctxHeaderKey struct{}
+
+// ContextWithHeaderMapping 为使用该 context 的请求追加 context key -> 请求头映射，
+// 与客户端全局映射合并，适用于只有部分调用需要透传某个值的场景
+func ContextWithHeaderMapping(ctx context.Context, key interface{}, header string) context.Context {
+	prev, _ := ctx.Value(ctxHeaderKey{}).([]ContextHeader)
+	mappings := make([]ContextHeader, 0, len(prev)+1)
+	mappings = append(mappings, prev...)
+	mappings = append(mappings, ContextHeader{Key: key, Header: header})
+	return context.WithValue(ctx, ctxHeaderKey{}, mappings)
+}
+
+// applyContextHeaders 按全局与 context 中的映射设置请求头
+func (c *Client) applyContextHeaders(req *http.Request) {
+	if len(c.headerScope) > 0 && !matchAny(c.headerScope, req.URL.Hostname()) {
+		return
+	}
+	ctx := req.Context()
+	if c.traceIDHeader != "" && req.Header.Get(c.traceIDHeader) == "" {
+		if traceID := logger.TraceIDFromContext(ctx); traceID != "" {
+			req.Header.Set(c.traceIDHeader, traceID)
+		}
+	}
+	perRequest, _ := ctx.Value(ctxHeaderKey{}).([]ContextHeader)
+	for _, list := range [][]ContextHeader{c.ctxHeaders, perRequest} {
+		for _, m := range list {
+			if req.Header.Get(m.Header) != "" {
+				continue
+			}
+			if v := ctx.Value(m.Key); v != nil {
+				if s := fmt.Sprint(v); s != "" {
+					req.Header.Set(m.Header, s)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+type tenantKey struct{}
+
+func TestClient_ContextHeaders(t *testing.T) {
+	srv := newEchoServer()
+	defer srv.Close()
+
+	c := NewClient(
+		WithBaseURL(srv.URL),
+		WithContextHeader(tenantKey{}, "X-Tenant-Id"),
+		WithContextHeader("userId", "X-User-Id"),
+		WithTraceIDHeader(""),
+	)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "t1")
+	ctx = context.WithValue(ctx, "userId", 42)
+	ctx = context.WithValue(ctx, "traceId", "trace-1")
+	ctx = context.WithValue(ctx, "locale", "zh-CN")
+	ctx = ContextWithHeaderMapping(ctx, "locale", "Accept-Language")
+
+	headers := http.Header{}
+	headers.Set("X-User-Id", "explicit")
+	_, body, err := c.Get(ctx, "/ctx", nil, headers)
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	var payload echoPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got := payload.Header.Get("X-Tenant-Id"); got != "t1" {
+		t.Errorf("X-Tenant-Id = %q", got)
+	}
+	if got := payload.Header.Get("X-User-Id"); got != "explicit" {
+		t.Errorf("per-request header should win, got %q", got)
+	}
+	if got := payload.Header.Get(DefaultTraceIDHeader); got != "trace-1" {
+		t.Errorf("trace header = %q", got)
+	}
+	if got := payload.Header.Get("Accept-Language"); got != "zh-CN" {
+		t.Errorf("per-request mapping = %q", got)
+	}
+
+	// 不在作用范围内的 host 不透传
+	scoped := NewClient(WithBaseURL(srv.URL), WithContextHeader(tenantKey{}, "X-Tenant-Id"), WithDefaultHeaderScope("*.internal"))
+	_, body, _ = scoped.Get(ctx, "/ctx", nil, nil)
+	payload = echoPayload{}
+	_ = json.Unmarshal(body, &payload)
+	if payload.Header.Get("X-Tenant-Id") != "" {
+		t.Error("context header leaked outside scope")
+	}
+}
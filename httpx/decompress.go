@@ -0,0 +1,175 @@
+package httpx
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ratioCheckThreshold 解压量超过该值后才检查压缩比，避免小而高度可压缩的正常响应被误判
+const ratioCheckThreshold = 1 << 20
+
+// WithDecompressionLimit 由客户端自行解压 gzip/deflate 响应，并限制解压后的大小（字节）与压缩比，
+// 超出时读取响应体返回 *DecompressionError，防止 zip 炸弹式响应耗尽内存；
+// maxSize <= 0 表示不限制大小，maxRatio <= 0 表示不限制压缩比。压缩比在解压超过 1MB 后才检查
+// 注意：请求已显式设置 Accept-Encoding 时不做处理，响应体保持原样
+// 使用示例：
+//
+//	c := httpx.NewClient(httpx.WithDecompressionLimit(64<<20, 100))
+//	_, _, err := c.Get(ctx, url, nil, nil)
+//	var de *httpx.DecompressionError
+//	if errors.As(err, &de) { ... }
+func WithDecompressionLimit(maxSize int64, maxRatio float64) Option {
+	return func(o *ClientOptions) {
+		o.MaxDecompressedSize = maxSize
+		o.MaxCompressionRatio = maxRatio
+	}
+}
+
+// DecompressionError 解压后的响应体超出大小或压缩比限制
+type DecompressionError struct {
+	URL          string
+	Encoding     string
+	Compressed   int64   // 出错时已读取的压缩字节数
+	Decompressed int64   // 出错时已解压的字节数
+	MaxSize      int64   // 大小上限，0 表示不限制
+	MaxRatio     float64 // 压缩比上限，0 表示不限制
+}
+
+// Error 实现 error 接口
+func (e *DecompressionError) Error() string {
+	return fmt.Sprintf("httpx: decompressed %s response from %s exceeds limit: compressed=%d decompressed=%d (max size=%d, max ratio=%g)",
+		e.Encoding, e.URL, e.Compressed, e.Decompressed, e.MaxSize, e.MaxRatio)
+}
+
+// decompressTransport 声明支持 gzip/deflate 并自行解压响应，解压过程中检查限制
+// （http.Transport 只在自己添加 Accept-Encoding 时透明解压，且不做任何限制）
+type decompressTransport struct {
+	base     http.RoundTripper
+	maxSize  int64
+	maxRatio float64
+}
+
+// newDecompressTransport 包装 base，base 为 nil 时使用 http.DefaultTransport
+func newDecompressTransport(base http.RoundTripper, maxSize int64, maxRatio float64) *decompressTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &decompressTransport{base: base, maxSize: maxSize, maxRatio: maxRatio}
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *decompressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") != "" || req.Method == http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+	// RoundTripper 不应修改原请求，克隆后设置请求头
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding != "gzip" && encoding != "deflate" || resp.Body == nil || resp.Body == http.NoBody {
+		return resp, nil
+	}
+	resp.Body = &limitedDecompressBody{
+		raw:   resp.Body,
+		count: &countingReader{r: resp.Body},
+		err:   DecompressionError{URL: req.URL.String(), Encoding: encoding, MaxSize: t.maxSize, MaxRatio: t.maxRatio},
+	}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// countingReader 统计已读取的字节数
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read 实现 io.Reader
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// limitedDecompressBody 惰性创建解压器（gzip 头在首次读取时才解析），按限制读取解压后的数据
+type limitedDecompressBody struct {
+	raw   io.ReadCloser
+	count *countingReader
+	zr    io.Reader
+	out   int64
+	err   DecompressionError // 出错时返回的错误模板
+}
+
+// Read 实现 io.Reader
+func (b *limitedDecompressBody) Read(p []byte) (int, error) {
+	if b.zr == nil {
+		if b.err.Encoding == "gzip" {
+			zr, err := gzip.NewReader(b.count)
+			if err != nil {
+				return 0, err
+			}
+			b.zr = zr
+		} else {
+			zr, err := newDeflateReader(b.count)
+			if err != nil {
+				return 0, err
+			}
+			b.zr = zr
+		}
+	}
+	if b.err.MaxSize > 0 && int64(len(p)) > b.err.MaxSize-b.out+1 {
+		// 多读 1 字节用于判断是否超限
+		p = p[:b.err.MaxSize-b.out+1]
+	}
+	n, err := b.zr.Read(p)
+	b.out += int64(n)
+	if b.exceeded() {
+		e := b.err
+		e.Compressed, e.Decompressed = b.count.n, b.out
+		return n, &e
+	}
+	return n, err
+}
+
+// newDeflateReader Content-Encoding: deflate 按 RFC 9110 为 zlib 格式（带 2 字节头与 Adler-32 校验），
+// 部分服务端实际发送不带头的原始 deflate 流，头部校验不通过时按原始流解压
+func newDeflateReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if h, err := br.Peek(2); err == nil && isZlibHeader(h[0], h[1]) {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+// isZlibHeader 判断 CMF/FLG 两字节是否为合法 zlib 头：压缩方法为 deflate 且校验位满足 31 的倍数
+func isZlibHeader(cmf, flg byte) bool {
+	return cmf&0x0f == 8 && cmf>>4 <= 7 && (uint16(cmf)<<8|uint16(flg))%31 == 0
+}
+
+// exceeded 是否超出大小或压缩比限制
+func (b *limitedDecompressBody) exceeded() bool {
+	if b.err.MaxSize > 0 && b.out > b.err.MaxSize {
+		return true
+	}
+	return b.err.MaxRatio > 0 && b.out > ratioCheckThreshold && b.count.n > 0 &&
+		float64(b.out)/float64(b.count.n) > b.err.MaxRatio
+}
+
+// Close 关闭底层 body
+func (b *limitedDecompressBody) Close() error {
+	return b.raw.Close()
+}
@@ -0,0 +1,111 @@
+package httpx
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newCompressedServer 返回按 encoding 压缩 payload 的服务，并记录收到的 Accept-Encoding；
+// encoding 为 raw-deflate 时以 Content-Encoding: deflate 发送不带 zlib 头的原始 deflate 流
+func newCompressedServer(t *testing.T, encoding string, payload []byte, acceptEncoding *string) *httptest.Server {
+	t.Helper()
+	var buf bytes.Buffer
+	switch encoding {
+	case "gzip":
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write(payload)
+		_ = zw.Close()
+	case "deflate":
+		zw, _ := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+		_, _ = zw.Write(payload)
+		_ = zw.Close()
+	case "raw-deflate":
+		zw, _ := flate.NewWriter(&buf, flate.BestCompression)
+		_, _ = zw.Write(payload)
+		_ = zw.Close()
+		encoding = "deflate"
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if acceptEncoding != nil {
+			*acceptEncoding = r.Header.Get("Accept-Encoding")
+		}
+		w.Header().Set("Content-Encoding", encoding)
+		_, _ = w.Write(buf.Bytes())
+	}))
+}
+
+func TestClient_DecompressionWithinLimit(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate", "raw-deflate"} {
+		t.Run(encoding, func(t *testing.T) {
+			payload := bytes.Repeat([]byte("hello "), 1000)
+			var accept string
+			srv := newCompressedServer(t, encoding, payload, &accept)
+			defer srv.Close()
+
+			c := NewClient(WithDecompressionLimit(1<<20, 100))
+			resp, body, err := c.Get(context.Background(), srv.URL, nil, nil)
+			if err != nil {
+				t.Fatalf("Get error: %v", err)
+			}
+			if !bytes.Equal(body, payload) {
+				t.Fatalf("body mismatch: got %d bytes", len(body))
+			}
+			if accept != "gzip, deflate" || resp.Header.Get("Content-Encoding") != "" || !resp.Uncompressed {
+				t.Errorf("unexpected headers: accept=%q resp=%v", accept, resp.Header)
+			}
+		})
+	}
+}
+
+func TestClient_DecompressionBomb(t *testing.T) {
+	payload := make([]byte, 8<<20) // 8MB 的 0，压缩后约 8KB
+	srv := newCompressedServer(t, "gzip", payload, nil)
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		maxSize  int64
+		maxRatio float64
+	}{
+		{"size", 4 << 20, 0},
+		{"ratio", 0, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(WithDecompressionLimit(tt.maxSize, tt.maxRatio))
+			_, _, err := c.Get(context.Background(), srv.URL, nil, nil)
+			var de *DecompressionError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecompressionError, got %v", err)
+			}
+			if de.Encoding != "gzip" || de.Compressed == 0 || de.Decompressed >= int64(len(payload)) {
+				t.Errorf("unexpected error detail: %+v", de)
+			}
+			if tt.maxSize > 0 && de.Decompressed != tt.maxSize+1 {
+				t.Errorf("decompressed = %d, want %d", de.Decompressed, tt.maxSize+1)
+			}
+		})
+	}
+}
+
+func TestClient_DecompressionExplicitAcceptEncoding(t *testing.T) {
+	srv := newCompressedServer(t, "gzip", make([]byte, 8<<20), nil)
+	defer srv.Close()
+
+	// 调用方自行处理压缩时原样返回
+	c := NewClient(WithDecompressionLimit(1024, 0))
+	resp, body, err := c.Get(context.Background(), srv.URL, nil, http.Header{"Accept-Encoding": {"gzip"}})
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if resp.Header.Get("Content-Encoding") != "gzip" || len(body) > 1<<20 {
+		t.Errorf("response should stay compressed: %v, %d bytes", resp.Header, len(body))
+	}
+}
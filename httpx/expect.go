@@ -0,0 +1,180 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// 状态码分类，可传给 ExpectStatus / Expectation.Status 表示整类状态码
+const (
+	Status1xx = 1
+	Status2xx = 2
+	Status3xx = 3
+	Status4xx = 4
+	Status5xx = 5
+)
+
+// ExpectationError 响应不满足预期时返回的错误，包含请求信息与响应体片段，便于排查
+type ExpectationError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Reason     string // 未满足的预期描述
+	Body       []byte
+}
+
+// Error 实现 error 接口
+func (e *ExpectationError) Error() string {
+	return fmt.Sprintf("http %s %s expectation failed: %s (status=%d body=%s)", e.Method, e.URL, e.Reason, e.StatusCode, truncate(e.Body, 512))
+}
+
+// Validator 响应校验函数，返回的 error 会被包装为 *ExpectationError
+type Validator func(resp *http.Response, body []byte) error
+
+// ExpectStatus 校验状态码，参数可以是具体状态码，也可以是 Status2xx 这类分类
+func ExpectStatus(codes ...int) Validator {
+	return func(resp *http.Response, _ []byte) error {
+		for _, c := range codes {
+			if c == resp.StatusCode || (c >= Status1xx && c <= Status5xx && resp.StatusCode/100 == c) {
+				return nil
+			}
+		}
+		return fmt.Errorf("unexpected status %d, want %s", resp.StatusCode, formatCodes(codes))
+	}
+}
+
+// ExpectHeader 校验响应头，value 为空时只要求存在；Content-Type 等带参数的头按前缀匹配媒体类型
+func ExpectHeader(key, value string) Validator {
+	return func(resp *http.Response, _ []byte) error {
+		got := resp.Header.Get(key)
+		if got == "" {
+			return fmt.Errorf("missing header %s", key)
+		}
+		if value == "" || got == value {
+			return nil
+		}
+		if media := strings.TrimSpace(strings.Split(got, ";")[0]); strings.EqualFold(media, value) {
+			return nil
+		}
+		return fmt.Errorf("header %s = %q, want %q", key, got, value)
+	}
+}
+
+// ExpectJSONSchema 使用 JSON Schema（支持常用子集，见 ValidateJSONSchema）校验响应体
+func ExpectJSONSchema(schema []byte) Validator {
+	return func(_ *http.Response, body []byte) error {
+		return ValidateJSONSchema(schema, body)
+	}
+}
+
+// Expectation 链式响应校验器
+// 实用场景: 调用方不再重复编写状态码、响应头、响应结构的判断代码
+// 使用示例：
+//
+//	var out User
+//	err := httpx.Check(c.Get(ctx, "/users/1", nil, nil)).
+//		Status(httpx.Status2xx).
+//		Header("Content-Type", "application/json").
+//		JSON(&out)
+type Expectation struct {
+	resp       *http.Response
+	body       []byte
+	err        error
+	statusErr  *StatusError // 状态码 >= 400 的错误，交由 Status 校验决定是否保留
+	statusSeen bool
+}
+
+// Check 包装请求结果，可直接接收 Get/Post 等方法的三个返回值
+func Check(resp *http.Response, body []byte, err error) *Expectation {
+	e := &Expectation{resp: resp, body: body}
+	var se *StatusError
+	if errors.As(err, &se) && resp != nil {
+		e.statusErr = se
+	} else {
+		e.err = err
+	}
+	return e
+}
+
+// Status 校验状态码；显式声明的状态码（如 404）会覆盖默认的 >= 400 错误
+func (e *Expectation) Status(codes ...int) *Expectation {
+	e.statusSeen = true
+	return e.Validate(ExpectStatus(codes...))
+}
+
+// Header 校验响应头
+func (e *Expectation) Header(key, value string) *Expectation {
+	return e.Validate(ExpectHeader(key, value))
+}
+
+// JSONSchema 校验响应体结构
+func (e *Expectation) JSONSchema(schema []byte) *Expectation {
+	return e.Validate(ExpectJSONSchema(schema))
+}
+
+// Validate 执行自定义校验，已有错误时跳过
+func (e *Expectation) Validate(validators ...Validator) *Expectation {
+	if e.err != nil || e.resp == nil {
+		return e
+	}
+	for _, v := range validators {
+		if err := v(e.resp, e.body); err != nil {
+			e.err = e.wrap(err)
+			return e
+		}
+	}
+	return e
+}
+
+// Err 返回第一个未满足的预期；未校验状态码时保留原始的 >= 400 错误
+func (e *Expectation) Err() error {
+	if e.err != nil {
+		return e.err
+	}
+	if e.statusErr != nil && !e.statusSeen {
+		return e.statusErr
+	}
+	return nil
+}
+
+// JSON 校验通过后将响应体解析到 out
+func (e *Expectation) JSON(out interface{}) error {
+	if err := e.Err(); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(e.body, out); err != nil {
+		return e.wrap(fmt.Errorf("decode json: %w", err))
+	}
+	return nil
+}
+
+// Body 返回响应与响应体，便于校验后继续使用
+func (e *Expectation) Body() (*http.Response, []byte, error) {
+	return e.resp, e.body, e.Err()
+}
+
+// wrap 将校验错误包装为 *ExpectationError
+func (e *Expectation) wrap(err error) error {
+	ee := &ExpectationError{StatusCode: e.resp.StatusCode, Reason: err.Error(), Body: e.body}
+	if e.resp.Request != nil {
+		ee.Method = e.resp.Request.Method
+		ee.URL = e.resp.Request.URL.String()
+	}
+	return ee
+}
+
+// formatCodes 格式化期望的状态码列表
+func formatCodes(codes []int) string {
+	parts := make([]string, 0, len(codes))
+	for _, c := range codes {
+		if c >= Status1xx && c <= Status5xx {
+			parts = append(parts, fmt.Sprintf("%dxx", c))
+		} else {
+			parts = append(parts, fmt.Sprint(c))
+		}
+	}
+	return strings.Join(parts, "|")
+}
@@ -0,0 +1,123 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const userSchema = `{
+	"type": "object",
+	"required": ["id", "name"],
+	"properties": {
+		"id": {"type": "integer", "minimum": 1},
+		"name": {"type": "string", "minLength": 1},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+func newJSONServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestCheck_Success(t *testing.T) {
+	srv := newJSONServer(http.StatusOK, `{"id":1,"name":"alice","tags":["a"]}`)
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	var out struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	err := Check(c.Get(context.Background(), "/users/1", nil, nil)).
+		Status(Status2xx).
+		Header("Content-Type", "application/json").
+		JSONSchema([]byte(userSchema)).
+		JSON(&out)
+	if err != nil {
+		t.Fatalf("Check error: %v", err)
+	}
+	if out.ID != 1 || out.Name != "alice" {
+		t.Errorf("unexpected out: %+v", out)
+	}
+}
+
+func TestCheck_SchemaFailure(t *testing.T) {
+	srv := newJSONServer(http.StatusOK, `{"id":0,"tags":[1]}`)
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	err := Check(c.Get(context.Background(), "/users/1", nil, nil)).
+		Status(http.StatusOK).
+		JSONSchema([]byte(userSchema)).
+		Err()
+
+	var ee *ExpectationError
+	if !errors.As(err, &ee) {
+		t.Fatalf("expected *ExpectationError, got %v", err)
+	}
+	if ee.Method != http.MethodGet || !strings.HasSuffix(ee.URL, "/users/1") {
+		t.Errorf("missing request info: %+v", ee)
+	}
+	if !strings.Contains(ee.Reason, `missing required property "name"`) {
+		t.Errorf("unexpected reason: %s", ee.Reason)
+	}
+}
+
+func TestCheck_StatusOverride(t *testing.T) {
+	srv := newJSONServer(http.StatusNotFound, `{"code":404}`)
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+
+	// 未声明状态码时保留默认的 >= 400 错误
+	var se *StatusError
+	if err := Check(c.Get(context.Background(), "/missing", nil, nil)).Err(); !errors.As(err, &se) {
+		t.Fatalf("expected *StatusError, got %v", err)
+	}
+	// 显式期望 404 时视为成功
+	if err := Check(c.Get(context.Background(), "/missing", nil, nil)).Status(http.StatusNotFound).Err(); err != nil {
+		t.Fatalf("expected 404 to be accepted, got %v", err)
+	}
+	// 期望 2xx 时返回 ExpectationError
+	var ee *ExpectationError
+	if err := Check(c.Get(context.Background(), "/missing", nil, nil)).Status(Status2xx).Err(); !errors.As(err, &ee) {
+		t.Fatalf("expected *ExpectationError, got %v", err)
+	}
+}
+
+func TestValidateJSONSchema(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"status": {"enum": ["ok", "fail"]},
+			"code": {"type": "string", "pattern": "^[A-Z]{3}$"},
+			"items": {"type": "array", "maxItems": 2}
+		}
+	}`)
+	cases := []struct {
+		doc     string
+		wantErr bool
+	}{
+		{`{"status":"ok","code":"ABC","items":[1,2]}`, false},
+		{`{"status":"unknown"}`, true},
+		{`{"code":"abc"}`, true},
+		{`{"items":[1,2,3]}`, true},
+		{`{"extra":1}`, true},
+		{`[]`, true},
+	}
+	for _, tc := range cases {
+		err := ValidateJSONSchema(schema, []byte(tc.doc))
+		if (err != nil) != tc.wantErr {
+			t.Errorf("doc %s: err = %v, wantErr %v", tc.doc, err, tc.wantErr)
+		}
+	}
+}
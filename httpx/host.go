@@ -0,0 +1,125 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// maxRedirects 与 net/http 默认策略一致的最大重定向次数
+const maxRedirects = 10
+
+// HostHeader 按 host 模式限定作用范围的默认请求头
+type HostHeader struct {
+	Pattern string      // host 模式，如 api.example.com 或 *.internal.example.com
+	Header  http.Header // 仅对匹配 host 发送的请求头
+}
+
+// WithHostHeader 增加仅对匹配 host 生效的默认请求头（可多次调用累加）
+// pattern 支持精确匹配（api.example.com）与通配子域名（*.internal.example.com），不含端口
+// 实用场景: 共享的 Client 只向内部域名发送内部 token，避免绝对 URL 请求把凭证泄露给外部站点
+func WithHostHeader(pattern, key, value string) Option {
+	return func(o *ClientOptions) {
+		pattern = strings.ToLower(pattern)
+		for i := range o.HostHeaders {
+			if o.HostHeaders[i].Pattern == pattern {
+				o.HostHeaders[i].Header.Add(key, value)
+				return
+			}
+		}
+		h := make(http.Header)
+		h.Add(key, value)
+		o.HostHeaders = append(o.HostHeaders, HostHeader{Pattern: pattern, Header: h})
+	}
+}
+
+// WithHostBearerToken 仅对匹配 host 的请求附加 Authorization: Bearer <token>
+func WithHostBearerToken(pattern, token string) Option {
+	return WithHostHeader(pattern, "Authorization", "Bearer "+token)
+}
+
+// WithDefaultHeaderScope 限定 WithHeader 设置的默认请求头只发送给匹配的 host
+// 未设置时默认请求头对所有请求生效（保持原有行为）
+func WithDefaultHeaderScope(patterns ...string) Option {
+	return func(o *ClientOptions) {
+		for _, p := range patterns {
+			o.HeaderScope = append(o.HeaderScope, strings.ToLower(p))
+		}
+	}
+}
+
+// matchHost 判断 host 是否匹配模式，"*.example.com" 匹配任意层级子域名但不匹配 example.com 本身
+func matchHost(pattern, host string) bool {
+	host = strings.ToLower(host)
+	if strings.HasPrefix(pattern, "*.") {
+		return strings.HasSuffix(host, pattern[1:])
+	}
+	return pattern == host
+}
+
+// matchAny 判断 host 是否匹配任一模式
+func matchAny(patterns []string, host string) bool {
+	for _, p := range patterns {
+		if matchHost(p, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyDefaultHeaders 按作用范围合并默认请求头与按 host 限定的请求头
+func (c *Client) applyDefaultHeaders(req *http.Request) {
+	host := req.URL.Hostname()
+	if len(c.headerScope) == 0 || matchAny(c.headerScope, host) {
+		addHeaders(req.Header, c.defaultHeaders)
+	}
+	for _, hh := range c.hostHeaders {
+		if matchHost(hh.Pattern, host) {
+			addHeaders(req.Header, hh.Header)
+		}
+	}
+}
+
+// checkRedirect 跨 host 重定向时移除只对原 host 生效的请求头：按 host 限定的请求头，
+// 以及设置了 WithDefaultHeaderScope 时的默认请求头与 context 映射头，避免凭证、租户等信息被转发到其他站点
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.New("stopped after 10 redirects")
+	}
+	// net/http 每一跳都从原始请求复制请求头，因此与原始请求的 host 比较
+	from, to := via[0].URL.Hostname(), req.URL.Hostname()
+	if strings.EqualFold(from, to) {
+		return nil
+	}
+	for _, hh := range c.hostHeaders {
+		if matchHost(hh.Pattern, from) && !matchHost(hh.Pattern, to) {
+			for k := range hh.Header {
+				req.Header.Del(k)
+			}
+		}
+	}
+	if len(c.headerScope) > 0 && matchAny(c.headerScope, from) && !matchAny(c.headerScope, to) {
+		for k := range c.defaultHeaders {
+			req.Header.Del(k)
+		}
+		if c.traceIDHeader != "" {
+			req.Header.Del(c.traceIDHeader)
+		}
+		perRequest, _ := req.Context().Value(ctxHeaderKey{}).([]ContextHeader)
+		for _, list := range [][]ContextHeader{c.ctxHeaders, perRequest} {
+			for _, m := range list {
+				req.Header.Del(m.Header)
+			}
+		}
+	}
+	return nil
+}
+
+// addHeaders 追加 Header
+func addHeaders(dst, src http.Header) {
+	for k, vs := range src {
+		for _, v := range vs {
+			dst.Add(k, v)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMatchHost(t *testing.T) {
+	cases := []struct {
+		pattern, host string
+		want          bool
+	}{
+		{"api.example.com", "api.example.com", true},
+		{"api.example.com", "API.example.com", true},
+		{"api.example.com", "evil.com", false},
+		{"*.internal.example.com", "svc.internal.example.com", true},
+		{"*.internal.example.com", "a.b.internal.example.com", true},
+		{"*.internal.example.com", "internal.example.com", false},
+		{"*.internal.example.com", "internal.example.com.evil.com", false},
+	}
+	for _, tc := range cases {
+		if got := matchHost(tc.pattern, tc.host); got != tc.want {
+			t.Errorf("matchHost(%q, %q) = %v, want %v", tc.pattern, tc.host, got, tc.want)
+		}
+	}
+}
+
+func TestClient_HostScopedHeaders(t *testing.T) {
+	srv := newEchoServer()
+	defer srv.Close()
+
+	c := NewClient(
+		WithBaseURL(srv.URL),
+		WithHeader("X-Default", "A"),
+		WithDefaultHeaderScope("*.internal.example.com"),
+		WithHostBearerToken("127.0.0.1", "local-token"),
+		WithHostHeader("*.internal.example.com", "X-Internal", "1"),
+	)
+
+	_, body, err := c.Get(context.Background(), "/scoped", nil, nil)
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	var payload echoPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload.Header.Get("Authorization") != "Bearer local-token" {
+		t.Errorf("missing host scoped token")
+	}
+	if payload.Header.Get("X-Default") != "" {
+		t.Errorf("default header should be limited to its scope")
+	}
+	if payload.Header.Get("X-Internal") != "" {
+		t.Errorf("internal header leaked to non-matching host")
+	}
+}
+
+func TestClient_RedirectStripsHostHeaders(t *testing.T) {
+	target := newEchoServer()
+	defer target.Close()
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		to := target.URL
+		if r.URL.Path == "/cross" {
+			to = strings.Replace(to, "127.0.0.1", "localhost", 1)
+		}
+		http.Redirect(w, r, to+"/landed", http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	c := NewClient(
+		WithBaseURL(redirect.URL),
+		WithHeader("X-Default", "A"),
+		WithDefaultHeaderScope("127.0.0.1"),
+		WithHostBearerToken("127.0.0.1", "local-token"),
+		WithHostHeader("127.0.0.1", "X-Tenant-Id", "t1"),
+	)
+	for path, kept := range map[string]bool{"/same": true, "/cross": false} {
+		_, body, err := c.Get(context.Background(), path, nil, nil)
+		if err != nil {
+			t.Fatalf("Get %s error: %v", path, err)
+		}
+		var payload echoPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		for _, k := range []string{"Authorization", "X-Tenant-Id", "X-Default"} {
+			if got := payload.Header.Get(k) != ""; got != kept {
+				t.Errorf("%s: header %s present = %v, want %v", path, k, got, kept)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package httpx
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/utils/metrics"
+)
+
+// metricsSubsystem httpx 客户端指标的前缀
+const metricsSubsystem = "http"
+
+// WithMetrics 将每次请求尝试（含重试）的次数与耗时记录到 r（nil 时为 metrics.Default）：
+//   - http_client_requests_total{client,operation,result}
+//   - http_client_request_duration_seconds{client,operation}
+//
+// client 为 WithClientName 设置的名称（未设置时为 default），operation 为 HTTP 方法，状态码 >= 400 记为 error；
+// 同名指标已以不同定义注册时 NewClient 会 panic（与 prometheus.MustRegister 一致，在启动阶段暴露配置错误）
+func WithMetrics(r *metrics.Registry) Option {
+	return func(o *ClientOptions) {
+		if r == nil {
+			r = metrics.Default
+		}
+		o.Metrics = r
+	}
+}
+
+// newClientMetrics 获取（或注册）httpx 的客户端指标
+func newClientMetrics(r *metrics.Registry) *metrics.ClientMetrics {
+	return metrics.Must(metrics.NewClientMetrics(r, metricsSubsystem, nil))
+}
+
+// observe 记录一次请求尝试
+func (c *Client) observe(req *http.Request, err error, d time.Duration) {
+	name := c.name
+	if name == "" {
+		name = "default"
+	}
+	c.metrics.Observe(name, req.Method, metrics.Result(err), d)
+}
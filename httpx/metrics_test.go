@@ -0,0 +1,43 @@
+package httpx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qingfeng-studio/go-utils/utils/metrics"
+)
+
+func TestWithMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	r := metrics.NewRegistry()
+	c := NewClient(WithBaseURL(srv.URL), WithClientName("billing"), WithMetrics(r))
+	_, _, _ = c.Get(context.Background(), "/ok", nil, nil)
+	_, _, _ = c.Get(context.Background(), "/fail", nil, nil)
+	// 多个客户端共享同一组指标
+	_, _, _ = NewClient(WithBaseURL(srv.URL), WithMetrics(r)).Get(context.Background(), "/ok", nil, nil)
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	out := string(body)
+	for _, want := range []string{
+		`http_client_requests_total{client="billing",operation="GET",result="ok"} 1`,
+		`http_client_requests_total{client="billing",operation="GET",result="error"} 1`,
+		`http_client_requests_total{client="default",operation="GET",result="ok"} 1`,
+		`http_client_request_duration_seconds_count{client="billing",operation="GET"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// httpxgen 根据 OpenAPI 3.0 文档生成基于 httpx.Client 的强类型客户端代码
+//
+// 用法：
+//
+//	go run github.com/qingfeng-studio/go-utils/httpx/openapi/cmd/httpxgen -spec order.yaml -pkg order -out order_client.go
+//
+// 在 go:generate 中使用时 -pkg 默认取环境变量 GOPACKAGE
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/qingfeng-studio/go-utils/httpx/openapi"
+)
+
+func main() {
+	spec := flag.String("spec", "", "OpenAPI 文档路径（JSON 或 YAML）")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "生成代码的包名")
+	out := flag.String("out", "", "输出文件路径，为空时输出到标准输出")
+	client := flag.String("client", "Client", "客户端类型名")
+	flag.Parse()
+
+	if *spec == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := openapi.Config{Package: *pkg, ClientName: *client, Source: *spec}
+	if *out != "" {
+		if err := openapi.GenerateFile(*spec, *out, cfg); err != nil {
+			fmt.Fprintln(os.Stderr, "httpxgen:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	doc, err := openapi.ParseFile(*spec)
+	if err == nil {
+		var code []byte
+		if code, err = openapi.Generate(doc, cfg); err == nil {
+			_, err = os.Stdout.Write(code)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "httpxgen:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,736 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// httpxImport 生成代码依赖的 httpx 包路径
+const httpxImport = "github.com/qingfeng-studio/go-utils/httpx"
+
+// Config 代码生成配置
+type Config struct {
+	Package    string // 生成代码的包名，必填
+	ClientName string // 客户端类型名，默认 Client，构造函数为 New + ClientName
+	Source     string // 文档来源，写入文件头注释，可为空
+}
+
+// Generate 根据 OpenAPI 文档生成 Go 客户端代码（已 gofmt）
+func Generate(spec *Spec, cfg Config) ([]byte, error) {
+	if cfg.Package == "" {
+		return nil, errors.New("openapi: package name is required")
+	}
+	if cfg.ClientName == "" {
+		cfg.ClientName = "Client"
+	}
+	g := &generator{
+		spec:    spec,
+		cfg:     cfg,
+		defined: make(map[string]bool),
+		imports: map[string]bool{httpxImport: true},
+		opNames: make(map[string]bool),
+	}
+	return g.generate()
+}
+
+// GenerateFile 读取 specPath 的文档，生成代码写入 outPath
+func GenerateFile(specPath, outPath string, cfg Config) error {
+	spec, err := ParseFile(specPath)
+	if err != nil {
+		return err
+	}
+	if cfg.Source == "" {
+		cfg.Source = specPath
+	}
+	code, err := Generate(spec, cfg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outPath, code, 0o644)
+}
+
+// generator 单次生成的状态
+type generator struct {
+	spec *Spec
+	cfg  Config
+
+	types   bytes.Buffer    // 类型定义
+	methods bytes.Buffer    // 客户端方法
+	defined map[string]bool // 已生成的类型名
+	imports map[string]bool
+	opNames map[string]bool
+}
+
+// generate 依次生成组件类型、操作方法并拼接成文件
+func (g *generator) generate() ([]byte, error) {
+	names := sortedKeys(g.spec.Components.Schemas)
+	for _, name := range names {
+		if err := g.namedType(goName(name), g.spec.Components.Schemas[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, path := range sortedKeys(g.spec.Paths) {
+		item := g.spec.Paths[path]
+		if item == nil {
+			continue
+		}
+		for _, o := range item.operations() {
+			if err := g.operation(path, o.Method, item, o.Op); err != nil {
+				return nil, fmt.Errorf("openapi: %s %s: %w", o.Method, path, err)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	source := ""
+	if g.cfg.Source != "" {
+		source = " from " + g.cfg.Source
+	}
+	fmt.Fprintf(&out, "// Code generated by httpxgen%s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&out, "package %s\n\n", g.cfg.Package)
+	out.WriteString("import (\n")
+	var std, third []string
+	for imp := range g.imports {
+		if strings.Contains(imp, ".") {
+			third = append(third, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(third)
+	for _, imp := range std {
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	if len(std) > 0 {
+		out.WriteString("\n")
+	}
+	for _, imp := range third {
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	out.WriteString(")\n\n")
+
+	out.Write(g.types.Bytes())
+
+	title := g.spec.Info.Title
+	if title == "" {
+		title = g.cfg.ClientName
+	}
+	fmt.Fprintf(&out, "// %s %s 客户端，基于 httpx.Client\n", g.cfg.ClientName, oneLine(title))
+	fmt.Fprintf(&out, "type %s struct {\n\tclient *httpx.Client\n}\n\n", g.cfg.ClientName)
+	fmt.Fprintf(&out, "// New%s 使用已配置好 BaseURL、鉴权等选项的 httpx.Client 创建客户端\n", g.cfg.ClientName)
+	fmt.Fprintf(&out, "func New%s(client *httpx.Client) *%s {\n\treturn &%s{client: client}\n}\n\n", g.cfg.ClientName, g.cfg.ClientName, g.cfg.ClientName)
+	out.Write(g.methods.Bytes())
+
+	code, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("openapi: format generated code: %w\n%s", err, out.Bytes())
+	}
+	return code, nil
+}
+
+// namedType 为组件 Schema 生成具名类型：对象生成结构体，其它生成类型定义
+func (g *generator) namedType(name string, s *Schema) error {
+	if isObject(s) && (len(s.Properties) > 0 || len(s.AllOf) > 0) {
+		_, err := g.structType(name, s)
+		return err
+	}
+	if g.defined[name] {
+		return nil
+	}
+	g.defined[name] = true
+	typ, err := g.goType(s, name+"Item")
+	if err != nil {
+		return err
+	}
+	writeDoc(&g.types, name, s.Description)
+	fmt.Fprintf(&g.types, "type %s %s\n\n", name, typ)
+
+	// 字符串枚举生成常量
+	if typ == "string" && len(s.Enum) > 0 {
+		g.types.WriteString("// " + name + " 的可选值\nconst (\n")
+		seen := make(map[string]bool)
+		for _, v := range s.Enum {
+			str, ok := v.(string)
+			if !ok {
+				continue
+			}
+			constName := name + goName(str)
+			if seen[constName] {
+				continue
+			}
+			seen[constName] = true
+			fmt.Fprintf(&g.types, "\t%s %s = %q\n", constName, name, str)
+		}
+		g.types.WriteString(")\n\n")
+	}
+	return nil
+}
+
+// structType 生成结构体定义，allOf 的各部分属性会合并到同一个结构体
+func (g *generator) structType(name string, s *Schema) (string, error) {
+	if g.defined[name] {
+		return name, nil
+	}
+	g.defined[name] = true
+
+	props := make(map[string]*Schema)
+	required := make(map[string]bool)
+	if err := g.collectProps(s, props, required, 0); err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	for _, prop := range sortedKeys(props) {
+		ps := props[prop]
+		field := goName(prop)
+		typ, err := g.goType(ps, name+field)
+		if err != nil {
+			return "", err
+		}
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+			if isNamed(typ) || typ == "time.Time" {
+				typ = "*" + typ
+			}
+		}
+		if ps != nil && ps.Description != "" {
+			fmt.Fprintf(&body, "\t// %s %s\n", field, oneLine(ps.Description))
+		}
+		fmt.Fprintf(&body, "\t%s %s `json:%q`\n", field, typ, tag)
+	}
+
+	writeDoc(&g.types, name, s.Description)
+	fmt.Fprintf(&g.types, "type %s struct {\n%s}\n\n", name, body.Bytes())
+	return name, nil
+}
+
+// collectProps 合并 Schema（含 allOf 与引用）的属性与必填字段
+func (g *generator) collectProps(s *Schema, props map[string]*Schema, required map[string]bool, depth int) error {
+	if depth > 16 {
+		return errors.New("allOf nested too deep")
+	}
+	s, err := g.spec.schema(s)
+	if err != nil || s == nil {
+		return err
+	}
+	for _, part := range s.AllOf {
+		if err := g.collectProps(part, props, required, depth+1); err != nil {
+			return err
+		}
+	}
+	for k, v := range s.Properties {
+		props[k] = v
+	}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	return nil
+}
+
+// goType 返回 Schema 对应的 Go 类型，内联对象按 hint 命名生成结构体
+func (g *generator) goType(s *Schema, hint string) (string, error) {
+	if s == nil {
+		g.imports["encoding/json"] = true
+		return "json.RawMessage", nil
+	}
+	if s.Ref != "" {
+		if !strings.HasPrefix(s.Ref, "#/components/schemas/") {
+			return "", fmt.Errorf("unsupported schema reference %s", s.Ref)
+		}
+		return goName(refName(s.Ref)), nil
+	}
+	if isObject(s) {
+		if len(s.Properties) > 0 || len(s.AllOf) > 0 {
+			return g.structType(hint, s)
+		}
+		if ap := s.AdditionalProperties; ap != nil && ap.Schema != nil {
+			elem, err := g.goType(ap.Schema, hint+"Value")
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + elem, nil
+		}
+		return "map[string]interface{}", nil
+	}
+	switch s.Type {
+	case "array":
+		elem, err := g.goType(s.Items, hint+"Item")
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case "string":
+		switch s.Format {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time", nil
+		case "byte", "binary":
+			return "[]byte", nil
+		}
+		return "string", nil
+	case "integer":
+		if s.Format == "int32" {
+			return "int32", nil
+		}
+		return "int64", nil
+	case "number":
+		if s.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	}
+	return "interface{}", nil
+}
+
+// param 解析后的参数
+type param struct {
+	*Parameter
+	goName string // 结构体字段名
+	arg    string // 方法参数名（path 参数）
+	typ    string
+}
+
+// operation 生成单个操作的方法
+func (g *generator) operation(path, method string, item *PathItem, op *Operation) error {
+	name := op.OperationID
+	if name == "" {
+		name = strings.ToLower(method) + " " + strings.NewReplacer("{", "by ", "}", "").Replace(path)
+	}
+	name = goName(name)
+	for base, i := name, 2; g.opNames[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.opNames[name] = true
+
+	params, err := g.params(name, item, op)
+	if err != nil {
+		return err
+	}
+	var pathParams, queryParams, headerParams []*param
+	for _, p := range params {
+		switch p.In {
+		case "path":
+			pathParams = append(pathParams, p)
+		case "query":
+			queryParams = append(queryParams, p)
+		case "header":
+			headerParams = append(headerParams, p)
+		}
+	}
+	// path 参数按在路径中出现的顺序排列
+	sort.SliceStable(pathParams, func(i, j int) bool {
+		return strings.Index(path, "{"+pathParams[i].Name+"}") < strings.Index(path, "{"+pathParams[j].Name+"}")
+	})
+
+	// 请求体：JSON 按类型生成，其它内容类型使用 io.Reader
+	var bodyType, bodyReaderCT string
+	hasBody := method == "POST" || method == "PUT" || method == "PATCH"
+	if hasBody {
+		rb, err := g.spec.requestBody(op.RequestBody)
+		if err != nil {
+			return err
+		}
+		if rb != nil {
+			if s := jsonContent(rb.Content); s != nil {
+				if bodyType, err = g.goType(s, name+"Request"); err != nil {
+					return err
+				}
+				if isNamed(bodyType) {
+					bodyType = "*" + bodyType
+				}
+				g.imports["encoding/json"] = true
+			} else if len(rb.Content) > 0 {
+				bodyReaderCT = sortedKeys(rb.Content)[0]
+				g.imports["io"] = true
+			}
+		}
+	}
+
+	respType, err := g.responseType(name, op)
+	if err != nil {
+		return err
+	}
+
+	// 必填的 query/header 参数作为方法参数，可选参数放入参数结构体
+	var required, optional []*param
+	for _, p := range append(append([]*param{}, queryParams...), headerParams...) {
+		if p.Required {
+			required = append(required, p)
+		} else {
+			optional = append(optional, p)
+		}
+	}
+	paramsType := ""
+	if len(optional) > 0 {
+		paramsType = name + "Params"
+		fmt.Fprintf(&g.types, "// %s %s 的可选 query 与 header 参数\n", paramsType, name)
+		fmt.Fprintf(&g.types, "type %s struct {\n", paramsType)
+		for _, p := range optional {
+			if p.Description != "" {
+				fmt.Fprintf(&g.types, "\t// %s %s\n", p.goName, oneLine(p.Description))
+			}
+			fmt.Fprintf(&g.types, "\t%s %s\n", p.goName, fieldType(p))
+		}
+		g.types.WriteString("}\n\n")
+	}
+
+	// 方法签名
+	w := &g.methods
+	summary := op.Summary
+	if summary == "" {
+		summary = op.Description
+	}
+	if summary != "" {
+		fmt.Fprintf(w, "// %s %s\n//\n// %s %s\n", name, oneLine(summary), method, path)
+	} else {
+		fmt.Fprintf(w, "// %s 调用 %s %s\n", name, method, path)
+	}
+	if op.Deprecated {
+		w.WriteString("//\n// Deprecated: 接口已在文档中标记为废弃\n")
+	}
+	g.imports["context"] = true
+	args := []string{"ctx context.Context"}
+	for _, p := range pathParams {
+		args = append(args, p.arg+" "+p.typ)
+	}
+	for _, p := range required {
+		args = append(args, p.arg+" "+p.typ)
+	}
+	switch {
+	case bodyType != "":
+		args = append(args, "body "+bodyType)
+	case bodyReaderCT != "":
+		args = append(args, "body io.Reader", "contentType string")
+	}
+	if paramsType != "" {
+		args = append(args, "params *"+paramsType)
+	}
+	results, errReturn := "error", "return err"
+	switch {
+	case respType == "":
+	case isNamed(respType):
+		results, errReturn = fmt.Sprintf("(*%s, error)", respType), "return nil, err"
+	default:
+		results, errReturn = fmt.Sprintf("(%s, error)", respType), "return out, err"
+	}
+	fmt.Fprintf(w, "func (c *%s) %s(%s) %s {\n", g.cfg.ClientName, name, strings.Join(args, ", "), results)
+
+	// 路径
+	if len(pathParams) == 0 {
+		fmt.Fprintf(w, "\tpath := %q\n", path)
+	} else {
+		g.imports["fmt"] = true
+		g.imports["net/url"] = true
+		tmpl := path
+		var values []string
+		for _, p := range pathParams {
+			tmpl = strings.Replace(tmpl, "{"+p.Name+"}", "%s", 1)
+			values = append(values, "url.PathEscape("+g.valueExpr(p.typ, p.arg)+")")
+		}
+		fmt.Fprintf(w, "\tpath := fmt.Sprintf(%q, %s)\n", tmpl, strings.Join(values, ", "))
+	}
+
+	// query 参数编码后直接拼到 path 上，以支持数组按 explode 展开为重复的 key
+	queryArg, headersArg := "nil", "nil"
+	if len(queryParams) > 0 {
+		g.imports["net/url"] = true
+		w.WriteString("\tquery := make(url.Values)\n")
+	}
+	if len(headerParams) > 0 {
+		headersArg = "headers"
+		g.imports["net/http"] = true
+		w.WriteString("\theaders := make(http.Header)\n")
+	}
+	for _, p := range required {
+		if err := g.writeParam(w, p, p.arg, false); err != nil {
+			return err
+		}
+	}
+	if paramsType != "" {
+		w.WriteString("\tif params != nil {\n")
+		for _, p := range optional {
+			if err := g.writeParam(w, p, "params."+p.goName, true); err != nil {
+				return err
+			}
+		}
+		w.WriteString("\t}\n")
+	}
+	if len(queryParams) > 0 {
+		w.WriteString("\tif len(query) > 0 {\n\t\tpath += \"?\" + query.Encode()\n\t}\n")
+	}
+	if respType != "" {
+		fmt.Fprintf(w, "\tvar out %s\n", respType)
+	}
+
+	// 调用
+	title := method[:1] + strings.ToLower(method[1:])
+	var call string
+	switch {
+	case bodyType != "":
+		w.WriteString("\tdata, err := json.Marshal(body)\n\tif err != nil {\n\t\t" + errReturn + "\n\t}\n")
+		call = fmt.Sprintf("c.client.%s(ctx, path, data, %q, %s, %s)", title, "application/json", headersArg, queryArg)
+	case bodyReaderCT != "":
+		w.WriteString("\tif contentType == \"\" {\n\t\tcontentType = " + fmt.Sprintf("%q", bodyReaderCT) + "\n\t}\n")
+		call = fmt.Sprintf("c.client.%sReader(ctx, path, body, -1, contentType, %s, %s)", title, headersArg, queryArg)
+	case hasBody:
+		call = fmt.Sprintf("c.client.%s(ctx, path, nil, \"\", %s, %s)", title, headersArg, queryArg)
+	default:
+		call = fmt.Sprintf("c.client.%s(ctx, path, %s, %s)", title, queryArg, headersArg)
+	}
+
+	switch {
+	case respType == "":
+		fmt.Fprintf(w, "\treturn httpx.Check(%s).Err()\n", call)
+	case isNamed(respType):
+		fmt.Fprintf(w, "\tif err := httpx.Check(%s).JSON(&out); err != nil {\n\t\treturn nil, err\n\t}\n\treturn &out, nil\n", call)
+	default:
+		fmt.Fprintf(w, "\tif err := httpx.Check(%s).JSON(&out); err != nil {\n\t\treturn out, err\n\t}\n\treturn out, nil\n", call)
+	}
+	w.WriteString("}\n\n")
+	return nil
+}
+
+// params 合并 path 级与操作级参数（操作级同名参数覆盖 path 级），cookie 参数忽略
+func (g *generator) params(opName string, item *PathItem, op *Operation) ([]*param, error) {
+	var merged []*Parameter
+	index := make(map[string]int)
+	for _, list := range [][]*Parameter{item.Parameters, op.Parameters} {
+		for _, raw := range list {
+			p, err := g.spec.parameter(raw)
+			if err != nil {
+				return nil, err
+			}
+			key := p.In + ":" + p.Name
+			if i, ok := index[key]; ok {
+				merged[i] = p
+				continue
+			}
+			index[key] = len(merged)
+			merged = append(merged, p)
+		}
+	}
+
+	var out []*param
+	used := map[string]bool{"ctx": true, "body": true, "contentType": true, "params": true, "path": true,
+		"query": true, "headers": true, "data": true, "out": true, "err": true, "c": true}
+	for _, p := range merged {
+		if p.In != "path" && p.In != "query" && p.In != "header" {
+			continue
+		}
+		typ, err := g.goType(p.Schema, opName+goName(p.Name))
+		if err != nil {
+			return nil, err
+		}
+		if p.In == "path" {
+			p.Required = true
+		}
+		arg := lowerFirst(goName(p.Name))
+		if token.IsKeyword(arg) || used[arg] {
+			arg += "Param"
+		}
+		used[arg] = true
+		out = append(out, &param{Parameter: p, goName: goName(p.Name), arg: arg, typ: typ})
+	}
+	return out, nil
+}
+
+// responseType 取第一个带 JSON 内容的 2xx 响应类型，没有时返回空串
+func (g *generator) responseType(opName string, op *Operation) (string, error) {
+	for _, code := range sortedKeys(op.Responses) {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		resp, err := g.spec.response(op.Responses[code])
+		if err != nil {
+			return "", err
+		}
+		if resp == nil {
+			continue
+		}
+		if s := jsonContent(resp.Content); s != nil {
+			return g.goType(s, opName+"Response")
+		}
+	}
+	return "", nil
+}
+
+// writeParam 生成把参数写入 query/headers 的代码。expr 为取值表达式，optional 的标量参数为指针，nil 时跳过；
+// 数组参数为空时跳过
+func (g *generator) writeParam(w *bytes.Buffer, p *param, expr string, optional bool) error {
+	target := "query"
+	if p.In == "header" {
+		target = "headers"
+	}
+	if strings.HasPrefix(p.typ, "[]") && p.typ != "[]byte" {
+		sep, explode, err := arrayStyle(p.Parameter)
+		if err != nil {
+			return err
+		}
+		elem := g.valueExpr(strings.TrimPrefix(p.typ, "[]"), "v")
+		if explode {
+			fmt.Fprintf(w, "\tfor _, v := range %s {\n\t\t%s.Add(%q, %s)\n\t}\n", expr, target, p.Name, elem)
+			return nil
+		}
+		g.imports["strings"] = true
+		fmt.Fprintf(w, "\tif len(%s) > 0 {\n", expr)
+		fmt.Fprintf(w, "\t\tparts := make([]string, len(%s))\n", expr)
+		fmt.Fprintf(w, "\t\tfor i, v := range %s {\n\t\t\tparts[i] = %s\n\t\t}\n", expr, elem)
+		fmt.Fprintf(w, "\t\t%s.Set(%q, strings.Join(parts, %q))\n\t}\n", target, p.Name, sep)
+		return nil
+	}
+	if !optional || strings.HasPrefix(p.typ, "map[") {
+		fmt.Fprintf(w, "\t%s.Set(%q, %s)\n", target, p.Name, g.valueExpr(p.typ, expr))
+		return nil
+	}
+	fmt.Fprintf(w, "\tif %s != nil {\n\t\t%s.Set(%q, %s)\n\t}\n", expr, target, p.Name, g.valueExpr(p.typ, "*"+expr))
+	return nil
+}
+
+// arrayStyle 按 style/explode 返回数组参数的分隔符以及是否展开为重复的 key。
+// query 支持 form（默认，explode 默认 true）、spaceDelimited、pipeDelimited；header 只支持 simple（逗号分隔）
+func arrayStyle(p *Parameter) (sep string, explode bool, err error) {
+	explode = p.Explode == nil || *p.Explode
+	switch p.In {
+	case "query":
+		switch p.Style {
+		case "", "form":
+			return ",", explode, nil
+		case "spaceDelimited":
+			return " ", explode, nil
+		case "pipeDelimited":
+			return "|", explode, nil
+		}
+	case "header":
+		if p.Style == "" || p.Style == "simple" {
+			return ",", false, nil
+		}
+	}
+	return "", false, fmt.Errorf("parameter %s: unsupported %s style %q", p.Name, p.In, p.Style)
+}
+
+// valueExpr 把参数值转为字符串的表达式
+func (g *generator) valueExpr(typ, expr string) string {
+	switch typ {
+	case "string":
+		return expr
+	case "time.Time":
+		return "(" + expr + ").Format(time.RFC3339)"
+	}
+	g.imports["fmt"] = true
+	return "fmt.Sprint(" + expr + ")"
+}
+
+// fieldType 参数结构体中的字段类型：可选的标量参数使用指针以区分未设置
+func fieldType(p *param) string {
+	if strings.HasPrefix(p.typ, "[]") || strings.HasPrefix(p.typ, "map[") {
+		return p.typ
+	}
+	return "*" + p.typ
+}
+
+// isObject 判断 Schema 是否为对象
+func isObject(s *Schema) bool {
+	return s != nil && s.Ref == "" && (s.Type == "object" || (s.Type == "" && (len(s.Properties) > 0 || len(s.AllOf) > 0)))
+}
+
+// isNamed 判断是否为生成的具名类型（而非切片、map 或内置类型）
+func isNamed(typ string) bool {
+	return typ != "" && unicode.IsUpper(rune(typ[0])) && !strings.Contains(typ, ".")
+}
+
+// commonInitialisms 转换为 Go 命名时保持全大写的缩写
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "CPU": true, "CSS": true, "DNS": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "OS": true, "SQL": true, "SSH": true, "TLS": true, "TTL": true,
+	"UI": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// goName 把 snake_case、kebab-case、camelCase 等名称转为导出的 Go 标识符
+func goName(s string) string {
+	var words []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		words = append(words, splitCamel(part)...)
+	}
+	var b strings.Builder
+	for _, w := range words {
+		if up := strings.ToUpper(w); commonInitialisms[up] {
+			b.WriteString(up)
+			continue
+		}
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "X" + name
+	}
+	return name
+}
+
+// splitCamel 在小写到大写的边界处拆分单词，如 petId -> pet, Id
+func splitCamel(s string) []string {
+	var words []string
+	r := []rune(s)
+	start := 0
+	for i := 1; i < len(r); i++ {
+		if unicode.IsUpper(r[i]) && !unicode.IsUpper(r[i-1]) {
+			words = append(words, string(r[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(r[start:]))
+}
+
+// lowerFirst 把导出名转为参数名，开头的缩写整体小写，如 IDCard -> idCard、URL -> url
+func lowerFirst(s string) string {
+	r := []rune(s)
+	i := 0
+	for i < len(r) && unicode.IsUpper(r[i]) {
+		i++
+	}
+	switch {
+	case i == 0:
+		return s
+	case i == 1 || i == len(r):
+		return strings.ToLower(string(r[:i])) + string(r[i:])
+	default:
+		// 多个大写字母后跟小写，最后一个大写字母属于下一个单词
+		return strings.ToLower(string(r[:i-1])) + string(r[i-1:])
+	}
+}
+
+// oneLine 把多行描述压缩为单行注释
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// writeDoc 写入类型注释，文档中没有描述时使用默认说明
+func writeDoc(w *bytes.Buffer, name, desc string) {
+	if desc == "" {
+		desc = "由 OpenAPI 文档生成"
+	}
+	fmt.Fprintf(w, "// %s %s\n", name, oneLine(desc))
+}
+
+// sortedKeys 返回排序后的 map key
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
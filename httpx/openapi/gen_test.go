@@ -0,0 +1,114 @@
+package openapi
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerate_Golden 生成结果需与 internal/petstore 中提交的代码一致（修改生成器后执行 go generate 更新）
+func TestGenerate_Golden(t *testing.T) {
+	spec, err := ParseFile("testdata/petstore.yaml")
+	require.NoError(t, err)
+
+	code, err := Generate(spec, Config{Package: "petstore", Source: "../../testdata/petstore.yaml"})
+	require.NoError(t, err)
+
+	golden, err := os.ReadFile("internal/petstore/client_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), string(code))
+}
+
+func TestGenerate_JSONSpec(t *testing.T) {
+	spec, err := Parse([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "Users"},
+		"paths": {
+			"/users/{user_id}": {
+				"get": {
+					"operationId": "get-user",
+					"parameters": [{"name": "user_id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"200": {"content": {"application/json": {"schema": {"type": "object", "properties": {"name": {"type": "string"}}}}}}}
+				}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+
+	code, err := Generate(spec, Config{Package: "users", ClientName: "UserAPI"})
+	require.NoError(t, err)
+	src := string(code)
+	assert.Contains(t, src, "func NewUserAPI(client *httpx.Client) *UserAPI")
+	assert.Contains(t, src, "func (c *UserAPI) GetUser(ctx context.Context, userID string) (*GetUserResponse, error)")
+	assert.Contains(t, src, `url.PathEscape(userID)`)
+}
+
+func TestGenerate_QueryStyle(t *testing.T) {
+	spec, err := Parse([]byte(`
+openapi: 3.0.0
+paths:
+  /search:
+    get:
+      operationId: search
+      parameters:
+        - {name: q, in: query, required: true, schema: {type: string}}
+        - {name: ids, in: query, explode: false, schema: {type: array, items: {type: integer}}}
+        - {name: sort, in: query, style: pipeDelimited, explode: false, schema: {type: array, items: {type: string}}}
+        - {name: tags, in: query, schema: {type: array, items: {type: string}}}
+`))
+	require.NoError(t, err)
+	code, err := Generate(spec, Config{Package: "search"})
+	require.NoError(t, err)
+	src := string(code)
+	assert.Contains(t, src, "func (c *Client) Search(ctx context.Context, q string, params *SearchParams) error")
+	assert.Contains(t, src, `query.Set("q", q)`)
+	assert.Contains(t, src, `query.Set("ids", strings.Join(parts, ","))`)
+	assert.Contains(t, src, `query.Set("sort", strings.Join(parts, "|"))`)
+	assert.Contains(t, src, `query.Add("tags", v)`)
+
+	spec, err = Parse([]byte(`
+openapi: 3.0.0
+paths:
+  /search:
+    get:
+      parameters:
+        - {name: filter, in: query, style: deepObject, schema: {type: array, items: {type: string}}}
+`))
+	require.NoError(t, err)
+	_, err = Generate(spec, Config{Package: "search"})
+	assert.ErrorContains(t, err, "unsupported")
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse([]byte(`swagger: "2.0"`))
+	assert.Error(t, err)
+
+	spec, err := Parse([]byte("openapi: 3.0.0\npaths:\n  /a:\n    get:\n      parameters:\n        - $ref: '#/components/parameters/Missing'\n"))
+	require.NoError(t, err)
+	_, err = Generate(spec, Config{Package: "x"})
+	assert.Error(t, err)
+
+	_, err = Generate(spec, Config{})
+	assert.Error(t, err)
+}
+
+func TestGoName(t *testing.T) {
+	cases := map[string]string{
+		"petId":       "PetID",
+		"created_at":  "CreatedAt",
+		"X-Tenant-Id": "XTenantID",
+		"get-user":    "GetUser",
+		"apiURL":      "APIURL",
+		"2fa":         "X2fa",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, goName(in), in)
+	}
+	assert.Equal(t, "petID", lowerFirst("PetID"))
+	assert.Equal(t, "id", lowerFirst("ID"))
+	assert.Equal(t, "urlPath", lowerFirst("URLPath"))
+	assert.True(t, strings.HasPrefix(goName("a b"), "AB"))
+}
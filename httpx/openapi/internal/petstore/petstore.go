@@ -0,0 +1,4 @@
+// Package petstore 由 testdata/petstore.yaml 生成的示例客户端，用于验证生成代码可编译、可调用
+package petstore
+
+//go:generate go run ../../cmd/httpxgen -spec ../../testdata/petstore.yaml -out client_gen.go
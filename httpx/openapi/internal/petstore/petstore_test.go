@@ -0,0 +1,81 @@
+package petstore
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qingfeng-studio/go-utils/httpx"
+)
+
+func TestGeneratedClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/pets":
+			assert.Equal(t, "10", r.URL.Query().Get("limit"))
+			assert.Equal(t, []string{"a", "b"}, r.URL.Query()["tags"])
+			assert.Equal(t, "t1", r.Header.Get("X-Tenant-Id"))
+			_, _ = w.Write([]byte(`[{"id":1,"name":"cat","status":"sold"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/pets":
+			var req CreatePetRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(Pet{ID: 2, Name: req.Name})
+		case r.Method == http.MethodPut && r.URL.Path == "/pets/a b/photo":
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, "image/png", r.Header.Get("Content-Type"))
+			assert.Equal(t, "png", string(body))
+			_, _ = w.Write([]byte(`{"url":"https://cdn/x.png"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/pets/404":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(httpx.NewClient(httpx.WithBaseURL(srv.URL)))
+	ctx := context.Background()
+
+	limit := int32(10)
+	pets, err := c.ListPets(ctx, "t1", &ListPetsParams{Limit: &limit, Tags: []string{"a", "b"}})
+	require.NoError(t, err)
+	require.Len(t, pets, 1)
+	assert.Equal(t, StatusSold, *pets[0].Status)
+
+	pet, err := c.CreatePet(ctx, &CreatePetRequest{Name: "dog"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), pet.ID)
+	assert.Equal(t, "dog", pet.Name)
+
+	photo, err := c.UploadPhoto(ctx, "a b", stringsReader("png"), "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn/x.png", photo.URL)
+
+	_, err = c.GetPetByID(ctx, 404)
+	var se *httpx.StatusError
+	assert.ErrorAs(t, err, &se)
+
+	assert.NoError(t, c.DeletePetsByPetID(ctx, 1))
+}
+
+func stringsReader(s string) io.Reader { return &reader{s: s} }
+
+// reader 不实现 io.Seeker，验证流式上传
+type reader struct{ s string }
+
+func (r *reader) Read(p []byte) (int, error) {
+	if r.s == "" {
+		return 0, io.EOF
+	}
+	n := copy(p, r.s)
+	r.s = r.s[n:]
+	return n, nil
+}
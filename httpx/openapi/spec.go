@@ -0,0 +1,255 @@
+// Package openapi 读取 OpenAPI 3.0 文档（JSON 或 YAML），生成基于 httpx.Client 的强类型 API 客户端代码
+//
+// 配合 go:generate 使用：
+//
+//	//go:generate go run github.com/qingfeng-studio/go-utils/httpx/openapi/cmd/httpxgen -spec order.yaml -pkg order -out order_client.go
+//
+// 支持的子集：paths 下的 GET/POST/PUT/PATCH/DELETE/HEAD/OPTIONS 操作，path/query/header 参数（含 components.parameters 引用），
+// application/json 请求体与 2xx 响应，components.schemas 中的 object/array/基础类型/allOf，
+// 以及 components.requestBodies、components.responses 引用。
+//
+// 生成的方法中 path 参数与必填的 query/header 参数为位置参数，可选参数放在 XxxParams 结构体中；
+// 数组 query 参数按 style/explode 序列化（form、spaceDelimited、pipeDelimited），header 数组按逗号拼接
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec OpenAPI 文档中生成代码所需的部分
+type Spec struct {
+	OpenAPI    string               `json:"openapi" yaml:"openapi"`
+	Info       Info                 `json:"info" yaml:"info"`
+	Paths      map[string]*PathItem `json:"paths" yaml:"paths"`
+	Components Components           `json:"components" yaml:"components"`
+}
+
+// Info 文档信息
+type Info struct {
+	Title   string `json:"title" yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// Components 可复用的定义
+type Components struct {
+	Schemas       map[string]*Schema      `json:"schemas" yaml:"schemas"`
+	Parameters    map[string]*Parameter   `json:"parameters" yaml:"parameters"`
+	RequestBodies map[string]*RequestBody `json:"requestBodies" yaml:"requestBodies"`
+	Responses     map[string]*Response    `json:"responses" yaml:"responses"`
+}
+
+// PathItem 单个路径下的操作
+type PathItem struct {
+	Parameters []*Parameter `json:"parameters" yaml:"parameters"`
+	Get        *Operation   `json:"get" yaml:"get"`
+	Post       *Operation   `json:"post" yaml:"post"`
+	Put        *Operation   `json:"put" yaml:"put"`
+	Patch      *Operation   `json:"patch" yaml:"patch"`
+	Delete     *Operation   `json:"delete" yaml:"delete"`
+	Head       *Operation   `json:"head" yaml:"head"`
+	Options    *Operation   `json:"options" yaml:"options"`
+}
+
+// operations 按固定顺序返回 HTTP 方法与操作
+func (p *PathItem) operations() []struct {
+	Method string
+	Op     *Operation
+} {
+	all := []struct {
+		Method string
+		Op     *Operation
+	}{
+		{"GET", p.Get}, {"POST", p.Post}, {"PUT", p.Put}, {"PATCH", p.Patch},
+		{"DELETE", p.Delete}, {"HEAD", p.Head}, {"OPTIONS", p.Options},
+	}
+	out := all[:0]
+	for _, o := range all {
+		if o.Op != nil {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// Operation 单个 API 操作
+type Operation struct {
+	OperationID string               `json:"operationId" yaml:"operationId"`
+	Summary     string               `json:"summary" yaml:"summary"`
+	Description string               `json:"description" yaml:"description"`
+	Deprecated  bool                 `json:"deprecated" yaml:"deprecated"`
+	Parameters  []*Parameter         `json:"parameters" yaml:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody" yaml:"requestBody"`
+	Responses   map[string]*Response `json:"responses" yaml:"responses"`
+}
+
+// Parameter 请求参数
+type Parameter struct {
+	Ref         string  `json:"$ref" yaml:"$ref"`
+	Name        string  `json:"name" yaml:"name"`
+	In          string  `json:"in" yaml:"in"` // path、query、header（cookie 不支持）
+	Description string  `json:"description" yaml:"description"`
+	Required    bool    `json:"required" yaml:"required"`
+	Schema      *Schema `json:"schema" yaml:"schema"`
+	Style       string  `json:"style" yaml:"style"`     // 数组序列化方式，query 默认 form，header 默认 simple
+	Explode     *bool   `json:"explode" yaml:"explode"` // 为空时 form 风格默认 true
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Ref      string                `json:"$ref" yaml:"$ref"`
+	Required bool                  `json:"required" yaml:"required"`
+	Content  map[string]*MediaType `json:"content" yaml:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Ref         string                `json:"$ref" yaml:"$ref"`
+	Description string                `json:"description" yaml:"description"`
+	Content     map[string]*MediaType `json:"content" yaml:"content"`
+}
+
+// MediaType 内容类型对应的结构
+type MediaType struct {
+	Schema *Schema `json:"schema" yaml:"schema"`
+}
+
+// Schema 数据结构定义（JSON Schema 子集）
+type Schema struct {
+	Ref                  string             `json:"$ref" yaml:"$ref"`
+	Type                 string             `json:"type" yaml:"type"`
+	Format               string             `json:"format" yaml:"format"`
+	Description          string             `json:"description" yaml:"description"`
+	Properties           map[string]*Schema `json:"properties" yaml:"properties"`
+	Required             []string           `json:"required" yaml:"required"`
+	Items                *Schema            `json:"items" yaml:"items"`
+	AllOf                []*Schema          `json:"allOf" yaml:"allOf"`
+	AdditionalProperties *Additional        `json:"additionalProperties" yaml:"additionalProperties"`
+	Enum                 []interface{}      `json:"enum" yaml:"enum"`
+	Nullable             bool               `json:"nullable" yaml:"nullable"`
+}
+
+// Additional additionalProperties 的取值，可以是布尔值或 Schema
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// UnmarshalJSON 兼容布尔值与 Schema 两种写法
+func (a *Additional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+// UnmarshalYAML 兼容布尔值与 Schema 两种写法
+func (a *Additional) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&a.Allowed)
+	}
+	a.Allowed = true
+	return node.Decode(&a.Schema)
+}
+
+// Parse 解析 JSON 或 YAML 格式的 OpenAPI 文档
+func Parse(data []byte) (*Spec, error) {
+	var spec Spec
+	trimmed := bytes.TrimSpace(data)
+	var err error
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(trimmed, &spec)
+	} else {
+		err = yaml.Unmarshal(trimmed, &spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("openapi: parse spec: %w", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return nil, fmt.Errorf("openapi: unsupported version %q, only 3.x is supported", spec.OpenAPI)
+	}
+	return &spec, nil
+}
+
+// ParseFile 读取并解析 OpenAPI 文档文件
+func ParseFile(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// refName 返回 "#/components/xxx/Name" 形式引用的名称
+func refName(ref string) string {
+	return ref[strings.LastIndexByte(ref, '/')+1:]
+}
+
+// parameter 解析参数引用
+func (s *Spec) parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	if r, ok := s.Components.Parameters[refName(p.Ref)]; ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("openapi: unresolved parameter %s", p.Ref)
+}
+
+// requestBody 解析请求体引用
+func (s *Spec) requestBody(b *RequestBody) (*RequestBody, error) {
+	if b == nil || b.Ref == "" {
+		return b, nil
+	}
+	if r, ok := s.Components.RequestBodies[refName(b.Ref)]; ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("openapi: unresolved request body %s", b.Ref)
+}
+
+// response 解析响应引用
+func (s *Spec) response(r *Response) (*Response, error) {
+	if r == nil || r.Ref == "" {
+		return r, nil
+	}
+	if resolved, ok := s.Components.Responses[refName(r.Ref)]; ok {
+		return resolved, nil
+	}
+	return nil, fmt.Errorf("openapi: unresolved response %s", r.Ref)
+}
+
+// schema 解析 Schema 引用（只解析一层，用于 allOf 合并属性）
+func (s *Spec) schema(sc *Schema) (*Schema, error) {
+	if sc == nil || sc.Ref == "" {
+		return sc, nil
+	}
+	if r, ok := s.Components.Schemas[refName(sc.Ref)]; ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("openapi: unresolved schema %s", sc.Ref)
+}
+
+// jsonContent 取 application/json（或 +json 结尾）内容的 Schema
+func jsonContent(content map[string]*MediaType) *Schema {
+	if mt, ok := content["application/json"]; ok && mt != nil {
+		return mt.Schema
+	}
+	types := make([]string, 0, len(content))
+	for ct := range content {
+		types = append(types, ct)
+	}
+	sort.Strings(types)
+	for _, ct := range types {
+		if mt := content[ct]; mt != nil && (strings.HasSuffix(ct, "+json") || strings.HasPrefix(ct, "application/json")) {
+			return mt.Schema
+		}
+	}
+	return nil
+}
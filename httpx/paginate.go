@@ -0,0 +1,269 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/utils/backoff"
+)
+
+// PageRequest 分页请求描述
+type PageRequest struct {
+	Path    string            // 相对路径或绝对 URL（Link 头返回的通常是绝对 URL）
+	Query   map[string]string // query 参数
+	Headers http.Header       // 额外请求头
+}
+
+// Page 一页响应
+type Page struct {
+	Index    int            // 页序号，从 0 开始
+	Request  PageRequest    // 本页的请求
+	Response *http.Response // 响应
+	Body     []byte         // 响应体
+}
+
+// NextPageFunc 根据当前页计算下一页请求，返回 nil 表示没有下一页
+type NextPageFunc func(page *Page) (*PageRequest, error)
+
+// NextByLinkHeader 按 RFC 8288 Link 头中 rel="next" 的地址翻页（GitHub 等 API 的风格）
+func NextByLinkHeader() NextPageFunc {
+	return func(page *Page) (*PageRequest, error) {
+		next := parseLinkNext(page.Response.Header.Values("Link"))
+		if next == "" {
+			return nil, nil
+		}
+		return &PageRequest{Path: next, Headers: page.Request.Headers}, nil
+	}
+}
+
+// NextByCursor 从 JSON 响应体中读取游标字段（支持 "data.next_cursor" 形式的点路径），
+// 并作为 query 参数 param 发起下一页请求；游标为空、null 或 false 时结束
+func NextByCursor(field, param string) NextPageFunc {
+	return func(page *Page) (*PageRequest, error) {
+		// 数字游标保持原文，超过 2^53 的整数经 float64 会丢失精度
+		var doc interface{}
+		dec := json.NewDecoder(bytes.NewReader(page.Body))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("decode cursor: %w", err)
+		}
+		for _, key := range strings.Split(field, ".") {
+			m, ok := doc.(map[string]interface{})
+			if !ok {
+				return nil, nil
+			}
+			doc = m[key]
+		}
+		var cursor string
+		switch v := doc.(type) {
+		case nil, bool:
+			return nil, nil
+		case string:
+			cursor = v
+		case json.Number:
+			cursor = v.String()
+		default:
+			return nil, fmt.Errorf("unsupported cursor type %T", v)
+		}
+		if cursor == "" {
+			return nil, nil
+		}
+		next := page.Request
+		next.Query = make(map[string]string, len(page.Request.Query)+1)
+		for k, v := range page.Request.Query {
+			next.Query[k] = v
+		}
+		next.Query[param] = cursor
+		return &next, nil
+	}
+}
+
+// PageOption 分页选项
+type PageOption func(*Pager)
+
+// WithMaxPages 限制最多拉取的页数，0 表示不限制
+func WithMaxPages(n int) PageOption { return func(p *Pager) { p.maxPages = n } }
+
+// WithPageInterval 设置两页请求之间的最小间隔，用于主动限速
+func WithPageInterval(d time.Duration) PageOption { return func(p *Pager) { p.interval = d } }
+
+// WithRateLimitRetries 设置遇到 429 时的最大重试次数（默认 3 次），等待时间取自 Retry-After，
+// 缺省时从 1 秒起指数退避，均不超过 WithRetryMaxDelay 设置的上限
+func WithRateLimitRetries(n int) PageOption { return func(p *Pager) { p.maxRetries = n } }
+
+// Pager 分页迭代器，用法与 bufio.Scanner 类似
+// 实用场景: 从上游分页 API 同步全量数据
+// 使用示例：
+//
+//	p := c.Paginate(ctx, httpx.PageRequest{Path: "/repos"}, httpx.NextByLinkHeader())
+//	for p.Next() {
+//		handle(p.Page().Body)
+//	}
+//	if err := p.Err(); err != nil { ... }
+type Pager struct {
+	c    *Client
+	ctx  context.Context
+	next NextPageFunc
+
+	req        *PageRequest
+	page       *Page
+	err        error
+	index      int
+	lastAt     time.Time
+	waitUntil  time.Time // 由限流响应头推算出的下次可请求时间
+	maxPages   int
+	interval   time.Duration
+	maxRetries int
+}
+
+// Paginate 创建分页迭代器，首页请求为 req，后续页由 next 计算
+func (c *Client) Paginate(ctx context.Context, req PageRequest, next NextPageFunc, opts ...PageOption) *Pager {
+	p := &Pager{c: c, ctx: ctx, next: next, req: &req, maxRetries: 3}
+	for _, o := range opts {
+		o(p)
+	}
+	return p
+}
+
+// Next 拉取下一页，没有更多数据或出错时返回 false；
+// 本页已拉取但计算下一页失败时仍返回 true，本页可正常处理，错误由之后的 Next 返回 false 与 Err 体现
+func (p *Pager) Next() bool {
+	if p.err != nil || p.req == nil || (p.maxPages > 0 && p.index >= p.maxPages) {
+		return false
+	}
+	if p.next == nil {
+		p.err = errors.New("next page func must not be nil")
+		return false
+	}
+
+	resp, body, err := p.fetch(*p.req)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	p.page = &Page{Index: p.index, Request: *p.req, Response: resp, Body: body}
+	p.index++
+
+	p.req, p.err = p.next(p.page)
+	if p.err != nil {
+		p.req = nil
+	}
+	return true
+}
+
+// Page 返回当前页
+func (p *Pager) Page() *Page { return p.page }
+
+// Err 返回迭代过程中的错误
+func (p *Pager) Err() error { return p.err }
+
+// fetch 请求一页，处理主动限速与 429 重试
+func (p *Pager) fetch(req PageRequest) (*http.Response, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		wait := time.Until(p.waitUntil)
+		if p.interval > 0 && !p.lastAt.IsZero() {
+			if d := p.interval - time.Since(p.lastAt); d > wait {
+				wait = d
+			}
+		}
+		if err := sleepCtx(p.ctx, wait); err != nil {
+			return nil, nil, err
+		}
+
+		p.lastAt = time.Now()
+		resp, body, err := p.c.Get(p.ctx, req.Path, req.Query, req.Headers)
+		if resp != nil {
+			p.waitUntil = rateLimitReset(resp.Header)
+		}
+
+		var se *StatusError
+		if errors.As(err, &se) && se.StatusCode == http.StatusTooManyRequests && attempt < p.maxRetries {
+			p.waitUntil = time.Now().Add(p.rateLimitDelay(resp.Header, attempt))
+			continue
+		}
+		return resp, body, err
+	}
+}
+
+// rateLimitDelay 第 attempt 次 429 后的等待时间：Retry-After 优先，否则指数退避，均不超过客户端的重试等待上限
+func (p *Pager) rateLimitDelay(h http.Header, attempt int) time.Duration {
+	max := p.c.maxDelay
+	d := retryAfter(h, backoff.Exponential(time.Second, max).Delay(attempt, 0))
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// parseLinkNext 解析 Link 头中 rel="next" 的地址
+func parseLinkNext(values []string) string {
+	for _, v := range values {
+		for _, link := range strings.Split(v, ",") {
+			parts := strings.Split(link, ";")
+			if len(parts) < 2 {
+				continue
+			}
+			target := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			for _, attr := range parts[1:] {
+				attr = strings.ReplaceAll(strings.TrimSpace(attr), " ", "")
+				if attr == `rel="next"` || attr == "rel=next" {
+					return target
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// retryAfter 解析 Retry-After（秒数或 HTTP 日期），缺省时使用 fallback
+func retryAfter(h http.Header, fallback time.Duration) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return fallback
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return fallback
+}
+
+// rateLimitReset 剩余额度为 0 时，根据 X-RateLimit-Reset（Unix 时间戳或剩余秒数）推算恢复时间
+func rateLimitReset(h http.Header) time.Time {
+	if h.Get("X-RateLimit-Remaining") != "0" {
+		return time.Time{}
+	}
+	reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	// 大于 10 年的秒数视为 Unix 时间戳
+	if reset > 10*365*24*3600 {
+		return time.Unix(reset, 0)
+	}
+	return time.Now().Add(time.Duration(reset) * time.Second)
+}
+
+// sleepCtx 可被 context 取消的等待
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
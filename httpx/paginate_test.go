@@ -0,0 +1,156 @@
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPaginate_LinkHeader(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 2 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=%d>; rel="next", <%s/items?page=2>; rel="last"`, srv.URL, page+1, srv.URL))
+		}
+		_, _ = fmt.Fprintf(w, "page-%d", page)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	p := c.Paginate(context.Background(), PageRequest{Path: "/items", Query: map[string]string{"page": "0"}}, NextByLinkHeader())
+
+	var got []string
+	for p.Next() {
+		got = append(got, string(p.Page().Body))
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("Paginate error: %v", err)
+	}
+	if fmt.Sprint(got) != "[page-0 page-1 page-2]" {
+		t.Errorf("unexpected pages: %v", got)
+	}
+}
+
+func TestPaginate_CursorAndRateLimit(t *testing.T) {
+	var limited int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		if cursor == "c1" && atomic.CompareAndSwapInt32(&limited, 0, 1) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch cursor {
+		case "":
+			_, _ = w.Write([]byte(`{"items":[1,2],"meta":{"next":"c1"}}`))
+		case "c1":
+			_, _ = w.Write([]byte(`{"items":[3],"meta":{"next":null}}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	p := c.Paginate(context.Background(), PageRequest{Path: "/list", Query: map[string]string{"size": "2"}}, NextByCursor("meta.next", "cursor"))
+
+	pages := 0
+	for p.Next() {
+		pages++
+		if p.Page().Request.Query["size"] != "2" {
+			t.Errorf("original query should be kept")
+		}
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("Paginate error: %v", err)
+	}
+	if pages != 2 {
+		t.Errorf("expected 2 pages, got %d", pages)
+	}
+	if atomic.LoadInt32(&limited) != 1 {
+		t.Errorf("expected one rate limited response")
+	}
+}
+
+// TestPager_RateLimitDelay 429 的等待时间不超过 RetryMaxDelay，重试次数很大时也不会溢出为负数
+func TestPager_RateLimitDelay(t *testing.T) {
+	p := NewClient(WithRetryMaxDelay(5*time.Second)).Paginate(context.Background(), PageRequest{}, NextByLinkHeader())
+	cases := []struct {
+		name       string
+		retryAfter string
+		attempt    int
+		want       time.Duration
+	}{
+		{"backoff first attempt", "", 0, time.Second},
+		{"backoff grows", "", 2, 4 * time.Second},
+		{"backoff capped", "", 10, 5 * time.Second},
+		{"backoff no overflow", "", 40, 5 * time.Second},
+		{"retry-after honored", "2", 0, 2 * time.Second},
+		{"retry-after zero", "0", 3, 0},
+		{"retry-after capped", "86400", 0, 5 * time.Second},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			if tc.retryAfter != "" {
+				h.Set("Retry-After", tc.retryAfter)
+			}
+			if got := p.rateLimitDelay(h, tc.attempt); got != tc.want {
+				t.Errorf("rateLimitDelay = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPaginate_MaxPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"next":"again"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	p := c.Paginate(context.Background(), PageRequest{Path: "/loop"}, NextByCursor("next", "cursor"), WithMaxPages(3))
+	pages := 0
+	for p.Next() {
+		pages++
+	}
+	if pages != 3 || p.Err() != nil {
+		t.Errorf("expected 3 pages without error, got %d, %v", pages, p.Err())
+	}
+}
+
+// TestPaginate_LargeCursorAndNextError 整数游标不经 float64 转换；计算下一页出错时本页仍可处理
+func TestPaginate_LargeCursorAndNextError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			_, _ = w.Write([]byte(`{"next":9007199254740993}`))
+		case "9007199254740993":
+			_, _ = w.Write([]byte(`{"next":{"bad":true}}`))
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	p := c.Paginate(context.Background(), PageRequest{Path: "/big"}, NextByCursor("next", "cursor"))
+	var bodies []string
+	for p.Next() {
+		bodies = append(bodies, string(p.Page().Body))
+	}
+	if len(bodies) != 2 || bodies[1] != `{"next":{"bad":true}}` {
+		t.Errorf("pages = %v", bodies)
+	}
+	if p.Err() == nil {
+		t.Error("expected cursor type error")
+	}
+	if p.Next() {
+		t.Error("Next should stay false after an error")
+	}
+}
@@ -0,0 +1,71 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+)
+
+// RetryAttemptHeader 重试时携带的请求头，值为第几次重试（首次请求不带），便于下游识别重复请求
+const RetryAttemptHeader = "X-Retry-Attempt"
+
+// WithClientName 设置客户端名称（如下游服务名），写入每次请求的 RequestInfo 与日志字段
+func WithClientName(name string) Option {
+	return func(o *ClientOptions) { o.Name = name }
+}
+
+// RequestInfo 单次出站请求的元数据，随请求 context 传递给 Transport 中间件与日志
+type RequestInfo struct {
+	Client         string // 客户端名称，见 WithClientName
+	Method         string
+	URL            string
+	Attempt        int    // 第几次尝试，从 1 开始，大于 1 表示重试
+	IdempotencyKey string // 请求头 Idempotency-Key 的值
+}
+
+// reqInfoKey RequestInfo 的 context key
+type reqInfoKey struct{}
+
+// RequestInfoFromContext 读取出站请求的元数据，用于自定义 Transport（WithTransport）中记录日志或打点
+// 使用示例：
+//
+//	func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+//		info, _ := httpx.RequestInfoFromContext(req.Context())
+//		logger.Info(req.Context(), "outbound request") // 日志自动带上 httpClient、attempt 字段
+//		...
+//	}
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(reqInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// withRequestInfo 返回携带本次尝试元数据的请求：写入 RequestInfo 与 logger 字段，重试时设置 RetryAttemptHeader
+func (c *Client) withRequestInfo(req *http.Request, attempt int) *http.Request {
+	info := RequestInfo{
+		Client:         c.name,
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		Attempt:        attempt,
+		IdempotencyKey: req.Header.Get("Idempotency-Key"),
+	}
+	fields := make([]zap.Field, 0, 3)
+	if info.Client != "" {
+		fields = append(fields, zap.String("httpClient", info.Client))
+	}
+	fields = append(fields, zap.Int("attempt", attempt))
+	if info.IdempotencyKey != "" {
+		fields = append(fields, zap.String("idempotencyKey", info.IdempotencyKey))
+	}
+	ctx := logger.AppendCtx(context.WithValue(req.Context(), reqInfoKey{}, info), fields...)
+
+	r := req.WithContext(ctx)
+	if attempt > 1 {
+		r.Header = req.Header.Clone()
+		r.Header.Set(RetryAttemptHeader, strconv.Itoa(attempt-1))
+	}
+	return r
+}
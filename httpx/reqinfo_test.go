@@ -0,0 +1,82 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+)
+
+// recordTransport 记录每次请求的 RequestInfo 与日志字段
+type recordTransport struct {
+	mu     sync.Mutex
+	infos  []RequestInfo
+	fields [][]string
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	info, _ := RequestInfoFromContext(req.Context())
+	var keys []string
+	for _, f := range logger.FieldsFromContext(req.Context()) {
+		keys = append(keys, f.Key)
+	}
+	t.mu.Lock()
+	t.infos = append(t.infos, info)
+	t.fields = append(t.fields, keys)
+	t.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClient_RequestInfo(t *testing.T) {
+	var attempts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts = append(attempts, r.Header.Get(RetryAttemptHeader))
+		if len(attempts) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	rt := &recordTransport{}
+	c := NewClient(WithClientName("payment"), WithTransport(rt), WithRetry(2, time.Millisecond), WithRetryBudget(-1, 0))
+	_, _, err := c.Post(context.Background(), srv.URL+"/charge", []byte("{}"), "application/json", http.Header{"Idempotency-Key": {"order-1"}}, nil)
+	if err != nil {
+		t.Fatalf("Post error: %v", err)
+	}
+
+	if len(rt.infos) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(rt.infos))
+	}
+	for i, info := range rt.infos {
+		if info.Client != "payment" || info.Attempt != i+1 || info.IdempotencyKey != "order-1" || info.Method != http.MethodPost {
+			t.Errorf("attempt %d: unexpected info %+v", i+1, info)
+		}
+		if len(rt.fields[i]) != 3 {
+			t.Errorf("attempt %d: unexpected log fields %v", i+1, rt.fields[i])
+		}
+	}
+	if attempts[0] != "" || attempts[1] != "1" || attempts[2] != "2" {
+		t.Errorf("unexpected %s headers: %q", RetryAttemptHeader, attempts)
+	}
+}
+
+func TestClient_RequestInfoWithoutRetry(t *testing.T) {
+	srv := newEchoServer()
+	defer srv.Close()
+
+	rt := &recordTransport{}
+	c := NewClient(WithTransport(rt))
+	if _, _, err := c.Get(context.Background(), srv.URL, nil, nil); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if len(rt.infos) != 1 || rt.infos[0].Attempt != 1 || rt.infos[0].Client != "" {
+		t.Fatalf("unexpected infos: %+v", rt.infos)
+	}
+	if _, ok := RequestInfoFromContext(context.Background()); ok {
+		t.Error("empty context should not carry RequestInfo")
+	}
+}
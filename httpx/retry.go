@@ -0,0 +1,193 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/utils/backoff"
+)
+
+const (
+	// defaultRetryBaseDelay 默认的首次重试等待时间
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	// maxRetryDelay 默认的单次重试等待上限
+	maxRetryDelay = 10 * time.Second
+	// defaultRetryBudgetRatio 默认重试预算：重试次数不超过请求数的 10%
+	defaultRetryBudgetRatio = 0.1
+	// defaultRetryBudgetMinPerSec 预算的保底值，低流量时也允许少量重试
+	defaultRetryBudgetMinPerSec = 1
+	// retryBudgetWindow 预算统计窗口（秒）
+	retryBudgetWindow = 10
+)
+
+// WithRetry 对幂等请求（GET/HEAD/OPTIONS/PUT/DELETE，或带 Idempotency-Key 请求头）在网络错误与
+// 429/502/503/504 时重试，最多 maxRetries 次，等待时间从 baseDelay（<= 0 时为 100ms）起指数增长并加随机抖动，
+// 响应带 Retry-After 时以其为准（不超过 WithRetryMaxDelay 设置的上限）；请求体不可重放（无 GetBody）时不重试
+// 重试受客户端级别的重试预算约束（默认不超过请求数的 10%），见 WithRetryBudget
+func WithRetry(maxRetries int, baseDelay time.Duration) Option {
+	return func(o *ClientOptions) {
+		o.MaxRetries = maxRetries
+		o.RetryBaseDelay = baseDelay
+	}
+}
+
+// WithRetryMaxDelay 设置单次重试等待的上限（默认 10s），指数退避与服务端返回的 Retry-After 都不会超过该值，
+// Paginate 遇到 429 时的等待同样受其限制
+func WithRetryMaxDelay(d time.Duration) Option {
+	return func(o *ClientOptions) { o.RetryMaxDelay = d }
+}
+
+// WithRetryBudget 设置客户端级别的重试预算：最近 10 秒内的重试次数不超过 ratio*请求数 + minPerSec*10，
+// 超出时直接返回本次结果而不再重试，避免下游故障时重试风暴放大流量；
+// 未设置时默认 ratio 为 0.1、minPerSec 为 1，ratio < 0 表示不限制
+func WithRetryBudget(ratio float64, minPerSec int) Option {
+	return func(o *ClientOptions) {
+		o.RetryBudgetRatio = ratio
+		o.RetryBudgetMinPerSec = minPerSec
+	}
+}
+
+// WithMaxInFlight 限制客户端同时进行中的请求数（含读取响应体），超出时等待空位直到 context 结束；
+// n <= 0 表示不限制
+func WithMaxInFlight(n int) Option {
+	return func(o *ClientOptions) { o.MaxInFlight = n }
+}
+
+// retryPolicy 重试策略
+type retryPolicy struct {
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	budget     *retryBudget // nil 表示不限制
+}
+
+// newRetryPolicy 根据选项创建重试策略，未开启重试时返回 nil
+func newRetryPolicy(opts *ClientOptions) *retryPolicy {
+	if opts.MaxRetries <= 0 {
+		return nil
+	}
+	p := &retryPolicy{maxRetries: opts.MaxRetries, baseDelay: opts.RetryBaseDelay, maxDelay: opts.RetryMaxDelay}
+	if p.baseDelay <= 0 {
+		p.baseDelay = defaultRetryBaseDelay
+	}
+	if p.maxDelay <= 0 {
+		p.maxDelay = maxRetryDelay
+	}
+	ratio, minPerSec := opts.RetryBudgetRatio, opts.RetryBudgetMinPerSec
+	if ratio == 0 && minPerSec == 0 {
+		ratio, minPerSec = defaultRetryBudgetRatio, defaultRetryBudgetMinPerSec
+	}
+	if ratio >= 0 {
+		p.budget = &retryBudget{ratio: ratio, minPerSec: minPerSec}
+	}
+	return p
+}
+
+// retryable 判断本次结果是否值得重试
+func (p *retryPolicy) retryable(req *http.Request, resp *http.Response, err error, attempt int) bool {
+	if attempt >= p.maxRetries || req.Context().Err() != nil || !idempotent(req) {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		switch se.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	// resp 不为 nil 说明请求已完成，错误来自读取响应体（如 DecompressionError），不重试
+	return err != nil && resp == nil
+}
+
+// delay 第 attempt 次重试前的等待时间：Retry-After 优先，否则指数退避加 full jitter，均不超过 maxDelay，
+// 避免服务端返回过大的 Retry-After 让调用方长时间阻塞
+func (p *retryPolicy) delay(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		if d := retryAfter(resp.Header, 0); d > 0 {
+			if d > p.maxDelay {
+				d = p.maxDelay
+			}
+			return d
+		}
+	}
+	return backoff.FullJitter(backoff.Exponential(p.baseDelay, p.maxDelay)).Delay(attempt, 0)
+}
+
+// idempotent 判断请求是否可以安全重试
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// retryBudget 按秒分桶统计最近 retryBudgetWindow 秒的请求数与重试数
+type retryBudget struct {
+	ratio     float64
+	minPerSec int
+
+	mu      sync.Mutex
+	buckets [retryBudgetWindow]budgetBucket
+}
+
+type budgetBucket struct {
+	sec      int64
+	requests int
+	retries  int
+}
+
+// bucket 返回当前秒的桶（调用方需持有锁）
+func (b *retryBudget) bucket(now time.Time) *budgetBucket {
+	sec := now.Unix()
+	bk := &b.buckets[sec%retryBudgetWindow]
+	if bk.sec != sec {
+		*bk = budgetBucket{sec: sec}
+	}
+	return bk
+}
+
+// request 记录一次请求
+func (b *retryBudget) request(now time.Time) {
+	b.mu.Lock()
+	b.bucket(now).requests++
+	b.mu.Unlock()
+}
+
+// withdraw 预算允许时记录一次重试并返回 true
+func (b *retryBudget) withdraw(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var requests, retries int
+	for _, bk := range b.buckets {
+		if now.Unix()-bk.sec < retryBudgetWindow {
+			requests += bk.requests
+			retries += bk.retries
+		}
+	}
+	if float64(retries) >= b.ratio*float64(requests)+float64(b.minPerSec*retryBudgetWindow) {
+		return false
+	}
+	b.bucket(now).retries++
+	return true
+}
+
+// acquire 占用一个并发名额，未限制时直接返回
+func (c *Client) acquire(ctx context.Context) (release func(), err error) {
+	if c.inFlight == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.inFlight <- struct{}{}:
+		return func() { <-c.inFlight }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,155 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Retry(t *testing.T) {
+	var hits atomic.Int32
+	var bodies []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(b))
+		mu.Unlock()
+		if hits.Add(1)%3 != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithRetry(3, time.Millisecond), WithRetryBudget(-1, 0))
+	ctx := context.Background()
+
+	_, body, err := c.Get(ctx, "/", nil, nil)
+	if err != nil || string(body) != "ok" || hits.Load() != 3 {
+		t.Fatalf("Get: body=%q err=%v hits=%d", body, err, hits.Load())
+	}
+
+	// 非幂等请求不重试
+	hits.Store(0)
+	_, _, err = c.Post(ctx, "/", []byte("payload"), "text/plain", nil, nil)
+	var se *StatusError
+	if !errors.As(err, &se) || hits.Load() != 1 {
+		t.Fatalf("Post should not retry: err=%v hits=%d", err, hits.Load())
+	}
+
+	// 带 Idempotency-Key 的 POST 会重试，且每次都重放请求体
+	hits.Store(0)
+	mu.Lock()
+	bodies = nil
+	mu.Unlock()
+	_, body, err = c.Post(ctx, "/", []byte("payload"), "text/plain", http.Header{"Idempotency-Key": {"k-1"}}, nil)
+	if err != nil || string(body) != "ok" {
+		t.Fatalf("idempotent Post: body=%q err=%v", body, err)
+	}
+	for _, b := range bodies {
+		if b != "payload" {
+			t.Errorf("request body not replayed: %q", bodies)
+		}
+	}
+}
+
+func TestClient_RetryNonRetryableStatus(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithRetry(3, time.Millisecond))
+	if _, _, err := c.Get(context.Background(), srv.URL, nil, nil); err == nil || hits.Load() != 1 {
+		t.Fatalf("400 should not be retried: err=%v hits=%d", err, hits.Load())
+	}
+}
+
+func TestClient_RetryBudget(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	// 重试次数不超过请求数的一半，且没有保底额度
+	c := NewClient(WithRetry(3, time.Millisecond), WithRetryBudget(0.5, 0))
+	for i := 0; i < 4; i++ {
+		_, _, _ = c.Get(context.Background(), srv.URL, nil, nil)
+	}
+	// 第 1、3 个请求各重试一次，其余请求预算不足
+	if hits.Load() != 6 {
+		t.Fatalf("hits = %d, want 6", hits.Load())
+	}
+}
+
+func TestRetryPolicy_DelayRetryAfter(t *testing.T) {
+	p := newRetryPolicy(&ClientOptions{MaxRetries: 3, RetryBaseDelay: time.Second})
+	resp := &http.Response{Header: http.Header{"Retry-After": {"3"}}}
+	if d := p.delay(resp, 0); d != 3*time.Second {
+		t.Errorf("delay = %v, want 3s", d)
+	}
+	resp.Header.Set("Retry-After", "7200")
+	if d := p.delay(resp, 0); d != maxRetryDelay {
+		t.Errorf("delay = %v, want capped at %v", d, maxRetryDelay)
+	}
+	capped := newRetryPolicy(&ClientOptions{MaxRetries: 3, RetryMaxDelay: 2 * time.Second})
+	if d := capped.delay(resp, 0); d != 2*time.Second {
+		t.Errorf("delay = %v, want 2s", d)
+	}
+	for attempt := 0; attempt < 40; attempt++ {
+		if d := p.delay(nil, attempt); d <= 0 || d > maxRetryDelay {
+			t.Fatalf("attempt %d: delay %v out of range", attempt, d)
+		}
+	}
+}
+
+func TestClient_MaxInFlight(t *testing.T) {
+	var cur, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := cur.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		cur.Add(-1)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithMaxInFlight(2))
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _ = c.Get(context.Background(), srv.URL, nil, nil)
+		}()
+	}
+	wg.Wait()
+	if peak.Load() != 2 {
+		t.Errorf("peak concurrency = %d, want 2", peak.Load())
+	}
+
+	// 等待名额时遵循 context
+	c.inFlight <- struct{}{}
+	c.inFlight <- struct{}{}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := c.Get(ctx, srv.URL, nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
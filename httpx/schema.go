@@ -0,0 +1,182 @@
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+)
+
+// jsonSchema 支持的 JSON Schema 关键字子集
+type jsonSchema struct {
+	Type                 interface{}            `json:"type"` // string 或 []string
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []interface{}          `json:"enum"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+}
+
+// ValidateJSONSchema 使用 JSON Schema 校验 JSON 文档
+// 支持的关键字: type、properties、required、additionalProperties(bool)、items、enum、
+// minLength、maxLength、pattern、minimum、maximum、minItems、maxItems
+func ValidateJSONSchema(schema, doc []byte) error {
+	var s jsonSchema
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return fmt.Errorf("invalid json schema: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("invalid json body: %w", err)
+	}
+	return s.validate("$", v)
+}
+
+// validate 递归校验，path 为 JSONPath 风格的位置
+func (s *jsonSchema) validate(path string, v interface{}) error {
+	if s == nil {
+		return nil
+	}
+	if err := s.checkType(path, v); err != nil {
+		return err
+	}
+	if len(s.Enum) > 0 && !s.inEnum(v) {
+		return fmt.Errorf("%s: value %v not in enum", path, v)
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ps, ok := s.Properties[k]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s: additional property %q not allowed", path, k)
+				}
+				continue
+			}
+			if err := ps.validate(path+"."+k, val[k]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			return fmt.Errorf("%s: expected at least %d items, got %d", path, *s.MinItems, len(val))
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			return fmt.Errorf("%s: expected at most %d items, got %d", path, *s.MaxItems, len(val))
+		}
+		for i, item := range val {
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+	case string:
+		n := len([]rune(val))
+		if s.MinLength != nil && n < *s.MinLength {
+			return fmt.Errorf("%s: length %d less than %d", path, n, *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return fmt.Errorf("%s: length %d greater than %d", path, n, *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			re, err := regexp.Compile(s.Pattern)
+			if err != nil {
+				return fmt.Errorf("%s: invalid pattern: %w", path, err)
+			}
+			if !re.MatchString(val) {
+				return fmt.Errorf("%s: %q does not match pattern %s", path, val, s.Pattern)
+			}
+		}
+	case json.Number:
+		f, _ := val.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			return fmt.Errorf("%s: %v less than minimum %v", path, f, *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return fmt.Errorf("%s: %v greater than maximum %v", path, f, *s.Maximum)
+		}
+	}
+	return nil
+}
+
+// checkType 校验 type 关键字
+func (s *jsonSchema) checkType(path string, v interface{}) error {
+	var types []string
+	switch t := s.Type.(type) {
+	case nil:
+		return nil
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, x := range t {
+			if str, ok := x.(string); ok {
+				types = append(types, str)
+			}
+		}
+	}
+	actual := jsonType(v)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: expected type %v, got %s", path, s.Type, actual)
+}
+
+// inEnum 判断值是否在枚举中
+func (s *jsonSchema) inEnum(v interface{}) bool {
+	for _, e := range s.Enum {
+		if fmt.Sprint(e) == fmt.Sprint(v) && (jsonType(v) == "string") == isString(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType 返回 JSON 值的类型名
+func jsonType(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if f, err := val.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
+
+func isString(v interface{}) bool {
+	_, ok := v.(string)
+	return ok
+}
@@ -0,0 +1,85 @@
+package httpx
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// PostReader 以流式 body 发送 POST 请求，不会把整个请求体读入内存
+// size 为请求体长度，<= 0 表示未提供（对于 io.Seeker 会自动计算，否则沿用 http.NewRequest 推断的长度或使用 chunked 编码）
+// 若 body 实现了 io.Seeker（如 *os.File），会自动设置 GetBody，以便重定向或连接重试时重放请求体；
+// body 的关闭由调用方负责
+func (c *Client) PostReader(ctx context.Context, path string, body io.Reader, size int64, contentType string, headers http.Header, query map[string]string) (*http.Response, []byte, error) {
+	return c.doReader(ctx, http.MethodPost, path, body, size, headers, query, contentType)
+}
+
+// PutReader 以流式 body 发送 PUT 请求，参数含义同 PostReader
+func (c *Client) PutReader(ctx context.Context, path string, body io.Reader, size int64, contentType string, headers http.Header, query map[string]string) (*http.Response, []byte, error) {
+	return c.doReader(ctx, http.MethodPut, path, body, size, headers, query, contentType)
+}
+
+// PatchReader 以流式 body 发送 PATCH 请求，参数含义同 PostReader
+func (c *Client) PatchReader(ctx context.Context, path string, body io.Reader, size int64, contentType string, headers http.Header, query map[string]string) (*http.Response, []byte, error) {
+	return c.doReader(ctx, http.MethodPatch, path, body, size, headers, query, contentType)
+}
+
+// doReader 创建流式请求，设置 Content-Length 与 GetBody 后发送
+func (c *Client) doReader(ctx context.Context, method, path string, body io.Reader, size int64, headers http.Header, query map[string]string, contentType string) (*http.Response, []byte, error) {
+	req, err := c.newRequest(ctx, method, path, body, headers, query, contentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := setStreamBody(req, body, size); err != nil {
+		return nil, nil, err
+	}
+	return c.send(req)
+}
+
+// setStreamBody 设置请求体长度与可重放的 GetBody
+// bytes.Reader / strings.Reader 等类型已由 http.NewRequest 处理，这里只补充 io.Seeker 的情况
+func setStreamBody(req *http.Request, body io.Reader, size int64) error {
+	if body == nil {
+		return nil
+	}
+	measured := false // size 是否为从 Seeker 实测得到的长度
+	seeker, ok := body.(io.ReadSeeker)
+	if ok && req.GetBody == nil {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		if size <= 0 {
+			end, err := seeker.Seek(0, io.SeekEnd)
+			if err != nil {
+				return err
+			}
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return err
+			}
+			size = end - start
+			measured = true
+		}
+		// 用 NopCloser 包装，避免 Transport 发送完成后关闭调用方的文件，导致无法重放
+		req.Body = io.NopCloser(seeker)
+		req.GetBody = func() (io.ReadCloser, error) {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return io.NopCloser(seeker), nil
+		}
+	}
+
+	switch {
+	case size > 0:
+		req.ContentLength = size
+	case measured:
+		// Seeker 剩余内容确实为空
+		req.ContentLength = 0
+		req.Body = http.NoBody
+		req.GetBody = func() (io.ReadCloser, error) { return http.NoBody, nil }
+	case req.ContentLength == 0 && req.Body != http.NoBody:
+		req.ContentLength = -1 // 长度未知，使用 chunked 编码
+	}
+	return nil
+}
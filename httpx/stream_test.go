@@ -0,0 +1,120 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type streamEcho struct {
+	Body             string   `json:"body"`
+	ContentLength    int64    `json:"content_length"`
+	TransferEncoding []string `json:"transfer_encoding"`
+}
+
+func newStreamServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 307 重定向要求客户端重放请求体
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/upload", http.StatusTemporaryRedirect)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.NewEncoder(w).Encode(streamEcho{Body: string(body), ContentLength: r.ContentLength, TransferEncoding: r.TransferEncoding})
+	}))
+}
+
+func TestClient_PostReader_Chunked(t *testing.T) {
+	srv := newStreamServer()
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	// 使用 io.MultiReader 隐藏具体类型，模拟长度未知的流
+	body := io.MultiReader(strings.NewReader("hello "), strings.NewReader("stream"))
+	_, respBody, err := c.PostReader(context.Background(), "/upload", body, -1, "text/plain", nil, nil)
+	if err != nil {
+		t.Fatalf("PostReader error: %v", err)
+	}
+	var echo streamEcho
+	if err := json.Unmarshal(respBody, &echo); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if echo.Body != "hello stream" {
+		t.Errorf("body mismatch: %s", echo.Body)
+	}
+	if len(echo.TransferEncoding) == 0 || echo.TransferEncoding[0] != "chunked" {
+		t.Errorf("expected chunked transfer encoding, got %v", echo.TransferEncoding)
+	}
+}
+
+func TestClient_PutReader_SeekerReplay(t *testing.T) {
+	srv := newStreamServer()
+	defer srv.Close()
+
+	p := filepath.Join(t.TempDir(), "payload.bin")
+	content := strings.Repeat("0123456789", 100)
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	_, respBody, err := c.PutReader(context.Background(), "/redirect", f, -1, "application/octet-stream", nil, nil)
+	if err != nil {
+		t.Fatalf("PutReader error: %v", err)
+	}
+	var echo streamEcho
+	if err := json.Unmarshal(respBody, &echo); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if echo.Body != content {
+		t.Errorf("body should be replayed after redirect, got %d bytes", len(echo.Body))
+	}
+	if echo.ContentLength != int64(len(content)) {
+		t.Errorf("content length = %s", strconv.FormatInt(echo.ContentLength, 10))
+	}
+}
+
+// TestClient_PostReader_ZeroSize size 为 0 视为未提供长度，不能丢弃非空 body
+func TestClient_PostReader_ZeroSize(t *testing.T) {
+	srv := newStreamServer()
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	cases := []struct {
+		name    string
+		body    io.Reader
+		want    string
+		wantLen int64
+	}{
+		{"strings reader", strings.NewReader("payload"), "payload", 7},
+		{"unknown length stream", io.MultiReader(strings.NewReader("pay"), strings.NewReader("load")), "payload", -1},
+		{"empty reader", strings.NewReader(""), "", 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, respBody, err := c.PostReader(context.Background(), "/upload", tc.body, 0, "text/plain", nil, nil)
+			if err != nil {
+				t.Fatalf("PostReader error: %v", err)
+			}
+			var echo streamEcho
+			if err := json.Unmarshal(respBody, &echo); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if echo.Body != tc.want || echo.ContentLength != tc.wantLen {
+				t.Errorf("body = %q, content length = %d, want %q, %d", echo.Body, echo.ContentLength, tc.want, tc.wantLen)
+			}
+		})
+	}
+}
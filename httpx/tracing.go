@@ -0,0 +1,37 @@
+package httpx
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/qingfeng-studio/go-utils/utils/otelx"
+)
+
+// tracingComponent httpx 的 instrumentation scope 名称
+const tracingComponent = "httpx"
+
+// WithTracing 为每次请求尝试（含重试）创建客户端 span，并以 W3C traceparent 请求头透传给下游；
+// span 名称为 "HTTP <方法>"，属性含方法、host、path 与响应状态码（不含 query，避免泄露参数），
+// 状态码 >= 400 记为错误；需先调用 otelx.Init
+func WithTracing() Option {
+	return func(o *ClientOptions) { o.Tracing = true }
+}
+
+// startSpan 为一次请求尝试开启 span 并注入链路请求头，返回的函数在尝试结束时调用
+func startSpan(req *http.Request) (*http.Request, func(resp *http.Response, err error)) {
+	ctx, span := otelx.StartClient(req.Context(), tracingComponent, "HTTP "+req.Method,
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Hostname()),
+		attribute.String("url.path", req.URL.Path),
+	)
+	req = req.WithContext(ctx)
+	req.Header = req.Header.Clone() // 各次尝试的 span 不同，不修改调用方的请求头
+	otelx.Inject(ctx, req.Header)
+	return req, func(resp *http.Response, err error) {
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		}
+		otelx.End(span, err)
+	}
+}
@@ -0,0 +1,55 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/qingfeng-studio/go-utils/config"
+	"github.com/qingfeng-studio/go-utils/utils/otelx"
+)
+
+func TestClient_Tracing(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	shutdown, err := otelx.Init(context.Background(), config.Tracing{Enabled: true, Sampler: config.SamplerAlwaysOn},
+		otelx.WithExporter(exp), otelx.WithSyncExport())
+	if err != nil {
+		t.Fatalf("otelx.Init: %v", err)
+	}
+	defer shutdown(context.Background())
+
+	srv := newEchoServer()
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithTracing())
+
+	_, body, err := c.Get(context.Background(), "/traced", map[string]string{"token": "secret"}, nil)
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	var payload echoPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	s := spans[0]
+	if s.Name != "HTTP GET" || s.Status.Code == codes.Error {
+		t.Errorf("unexpected span: %+v", s)
+	}
+	if tp := payload.Header.Get("Traceparent"); tp == "" || tp[3:35] != s.SpanContext.TraceID().String() {
+		t.Errorf("traceparent = %q, trace id = %s", tp, s.SpanContext.TraceID())
+	}
+	for _, kv := range s.Attributes {
+		if kv.Key == "url.path" && kv.Value.AsString() != "/traced" {
+			t.Errorf("url.path = %q", kv.Value.AsString())
+		}
+		if kv.Key == "http.response.status_code" && kv.Value.AsInt64() != 200 {
+			t.Errorf("status code = %d", kv.Value.AsInt64())
+		}
+	}
+}
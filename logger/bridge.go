@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 桥接第三方库的日志输出，使依赖库的日志与业务日志共享同一个 core、切割策略和 JSON 格式
+
+// parseLevel 解析级别字符串，非法时回退到 info
+func parseLevel(level string) zapcore.Level {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return zapcore.InfoLevel
+	}
+	return lvl
+}
+
+// stdWriter 将标准库 log 的每行输出转为一条日志
+type stdWriter struct {
+	logger *zap.Logger
+	level  zapcore.Level
+}
+
+// Write 实现 io.Writer
+func (w *stdWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimRight(p, "\n"))
+	if ce := w.logger.Check(w.level, msg); ce != nil {
+		ce.Write()
+	}
+	return len(p), nil
+}
+
+// NewStdLog 返回写入 l 的标准库 *log.Logger，适用于 http.Server.ErrorLog 等只接受 *log.Logger 的场景
+// 使用示例：
+//
+//	srv := &http.Server{ErrorLog: logger.NewStdLog(l, "error")}
+func NewStdLog(l *Logger, level string) *log.Logger {
+	return log.New(NewStdWriter(l, level), "", 0)
+}
+
+// NewStdWriter 返回按行写入 l 的 io.Writer，可用于 log.SetOutput 接管标准库全局 logger
+func NewStdWriter(l *Logger, level string) io.Writer {
+	// 调用栈: 业务代码 -> log.Printf -> log.output -> Write，在默认跳过层数基础上再跳过 1 层
+	return &stdWriter{logger: l.logger.WithOptions(zap.AddCallerSkip(1)), level: parseLevel(level)}
+}
+
+// GRPCLogger 实现 grpclog.LoggerV2 的方法集（无需引入 grpc 依赖）
+// 使用示例：
+//
+//	grpclog.SetLoggerV2(logger.NewGRPCLogger(l, 0))
+type GRPCLogger struct {
+	sugar     *zap.SugaredLogger
+	verbosity int
+}
+
+// NewGRPCLogger 创建 gRPC 日志适配器，verbosity 对应 grpclog 的 V 级别
+func NewGRPCLogger(l *Logger, verbosity int) *GRPCLogger {
+	return &GRPCLogger{sugar: l.logger.WithOptions(zap.AddCallerSkip(-1)).Sugar(), verbosity: verbosity}
+}
+
+func (g *GRPCLogger) Info(args ...interface{})   { g.sugar.Info(args...) }
+func (g *GRPCLogger) Infoln(args ...interface{}) { g.sugar.Info(sprintln(args)) }
+func (g *GRPCLogger) Infof(format string, args ...interface{}) {
+	g.sugar.Infof(format, args...)
+}
+func (g *GRPCLogger) Warning(args ...interface{})   { g.sugar.Warn(args...) }
+func (g *GRPCLogger) Warningln(args ...interface{}) { g.sugar.Warn(sprintln(args)) }
+func (g *GRPCLogger) Warningf(format string, args ...interface{}) {
+	g.sugar.Warnf(format, args...)
+}
+func (g *GRPCLogger) Error(args ...interface{})   { g.sugar.Error(args...) }
+func (g *GRPCLogger) Errorln(args ...interface{}) { g.sugar.Error(sprintln(args)) }
+func (g *GRPCLogger) Errorf(format string, args ...interface{}) {
+	g.sugar.Errorf(format, args...)
+}
+func (g *GRPCLogger) Fatal(args ...interface{})   { g.sugar.Fatal(args...) }
+func (g *GRPCLogger) Fatalln(args ...interface{}) { g.sugar.Fatal(sprintln(args)) }
+func (g *GRPCLogger) Fatalf(format string, args ...interface{}) {
+	g.sugar.Fatalf(format, args...)
+}
+
+// V 报告给定的 verbosity 是否开启
+func (g *GRPCLogger) V(l int) bool { return l <= g.verbosity }
+
+// SaramaLogger 实现 sarama.StdLogger（Print/Printf/Println）
+// 使用示例：
+//
+//	sarama.Logger = logger.NewSaramaLogger(l, "info")
+type SaramaLogger struct {
+	logger *zap.Logger
+	level  zapcore.Level
+}
+
+// NewSaramaLogger 创建 sarama 日志适配器，所有输出使用同一级别
+func NewSaramaLogger(l *Logger, level string) *SaramaLogger {
+	return &SaramaLogger{logger: l.logger, level: parseLevel(level)}
+}
+
+func (s *SaramaLogger) Print(v ...interface{}) { s.write(fmt.Sprint(v...)) }
+func (s *SaramaLogger) Printf(format string, v ...interface{}) {
+	s.write(fmt.Sprintf(format, v...))
+}
+func (s *SaramaLogger) Println(v ...interface{}) { s.write(sprintln(v)) }
+
+// write 按固定级别输出
+func (s *SaramaLogger) write(msg string) {
+	if ce := s.logger.Check(s.level, strings.TrimRight(msg, "\n")); ce != nil {
+		ce.Write()
+	}
+}
+
+// sprintln 与 fmt.Sprintln 一致但去除末尾换行
+func sprintln(args []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
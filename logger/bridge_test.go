@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestBridges 测试第三方日志适配器写入同一个日志文件
+func TestBridges(t *testing.T) {
+	testDir := "./test_logs"
+	defer os.RemoveAll(testDir)
+
+	logFile := filepath.Join(testDir, "bridge_test.log")
+	l := New(&Config{Level: "debug", FileName: logFile})
+
+	NewStdLog(l, "warn").Printf("std log %d", 1)
+	g := NewGRPCLogger(l, 2)
+	g.Infoln("grpc", "info")
+	g.Warningf("grpc %s", "warning")
+	NewSaramaLogger(l, "info").Println("sarama", "message")
+
+	if !g.V(2) || g.V(3) {
+		t.Error("V should respect configured verbosity")
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{`"msg":"std log 1"`, `"level":"WARN"`, `"msg":"grpc info"`, `"msg":"grpc warning"`, `"msg":"sarama message"`} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected log file to contain %s", want)
+		}
+	}
+	if !strings.Contains(content, "bridge_test.go") {
+		t.Error("Caller should point to the call site")
+	}
+}
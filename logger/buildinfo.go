@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WithBuildInfo 返回一个子 logger，之后的每条日志都带上 version、commit、buildTime 字段，
+// 并输出一条标准化的启动日志（含 Go 版本、平台、主机名、进程号），便于跨服务排查问题时确认运行的版本；
+// 参数为空时尝试从 debug.ReadBuildInfo 中读取（模块版本、vcs.revision、vcs.time）
+// 使用示例（通过 -ldflags "-X main.version=v1.2.3" 注入）：
+//
+//	l = l.WithBuildInfo(version, commit, buildTime)
+func (l *Logger) WithBuildInfo(version, commit, buildTime string) *Logger {
+	version, commit, buildTime = fillBuildInfo(version, commit, buildTime)
+	child := l.With(
+		zap.String("version", version),
+		zap.String("commit", commit),
+		zap.String("buildTime", buildTime),
+	)
+
+	host, _ := os.Hostname()
+	child.logger.Info("service starting",
+		zap.String("goVersion", runtime.Version()),
+		zap.String("platform", runtime.GOOS+"/"+runtime.GOARCH),
+		zap.String("host", host),
+		zap.Int("pid", os.Getpid()),
+		zap.Time("startTime", time.Now()),
+	)
+	return child
+}
+
+// fillBuildInfo 用编译时嵌入的构建信息补全为空的参数
+func fillBuildInfo(version, commit, buildTime string) (string, string, string) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version, commit, buildTime
+	}
+	if version == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		version = info.Main.Version
+	}
+	for _, s := range info.Settings {
+		switch {
+		case s.Key == "vcs.revision" && commit == "":
+			commit = s.Value
+		case s.Key == "vcs.time" && buildTime == "":
+			buildTime = s.Value
+		}
+	}
+	return version, commit, buildTime
+}
+
+// WithBuildInfo 为全局logger绑定构建信息并输出启动日志，应在程序启动时调用一次
+func WithBuildInfo(version, commit, buildTime string) {
+	globalLogger = Default().WithBuildInfo(version, commit, buildTime)
+}
@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithBuildInfo(t *testing.T) {
+	l := newReportLogger(t, Config{})
+	child := l.WithBuildInfo("v1.2.3", "abc123", "2024-05-01T10:00:00Z")
+	child.Info(context.Background(), "handled")
+	l.Info(context.Background(), "parent")
+	_ = l.Sync()
+
+	data, err := os.ReadFile(l.GetConfig().FileName)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %s", len(lines), data)
+	}
+	fields := `"version":"v1.2.3","commit":"abc123","buildTime":"2024-05-01T10:00:00Z"`
+	if !strings.Contains(lines[0], `"msg":"service starting"`) || !strings.Contains(lines[0], fields) || !strings.Contains(lines[0], `"goVersion":"go`) {
+		t.Errorf("unexpected banner: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], fields) {
+		t.Errorf("build info not bound to entries: %s", lines[1])
+	}
+	if strings.Contains(lines[2], "version") {
+		t.Errorf("parent logger should not carry build info: %s", lines[2])
+	}
+
+	// 子 logger 与父 logger 共享级别
+	if err := child.SetLevel("error"); err != nil {
+		t.Fatal(err)
+	}
+	if l.GetConfig().Level != "error" || l.level.Enabled(zapcore.DebugLevel) {
+		t.Error("level should be shared with parent")
+	}
+}
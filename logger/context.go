@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// loggerCtxKey 存放 *Logger 的 context key，使用私有类型避免冲突
+type loggerCtxKey struct{}
+
+// ToContext 将 logger 放入 context，便于请求级 logger 沿调用链传递，无需逐层传参
+func ToContext(ctx context.Context, l *Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// FromContext 从 context 中取出 logger，未设置时返回默认 logger
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerCtxKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	return Default()
+}
+
+// ContextKey logger 使用的 context key 类型，避免与其它包的字符串 key 冲突
+type ContextKey string
+
+// TraceIDContextKey 存放 traceId 的 context key，推荐通过 WithTraceID 写入
+const TraceIDContextKey ContextKey = "traceId"
+
+// legacyTraceIDKey 早期版本约定的字符串 key，读取时仍兼容 context.WithValue(ctx, "traceId", id) 写入的值
+const legacyTraceIDKey = "traceId"
+
+// WithTraceID 将 traceId 写入 context，之后使用该 context 的日志都会带上 traceId 字段
+func WithTraceID(ctx context.Context, id string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, TraceIDContextKey, id)
+}
+
+// TraceIDFromContext 读取 context 中的 traceId（与日志输出的 traceId 字段一致），
+// 优先读取 TraceIDContextKey，其次兼容旧的字符串 key "traceId"，未设置时返回空串
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(TraceIDContextKey).(string); ok && id != "" {
+		return id
+	}
+	if v := ctx.Value(legacyTraceIDKey); v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// ctxFieldsKey 存放 AppendCtx 累积字段的 context key
+type ctxFieldsKey struct{}
+
+// AppendCtx 向 context 追加日志字段（类似 MDC），之后使用该 context 的日志调用都会自动带上这些字段，
+// 无需层层传递 With 出来的 logger；同名字段以后追加的为准，返回新的 context，不影响原 context
+//
+//	ctx = logger.AppendCtx(ctx, zap.String("userId", uid), zap.String("orderId", oid))
+//	logger.Info(ctx, "order paid") // 自动包含 userId、orderId
+func AppendCtx(ctx context.Context, fields ...zap.Field) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(fields) == 0 {
+		return ctx
+	}
+	prev := FieldsFromContext(ctx)
+	merged := make([]zap.Field, 0, len(prev)+len(fields))
+	for _, f := range prev {
+		if !hasKey(fields, f.Key) {
+			merged = append(merged, f)
+		}
+	}
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, ctxFieldsKey{}, merged)
+}
+
+// FieldsFromContext 返回 AppendCtx 累积的字段，调用方不应修改返回的切片
+func FieldsFromContext(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(ctxFieldsKey{}).([]zap.Field)
+	return fields
+}
+
+// hasKey 判断字段列表中是否存在指定 key
+func hasKey(fields []zap.Field, key string) bool {
+	for _, f := range fields {
+		if f.Key == key {
+			return true
+		}
+	}
+	return false
+}
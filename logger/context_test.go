@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// TestContextLogger 测试 logger 注入与取出
+func TestContextLogger(t *testing.T) {
+	testDir := "./test_logs"
+	defer os.RemoveAll(testDir)
+
+	l := New(&Config{Level: "debug", FileName: filepath.Join(testDir, "ctx_logger.log")})
+	ctx := ToContext(context.Background(), l)
+
+	if got := FromContext(ctx); got != l {
+		t.Error("FromContext should return the injected logger")
+	}
+	if got := FromContext(context.Background()); got != Default() {
+		t.Error("FromContext should fall back to Default()")
+	}
+	if got := FromContext(nil); got != Default() {
+		t.Error("FromContext(nil) should fall back to Default()")
+	}
+
+	FromContext(ctx).Info(ctx, "message from context logger")
+}
+
+// TestAppendCtx 测试 context 中累积的字段自动写入日志
+func TestAppendCtx(t *testing.T) {
+	testDir := "./test_logs_mdc"
+	defer os.RemoveAll(testDir)
+
+	file := filepath.Join(testDir, "mdc.log")
+	l := New(&Config{Level: "debug", FileName: file})
+
+	base := context.WithValue(context.Background(), "traceId", "t-mdc")
+	ctx := AppendCtx(base, zap.String("userId", "u1"), zap.String("step", "a"))
+	ctx = AppendCtx(ctx, zap.String("step", "b"))
+
+	if got := FieldsFromContext(base); len(got) != 0 {
+		t.Errorf("parent context should not be modified, got %v", got)
+	}
+	if got := FieldsFromContext(ctx); len(got) != 2 {
+		t.Errorf("expected 2 fields after override, got %v", got)
+	}
+
+	l.Info(ctx, "structured", zap.Int("n", 1))
+	l.Infof(ctx, "formatted %d", 2)
+	_ = l.Sync()
+
+	data, _ := os.ReadFile(file)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(lines), data)
+	}
+	for _, line := range lines {
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		if m["userId"] != "u1" || m["step"] != "b" || m["traceId"] != "t-mdc" {
+			t.Errorf("context fields missing: %s", line)
+		}
+	}
+}
+
+// TestWithTraceID 测试类型化 key 与旧字符串 key 的兼容
+func TestWithTraceID(t *testing.T) {
+	if got := TraceIDFromContext(nil); got != "" {
+		t.Errorf("nil context should have no trace id, got %q", got)
+	}
+	legacy := context.WithValue(context.Background(), "traceId", "t-legacy")
+	if got := TraceIDFromContext(legacy); got != "t-legacy" {
+		t.Errorf("legacy key not supported, got %q", got)
+	}
+	typed := WithTraceID(legacy, "t-typed")
+	if got := TraceIDFromContext(typed); got != "t-typed" {
+		t.Errorf("typed key should take precedence, got %q", got)
+	}
+
+	file := filepath.Join(t.TempDir(), "trace.log")
+	l := New(&Config{Level: "debug", FileName: file})
+	l.Info(WithTraceID(context.Background(), "t-1"), "typed")
+	_ = l.Sync()
+	data, _ := os.ReadFile(file)
+	if !strings.Contains(string(data), `"traceId":"t-1"`) {
+		t.Errorf("trace id missing from log: %s", data)
+	}
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WriteErrorEvent 日志文件写入状态变化事件：写入失败开始回退到 stderr，或恢复写入文件
+type WriteErrorEvent struct {
+	Path      string    // 日志文件路径
+	Err       error     // 导致回退的错误，恢复事件中为最后一次的错误
+	Recovered bool      // true 表示文件写入已恢复
+	Time      time.Time // 事件时间
+}
+
+// fallbackWriter 文件写入失败（磁盘写满、运行中权限丢失等）时把日志回退到 stderr，避免静默丢日志
+type fallbackWriter struct {
+	primary  zapcore.WriteSyncer
+	fallback io.Writer
+	path     string
+
+	mu      sync.Mutex
+	lastErr error
+	failing bool
+	hooks   []func(WriteErrorEvent)
+}
+
+// newFallbackWriter 创建带回退的写入器
+func newFallbackWriter(primary zapcore.WriteSyncer, path string) *fallbackWriter {
+	return &fallbackWriter{primary: primary, fallback: os.Stderr, path: path}
+}
+
+// Write 优先写入文件，失败时改写到 stderr，并在状态变化时发出事件
+func (w *fallbackWriter) Write(p []byte) (int, error) {
+	n, err := w.primary.Write(p)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err == nil {
+		if w.failing {
+			w.failing = false
+			w.emitLocked(WriteErrorEvent{Path: w.path, Err: w.lastErr, Recovered: true, Time: time.Now()})
+			w.lastErr = nil
+		}
+		return n, nil
+	}
+
+	w.lastErr = err
+	if !w.failing {
+		w.failing = true
+		// 内部告警直接写 stderr，不能再经过 logger 本身，避免递归写入
+		fmt.Fprintf(w.fallback, "logger: write to %s failed, falling back to stderr: %v\n", w.path, err)
+		w.emitLocked(WriteErrorEvent{Path: w.path, Err: err, Time: time.Now()})
+	}
+	if _, ferr := w.fallback.Write(p); ferr != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sync 满足 zapcore.WriteSyncer
+func (w *fallbackWriter) Sync() error {
+	return w.primary.Sync()
+}
+
+// OnWriteError 注册写入状态变化回调
+func (w *fallbackWriter) OnWriteError(fn func(WriteErrorEvent)) {
+	if fn == nil {
+		return
+	}
+	w.mu.Lock()
+	w.hooks = append(w.hooks, fn)
+	w.mu.Unlock()
+}
+
+// LastWriteError 返回当前的文件写入错误，写入正常时为 nil
+func (w *fallbackWriter) LastWriteError() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastErr
+}
+
+// emitLocked 异步通知回调（回调中可能继续打日志），调用方需持有锁
+func (w *fallbackWriter) emitLocked(ev WriteErrorEvent) {
+	if len(w.hooks) == 0 {
+		return
+	}
+	hooks := append([]func(WriteErrorEvent){}, w.hooks...)
+	go func() {
+		for _, fn := range hooks {
+			fn(ev)
+		}
+	}()
+}
+
+// OnWriteError 注册日志文件写入失败/恢复的回调，回调在独立 goroutine 中执行，可用于上报告警
+func (l *Logger) OnWriteError(fn func(WriteErrorEvent)) {
+	if l.writer != nil {
+		l.writer.OnWriteError(fn)
+	}
+}
+
+// LastWriteError 返回当前的日志文件写入错误，写入正常（或已恢复）时为 nil，可用于健康检查
+func (l *Logger) LastWriteError() error {
+	if l.writer == nil {
+		return nil
+	}
+	return l.writer.LastWriteError()
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// flakyWriter 可切换为失败状态的写入器
+type flakyWriter struct {
+	buf  bytes.Buffer
+	fail bool
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.fail {
+		return 0, errors.New("no space left on device")
+	}
+	return w.buf.Write(p)
+}
+
+func (w *flakyWriter) Sync() error { return nil }
+
+// TestFallbackWriter 测试文件写入失败时回退到 stderr 并在恢复后继续写文件
+func TestFallbackWriter(t *testing.T) {
+	primary := &flakyWriter{}
+	var stderr bytes.Buffer
+	w := newFallbackWriter(zapcore.WriteSyncer(primary), "app.log")
+	w.fallback = &stderr
+
+	events := make(chan WriteErrorEvent, 2)
+	w.OnWriteError(func(ev WriteErrorEvent) { events <- ev })
+
+	_, _ = w.Write([]byte("ok1\n"))
+	primary.fail = true
+	if n, err := w.Write([]byte("lost?\n")); err != nil || n != 6 {
+		t.Fatalf("fallback write should succeed, got n=%d err=%v", n, err)
+	}
+	if w.LastWriteError() == nil {
+		t.Error("expected LastWriteError while failing")
+	}
+	if !strings.Contains(stderr.String(), "falling back to stderr") || !strings.Contains(stderr.String(), "lost?") {
+		t.Errorf("unexpected stderr output: %q", stderr.String())
+	}
+	if ev := waitEvent(t, events); ev.Recovered || ev.Err == nil {
+		t.Errorf("unexpected failure event: %+v", ev)
+	}
+
+	primary.fail = false
+	_, _ = w.Write([]byte("ok2\n"))
+	if err := w.LastWriteError(); err != nil {
+		t.Errorf("expected nil after recovery, got %v", err)
+	}
+	if ev := waitEvent(t, events); !ev.Recovered {
+		t.Errorf("expected recovered event, got %+v", ev)
+	}
+	if primary.buf.String() != "ok1\nok2\n" {
+		t.Errorf("unexpected file output: %q", primary.buf.String())
+	}
+}
+
+func waitEvent(t *testing.T, ch <-chan WriteErrorEvent) WriteErrorEvent {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return WriteErrorEvent{}
+	}
+}
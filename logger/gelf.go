@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// gelfChunkSize UDP 分片大小，按常见 MTU 取值，超过时按 GELF 分片协议拆包
+	gelfChunkSize = 1420
+	// gelfMaxChunks GELF 允许的最大分片数
+	gelfMaxChunks = 128
+)
+
+// gelfFormat 编码为 GELF 1.1：业务字段加 "_" 前缀作为附加字段，堆栈放入 full_message
+func gelfFormat(host string) sinkFormat {
+	return func(ent zapcore.Entry, fields map[string]interface{}) ([]byte, error) {
+		msg := map[string]interface{}{
+			"version":       "1.1",
+			"host":          host,
+			"short_message": ent.Message,
+			"timestamp":     float64(ent.Time.UnixMilli()) / 1000,
+			"level":         syslogSeverity(ent.Level),
+		}
+		if ent.Stack != "" {
+			msg["full_message"] = ent.Message + "\n" + ent.Stack
+		}
+		if ent.Caller.Defined {
+			msg["_caller"] = ent.Caller.TrimmedPath()
+		}
+		if ent.LoggerName != "" {
+			msg["_logger"] = ent.LoggerName
+		}
+		for k, v := range fields {
+			msg[gelfFieldName(k)] = gelfValue(v)
+		}
+		return json.Marshal(msg)
+	}
+}
+
+// gelfFieldName 附加字段名只允许字母、数字、下划线、点与连字符，且 "_id" 为保留字段
+func gelfFieldName(key string) string {
+	name := "_" + strings.Map(func(r rune) rune {
+		if r == '_' || r == '.' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, key)
+	if name == "_id" {
+		return "_field_id"
+	}
+	return name
+}
+
+// gelfValue 附加字段只能是字符串或数字，其它类型转为字符串
+func gelfValue(v interface{}) interface{} {
+	switch v.(type) {
+	case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case bool, fmt.Stringer, error:
+		return fmt.Sprint(v)
+	}
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// gelfFrame GELF 分帧：TCP 以 '\0' 结尾，UDP 超过分片大小时拆分为多个分片
+func gelfFrame(network string) func(conn net.Conn, msg []byte) error {
+	if network == "tcp" {
+		return func(conn net.Conn, msg []byte) error {
+			_, err := conn.Write(append(msg, 0))
+			return err
+		}
+	}
+	return func(conn net.Conn, msg []byte) error {
+		if len(msg) <= gelfChunkSize {
+			_, err := conn.Write(msg)
+			return err
+		}
+		const payload = gelfChunkSize - 12
+		count := (len(msg) + payload - 1) / payload
+		if count > gelfMaxChunks {
+			return fmt.Errorf("logger: gelf message too large (%d bytes)", len(msg))
+		}
+		chunk := make([]byte, 12, gelfChunkSize)
+		chunk[0], chunk[1] = 0x1e, 0x0f
+		if _, err := rand.Read(chunk[2:10]); err != nil {
+			return err
+		}
+		chunk[11] = byte(count)
+		for i := 0; i < count; i++ {
+			end := (i + 1) * payload
+			if end > len(msg) {
+				end = len(msg)
+			}
+			chunk[10] = byte(i)
+			if _, err := conn.Write(append(chunk[:12], msg[i*payload:end]...)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// hookHub 通过 AddCore 注册的额外 core，父子 logger 共享；写入路径只做原子读取
+type hookHub struct {
+	mu    sync.Mutex // 串行化注册与移除
+	cores atomic.Pointer[[]*hookEntry]
+}
+
+// hookEntry 包一层指针，使同一个 core 重复注册时也能按注册项精确移除
+type hookEntry struct {
+	core zapcore.Core
+}
+
+// load 返回当前注册的 core 列表
+func (h *hookHub) load() []*hookEntry {
+	if p := h.cores.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// add 注册 core，返回移除函数
+func (h *hookHub) add(core zapcore.Core) func() {
+	e := &hookEntry{core: core}
+	h.mu.Lock()
+	next := append(append([]*hookEntry(nil), h.load()...), e)
+	h.cores.Store(&next)
+	h.mu.Unlock()
+
+	var once sync.Once
+	return func() { once.Do(func() { h.remove(e) }) }
+}
+
+// remove 移除注册项
+func (h *hookHub) remove(e *hookEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	cur := h.load()
+	next := make([]*hookEntry, 0, len(cur))
+	for _, c := range cur {
+		if c != e {
+			next = append(next, c)
+		}
+	}
+	h.cores.Store(&next)
+}
+
+// hookCore 将日志分发给 hookHub 中的 core，与文件/标准输出并列（Tee），位于 ruleCore 之内，
+// 因此全局、包级别与名称级别规则同样生效；每条日志再经各 core 自身的 Check 过滤，采样、级别等包装 core 照常生效
+type hookCore struct {
+	hub    *hookHub
+	fields []zapcore.Field
+}
+
+// Enabled 实现 zapcore.LevelEnabler
+func (c *hookCore) Enabled(lvl zapcore.Level) bool {
+	for _, e := range c.hub.load() {
+		if e.core.Enabled(lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+// With 实现 zapcore.Core；字段在 Write 时统一传给各 core，使运行时新注册的 core 也能拿到子 logger 的固定字段
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(append(merged, c.fields...), fields...)
+	return &hookCore{hub: c.hub, fields: merged}
+}
+
+// Check 实现 zapcore.Core
+func (c *hookCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core，某个 core 写入失败不影响其它 core
+func (c *hookCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	entries := c.hub.load()
+	if len(entries) == 0 {
+		return nil
+	}
+	if len(c.fields) > 0 {
+		fields = append(append(make([]zapcore.Field, 0, len(c.fields)+len(fields)), c.fields...), fields...)
+	}
+	var sink errSink
+	for _, e := range entries {
+		ce := e.core.Check(ent, nil)
+		if ce == nil {
+			continue
+		}
+		ce.ErrorOutput = &sink
+		ce.Write(fields...)
+	}
+	return errors.Join(sink.errs...)
+}
+
+// errSink 收集 CheckedEntry.Write 输出的写入错误，作为 hookCore.Write 的返回值
+type errSink struct {
+	errs []error
+}
+
+func (s *errSink) Write(p []byte) (int, error) {
+	s.errs = append(s.errs, errors.New(strings.TrimSpace(string(p))))
+	return len(p), nil
+}
+
+func (s *errSink) Sync() error { return nil }
+
+// Sync 实现 zapcore.Core
+func (c *hookCore) Sync() error {
+	var errs []error
+	for _, e := range c.hub.load() {
+		if err := e.core.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AddCore 注册额外的 zapcore.Core，与文件/标准输出并列接收日志（如写入 Kafka、内存测试 sink），
+// 对该 logger 及其 With/Named 子 logger（包括注册前创建的）均生效；
+// 日志先经过全局、包级别与名称级别过滤，再经 core 自身的 Check（级别、采样等）过滤；返回的函数用于移除，可重复调用
+// 使用示例：
+//
+//	core, logs := observer.New(zap.DebugLevel)
+//	remove := l.AddCore(core)
+//	defer remove()
+//	// ... logs.FilterMessage("order created").Len()
+func (l *Logger) AddCore(core zapcore.Core) (remove func()) {
+	if core == nil || l.hooks == nil {
+		return func() {}
+	}
+	return l.hooks.add(core)
+}
+
+// AddCore 为默认 logger 注册额外的 core
+func AddCore(core zapcore.Core) (remove func()) {
+	return Default().AddCore(core)
+}
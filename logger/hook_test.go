@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestAddCore 测试注册额外 core 后日志同时写入文件与该 core
+func TestAddCore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "hook.log")
+	l := New(&Config{Level: "info", FileName: file, NamedLevels: map[string]string{"db": "debug"}})
+	ctx := WithTraceID(context.Background(), "trace-1")
+
+	// 注册前创建的子 logger 同样生效
+	orderLog := l.With(zap.String("module", "order"))
+	core, logs := observer.New(zapcore.DebugLevel)
+	remove := l.AddCore(core)
+	warnCore, warnLogs := observer.New(zapcore.WarnLevel)
+	l.AddCore(warnCore)
+
+	orderLog.Info(ctx, "order created", zap.String("orderId", "42"))
+	l.Debug(ctx, "debug filtered by global level")
+	l.Named("db").Debug(ctx, "db debug")
+	l.Warn(ctx, "warn to both")
+
+	if got := logs.Len(); got != 3 {
+		t.Fatalf("expected 3 entries, got %d: %v", got, logs.All())
+	}
+	entry := logs.FilterMessage("order created").All()[0]
+	ctxMap := entry.ContextMap()
+	if ctxMap["module"] != "order" || ctxMap["orderId"] != "42" || ctxMap[traceIDKey] != "trace-1" {
+		t.Errorf("unexpected fields: %v", ctxMap)
+	}
+	if logs.FilterMessage("db debug").All()[0].LoggerName != "db" {
+		t.Error("expected logger name db")
+	}
+	if warnLogs.Len() != 1 || warnLogs.All()[0].Message != "warn to both" {
+		t.Errorf("warn core should only receive warn: %v", warnLogs.All())
+	}
+
+	remove()
+	remove()
+	l.Info(ctx, "after remove")
+	if logs.FilterMessage("after remove").Len() != 0 {
+		t.Error("removed core still receives logs")
+	}
+	_ = l.Sync()
+
+	data, _ := os.ReadFile(file)
+	for _, want := range []string{"order created", "warn to both", "after remove"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in file output:\n%s", want, data)
+		}
+	}
+	if fn := l.AddCore(nil); fn == nil {
+		t.Error("expected no-op remove func for nil core")
+	}
+}
+
+// TestAddCore_Check 注册的 core 经 Check 写入，采样等包装 core 不被绕过
+func TestAddCore_Check(t *testing.T) {
+	l := New(&Config{Level: "info", FileName: filepath.Join(t.TempDir(), "hook.log")})
+	core, logs := observer.New(zapcore.InfoLevel)
+	defer l.AddCore(zapcore.NewSamplerWithOptions(core, time.Minute, 1, 0))()
+
+	for i := 0; i < 5; i++ {
+		l.Info(context.Background(), "sampled")
+	}
+	if got := logs.FilterMessage("sampled").Len(); got != 1 {
+		t.Fatalf("expected sampler to keep 1 entry, got %d", got)
+	}
+}
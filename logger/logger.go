@@ -23,6 +23,26 @@ type Config struct {
 	MaxBackups int    `json:"maxbackups" yaml:"maxbackups"` // 最大备份文件数量
 	Compress   bool   `json:"compress" yaml:"compress"`     // 是否压缩备份文件
 	TimeZone   string `json:"timezone" yaml:"timezone"`     // 时区，默认"Asia/Shanghai"
+
+	DirQuota       int    `json:"dirquota" yaml:"dirquota"`             // 日志文件（当前文件与切割备份）总大小上限(MB)，0 表示不限制
+	DirQuotaAction string `json:"dirquotaaction" yaml:"dirquotaaction"` // 超出配额时的动作: warn/delete/pause，默认 warn
+
+	PackageLevels map[string]string `json:"packagelevels" yaml:"packagelevels"` // 包路径前缀 -> 日志级别，覆盖全局级别
+	NamedLevels   map[string]string `json:"namedlevels" yaml:"namedlevels"`     // Named 子 logger 名称 -> 日志级别，如 {"db": "debug"}，优先于包级别
+
+	StableFields bool `json:"stablefields" yaml:"stablefields"` // 固定顶层字段顺序（time, level, traceId, caller, msg），与标准字段重名的业务字段加 field_ 前缀
+
+	Encoding string `json:"encoding" yaml:"encoding"` // 输出格式: json（默认）/console，console 为便于本地开发阅读的制表符分隔格式
+	Console  bool   `json:"console" yaml:"console"`   // 等同于 Encoding: console，便于按环境用一个开关切换；console 格式下 StableFields 不生效
+
+	SentryDSN     string `json:"sentrydsn" yaml:"sentrydsn"`         // 配置后将错误日志上报到 Sentry
+	ReportWebhook string `json:"reportwebhook" yaml:"reportwebhook"` // 配置后将错误日志以 JSON POST 到该地址
+	ReportLevel   string `json:"reportlevel" yaml:"reportlevel"`     // 上报的最低级别，默认 error
+	ReportRate    int    `json:"reportrate" yaml:"reportrate"`       // 每分钟最多上报条数，默认 60
+
+	Sinks []SinkConfig `json:"sinks" yaml:"sinks"` // 额外输出目标（GELF、syslog），与文件输出并列
+
+	Outputs []string `json:"outputs" yaml:"outputs"` // 输出目标: stdout/stderr/file 或 RegisterOutput 注册的名称，默认 [stdout, file]；容器中通常只需 stdout
 }
 
 // Logger 日志器结构体
@@ -30,7 +50,15 @@ type Logger struct {
 	logger *zap.Logger
 	config *Config
 	level  zap.AtomicLevel
-	mu     sync.RWMutex
+	mu     *sync.RWMutex // 子 logger 与父 logger 共享配置，因此共享同一把锁
+
+	rotator *rotateWriter   // 文件切割写入器，未输出到文件时为 nil
+	writer  *fallbackWriter // 包装 rotator，写入失败时回退到 stderr
+	rules   *levelRules     // 按包路径覆盖的日志级别
+	names   *levelRules     // 按 logger 名称覆盖的日志级别
+	reports *reportHub      // 错误上报目标
+	hooks   *hookHub        // AddCore 注册的额外 core
+	sinks   []*sinkCore     // Config.Sinks 创建的网络输出
 }
 
 // 默认配置
@@ -94,6 +122,9 @@ func New(config *Config) *Logger {
 
 	logger := &Logger{
 		config: config,
+		mu:     &sync.RWMutex{},
+		rules:  newLevelRules("/"),
+		names:  newLevelRules("."),
 	}
 
 	if err := logger.init(); err != nil {
@@ -130,19 +161,23 @@ func (l *Logger) init() error {
 		enc.AppendString(t.In(loc).Format("2006-01-02 15:04:05.000"))
 	}
 
-	// 确保日志目录存在
-	dir := filepath.Dir(l.config.FileName)
-	if dir != "." && dir != "" {
-		_ = os.MkdirAll(dir, 0o755)
+	// 只在输出到文件时创建日志目录与切割器
+	if l.config.hasOutput(OutputFile) {
+		dir := filepath.Dir(l.config.FileName)
+		if dir != "." && dir != "" {
+			_ = os.MkdirAll(dir, 0o755)
+		}
+		// Lumberjack 日志分割器，外层包装以支持切割回调
+		l.rotator = newRotateWriter(&lumberjack.Logger{
+			Filename:   l.config.FileName,
+			MaxSize:    l.config.MaxSize,
+			MaxAge:     l.config.MaxAge,
+			MaxBackups: l.config.MaxBackups,
+			Compress:   l.config.Compress,
+		})
+		l.rotator.guard = newDirGuard(l.config.DirQuota, l.config.DirQuotaAction)
+		l.writer = newFallbackWriter(l.rotator, l.config.FileName)
 	}
-	// Lumberjack 日志分割器
-	writer := zapcore.AddSync(&lumberjack.Logger{
-		Filename:   l.config.FileName,
-		MaxSize:    l.config.MaxSize,
-		MaxAge:     l.config.MaxAge,
-		MaxBackups: l.config.MaxBackups,
-		Compress:   l.config.Compress,
-	})
 
 	// 初始化日志级别（使用可动态调整的 AtomicLevel）
 	l.level = zap.NewAtomicLevel()
@@ -150,27 +185,99 @@ func (l *Logger) init() error {
 		l.level.SetLevel(zap.InfoLevel) // 默认info级别
 	}
 
-	// 同步写入
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderCfg),
-		zapcore.NewMultiWriteSyncer(zapcore.AddSync(os.Stdout), writer),
-		l.level,
+	for prefix, level := range l.config.PackageLevels {
+		var lvl zapcore.Level
+		if err := lvl.UnmarshalText([]byte(level)); err == nil {
+			l.rules.set(prefix, lvl)
+		}
+	}
+	for name, level := range l.config.NamedLevels {
+		var lvl zapcore.Level
+		if err := lvl.UnmarshalText([]byte(level)); err == nil {
+			l.names.set(name, lvl)
+		}
+	}
+
+	stable := l.config.StableFields && !l.config.console()
+	var encoder zapcore.Encoder
+	switch {
+	case l.config.console():
+		encoder = zapcore.NewConsoleEncoder(encoderCfg)
+	case stable:
+		encoder = newStableEncoder(encoderCfg)
+	default:
+		encoder = zapcore.NewJSONEncoder(encoderCfg)
+	}
+	// 同步写入；级别由外层 ruleCore 统一判断（全局级别 + 包级别规则）
+	var inner zapcore.Core = zapcore.NewCore(
+		encoder,
+		l.buildOutputs(),
+		zapcore.DebugLevel,
 	)
+	if stable {
+		inner = &stableCore{Core: inner}
+	}
+	l.sinks = newSinks(l.config.Sinks)
+	if len(l.sinks) > 0 {
+		cores := []zapcore.Core{inner}
+		for _, c := range l.sinks {
+			cores = append(cores, c)
+		}
+		inner = zapcore.NewTee(cores...)
+	}
+	l.hooks = &hookHub{}
+	inner = zapcore.NewTee(inner, &hookCore{hub: l.hooks})
+	core := newRuleCore(inner, l.level, l.rules, l.names)
+
+	// 错误上报与写文件并列，级别独立判断，未配置上报目标时不产生开销
+	l.reports = newReportHub(l.config.ReportLevel, l.config.ReportRate)
+	if l.config.SentryDSN != "" {
+		if r, err := NewSentryReporter(SentryConfig{DSN: l.config.SentryDSN}); err == nil {
+			l.reports.add(r)
+		} else {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}
+	if l.config.ReportWebhook != "" {
+		l.reports.add(NewWebhookReporter(l.config.ReportWebhook))
+	}
+	core = zapcore.NewTee(core, &reportCore{hub: l.reports})
 
 	l.logger = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(2), zap.AddStacktrace(zap.ErrorLevel))
 	return nil
 }
 
-// addTraceID 添加traceId到字段中
+// console 是否使用 console 格式输出
+func (c *Config) console() bool {
+	return c.Console || strings.EqualFold(c.Encoding, "console")
+}
+
+// addTraceID 添加 AppendCtx 累积的字段与 traceId 到字段中
 func (l *Logger) addTraceID(ctx context.Context, fields []zap.Field) []zap.Field {
-	if ctx != nil {
-		if traceId := ctx.Value("traceId"); traceId != nil {
-			fields = append(fields, zap.String("traceId", fmt.Sprint(traceId)))
-		}
+	if ctxFields := FieldsFromContext(ctx); len(ctxFields) > 0 {
+		fields = append(append(make([]zap.Field, 0, len(ctxFields)+len(fields)+1), ctxFields...), fields...)
+	}
+	if traceId := TraceIDFromContext(ctx); traceId != "" {
+		fields = append(fields, zap.String(traceIDKey, traceId))
 	}
 	return fields
 }
 
+// With 返回绑定了固定字段（如 service、module、requestId）的子 logger，之后的每条日志都自动带上这些字段；
+// 子 logger 与父 logger 共享配置、级别、输出与上报目标，父 logger 不受影响
+// 使用示例：
+//
+//	orderLog := l.With(zap.String("module", "order"))
+//	orderLog.Info(ctx, "order created", zap.String("orderId", id))
+func (l *Logger) With(fields ...zap.Field) *Logger {
+	if len(fields) == 0 {
+		return l
+	}
+	child := *l
+	child.logger = l.logger.With(fields...)
+	return &child
+}
+
 // Info 记录info级别日志
 func (l *Logger) Info(ctx context.Context, msg string, fields ...zap.Field) {
 	fields = l.addTraceID(ctx, fields)
@@ -201,48 +308,58 @@ func (l *Logger) Fatal(ctx context.Context, msg string, fields ...zap.Field) {
 	l.logger.Fatal(msg, fields...)
 }
 
-// Infof 格式化记录info级别日志
-func (l *Logger) Infof(ctx context.Context, msg string, args ...interface{}) {
+// sugar 返回携带 context 字段与 traceId 的 SugaredLogger
+func (l *Logger) sugar(ctx context.Context) *zap.SugaredLogger {
 	sugar := l.logger.Sugar()
-	if ctx != nil {
-		if traceId := ctx.Value("traceId"); traceId != nil {
-			sugar = sugar.With("traceId", fmt.Sprint(traceId))
+	if fields := l.addTraceID(ctx, nil); len(fields) > 0 {
+		args := make([]interface{}, len(fields))
+		for i, f := range fields {
+			args[i] = f
 		}
+		sugar = sugar.With(args...)
 	}
-	sugar.Infof(msg, args...)
+	return sugar
+}
+
+// Infof 格式化记录info级别日志
+func (l *Logger) Infof(ctx context.Context, msg string, args ...interface{}) {
+	l.sugar(ctx).Infof(msg, args...)
 }
 
 // Errorf 格式化记录error级别日志
 func (l *Logger) Errorf(ctx context.Context, msg string, args ...interface{}) {
-	sugar := l.logger.Sugar()
-	if ctx != nil {
-		if traceId := ctx.Value("traceId"); traceId != nil {
-			sugar = sugar.With("traceId", fmt.Sprint(traceId))
-		}
-	}
-	sugar.Errorf(msg, args...)
+	l.sugar(ctx).Errorf(msg, args...)
 }
 
 // Debugf 格式化记录debug级别日志
 func (l *Logger) Debugf(ctx context.Context, msg string, args ...interface{}) {
-	sugar := l.logger.Sugar()
-	if ctx != nil {
-		if traceId := ctx.Value("traceId"); traceId != nil {
-			sugar = sugar.With("traceId", fmt.Sprint(traceId))
-		}
-	}
-	sugar.Debugf(msg, args...)
+	l.sugar(ctx).Debugf(msg, args...)
 }
 
 // Warnf 格式化记录warn级别日志
 func (l *Logger) Warnf(ctx context.Context, msg string, args ...interface{}) {
-	sugar := l.logger.Sugar()
-	if ctx != nil {
-		if traceId := ctx.Value("traceId"); traceId != nil {
-			sugar = sugar.With("traceId", fmt.Sprint(traceId))
-		}
-	}
-	sugar.Warnf(msg, args...)
+	l.sugar(ctx).Warnf(msg, args...)
+}
+
+// Infow 以松散的键值对记录info级别日志，如 Infow(ctx, "login", "userId", 1, "ip", ip)；
+// 键值对规则同 zap.SugaredLogger：参数也可以直接是 zap.Field，缺少值的键会被忽略并记录一条错误
+func (l *Logger) Infow(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.sugar(ctx).Infow(msg, keysAndValues...)
+}
+
+// Errorw 以键值对记录error级别日志
+func (l *Logger) Errorw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.sugar(ctx).Errorw(msg, keysAndValues...)
+}
+
+// Debugw 以键值对记录debug级别日志
+func (l *Logger) Debugw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.sugar(ctx).Debugw(msg, keysAndValues...)
+}
+
+// Warnw 以键值对记录warn级别日志
+func (l *Logger) Warnw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.sugar(ctx).Warnw(msg, keysAndValues...)
 }
 
 // Sync 同步日志缓冲区
@@ -272,6 +389,22 @@ func (l *Logger) SetLevel(level string) error {
 	return nil
 }
 
+// OnRotate 注册日志文件切割后的回调，回调在独立 goroutine 中执行，
+// 可用于上传备份文件到对象存储等场景，无需轮询日志目录
+func (l *Logger) OnRotate(fn func(RotateEvent)) {
+	if l.rotator != nil {
+		l.rotator.OnRotate(fn)
+	}
+}
+
+// Rotate 手动触发日志文件切割（例如收到 SIGHUP 时）
+func (l *Logger) Rotate() error {
+	if l.rotator == nil {
+		return nil
+	}
+	return l.rotator.Rotate()
+}
+
 // GetConfig 获取当前配置
 func (l *Logger) GetConfig() *Config {
 	l.mu.RLock()
@@ -279,9 +412,28 @@ func (l *Logger) GetConfig() *Config {
 
 	// 返回配置的副本
 	config := *l.config
+	if l.config.PackageLevels != nil {
+		config.PackageLevels = make(map[string]string, len(l.config.PackageLevels))
+		for k, v := range l.config.PackageLevels {
+			config.PackageLevels[k] = v
+		}
+	}
+	if l.config.NamedLevels != nil {
+		config.NamedLevels = make(map[string]string, len(l.config.NamedLevels))
+		for k, v := range l.config.NamedLevels {
+			config.NamedLevels[k] = v
+		}
+	}
+	config.Sinks = append([]SinkConfig(nil), l.config.Sinks...)
+	config.Outputs = append([]string(nil), l.config.Outputs...)
 	return &config
 }
 
+// With 返回默认 logger 绑定了固定字段的子 logger
+func With(fields ...zap.Field) *Logger {
+	return Default().With(fields...)
+}
+
 // 全局便捷方法，使用默认logger实例
 func Info(ctx context.Context, msg string, fields ...zap.Field) {
 	Default().Info(ctx, msg, fields...)
@@ -315,6 +467,22 @@ func Warnf(ctx context.Context, msg string, args ...interface{}) {
 	Default().Warnf(ctx, msg, args...)
 }
 
+func Infow(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	Default().Infow(ctx, msg, keysAndValues...)
+}
+
+func Errorw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	Default().Errorw(ctx, msg, keysAndValues...)
+}
+
+func Debugw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	Default().Debugw(ctx, msg, keysAndValues...)
+}
+
+func Warnw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	Default().Warnw(ctx, msg, keysAndValues...)
+}
+
 // SetGlobalConfig 设置全局logger配置
 func SetGlobalConfig(config *Config) {
 	globalLogger = New(config)
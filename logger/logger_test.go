@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -314,6 +315,25 @@ func TestLogMethods(t *testing.T) {
 		logger.Warnf(ctx, "warn: %d attempts remaining", 3)
 		logger.Errorf(ctx, "error: failed to process %s", "request")
 	})
+
+	// 测试键值对日志方法
+	t.Run("keyvalue_logging", func(t *testing.T) {
+		logger.Debugw(ctx, "debug kv", "count", 10)
+		logger.Infow(ctx, "info kv", "user", "john", "age", 18, zap.Bool("vip", true))
+		logger.Warnw(ctx, "warn kv", "remaining", 3)
+		logger.Errorw(ctx, "error kv", "err", fmt.Errorf("boom"))
+		_ = logger.Sync()
+
+		data, err := os.ReadFile(filepath.Join(testDir, "methods_test.log"))
+		if err != nil {
+			t.Fatalf("read log file: %v", err)
+		}
+		for _, want := range []string{`"msg":"info kv","traceId":"test-123","user":"john","age":18,"vip":true`, `"msg":"error kv","traceId":"test-123","err":"boom"`} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("log file missing %s", want)
+			}
+		}
+	})
 }
 
 // TestGlobalMethods 测试全局便捷方法
@@ -339,6 +359,14 @@ func TestGlobalMethods(t *testing.T) {
 		Warnf(ctx, "global warn: %s", "formatted")
 		Errorf(ctx, "global error: %s", "formatted")
 	})
+
+	// 测试全局键值对日志方法
+	t.Run("global_keyvalue", func(t *testing.T) {
+		Debugw(ctx, "global debug", "method", "Debugw")
+		Infow(ctx, "global info", "method", "Infow")
+		Warnw(ctx, "global warn", "method", "Warnw")
+		Errorw(ctx, "global error", "method", "Errorw")
+	})
 }
 
 // TestSetGlobalConfig 测试设置全局配置
@@ -389,6 +417,34 @@ func TestSync(t *testing.T) {
 	}
 }
 
+// TestConsoleEncoding 测试 console 输出格式
+func TestConsoleEncoding(t *testing.T) {
+	testDir := "./test_logs"
+	defer os.RemoveAll(testDir)
+
+	for _, cfg := range []*Config{
+		{Encoding: "console", FileName: filepath.Join(testDir, "console_encoding.log")},
+		{Console: true, StableFields: true, FileName: filepath.Join(testDir, "console_flag.log")},
+	} {
+		l := New(cfg)
+		ctx := context.WithValue(context.Background(), "traceId", "trace-1")
+		l.Info(ctx, "hello console", zap.String("user", "u1"))
+		_ = l.Sync()
+
+		data, err := os.ReadFile(cfg.FileName)
+		if err != nil {
+			t.Fatalf("read log file: %v", err)
+		}
+		line := string(data)
+		if strings.HasPrefix(line, "{") || !strings.Contains(line, "\tINFO\t") || !strings.Contains(line, "hello console") {
+			t.Errorf("%s: expected console output, got %q", cfg.FileName, line)
+		}
+		if !strings.Contains(line, `{"user": "u1", "traceId": "trace-1"}`) {
+			t.Errorf("%s: fields should be rendered as trailing JSON, got %q", cfg.FileName, line)
+		}
+	}
+}
+
 // TestConcurrentAccess 测试并发访问安全性
 func TestConcurrentAccess(t *testing.T) {
 	testDir := "./test_logs"
@@ -613,3 +669,32 @@ func TestLoggerMemoryLeak(t *testing.T) {
 		logger.Sync()
 	}
 }
+
+// TestWith 测试子 logger 携带固定字段且不影响父 logger
+func TestWith(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "with.log")
+	l := New(&Config{Level: "debug", FileName: file})
+	child := l.With(zap.String("module", "order"))
+	if l.With() != l {
+		t.Error("With without fields should return the same logger")
+	}
+
+	ctx := WithTraceID(context.Background(), "t-with")
+	child.With(zap.String("requestId", "r-1")).Info(ctx, "child")
+	l.Info(ctx, "parent")
+	_ = l.Sync()
+
+	data, _ := os.ReadFile(file)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(lines), data)
+	}
+	for _, want := range []string{`"module":"order"`, `"requestId":"r-1"`, `"traceId":"t-with"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("child line missing %s: %s", want, lines[0])
+		}
+	}
+	if strings.Contains(lines[1], "module") {
+		t.Errorf("parent should not carry child fields: %s", lines[1])
+	}
+}
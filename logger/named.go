@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// Named 返回名为 name 的子 logger，日志中 logger 字段为该名称；多次调用时以 "." 连接形成层级，
+// 如 l.Named("http").Named("client") 的名称为 "http.client"。
+// 每个名称可通过 Config.NamedLevels 或 SetNamedLevel 单独设置级别，父名称的级别对子名称同样生效，
+// 便于在生产环境只打开某个子系统的 debug 日志
+// 使用示例：
+//
+//	dbLog := logger.Named("db")
+//	dbLog.Debug(ctx, "query", zap.String("sql", sql)) // 仅当 db 的级别为 debug 时输出
+func (l *Logger) Named(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	child := *l
+	child.logger = l.logger.Named(name)
+	return &child
+}
+
+// Named 返回默认 logger 的子 logger
+func Named(name string) *Logger {
+	return Default().Named(name)
+}
+
+// SetNamedLevel 为 logger 名称设置独立的日志级别，运行时生效，对 name 及以 "name." 开头的子名称生效，最长匹配优先
+func (l *Logger) SetNamedLevel(name, level string) error {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.names.set(name, lvl)
+	if l.config.NamedLevels == nil {
+		l.config.NamedLevels = make(map[string]string)
+	}
+	l.config.NamedLevels[name] = level
+	return nil
+}
+
+// RemoveNamedLevel 删除 logger 名称的级别规则，恢复使用包级别或全局级别
+func (l *Logger) RemoveNamedLevel(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.names.remove(name)
+	delete(l.config.NamedLevels, name)
+}
+
+// NamedLevels 返回当前的名称级别规则
+func (l *Logger) NamedLevels() map[string]string {
+	return l.names.snapshot()
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNamedLevels 测试按 logger 名称覆盖日志级别
+func TestNamedLevels(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "named.log")
+	l := New(&Config{Level: "info", FileName: file, NamedLevels: map[string]string{"db": "debug", "http.client": "error"}})
+	ctx := context.Background()
+
+	db, http := l.Named("db"), l.Named("http")
+	db.Debug(ctx, "db debug enabled")
+	db.Named("pool").Debug(ctx, "child name inherits db level")
+	http.Debug(ctx, "http debug suppressed")
+	http.Info(ctx, "http info enabled")
+	http.Named("client").Warn(ctx, "client warn suppressed")
+	l.Debug(ctx, "root debug suppressed")
+
+	if err := l.SetNamedLevel("http", "debug"); err != nil {
+		t.Fatalf("SetNamedLevel failed: %v", err)
+	}
+	http.Debug(ctx, "http debug enabled at runtime")
+	l.RemoveNamedLevel("db")
+	db.Debug(ctx, "db debug suppressed after removal")
+	_ = l.Sync()
+
+	data, _ := os.ReadFile(file)
+	out := string(data)
+	for _, want := range []string{"db debug enabled", "child name inherits db level", "http info enabled", "http debug enabled at runtime", `"logger":"db.pool"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in log output:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"suppressed"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("unexpected %q in log output:\n%s", unwanted, out)
+		}
+	}
+
+	if got := l.NamedLevels(); len(got) != 2 || got["http"] != "debug" || got["http.client"] != "error" {
+		t.Errorf("unexpected rules: %v", got)
+	}
+	if got := l.GetConfig().NamedLevels; got["http"] != "debug" {
+		t.Errorf("config not updated: %v", got)
+	}
+	if err := l.SetNamedLevel("x", "verbose"); err == nil {
+		t.Error("expected error for invalid level")
+	}
+}
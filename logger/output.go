@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 内置的输出目标，用于 Config.Outputs
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+	OutputFile   = "file" // 按 FileName 写入文件并切割
+)
+
+// defaultOutputs 未配置 Outputs 时同时输出到标准输出与文件
+var defaultOutputs = []string{OutputStdout, OutputFile}
+
+var (
+	outputsMu     sync.RWMutex
+	customOutputs = map[string]io.Writer{}
+)
+
+// RegisterOutput 注册自定义输出目标，之后可在 Config.Outputs 中按 name 引用；需在创建 logger 之前注册，
+// 同名时覆盖，w 的写入会被加锁串行化
+// 使用示例：
+//
+//	logger.RegisterOutput("kafka", kafkaWriter)
+//	logger.New(&logger.Config{Outputs: []string{"stdout", "kafka"}})
+func RegisterOutput(name string, w io.Writer) {
+	outputsMu.Lock()
+	defer outputsMu.Unlock()
+	customOutputs[strings.ToLower(name)] = w
+}
+
+// hasOutput 是否配置了指定的输出目标
+func (c *Config) hasOutput(name string) bool {
+	outputs := c.Outputs
+	if len(outputs) == 0 {
+		outputs = defaultOutputs
+	}
+	for _, o := range outputs {
+		if strings.EqualFold(strings.TrimSpace(o), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildOutputs 按配置组合输出目标，未知的名称输出提示到 stderr 后忽略，全部无效时回退到标准输出
+func (l *Logger) buildOutputs() zapcore.WriteSyncer {
+	outputs := l.config.Outputs
+	if len(outputs) == 0 {
+		outputs = defaultOutputs
+	}
+	var syncers []zapcore.WriteSyncer
+	seen := make(map[string]bool, len(outputs))
+	for _, o := range outputs {
+		name := strings.ToLower(strings.TrimSpace(o))
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		switch name {
+		case OutputStdout:
+			syncers = append(syncers, zapcore.AddSync(os.Stdout))
+		case OutputStderr:
+			syncers = append(syncers, zapcore.AddSync(os.Stderr))
+		case OutputFile:
+			if l.writer != nil {
+				syncers = append(syncers, l.writer)
+			}
+		default:
+			outputsMu.RLock()
+			w, ok := customOutputs[name]
+			outputsMu.RUnlock()
+			if !ok {
+				fmt.Fprintf(os.Stderr, "logger: unknown output %q\n", o)
+				continue
+			}
+			syncers = append(syncers, zapcore.Lock(zapcore.AddSync(w)))
+		}
+	}
+	if len(syncers) == 0 {
+		return zapcore.AddSync(os.Stdout)
+	}
+	return zapcore.NewMultiWriteSyncer(syncers...)
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestOutputs 测试输出目标配置
+func TestOutputs(t *testing.T) {
+	testDir := "./test_logs"
+	defer os.RemoveAll(testDir)
+
+	var buf bytes.Buffer
+	RegisterOutput("Memory", &buf)
+	logFile := filepath.Join(testDir, "outputs", "app.log")
+	l := New(&Config{FileName: logFile, Outputs: []string{"memory", "unknown"}})
+	l.Info(context.Background(), "to memory only")
+	_ = l.Sync()
+
+	if !strings.Contains(buf.String(), `"msg":"to memory only"`) {
+		t.Errorf("custom output should receive the entry, got %q", buf.String())
+	}
+	if _, err := os.Stat(logFile); !os.IsNotExist(err) {
+		t.Errorf("log file should not be created without the file output: %v", err)
+	}
+	if err := l.Rotate(); err != nil {
+		t.Errorf("Rotate without file output = %v", err)
+	}
+	if got := l.GetConfig().Outputs; len(got) != 2 || got[0] != "memory" {
+		t.Errorf("GetConfig().Outputs = %v", got)
+	}
+
+	fileOnly := New(&Config{FileName: filepath.Join(testDir, "file_only.log"), Outputs: []string{OutputFile}})
+	fileOnly.Info(context.Background(), "to file")
+	_ = fileOnly.Sync()
+	data, err := os.ReadFile(filepath.Join(testDir, "file_only.log"))
+	if err != nil || !strings.Contains(string(data), `"msg":"to file"`) {
+		t.Errorf("file output = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levelRule 前缀 -> 日志级别
+type levelRule struct {
+	prefix string
+	level  zapcore.Level
+}
+
+// levelRules 按层级前缀覆盖日志级别的规则集（调用方包路径或 logger 名称），运行时可修改
+type levelRules struct {
+	mu    sync.RWMutex
+	sep   string      // 层级分隔符，包路径为 "/"，logger 名称为 "."
+	rules []levelRule // 按前缀长度降序，最长匹配优先
+	min   zapcore.Level
+}
+
+// newLevelRules 创建以 sep 分隔层级的空规则集
+func newLevelRules(sep string) *levelRules {
+	return &levelRules{sep: sep, min: zapcore.InvalidLevel}
+}
+
+// set 新增或更新规则
+func (r *levelRules) set(prefix string, lvl zapcore.Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prefix = strings.TrimSuffix(prefix, r.sep)
+	for i := range r.rules {
+		if r.rules[i].prefix == prefix {
+			r.rules[i].level = lvl
+			r.refresh()
+			return
+		}
+	}
+	r.rules = append(r.rules, levelRule{prefix: prefix, level: lvl})
+	sort.SliceStable(r.rules, func(i, j int) bool { return len(r.rules[i].prefix) > len(r.rules[j].prefix) })
+	r.refresh()
+}
+
+// remove 删除规则
+func (r *levelRules) remove(prefix string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prefix = strings.TrimSuffix(prefix, r.sep)
+	for i := range r.rules {
+		if r.rules[i].prefix == prefix {
+			r.rules = append(r.rules[:i], r.rules[i+1:]...)
+			break
+		}
+	}
+	r.refresh()
+}
+
+// refresh 重新计算规则中的最低级别（调用方需持有写锁）
+func (r *levelRules) refresh() {
+	r.min = zapcore.InvalidLevel
+	for _, rule := range r.rules {
+		if r.min == zapcore.InvalidLevel || rule.level < r.min {
+			r.min = rule.level
+		}
+	}
+}
+
+// snapshot 返回规则副本
+func (r *levelRules) snapshot() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]string, len(r.rules))
+	for _, rule := range r.rules {
+		out[rule.prefix] = rule.level.String()
+	}
+	return out
+}
+
+// minLevel 规则中的最低级别，没有规则时返回 InvalidLevel
+func (r *levelRules) minLevel() zapcore.Level {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.min
+}
+
+// match 按层级前缀查找 key（包路径或 logger 名称）的级别，未命中时返回 false
+func (r *levelRules) match(key string) (zapcore.Level, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.rules) == 0 || key == "" {
+		return 0, false
+	}
+	for _, rule := range r.rules {
+		if key == rule.prefix || strings.HasPrefix(key, rule.prefix+r.sep) {
+			return rule.level, true
+		}
+	}
+	return 0, false
+}
+
+// packageOf 从完整函数名中提取包路径，如 "github.com/x/repo/db.(*T).Query" -> "github.com/x/repo/db"
+func packageOf(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	if dot := strings.IndexByte(function[slash+1:], '.'); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
+
+// ruleCore 按 logger 名称与调用方包路径过滤日志的 core
+// zap 在 Check 之后才填充调用方信息，因此 Check 只做粗过滤（全局级别与规则最低级别取小），
+// 在 Write 中根据 ent.LoggerName、ent.Caller 精确判断
+type ruleCore struct {
+	zapcore.Core
+	global zap.AtomicLevel
+	rules  *levelRules // 包路径规则
+	names  *levelRules // logger 名称规则
+}
+
+// newRuleCore 包装 inner，inner 自身不应再做级别过滤
+func newRuleCore(inner zapcore.Core, global zap.AtomicLevel, rules, names *levelRules) zapcore.Core {
+	return &ruleCore{Core: inner, global: global, rules: rules, names: names}
+}
+
+// Enabled 实现 zapcore.LevelEnabler
+func (c *ruleCore) Enabled(lvl zapcore.Level) bool {
+	if c.global.Enabled(lvl) {
+		return true
+	}
+	for _, r := range []*levelRules{c.rules, c.names} {
+		if min := r.minLevel(); min != zapcore.InvalidLevel && lvl >= min {
+			return true
+		}
+	}
+	return false
+}
+
+// With 实现 zapcore.Core
+func (c *ruleCore) With(fields []zapcore.Field) zapcore.Core {
+	return &ruleCore{Core: c.Core.With(fields), global: c.global, rules: c.rules, names: c.names}
+}
+
+// Check 实现 zapcore.Core
+func (c *ruleCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core，级别优先取 logger 名称规则，其次调用方所在包的规则，最后为全局级别
+func (c *ruleCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	lvl, ok := c.names.match(ent.LoggerName)
+	if !ok && ent.Caller.Function != "" {
+		lvl, ok = c.rules.match(packageOf(ent.Caller.Function))
+	}
+	if !ok {
+		lvl = c.global.Level()
+	}
+	if ent.Level < lvl {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// SetPackageLevel 为包路径前缀设置独立的日志级别，运行时生效，最长前缀优先
+// 例如 SetPackageLevel("github.com/x/repo/internal/db", "debug") 只打开 db 包（含子包）的 debug 日志
+func (l *Logger) SetPackageLevel(prefix, level string) error {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rules.set(prefix, lvl)
+	if l.config.PackageLevels == nil {
+		l.config.PackageLevels = make(map[string]string)
+	}
+	l.config.PackageLevels[prefix] = level
+	return nil
+}
+
+// RemovePackageLevel 删除包路径前缀的级别规则，恢复使用全局级别
+func (l *Logger) RemovePackageLevel(prefix string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rules.remove(prefix)
+	delete(l.config.PackageLevels, prefix)
+}
+
+// PackageLevels 返回当前的包级别规则
+func (l *Logger) PackageLevels() map[string]string {
+	return l.rules.snapshot()
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPackageLevel 测试按调用方包路径覆盖日志级别
+func TestPackageLevel(t *testing.T) {
+	testDir := "./test_logs_pkglevel"
+	defer os.RemoveAll(testDir)
+
+	file := filepath.Join(testDir, "pkg.log")
+	l := New(&Config{Level: "warn", FileName: file})
+	ctx := context.Background()
+
+	// 与全局便捷方法一样多包一层调用，使 caller 落在本包的测试函数上
+	debug := func(msg string) { l.Debug(ctx, msg) }
+	debugf := func(msg string, args ...interface{}) { l.Debugf(ctx, msg, args...) }
+	info := func(msg string) { l.Info(ctx, msg) }
+
+	debug("suppressed by global level")
+	if err := l.SetPackageLevel("github.com/qingfeng-studio/go-utils/logger", "debug"); err != nil {
+		t.Fatalf("SetPackageLevel failed: %v", err)
+	}
+	debug("enabled by package rule")
+	debugf("enabled by package rule %s", "sugar")
+
+	// 更长的前缀优先：其它包的规则不影响本包
+	_ = l.SetPackageLevel("github.com/qingfeng-studio/go-utils/logger/internal", "error")
+	info("still enabled")
+
+	l.RemovePackageLevel("github.com/qingfeng-studio/go-utils/logger")
+	info("suppressed after rule removed")
+	_ = l.Sync()
+
+	data, _ := os.ReadFile(file)
+	out := string(data)
+	for _, want := range []string{"enabled by package rule", "enabled by package rule sugar", "still enabled"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in log output", want)
+		}
+	}
+	for _, unwanted := range []string{"suppressed by global level", "suppressed after rule removed"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("unexpected %q in log output", unwanted)
+		}
+	}
+
+	if got := l.PackageLevels(); len(got) != 1 || got["github.com/qingfeng-studio/go-utils/logger/internal"] != "error" {
+		t.Errorf("unexpected rules: %v", got)
+	}
+	if err := l.SetPackageLevel("x", "verbose"); err == nil {
+		t.Error("expected error for invalid level")
+	}
+}
+
+// TestPackageOf 测试从函数名提取包路径
+func TestPackageOf(t *testing.T) {
+	cases := map[string]string{
+		"github.com/x/repo/internal/db.(*Repo).Query": "github.com/x/repo/internal/db",
+		"github.com/x/repo/svc.Handle.func1":          "github.com/x/repo/svc",
+		"main.main":                                   "main",
+	}
+	for in, want := range cases {
+		if got := packageOf(in); got != want {
+			t.Errorf("packageOf(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 日志目录超出配额时的处理动作
+const (
+	QuotaActionWarn   = "warn"   // 仅输出告警到 stderr
+	QuotaActionDelete = "delete" // 从最旧的备份文件开始删除，直到回到配额内
+	QuotaActionPause  = "pause"  // 暂停文件输出（标准输出不受影响），回到配额内后自动恢复
+)
+
+// quotaCheckInterval 写入路径上检查目录大小的最小间隔，避免频繁遍历目录
+const quotaCheckInterval = 10 * time.Second
+
+// dirGuard 日志目录磁盘用量守卫
+type dirGuard struct {
+	limit     int64 // 字节
+	action    string
+	lastCheck time.Time
+	paused    bool
+}
+
+// newDirGuard 根据配置创建守卫，quotaMB <= 0 时返回 nil
+func newDirGuard(quotaMB int, action string) *dirGuard {
+	if quotaMB <= 0 {
+		return nil
+	}
+	if action == "" {
+		action = QuotaActionWarn
+	}
+	return &dirGuard{limit: int64(quotaMB) * 1024 * 1024, action: action}
+}
+
+// due 判断是否到了下一次检查时间
+func (g *dirGuard) due(now time.Time) bool {
+	return now.Sub(g.lastCheck) >= quotaCheckInterval
+}
+
+// enforceQuotaLocked 检查目录用量并执行配置的动作，调用方需持有 w.mu
+func (w *rotateWriter) enforceQuotaLocked() {
+	g := w.guard
+	g.lastCheck = time.Now()
+
+	dir := filepath.Dir(w.lj.Filename)
+	usage := w.logSize()
+	if usage <= g.limit {
+		g.paused = false
+		return
+	}
+
+	switch g.action {
+	case QuotaActionDelete:
+		for _, f := range w.backups() {
+			if usage <= g.limit {
+				break
+			}
+			info, err := os.Stat(f)
+			if err != nil {
+				continue
+			}
+			if err := os.Remove(f); err == nil {
+				usage -= info.Size()
+			}
+		}
+		if usage > g.limit {
+			quotaWarn(dir, usage, g.limit)
+		}
+	case QuotaActionPause:
+		if !g.paused {
+			quotaWarn(dir, usage, g.limit)
+			fmt.Fprintf(os.Stderr, "logger: file output paused until %s is back under quota\n", dir)
+		}
+		g.paused = true
+	default:
+		quotaWarn(dir, usage, g.limit)
+	}
+}
+
+// quotaWarn 输出超限告警；此处不能再经过 logger 本身，避免递归写入
+func quotaWarn(dir string, usage, limit int64) {
+	fmt.Fprintf(os.Stderr, "logger: log files in %s use %d bytes, exceeds quota %d bytes\n", dir, usage, limit)
+}
+
+// logSize 统计当前日志文件与其切割备份的总大小；目录中的其它文件与子目录不计入，
+// 否则删除备份永远无法回到配额内
+func (w *rotateWriter) logSize() int64 {
+	var total int64
+	for _, f := range append(w.backups(), w.lj.Filename) {
+		if info, err := os.Stat(f); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newQuotaWriter 创建一个带配额守卫的写入器，配额以字节为单位便于测试
+func newQuotaWriter(t *testing.T, dir, action string, limit int64) *rotateWriter {
+	t.Helper()
+	logger := New(&Config{Level: "info", FileName: filepath.Join(dir, "quota.log"), DirQuota: 1, DirQuotaAction: action})
+	if logger.rotator.guard == nil {
+		t.Fatal("Expected quota guard to be configured")
+	}
+	logger.rotator.guard.limit = limit
+	return logger.rotator
+}
+
+// backupName 返回 age 之前切割出的备份文件名；时间取最近时刻，
+// 避免 lumberjack 按 MaxAge 异步清理过期备份干扰断言
+func backupName(dir, ext string, age time.Duration) string {
+	return filepath.Join(dir, "quota-"+time.Now().Add(-age).UTC().Format("2006-01-02T15-04-05.000")+ext)
+}
+
+// TestDirQuotaDelete 测试超出配额时删除最旧的备份
+func TestDirQuotaDelete(t *testing.T) {
+	testDir := "./test_logs_quota_delete"
+	defer os.RemoveAll(testDir)
+	_ = os.MkdirAll(testDir, 0o755)
+
+	oldest := backupName(testDir, ".log.gz", 2*time.Hour)
+	newer := backupName(testDir, ".log.gz", time.Hour)
+	for _, f := range []string{oldest, newer} {
+		if err := os.WriteFile(f, []byte(strings.Repeat("x", 600)), 0o644); err != nil {
+			t.Fatalf("write backup: %v", err)
+		}
+	}
+
+	w := newQuotaWriter(t, testDir, QuotaActionDelete, 1000)
+	if _, err := w.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	if _, err := os.Stat(oldest); !os.IsNotExist(err) {
+		t.Error("Oldest backup should be deleted")
+	}
+	if _, err := os.Stat(newer); err != nil {
+		t.Error("Newer backup should be kept")
+	}
+}
+
+// TestDirQuotaPause 测试超出配额时暂停文件输出
+func TestDirQuotaPause(t *testing.T) {
+	testDir := "./test_logs_quota_pause"
+	defer os.RemoveAll(testDir)
+	_ = os.MkdirAll(testDir, 0o755)
+
+	if err := os.WriteFile(backupName(testDir, ".log", time.Hour), []byte(strings.Repeat("x", 2000)), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	w := newQuotaWriter(t, testDir, QuotaActionPause, 1000)
+	n, err := w.Write([]byte("dropped\n"))
+	if err != nil || n != len("dropped\n") {
+		t.Fatalf("Write should report success while paused, n=%d err=%v", n, err)
+	}
+	if !w.guard.paused {
+		t.Fatal("Expected file output to be paused")
+	}
+	if data, _ := os.ReadFile(filepath.Join(testDir, "quota.log")); strings.Contains(string(data), "dropped") {
+		t.Error("Entries should not be written while paused")
+	}
+}
+
+// TestDirQuotaIgnoresOtherFiles 目录中与日志无关的文件不计入配额，也不会被删除
+func TestDirQuotaIgnoresOtherFiles(t *testing.T) {
+	testDir := t.TempDir()
+	other := filepath.Join(testDir, "other.bin")
+	if err := os.WriteFile(other, []byte(strings.Repeat("x", 2000)), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(testDir, "archive"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(testDir, "archive", "quota-2023-01-01T00-00-00.000.log"), []byte(strings.Repeat("x", 2000)), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	backup := backupName(testDir, ".log.gz", time.Hour)
+	if err := os.WriteFile(backup, []byte(strings.Repeat("x", 600)), 0o644); err != nil {
+		t.Fatalf("write backup: %v", err)
+	}
+
+	w := newQuotaWriter(t, testDir, QuotaActionDelete, 1000)
+	if _, err := w.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := os.Stat(backup); err != nil {
+		t.Error("Backup should be kept while log files are under quota")
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Error("Unrelated files must never be deleted")
+	}
+}
@@ -0,0 +1,276 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// defaultReportRate 默认每分钟最多上报的条数
+	defaultReportRate = 60
+	// reportQueueSize 异步上报队列长度，队列满时丢弃
+	reportQueueSize = 256
+	// reportTimeout 单次上报超时
+	reportTimeout = 5 * time.Second
+)
+
+// ReportEvent 上报到错误追踪服务的日志条目
+type ReportEvent struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"msg"`
+	Caller  string                 `json:"caller,omitempty"`
+	Stack   string                 `json:"stacktrace,omitempty"`
+	TraceID string                 `json:"traceId,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// Reporter 错误上报目标（Sentry、告警 webhook 等）
+type Reporter interface {
+	Report(ctx context.Context, ev ReportEvent) error
+}
+
+// reportHub 上报的共享状态：目标列表、级别、限流与异步队列
+type reportHub struct {
+	level zapcore.Level
+	rate  int
+
+	mu        sync.RWMutex
+	reporters []Reporter
+	queue     chan ReportEvent
+	pending   atomic.Int64
+
+	windowMu    sync.Mutex
+	windowStart time.Time
+	windowCount int
+	dropped     atomic.Int64
+}
+
+// newReportHub 创建上报中心，level 为空时默认 error，rate <= 0 时默认每分钟 60 条
+func newReportHub(level string, rate int) *reportHub {
+	lvl := zapcore.ErrorLevel
+	if level != "" {
+		lvl = parseLevel(level)
+	}
+	if rate <= 0 {
+		rate = defaultReportRate
+	}
+	return &reportHub{level: lvl, rate: rate}
+}
+
+// add 添加上报目标，首次添加时启动后台发送协程
+func (h *reportHub) add(r Reporter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reporters = append(h.reporters, r)
+	if h.queue == nil {
+		h.queue = make(chan ReportEvent, reportQueueSize)
+		go h.loop(h.queue)
+	}
+}
+
+// active 是否配置了上报目标
+func (h *reportHub) active() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.reporters) > 0
+}
+
+// allow 固定窗口限流，避免错误风暴时打爆上报服务
+func (h *reportHub) allow(now time.Time) bool {
+	h.windowMu.Lock()
+	defer h.windowMu.Unlock()
+	if now.Sub(h.windowStart) >= time.Minute {
+		h.windowStart, h.windowCount = now, 0
+	}
+	if h.windowCount >= h.rate {
+		return false
+	}
+	h.windowCount++
+	return true
+}
+
+// enqueue 异步上报，队列满或超出限流时丢弃
+func (h *reportHub) enqueue(ev ReportEvent) {
+	if !h.allow(ev.Time) {
+		h.dropped.Add(1)
+		return
+	}
+	h.mu.RLock()
+	queue := h.queue
+	h.mu.RUnlock()
+	h.pending.Add(1)
+	select {
+	case queue <- ev:
+	default:
+		h.pending.Add(-1)
+		h.dropped.Add(1)
+	}
+}
+
+// loop 后台发送协程
+func (h *reportHub) loop(queue chan ReportEvent) {
+	for ev := range queue {
+		h.send(ev)
+		h.pending.Add(-1)
+	}
+}
+
+// send 同步发送到所有目标，失败时写 stderr（不能经过 logger 本身，避免递归）
+func (h *reportHub) send(ev ReportEvent) {
+	h.mu.RLock()
+	reporters := append([]Reporter(nil), h.reporters...)
+	h.mu.RUnlock()
+	for _, r := range reporters {
+		ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+		if err := r.Report(ctx, ev); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: report %q failed: %v\n", ev.Message, err)
+		}
+		cancel()
+	}
+}
+
+// flush 等待队列中的事件发送完成
+func (h *reportHub) flush(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for h.pending.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// reportCore 将达到上报级别的日志转为 ReportEvent 交给 reportHub，与写文件的 core 并列（Tee）
+type reportCore struct {
+	hub    *reportHub
+	fields []zapcore.Field
+}
+
+// Enabled 实现 zapcore.LevelEnabler
+func (c *reportCore) Enabled(lvl zapcore.Level) bool {
+	return lvl >= c.hub.level && c.hub.active()
+}
+
+// With 实现 zapcore.Core
+func (c *reportCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(append(merged, c.fields...), fields...)
+	return &reportCore{hub: c.hub, fields: merged}
+}
+
+// Check 实现 zapcore.Core
+func (c *reportCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core；Fatal/Panic 级别进程即将退出，同步发送
+func (c *reportCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	ev := ReportEvent{
+		Time:    ent.Time,
+		Level:   ent.Level.String(),
+		Message: ent.Message,
+		Stack:   ent.Stack,
+		Fields:  enc.Fields,
+	}
+	if ent.Caller.Defined {
+		ev.Caller = ent.Caller.TrimmedPath()
+	}
+	if v, ok := ev.Fields[traceIDKey]; ok {
+		ev.TraceID = fmt.Sprint(v)
+		delete(ev.Fields, traceIDKey)
+	}
+	if ent.Level > zapcore.ErrorLevel {
+		c.hub.flush(reportTimeout)
+		c.hub.send(ev)
+		return nil
+	}
+	c.hub.enqueue(ev)
+	return nil
+}
+
+// Sync 实现 zapcore.Core，等待已排队的事件发送完成
+func (c *reportCore) Sync() error {
+	c.hub.flush(reportTimeout)
+	return nil
+}
+
+// AddReporter 添加错误上报目标，达到 Config.ReportLevel（默认 error）的日志会连同字段、堆栈与 traceId
+// 异步发送，发送失败只写 stderr，不影响业务；应在初始化阶段调用
+// 使用示例：
+//
+//	l.AddReporter(logger.NewWebhookReporter("https://alert.example.com/hooks/logs"))
+func (l *Logger) AddReporter(r Reporter) {
+	if r != nil && l.reports != nil {
+		l.reports.add(r)
+	}
+}
+
+// ReportsDropped 返回因限流或队列已满被丢弃的上报条数
+func (l *Logger) ReportsDropped() int64 {
+	if l.reports == nil {
+		return 0
+	}
+	return l.reports.dropped.Load()
+}
+
+// WebhookReporter 以 JSON（ReportEvent）POST 到任意 webhook 的上报目标
+type WebhookReporter struct {
+	URL     string
+	Headers map[string]string
+	Client  *http.Client // 默认 http.DefaultClient
+}
+
+// NewWebhookReporter 创建 webhook 上报目标
+func NewWebhookReporter(url string) *WebhookReporter {
+	return &WebhookReporter{URL: url}
+}
+
+// Report 实现 Reporter
+func (w *WebhookReporter) Report(ctx context.Context, ev ReportEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+	return doReport(w.Client, req)
+}
+
+// doReport 发送请求，非 2xx 视为失败
+func doReport(client *http.Client, req *http.Request) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// captureReporter 记录上报事件
+type captureReporter struct {
+	mu     sync.Mutex
+	events []ReportEvent
+}
+
+func (c *captureReporter) Report(_ context.Context, ev ReportEvent) error {
+	c.mu.Lock()
+	c.events = append(c.events, ev)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *captureReporter) snapshot() []ReportEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ReportEvent(nil), c.events...)
+}
+
+func newReportLogger(t *testing.T, cfg Config) *Logger {
+	t.Helper()
+	dir := t.TempDir()
+	cfg.FileName = filepath.Join(dir, "app.log")
+	return New(&cfg)
+}
+
+func TestReporter_ErrorEntries(t *testing.T) {
+	l := newReportLogger(t, Config{Level: "debug"})
+	rep := &captureReporter{}
+	l.AddReporter(rep)
+
+	ctx := AppendCtx(context.WithValue(context.Background(), "traceId", "t-1"), zap.String("userId", "u1"))
+	l.Warn(ctx, "just a warning")
+	l.logger.With(zap.String("component", "db")).Error("query failed", zap.Error(errors.New("timeout")))
+	l.Error(ctx, "pay failed", zap.Int("amount", 100))
+	_ = l.Sync()
+
+	events := rep.snapshot()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d: %+v", len(events), events)
+	}
+	if events[0].Message != "query failed" || events[0].Fields["component"] != "db" || events[0].Fields["error"] != "timeout" {
+		t.Errorf("unexpected event: %+v", events[0])
+	}
+	ev := events[1]
+	if ev.Level != "error" || ev.TraceID != "t-1" || ev.Fields["userId"] != "u1" || ev.Fields["amount"] != int64(100) {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+	if _, ok := ev.Fields[traceIDKey]; ok {
+		t.Error("traceId should be moved out of fields")
+	}
+	if ev.Stack == "" || ev.Caller == "" {
+		t.Errorf("missing stack or caller: %q %q", ev.Stack, ev.Caller)
+	}
+}
+
+func TestReporter_LevelAndRate(t *testing.T) {
+	l := newReportLogger(t, Config{Level: "debug", ReportLevel: "warn", ReportRate: 3})
+	rep := &captureReporter{}
+	l.AddReporter(rep)
+
+	ctx := context.Background()
+	l.Info(ctx, "info")
+	for i := 0; i < 5; i++ {
+		l.Warn(ctx, "warn")
+	}
+	_ = l.Sync()
+	if n := len(rep.snapshot()); n != 3 {
+		t.Fatalf("expected 3 reported events, got %d", n)
+	}
+	if l.ReportsDropped() != 2 {
+		t.Fatalf("expected 2 dropped, got %d", l.ReportsDropped())
+	}
+}
+
+func TestReporter_Webhook(t *testing.T) {
+	got := make(chan ReportEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev ReportEvent
+		_ = json.NewDecoder(r.Body).Decode(&ev)
+		got <- ev
+	}))
+	defer srv.Close()
+
+	l := newReportLogger(t, Config{ReportWebhook: srv.URL})
+	l.Error(context.Background(), "boom", zap.String("k", "v"))
+	_ = l.Sync()
+
+	select {
+	case ev := <-got:
+		if ev.Message != "boom" || ev.Fields["k"] != "v" {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	default:
+		t.Fatal("webhook not called")
+	}
+}
+
+func TestReporter_Sentry(t *testing.T) {
+	var (
+		auth  string
+		lines []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/prefix/api/42/envelope/" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		auth = r.Header.Get("X-Sentry-Auth")
+		sc := bufio.NewScanner(r.Body)
+		sc.Buffer(make([]byte, 1<<20), 1<<20)
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer srv.Close()
+
+	dsn := strings.Replace(srv.URL, "://", "://pubkey@", 1) + "/prefix/42"
+	r, err := NewSentryReporter(SentryConfig{DSN: dsn, Environment: "prod", Release: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("NewSentryReporter: %v", err)
+	}
+	stack := "main.handler\n\t/app/main.go:20\nnet/http.HandlerFunc.ServeHTTP\n\t/usr/local/go/src/net/http/server.go:2166"
+	err = r.Report(context.Background(), ReportEvent{
+		Level: "error", Message: "pay failed", TraceID: "t-1", Stack: stack,
+		Fields: map[string]interface{}{"error": "insufficient balance", "orderId": "o-1"},
+	})
+	if err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if !strings.Contains(auth, "sentry_key=pubkey") || !strings.Contains(auth, "sentry_version=7") {
+		t.Errorf("unexpected auth header: %s", auth)
+	}
+	if len(lines) != 3 || !strings.Contains(lines[1], `"type":"event"`) {
+		t.Fatalf("unexpected envelope: %q", lines)
+	}
+	var ev sentryEvent
+	if err := json.Unmarshal([]byte(lines[2]), &ev); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if ev.Level != "error" || ev.Environment != "prod" || ev.Release != "v1.2.3" || ev.Tags["traceId"] != "t-1" ||
+		ev.Extra["orderId"] != "o-1" || ev.Message["formatted"] != "pay failed" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+	exc := ev.Exception.Values[0]
+	if exc.Value != "insufficient balance" || len(exc.Stacktrace.Frames) != 2 {
+		t.Fatalf("unexpected exception: %+v", exc)
+	}
+	// 最内层的帧在最后
+	last := exc.Stacktrace.Frames[1]
+	if last.Function != "main.handler" || last.AbsPath != "/app/main.go" || last.Lineno != 20 || !last.InApp {
+		t.Errorf("unexpected frame: %+v", last)
+	}
+	if exc.Stacktrace.Frames[0].InApp {
+		t.Errorf("stdlib frame should not be in app: %+v", exc.Stacktrace.Frames[0])
+	}
+
+	if _, err := NewSentryReporter(SentryConfig{DSN: "https://sentry.io/"}); err == nil {
+		t.Error("expected invalid dsn error")
+	}
+}
+
+func TestReporter_Inactive(t *testing.T) {
+	l := newReportLogger(t, Config{})
+	if l.reports.active() {
+		t.Fatal("no reporter should be configured")
+	}
+	if os.Getenv("SENTRY_DSN") == "" {
+		l.Error(context.Background(), "not reported")
+	}
+}
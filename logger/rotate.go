@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lumberjack "gopkg.in/natefinch/lumberjack.v2"
+)
+
+// RotateEvent 日志文件切割事件
+type RotateEvent struct {
+	OldPath    string    // 切割出的备份文件路径（开启压缩时为 .gz 文件）
+	NewPath    string    // 新的当前日志文件路径
+	Compressed bool      // 备份文件是否已压缩
+	Time       time.Time // 切割时间
+}
+
+// 压缩完成的等待参数：lumberjack 在后台异步压缩备份文件
+const (
+	compressPollInterval = 100 * time.Millisecond
+	compressWaitTimeout  = time.Minute
+)
+
+// rotateWriter 包装 lumberjack，自行判断切割时机，以便在切割后触发回调
+type rotateWriter struct {
+	lj      *lumberjack.Logger
+	maxSize int64
+	guard   *dirGuard // 目录配额守卫，未配置时为 nil
+
+	mu    sync.Mutex
+	size  int64
+	hooks []func(RotateEvent)
+}
+
+// newRotateWriter 创建可回调的切割写入器
+func newRotateWriter(lj *lumberjack.Logger) *rotateWriter {
+	w := &rotateWriter{lj: lj, maxSize: int64(lj.MaxSize) * 1024 * 1024}
+	if w.maxSize <= 0 {
+		w.maxSize = 100 * 1024 * 1024 // 与 lumberjack 默认值一致
+	}
+	if info, err := os.Stat(lj.Filename); err == nil {
+		w.size = info.Size()
+	}
+	return w
+}
+
+// Write 写入日志，超出大小时先切割再写入
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.guard != nil && w.guard.due(time.Now()) {
+		w.enforceQuotaLocked()
+	}
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotateLocked(); err != nil {
+			return 0, err
+		}
+	}
+	if w.guard != nil && w.guard.paused {
+		return len(p), nil // 暂停期间丢弃文件输出
+	}
+	n, err := w.lj.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Sync 满足 zapcore.WriteSyncer，lumberjack 每次写入即落盘
+func (w *rotateWriter) Sync() error { return nil }
+
+// Rotate 手动触发切割
+func (w *rotateWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rotateLocked()
+}
+
+// OnRotate 注册切割回调
+func (w *rotateWriter) OnRotate(fn func(RotateEvent)) {
+	if fn == nil {
+		return
+	}
+	w.mu.Lock()
+	w.hooks = append(w.hooks, fn)
+	w.mu.Unlock()
+}
+
+// rotateLocked 执行切割并异步通知回调，调用方需持有锁
+func (w *rotateWriter) rotateLocked() error {
+	before := w.backups()
+	if err := w.lj.Rotate(); err != nil {
+		return err
+	}
+	w.size = 0
+	if w.guard != nil {
+		w.enforceQuotaLocked()
+	}
+	if len(w.hooks) == 0 {
+		return nil
+	}
+
+	ev := RotateEvent{OldPath: newestExcept(w.backups(), before), NewPath: w.lj.Filename, Time: time.Now()}
+	hooks := append([]func(RotateEvent){}, w.hooks...)
+	go func() {
+		if w.lj.Compress && ev.OldPath != "" {
+			ev.OldPath, ev.Compressed = waitCompressed(ev.OldPath)
+		}
+		for _, fn := range hooks {
+			fn(ev)
+		}
+	}()
+	return nil
+}
+
+// backups 列出当前目录下的备份文件（与 lumberjack 的命名规则一致：name-时间戳.ext）
+func (w *rotateWriter) backups() []string {
+	dir := filepath.Dir(w.lj.Filename)
+	base := filepath.Base(w.lj.Filename)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if strings.HasSuffix(name, ext) || strings.HasSuffix(name, ext+".gz") {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// newestExcept 返回切割后新出现的、时间戳最新的备份文件；
+// 列目录时 lumberjack 可能正在压缩，此时会同时看到原文件和未写完的 .gz，统一返回原文件路径，由 waitCompressed 等待压缩完成
+func newestExcept(after, before []string) string {
+	seen := make(map[string]bool, len(before))
+	for _, f := range before {
+		seen[f] = true
+	}
+	for i := len(after) - 1; i >= 0; i-- {
+		if !seen[after[i]] {
+			return strings.TrimSuffix(after[i], ".gz")
+		}
+	}
+	return ""
+}
+
+// waitCompressed 等待 lumberjack 后台压缩完成，超时则返回原路径
+func waitCompressed(path string) (string, bool) {
+	gz := path + ".gz"
+	deadline := time.Now().Add(compressWaitTimeout)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if _, err := os.Stat(gz); err == nil {
+				return gz, true
+			}
+		}
+		time.Sleep(compressPollInterval)
+	}
+	return path, false
+}
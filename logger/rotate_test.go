@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestOnRotate 测试切割回调
+func TestOnRotate(t *testing.T) {
+	testDir := "./test_logs"
+	defer os.RemoveAll(testDir)
+
+	logFile := filepath.Join(testDir, "rotate_test.log")
+	logger := New(&Config{Level: "info", FileName: logFile})
+
+	events := make(chan RotateEvent, 1)
+	logger.OnRotate(func(ev RotateEvent) { events <- ev })
+
+	logger.Info(context.Background(), "before rotate")
+	if err := logger.Rotate(); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.NewPath != logFile {
+			t.Errorf("Expected NewPath '%s', got '%s'", logFile, ev.NewPath)
+		}
+		if !strings.HasPrefix(filepath.Base(ev.OldPath), "rotate_test-") {
+			t.Errorf("Unexpected OldPath '%s'", ev.OldPath)
+		}
+		if ev.Compressed && !strings.HasSuffix(ev.OldPath, ".gz") {
+			t.Errorf("Compressed backup should end with .gz, got '%s'", ev.OldPath)
+		}
+		if _, err := os.Stat(ev.OldPath); err != nil {
+			t.Errorf("Backup file should exist: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected rotate event")
+	}
+}
+
+// TestRotateBySize 测试按大小自动切割
+func TestRotateBySize(t *testing.T) {
+	testDir := "./test_logs"
+	defer os.RemoveAll(testDir)
+
+	lj := filepath.Join(testDir, "size_test.log")
+	_ = os.MkdirAll(testDir, 0o755)
+	logger := New(&Config{Level: "info", FileName: lj, MaxSize: 1})
+
+	events := make(chan RotateEvent, 4)
+	logger.OnRotate(func(ev RotateEvent) { events <- ev })
+
+	// 直接写入切割器，避免向标准输出打印大量日志
+	line := []byte(strings.Repeat("x", 1023) + "\n")
+	for i := 0; i < 1100; i++ {
+		if _, err := logger.rotator.Write(line); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected rotate event after exceeding MaxSize")
+	}
+}
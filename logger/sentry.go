@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sentryClient 上报时使用的客户端标识
+const sentryClient = "go-utils-logger/1.0"
+
+// SentryConfig Sentry 上报配置
+type SentryConfig struct {
+	DSN         string // 如 https://<key>@o123.ingest.sentry.io/456
+	Environment string
+	Release     string
+	ServerName  string       // 默认主机名
+	Client      *http.Client // 默认 http.DefaultClient
+}
+
+// SentryReporter 通过 envelope 接口上报到 Sentry（含自建 Sentry），无需引入 sentry-go
+type SentryReporter struct {
+	cfg      SentryConfig
+	endpoint string
+	key      string
+}
+
+// NewSentryReporter 解析 DSN 并创建 Sentry 上报目标
+func NewSentryReporter(cfg SentryConfig) (*SentryReporter, error) {
+	u, err := url.Parse(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("logger: invalid sentry dsn: %w", err)
+	}
+	project := strings.TrimPrefix(u.Path[strings.LastIndexByte(u.Path, '/')+1:], "/")
+	if u.User == nil || u.User.Username() == "" || project == "" || u.Host == "" {
+		return nil, errors.New("logger: invalid sentry dsn")
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName, _ = os.Hostname()
+	}
+	prefix := strings.TrimSuffix(u.Path[:strings.LastIndexByte(u.Path, '/')+1], "/")
+	return &SentryReporter{
+		cfg:      cfg,
+		endpoint: u.Scheme + "://" + u.Host + prefix + "/api/" + project + "/envelope/",
+		key:      u.User.Username(),
+	}, nil
+}
+
+// sentryFrame Sentry 堆栈帧
+type sentryFrame struct {
+	Function string `json:"function,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+// sentryEvent Sentry 事件（本包用到的字段）
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Platform    string                 `json:"platform"`
+	Level       string                 `json:"level"`
+	Logger      string                 `json:"logger,omitempty"`
+	ServerName  string                 `json:"server_name,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	Message     map[string]string      `json:"message"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+	Exception   *sentryExceptions      `json:"exception,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+	Stacktrace *struct {
+		Frames []sentryFrame `json:"frames"`
+	} `json:"stacktrace,omitempty"`
+}
+
+// Report 实现 Reporter
+func (s *SentryReporter) Report(ctx context.Context, ev ReportEvent) error {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	event := s.event(hex.EncodeToString(id), ev)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	header, _ := json.Marshal(map[string]string{"event_id": event.EventID, "sent_at": time.Now().UTC().Format(time.RFC3339), "dsn": s.cfg.DSN})
+	body.Write(header)
+	body.WriteString("\n{\"type\":\"event\",\"length\":" + strconv.Itoa(len(payload)) + "}\n")
+	body.Write(payload)
+	body.WriteByte('\n')
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", "Sentry sentry_version=7, sentry_client="+sentryClient+", sentry_key="+s.key)
+	return doReport(s.cfg.Client, req)
+}
+
+// event 将 ReportEvent 转为 Sentry 事件：error 字段作为异常描述，zap 堆栈解析为堆栈帧，traceId 作为 tag
+func (s *SentryReporter) event(id string, ev ReportEvent) *sentryEvent {
+	level := ev.Level
+	switch level {
+	case "dpanic", "panic":
+		level = "fatal"
+	case "warn":
+		level = "warning"
+	}
+	out := &sentryEvent{
+		EventID:     id,
+		Timestamp:   ev.Time.UTC().Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       level,
+		ServerName:  s.cfg.ServerName,
+		Environment: s.cfg.Environment,
+		Release:     s.cfg.Release,
+		Message:     map[string]string{"formatted": ev.Message},
+		Extra:       ev.Fields,
+	}
+	if ev.TraceID != "" {
+		out.Tags = map[string]string{traceIDKey: ev.TraceID}
+	}
+	if ev.Caller != "" {
+		if out.Extra == nil {
+			out.Extra = map[string]interface{}{}
+		}
+		out.Extra["caller"] = ev.Caller
+	}
+	exc := sentryException{Type: ev.Message, Value: ev.Message}
+	if errMsg, ok := ev.Fields["error"].(string); ok {
+		exc.Value = errMsg
+	}
+	if frames := parseStack(ev.Stack); len(frames) > 0 {
+		exc.Stacktrace = &struct {
+			Frames []sentryFrame `json:"frames"`
+		}{Frames: frames}
+	}
+	out.Exception = &sentryExceptions{Values: []sentryException{exc}}
+	return out
+}
+
+// parseStack 解析 zap 输出的堆栈文本（函数名与 "\t文件:行号" 交替出现），按 Sentry 要求从最外层到最内层排列
+func parseStack(stack string) []sentryFrame {
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+	var frames []sentryFrame
+	for i := 0; i+1 < len(lines); i += 2 {
+		fn := strings.TrimSpace(lines[i])
+		loc := strings.TrimSpace(lines[i+1])
+		f := sentryFrame{Function: fn, AbsPath: loc}
+		if colon := strings.LastIndexByte(loc, ':'); colon > 0 {
+			if n, err := strconv.Atoi(loc[colon+1:]); err == nil {
+				f.AbsPath, f.Lineno = loc[:colon], n
+			}
+		}
+		f.InApp = inApp(fn, f.AbsPath)
+		frames = append(frames, f)
+	}
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames
+}
+
+// inApp 判断堆栈帧是否属于业务代码：标准库（包路径首段不含 "."）与模块缓存中的依赖不算
+func inApp(function, path string) bool {
+	if strings.Contains(path, "/pkg/mod/") {
+		return false
+	}
+	pkg := packageOf(function)
+	if pkg == "main" || strings.HasPrefix(pkg, "main.") {
+		return true
+	}
+	first, _, _ := strings.Cut(pkg, "/")
+	return strings.Contains(first, ".")
+}
@@ -0,0 +1,299 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// sinkDialTimeout 建立连接的超时
+	sinkDialTimeout = 3 * time.Second
+	// sinkWriteTimeout 单次写入的超时
+	sinkWriteTimeout = 3 * time.Second
+	// sinkRetryInterval 连接失败后的重试间隔，期间的日志直接丢弃，避免每条日志都阻塞在拨号上
+	sinkRetryInterval = 5 * time.Second
+	// sinkQueueSize 异步发送队列长度，队列满时丢弃，避免慢速的收集端拖住业务协程
+	sinkQueueSize = 1024
+	// sinkFlushTimeout Sync 等待队列发送完成的最长时间
+	sinkFlushTimeout = 5 * time.Second
+)
+
+// SinkConfig 额外的日志输出目标，与文件输出并列。日志经有界队列异步发送，
+// 队列满或发送失败时丢弃（Logger.SinksDropped 查看丢弃条数），Sync 时等待队列发送完成
+// 使用示例（YAML）：
+//
+//	sinks:
+//	  - type: gelf
+//	    network: udp
+//	    address: graylog.internal:12201
+//	  - type: syslog
+//	    network: tcp
+//	    address: 10.0.0.5:514
+//	    facility: local0
+//	    level: warn
+type SinkConfig struct {
+	Type     string `json:"type" yaml:"type"`         // gelf 或 syslog
+	Network  string `json:"network" yaml:"network"`   // udp（默认）、tcp；syslog 还支持 unixgram、unix
+	Address  string `json:"address" yaml:"address"`   // 默认 gelf 为 127.0.0.1:12201，syslog 为 127.0.0.1:514，unix 为 /dev/log
+	Level    string `json:"level" yaml:"level"`       // 该目标的最低级别，为空时跟随全局级别（只能比全局级别更严格）
+	Facility string `json:"facility" yaml:"facility"` // syslog facility，如 user（默认）、daemon、local0~local7
+	AppName  string `json:"appname" yaml:"appname"`   // syslog APP-NAME，默认进程名
+	Host     string `json:"host" yaml:"host"`         // 上报的主机名，默认 os.Hostname
+}
+
+// sinkFormat 将一条日志编码为一帧
+type sinkFormat func(ent zapcore.Entry, fields map[string]interface{}) ([]byte, error)
+
+// sinkCore 按 format 编码日志后放入队列，由后台协程写入网络连接
+// 位于 ruleCore 之内，Tee 写入时不会再按各 core 的级别过滤，因此 Write 中自行判断
+type sinkCore struct {
+	level  zapcore.Level
+	format sinkFormat
+	out    *netWriter
+	fields []zapcore.Field
+}
+
+// newSink 根据配置创建 sink
+func newSink(cfg SinkConfig) (*sinkCore, error) {
+	if cfg.Host == "" {
+		cfg.Host, _ = os.Hostname()
+	}
+	network := strings.ToLower(cfg.Network)
+	if network == "" {
+		network = "udp"
+	}
+	level := zapcore.DebugLevel
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return nil, fmt.Errorf("logger: invalid sink level %q", cfg.Level)
+		}
+	}
+
+	c := &sinkCore{level: level}
+	switch strings.ToLower(cfg.Type) {
+	case "gelf":
+		if network != "udp" && network != "tcp" {
+			return nil, fmt.Errorf("logger: unsupported gelf network %q", cfg.Network)
+		}
+		c.format = gelfFormat(cfg.Host)
+		c.out = newNetWriter(network, defaultString(cfg.Address, "127.0.0.1:12201"), gelfFrame(network))
+	case "syslog":
+		facility, ok := syslogFacilities[strings.ToLower(defaultString(cfg.Facility, "user"))]
+		if !ok {
+			return nil, fmt.Errorf("logger: unknown syslog facility %q", cfg.Facility)
+		}
+		addr := cfg.Address
+		switch network {
+		case "udp", "tcp":
+			addr = defaultString(addr, "127.0.0.1:514")
+		case "unix", "unixgram":
+			addr = defaultString(addr, "/dev/log")
+		default:
+			return nil, fmt.Errorf("logger: unsupported syslog network %q", cfg.Network)
+		}
+		c.format = syslogFormat(facility, cfg.Host, defaultString(cfg.AppName, appName()))
+		c.out = newNetWriter(network, addr, syslogFrame(network))
+	default:
+		return nil, fmt.Errorf("logger: unknown sink type %q", cfg.Type)
+	}
+	return c, nil
+}
+
+// Enabled 实现 zapcore.LevelEnabler
+func (c *sinkCore) Enabled(lvl zapcore.Level) bool {
+	return lvl >= c.level
+}
+
+// With 实现 zapcore.Core
+func (c *sinkCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(append(merged, c.fields...), fields...)
+	return &sinkCore{level: c.level, format: c.format, out: c.out, fields: merged}
+}
+
+// Check 实现 zapcore.Core
+func (c *sinkCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core
+func (c *sinkCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !c.Enabled(ent.Level) {
+		return nil
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	msg, err := c.format(ent, enc.Fields)
+	if err != nil {
+		return err
+	}
+	c.out.enqueue(msg)
+	return nil
+}
+
+// Sync 实现 zapcore.Core，等待已排队的日志发送完成
+func (c *sinkCore) Sync() error {
+	c.out.flush(sinkFlushTimeout)
+	return nil
+}
+
+// errSinkUnavailable 连接失败后的重试间隔内直接返回
+var errSinkUnavailable = errors.New("logger: sink unavailable")
+
+// netWriter 异步写入网络：日志进入有界队列，由后台协程惰性建立连接并写入，
+// 写入失败时断开并在下次写入时重连；队列满或写入失败的日志计入 dropped
+type netWriter struct {
+	network string
+	addr    string
+	frame   func(conn net.Conn, msg []byte) error
+
+	queue   chan []byte
+	pending atomic.Int64
+	dropped atomic.Int64
+
+	// 以下字段只由后台协程访问
+	conn     net.Conn
+	failedAt time.Time
+}
+
+// newNetWriter 创建网络写入器并启动后台发送协程，frame 负责按协议分帧写入
+func newNetWriter(network, addr string, frame func(conn net.Conn, msg []byte) error) *netWriter {
+	w := &netWriter{network: network, addr: addr, frame: frame, queue: make(chan []byte, sinkQueueSize)}
+	go w.loop()
+	return w
+}
+
+// enqueue 放入发送队列，队列满时丢弃
+func (w *netWriter) enqueue(msg []byte) {
+	w.pending.Add(1)
+	select {
+	case w.queue <- msg:
+	default:
+		w.pending.Add(-1)
+		w.dropped.Add(1)
+	}
+}
+
+// loop 后台发送协程，失败时写 stderr（不能经过 logger 本身，避免递归）；重试间隔内的丢弃不再重复提示
+func (w *netWriter) loop() {
+	for msg := range w.queue {
+		if err := w.write(msg); err != nil {
+			w.dropped.Add(1)
+			if !errors.Is(err, errSinkUnavailable) {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+			}
+		}
+		w.pending.Add(-1)
+	}
+}
+
+// flush 等待队列中的日志发送完成
+func (w *netWriter) flush(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for w.pending.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// write 写入一帧，连接断开（如 TCP 对端重启）时重连一次
+func (w *netWriter) write(msg []byte) error {
+	for attempt := 0; attempt < 2; attempt++ {
+		if err := w.connect(); err != nil {
+			return err
+		}
+		_ = w.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+		err := w.frame(w.conn, msg)
+		if err == nil {
+			return nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+		if attempt == 1 {
+			return fmt.Errorf("logger: write to %s://%s: %w", w.network, w.addr, err)
+		}
+	}
+	return nil
+}
+
+// connect 建立连接
+func (w *netWriter) connect() error {
+	if w.conn != nil {
+		return nil
+	}
+	if !w.failedAt.IsZero() && time.Since(w.failedAt) < sinkRetryInterval {
+		return errSinkUnavailable
+	}
+	conn, err := net.DialTimeout(w.network, w.addr, sinkDialTimeout)
+	if err != nil {
+		w.failedAt = time.Now()
+		return fmt.Errorf("logger: dial %s://%s: %w", w.network, w.addr, err)
+	}
+	w.conn, w.failedAt = conn, time.Time{}
+	return nil
+}
+
+// newSinks 根据配置创建全部 sink，配置错误的 sink 跳过并写 stderr
+func newSinks(cfgs []SinkConfig) []*sinkCore {
+	var cores []*sinkCore
+	for _, cfg := range cfgs {
+		c, err := newSink(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			continue
+		}
+		cores = append(cores, c)
+	}
+	return cores
+}
+
+// SinksDropped 返回因队列已满或写入失败被丢弃的 sink 日志条数（所有 sink 合计）
+func (l *Logger) SinksDropped() int64 {
+	var n int64
+	for _, c := range l.sinks {
+		n += c.out.dropped.Load()
+	}
+	return n
+}
+
+// defaultString s 为空时返回 def
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// syslogSeverity zap 级别对应的 syslog severity，GELF 的 level 字段同样使用该值
+func syslogSeverity(lvl zapcore.Level) int {
+	switch lvl {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel:
+		return 2
+	case zapcore.PanicLevel:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,250 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSink_GELFUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp unavailable: %v", err)
+	}
+	defer pc.Close()
+
+	l := newReportLogger(t, Config{Level: "debug", Sinks: []SinkConfig{{Type: "gelf", Address: pc.LocalAddr().String(), Host: "web-1"}}})
+	ctx := context.WithValue(context.Background(), "traceId", "t-1")
+	l.Info(ctx, "order created", zap.String("id", "o-1"), zap.Int("amount", 100), zap.Bool("paid", true))
+
+	buf := make([]byte, 65536)
+	_ = pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var msg map[string]interface{}
+	if err := json.Unmarshal(buf[:n], &msg); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if msg["version"] != "1.1" || msg["host"] != "web-1" || msg["short_message"] != "order created" || msg["level"] != float64(6) {
+		t.Errorf("unexpected message: %v", msg)
+	}
+	if msg["_traceId"] != "t-1" || msg["_field_id"] != "o-1" || msg["_amount"] != float64(100) || msg["_paid"] != "true" {
+		t.Errorf("unexpected additional fields: %v", msg)
+	}
+	if _, ok := msg["_caller"]; !ok {
+		t.Error("missing _caller")
+	}
+}
+
+func TestSink_GELFChunked(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp unavailable: %v", err)
+	}
+	defer pc.Close()
+
+	core, err := newSink(SinkConfig{Type: "gelf", Address: pc.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("newSink: %v", err)
+	}
+	big := strings.Repeat("x", 3*gelfChunkSize)
+	if err := core.Write(zapcore.Entry{Level: zapcore.WarnLevel, Time: time.Now(), Message: big}, nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	chunks := map[byte][]byte{}
+	var count byte
+	buf := make([]byte, 65536)
+	for {
+		_ = pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if n > gelfChunkSize || buf[0] != 0x1e || buf[1] != 0x0f {
+			t.Fatalf("invalid chunk header: % x (%d bytes)", buf[:12], n)
+		}
+		count = buf[11]
+		chunks[buf[10]] = append([]byte(nil), buf[12:n]...)
+		if len(chunks) == int(count) {
+			break
+		}
+	}
+	var whole bytes.Buffer
+	for i := byte(0); i < count; i++ {
+		whole.Write(chunks[i])
+	}
+	var msg map[string]interface{}
+	if err := json.Unmarshal(whole.Bytes(), &msg); err != nil {
+		t.Fatalf("reassemble: %v", err)
+	}
+	if msg["short_message"] != big || msg["level"] != float64(4) {
+		t.Errorf("unexpected reassembled message")
+	}
+}
+
+func TestSink_GELFTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("tcp unavailable: %v", err)
+	}
+	defer ln.Close()
+	got := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		var frames []string
+		for len(frames) < 2 {
+			frame, err := r.ReadString(0)
+			if err != nil {
+				break
+			}
+			frames = append(frames, strings.TrimSuffix(frame, "\x00"))
+		}
+		got <- frames
+	}()
+
+	l := newReportLogger(t, Config{Sinks: []SinkConfig{{Type: "gelf", Network: "tcp", Address: ln.Addr().String()}}})
+	l.Info(context.Background(), "first")
+	l.Error(context.Background(), "second")
+
+	select {
+	case frames := <-got:
+		if len(frames) != 2 || !strings.Contains(frames[0], `"short_message":"first"`) || !strings.Contains(frames[1], `"full_message"`) {
+			t.Fatalf("unexpected frames: %q", frames)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no frames received")
+	}
+}
+
+func TestSink_SyslogTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("tcp unavailable: %v", err)
+	}
+	defer ln.Close()
+	got := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		size, err := r.ReadString(' ')
+		if err != nil {
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(size))
+		if err != nil {
+			return
+		}
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(r, msg); err == nil {
+			got <- string(msg)
+		}
+	}()
+
+	l := newReportLogger(t, Config{Sinks: []SinkConfig{{
+		Type: "syslog", Network: "tcp", Address: ln.Addr().String(),
+		Facility: "local0", AppName: "order-svc", Host: "web 1", Level: "warn",
+	}}})
+	l.Info(context.Background(), "below sink level")
+	l.Warn(context.Background(), "slow query", zap.String("sql", `select "a"]`), zap.Duration("cost", 1500*time.Millisecond))
+
+	select {
+	case msg := <-got:
+		// local0(16)*8 + warning(4) = 132
+		if !strings.HasPrefix(msg, "<132>1 ") {
+			t.Errorf("unexpected priority: %s", msg)
+		}
+		for _, want := range []string{" web1 order-svc ", `[fields@32473 caller="`, `cost="1.5s"`, `sql="select \"a\"\]"`, "] slow query"} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("message %q missing %q", msg, want)
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no message received")
+	}
+}
+
+func TestSink_InvalidConfig(t *testing.T) {
+	for _, cfg := range []SinkConfig{
+		{Type: "kafka"},
+		{Type: "gelf", Network: "unix"},
+		{Type: "syslog", Facility: "nope"},
+		{Type: "syslog", Level: "loud"},
+	} {
+		if _, err := newSink(cfg); err == nil {
+			t.Errorf("expected error for %+v", cfg)
+		}
+	}
+}
+
+// TestSink_SlowCollector 收集端阻塞时日志调用不阻塞，队列满后丢弃并计数，Sync 等待队列发送完成
+func TestSink_SlowCollector(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("tcp unavailable: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() { _, _ = io.Copy(io.Discard, conn) }()
+		}
+	}()
+
+	release := make(chan struct{})
+	var written atomic.Int64
+	w := newNetWriter("tcp", ln.Addr().String(), func(conn net.Conn, msg []byte) error {
+		<-release
+		written.Add(1)
+		return nil
+	})
+	core := &sinkCore{level: zapcore.DebugLevel, format: func(ent zapcore.Entry, _ map[string]interface{}) ([]byte, error) {
+		return []byte(ent.Message), nil
+	}, out: w}
+
+	start := time.Now()
+	total := sinkQueueSize + 10
+	for i := 0; i < total; i++ {
+		if err := core.Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: "m"}, nil); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("writes blocked for %v", d)
+	}
+	dropped := w.dropped.Load()
+	if dropped < 9 {
+		t.Errorf("dropped = %d, want >= 9", dropped)
+	}
+
+	close(release)
+	_ = core.Sync()
+	if got := written.Load() + dropped; got != int64(total) {
+		t.Errorf("written + dropped = %d, want %d", got, total)
+	}
+}
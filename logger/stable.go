@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// ReservedFieldPrefix 业务字段与标准字段重名时添加的前缀，例如 msg -> field_msg
+const ReservedFieldPrefix = "field_"
+
+// traceIDKey 日志中 traceId 字段的 key
+const traceIDKey = "traceId"
+
+// stableBufferPool stableEncoder 输出使用的缓冲池
+var stableBufferPool = buffer.NewPool()
+
+// reservedKeys 开启 StableFields 后保留给顶层标准字段的 key
+var reservedKeys = map[string]bool{
+	"time": true, "level": true, "caller": true, "msg": true,
+	"logger": true, "stacktrace": true, traceIDKey: true,
+}
+
+// stableCore 处理字段重名并把 With 携带的 traceId 提升到每条日志的 traceId 字段，
+// 配合 stableEncoder 保证 traceId 总是出现在固定位置
+type stableCore struct {
+	zapcore.Core
+	traceID *zapcore.Field
+}
+
+// With 实现 zapcore.Core
+func (c *stableCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &stableCore{traceID: c.traceID}
+	rest := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		if f.Key == traceIDKey {
+			f := f
+			clone.traceID = &f
+			continue
+		}
+		rest = append(rest, renameReserved(f))
+	}
+	clone.Core = c.Core.With(rest)
+	return clone
+}
+
+// Check 实现 zapcore.Core
+func (c *stableCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core；显式传入的 traceId 字段与 context 中的 traceId 同时存在时只保留第一个
+// （显式字段在前），避免输出重复的 JSON key
+func (c *stableCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	out := make([]zapcore.Field, 0, len(fields)+1)
+	hasTrace := false
+	for _, f := range fields {
+		if f.Key == traceIDKey {
+			if !hasTrace {
+				hasTrace = true
+				out = append(out, f)
+			}
+			continue
+		}
+		out = append(out, renameReserved(f))
+	}
+	if !hasTrace && c.traceID != nil {
+		out = append(out, *c.traceID)
+	}
+	return c.Core.Write(ent, out)
+}
+
+// renameReserved 与标准字段重名的业务字段加上前缀
+func renameReserved(f zapcore.Field) zapcore.Field {
+	if reservedKeys[f.Key] {
+		f.Key = ReservedFieldPrefix + f.Key
+	}
+	return f
+}
+
+// stableEncoder 固定顶层字段顺序的 JSON 编码器：time, level, traceId, logger, caller, msg，之后为业务字段与 stacktrace
+// 标准字段由本编码器按 EncoderConfig 中的编码函数输出，业务字段仍交给 zap 的 JSON 编码器
+type stableEncoder struct {
+	zapcore.Encoder // 仅编码业务字段的 JSON 编码器（标准 key 均已置空）
+	cfg             zapcore.EncoderConfig
+}
+
+// newStableEncoder 创建固定字段顺序的编码器
+func newStableEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	inner := cfg
+	inner.TimeKey, inner.LevelKey, inner.NameKey = "", "", ""
+	inner.CallerKey, inner.FunctionKey, inner.MessageKey = "", "", ""
+	return &stableEncoder{Encoder: zapcore.NewJSONEncoder(inner), cfg: cfg}
+}
+
+// Clone 实现 zapcore.Encoder
+func (e *stableEncoder) Clone() zapcore.Encoder {
+	return &stableEncoder{Encoder: e.Encoder.Clone(), cfg: e.cfg}
+}
+
+// EncodeEntry 实现 zapcore.Encoder
+func (e *stableEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	var traceID *zapcore.Field
+	rest := make([]zapcore.Field, 0, len(fields))
+	for i := range fields {
+		if fields[i].Key == traceIDKey {
+			if traceID == nil {
+				traceID = &fields[i]
+			}
+			continue
+		}
+		rest = append(rest, fields[i])
+	}
+
+	body, err := e.Encoder.EncodeEntry(ent, rest)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Free()
+
+	var head bytes.Buffer
+	head.WriteByte('{')
+	writeKV := func(key string, val interface{}) {
+		if key == "" || val == nil {
+			return
+		}
+		if head.Len() > 1 {
+			head.WriteByte(',')
+		}
+		writeJSON(&head, key)
+		head.WriteByte(':')
+		writeJSON(&head, val)
+	}
+
+	writeKV(e.cfg.TimeKey, encodeWith(func(ae zapcore.PrimitiveArrayEncoder) {
+		if e.cfg.EncodeTime != nil {
+			e.cfg.EncodeTime(ent.Time, ae)
+		}
+	}))
+	writeKV(e.cfg.LevelKey, encodeWith(func(ae zapcore.PrimitiveArrayEncoder) {
+		if e.cfg.EncodeLevel != nil {
+			e.cfg.EncodeLevel(ent.Level, ae)
+		}
+	}))
+	if traceID != nil {
+		m := zapcore.NewMapObjectEncoder()
+		traceID.AddTo(m)
+		writeKV(traceIDKey, m.Fields[traceIDKey])
+	}
+	if ent.LoggerName != "" {
+		writeKV(e.cfg.NameKey, ent.LoggerName)
+	}
+	if ent.Caller.Defined {
+		writeKV(e.cfg.CallerKey, encodeWith(func(ae zapcore.PrimitiveArrayEncoder) {
+			if e.cfg.EncodeCaller != nil {
+				e.cfg.EncodeCaller(ent.Caller, ae)
+			}
+		}))
+	}
+	writeKV(e.cfg.MessageKey, ent.Message)
+
+	// 拼接业务字段：body 形如 {"k":"v"}\n 或 {}\n
+	rest2 := bytes.TrimPrefix(body.Bytes(), []byte{'{'})
+	out := stableBufferPool.Get()
+	out.Write(head.Bytes())
+	if len(rest2) > 0 && rest2[0] != '}' {
+		out.AppendByte(',')
+	}
+	out.Write(rest2)
+	return out, nil
+}
+
+// encodeWith 借助 MapObjectEncoder 执行 zap 的原始类型编码函数，取得编码后的值
+func encodeWith(fn func(zapcore.PrimitiveArrayEncoder)) interface{} {
+	m := zapcore.NewMapObjectEncoder()
+	_ = m.AddArray("v", zapcore.ArrayMarshalerFunc(func(ae zapcore.ArrayEncoder) error {
+		fn(ae)
+		return nil
+	}))
+	if arr, ok := m.Fields["v"].([]interface{}); ok && len(arr) > 0 {
+		return arr[0]
+	}
+	return nil
+}
+
+// writeJSON 以 JSON 编码写入值，不转义 HTML 字符（与 zap 的 JSON 编码器一致）
+func writeJSON(buf *bytes.Buffer, v interface{}) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+	// json.Encoder 会追加换行
+	buf.Truncate(buf.Len() - 1)
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// topLevelKeys 按出现顺序返回一行 JSON 日志的顶层 key
+func topLevelKeys(t *testing.T, line []byte) ([]string, map[string]interface{}) {
+	t.Helper()
+	var m map[string]interface{}
+	if err := json.Unmarshal(line, &m); err != nil {
+		t.Fatalf("invalid json %q: %v", line, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	_, _ = dec.Token() // {
+	var keys []string
+	for dec.More() {
+		tok, _ := dec.Token()
+		keys = append(keys, tok.(string))
+		var skip json.RawMessage
+		_ = dec.Decode(&skip)
+	}
+	return keys, m
+}
+
+// TestStableFields 测试固定字段顺序与保留字段冲突处理
+func TestStableFields(t *testing.T) {
+	testDir := "./test_logs_stable"
+	defer os.RemoveAll(testDir)
+
+	file := filepath.Join(testDir, "stable.log")
+	l := New(&Config{Level: "debug", FileName: file, StableFields: true})
+	ctx := context.WithValue(context.Background(), "traceId", "t-1")
+
+	info := func(msg string, fields ...zap.Field) { l.Info(ctx, msg, fields...) }
+	info("hello <world>", zap.String("msg", "user msg"), zap.Int("level", 3), zap.String("user", "bob"))
+
+	// With 携带的 traceId 同样提升到固定位置
+	l.logger.With(zap.String("traceId", "t-2"), zap.String("time", "x")).Info("with fields")
+	_ = l.Sync()
+
+	data, _ := os.ReadFile(file)
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(lines), data)
+	}
+
+	keys, m := topLevelKeys(t, lines[0])
+	want := []string{"time", "level", "traceId", "caller", "msg", "field_msg", "field_level", "user"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("unexpected key order: %v, want %v", keys, want)
+	}
+	if m["msg"] != "hello <world>" || m["field_msg"] != "user msg" || m["level"] != "INFO" || m["traceId"] != "t-1" {
+		t.Errorf("unexpected values: %v", m)
+	}
+	if !bytes.Contains(lines[0], []byte("<world>")) {
+		t.Errorf("html characters should not be escaped: %s", lines[0])
+	}
+
+	keys, m = topLevelKeys(t, lines[1])
+	want = []string{"time", "level", "traceId", "caller", "msg", "field_time"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("unexpected key order: %v, want %v", keys, want)
+	}
+	if m["traceId"] != "t-2" {
+		t.Errorf("expected traceId from With, got %v", m["traceId"])
+	}
+}
+
+// TestStableFields_ExplicitTraceID 显式传入的 traceId 与 context 中的 traceId 只输出一个
+func TestStableFields_ExplicitTraceID(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "stable.log")
+	l := New(&Config{Level: "debug", FileName: file, StableFields: true})
+	ctx := WithTraceID(context.Background(), "ctx-trace")
+
+	l.Info(ctx, "explicit", zap.String("traceId", "explicit-trace"))
+	_ = l.Sync()
+
+	data, _ := os.ReadFile(file)
+	keys, m := topLevelKeys(t, bytes.TrimSpace(data))
+	n := 0
+	for _, k := range keys {
+		if k == "traceId" {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("traceId emitted %d times: %s", n, data)
+	}
+	if m["traceId"] != "explicit-trace" {
+		t.Errorf("traceId = %v, want explicit-trace", m["traceId"])
+	}
+}
@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syslogSDID 结构化数据的 SD-ID，32473 为 RFC 5612 保留给示例与文档的企业号
+const syslogSDID = "fields@32473"
+
+// syslogFacilities facility 名称 -> 编号
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogFormat 编码为 RFC 5424：<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG，
+// 业务字段与 caller 放入结构化数据，堆栈追加在消息之后
+func syslogFormat(facility int, host, app string) sinkFormat {
+	host = syslogHeaderField(host, 255)
+	app = syslogHeaderField(app, 48)
+	procID := strconv.Itoa(os.Getpid())
+	return func(ent zapcore.Entry, fields map[string]interface{}) ([]byte, error) {
+		var b strings.Builder
+		b.WriteString("<" + strconv.Itoa(facility*8+syslogSeverity(ent.Level)) + ">1 ")
+		b.WriteString(ent.Time.Format("2006-01-02T15:04:05.000000Z07:00"))
+		b.WriteString(" " + host + " " + app + " " + procID + " - ")
+
+		params := make([]string, 0, len(fields)+1)
+		if ent.Caller.Defined {
+			params = append(params, syslogParam("caller", ent.Caller.TrimmedPath()))
+		}
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			params = append(params, syslogParam(k, fields[k]))
+		}
+		if len(params) == 0 {
+			b.WriteString("-")
+		} else {
+			b.WriteString("[" + syslogSDID + " " + strings.Join(params, " ") + "]")
+		}
+
+		b.WriteString(" " + ent.Message)
+		if ent.Stack != "" {
+			b.WriteString("\n" + ent.Stack)
+		}
+		return []byte(b.String()), nil
+	}
+}
+
+// syslogParam 编码 SD-PARAM：名称只保留可打印 ASCII（不含 '=', ' ', ']', '"'）且最长 32 字符，值转义 '"', '\', ']'
+func syslogParam(key string, v interface{}) string {
+	name := strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	var val string
+	switch x := v.(type) {
+	case string:
+		val = x
+	case map[string]interface{}, []interface{}:
+		b, _ := json.Marshal(x)
+		val = string(b)
+	default:
+		val = fmt.Sprint(x)
+	}
+	val = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(val)
+	return name + `="` + val + `"`
+}
+
+// syslogHeaderField 头部字段只允许可打印 ASCII，为空时使用 NILVALUE "-"
+func syslogHeaderField(s string, max int) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) > max {
+		s = s[:max]
+	}
+	return defaultString(s, "-")
+}
+
+// syslogFrame syslog 分帧：TCP 使用 RFC 6587 的 octet-counting（"长度 消息"），unix 流以换行结尾，
+// 报文协议一条消息一个报文
+func syslogFrame(network string) func(conn net.Conn, msg []byte) error {
+	switch network {
+	case "tcp":
+		return func(conn net.Conn, msg []byte) error {
+			_, err := conn.Write(append([]byte(strconv.Itoa(len(msg))+" "), msg...))
+			return err
+		}
+	case "unix":
+		return func(conn net.Conn, msg []byte) error {
+			_, err := conn.Write(append(msg, '\n'))
+			return err
+		}
+	}
+	return func(conn net.Conn, msg []byte) error {
+		_, err := conn.Write(msg)
+		return err
+	}
+}
+
+// appName 默认的 APP-NAME：进程名
+func appName() string {
+	return filepath.Base(os.Args[0])
+}
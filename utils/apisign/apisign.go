@@ -0,0 +1,154 @@
+// Package apisign 开放平台风格的 API 签名：参数按 key 排序拼接 + 时间戳 + 随机串，
+// 使用 MD5 或 HMAC-SHA256 计算签名，服务端校验签名、时间窗口并防重放
+//
+// 待签名串规则（与常见支付/开放平台一致）：
+//
+//	除 sign 外的非空参数按 key 字典序排列，拼接为 k1=v1&k2=v2...，末尾追加 &key=<secret>
+//	非表单请求的 body 以 body_sha256=hex(sha256(body)) 参与签名（空 body 不参与），防止 body 被篡改
+//	MD5：        upper(hex(md5(str)))
+//	HMAC-SHA256：upper(hex(hmac_sha256(secret, str)))
+package apisign
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 公共参数名
+const (
+	ParamAppKey    = "app_key"
+	ParamTimestamp = "timestamp"
+	ParamNonce     = "nonce"
+	ParamSignType  = "sign_type"
+	ParamSign      = "sign"
+
+	// ParamBodyDigest 非表单请求 body 的 SHA-256 摘要，由 Transport 与 Middleware 根据 body 计算，不随请求传输
+	ParamBodyDigest = "body_sha256"
+)
+
+// Algorithm 签名算法
+type Algorithm string
+
+const (
+	MD5        Algorithm = "MD5"
+	HMACSHA256 Algorithm = "HMAC-SHA256"
+)
+
+// 校验失败的错误
+var (
+	ErrMissingParam      = errors.New("apisign: missing required param")
+	ErrUnknownApp        = errors.New("apisign: unknown app key")
+	ErrTimestampExpired  = errors.New("apisign: timestamp out of allowed window")
+	ErrNonceReused       = errors.New("apisign: nonce already used")
+	ErrSignatureMismatch = errors.New("apisign: signature mismatch")
+)
+
+// StringToSign 生成待签名串（不含 &key=secret 部分），sign 参数与空值参数不参与签名
+func StringToSign(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k, v := range params {
+		if k == ParamSign || v == "" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(params[k])
+	}
+	return b.String()
+}
+
+// Sign 按指定算法计算签名
+func Sign(params map[string]string, secret string, algo Algorithm) (string, error) {
+	str := StringToSign(params) + "&key=" + secret
+	var h hash.Hash
+	switch algo {
+	case MD5, "":
+		h = md5.New()
+	case HMACSHA256:
+		h = hmac.New(sha256.New, []byte(secret))
+	default:
+		return "", fmt.Errorf("apisign: unsupported algorithm %q", algo)
+	}
+	h.Write([]byte(str))
+	return strings.ToUpper(hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// Signer 客户端签名器
+type Signer struct {
+	AppKey    string
+	Secret    string
+	Algorithm Algorithm // 默认 MD5
+
+	now func() time.Time
+}
+
+// NewSigner 创建签名器
+func NewSigner(appKey, secret string, algo Algorithm) *Signer {
+	return &Signer{AppKey: appKey, Secret: secret, Algorithm: algo, now: time.Now}
+}
+
+// SignParams 补齐 app_key/timestamp/nonce/sign_type 并计算 sign，返回新的参数表（不修改入参）
+func (s *Signer) SignParams(params map[string]string) (map[string]string, error) {
+	out := make(map[string]string, len(params)+5)
+	for k, v := range params {
+		out[k] = v
+	}
+	nonce, err := NewNonce()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	out[ParamAppKey] = s.AppKey
+	out[ParamTimestamp] = strconv.FormatInt(now().Unix(), 10)
+	out[ParamNonce] = nonce
+	if s.Algorithm != "" {
+		out[ParamSignType] = string(s.Algorithm)
+	}
+	sign, err := Sign(out, s.Secret, s.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	out[ParamSign] = sign
+	return out, nil
+}
+
+// BodyDigest 计算 body 的十六进制 SHA-256 摘要，空 body 返回空串（不参与签名）。
+// 手动调用 SignParams 签名 JSON 等非表单请求时，需把结果放入 params[ParamBodyDigest]
+func BodyDigest(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// NewNonce 生成 32 位十六进制随机串
+func NewNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,174 @@
+package apisign
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 微信支付文档中的签名示例
+func TestSign(t *testing.T) {
+	params := map[string]string{
+		"appid":       "wxd930ea5d5a258f4f",
+		"mch_id":      "10000100",
+		"device_info": "1000",
+		"body":        "test",
+		"nonce_str":   "ibuaiVcKdpRxkhJA",
+		"empty":       "",
+		"sign":        "ignored",
+	}
+	secret := "192006250b4c09247ec02edce69f6a2d"
+	assert.Equal(t, "appid=wxd930ea5d5a258f4f&body=test&device_info=1000&mch_id=10000100&nonce_str=ibuaiVcKdpRxkhJA", StringToSign(params))
+
+	sign, err := Sign(params, secret, MD5)
+	assert.NoError(t, err)
+	assert.Equal(t, "9A0A8659F005D6984697E2CA0A9CF3B7", sign)
+
+	sign, err = Sign(params, secret, HMACSHA256)
+	assert.NoError(t, err)
+	assert.Equal(t, "6A9AE1657590FD6257D693A078E1C3E4BB6BA4DC30B23E0EE2496E54170DACD6", sign)
+}
+
+func TestVerify(t *testing.T) {
+	secrets := func(_ context.Context, appKey string) (string, error) {
+		if appKey != "app1" {
+			return "", ErrUnknownApp
+		}
+		return "s3cret", nil
+	}
+	v := &Verifier{Secrets: secrets, Nonces: NewMemoryNonceStore()}
+	s := NewSigner("app1", "s3cret", HMACSHA256)
+	ctx := context.Background()
+
+	params, err := s.SignParams(map[string]string{"order_id": "1001"})
+	assert.NoError(t, err)
+	appKey, err := v.Verify(ctx, params)
+	assert.NoError(t, err)
+	assert.Equal(t, "app1", appKey)
+
+	_, err = v.Verify(ctx, params)
+	assert.ErrorIs(t, err, ErrNonceReused)
+
+	params, _ = s.SignParams(map[string]string{"order_id": "1001"})
+	params["order_id"] = "1002"
+	_, err = v.Verify(ctx, params)
+	assert.ErrorIs(t, err, ErrSignatureMismatch)
+
+	s.now = func() time.Time { return time.Now().Add(-10 * time.Minute) }
+	params, _ = s.SignParams(nil)
+	_, err = v.Verify(ctx, params)
+	assert.ErrorIs(t, err, ErrTimestampExpired)
+
+	_, err = v.Verify(ctx, map[string]string{"app_key": "app1"})
+	assert.ErrorIs(t, err, ErrMissingParam)
+}
+
+func TestTransportAndMiddleware(t *testing.T) {
+	// 设置 OnReject，避免默认的全局 logger 在包目录下写日志文件
+	v := &Verifier{
+		Secrets:  func(context.Context, string) (string, error) { return "s3cret", nil },
+		Nonces:   NewMemoryNonceStore(),
+		OnReject: func(*http.Request, int, error) {},
+	}
+	srv := httptest.NewServer(v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		appKey, _ := AppKeyFromContext(r.Context())
+		_ = r.ParseForm()
+		_, _ = io.WriteString(w, appKey+":"+r.PostForm.Get("amount"))
+	})))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewSigner("app1", "s3cret", MD5).Transport(nil)}
+	resp, err := client.Post(srv.URL+"/pay?order_id=1", "application/x-www-form-urlencoded", strings.NewReader(url.Values{"amount": {"100"}}.Encode()))
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	assert.Equal(t, "app1:100", string(body))
+
+	// 未签名的请求被拒绝
+	resp, err = http.Get(srv.URL + "/pay?order_id=1")
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestTransportAndMiddleware_BodyDigest(t *testing.T) {
+	v := &Verifier{
+		Secrets:  func(context.Context, string) (string, error) { return "s3cret", nil },
+		OnReject: func(*http.Request, int, error) {},
+	}
+	srv := httptest.NewServer(v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	})))
+	defer srv.Close()
+
+	// 篡改 body 的中间人：在签名之后替换请求体
+	var tamper bool
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if tamper {
+			body := `{"amount":1}`
+			req.Body = io.NopCloser(strings.NewReader(body))
+			req.ContentLength = int64(len(body))
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	client := &http.Client{Transport: NewSigner("app1", "s3cret", HMACSHA256).Transport(base)}
+
+	resp, err := client.Post(srv.URL+"/pay", "application/json", strings.NewReader(`{"amount":100}`))
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	assert.Equal(t, `{"amount":100}`, string(body))
+
+	tamper = true
+	resp, err = client.Post(srv.URL+"/pay", "application/json", strings.NewReader(`{"amount":100}`))
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestMiddleware_RejectOpaque(t *testing.T) {
+	var statuses []int
+	var reasons []error
+	v := &Verifier{
+		Secrets:     func(context.Context, string) (string, error) { return "s3cret", nil },
+		MaxBodySize: 16,
+		OnReject: func(_ *http.Request, status int, err error) {
+			statuses = append(statuses, status)
+			reasons = append(reasons, err)
+		},
+	}
+	srv := httptest.NewServer(v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	defer srv.Close()
+	client := &http.Client{Transport: NewSigner("app1", "s3cret", HMACSHA256).Transport(nil)}
+
+	// 超过 MaxBodySize 返回 413
+	resp, err := client.Post(srv.URL+"/pay", "application/json", strings.NewReader(`{"amount":100,"memo":"too long"}`))
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	// 401 只返回固定文案，具体原因交给 OnReject
+	resp, err = http.Get(srv.URL + "/pay?app_key=app1&timestamp=1&nonce=n&sign=x")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusText(http.StatusUnauthorized)+"\n", string(body))
+
+	assert.Equal(t, []int{http.StatusRequestEntityTooLarge, http.StatusUnauthorized}, statuses)
+	assert.ErrorIs(t, reasons[1], ErrTimestampExpired)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -0,0 +1,62 @@
+package apisign
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NonceStore 记录已使用的 nonce，Use 首次使用返回 true，重复使用返回 false
+type NonceStore interface {
+	Use(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// RedisNonceStore 基于 Redis SET NX 的 nonce 存储，多实例部署时共享
+type RedisNonceStore struct {
+	c      redis.Cmdable
+	prefix string
+}
+
+// NewRedisNonceStore 创建 Redis nonce 存储，c 可以是 rediscluster 创建的集群客户端
+func NewRedisNonceStore(c redis.Cmdable, prefix string) *RedisNonceStore {
+	return &RedisNonceStore{c: c, prefix: prefix}
+}
+
+// Use 实现 NonceStore
+func (s *RedisNonceStore) Use(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return s.c.SetNX(ctx, s.prefix+key, 1, ttl).Result()
+}
+
+// MemoryNonceStore 进程内 nonce 存储，适用于单实例或测试
+type MemoryNonceStore struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryNonceStore 创建进程内 nonce 存储
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{seen: make(map[string]time.Time)}
+}
+
+// Use 实现 NonceStore，每分钟最多清理一次过期记录
+func (s *MemoryNonceStore) Use(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) > time.Minute {
+		s.lastSweep = now
+		for k, exp := range s.seen {
+			if now.After(exp) {
+				delete(s.seen, k)
+			}
+		}
+	}
+	if exp, ok := s.seen[key]; ok && now.Before(exp) {
+		return false, nil
+	}
+	s.seen[key] = now.Add(ttl)
+	return true, nil
+}
@@ -0,0 +1,96 @@
+package apisign
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Transport 返回自动签名的 http.RoundTripper，可直接用于 httpx：
+//
+//	client := httpx.NewClient(httpx.WithBaseURL(api), httpx.WithTransport(signer.Transport(nil)))
+//
+// 参与签名的参数为 URL query 与 application/x-www-form-urlencoded 表单字段（同名参数取第一个值），
+// 其它内容类型的 body 以 SHA-256 摘要参与签名；生成的公共参数与 sign 追加到 URL query 中；
+// base 为空时使用 http.DefaultTransport
+func (s *Signer) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &signTransport{signer: s, base: base}
+}
+
+type signTransport struct {
+	signer *Signer
+	base   http.RoundTripper
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *signTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	params := firstValues(req.URL.Query())
+	// 按 RoundTripper 约定不修改原请求，克隆后替换 Body
+	orig := req
+	req = orig.Clone(orig.Context())
+	var body []byte
+	if orig.Body != nil && orig.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(orig.Body)
+		_ = orig.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+		req.ContentLength = int64(len(body))
+	}
+	if err := addBodyParams(params, req.Header.Get("Content-Type"), body); err != nil {
+		return nil, err
+	}
+
+	signed, err := t.signer.SignParams(params)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	for _, k := range []string{ParamAppKey, ParamTimestamp, ParamNonce, ParamSignType, ParamSign} {
+		if v, ok := signed[k]; ok {
+			q.Set(k, v)
+		}
+	}
+	req.URL.RawQuery = q.Encode()
+	return t.base.RoundTrip(req)
+}
+
+// addBodyParams 表单请求合并表单字段，其它请求写入 body 摘要（覆盖 query 中的同名参数）
+func addBodyParams(params map[string]string, contentType string, body []byte) error {
+	if !isForm(contentType) {
+		params[ParamBodyDigest] = BodyDigest(body)
+		return nil
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range firstValues(form) {
+		params[k] = v
+	}
+	return nil
+}
+
+// firstValues 多值参数取第一个值
+func firstValues(v url.Values) map[string]string {
+	out := make(map[string]string, len(v))
+	for k, vs := range v {
+		if len(vs) > 0 {
+			out[k] = vs[0]
+		}
+	}
+	return out
+}
+
+// isForm 判断是否为表单请求
+func isForm(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(contentType), "application/x-www-form-urlencoded")
+}
@@ -0,0 +1,185 @@
+package apisign
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultMaxBodySize Middleware 默认读取的最大请求体字节数
+const DefaultMaxBodySize = 1 << 20
+
+// SecretFunc 根据 app_key 查询对应的密钥，未知应用返回 ErrUnknownApp
+type SecretFunc func(ctx context.Context, appKey string) (string, error)
+
+// Verifier 服务端签名校验器
+type Verifier struct {
+	Secrets SecretFunc    // 必填，app_key -> secret
+	MaxSkew time.Duration // 允许的时间偏差，默认 5 分钟
+	Nonces  NonceStore    // 可选，配置后校验 nonce 未被使用过（防重放）
+
+	// Algorithms 允许的签名算法，为空表示 MD5 与 HMAC-SHA256 均允许
+	Algorithms []Algorithm
+
+	// MaxBodySize Middleware 读取请求体的上限，超出返回 413；0 表示 DefaultMaxBodySize，负数表示不限制
+	MaxBodySize int64
+
+	// OnReject 可选，Middleware 拒绝请求时调用，可接入审计日志；未设置时以 Warn 级别写入 logger。
+	// 响应体只包含固定的错误文案，具体原因仅通过此回调（或日志）暴露
+	OnReject func(r *http.Request, status int, err error)
+
+	now func() time.Time
+}
+
+// Verify 校验参数的签名、时间戳与 nonce，成功时返回 app_key
+func (v *Verifier) Verify(ctx context.Context, params map[string]string) (string, error) {
+	appKey, ts, nonce, sign := params[ParamAppKey], params[ParamTimestamp], params[ParamNonce], params[ParamSign]
+	if appKey == "" || ts == "" || nonce == "" || sign == "" {
+		return "", ErrMissingParam
+	}
+
+	algo := Algorithm(params[ParamSignType])
+	if algo == "" {
+		algo = MD5
+	}
+	if !v.allowed(algo) {
+		return "", errors.New("apisign: sign_type not allowed")
+	}
+
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return "", ErrTimestampExpired
+	}
+	skew := v.MaxSkew
+	if skew <= 0 {
+		skew = 5 * time.Minute
+	}
+	now := time.Now
+	if v.now != nil {
+		now = v.now
+	}
+	if d := now().Sub(time.Unix(sec, 0)); d > skew || d < -skew {
+		return "", ErrTimestampExpired
+	}
+
+	secret, err := v.Secrets(ctx, appKey)
+	if err != nil {
+		return "", err
+	}
+	want, err := Sign(params, secret, algo)
+	if err != nil {
+		return "", err
+	}
+	if subtle.ConstantTimeCompare([]byte(want), []byte(sign)) != 1 {
+		return "", ErrSignatureMismatch
+	}
+
+	// 签名通过后再占用 nonce，避免伪造请求耗尽 nonce 空间；保留时间覆盖整个时间窗口
+	if v.Nonces != nil {
+		ok, err := v.Nonces.Use(ctx, appKey+":"+nonce, 2*skew)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", ErrNonceReused
+		}
+	}
+	return appKey, nil
+}
+
+// allowed 判断签名算法是否被允许
+func (v *Verifier) allowed(algo Algorithm) bool {
+	if algo != MD5 && algo != HMACSHA256 {
+		return false
+	}
+	if len(v.Algorithms) == 0 {
+		return true
+	}
+	for _, a := range v.Algorithms {
+		if a == algo {
+			return true
+		}
+	}
+	return false
+}
+
+// appKeyCtxKey context 中存放 app_key 的 key
+type appKeyCtxKey struct{}
+
+// AppKeyFromContext 获取中间件校验通过的 app_key
+func AppKeyFromContext(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(appKeyCtxKey{}).(string)
+	return v, ok
+}
+
+// Middleware 校验签名的 HTTP 中间件，参数取自 URL query 与表单或 body 摘要（与 Signer.Transport 对应）
+// 请求体超过 MaxBodySize 返回 413，无法解析返回 400，校验失败返回 401；通过后 app_key 写入 request context
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params, err := requestParams(w, r, v.maxBodySize())
+		if err != nil {
+			status := http.StatusBadRequest
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			v.reject(w, r, status, err)
+			return
+		}
+		appKey, err := v.Verify(r.Context(), params)
+		if err != nil {
+			v.reject(w, r, http.StatusUnauthorized, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), appKeyCtxKey{}, appKey)))
+	})
+}
+
+// maxBodySize 返回生效的请求体上限，<= 0 表示不限制
+func (v *Verifier) maxBodySize() int64 {
+	if v.MaxBodySize == 0 {
+		return DefaultMaxBodySize
+	}
+	return v.MaxBodySize
+}
+
+// reject 上报拒绝原因并返回固定文案，避免向调用方泄露校验细节（如 app_key 是否存在）
+func (v *Verifier) reject(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if v.OnReject != nil {
+		v.OnReject(r, status, err)
+	} else {
+		logger.Warn(r.Context(), "apisign: request rejected",
+			zap.Int("status", status), zap.String("path", r.URL.Path), zap.Error(err))
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+// requestParams 收集参与签名的参数（含表单字段或 body 摘要），读取 Body 后恢复以便后续 handler 使用；
+// limit > 0 时最多读取 limit 字节，超出返回 *http.MaxBytesError
+func requestParams(w http.ResponseWriter, r *http.Request, limit int64) (map[string]string, error) {
+	params := firstValues(r.URL.Query())
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		src := r.Body
+		if limit > 0 {
+			src = http.MaxBytesReader(w, r.Body, limit)
+		}
+		var err error
+		if body, err = io.ReadAll(src); err != nil {
+			return nil, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if err := addBodyParams(params, r.Header.Get("Content-Type"), body); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
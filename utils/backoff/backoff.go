@@ -0,0 +1,143 @@
+// Package backoff 重试退避策略：固定间隔、指数、去相关抖动（decorrelated jitter）与斐波那契，
+// 以及按策略逐次产出等待时间的迭代器；httpx 重试、mysqlx 分批查询、rediscluster 乐观锁等共用同一套实现
+// 使用示例：
+//
+//	it := backoff.Iter(backoff.FullJitter(backoff.Exponential(100*time.Millisecond, 5*time.Second)), backoff.WithMaxAttempts(5))
+//	for {
+//		if err = call(ctx); err == nil || !temporary(err) {
+//			break
+//		}
+//		if werr := it.Wait(ctx); werr != nil {
+//			break // 次数用尽或 ctx 结束
+//		}
+//	}
+package backoff
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrExhausted 已达到最大重试次数或最长重试时间
+var ErrExhausted = errors.New("backoff: retries exhausted")
+
+// Strategy 退避策略，Delay 返回第 attempt 次（从 0 开始）重试前的等待时间，prev 为上一次的等待时间（首次为 0）；
+// 实现应是无状态的，可被多个协程共享
+type Strategy interface {
+	Delay(attempt int, prev time.Duration) time.Duration
+}
+
+// StrategyFunc 将函数适配为 Strategy
+type StrategyFunc func(attempt int, prev time.Duration) time.Duration
+
+// Delay 实现 Strategy
+func (f StrategyFunc) Delay(attempt int, prev time.Duration) time.Duration { return f(attempt, prev) }
+
+// capDelay 限制上限，max <= 0 表示不限制；d 溢出为负数时返回上限
+func capDelay(d, max time.Duration) time.Duration {
+	if max <= 0 {
+		max = math.MaxInt64
+	}
+	if d <= 0 || d > max {
+		return max
+	}
+	return d
+}
+
+// Constant 固定等待 d
+func Constant(d time.Duration) Strategy {
+	return StrategyFunc(func(int, time.Duration) time.Duration { return d })
+}
+
+// Exponential 指数退避：base * 2^attempt，不超过 max（max <= 0 表示不限制）；base <= 0 时不等待
+func Exponential(base, max time.Duration) Strategy {
+	return StrategyFunc(func(attempt int, _ time.Duration) time.Duration {
+		if base <= 0 {
+			return 0
+		}
+		if attempt >= 63 {
+			return capDelay(-1, max)
+		}
+		return capDelay(base<<attempt, max)
+	})
+}
+
+// Fibonacci 斐波那契退避：base 依次乘以 1、1、2、3、5、8……，增长比指数退避平缓，不超过 max；base <= 0 时不等待
+func Fibonacci(base, max time.Duration) Strategy {
+	return StrategyFunc(func(attempt int, _ time.Duration) time.Duration {
+		if base <= 0 {
+			return 0
+		}
+		a, b := time.Duration(1), time.Duration(1)
+		for i := 0; i < attempt; i++ {
+			a, b = b, a+b
+			if b <= 0 || (max > 0 && a*base > max) {
+				return capDelay(-1, max)
+			}
+		}
+		return capDelay(a*base, max)
+	})
+}
+
+// DecorrelatedJitter 去相关抖动：在 [base, prev*3] 之间随机取值，不超过 max；
+// 与指数退避加抖动相比，竞争的客户端更快错开，总等待时间也更短
+func DecorrelatedJitter(base, max time.Duration) Strategy {
+	return StrategyFunc(func(_ int, prev time.Duration) time.Duration {
+		if prev < base {
+			prev = base
+		}
+		upper := prev * 3
+		if upper <= 0 || upper < prev {
+			upper = math.MaxInt64
+		}
+		return capDelay(base+randN(upper-base+1), max)
+	})
+}
+
+// FullJitter 在 (0, d] 之间随机取值，d 为 s 给出的等待时间；适合大量客户端同时重试的场景
+func FullJitter(s Strategy) Strategy {
+	return StrategyFunc(func(attempt int, prev time.Duration) time.Duration {
+		d := s.Delay(attempt, prev)
+		if d <= 0 {
+			return d
+		}
+		return randN(d) + 1
+	})
+}
+
+// EqualJitter 在 [d/2, d] 之间随机取值，保留至少一半的等待时间
+func EqualJitter(s Strategy) Strategy {
+	return StrategyFunc(func(attempt int, prev time.Duration) time.Duration {
+		d := s.Delay(attempt, prev)
+		if d <= 0 {
+			return d
+		}
+		return d/2 + randN(d-d/2+1)
+	})
+}
+
+// randN 返回 [0, n) 的随机时长，n <= 0 时返回 0
+func randN(n time.Duration) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	return rand.N(n)
+}
+
+// Sleep 等待 d，期间 ctx 结束时返回其错误
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
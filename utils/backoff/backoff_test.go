@@ -0,0 +1,84 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStrategies(t *testing.T) {
+	exp := Exponential(100*time.Millisecond, time.Second)
+	for attempt, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		if got := exp.Delay(attempt, 0); got != want*time.Millisecond {
+			t.Errorf("exponential(%d) = %v, want %v", attempt, got, want*time.Millisecond)
+		}
+	}
+	if got := exp.Delay(100, 0); got != time.Second {
+		t.Errorf("exponential overflow = %v", got)
+	}
+
+	fib := Fibonacci(time.Second, 10*time.Second)
+	for attempt, want := range []time.Duration{1, 1, 2, 3, 5, 8, 10, 10} {
+		if got := fib.Delay(attempt, 0); got != want*time.Second {
+			t.Errorf("fibonacci(%d) = %v, want %v", attempt, got, want*time.Second)
+		}
+	}
+	if got := Fibonacci(time.Second, 0).Delay(200, 0); got <= 0 {
+		t.Errorf("uncapped fibonacci overflow = %v", got)
+	}
+
+	if got := Exponential(0, time.Second).Delay(3, 0); got != 0 {
+		t.Errorf("zero base should not wait, got %v", got)
+	}
+	if got := Constant(time.Second).Delay(5, 0); got != time.Second {
+		t.Errorf("constant = %v", got)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	base := Constant(100 * time.Millisecond)
+	dj := DecorrelatedJitter(10*time.Millisecond, 50*time.Millisecond)
+	for i := 0; i < 1000; i++ {
+		if d := FullJitter(base).Delay(0, 0); d <= 0 || d > 100*time.Millisecond {
+			t.Fatalf("full jitter = %v", d)
+		}
+		if d := EqualJitter(base).Delay(0, 0); d < 50*time.Millisecond || d > 100*time.Millisecond {
+			t.Fatalf("equal jitter = %v", d)
+		}
+		if d := dj.Delay(0, 20*time.Millisecond); d < 10*time.Millisecond || d > 50*time.Millisecond {
+			t.Fatalf("decorrelated jitter = %v", d)
+		}
+	}
+}
+
+func TestIterator(t *testing.T) {
+	it := Iter(Exponential(time.Millisecond, 0), WithMaxAttempts(3))
+	var got []time.Duration
+	for {
+		d, ok := it.Next()
+		if !ok {
+			break
+		}
+		got = append(got, d)
+	}
+	if len(got) != 3 || got[2] != 4*time.Millisecond || it.Attempt() != 3 {
+		t.Errorf("delays = %v, attempt = %d", got, it.Attempt())
+	}
+	if err := it.Wait(context.Background()); err != ErrExhausted {
+		t.Errorf("Wait after exhaustion = %v", err)
+	}
+	it.Reset()
+	if d, ok := it.Next(); !ok || d != time.Millisecond {
+		t.Errorf("after Reset = %v %v", d, ok)
+	}
+
+	it = Iter(Constant(time.Hour), WithMaxElapsed(time.Minute))
+	if _, ok := it.Next(); ok {
+		t.Error("delay beyond max elapsed should stop")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Iter(Constant(time.Hour)).Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait on canceled ctx = %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package backoff
+
+import (
+	"context"
+	"time"
+)
+
+// Option 迭代器选项
+type Option func(*Iterator)
+
+// WithMaxAttempts 最多产出 n 次等待时间（即最多重试 n 次），0 表示不限制
+func WithMaxAttempts(n int) Option {
+	return func(it *Iterator) { it.maxAttempts = n }
+}
+
+// WithMaxElapsed 从创建（或 Reset）起超过 d 后不再产出等待时间，0 表示不限制
+func WithMaxElapsed(d time.Duration) Option {
+	return func(it *Iterator) { it.maxElapsed = d }
+}
+
+// Iterator 按策略逐次产出等待时间，记录已重试次数与上一次的等待时间；不是并发安全的，每次重试流程各用一个
+type Iterator struct {
+	s           Strategy
+	maxAttempts int
+	maxElapsed  time.Duration
+
+	attempt int
+	prev    time.Duration
+	start   time.Time
+}
+
+// Iter 创建迭代器
+func Iter(s Strategy, opts ...Option) *Iterator {
+	it := &Iterator{s: s, start: time.Now()}
+	for _, opt := range opts {
+		opt(it)
+	}
+	return it
+}
+
+// Next 返回下一次重试前的等待时间，次数或时间用尽时 ok 为 false
+func (it *Iterator) Next() (d time.Duration, ok bool) {
+	if it.maxAttempts > 0 && it.attempt >= it.maxAttempts {
+		return 0, false
+	}
+	d = it.s.Delay(it.attempt, it.prev)
+	if it.maxElapsed > 0 && time.Since(it.start)+d > it.maxElapsed {
+		return 0, false
+	}
+	it.attempt++
+	it.prev = d
+	return d, true
+}
+
+// Wait 等待下一次重试的时间，用尽时返回 ErrExhausted，ctx 结束时返回其错误
+func (it *Iterator) Wait(ctx context.Context) error {
+	d, ok := it.Next()
+	if !ok {
+		return ErrExhausted
+	}
+	return Sleep(ctx, d)
+}
+
+// Attempt 已产出的等待次数
+func (it *Iterator) Attempt() int { return it.attempt }
+
+// Reset 重置次数与计时，用于连接恢复后重新开始退避（如 Redis 断线重连成功后）
+func (it *Iterator) Reset() {
+	it.attempt, it.prev, it.start = 0, 0, time.Now()
+}
@@ -0,0 +1,214 @@
+// Package captcha 图形验证码与算术验证码：生成 PNG 图片，答案保存在 Store（进程内或 Redis）中，
+// 带过期时间且只能校验一次，无论校验成功与否答案都会被删除，防止暴力重试
+//
+//	m := captcha.New(captcha.WithStore(captcha.NewRedisStore(rdb, "captcha:")))
+//	c, err := m.Generate(ctx)        // 返回 c.ID 与 c.DataURI() 给前端
+//	ok, err := m.Verify(ctx, id, in) // 登录、发短信等接口中校验
+package captcha
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrEmptyID 校验时验证码 ID 为空
+var ErrEmptyID = errors.New("captcha: empty id")
+
+// Generator 生成验证码内容：content 绘制在图片上，answer 为期望的输入
+type Generator interface {
+	Generate() (content, answer string, err error)
+}
+
+// digitChars 数字字符集
+const digitChars = "0123456789"
+
+// alnumChars 字母数字字符集，去掉了容易混淆的 0/O、1/I/L
+const alnumChars = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// charsGenerator 从字符集中随机取字符
+type charsGenerator struct {
+	chars  string
+	length int
+}
+
+// Digits 纯数字验证码，length <= 0 时 panic
+func Digits(length int) Generator {
+	return newCharsGenerator(digitChars, length)
+}
+
+// Alphanumeric 字母数字验证码，校验时不区分大小写，length <= 0 时 panic
+func Alphanumeric(length int) Generator {
+	return newCharsGenerator(alnumChars, length)
+}
+
+// newCharsGenerator 创建字符验证码生成器，长度为 0 的验证码任何输入都能通过，属于配置错误
+func newCharsGenerator(chars string, length int) *charsGenerator {
+	if length <= 0 {
+		panic(fmt.Sprintf("captcha: invalid length %d", length))
+	}
+	return &charsGenerator{chars: chars, length: length}
+}
+
+// Generate 实现 Generator
+func (g *charsGenerator) Generate() (string, string, error) {
+	b := make([]byte, g.length)
+	for i := range b {
+		n, err := randInt(len(g.chars))
+		if err != nil {
+			return "", "", err
+		}
+		b[i] = g.chars[n]
+	}
+	return string(b), string(b), nil
+}
+
+// mathGenerator 算术验证码
+type mathGenerator struct{}
+
+// Math 算术验证码，如 “7 + 3 = ?”，答案为计算结果；减法保证结果非负
+func Math() Generator {
+	return mathGenerator{}
+}
+
+// Generate 实现 Generator
+func (mathGenerator) Generate() (string, string, error) {
+	nums := make([]int, 3)
+	for i, max := range []int{3, 10, 10} {
+		n, err := randInt(max)
+		if err != nil {
+			return "", "", err
+		}
+		nums[i] = n
+	}
+	a, b := nums[1], nums[2]
+	switch nums[0] {
+	case 0:
+		return fmt.Sprintf("%d+%d=?", a, b), strconv.Itoa(a + b), nil
+	case 1:
+		if a < b {
+			a, b = b, a
+		}
+		return fmt.Sprintf("%d-%d=?", a, b), strconv.Itoa(a - b), nil
+	default:
+		return fmt.Sprintf("%d×%d=?", a, b), strconv.Itoa(a * b), nil
+	}
+}
+
+// Captcha 生成的验证码
+type Captcha struct {
+	ID        string
+	Image     []byte // PNG
+	ExpiresAt time.Time
+}
+
+// DataURI 返回可直接用于 <img src> 的 data URI
+func (c *Captcha) DataURI() string {
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(c.Image)
+}
+
+// Manager 验证码管理器
+type Manager struct {
+	store     Store
+	generator Generator
+	ttl       time.Duration
+	width     int
+	height    int
+}
+
+// Option Manager 选项
+type Option func(*Manager)
+
+// WithStore 设置答案存储，默认进程内存储，多实例部署时应使用 RedisStore
+func WithStore(s Store) Option {
+	return func(m *Manager) { m.store = s }
+}
+
+// WithGenerator 设置内容生成器，默认 Digits(4)
+func WithGenerator(g Generator) Option {
+	return func(m *Manager) { m.generator = g }
+}
+
+// WithTTL 设置有效期，默认 5 分钟
+func WithTTL(ttl time.Duration) Option {
+	return func(m *Manager) { m.ttl = ttl }
+}
+
+// WithSize 设置图片尺寸，默认 240x80
+func WithSize(width, height int) Option {
+	return func(m *Manager) { m.width, m.height = width, height }
+}
+
+// New 创建验证码管理器
+func New(opts ...Option) *Manager {
+	m := &Manager{generator: Digits(4), ttl: 5 * time.Minute, width: 240, height: 80}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.store == nil {
+		m.store = NewMemoryStore()
+	}
+	return m
+}
+
+// Generate 生成验证码并保存答案
+func (m *Manager) Generate(ctx context.Context) (*Captcha, error) {
+	content, answer, err := m.generator.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("captcha: %w", err)
+	}
+	img, err := Render(content, m.width, m.height)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.store.Set(ctx, id, answer, m.ttl); err != nil {
+		return nil, fmt.Errorf("captcha: store: %w", err)
+	}
+	return &Captcha{ID: id, Image: img, ExpiresAt: time.Now().Add(m.ttl)}, nil
+}
+
+// Verify 校验答案（忽略首尾空白与大小写），验证码只能校验一次；不存在或已过期返回 false
+func (m *Manager) Verify(ctx context.Context, id, answer string) (bool, error) {
+	if id == "" {
+		return false, ErrEmptyID
+	}
+	want, ok, err := m.store.Take(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("captcha: store: %w", err)
+	}
+	if !ok {
+		return false, nil
+	}
+	got := strings.ToUpper(strings.TrimSpace(answer))
+	return subtle.ConstantTimeCompare([]byte(got), []byte(strings.ToUpper(want))) == 1, nil
+}
+
+// newID 生成随机验证码 ID
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("captcha: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// randInt 返回 [0, n) 的密码学安全随机数
+func randInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
@@ -0,0 +1,149 @@
+package captcha
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedGenerator 固定内容的生成器，便于测试
+type fixedGenerator struct{ content, answer string }
+
+func (g fixedGenerator) Generate() (string, string, error) { return g.content, g.answer, nil }
+
+func TestGenerateAndVerify(t *testing.T) {
+	ctx := context.Background()
+	m := New(WithGenerator(fixedGenerator{"ab12", "AB12"}), WithSize(160, 60))
+
+	c, err := m.Generate(ctx)
+	require.NoError(t, err)
+	assert.Len(t, c.ID, 32)
+	assert.True(t, strings.HasPrefix(c.DataURI(), "data:image/png;base64,"))
+	img, err := png.Decode(bytes.NewReader(c.Image))
+	require.NoError(t, err)
+	assert.Equal(t, 160, img.Bounds().Dx())
+	assert.Equal(t, 60, img.Bounds().Dy())
+
+	ok, err := m.Verify(ctx, c.ID, " ab12 ")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// 只能校验一次
+	ok, err = m.Verify(ctx, c.ID, "ab12")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestVerifyWrongAnswerConsumes(t *testing.T) {
+	ctx := context.Background()
+	m := New(WithGenerator(fixedGenerator{"1234", "1234"}))
+	c, err := m.Generate(ctx)
+	require.NoError(t, err)
+
+	ok, err := m.Verify(ctx, c.ID, "0000")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = m.Verify(ctx, c.ID, "1234")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = m.Verify(ctx, "", "1234")
+	assert.ErrorIs(t, err, ErrEmptyID)
+}
+
+func TestVerifyExpired(t *testing.T) {
+	ctx := context.Background()
+	m := New(WithGenerator(fixedGenerator{"1234", "1234"}), WithTTL(10*time.Millisecond))
+	c, err := m.Generate(ctx)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	ok, err := m.Verify(ctx, c.ID, "1234")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestGenerators(t *testing.T) {
+	assert.Panics(t, func() { Digits(0) })
+	assert.Panics(t, func() { Alphanumeric(-1) })
+
+	content, answer, err := Digits(6).Generate()
+	require.NoError(t, err)
+	assert.Len(t, content, 6)
+	assert.Equal(t, content, answer)
+	_, err = strconv.Atoi(content)
+	assert.NoError(t, err)
+
+	content, _, err = Alphanumeric(5).Generate()
+	require.NoError(t, err)
+	assert.Len(t, content, 5)
+	assert.NotContains(t, content, "0")
+	assert.NotContains(t, content, "O")
+
+	for i := 0; i < 50; i++ {
+		content, answer, err := Math().Generate()
+		require.NoError(t, err)
+		require.True(t, strings.HasSuffix(content, "=?"))
+		expr := strings.TrimSuffix(content, "=?")
+		var a, b int
+		var want int
+		switch {
+		case strings.Contains(expr, "+"):
+			parts := strings.Split(expr, "+")
+			a, _ = strconv.Atoi(parts[0])
+			b, _ = strconv.Atoi(parts[1])
+			want = a + b
+		case strings.Contains(expr, "-"):
+			parts := strings.Split(expr, "-")
+			a, _ = strconv.Atoi(parts[0])
+			b, _ = strconv.Atoi(parts[1])
+			want = a - b
+			assert.GreaterOrEqual(t, want, 0)
+		default:
+			parts := strings.Split(expr, "×")
+			a, _ = strconv.Atoi(parts[0])
+			b, _ = strconv.Atoi(parts[1])
+			want = a * b
+		}
+		assert.Equal(t, strconv.Itoa(want), answer, content)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	require.NoError(t, s.Set(ctx, "id", "42", time.Minute))
+	answer, ok, err := s.Take(ctx, "id")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "42", answer)
+	_, ok, _ = s.Take(ctx, "id")
+	assert.False(t, ok)
+
+	// 达到上限时淘汰最早写入的记录，覆盖写入不占用额外名额
+	s = NewMemoryStore(WithMaxEntries(2))
+	require.NoError(t, s.Set(ctx, "a", "1", time.Minute))
+	require.NoError(t, s.Set(ctx, "b", "2", time.Minute))
+	require.NoError(t, s.Set(ctx, "a", "3", time.Minute))
+	require.NoError(t, s.Set(ctx, "c", "4", time.Minute))
+	_, ok, _ = s.Take(ctx, "b")
+	assert.False(t, ok)
+	for id, want := range map[string]string{"a": "3", "c": "4"} {
+		answer, ok, _ := s.Take(ctx, id)
+		assert.True(t, ok, id)
+		assert.Equal(t, want, answer, id)
+	}
+}
+
+func TestRenderInvalid(t *testing.T) {
+	_, err := Render("", 100, 40)
+	assert.Error(t, err)
+	_, err = Render("12", 0, 40)
+	assert.Error(t, err)
+}
@@ -0,0 +1,177 @@
+package captcha
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"math/rand/v2"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	fontOnce sync.Once
+	fontData *opentype.Font
+	fontErr  error
+)
+
+// loadFont 解析内置的 Go Mono Bold 字体
+func loadFont() (*opentype.Font, error) {
+	fontOnce.Do(func() {
+		fontData, fontErr = opentype.Parse(gomonobold.TTF)
+	})
+	return fontData, fontErr
+}
+
+// Render 将文本绘制为带干扰的 PNG 图片：字符随机旋转、偏移和着色，叠加干扰曲线、噪点与整体波形扭曲
+func Render(text string, width, height int) ([]byte, error) {
+	fnt, err := loadFont()
+	if err != nil {
+		return nil, fmt.Errorf("captcha: %w", err)
+	}
+	runes := []rune(text)
+	if len(runes) == 0 || width <= 0 || height <= 0 {
+		return nil, errors.New("captcha: invalid render arguments")
+	}
+	// 字号同时受高度与每个字符可用宽度限制
+	size := math.Min(float64(height)*0.7, float64(width)/float64(len(runes))*1.3)
+	face, err := opentype.NewFace(fnt, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingNone})
+	if err != nil {
+		return nil, fmt.Errorf("captcha: %w", err)
+	}
+	defer face.Close()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	bg := color.NRGBA{R: uint8(225 + rand.IntN(30)), G: uint8(225 + rand.IntN(30)), B: uint8(225 + rand.IntN(30)), A: 255}
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = bg.R, bg.G, bg.B, bg.A
+	}
+
+	drawNoiseCurves(img, 3)
+	slot := float64(width) / float64(len(runes))
+	for i, r := range runes {
+		cx := slot*(float64(i)+0.5) + (rand.Float64()-0.5)*slot*0.2
+		cy := float64(height)/2 + (rand.Float64()-0.5)*float64(height)*0.15
+		angle := (rand.Float64() - 0.5) * math.Pi / 3.5
+		drawGlyph(img, face, r, cx, cy, angle, randomInk())
+	}
+	drawNoiseCurves(img, 2)
+	drawNoiseDots(img, width*height/40)
+	img = wave(img, float64(height)/12, float64(width)/(1.5+rand.Float64()))
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("captcha: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// randomInk 随机的深色
+func randomInk() color.NRGBA {
+	return color.NRGBA{R: uint8(rand.IntN(130)), G: uint8(rand.IntN(130)), B: uint8(rand.IntN(130)), A: 255}
+}
+
+// drawGlyph 先将字符绘制到透明图块，再以 (cx, cy) 为中心旋转 angle 后混合到目标图片
+func drawGlyph(dst *image.NRGBA, face font.Face, r rune, cx, cy, angle float64, ink color.NRGBA) {
+	m := face.Metrics()
+	adv, _ := face.GlyphAdvance(r)
+	w, h := adv.Ceil(), (m.Ascent + m.Descent).Ceil()
+	if w == 0 || h == 0 {
+		return
+	}
+	tile := image.NewAlpha(image.Rect(0, 0, w, h))
+	d := font.Drawer{Dst: tile, Src: image.Opaque, Face: face, Dot: fixed.Point26_6{Y: m.Ascent}}
+	d.DrawString(string(r))
+
+	sin, cos := math.Sincos(angle)
+	tcx, tcy := float64(w)/2, float64(h)/2
+	radius := math.Hypot(tcx, tcy)
+	b := dst.Bounds()
+	for y := int(cy - radius); y <= int(cy+radius); y++ {
+		for x := int(cx - radius); x <= int(cx+radius); x++ {
+			if !image.Pt(x, y).In(b) {
+				continue
+			}
+			// 目标像素反向旋转到图块坐标
+			dx, dy := float64(x)-cx, float64(y)-cy
+			sx, sy := int(cos*dx+sin*dy+tcx), int(-sin*dx+cos*dy+tcy)
+			if sx < 0 || sy < 0 || sx >= w || sy >= h {
+				continue
+			}
+			if a := tile.AlphaAt(sx, sy).A; a > 0 {
+				blend(dst, x, y, ink, a)
+			}
+		}
+	}
+}
+
+// drawNoiseCurves 绘制随机正弦干扰曲线
+func drawNoiseCurves(img *image.NRGBA, n int) {
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+	for i := 0; i < n; i++ {
+		ink := randomInk()
+		ink.A = 160
+		amp := h * (0.1 + rand.Float64()*0.2)
+		period := w * (0.5 + rand.Float64())
+		phase := rand.Float64() * 2 * math.Pi
+		base := h * (0.2 + rand.Float64()*0.6)
+		for x := 0; x < b.Dx(); x++ {
+			y := int(base + amp*math.Sin(2*math.Pi*float64(x)/period+phase))
+			for t := 0; t < 2; t++ {
+				if image.Pt(x, y+t).In(b) {
+					blend(img, x, y+t, ink, ink.A)
+				}
+			}
+		}
+	}
+}
+
+// drawNoiseDots 绘制随机噪点
+func drawNoiseDots(img *image.NRGBA, n int) {
+	b := img.Bounds()
+	for i := 0; i < n; i++ {
+		ink := randomInk()
+		blend(img, rand.IntN(b.Dx()), rand.IntN(b.Dy()), ink, 200)
+	}
+}
+
+// wave 对图片做水平方向的正弦扭曲
+func wave(src *image.NRGBA, amp, period float64) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(b)
+	phase := rand.Float64() * 2 * math.Pi
+	for y := 0; y < b.Dy(); y++ {
+		shift := int(amp * math.Sin(2*math.Pi*float64(y)/period+phase))
+		for x := 0; x < b.Dx(); x++ {
+			sx := x + shift
+			if sx < 0 {
+				sx = 0
+			} else if sx >= b.Dx() {
+				sx = b.Dx() - 1
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, y):src.PixOffset(sx, y)+4])
+		}
+	}
+	return dst
+}
+
+// blend 按透明度 a 将颜色混合到像素上
+func blend(img *image.NRGBA, x, y int, c color.NRGBA, a uint8) {
+	i := img.PixOffset(x, y)
+	alpha := uint32(a)
+	mix := func(dst, src uint8) uint8 {
+		return uint8((uint32(src)*alpha + uint32(dst)*(255-alpha)) / 255)
+	}
+	img.Pix[i] = mix(img.Pix[i], c.R)
+	img.Pix[i+1] = mix(img.Pix[i+1], c.G)
+	img.Pix[i+2] = mix(img.Pix[i+2], c.B)
+}
@@ -0,0 +1,157 @@
+package captcha
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store 保存验证码答案，Take 取出并删除答案，不存在或已过期时 ok 为 false
+type Store interface {
+	Set(ctx context.Context, id, answer string, ttl time.Duration) error
+	Take(ctx context.Context, id string) (answer string, ok bool, err error)
+}
+
+// RedisStore 基于 Redis 的答案存储，多实例部署时共享；Take 使用 GETDEL 保证只能取出一次
+type RedisStore struct {
+	c      redis.Cmdable
+	prefix string
+}
+
+// NewRedisStore 创建 Redis 答案存储，c 可以是 rediscluster 创建的集群客户端
+func NewRedisStore(c redis.Cmdable, prefix string) *RedisStore {
+	return &RedisStore{c: c, prefix: prefix}
+}
+
+// Set 实现 Store
+func (s *RedisStore) Set(ctx context.Context, id, answer string, ttl time.Duration) error {
+	return s.c.Set(ctx, s.prefix+id, answer, ttl).Err()
+}
+
+// Take 实现 Store
+func (s *RedisStore) Take(ctx context.Context, id string) (string, bool, error) {
+	answer, err := s.c.GetDel(ctx, s.prefix+id).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return answer, true, nil
+}
+
+// defaultMaxEntries MemoryStore 默认的最大记录数
+const defaultMaxEntries = 100000
+
+type memoryEntry struct {
+	answer  string
+	expires time.Time
+	seq     uint64 // 写入序号，与 order 中的记录对应
+}
+
+// queued 按写入顺序排队的记录，seq 与当前记录不一致时说明已被删除或覆盖
+type queued struct {
+	id  string
+	seq uint64
+}
+
+// MemoryStore 进程内答案存储，适用于单实例或测试；记录数达到上限时淘汰最早写入的记录，
+// 避免大量生成验证码而不校验时内存无限增长
+type MemoryStore struct {
+	mu         sync.Mutex
+	entries    map[string]memoryEntry
+	order      []queued // 写入顺序，淘汰时从头部取
+	seq        uint64
+	maxEntries int
+	lastSweep  time.Time
+}
+
+// MemoryStoreOption MemoryStore 的函数式选项
+type MemoryStoreOption func(*MemoryStore)
+
+// WithMaxEntries 设置最大记录数，默认 100000，<= 0 时使用默认值
+func WithMaxEntries(n int) MemoryStoreOption {
+	return func(s *MemoryStore) { s.maxEntries = n }
+}
+
+// NewMemoryStore 创建进程内答案存储
+func NewMemoryStore(opts ...MemoryStoreOption) *MemoryStore {
+	s := &MemoryStore{entries: make(map[string]memoryEntry)}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.maxEntries <= 0 {
+		s.maxEntries = defaultMaxEntries
+	}
+	return s
+}
+
+// Set 实现 Store，每分钟最多清理一次过期记录，达到上限时淘汰最早写入的记录
+func (s *MemoryStore) Set(_ context.Context, id, answer string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) > time.Minute {
+		s.lastSweep = now
+		s.sweep(now)
+	}
+	if _, ok := s.entries[id]; !ok {
+		for len(s.entries) >= s.maxEntries {
+			if !s.evictOldest() {
+				break
+			}
+		}
+	}
+	s.seq++
+	s.entries[id] = memoryEntry{answer: answer, expires: now.Add(ttl), seq: s.seq}
+	s.order = append(s.order, queued{id: id, seq: s.seq})
+	return nil
+}
+
+// sweep 删除过期记录，并丢弃写入队列中已失效的记录
+func (s *MemoryStore) sweep(now time.Time) {
+	for k, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+	live := s.order[:0]
+	for _, q := range s.order {
+		if e, ok := s.entries[q.id]; ok && e.seq == q.seq {
+			live = append(live, q)
+		}
+	}
+	clear(s.order[len(live):])
+	s.order = live
+}
+
+// evictOldest 删除最早写入且仍有效的记录，队列为空时返回 false
+func (s *MemoryStore) evictOldest() bool {
+	for len(s.order) > 0 {
+		q := s.order[0]
+		s.order = s.order[1:]
+		if e, ok := s.entries[q.id]; ok && e.seq == q.seq {
+			delete(s.entries, q.id)
+			return true
+		}
+	}
+	return false
+}
+
+// Take 实现 Store
+func (s *MemoryStore) Take(_ context.Context, id string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok {
+		return "", false, nil
+	}
+	delete(s.entries, id)
+	if time.Now().After(e.expires) {
+		return "", false, nil
+	}
+	return e.answer, true, nil
+}
@@ -0,0 +1,73 @@
+package cnvalid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMobile(t *testing.T) {
+	cases := map[string]Carrier{
+		"13800138000":       CarrierMobile,
+		"+86 186-0000-0000": CarrierUnicom,
+		"8618900000000":     CarrierTelecom,
+		"19200000000":       CarrierBroadnet,
+		"17050000000":       CarrierMobile,
+		"13490000000":       CarrierSatellite,
+	}
+	for in, want := range cases {
+		m, err := ParseMobile(in)
+		if assert.NoError(t, err, in) {
+			assert.Equal(t, want, m.Carrier, in)
+		}
+	}
+	for _, in := range []string{"12000000000", "1380013800", "1380013800a", "10000000000"} {
+		assert.False(t, IsMobile(in), in)
+	}
+	m, _ := ParseMobile("17050000000")
+	assert.True(t, m.Virtual)
+	assert.Equal(t, "138****8000", MaskMobile("13800138000"))
+}
+
+func TestParseIDCard(t *testing.T) {
+	c, err := ParseIDCard("11010519491231002x")
+	assert.NoError(t, err)
+	assert.Equal(t, "11010519491231002X", c.Number)
+	assert.Equal(t, "110105", c.RegionCode)
+	assert.Equal(t, "北京", c.Province)
+	assert.Equal(t, Female, c.Gender)
+	assert.Equal(t, 1949, c.Birthday.Year())
+	assert.Equal(t, 74, c.Age(time.Date(2024, 12, 30, 0, 0, 0, 0, time.Local)))
+	assert.Equal(t, 75, c.Age(time.Date(2024, 12, 31, 0, 0, 0, 0, time.Local)))
+
+	for _, in := range []string{
+		"110105194912310021", // 校验位错误
+		"11010519491332002X", // 日期非法
+		"99010519491231002X", // 省份非法
+		"11010519491231002",  // 长度不足
+	} {
+		assert.False(t, IsIDCard(in), in)
+	}
+	assert.Equal(t, "110105********002X", MaskIDCard("11010519491231002X"))
+}
+
+func TestUSCCPlateEmail(t *testing.T) {
+	assert.True(t, IsUSCC("91350100M000100Y43"))
+	assert.False(t, IsUSCC("91350100M000100Y44"))
+	assert.False(t, IsUSCC("91350100M000100Y4"))
+
+	for _, p := range []string{"京A12345", "粤B1234学", "沪AD12345", "浙A12345D"} {
+		assert.True(t, IsLicensePlate(p), p)
+	}
+	for _, p := range []string{"京I12345", "A12345", "京A1234", "京AO1234"} {
+		assert.False(t, IsLicensePlate(p), p)
+	}
+	assert.True(t, IsNewEnergyPlate("沪AD12345"))
+	assert.False(t, IsNewEnergyPlate("京A12345"))
+
+	assert.True(t, IsEmail("dev.team+go@example.com.cn"))
+	for _, e := range []string{"a@localhost", "no-at.example.com", "a@b..com", "Bob <bob@example.com>"} {
+		assert.False(t, IsEmail(e), e)
+	}
+}
@@ -0,0 +1,113 @@
+package cnvalid
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidIDCard 身份证号错误
+var ErrInvalidIDCard = errors.New("cnvalid: invalid id card number")
+
+// Gender 性别
+type Gender string
+
+const (
+	Male   Gender = "男"
+	Female Gender = "女"
+)
+
+// IDCard 18 位居民身份证解析结果
+type IDCard struct {
+	Number       string    // 规范化后的号码（末位 x 转为大写）
+	RegionCode   string    // 6 位行政区划代码（签发时的户籍地）
+	ProvinceCode string    // 2 位省级代码
+	Province     string    // 省级行政区名称，未知代码时为空
+	Birthday     time.Time // 出生日期（本地时区零点）
+	Gender       Gender    // 性别，由第 17 位奇偶决定
+}
+
+// Age 计算在 at 时刻的周岁年龄
+func (c *IDCard) Age(at time.Time) int {
+	age := at.Year() - c.Birthday.Year()
+	if at.Month() < c.Birthday.Month() || (at.Month() == c.Birthday.Month() && at.Day() < c.Birthday.Day()) {
+		age--
+	}
+	return age
+}
+
+// idWeights 第 1~17 位加权因子
+var idWeights = [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+// idCheckCodes 加权和 mod 11 对应的校验码
+const idCheckCodes = "10X98765432"
+
+// provinces 省级行政区代码
+var provinces = map[string]string{
+	"11": "北京", "12": "天津", "13": "河北", "14": "山西", "15": "内蒙古",
+	"21": "辽宁", "22": "吉林", "23": "黑龙江",
+	"31": "上海", "32": "江苏", "33": "浙江", "34": "安徽", "35": "福建", "36": "江西", "37": "山东",
+	"41": "河南", "42": "湖北", "43": "湖南", "44": "广东", "45": "广西", "46": "海南",
+	"50": "重庆", "51": "四川", "52": "贵州", "53": "云南", "54": "西藏",
+	"61": "陕西", "62": "甘肃", "63": "青海", "64": "宁夏", "65": "新疆",
+	"71": "台湾", "81": "香港", "82": "澳门",
+	"83": "台湾", // 港澳台居民居住证
+}
+
+// IsIDCard 判断是否为有效的 18 位身份证号
+func IsIDCard(s string) bool {
+	_, err := ParseIDCard(s)
+	return err == nil
+}
+
+// ParseIDCard 校验并解析 18 位身份证号：格式、省级代码、出生日期（不晚于今天）与校验位
+func ParseIDCard(s string) (*IDCard, error) {
+	n := strings.ToUpper(strings.TrimSpace(s))
+	if len(n) != 18 || !allDigits(n[:17]) {
+		return nil, ErrInvalidIDCard
+	}
+	if IDCardCheckCode(n[:17]) != n[17] {
+		return nil, ErrInvalidIDCard
+	}
+	province, ok := provinces[n[:2]]
+	if !ok {
+		return nil, ErrInvalidIDCard
+	}
+	birthday, err := time.ParseInLocation("20060102", n[6:14], time.Local)
+	if err != nil || birthday.After(time.Now()) || birthday.Year() < 1900 {
+		return nil, ErrInvalidIDCard
+	}
+
+	gender := Female
+	if (n[16]-'0')%2 == 1 {
+		gender = Male
+	}
+	return &IDCard{
+		Number:       n,
+		RegionCode:   n[:6],
+		ProvinceCode: n[:2],
+		Province:     province,
+		Birthday:     birthday,
+		Gender:       gender,
+	}, nil
+}
+
+// IDCardCheckCode 根据前 17 位计算校验码（GB 11643-1999），输入非法时返回 0
+func IDCardCheckCode(first17 string) byte {
+	if len(first17) != 17 || !allDigits(first17) {
+		return 0
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		sum += int(first17[i]-'0') * idWeights[i]
+	}
+	return idCheckCodes[sum%11]
+}
+
+// MaskIDCard 身份证号脱敏，保留前六后四位
+func MaskIDCard(s string) string {
+	if len(s) != 18 {
+		return s
+	}
+	return s[:6] + "********" + s[14:]
+}
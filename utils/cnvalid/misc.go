@@ -0,0 +1,64 @@
+package cnvalid
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// usccChars 统一社会信用代码字符集（不含 I、O、Z、S、V）
+const usccChars = "0123456789ABCDEFGHJKLMNPQRTUWXY"
+
+// usccWeights 第 1~17 位加权因子
+var usccWeights = [17]int{1, 3, 9, 27, 19, 26, 16, 17, 20, 29, 25, 13, 8, 24, 10, 30, 28}
+
+// IsUSCC 校验 18 位统一社会信用代码（GB 32100-2015），含校验位
+func IsUSCC(s string) bool {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) != 18 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		v := strings.IndexByte(usccChars, s[i])
+		if v < 0 {
+			return false
+		}
+		sum += v * usccWeights[i]
+	}
+	check := (31 - sum%31) % 31
+	return s[17] == usccChars[check]
+}
+
+// 车牌号规则：省份简称 + 发牌机关字母 + 序号，字母不含 I、O
+const plateProvinces = "京津沪渝冀豫云辽黑湘皖鲁新苏浙赣鄂桂甘晋蒙陕吉闽贵粤青藏川宁琼"
+
+var (
+	// 普通车牌：5 位序号，末位可为 挂/学/警/港/澳
+	regularPlate = regexp.MustCompile(`^[` + plateProvinces + `][A-HJ-NP-Z][A-HJ-NP-Z0-9]{4}[A-HJ-NP-Z0-9挂学警港澳]$`)
+	// 新能源车牌：6 位序号，小型车首位为能源类型字母，大型车末位为能源类型字母
+	newEnergyPlate = regexp.MustCompile(`^[` + plateProvinces + `][A-HJ-NP-Z]([A-HJ-K][A-HJ-NP-Z0-9][0-9]{4}|[0-9]{5}[A-HJ-K])$`)
+)
+
+// IsLicensePlate 判断是否为有效的车牌号（普通或新能源）
+func IsLicensePlate(s string) bool {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	return regularPlate.MatchString(s) || newEnergyPlate.MatchString(s)
+}
+
+// IsNewEnergyPlate 判断是否为新能源车牌
+func IsNewEnergyPlate(s string) bool {
+	return newEnergyPlate.MatchString(strings.ToUpper(strings.TrimSpace(s)))
+}
+
+// emailPattern 常见邮箱格式（比 RFC 5322 更严格，拒绝显示名、引号等少见写法）
+var emailPattern = regexp.MustCompile(`^[A-Za-z0-9._%+\-]+@[A-Za-z0-9](?:[A-Za-z0-9\-]*[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9\-]*[A-Za-z0-9])?)+$`)
+
+// IsEmail 判断是否为有效邮箱地址，要求域名包含点号（拒绝 user@localhost）
+func IsEmail(s string) bool {
+	if len(s) > 254 || !emailPattern.MatchString(s) {
+		return false
+	}
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
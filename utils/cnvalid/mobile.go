@@ -0,0 +1,126 @@
+// Package cnvalid 中国大陆常用证件与号码的校验和解析：手机号（含运营商识别）、
+// 18 位身份证、统一社会信用代码、车牌号与邮箱
+package cnvalid
+
+import (
+	"errors"
+	"strings"
+)
+
+// Carrier 运营商
+type Carrier string
+
+const (
+	CarrierMobile    Carrier = "中国移动"
+	CarrierUnicom    Carrier = "中国联通"
+	CarrierTelecom   Carrier = "中国电信"
+	CarrierBroadnet  Carrier = "中国广电"
+	CarrierSatellite Carrier = "卫星通信"
+	CarrierUnknown   Carrier = ""
+)
+
+// ErrInvalidMobile 手机号格式错误
+var ErrInvalidMobile = errors.New("cnvalid: invalid mobile number")
+
+// Mobile 手机号解析结果
+type Mobile struct {
+	Number  string  // 规范化后的 11 位号码
+	Carrier Carrier // 运营商
+	Virtual bool    // 是否为虚拟运营商号段（170/171/162/165/167）
+}
+
+// carrierPrefixes 三位号段 -> 运营商
+var carrierPrefixes = map[string]Carrier{
+	// 中国移动
+	"134": CarrierMobile, "135": CarrierMobile, "136": CarrierMobile, "137": CarrierMobile,
+	"138": CarrierMobile, "139": CarrierMobile, "147": CarrierMobile, "148": CarrierMobile,
+	"150": CarrierMobile, "151": CarrierMobile, "152": CarrierMobile, "157": CarrierMobile,
+	"158": CarrierMobile, "159": CarrierMobile, "172": CarrierMobile, "178": CarrierMobile,
+	"182": CarrierMobile, "183": CarrierMobile, "184": CarrierMobile, "187": CarrierMobile,
+	"188": CarrierMobile, "195": CarrierMobile, "197": CarrierMobile, "198": CarrierMobile,
+	"165": CarrierMobile,
+	// 中国联通
+	"130": CarrierUnicom, "131": CarrierUnicom, "132": CarrierUnicom, "145": CarrierUnicom,
+	"146": CarrierUnicom, "155": CarrierUnicom, "156": CarrierUnicom, "166": CarrierUnicom,
+	"167": CarrierUnicom, "171": CarrierUnicom, "175": CarrierUnicom, "176": CarrierUnicom,
+	"185": CarrierUnicom, "186": CarrierUnicom, "196": CarrierUnicom,
+	// 中国电信
+	"133": CarrierTelecom, "149": CarrierTelecom, "153": CarrierTelecom, "162": CarrierTelecom,
+	"173": CarrierTelecom, "174": CarrierTelecom, "177": CarrierTelecom, "180": CarrierTelecom,
+	"181": CarrierTelecom, "189": CarrierTelecom, "190": CarrierTelecom, "191": CarrierTelecom,
+	"193": CarrierTelecom, "199": CarrierTelecom,
+	// 中国广电
+	"192": CarrierBroadnet,
+}
+
+// virtualPrefixes 虚拟运营商号段
+var virtualPrefixes = map[string]bool{"162": true, "165": true, "167": true, "170": true, "171": true}
+
+// NormalizeMobile 去除空格、短横线与 +86/86/0086 前缀
+func NormalizeMobile(s string) string {
+	s = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(strings.TrimSpace(s))
+	for _, p := range []string{"+86", "0086", "86"} {
+		if strings.HasPrefix(s, p) && len(s) == len(p)+11 {
+			return s[len(p):]
+		}
+	}
+	return s
+}
+
+// IsMobile 判断是否为有效的大陆手机号（号段需已分配）
+func IsMobile(s string) bool {
+	_, err := ParseMobile(s)
+	return err == nil
+}
+
+// ParseMobile 解析手机号并识别运营商
+// 注意：携号转网后号段不再代表实际运营商，这里返回的是号段归属运营商
+func ParseMobile(s string) (*Mobile, error) {
+	n := NormalizeMobile(s)
+	if len(n) != 11 || n[0] != '1' || !allDigits(n) {
+		return nil, ErrInvalidMobile
+	}
+	m := &Mobile{Number: n, Virtual: virtualPrefixes[n[:3]]}
+	switch {
+	case n[:4] == "1349":
+		m.Carrier = CarrierSatellite
+	case n[:5] >= "17400" && n[:5] <= "17405":
+		m.Carrier = CarrierSatellite
+	case n[:3] == "170":
+		// 170 号段按第四位区分：0-2 电信，3/5/6 移动，4/7/8/9 联通
+		switch n[3] {
+		case '0', '1', '2':
+			m.Carrier = CarrierTelecom
+		case '3', '5', '6':
+			m.Carrier = CarrierMobile
+		default:
+			m.Carrier = CarrierUnicom
+		}
+	default:
+		c, ok := carrierPrefixes[n[:3]]
+		if !ok {
+			return nil, ErrInvalidMobile
+		}
+		m.Carrier = c
+	}
+	return m, nil
+}
+
+// MaskMobile 手机号脱敏，保留前三后四位：138****8000
+func MaskMobile(s string) string {
+	n := NormalizeMobile(s)
+	if len(n) != 11 {
+		return s
+	}
+	return n[:3] + "****" + n[7:]
+}
+
+// allDigits 判断是否全为数字
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
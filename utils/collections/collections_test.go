@@ -0,0 +1,114 @@
+package collections
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	a := NewSet(1, 2, 3)
+	b := NewSet(3, 4)
+	a.Add(2)
+	if a.Len() != 3 || !a.Contains(2) || a.Contains(4) {
+		t.Fatalf("unexpected set: %v", a.Items())
+	}
+	sorted := func(s *Set[int]) []int {
+		items := s.Items()
+		sort.Ints(items)
+		return items
+	}
+	if got := sorted(a.Union(b)); !reflect.DeepEqual(got, []int{1, 2, 3, 4}) {
+		t.Errorf("Union = %v", got)
+	}
+	if got := sorted(a.Intersect(b)); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("Intersect = %v", got)
+	}
+	if got := sorted(a.Difference(b)); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Difference = %v", got)
+	}
+	c := a.Clone()
+	c.Remove(1)
+	if !a.Contains(1) || a.Equal(c) || !c.Equal(NewSet(2, 3)) {
+		t.Error("Clone should be independent")
+	}
+}
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set("c", 3)
+	m.Set("b", 10) // 更新不改变位置
+	if !reflect.DeepEqual(m.Keys(), []string{"b", "a", "c"}) || !reflect.DeepEqual(m.Values(), []int{10, 2, 3}) {
+		t.Fatalf("unexpected order: %v %v", m.Keys(), m.Values())
+	}
+	if !m.Delete("a") || m.Delete("a") || m.Has("a") || m.Len() != 2 {
+		t.Error("Delete failed")
+	}
+	if v, ok := m.Get("c"); !ok || v != 3 {
+		t.Errorf("Get = %v %v", v, ok)
+	}
+	var keys []string
+	m.Each(func(k string, v int) bool {
+		keys = append(keys, k)
+		return false
+	})
+	if !reflect.DeepEqual(keys, []string{"b"}) {
+		t.Errorf("Each should stop early, got %v", keys)
+	}
+}
+
+func TestRing(t *testing.T) {
+	r := NewRing[int](3)
+	for i := 1; i <= 3; i++ {
+		if _, evicted := r.Push(i); evicted {
+			t.Fatal("unexpected eviction")
+		}
+	}
+	if old, evicted := r.Push(4); !evicted || old != 1 {
+		t.Errorf("Push evicted %v %v", old, evicted)
+	}
+	if !r.Full() || !reflect.DeepEqual(r.Items(), []int{2, 3, 4}) {
+		t.Fatalf("Items = %v", r.Items())
+	}
+	if v, _ := r.Last(); v != 4 {
+		t.Errorf("Last = %d", v)
+	}
+	if v, ok := r.Pop(); !ok || v != 2 {
+		t.Errorf("Pop = %v %v", v, ok)
+	}
+	r.Push(5)
+	if !reflect.DeepEqual(r.Items(), []int{3, 4, 5}) {
+		t.Errorf("Items after wrap = %v", r.Items())
+	}
+	r.Clear()
+	if _, ok := r.Peek(); ok || r.Len() != 0 {
+		t.Error("Clear failed")
+	}
+}
+
+func TestPriorityQueue_Stable(t *testing.T) {
+	type task struct {
+		name     string
+		priority int
+	}
+	q := NewPriorityQueue(func(a, b task) bool { return a.priority > b.priority })
+	for _, tk := range []task{{"a", 1}, {"b", 5}, {"c", 1}, {"d", 5}, {"e", 3}, {"f", 1}} {
+		q.Push(tk)
+	}
+	if top, _ := q.Peek(); top.name != "b" {
+		t.Errorf("Peek = %v", top)
+	}
+	var got []string
+	for q.Len() > 0 {
+		tk, _ := q.Pop()
+		got = append(got, tk.name)
+	}
+	if want := []string{"b", "d", "e", "a", "c", "f"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if _, ok := q.Pop(); ok {
+		t.Error("Pop on empty queue should fail")
+	}
+}
@@ -0,0 +1,87 @@
+package collections
+
+import "container/list"
+
+// orderedEntry OrderedMap 的键值对
+type orderedEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// OrderedMap 按插入顺序遍历的 map，更新已有 key 不改变其位置；读写均为 O(1)
+// 实用场景: 保持字段顺序的 JSON/表头输出、按到达顺序处理的去重队列
+type OrderedMap[K comparable, V any] struct {
+	m map[K]*list.Element
+	l *list.List
+}
+
+// NewOrderedMap 创建有序 map
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{m: make(map[K]*list.Element), l: list.New()}
+}
+
+// Set 写入键值，key 已存在时只更新值
+func (o *OrderedMap[K, V]) Set(key K, value V) {
+	if e, ok := o.m[key]; ok {
+		e.Value.(*orderedEntry[K, V]).value = value
+		return
+	}
+	o.m[key] = o.l.PushBack(&orderedEntry[K, V]{key: key, value: value})
+}
+
+// Get 读取值
+func (o *OrderedMap[K, V]) Get(key K) (V, bool) {
+	if e, ok := o.m[key]; ok {
+		return e.Value.(*orderedEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Has 判断 key 是否存在
+func (o *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := o.m[key]
+	return ok
+}
+
+// Delete 删除 key，返回是否存在
+func (o *OrderedMap[K, V]) Delete(key K) bool {
+	e, ok := o.m[key]
+	if !ok {
+		return false
+	}
+	o.l.Remove(e)
+	delete(o.m, key)
+	return true
+}
+
+// Len 键值对个数
+func (o *OrderedMap[K, V]) Len() int { return len(o.m) }
+
+// Keys 按插入顺序返回所有 key
+func (o *OrderedMap[K, V]) Keys() []K {
+	out := make([]K, 0, len(o.m))
+	for e := o.l.Front(); e != nil; e = e.Next() {
+		out = append(out, e.Value.(*orderedEntry[K, V]).key)
+	}
+	return out
+}
+
+// Values 按插入顺序返回所有值
+func (o *OrderedMap[K, V]) Values() []V {
+	out := make([]V, 0, len(o.m))
+	for e := o.l.Front(); e != nil; e = e.Next() {
+		out = append(out, e.Value.(*orderedEntry[K, V]).value)
+	}
+	return out
+}
+
+// Each 按插入顺序遍历，fn 返回 false 时停止；遍历期间不能增删元素
+func (o *OrderedMap[K, V]) Each(fn func(key K, value V) bool) {
+	for e := o.l.Front(); e != nil; e = e.Next() {
+		entry := e.Value.(*orderedEntry[K, V])
+		if !fn(entry.key, entry.value) {
+			return
+		}
+	}
+}
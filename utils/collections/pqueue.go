@@ -0,0 +1,80 @@
+package collections
+
+import "container/heap"
+
+// pqItem 队列元素，seq 为入队序号，优先级相同时先入队的先出
+type pqItem[T any] struct {
+	v   T
+	seq uint64
+}
+
+// pqHeap 实现 heap.Interface
+type pqHeap[T any] struct {
+	items []pqItem[T]
+	less  func(a, b T) bool
+}
+
+func (h *pqHeap[T]) Len() int { return len(h.items) }
+func (h *pqHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.less(a.v, b.v) {
+		return true
+	}
+	if h.less(b.v, a.v) {
+		return false
+	}
+	return a.seq < b.seq
+}
+func (h *pqHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *pqHeap[T]) Push(x any)    { h.items = append(h.items, x.(pqItem[T])) }
+func (h *pqHeap[T]) Pop() any {
+	n := len(h.items) - 1
+	it := h.items[n]
+	h.items[n] = pqItem[T]{}
+	h.items = h.items[:n]
+	return it
+}
+
+// PriorityQueue 基于二叉堆的优先队列，less(a, b) 为 true 表示 a 先出队；
+// 优先级相同的元素按入队顺序出队（稳定），避免同优先级任务被无限推迟
+// 使用示例：
+//
+//	q := collections.NewPriorityQueue(func(a, b *Task) bool { return a.Priority > b.Priority })
+//	q.Push(task)
+//	next, ok := q.Pop()
+type PriorityQueue[T any] struct {
+	h   pqHeap[T]
+	seq uint64
+}
+
+// NewPriorityQueue 创建优先队列
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{h: pqHeap[T]{less: less}}
+}
+
+// Push 入队
+func (q *PriorityQueue[T]) Push(v T) {
+	heap.Push(&q.h, pqItem[T]{v: v, seq: q.seq})
+	q.seq++
+}
+
+// Pop 取出优先级最高的元素
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	if q.h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return heap.Pop(&q.h).(pqItem[T]).v, true
+}
+
+// Peek 查看优先级最高的元素
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	if q.h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.h.items[0].v, true
+}
+
+// Len 队列长度
+func (q *PriorityQueue[T]) Len() int { return q.h.Len() }
@@ -0,0 +1,85 @@
+package collections
+
+// Ring 定长环形缓冲区，写满后新元素覆盖最旧的元素
+// 实用场景: 保留最近 N 条日志/请求记录、滑动窗口内的采样值
+type Ring[T any] struct {
+	buf   []T
+	start int // 最旧元素的下标
+	n     int
+}
+
+// NewRing 创建容量为 capacity 的环形缓冲区，capacity <= 0 时按 1 处理
+func NewRing[T any](capacity int) *Ring[T] {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &Ring[T]{buf: make([]T, capacity)}
+}
+
+// Push 追加元素，已满时覆盖并返回最旧的元素
+func (r *Ring[T]) Push(v T) (evicted T, ok bool) {
+	if r.n < len(r.buf) {
+		r.buf[(r.start+r.n)%len(r.buf)] = v
+		r.n++
+		return evicted, false
+	}
+	evicted = r.buf[r.start]
+	r.buf[r.start] = v
+	r.start = (r.start + 1) % len(r.buf)
+	return evicted, true
+}
+
+// Pop 取出最旧的元素
+func (r *Ring[T]) Pop() (T, bool) {
+	var zero T
+	if r.n == 0 {
+		return zero, false
+	}
+	v := r.buf[r.start]
+	r.buf[r.start] = zero // 释放引用
+	r.start = (r.start + 1) % len(r.buf)
+	r.n--
+	return v, true
+}
+
+// Peek 查看最旧的元素
+func (r *Ring[T]) Peek() (T, bool) {
+	if r.n == 0 {
+		var zero T
+		return zero, false
+	}
+	return r.buf[r.start], true
+}
+
+// Last 查看最新的元素
+func (r *Ring[T]) Last() (T, bool) {
+	if r.n == 0 {
+		var zero T
+		return zero, false
+	}
+	return r.buf[(r.start+r.n-1)%len(r.buf)], true
+}
+
+// Items 按从旧到新的顺序返回所有元素
+func (r *Ring[T]) Items() []T {
+	out := make([]T, r.n)
+	for i := range out {
+		out[i] = r.buf[(r.start+i)%len(r.buf)]
+	}
+	return out
+}
+
+// Len 当前元素个数
+func (r *Ring[T]) Len() int { return r.n }
+
+// Cap 容量
+func (r *Ring[T]) Cap() int { return len(r.buf) }
+
+// Full 是否已满
+func (r *Ring[T]) Full() bool { return r.n == len(r.buf) }
+
+// Clear 清空缓冲区
+func (r *Ring[T]) Clear() {
+	clear(r.buf)
+	r.start, r.n = 0, 0
+}
@@ -0,0 +1,113 @@
+// Package collections 泛型集合类型：Set、按插入顺序遍历的 OrderedMap、定长环形缓冲区 Ring
+// 与稳定排序的优先队列 PriorityQueue。均不是并发安全的，多协程访问时需由调用方加锁
+package collections
+
+// Set 基于 map 的集合，零值不可用，需通过 NewSet 创建
+type Set[T comparable] struct {
+	m map[T]struct{}
+}
+
+// NewSet 创建集合并加入初始元素
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{m: make(map[T]struct{}, len(items))}
+	s.Add(items...)
+	return s
+}
+
+// Add 加入元素
+func (s *Set[T]) Add(items ...T) {
+	for _, v := range items {
+		s.m[v] = struct{}{}
+	}
+}
+
+// Remove 移除元素
+func (s *Set[T]) Remove(items ...T) {
+	for _, v := range items {
+		delete(s.m, v)
+	}
+}
+
+// Contains 判断元素是否存在
+func (s *Set[T]) Contains(v T) bool {
+	_, ok := s.m[v]
+	return ok
+}
+
+// Len 元素个数
+func (s *Set[T]) Len() int { return len(s.m) }
+
+// Items 返回所有元素，顺序不固定
+func (s *Set[T]) Items() []T {
+	out := make([]T, 0, len(s.m))
+	for v := range s.m {
+		out = append(out, v)
+	}
+	return out
+}
+
+// Each 遍历元素，fn 返回 false 时停止，顺序不固定
+func (s *Set[T]) Each(fn func(v T) bool) {
+	for v := range s.m {
+		if !fn(v) {
+			return
+		}
+	}
+}
+
+// Clone 复制集合
+func (s *Set[T]) Clone() *Set[T] {
+	c := &Set[T]{m: make(map[T]struct{}, len(s.m))}
+	for v := range s.m {
+		c.m[v] = struct{}{}
+	}
+	return c
+}
+
+// Union 并集，返回新集合
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	c := s.Clone()
+	for v := range other.m {
+		c.m[v] = struct{}{}
+	}
+	return c
+}
+
+// Intersect 交集，返回新集合
+func (s *Set[T]) Intersect(other *Set[T]) *Set[T] {
+	small, large := s, other
+	if small.Len() > large.Len() {
+		small, large = large, small
+	}
+	c := NewSet[T]()
+	for v := range small.m {
+		if large.Contains(v) {
+			c.m[v] = struct{}{}
+		}
+	}
+	return c
+}
+
+// Difference 差集（在 s 中但不在 other 中），返回新集合
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	c := NewSet[T]()
+	for v := range s.m {
+		if !other.Contains(v) {
+			c.m[v] = struct{}{}
+		}
+	}
+	return c
+}
+
+// Equal 判断两个集合元素是否完全相同
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	if s.Len() != other.Len() {
+		return false
+	}
+	for v := range s.m {
+		if !other.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+// Package copyx 深拷贝与结构体字段映射（DTO <-> Model），替代各服务中手写的反射拷贝代码
+package copyx
+
+import "reflect"
+
+// Clone 深拷贝任意值：指针、切片、map、数组与结构体的导出字段会递归复制，
+// 循环引用的指针保持同样的引用关系；未导出字段、chan、func 按值浅拷贝
+func Clone[T any](v T) T {
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	deepCopy(dst, src, make(map[visitKey]reflect.Value))
+	return dst.Interface().(T)
+}
+
+// visitKey 已复制过的指针：结构体与其第一个字段地址相同，因此需要同时按类型区分
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// deepCopy 将 src 深拷贝到 dst（dst 可寻址且类型相同）
+func deepCopy(dst, src reflect.Value, visited map[visitKey]reflect.Value) {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		key := visitKey{ptr: src.Pointer(), typ: src.Type()}
+		if p, ok := visited[key]; ok {
+			dst.Set(p)
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		visited[key] = p
+		deepCopy(p.Elem(), src.Elem(), visited)
+		dst.Set(p)
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		inner := src.Elem()
+		cp := reflect.New(inner.Type()).Elem()
+		deepCopy(cp, inner, visited)
+		dst.Set(cp)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(s.Index(i), src.Index(i), visited)
+		}
+		dst.Set(s)
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(dst.Index(i), src.Index(i), visited)
+		}
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			k := reflect.New(iter.Key().Type()).Elem()
+			deepCopy(k, iter.Key(), visited)
+			v := reflect.New(iter.Value().Type()).Elem()
+			deepCopy(v, iter.Value(), visited)
+			m.SetMapIndex(k, v)
+		}
+		dst.Set(m)
+
+	case reflect.Struct:
+		// 先整体赋值以保留未导出字段（如 time.Time），再逐个深拷贝导出字段
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				deepCopy(dst.Field(i), src.Field(i), visited)
+			}
+		}
+
+	default:
+		dst.Set(src)
+	}
+}
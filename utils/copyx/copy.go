@@ -0,0 +1,284 @@
+package copyx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// TagName 字段映射使用的 struct tag，`copy:"name"` 指定映射名，`copy:"-"` 忽略该字段
+const TagName = "copy"
+
+// Option Copy 的可选配置
+type Option func(*options)
+
+type options struct {
+	ignoreEmpty bool
+	converters  map[[2]reflect.Type]func(reflect.Value) (reflect.Value, error)
+	visited     map[visitKey]reflect.Value // 源指针与目标类型 -> 已创建的目标指针，处理循环引用
+}
+
+// IgnoreEmpty 源字段为零值时不覆盖目标字段，适合 PATCH 场景
+func IgnoreEmpty() Option {
+	return func(o *options) { o.ignoreEmpty = true }
+}
+
+// WithConverter 注册 From -> To 的类型转换函数，优先于内置转换规则
+// 使用示例：
+//
+//	copyx.Copy(&dto, &user, copyx.WithConverter(func(t time.Time) (string, error) {
+//		return t.Format("2006-01-02"), nil
+//	}))
+func WithConverter[From, To any](fn func(From) (To, error)) Option {
+	from := reflect.TypeOf((*From)(nil)).Elem()
+	to := reflect.TypeOf((*To)(nil)).Elem()
+	return func(o *options) {
+		o.converters[[2]reflect.Type{from, to}] = func(v reflect.Value) (reflect.Value, error) {
+			out, err := fn(v.Interface().(From))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(&out).Elem(), nil
+		}
+	}
+}
+
+// Copy 按字段名（或 copy tag）将 src 的字段复制到 dst，dst 必须为结构体指针，src 为结构体或其指针
+// 内置转换：可直接赋值、数值类型互转、指针与值互转、嵌套结构体/切片/map 递归映射、time.Time 与 RFC 3339 字符串互转；
+// 源结构体中不存在的字段保持不变，无法转换的字段返回错误；指针之间的循环引用在目标中保持同样的引用关系
+func Copy(dst, src interface{}, opts ...Option) error {
+	o := &options{
+		converters: make(map[[2]reflect.Type]func(reflect.Value) (reflect.Value, error)),
+		visited:    make(map[visitKey]reflect.Value),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return errors.New("copyx: dst must be a non-nil pointer to struct")
+	}
+	sv := reflect.Indirect(reflect.ValueOf(src))
+	if sv.Kind() != reflect.Struct {
+		return errors.New("copyx: src must be a struct or pointer to struct")
+	}
+	if p := reflect.ValueOf(src); p.Kind() == reflect.Ptr {
+		o.visited[visitKey{ptr: p.Pointer(), typ: dv.Type()}] = dv
+	}
+	return o.copyStruct(dv.Elem(), sv)
+}
+
+// copyStruct 结构体之间按映射名复制
+func (o *options) copyStruct(dst, src reflect.Value) error {
+	srcFields := fieldIndex(src.Type())
+	for name, di := range fieldIndex(dst.Type()) {
+		si, ok := srcFields[name]
+		if !ok {
+			continue
+		}
+		sf, err := src.FieldByIndexErr(si)
+		if err != nil {
+			// 经过 nil 嵌入指针的提升字段，视为不存在
+			continue
+		}
+		if o.ignoreEmpty && sf.IsZero() {
+			continue
+		}
+		df, err := fieldByIndexAlloc(dst, di)
+		if err != nil {
+			return err
+		}
+		if err := o.assign(df, sf); err != nil {
+			return fmt.Errorf("copyx: field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// assign 将 src 转换后赋值给 dst
+func (o *options) assign(dst, src reflect.Value) error {
+	st, dt := src.Type(), dst.Type()
+	if conv, ok := o.converters[[2]reflect.Type{st, dt}]; ok {
+		v, err := conv(src)
+		if err != nil {
+			return err
+		}
+		dst.Set(v)
+		return nil
+	}
+
+	switch {
+	case st.AssignableTo(dt) && !needsDeepMap(st):
+		dst.Set(src)
+		return nil
+
+	case st.Kind() == reflect.Ptr && dt.Kind() == reflect.Ptr:
+		// *T -> *U：同一源指针映射到同一目标指针，避免循环引用无限递归；nil 指针保持目标不变
+		if src.IsNil() {
+			return nil
+		}
+		key := visitKey{ptr: src.Pointer(), typ: dt}
+		if p, ok := o.visited[key]; ok {
+			dst.Set(p)
+			return nil
+		}
+		p := reflect.New(dt.Elem())
+		o.visited[key] = p
+		if err := o.assign(p.Elem(), src.Elem()); err != nil {
+			return err
+		}
+		dst.Set(p)
+		return nil
+
+	case st.Kind() == reflect.Ptr:
+		// *T -> U：nil 指针保持目标不变
+		if src.IsNil() {
+			return nil
+		}
+		return o.assign(dst, src.Elem())
+
+	case dt.Kind() == reflect.Ptr:
+		// T -> *U
+		p := reflect.New(dt.Elem())
+		if err := o.assign(p.Elem(), src); err != nil {
+			return err
+		}
+		dst.Set(p)
+		return nil
+
+	case st == timeType && dt.Kind() == reflect.String:
+		t := src.Interface().(time.Time)
+		if !t.IsZero() {
+			dst.SetString(t.Format(time.RFC3339))
+		}
+		return nil
+
+	case st.Kind() == reflect.String && dt == timeType:
+		if src.String() == "" {
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339, src.String())
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+
+	case st.Kind() == reflect.Struct && dt.Kind() == reflect.Struct:
+		return o.copyStruct(dst, src)
+
+	case st.Kind() == reflect.Slice && dt.Kind() == reflect.Slice:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dt))
+			return nil
+		}
+		s := reflect.MakeSlice(dt, src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := o.assign(s.Index(i), src.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		dst.Set(s)
+		return nil
+
+	case st.Kind() == reflect.Map && dt.Kind() == reflect.Map:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dt))
+			return nil
+		}
+		m := reflect.MakeMapWithSize(dt, src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			k := reflect.New(dt.Key()).Elem()
+			if err := o.assign(k, iter.Key()); err != nil {
+				return err
+			}
+			v := reflect.New(dt.Elem()).Elem()
+			if err := o.assign(v, iter.Value()); err != nil {
+				return err
+			}
+			m.SetMapIndex(k, v)
+		}
+		dst.Set(m)
+		return nil
+
+	case isNumber(st.Kind()) && isNumber(dt.Kind()):
+		dst.Set(src.Convert(dt))
+		return nil
+
+	case st.Kind() == reflect.String && dt.Kind() == reflect.String:
+		// 基础类型为 string 的自定义类型互转
+		dst.SetString(src.String())
+		return nil
+	}
+	return fmt.Errorf("cannot convert %s to %s", st, dt)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// needsDeepMap 同类型的引用类型也需要逐元素复制，避免 dst 与 src 共享底层数据
+func needsDeepMap(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// isNumber 判断是否为数值类型
+func isNumber(k reflect.Kind) bool {
+	return (k >= reflect.Int && k <= reflect.Uint64) || k == reflect.Float32 || k == reflect.Float64
+}
+
+// fieldIndex 返回类型中所有可导出字段（含嵌入结构体的提升字段）的映射名 -> 索引
+func fieldIndex(t reflect.Type) map[string][]int {
+	out := make(map[string][]int)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || (f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct) {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup(TagName); ok {
+			tag = strings.Split(tag, ",")[0]
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		// 浅层字段优先（VisibleFields 按深度顺序返回）
+		if _, exists := out[name]; !exists {
+			out[name] = f.Index
+		}
+	}
+	return out
+}
+
+// indirectType 取指针指向的类型
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// fieldByIndexAlloc 按索引取字段，途经 nil 嵌入指针时自动分配
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, errors.New("copyx: cannot allocate embedded pointer")
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
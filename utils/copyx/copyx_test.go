@@ -0,0 +1,182 @@
+package copyx
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type node struct {
+	Name     string
+	Tags     []string
+	Attrs    map[string]*int
+	Next     *node
+	Created  time.Time
+	internal int
+}
+
+func TestClone(t *testing.T) {
+	n := 1
+	a := &node{Name: "a", Tags: []string{"x"}, Attrs: map[string]*int{"n": &n}, Created: time.Now(), internal: 7}
+	a.Next = a // 循环引用
+
+	b := Clone(a)
+	assert.NotSame(t, a, b)
+	assert.Same(t, b, b.Next, "cycle should point to the cloned node")
+	assert.Equal(t, 7, b.internal)
+	assert.True(t, a.Created.Equal(b.Created))
+
+	b.Tags[0] = "y"
+	*b.Attrs["n"] = 2
+	assert.Equal(t, "x", a.Tags[0])
+	assert.Equal(t, 1, n)
+
+	var nilMap map[string]int
+	assert.Nil(t, Clone(nilMap))
+	assert.Equal(t, []int{1, 2}, Clone([]int{1, 2}))
+}
+
+type Base struct {
+	ID        int64
+	CreatedAt time.Time
+}
+
+type Item struct {
+	SKU string
+	Qty int
+}
+
+type Order struct {
+	Base
+	UserID  int64
+	Status  int8
+	Remark  *string
+	Items   []Item
+	Secret  string
+	Amount  int64
+	Meta    map[string]string
+	Created time.Time `copy:"created_at"`
+}
+
+type ItemDTO struct {
+	SKU string
+	Qty int64
+}
+
+type OrderDTO struct {
+	ID        int64
+	UserID    string
+	Status    int
+	Remark    string
+	Items     []ItemDTO
+	Secret    string `copy:"-"`
+	Amount    *int64
+	Meta      map[string]string
+	CreatedAt string `copy:"created_at"`
+}
+
+func TestCopy(t *testing.T) {
+	remark := "fast"
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	o := Order{
+		Base:    Base{ID: 9},
+		UserID:  42,
+		Status:  3,
+		Remark:  &remark,
+		Items:   []Item{{SKU: "A", Qty: 2}},
+		Secret:  "s",
+		Amount:  100,
+		Meta:    map[string]string{"k": "v"},
+		Created: created,
+	}
+
+	var dto OrderDTO
+	err := Copy(&dto, &o, WithConverter(func(id int64) (string, error) { return strconv.FormatInt(id, 10), nil }))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), dto.ID)
+	assert.Equal(t, "42", dto.UserID)
+	assert.Equal(t, 3, dto.Status)
+	assert.Equal(t, "fast", dto.Remark)
+	assert.Equal(t, []ItemDTO{{SKU: "A", Qty: 2}}, dto.Items)
+	assert.Empty(t, dto.Secret)
+	assert.Equal(t, int64(100), *dto.Amount)
+	assert.Equal(t, "2024-01-02T03:04:05Z", dto.CreatedAt)
+	dto.Meta["k"] = "changed"
+	assert.Equal(t, "v", o.Meta["k"])
+
+	// 反向映射 + IgnoreEmpty
+	back := Order{Status: 1, Secret: "keep"}
+	err = Copy(&back, OrderDTO{Status: 0, Remark: "slow", CreatedAt: "2024-01-02T03:04:05Z"}, IgnoreEmpty())
+	assert.NoError(t, err)
+	assert.Equal(t, int8(1), back.Status)
+	assert.Equal(t, "slow", *back.Remark)
+	assert.Equal(t, "keep", back.Secret)
+	assert.True(t, created.Equal(back.Created))
+
+	// 无法转换时返回错误
+	assert.Error(t, Copy(&back, OrderDTO{UserID: "x"}))
+	assert.Error(t, Copy(back, o))
+}
+
+type pair struct {
+	X int
+	Y int
+}
+
+type pairRef struct {
+	Pair *pair
+	X    *int // 与 Pair 指向同一地址（结构体的第一个字段）
+}
+
+func TestClone_FirstFieldPointer(t *testing.T) {
+	p := &pair{X: 1, Y: 2}
+	c := Clone(pairRef{Pair: p, X: &p.X})
+	assert.NotSame(t, p, c.Pair)
+	assert.Equal(t, pair{X: 1, Y: 2}, *c.Pair)
+	assert.Equal(t, 1, *c.X)
+}
+
+type chain struct {
+	Name string
+	Next *chain
+}
+
+type chainDTO struct {
+	Name string
+	Next *chainDTO
+}
+
+type pairDTO struct {
+	X int64
+	Y int64
+}
+
+type pairRefDTO struct {
+	Pair *pairDTO
+	X    *int64
+}
+
+func TestCopy_Cycles(t *testing.T) {
+	a := &chain{Name: "a"}
+	b := &chain{Name: "b", Next: a}
+	a.Next = b
+
+	var dto chainDTO
+	assert.NoError(t, Copy(&dto, a))
+	assert.Equal(t, "b", dto.Next.Name)
+	assert.Same(t, &dto, dto.Next.Next, "cycle should point back to dst")
+
+	self := &chain{Name: "self"}
+	self.Next = self
+	var selfDTO chainDTO
+	assert.NoError(t, Copy(&selfDTO, *self))
+	assert.Same(t, selfDTO.Next, selfDTO.Next.Next)
+
+	p := &pair{X: 1, Y: 2}
+	var ref pairRefDTO
+	assert.NoError(t, Copy(&ref, pairRef{Pair: p, X: &p.X}))
+	assert.Equal(t, pairDTO{X: 1, Y: 2}, *ref.Pair)
+	assert.Equal(t, int64(1), *ref.X)
+}
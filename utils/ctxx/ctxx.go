@@ -0,0 +1,87 @@
+// Package ctxx 请求上下文工具：traceId（与 logger、httpx 读取的是同一个值）、
+// 用户与租户 ID 的存取（与 featureflag、mysqlx 读取的是同一个值），
+// 以及从请求派生后台任务时使用的 DetachedContext
+// 使用示例：
+//
+//	ctx = ctxx.WithTraceID(ctx, ctxx.NewTraceID())
+//	ctx = ctxx.WithUserID(ctx, claims.Subject)
+//	go audit(ctxx.DetachedContext(ctx)) // 请求结束后仍可继续执行，日志仍带 traceId
+package ctxx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+)
+
+// NewTraceID 生成 32 位十六进制的随机 traceId，格式与 W3C Trace Context 的 trace-id 一致
+func NewTraceID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithTraceID 将 traceId 写入 context，之后的日志自动带上 traceId 字段，httpx 可透传到下游
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return logger.WithTraceID(ctx, id)
+}
+
+// TraceID 读取 context 中的 traceId，未设置时返回空串
+func TraceID(ctx context.Context) string {
+	return logger.TraceIDFromContext(ctx)
+}
+
+// EnsureTraceID context 中没有 traceId 时生成一个，返回新的 context 与 traceId
+func EnsureTraceID(ctx context.Context) (context.Context, string) {
+	if id := TraceID(ctx); id != "" {
+		return ctx, id
+	}
+	id := NewTraceID()
+	return WithTraceID(ctx, id), id
+}
+
+// ContextKey ctxx 使用的 context key 类型，避免与其它包的字符串 key 冲突
+type ContextKey string
+
+// 用户与租户 ID 的 context key，featureflag（灰度分桶）与 mysqlx（租户库路由）读取的是同一个值；
+// 也可直接传给 httpx.WithContextHeader 透传到下游
+const (
+	UserIDKey   ContextKey = "userId"
+	TenantIDKey ContextKey = "tenantId"
+)
+
+// WithUserID 将当前用户 ID 写入 context
+func WithUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, UserIDKey, id)
+}
+
+// UserID 读取当前用户 ID，未设置时 ok 为 false
+func UserID(ctx context.Context) (id string, ok bool) {
+	id, ok = ctx.Value(UserIDKey).(string)
+	return id, ok
+}
+
+// WithTenantID 将当前租户 ID 写入 context
+func WithTenantID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, TenantIDKey, id)
+}
+
+// TenantID 读取当前租户 ID，未设置时 ok 为 false
+func TenantID(ctx context.Context) (id string, ok bool) {
+	id, ok = ctx.Value(TenantIDKey).(string)
+	return id, ok
+}
+
+// DetachedContext 返回保留 ctx 中所有值（traceId、用户、日志字段等）但不随 ctx 取消或超时的 context，
+// 用于从请求中派生、需要在请求结束后继续执行的后台任务
+func DetachedContext(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
+
+// DetachedWithTimeout 同 DetachedContext，并为后台任务设置独立的超时，避免任务无限期运行
+func DetachedWithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), d)
+}
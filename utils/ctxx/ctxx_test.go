@@ -0,0 +1,58 @@
+package ctxx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+)
+
+func TestTraceID(t *testing.T) {
+	id := NewTraceID()
+	if len(id) != 32 || id == NewTraceID() {
+		t.Fatalf("unexpected trace id %q", id)
+	}
+	ctx := WithTraceID(context.Background(), id)
+	if TraceID(ctx) != id || logger.TraceIDFromContext(ctx) != id {
+		t.Error("trace id should be shared with logger")
+	}
+	if got, gotID := EnsureTraceID(ctx); got != ctx || gotID != id {
+		t.Error("EnsureTraceID should keep an existing id")
+	}
+	if _, gotID := EnsureTraceID(context.Background()); len(gotID) != 32 {
+		t.Errorf("EnsureTraceID generated %q", gotID)
+	}
+}
+
+func TestUserAndTenant(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := UserID(ctx); ok {
+		t.Error("user id should be unset")
+	}
+	ctx = WithTenantID(WithUserID(ctx, "u1"), "t1")
+	if id, ok := UserID(ctx); !ok || id != "u1" {
+		t.Errorf("UserID = %q %v", id, ok)
+	}
+	if id, ok := TenantID(ctx); !ok || id != "t1" {
+		t.Errorf("TenantID = %q %v", id, ok)
+	}
+}
+
+func TestDetachedContext(t *testing.T) {
+	parent, cancel := context.WithCancel(WithUserID(context.Background(), "u1"))
+	cancel()
+	ctx := DetachedContext(parent)
+	if ctx.Err() != nil {
+		t.Error("detached context should not be canceled")
+	}
+	if id, _ := UserID(ctx); id != "u1" {
+		t.Error("detached context should keep values")
+	}
+	ctx, stop := DetachedWithTimeout(parent, 10*time.Millisecond)
+	defer stop()
+	<-ctx.Done()
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("err = %v", ctx.Err())
+	}
+}
@@ -0,0 +1,140 @@
+// Package debugx 运行时调试开关：按配置在内部端口挂载 pprof 与 expvar、收到信号时将协程栈保存到文件、
+// RSS 超过阈值时自动保存堆 profile（均只保留最近若干个文件），用于线上排查内存与协程泄漏
+// 使用示例：
+//
+//	debugx.Start(ctx, debugx.Config{
+//		Enabled:          os.Getenv("DEBUG_PPROF") == "1",
+//		Addr:             "127.0.0.1:6060",
+//		HeapRSSThreshold: 2 << 30,
+//		HeapProfileDir:   "/tmp/profiles",
+//	})
+//	debugx.DumpOnSignal(ctx, []os.Signal{syscall.SIGUSR1})
+package debugx
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+	"go.uber.org/zap"
+)
+
+// 默认值
+const (
+	defaultAddr         = "127.0.0.1:6060"
+	defaultRSSInterval  = 10 * time.Second
+	defaultHeapCooldown = 10 * time.Minute
+	defaultKeep         = 10
+	shutdownTimeout     = 5 * time.Second
+)
+
+// Config 调试配置，Enabled 为 false 时 Start 不做任何事
+type Config struct {
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	Addr    string `json:"addr" yaml:"addr"` // pprof/expvar 监听地址，默认 127.0.0.1:6060，不要暴露到公网
+
+	HeapRSSThreshold uint64        `json:"heap_rss_threshold" yaml:"heap_rss_threshold"` // RSS 超过该字节数时保存堆 profile，0 表示不监控
+	HeapProfileDir   string        `json:"heap_profile_dir" yaml:"heap_profile_dir"`     // 堆 profile 保存目录，默认系统临时目录
+	RSSInterval      time.Duration `json:"rss_interval" yaml:"rss_interval"`             // RSS 检查间隔，默认 10 秒
+	HeapCooldown     time.Duration `json:"heap_cooldown" yaml:"heap_cooldown"`           // 两次保存的最小间隔，默认 10 分钟
+	HeapProfileKeep  int           `json:"heap_profile_keep" yaml:"heap_profile_keep"`   // 最多保留的堆 profile 个数，默认 10，< 0 表示不清理
+}
+
+// Option 函数式选项
+type Option func(*options)
+
+// options 日志、文件保存等公共选项
+type options struct {
+	log  *logger.Logger
+	dir  string
+	keep int
+}
+
+// WithLogger 指定输出日志的 logger，默认 logger.Default()
+func WithLogger(l *logger.Logger) Option {
+	return func(o *options) { o.log = l }
+}
+
+// WithDumpDir 指定 DumpOnSignal 保存协程栈文件的目录，默认系统临时目录
+func WithDumpDir(dir string) Option {
+	return func(o *options) { o.dir = dir }
+}
+
+// WithKeep 每类文件（协程栈、堆 profile）最多保留的个数，超出时删除最旧的；默认 10，< 0 表示不清理
+func WithKeep(n int) Option {
+	return func(o *options) { o.keep = n }
+}
+
+// newOptions 应用选项
+func newOptions(opts []Option) options {
+	o := options{keep: defaultKeep}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.log == nil {
+		o.log = logger.Default()
+	}
+	if o.dir == "" {
+		o.dir = os.TempDir()
+	}
+	if o.keep == 0 {
+		o.keep = defaultKeep
+	}
+	return o
+}
+
+// Handler 返回挂载了 /debug/pprof/ 与 /debug/vars 的处理器，可挂到已有的内部管理端口上
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// Start 按配置启动调试端口与 RSS 监控，ctx 结束时关闭；未启用时直接返回 nil。
+// 监听失败时同步返回错误，之后的运行错误只记录日志
+func Start(ctx context.Context, cfg Config, opts ...Option) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	o := newOptions(opts)
+	addr := cfg.Addr
+	if addr == "" {
+		addr = defaultAddr
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			o.log.Error(ctx, "debugx: server stopped", zap.Error(err))
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(sctx)
+	}()
+	o.log.Info(ctx, "debugx: pprof listening", zap.String("addr", ln.Addr().String()))
+
+	if cfg.HeapRSSThreshold > 0 {
+		if cfg.HeapProfileKeep != 0 {
+			opts = append([]Option{WithKeep(cfg.HeapProfileKeep)}, opts...)
+		}
+		go WatchRSS(ctx, cfg.HeapRSSThreshold, cfg.HeapProfileDir, cfg.RSSInterval, cfg.HeapCooldown, opts...)
+	}
+	return nil
+}
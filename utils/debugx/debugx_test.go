@@ -0,0 +1,97 @@
+package debugx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+)
+
+// testLogger 日志写入测试临时目录
+func testLogger(t *testing.T) Option {
+	return WithLogger(logger.New(&logger.Config{FileName: filepath.Join(t.TempDir(), "app.log")}))
+}
+
+func TestHandler(t *testing.T) {
+	h := Handler()
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/goroutine?debug=1", "/debug/vars"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: code = %d", path, rec.Code)
+		}
+	}
+}
+
+func TestStart(t *testing.T) {
+	if err := Start(context.Background(), Config{}); err != nil {
+		t.Fatalf("disabled Start should be a no-op: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := Start(ctx, Config{Enabled: true, Addr: "127.0.0.1:0"}, testLogger(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := Start(ctx, Config{Enabled: true, Addr: "invalid:addr:x"}, testLogger(t)); err == nil {
+		t.Error("expected listen error")
+	}
+}
+
+func TestWatchRSS(t *testing.T) {
+	if RSS() == 0 {
+		t.Fatal("RSS should be positive")
+	}
+	dir := t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	WatchRSS(ctx, 1, dir, 10*time.Millisecond, time.Hour, testLogger(t))
+	files, _ := filepath.Glob(filepath.Join(dir, "heap-*.pprof"))
+	if len(files) != 1 {
+		t.Fatalf("want exactly one profile within cooldown, got %v", files)
+	}
+	if fi, err := os.Stat(files[0]); err != nil || fi.Size() == 0 {
+		t.Errorf("profile should not be empty: %v", err)
+	}
+}
+
+func TestGoroutineDump(t *testing.T) {
+	if s := GoroutineDump(); !strings.Contains(s, "TestGoroutineDump") {
+		t.Error("dump should include the current goroutine")
+	}
+	path, err := WriteGoroutineDump(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "TestGoroutineDump") {
+		t.Errorf("dump file %s should include the current goroutine", path)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"heap-1-a.pprof", "heap-1-b.pprof", "heap-1-c.pprof", "goroutine-1-a.txt"} {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mt := now.Add(time.Duration(i) * time.Minute)
+		_ = os.Chtimes(p, mt, mt)
+	}
+	if err := prune(dir, "heap-*.pprof", 2); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	for i := range files {
+		files[i] = filepath.Base(files[i])
+	}
+	if strings.Join(files, ",") != "goroutine-1-a.txt,heap-1-b.pprof,heap-1-c.pprof" {
+		t.Errorf("files after prune = %v", files)
+	}
+}
@@ -0,0 +1,55 @@
+package debugx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"runtime/pprof"
+
+	"go.uber.org/zap"
+)
+
+// DumpOnSignal 收到 sigs 中的信号时将所有协程栈保存到 goroutine-<pid>-<时间>.txt（目录见 WithDumpDir，
+// 只保留最近的若干个，见 WithKeep），并以 warn 级别记录文件路径；协程栈可达数 MB，不直接写入日志。
+// ctx 结束时停止监听；通常使用 syscall.SIGUSR1，避免与优雅退出的 SIGTERM 冲突
+func DumpOnSignal(ctx context.Context, sigs []os.Signal, opts ...Option) {
+	o := newOptions(opts)
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-ch:
+				path, err := WriteGoroutineDump(o.dir)
+				if err != nil {
+					o.log.Error(ctx, "debugx: write goroutine dump", zap.String("signal", sig.String()), zap.Error(err))
+					continue
+				}
+				o.log.Warn(ctx, "debugx: goroutine dump saved",
+					zap.String("signal", sig.String()),
+					zap.Int("goroutines", pprof.Lookup("goroutine").Count()),
+					zap.String("path", path))
+				if err := prune(o.dir, "goroutine-*.txt", o.keep); err != nil {
+					o.log.Error(ctx, "debugx: prune goroutine dumps", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// WriteGoroutineDump 将所有协程的完整调用栈写入 dir/goroutine-<pid>-<时间>.txt 并返回文件路径
+func WriteGoroutineDump(dir string) (string, error) {
+	return writeProfile(dir, "goroutine", "txt", func(w io.Writer) error { return pprof.Lookup("goroutine").WriteTo(w, 2) })
+}
+
+// GoroutineDump 返回所有协程的完整调用栈，格式与 panic 时的输出一致
+func GoroutineDump() string {
+	var buf bytes.Buffer
+	_ = pprof.Lookup("goroutine").WriteTo(&buf, 2)
+	return buf.String()
+}
@@ -0,0 +1,129 @@
+package debugx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WatchRSS 每隔 interval 检查进程 RSS，超过 threshold 字节时将堆 profile 保存到 dir 并记录日志，
+// 两次保存至少间隔 cooldown，目录中只保留最近的若干个堆 profile（见 WithKeep）；阻塞直到 ctx 结束，通常以 go 启动。
+// dir 为空时使用系统临时目录，interval、cooldown 为 0 时分别为 10 秒、10 分钟
+func WatchRSS(ctx context.Context, threshold uint64, dir string, interval, cooldown time.Duration, opts ...Option) {
+	o := newOptions(opts)
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if interval <= 0 {
+		interval = defaultRSSInterval
+	}
+	if cooldown <= 0 {
+		cooldown = defaultHeapCooldown
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		rss := RSS()
+		if rss < threshold || (!last.IsZero() && time.Since(last) < cooldown) {
+			continue
+		}
+		last = time.Now()
+		path, err := WriteHeapProfile(dir)
+		if err != nil {
+			o.log.Error(ctx, "debugx: write heap profile", zap.Uint64("rss", rss), zap.Error(err))
+			continue
+		}
+		o.log.Warn(ctx, "debugx: rss exceeded threshold, heap profile saved",
+			zap.Uint64("rss", rss), zap.Uint64("threshold", threshold), zap.String("path", path))
+		if err := prune(dir, "heap-*.pprof", o.keep); err != nil {
+			o.log.Error(ctx, "debugx: prune heap profiles", zap.Error(err))
+		}
+	}
+}
+
+// WriteHeapProfile 将堆 profile 写入 dir/heap-<pid>-<时间>.pprof 并返回文件路径，可用 go tool pprof 分析
+func WriteHeapProfile(dir string) (string, error) {
+	return writeProfile(dir, "heap", "pprof", func(w io.Writer) error { return pprof.Lookup("heap").WriteTo(w, 0) })
+}
+
+// writeProfile 将 write 的输出保存到 dir/<kind>-<pid>-<时间>.<ext> 并返回文件路径
+func writeProfile(dir, kind, ext string, write func(w io.Writer) error) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%d-%s.%s", kind, os.Getpid(), time.Now().Format("20060102T150405.000"), ext)
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// prune 删除 dir 中匹配 pattern 的文件，只保留修改时间最新的 keep 个；keep < 0 时不清理
+func prune(dir, pattern string, keep int) error {
+	if keep < 0 {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil || len(files) <= keep {
+		return err
+	}
+	mtime := make(map[string]time.Time, len(files))
+	for _, f := range files {
+		if fi, err := os.Stat(f); err == nil {
+			mtime[f] = fi.ModTime()
+		}
+	}
+	// 修改时间相同时按文件名（含时间戳）排序
+	sort.Slice(files, func(i, j int) bool {
+		if ti, tj := mtime[files[i]], mtime[files[j]]; !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return files[i] > files[j]
+	})
+	var errs []error
+	for _, f := range files[keep:] {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RSS 返回进程常驻内存字节数；Linux 上读取 /proc/self/statm，其它平台以 Go 运行时从系统申请的内存近似
+func RSS() uint64 {
+	if runtime.GOOS == "linux" {
+		if data, err := os.ReadFile("/proc/self/statm"); err == nil {
+			if fields := strings.Fields(string(data)); len(fields) > 1 {
+				if pages, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+					return pages * uint64(os.Getpagesize())
+				}
+			}
+		}
+	}
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.Sys
+}
@@ -0,0 +1,121 @@
+package eventbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Transport 跨进程广播通道，Bridge 通过它收发事件
+type Transport interface {
+	// Publish 向频道广播消息
+	Publish(ctx context.Context, channel string, data []byte) error
+	// Subscribe 订阅频道并对每条消息调用 fn，阻塞直到 ctx 结束
+	Subscribe(ctx context.Context, channel string, fn func(data []byte)) error
+}
+
+// envelope 广播的消息格式，Source 用于忽略本进程自己发出的消息
+type envelope struct {
+	Source  string          `json:"source"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// remoteKey 标记来自远端的事件，避免再次转发
+type remoteKey struct{}
+
+// Bridge 将主题桥接到 Transport：本地发布的事件（JSON 编码）广播到频道 prefix+主题名，
+// 其它进程广播的事件在本地重新发布；本进程发出的消息会被忽略，不会重复投递。
+// 阻塞直到 ctx 结束，通常在单独的协程中运行：
+//
+//	go eventbus.Bridge(ctx, bus, OrderPaid, eventbus.NewRedisTransport(cli), "events:")
+func Bridge[T any](ctx context.Context, b *Bus, topic Topic[T], t Transport, prefix string) error {
+	source, err := newSourceID()
+	if err != nil {
+		return err
+	}
+	channel := prefix + topic.name
+
+	unsubscribe := Subscribe(b, topic, func(ctx context.Context, event T) error {
+		if ctx.Value(remoteKey{}) != nil {
+			return nil
+		}
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("eventbus: encode %s: %w", topic.name, err)
+		}
+		data, err := json.Marshal(envelope{Source: source, Payload: payload})
+		if err != nil {
+			return err
+		}
+		return t.Publish(ctx, channel, data)
+	}, Async(1))
+	defer unsubscribe()
+
+	return t.Subscribe(ctx, channel, func(data []byte) {
+		var env envelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			b.reportError(topic.name, fmt.Errorf("eventbus: decode %s: %w", topic.name, err))
+			return
+		}
+		if env.Source == source {
+			return
+		}
+		var event T
+		if err := json.Unmarshal(env.Payload, &event); err != nil {
+			b.reportError(topic.name, fmt.Errorf("eventbus: decode %s: %w", topic.name, err))
+			return
+		}
+		if err := Publish(context.WithValue(ctx, remoteKey{}, true), b, topic, event); err != nil {
+			b.reportError(topic.name, err)
+		}
+	})
+}
+
+// newSourceID 生成进程内唯一的来源 ID
+func newSourceID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// RedisTransport 基于 Redis Pub/Sub 的 Transport，单机与集群客户端均可使用
+type RedisTransport struct {
+	c redis.UniversalClient
+}
+
+// NewRedisTransport 创建 Redis Pub/Sub 通道
+func NewRedisTransport(c redis.UniversalClient) *RedisTransport {
+	return &RedisTransport{c: c}
+}
+
+// Publish 实现 Transport
+func (r *RedisTransport) Publish(ctx context.Context, channel string, data []byte) error {
+	return r.c.Publish(ctx, channel, data).Err()
+}
+
+// Subscribe 实现 Transport，连接断开时 go-redis 会自动重连并重新订阅
+func (r *RedisTransport) Subscribe(ctx context.Context, channel string, fn func(data []byte)) error {
+	sub := r.c.Subscribe(ctx, channel)
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		return err
+	}
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			fn([]byte(msg.Payload))
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// Package eventbus 进程内事件总线：类型安全的主题、同步/异步投递、有界队列、panic 隔离与优雅关闭，
+// 让模块之间通过事件解耦而无需互相引用；可通过 Bridge 将主题桥接到 Redis Pub/Sub 实现跨进程广播
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrClosed 总线已关闭
+	ErrClosed = errors.New("eventbus: bus closed")
+	// ErrQueueFull 异步订阅的队列已满，事件被丢弃（仅 DropWhenFull 订阅）
+	ErrQueueFull = errors.New("eventbus: queue full")
+	// ErrHandlerPanic 处理函数 panic，已被恢复
+	ErrHandlerPanic = errors.New("eventbus: handler panic")
+)
+
+// defaultQueueSize 异步订阅默认队列长度
+const defaultQueueSize = 1024
+
+// Topic 类型化主题，发布与订阅的载荷类型在编译期保持一致
+//
+//	var OrderPaid = eventbus.NewTopic[OrderPaidEvent]("order.paid")
+type Topic[T any] struct {
+	name string
+}
+
+// NewTopic 创建主题
+func NewTopic[T any](name string) Topic[T] { return Topic[T]{name: name} }
+
+// Name 主题名
+func (t Topic[T]) Name() string { return t.name }
+
+// Option 总线选项
+type Option func(*Bus)
+
+// WithErrorHandler 异步处理失败、panic 或队列满丢弃时的回调，默认忽略
+func WithErrorHandler(fn func(topic string, err error)) Option {
+	return func(b *Bus) { b.onError = fn }
+}
+
+// SubOption 订阅选项
+type SubOption func(*subscription)
+
+// Async 异步投递：事件进入订阅者自己的有界队列，由 workers 个协程处理，默认队列长度 1024、1 个协程（保序）
+func Async(workers int) SubOption {
+	return func(s *subscription) {
+		if workers <= 0 {
+			workers = 1
+		}
+		s.workers = workers
+	}
+}
+
+// QueueSize 设置异步订阅的队列长度
+func QueueSize(n int) SubOption {
+	return func(s *subscription) {
+		if n > 0 {
+			s.queueSize = n
+		}
+	}
+}
+
+// DropWhenFull 异步队列满时丢弃事件（通过 ErrorHandler 报告 ErrQueueFull），默认阻塞发布方直到有空位或 ctx 结束
+func DropWhenFull() SubOption {
+	return func(s *subscription) { s.drop = true }
+}
+
+// Bus 事件总线，零值不可用，需通过 New 创建
+type Bus struct {
+	onError func(topic string, err error)
+
+	mu       sync.RWMutex
+	subs     map[string][]*subscription
+	nextID   uint64
+	closed   bool
+	inflight sync.WaitGroup // 进行中的 Publish
+	workers  sync.WaitGroup // 异步订阅的处理协程
+}
+
+// New 创建事件总线
+func New(opts ...Option) *Bus {
+	b := &Bus{subs: make(map[string][]*subscription)}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// delivery 异步投递的事件
+type delivery struct {
+	ctx     context.Context
+	payload interface{}
+}
+
+// subscription 一个订阅者
+type subscription struct {
+	id        uint64
+	topic     string
+	handler   func(ctx context.Context, payload interface{}) error
+	workers   int // 0 表示同步投递
+	queueSize int
+	drop      bool
+	queue     chan delivery
+	done      chan struct{}
+	once      sync.Once
+}
+
+// Subscribe 订阅主题，返回取消订阅函数
+// 同步订阅在 Publish 调用方的协程中执行，错误会返回给 Publish；异步订阅的错误交给 ErrorHandler
+func Subscribe[T any](b *Bus, topic Topic[T], handler func(ctx context.Context, event T) error, opts ...SubOption) (unsubscribe func()) {
+	s := &subscription{
+		topic:     topic.name,
+		queueSize: defaultQueueSize,
+		done:      make(chan struct{}),
+		handler: func(ctx context.Context, payload interface{}) error {
+			return handler(ctx, payload.(T))
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return func() {}
+	}
+	b.nextID++
+	s.id = b.nextID
+	if s.workers > 0 {
+		s.queue = make(chan delivery, s.queueSize)
+		b.workers.Add(s.workers)
+		for i := 0; i < s.workers; i++ {
+			go b.work(s)
+		}
+	}
+	b.subs[s.topic] = append(b.subs[s.topic], s)
+	b.mu.Unlock()
+
+	return func() { b.unsubscribe(s) }
+}
+
+// Publish 发布事件：同步订阅者依次执行（返回合并后的错误），异步订阅者入队
+// 异步订阅的队列满且未设置 DropWhenFull 时阻塞，ctx 结束则返回 ctx.Err()
+func Publish[T any](ctx context.Context, b *Bus, topic Topic[T], event T) error {
+	return b.publish(ctx, topic.name, event)
+}
+
+// publish 按主题名投递
+func (b *Bus) publish(ctx context.Context, topic string, payload interface{}) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrClosed
+	}
+	subs := append([]*subscription(nil), b.subs[topic]...)
+	b.inflight.Add(1)
+	b.mu.RUnlock()
+	defer b.inflight.Done()
+
+	var errs []error
+	for _, s := range subs {
+		if s.workers == 0 {
+			if err := s.call(ctx, payload); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := b.enqueue(ctx, s, payload); err != nil {
+			if errors.Is(err, ErrQueueFull) {
+				b.reportError(topic, err)
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// enqueue 放入异步订阅的队列；异步处理不受发布方 ctx 取消的影响，但保留其中的值（如 traceId）
+func (b *Bus) enqueue(ctx context.Context, s *subscription, payload interface{}) error {
+	d := delivery{ctx: context.WithoutCancel(ctx), payload: payload}
+	if s.drop {
+		select {
+		case s.queue <- d:
+		case <-s.done:
+		default:
+			return ErrQueueFull
+		}
+		return nil
+	}
+	select {
+	case s.queue <- d:
+		return nil
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work 异步订阅的处理协程，done 关闭后处理完队列中剩余事件再退出
+func (b *Bus) work(s *subscription) {
+	defer b.workers.Done()
+	for {
+		select {
+		case d := <-s.queue:
+			b.handleAsync(s, d)
+		case <-s.done:
+			for {
+				select {
+				case d := <-s.queue:
+					b.handleAsync(s, d)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// handleAsync 执行异步处理并报告错误
+func (b *Bus) handleAsync(s *subscription, d delivery) {
+	if err := s.call(d.ctx, d.payload); err != nil {
+		b.reportError(s.topic, err)
+	}
+}
+
+// call 执行处理函数，panic 转换为 ErrHandlerPanic
+func (s *subscription) call(ctx context.Context, payload interface{}) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%w: topic %s: %v", ErrHandlerPanic, s.topic, p)
+		}
+	}()
+	return s.handler(ctx, payload)
+}
+
+// reportError 调用 ErrorHandler
+func (b *Bus) reportError(topic string, err error) {
+	if b.onError != nil {
+		b.onError(topic, err)
+	}
+}
+
+// unsubscribe 移除订阅，异步订阅会处理完已入队的事件
+func (b *Bus) unsubscribe(s *subscription) {
+	b.mu.Lock()
+	subs := b.subs[s.topic]
+	for i, x := range subs {
+		if x.id == s.id {
+			b.subs[s.topic] = append(subs[:i:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(b.subs[s.topic]) == 0 {
+		delete(b.subs, s.topic)
+	}
+	b.mu.Unlock()
+	s.once.Do(func() { close(s.done) })
+}
+
+// Close 优雅关闭：拒绝新的发布，等待进行中的发布完成，再等待异步队列中的事件处理完毕；
+// ctx 结束时不再等待并返回 ctx.Err()，未处理的事件会在后台继续处理
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	var all []*subscription
+	for _, subs := range b.subs {
+		all = append(all, subs...)
+	}
+	b.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		b.inflight.Wait()
+		for _, s := range all {
+			s.once.Do(func() { close(s.done) })
+		}
+		b.workers.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,174 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderPaid struct {
+	OrderID string `json:"order_id"`
+	Amount  int64  `json:"amount"`
+}
+
+var topicOrderPaid = NewTopic[orderPaid]("order.paid")
+
+func TestSyncAndAsync(t *testing.T) {
+	var asyncErrs []error
+	var mu sync.Mutex
+	b := New(WithErrorHandler(func(topic string, err error) {
+		mu.Lock()
+		asyncErrs = append(asyncErrs, err)
+		mu.Unlock()
+	}))
+
+	var syncGot []string
+	Subscribe(b, topicOrderPaid, func(ctx context.Context, e orderPaid) error {
+		syncGot = append(syncGot, e.OrderID)
+		return nil
+	})
+	var asyncCount int32
+	Subscribe(b, topicOrderPaid, func(ctx context.Context, e orderPaid) error {
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&asyncCount, 1)
+		return nil
+	}, Async(2))
+	Subscribe(b, topicOrderPaid, func(ctx context.Context, e orderPaid) error {
+		panic("boom")
+	}, Async(1))
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, Publish(context.Background(), b, topicOrderPaid, orderPaid{OrderID: "o1"}))
+	}
+	assert.Len(t, syncGot, 10)
+
+	require.NoError(t, b.Close(context.Background()))
+	assert.Equal(t, int32(10), atomic.LoadInt32(&asyncCount))
+	assert.Len(t, asyncErrs, 10)
+	assert.True(t, errors.Is(asyncErrs[0], ErrHandlerPanic))
+	assert.ErrorIs(t, Publish(context.Background(), b, topicOrderPaid, orderPaid{}), ErrClosed)
+}
+
+func TestSyncErrorAndPanic(t *testing.T) {
+	b := New()
+	errBiz := errors.New("biz")
+	Subscribe(b, topicOrderPaid, func(ctx context.Context, e orderPaid) error { return errBiz })
+	unsubscribe := Subscribe(b, topicOrderPaid, func(ctx context.Context, e orderPaid) error { panic("boom") })
+
+	err := Publish(context.Background(), b, topicOrderPaid, orderPaid{})
+	assert.ErrorIs(t, err, errBiz)
+	assert.ErrorIs(t, err, ErrHandlerPanic)
+
+	unsubscribe()
+	err = Publish(context.Background(), b, topicOrderPaid, orderPaid{})
+	assert.ErrorIs(t, err, errBiz)
+	assert.NotErrorIs(t, err, ErrHandlerPanic)
+}
+
+func TestBoundedQueue(t *testing.T) {
+	var dropped int32
+	b := New(WithErrorHandler(func(topic string, err error) {
+		if errors.Is(err, ErrQueueFull) {
+			atomic.AddInt32(&dropped, 1)
+		}
+	}))
+	release := make(chan struct{})
+	handler := func(ctx context.Context, e orderPaid) error { <-release; return nil }
+	Subscribe(b, topicOrderPaid, handler, Async(1), QueueSize(1), DropWhenFull())
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, Publish(context.Background(), b, topicOrderPaid, orderPaid{}))
+	}
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&dropped), int32(3))
+
+	// 阻塞模式下 ctx 超时返回错误
+	blocking := NewTopic[orderPaid]("order.blocking")
+	Subscribe(b, blocking, handler, Async(1), QueueSize(1))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = Publish(ctx, b, blocking, orderPaid{})
+	}
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, b.Close(context.Background()))
+}
+
+func TestCloseTimeout(t *testing.T) {
+	b := New()
+	release := make(chan struct{})
+	defer close(release)
+	Subscribe(b, topicOrderPaid, func(ctx context.Context, e orderPaid) error { <-release; return nil }, Async(1))
+	require.NoError(t, Publish(context.Background(), b, topicOrderPaid, orderPaid{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, b.Close(ctx), context.DeadlineExceeded)
+}
+
+// memTransport 进程内模拟的广播通道
+type memTransport struct {
+	mu   sync.Mutex
+	subs map[string][]func([]byte)
+}
+
+func (m *memTransport) Publish(ctx context.Context, channel string, data []byte) error {
+	m.mu.Lock()
+	subs := append([]func([]byte){}, m.subs[channel]...)
+	m.mu.Unlock()
+	for _, fn := range subs {
+		fn(data)
+	}
+	return nil
+}
+
+func (m *memTransport) Subscribe(ctx context.Context, channel string, fn func([]byte)) error {
+	m.mu.Lock()
+	if m.subs == nil {
+		m.subs = make(map[string][]func([]byte))
+	}
+	m.subs[channel] = append(m.subs[channel], fn)
+	m.mu.Unlock()
+	<-ctx.Done()
+	return nil
+}
+
+func TestBridge(t *testing.T) {
+	tr := &memTransport{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b1, b2 := New(), New()
+	got1 := make(chan orderPaid, 10)
+	got2 := make(chan orderPaid, 10)
+	Subscribe(b1, topicOrderPaid, func(ctx context.Context, e orderPaid) error { got1 <- e; return nil })
+	Subscribe(b2, topicOrderPaid, func(ctx context.Context, e orderPaid) error { got2 <- e; return nil })
+	go Bridge(ctx, b1, topicOrderPaid, tr, "events:")
+	go Bridge(ctx, b2, topicOrderPaid, tr, "events:")
+	assert.Eventually(t, func() bool {
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+		return len(tr.subs["events:order.paid"]) == 2
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, Publish(context.Background(), b1, topicOrderPaid, orderPaid{OrderID: "o1", Amount: 100}))
+	assert.Equal(t, orderPaid{OrderID: "o1", Amount: 100}, <-got1)
+	select {
+	case e := <-got2:
+		assert.Equal(t, "o1", e.OrderID)
+	case <-time.After(time.Second):
+		t.Fatal("expected bridged event")
+	}
+	// 不会回环或重复投递
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, got1, 0)
+	assert.Len(t, got2, 0)
+}
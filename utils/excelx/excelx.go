@@ -0,0 +1,114 @@
+// Package excelx 提供基于结构体标签的 CSV/XLSX 导入导出：
+// 导出按行流式写出，适合大文件；导入逐行校验，出错的行汇总为 Errors 返回而不中断整体导入。
+// 导出时以 = + - @ 开头的文本默认加 ' 前缀防止公式注入（见 WithoutFormulaEscape），超过 15 位的整数写为文本。
+//
+//	type User struct {
+//		Name   string    `excel:"姓名,required"`
+//		Mobile string    `excel:"手机号"`
+//		Birth  time.Time `excel:"出生日期,layout=2006-01-02"`
+//	}
+//
+//	err := excelx.WriteXLSX(w, users)
+//	users, err := excelx.ReadXLSX[User](r)
+package excelx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNoHeader 文件中没有表头行
+	ErrNoHeader = errors.New("excelx: missing header row")
+	// ErrMissingColumn 缺少 required 字段对应的列
+	ErrMissingColumn = errors.New("excelx: missing column")
+	// ErrRequired required 字段的单元格为空
+	ErrRequired = errors.New("required")
+)
+
+// Validator 由导入的结构体实现时，每行解析完成后调用 Validate，返回错误则该行记为无效行
+type Validator interface {
+	Validate() error
+}
+
+// RowError 单行导入错误，Row 为表格中的行号（表头为第 1 行），Column 为出错的列名，整行校验失败时为空
+type RowError struct {
+	Row    int
+	Column string
+	Err    error
+}
+
+// Error 实现 error
+func (e *RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+	}
+	return fmt.Sprintf("row %d column %s: %v", e.Row, e.Column, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *RowError) Unwrap() error { return e.Err }
+
+// Errors 导入过程中的全部行错误，同一行可能包含多个列错误
+type Errors []*RowError
+
+// Error 实现 error，最多列出前 5 个错误
+func (e Errors) Error() string {
+	const max = 5
+	msgs := make([]string, 0, max)
+	for i, re := range e {
+		if i == max {
+			msgs = append(msgs, fmt.Sprintf("and %d more", len(e)-max))
+			break
+		}
+		msgs = append(msgs, re.Error())
+	}
+	return "excelx: " + strings.Join(msgs, "; ")
+}
+
+// Rows 返回出错的行号（去重、升序）
+func (e Errors) Rows() []int {
+	var rows []int
+	for _, re := range e {
+		if len(rows) == 0 || rows[len(rows)-1] != re.Row {
+			rows = append(rows, re.Row)
+		}
+	}
+	return rows
+}
+
+// options 导入导出选项
+type options struct {
+	sheet    string
+	noBOM    bool
+	noEscape bool
+}
+
+// Option 导入导出选项
+type Option func(*options)
+
+// WithSheet 设置 XLSX 工作表名称；导出默认 Sheet1，导入默认第一个工作表
+func WithSheet(name string) Option {
+	return func(o *options) { o.sheet = name }
+}
+
+// WithoutBOM 导出 CSV 时不写入 UTF-8 BOM（默认写入，以便 Excel 正确识别中文）
+func WithoutBOM() Option {
+	return func(o *options) { o.noBOM = true }
+}
+
+// WithoutFormulaEscape 导出时不转义以 = + - @ 等开头的文本。
+// 默认在这类文本前加 ' 防止表格软件将其作为公式执行（CSV 注入），导入时会去掉该前缀；
+// 仅在确认数据可信且需要导出公式时使用
+func WithoutFormulaEscape() Option {
+	return func(o *options) { o.noEscape = true }
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
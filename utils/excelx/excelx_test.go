@@ -0,0 +1,226 @@
+package excelx
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+type Base struct {
+	ID int64 `excel:"编号"`
+}
+
+type user struct {
+	Base
+	Name    string    `excel:"姓名,required"`
+	Age     int       `excel:"年龄"`
+	Balance float64   `excel:"余额"`
+	VIP     bool      `excel:"会员"`
+	Birth   time.Time `excel:"出生日期,layout=2006-01-02"`
+	Email   *string   `excel:"邮箱"`
+	secret  string
+	Note    string `excel:"-"`
+}
+
+func (u *user) Validate() error {
+	if u.Age < 0 {
+		return errors.New("age must not be negative")
+	}
+	return nil
+}
+
+func sampleUsers() []user {
+	email := "a@example.com"
+	return []user{
+		{Base: Base{ID: 1}, Name: "张三", Age: 30, Balance: 12.5, VIP: true, Birth: time.Date(1994, 5, 6, 0, 0, 0, 0, time.Local), Email: &email},
+		{Base: Base{ID: 2}, Name: "李四", Age: 25},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, sampleUsers()))
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, utf8BOM))
+	lines := strings.Split(strings.TrimSpace(strings.TrimPrefix(out, utf8BOM)), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "编号,姓名,年龄,余额,会员,出生日期,邮箱", lines[0])
+	assert.Equal(t, "1,张三,30,12.5,true,1994-05-06,a@example.com", lines[1])
+	assert.Equal(t, "2,李四,25,0,false,,", lines[2])
+
+	buf.Reset()
+	require.NoError(t, WriteCSV(&buf, sampleUsers(), WithoutBOM()))
+	assert.True(t, strings.HasPrefix(buf.String(), "编号,"))
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, sampleUsers()))
+
+	got, err := ReadCSV[user](&buf)
+	require.NoError(t, err)
+	assert.Equal(t, sampleUsers(), got)
+}
+
+func TestReadCSVRowErrors(t *testing.T) {
+	data := "姓名, 年龄 ,会员,备注\n" +
+		"张三,30,是,x\n" +
+		",abc,maybe,\n" +
+		"\n" +
+		"王五,-1,否\n" +
+		"赵六,\"1,000\",Y\n"
+
+	got, err := ReadCSV[user](strings.NewReader(data))
+	var errs Errors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, got, 2)
+	assert.Equal(t, "张三", got[0].Name)
+	assert.True(t, got[0].VIP)
+	assert.Equal(t, 1000, got[1].Age)
+
+	require.Len(t, errs, 4)
+	assert.Equal(t, []int{3, 5}, errs.Rows())
+	assert.Equal(t, "姓名", errs[0].Column)
+	assert.ErrorIs(t, errs[0], ErrRequired)
+	assert.Equal(t, "年龄", errs[1].Column)
+	assert.Equal(t, "会员", errs[2].Column)
+	assert.Equal(t, 5, errs[3].Row)
+	assert.Empty(t, errs[3].Column)
+	assert.Contains(t, err.Error(), "row 5: age must not be negative")
+}
+
+func TestEachCSV(t *testing.T) {
+	data := "姓名,年龄\n张三,1\n李四,2\n王五,3\n"
+	var rows []int
+	stop := errors.New("stop")
+	err := EachCSV(strings.NewReader(data), func(row int, v user) error {
+		rows = append(rows, row)
+		if v.Name == "李四" {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []int{2, 3}, rows)
+}
+
+func TestReadCSVHeader(t *testing.T) {
+	_, err := ReadCSV[user](strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrNoHeader)
+
+	_, err = ReadCSV[user](strings.NewReader("年龄\n1\n"))
+	assert.ErrorIs(t, err, ErrMissingColumn)
+	assert.Contains(t, err.Error(), "姓名")
+}
+
+func TestXLSXRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteXLSX(&buf, sampleUsers(), WithSheet("用户")))
+
+	f, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"用户"}, f.GetSheetList())
+	v, err := f.GetCellValue("用户", "B2")
+	require.NoError(t, err)
+	assert.Equal(t, "张三", v)
+	require.NoError(t, f.Close())
+
+	got, err := ReadXLSX[user](bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, sampleUsers(), got)
+}
+
+func TestReadXLSXDateCell(t *testing.T) {
+	f := excelize.NewFile()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"姓名", "出生日期"}))
+	require.NoError(t, f.SetCellValue("Sheet1", "A2", "张三"))
+	require.NoError(t, f.SetCellValue("Sheet1", "B2", time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)))
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+	require.NoError(t, f.Close())
+
+	got, err := ReadXLSX[user](&buf)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, time.Date(2000, 1, 2, 0, 0, 0, 0, time.Local), got[0].Birth)
+}
+
+func TestXLSXWriterStreaming(t *testing.T) {
+	var buf bytes.Buffer
+	xw, err := NewXLSXWriter[user](&buf)
+	require.NoError(t, err)
+	for i := 0; i < 5000; i++ {
+		require.NoError(t, xw.Write(user{Base: Base{ID: int64(i)}, Name: "u"}))
+	}
+	require.NoError(t, xw.Close())
+
+	n := 0
+	err = EachXLSX(&buf, func(row int, v user) error {
+		assert.Equal(t, int64(row-2), v.ID)
+		n++
+		return nil
+	}, WithSheet("Sheet1"))
+	require.NoError(t, err)
+	assert.Equal(t, 5000, n)
+}
+
+// ptrCode 仅指针接收者实现 TextMarshaler
+type ptrCode struct{ v string }
+
+func (c *ptrCode) MarshalText() ([]byte, error) { return []byte("code-" + c.v), nil }
+
+type exportRow struct {
+	Name  string  `excel:"名称"`
+	Big   int64   `excel:"大整数"`
+	UBig  uint64  `excel:"无符号"`
+	Small int64   `excel:"小整数"`
+	Code  ptrCode `excel:"编码"`
+}
+
+func TestExportEscapingAndPrecision(t *testing.T) {
+	rows := []exportRow{{Name: "=HYPERLINK(\"http://x\")", Big: 1234567890123456789, UBig: 18446744073709551615, Small: -5, Code: ptrCode{v: "a"}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, rows, WithoutBOM()))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `"'=HYPERLINK(""http://x"")",1234567890123456789,18446744073709551615,-5,code-a`, lines[1])
+
+	buf.Reset()
+	require.NoError(t, WriteCSV(&buf, rows, WithoutBOM(), WithoutFormulaEscape()))
+	assert.Contains(t, buf.String(), "\n\"=HYPERLINK(")
+
+	buf.Reset()
+	require.NoError(t, WriteXLSX(&buf, rows))
+	f, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer f.Close()
+	for cell, want := range map[string]excelize.CellType{"B2": excelize.CellTypeInlineString, "C2": excelize.CellTypeInlineString, "D2": excelize.CellTypeUnset} {
+		typ, err := f.GetCellType("Sheet1", cell)
+		require.NoError(t, err)
+		assert.Equal(t, want, typ, cell)
+	}
+	v, err := f.GetCellValue("Sheet1", "A2")
+	require.NoError(t, err)
+	assert.Equal(t, `'=HYPERLINK("http://x")`, v)
+	v, err = f.GetCellValue("Sheet1", "B2")
+	require.NoError(t, err)
+	assert.Equal(t, "1234567890123456789", v)
+}
+
+func TestImportUnescapesFormula(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, []user{{Name: "-张三"}, {Name: "'普通"}}))
+	got, err := ReadCSV[user](&buf)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "-张三", got[0].Name)
+	assert.Equal(t, "'普通", got[1].Name)
+}
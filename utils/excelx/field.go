@@ -0,0 +1,304 @@
+package excelx
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// defaultTimeLayout 导出时间的默认格式
+const defaultTimeLayout = "2006-01-02 15:04:05"
+
+// importTimeLayouts 导入时未指定 layout 时依次尝试的时间格式
+var importTimeLayouts = []string{
+	"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02", "2006/01/02 15:04:05", "2006/01/02", "2006/1/2", time.RFC3339,
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// field 一列的定义，由结构体字段与 excel 标签得出
+type field struct {
+	header   string
+	index    []int
+	layout   string // 时间格式
+	required bool
+}
+
+// fieldCache 按类型缓存列定义
+var fieldCache sync.Map
+
+// fieldsOf 解析结构体的列定义：
+//
+//	Name  string    `excel:"姓名,required"`
+//	Birth time.Time `excel:"出生日期,layout=2006-01-02"`
+//	Note  string    `excel:"-"`
+//
+// 未设置标签的导出字段以字段名为表头，匿名结构体字段会被展开
+func fieldsOf(t reflect.Type) ([]field, error) {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.([]field), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("excelx: %s is not a struct", t)
+	}
+	var fields []field
+	collectFields(t, nil, &fields)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("excelx: %s has no exported fields", t)
+	}
+	fieldCache.Store(t, fields)
+	return fields, nil
+}
+
+func collectFields(t reflect.Type, parent []int, out *[]field) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("excel")
+		if tag == "-" {
+			continue
+		}
+		index := append(append([]int(nil), parent...), i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct && tag == "" {
+			collectFields(sf.Type, index, out)
+			continue
+		}
+		f := field{header: sf.Name, index: index}
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			f.header = parts[0]
+		}
+		for _, opt := range parts[1:] {
+			switch {
+			case opt == "required":
+				f.required = true
+			case strings.HasPrefix(opt, "layout="):
+				f.layout = strings.TrimPrefix(opt, "layout=")
+			}
+		}
+		*out = append(*out, f)
+	}
+}
+
+// headers 返回表头
+func headers(fields []field) []string {
+	out := make([]string, len(fields))
+	for i, f := range fields {
+		out[i] = f.header
+	}
+	return out
+}
+
+// maxExactInt Excel 数值只有 15 位有效数字，绝对值超过该值的整数导出为文本，避免精度丢失
+const maxExactInt = 999_999_999_999_999
+
+// formulaPrefixes 表格软件会作为公式解析的起始字符
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula 在可能被解析为公式的文本前加 '
+func escapeFormula(s string) string {
+	if s != "" && strings.IndexByte(formulaPrefixes, s[0]) >= 0 {
+		return "'" + s
+	}
+	return s
+}
+
+// unescapeFormula 去掉 escapeFormula 添加的 '
+func unescapeFormula(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.IndexByte(formulaPrefixes, s[1]) >= 0 {
+		return s[1:]
+	}
+	return s
+}
+
+// exportValue 返回导出单元格的值，escape 为 true 时转义文本中的公式
+func exportValue(f field, v reflect.Value, escape bool) interface{} {
+	x := cellValue(f, v)
+	if s, ok := x.(string); ok && escape {
+		return escapeFormula(s)
+	}
+	return x
+}
+
+// cellValue 返回导出单元格的值：数字与布尔保持原类型（XLSX 中为数值单元格），超过 15 位的整数及其余类型转换为字符串
+func cellValue(f field, v reflect.Value) interface{} {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		layout := f.layout
+		if layout == "" {
+			layout = defaultTimeLayout
+		}
+		return t.Format(layout)
+	}
+	if v.Type().Implements(textMarshalerType) || reflect.PointerTo(v.Type()).Implements(textMarshalerType) {
+		if !v.CanAddr() {
+			// 指针接收者的方法需要可寻址的值
+			pv := reflect.New(v.Type())
+			pv.Elem().Set(v)
+			v = pv.Elem()
+		}
+		tm, ok := v.Interface().(encoding.TextMarshaler)
+		if !ok {
+			tm = v.Addr().Interface().(encoding.TextMarshaler)
+		}
+		b, err := tm.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := v.Int(); n > maxExactInt || n < -maxExactInt {
+			return strconv.FormatInt(n, 10)
+		}
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n := v.Uint(); n > maxExactInt {
+			return strconv.FormatUint(n, 10)
+		}
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// formatCell 将单元格值转换为 CSV 文本
+func formatCell(x interface{}) string {
+	switch v := x.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(x)
+}
+
+// setCell 将单元格文本解析到字段，空文本保留零值（指针为 nil）
+func setCell(f field, v reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		if f.required {
+			return ErrRequired
+		}
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if v.Type() == timeType {
+		t, err := parseTime(s, f.layout)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(unescapeFormula(s))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.ReplaceAll(s, ",", ""), 10, v.Type().Bits())
+		if err != nil {
+			// Excel 中的整数可能被保存为 1.0 这样的浮点文本
+			fv, ferr := strconv.ParseFloat(s, 64)
+			if ferr != nil || fv != float64(int64(fv)) {
+				return fmt.Errorf("invalid integer %q", s)
+			}
+			n = int64(fv)
+		}
+		if v.OverflowInt(n) {
+			return fmt.Errorf("integer %q out of range", s)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.ReplaceAll(s, ",", ""), 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := parseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+// parseBool 解析布尔值，支持 true/false、1/0、是/否、Y/N
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "1", "是", "y", "yes":
+		return true, nil
+	case "false", "0", "否", "n", "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+// parseTime 按指定格式或常见格式解析时间（本地时区），也接受 XLSX 日期单元格的序列号
+func parseTime(s, layout string) (time.Time, error) {
+	layouts := importTimeLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+	for _, l := range layouts {
+		if t, err := time.ParseInLocation(l, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(s, 64); err == nil && serial > 0 {
+		if t, err := excelize.ExcelDateToTime(serial, false); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
@@ -0,0 +1,225 @@
+package excelx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// rowSource 逐行读取表格，返回行数据与其在表格中的行号，读完返回 io.EOF
+type rowSource interface {
+	next() ([]string, int, error)
+}
+
+// csvSource CSV 行来源，容忍不规范的引号与不一致的列数
+type csvSource struct {
+	r *csv.Reader
+}
+
+func newCSVSource(r io.Reader) *csvSource {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	return &csvSource{r: cr}
+}
+
+func (s *csvSource) next() ([]string, int, error) {
+	record, err := s.r.Read()
+	if err != nil {
+		return nil, 0, err
+	}
+	// csv.Reader 会跳过空行，行号取字段起始位置所在的行
+	line, _ := s.r.FieldPos(0)
+	return record, line, nil
+}
+
+// xlsxSource XLSX 行来源，单元格取原始值（日期为 Excel 序列号，由 parseTime 转换）
+type xlsxSource struct {
+	file *excelize.File
+	rows *excelize.Rows
+	row  int
+}
+
+func newXLSXSource(r io.Reader, sheet string) (*xlsxSource, error) {
+	f, err := excelize.OpenReader(r, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("excelx: %w", err)
+	}
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("excelx: %w", err)
+	}
+	return &xlsxSource{file: f, rows: rows}, nil
+}
+
+func (s *xlsxSource) next() ([]string, int, error) {
+	if !s.rows.Next() {
+		if err := s.rows.Error(); err != nil {
+			return nil, 0, err
+		}
+		return nil, 0, io.EOF
+	}
+	s.row++
+	record, err := s.rows.Columns()
+	return record, s.row, err
+}
+
+func (s *xlsxSource) close() {
+	s.rows.Close()
+	s.file.Close()
+}
+
+// each 按表头匹配列并逐行解析，有效行交给 fn；行错误汇总为 Errors，fn 返回错误时立即中止
+func each[T any](src rowSource, fn func(row int, v T) error) error {
+	fields, err := fieldsOf(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return err
+	}
+	header, _, err := src.next()
+	if errors.Is(err, io.EOF) {
+		return ErrNoHeader
+	}
+	if err != nil {
+		return fmt.Errorf("excelx: %w", err)
+	}
+	columns, err := matchColumns(fields, header)
+	if err != nil {
+		return err
+	}
+
+	var errs Errors
+	for {
+		record, row, err := src.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("excelx: %w", err)
+		}
+		if blank(record) {
+			continue
+		}
+		var v T
+		rv := reflect.ValueOf(&v).Elem()
+		valid := true
+		for i, f := range fields {
+			cell := ""
+			if c := columns[i]; c >= 0 && c < len(record) {
+				cell = record[c]
+			}
+			if err := setCell(f, rv.FieldByIndex(f.index), cell); err != nil {
+				errs = append(errs, &RowError{Row: row, Column: f.header, Err: err})
+				valid = false
+			}
+		}
+		if valid {
+			if vd, ok := any(&v).(Validator); ok {
+				if err := vd.Validate(); err != nil {
+					errs = append(errs, &RowError{Row: row, Err: err})
+					valid = false
+				}
+			}
+		}
+		if !valid {
+			continue
+		}
+		if err := fn(row, v); err != nil {
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// matchColumns 返回每个字段对应的列下标，未找到的列为 -1；缺少 required 列时报错
+func matchColumns(fields []field, header []string) ([]int, error) {
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		h = strings.TrimSpace(strings.TrimPrefix(h, utf8BOM))
+		if _, ok := index[h]; !ok {
+			index[h] = i
+		}
+	}
+	columns := make([]int, len(fields))
+	var missing []string
+	for i, f := range fields {
+		c, ok := index[f.header]
+		if !ok {
+			c = -1
+			if f.required {
+				missing = append(missing, f.header)
+			}
+		}
+		columns[i] = c
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingColumn, strings.Join(missing, ", "))
+	}
+	return columns, nil
+}
+
+// blank 判断是否为空行
+func blank(record []string) bool {
+	for _, s := range record {
+		if strings.TrimSpace(s) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// collect 读取全部有效行；存在行错误时同时返回有效行与 Errors
+func collect[T any](src rowSource) ([]T, error) {
+	var out []T
+	err := each(src, func(_ int, v T) error {
+		out = append(out, v)
+		return nil
+	})
+	var errs Errors
+	if err != nil && !errors.As(err, &errs) {
+		return nil, err
+	}
+	return out, err
+}
+
+// EachCSV 流式读取 CSV，每个有效行调用一次 fn，row 为表格行号；
+// 全部读完后若存在无效行返回 Errors，fn 返回错误时立即中止并返回该错误
+func EachCSV[T any](r io.Reader, fn func(row int, v T) error) error {
+	return each(newCSVSource(r), fn)
+}
+
+// ReadCSV 读取 CSV 中的全部有效行，存在无效行时同时返回有效行与 Errors
+func ReadCSV[T any](r io.Reader) ([]T, error) {
+	return collect[T](newCSVSource(r))
+}
+
+// EachXLSX 逐行读取 XLSX 工作表，语义同 EachCSV；可用 WithSheet 指定工作表
+func EachXLSX[T any](r io.Reader, fn func(row int, v T) error, opts ...Option) error {
+	src, err := newXLSXSource(r, newOptions(opts).sheet)
+	if err != nil {
+		return err
+	}
+	defer src.close()
+	return each(src, fn)
+}
+
+// ReadXLSX 读取 XLSX 工作表中的全部有效行，存在无效行时同时返回有效行与 Errors
+func ReadXLSX[T any](r io.Reader, opts ...Option) ([]T, error) {
+	src, err := newXLSXSource(r, newOptions(opts).sheet)
+	if err != nil {
+		return nil, err
+	}
+	defer src.close()
+	return collect[T](src)
+}
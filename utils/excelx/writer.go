@@ -0,0 +1,180 @@
+package excelx
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// utf8BOM 写在 CSV 开头，Excel 据此按 UTF-8 打开
+const utf8BOM = "\xef\xbb\xbf"
+
+// CSVWriter 按行流式写出 CSV，创建时写入表头
+type CSVWriter[T any] struct {
+	w      *csv.Writer
+	fields []field
+	record []string
+	escape bool
+}
+
+// NewCSVWriter 创建 CSV 写出器，默认写入 UTF-8 BOM
+func NewCSVWriter[T any](w io.Writer, opts ...Option) (*CSVWriter[T], error) {
+	fields, err := fieldsOf(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	o := newOptions(opts)
+	if !o.noBOM {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return nil, fmt.Errorf("excelx: %w", err)
+		}
+	}
+	cw := &CSVWriter[T]{w: csv.NewWriter(w), fields: fields, record: make([]string, len(fields)), escape: !o.noEscape}
+	if err := cw.w.Write(headers(fields)); err != nil {
+		return nil, fmt.Errorf("excelx: %w", err)
+	}
+	return cw, nil
+}
+
+// Write 写出一行
+func (cw *CSVWriter[T]) Write(v T) error {
+	rv := reflect.ValueOf(&v).Elem()
+	for i, f := range cw.fields {
+		cw.record[i] = formatCell(exportValue(f, rv.FieldByIndex(f.index), cw.escape))
+	}
+	if err := cw.w.Write(cw.record); err != nil {
+		return fmt.Errorf("excelx: %w", err)
+	}
+	return nil
+}
+
+// Flush 将缓冲数据写入底层 io.Writer
+func (cw *CSVWriter[T]) Flush() error {
+	cw.w.Flush()
+	if err := cw.w.Error(); err != nil {
+		return fmt.Errorf("excelx: %w", err)
+	}
+	return nil
+}
+
+// Close 刷新缓冲，不关闭底层 io.Writer
+func (cw *CSVWriter[T]) Close() error {
+	return cw.Flush()
+}
+
+// XLSXWriter 按行流式写出 XLSX，行数据超过内存阈值后暂存到临时文件，Close 时写出完整文件
+type XLSXWriter[T any] struct {
+	w      io.Writer
+	file   *excelize.File
+	sw     *excelize.StreamWriter
+	fields []field
+	row    int
+	values []interface{}
+	escape bool
+}
+
+// NewXLSXWriter 创建 XLSX 写出器，表头加粗并冻结首行
+func NewXLSXWriter[T any](w io.Writer, opts ...Option) (*XLSXWriter[T], error) {
+	fields, err := fieldsOf(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	o := newOptions(opts)
+	f := excelize.NewFile()
+	sheet := "Sheet1"
+	if o.sheet != "" && o.sheet != sheet {
+		if err := f.SetSheetName(sheet, o.sheet); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("excelx: %w", err)
+		}
+		sheet = o.sheet
+	}
+	xw := &XLSXWriter[T]{w: w, file: f, fields: fields, values: make([]interface{}, len(fields)), escape: !o.noEscape}
+	if err := xw.init(sheet); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("excelx: %w", err)
+	}
+	return xw, nil
+}
+
+func (xw *XLSXWriter[T]) init(sheet string) error {
+	sw, err := xw.file.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	xw.sw = sw
+	style, err := xw.file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	if err := sw.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return err
+	}
+	header := make([]interface{}, len(xw.fields))
+	for i, f := range xw.fields {
+		header[i] = excelize.Cell{StyleID: style, Value: f.header}
+	}
+	xw.row = 1
+	return sw.SetRow("A1", header)
+}
+
+// Write 写出一行，数字与布尔写为对应类型的单元格，超过 15 位的整数写为文本
+func (xw *XLSXWriter[T]) Write(v T) error {
+	rv := reflect.ValueOf(&v).Elem()
+	for i, f := range xw.fields {
+		xw.values[i] = exportValue(f, rv.FieldByIndex(f.index), xw.escape)
+	}
+	xw.row++
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return fmt.Errorf("excelx: %w", err)
+	}
+	if err := xw.sw.SetRow(cell, xw.values); err != nil {
+		return fmt.Errorf("excelx: %w", err)
+	}
+	return nil
+}
+
+// Close 结束写入，将完整文件写出到 io.Writer 并清理临时文件
+func (xw *XLSXWriter[T]) Close() error {
+	defer xw.file.Close()
+	if err := xw.sw.Flush(); err != nil {
+		return fmt.Errorf("excelx: %w", err)
+	}
+	if err := xw.file.Write(xw.w); err != nil {
+		return fmt.Errorf("excelx: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV 将切片写出为 CSV
+func WriteCSV[T any](w io.Writer, rows []T, opts ...Option) error {
+	cw, err := NewCSVWriter[T](w, opts...)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	return cw.Close()
+}
+
+// WriteXLSX 将切片写出为 XLSX
+func WriteXLSX[T any](w io.Writer, rows []T, opts ...Option) error {
+	xw, err := NewXLSXWriter[T](w, opts...)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := xw.Write(row); err != nil {
+			xw.file.Close()
+			return err
+		}
+	}
+	return xw.Close()
+}
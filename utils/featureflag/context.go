@@ -0,0 +1,49 @@
+package featureflag
+
+import (
+	"context"
+
+	"github.com/qingfeng-studio/go-utils/utils/ctxx"
+)
+
+type attrsKey struct{}
+
+// WithUser 将用户 ID 写入 context，供灰度分桶与白名单判断，等同于 ctxx.WithUserID
+func WithUser(ctx context.Context, userID string) context.Context {
+	return ctxx.WithUserID(ctx, userID)
+}
+
+// UserFromContext 读取 context 中的用户 ID（ctxx.WithUserID 或 WithUser 写入）
+func UserFromContext(ctx context.Context) string {
+	v, _ := ctxx.UserID(ctx)
+	return v
+}
+
+// WithTenant 将租户 ID 写入 context，等同于 ctxx.WithTenantID
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return ctxx.WithTenantID(ctx, tenantID)
+}
+
+// TenantFromContext 读取 context 中的租户 ID（ctxx.WithTenantID 或 WithTenant 写入）
+func TenantFromContext(ctx context.Context) string {
+	v, _ := ctxx.TenantID(ctx)
+	return v
+}
+
+// WithAttributes 写入规则表达式可引用的属性（如 region、app_version），与已有属性合并，同名覆盖
+func WithAttributes(ctx context.Context, attrs map[string]string) context.Context {
+	merged := make(map[string]string, len(attrs))
+	for k, v := range attributesFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range attrs {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, attrsKey{}, merged)
+}
+
+// attributesFromContext 读取规则属性
+func attributesFromContext(ctx context.Context) map[string]string {
+	v, _ := ctx.Value(attrsKey{}).(map[string]string)
+	return v
+}
@@ -0,0 +1,446 @@
+package featureflag
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// env 规则求值时可访问的变量：user、tenant 以及通过 WithAttributes 传入的属性
+type env struct {
+	user   string
+	tenant string
+	attrs  map[string]string
+}
+
+// lookup 读取变量，不存在时为空字符串
+func (e *env) lookup(name string) string {
+	switch name {
+	case "user":
+		return e.user
+	case "tenant":
+		return e.tenant
+	}
+	return e.attrs[name]
+}
+
+// node 规则表达式语法树节点
+type node interface {
+	eval(e *env) interface{}
+}
+
+type (
+	literal struct{ v interface{} }
+	ident   struct{ name string }
+	notNode struct{ x node }
+	logical struct {
+		op   string
+		l, r node
+	}
+	compare struct {
+		op   string
+		l, r node
+	}
+	inList struct {
+		x     node
+		items []interface{}
+	}
+)
+
+// numLit 数字字面量，保留原文以便按版本号比较（3.10 与 3.1 不同）
+type numLit struct {
+	f    float64
+	text string
+}
+
+func (n numLit) String() string { return n.text }
+
+func (n literal) eval(*env) interface{}   { return n.v }
+func (n ident) eval(e *env) interface{}   { return e.lookup(n.name) }
+func (n notNode) eval(e *env) interface{} { return !truthy(n.x.eval(e)) }
+
+func (n logical) eval(e *env) interface{} {
+	if n.op == "&&" {
+		return truthy(n.l.eval(e)) && truthy(n.r.eval(e))
+	}
+	return truthy(n.l.eval(e)) || truthy(n.r.eval(e))
+}
+
+func (n compare) eval(e *env) interface{} {
+	l, r := n.l.eval(e), n.r.eval(e)
+	switch n.op {
+	case "==":
+		return equal(l, r)
+	case "!=":
+		return !equal(l, r)
+	}
+	c, ok := order(l, r)
+	if !ok {
+		return false
+	}
+	switch n.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+func (n inList) eval(e *env) interface{} {
+	v := n.x.eval(e)
+	for _, item := range n.items {
+		if equal(v, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// truthy 非布尔值的真假：非空字符串、非零数字为真
+func truthy(v interface{}) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case numLit:
+		return t.f != 0
+	}
+	return false
+}
+
+// equal 两侧均为数字字面量时按数值比较，否则按字符串比较；
+// 属性值均为字符串，因此 "001" 与 1 不相等
+func equal(a, b interface{}) bool {
+	if x, ok := a.(numLit); ok {
+		if y, ok := b.(numLit); ok {
+			return x.f == y.f
+		}
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// order 比较大小：两侧均为点分数字（如 3.2、3.10.1）时按版本号逐段比较整数，
+// 否则按浮点数比较；无法比较时返回 false
+func order(a, b interface{}) (int, bool) {
+	if x, ok := versionSegments(a); ok {
+		if y, ok := versionSegments(b); ok {
+			return compareVersion(x, y), true
+		}
+	}
+	x, ok1 := number(a)
+	y, ok2 := number(b)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	}
+	return 0, true
+}
+
+// versionSegments 把点分数字拆成各段，每段去掉前导零；不是点分数字时返回 false
+func versionSegments(v interface{}) ([]string, bool) {
+	var s string
+	switch t := v.(type) {
+	case string:
+		s = t
+	case numLit:
+		s = t.text
+	default:
+		return nil, false
+	}
+	segs := strings.Split(s, ".")
+	for i, seg := range segs {
+		if seg == "" || strings.Trim(seg, "0123456789") != "" {
+			return nil, false
+		}
+		if segs[i] = strings.TrimLeft(seg, "0"); segs[i] == "" {
+			segs[i] = "0"
+		}
+	}
+	return segs, true
+}
+
+// compareVersion 逐段按整数比较，缺少的段视为 0，各段按长度再按字典序比较以避免溢出
+func compareVersion(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		x, y := "0", "0"
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if len(x) != len(y) {
+			if len(x) < len(y) {
+				return -1
+			}
+			return 1
+		}
+		if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// number 转换为数字，属性值均为字符串，比较大小时需要解析
+func number(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case numLit:
+		return t.f, true
+	case string:
+		f, err := strconv.ParseFloat(t, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// compileRule 解析规则表达式，支持：
+// 变量（user、tenant、属性名，属性名可含 . 和 -）、字符串、数字、true/false，
+// == != < <= > >=、in [..]、&& || !、括号。点分数字按版本号比较（3.10 > 3.2），例如：
+//
+//	tenant in ["t1", "t2"] || (region == "cn" && app_version >= 3.2)
+func compileRule(src string) (node, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	return n, nil
+}
+
+// token 词法单元
+type token struct {
+	kind byte // i 标识符, s 字符串, n 数字, o 运算符/标点
+	text string
+	num  numLit
+}
+
+// tokenize 词法分析
+func tokenize(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			var sb strings.Builder
+			for ; j < len(src) && src[j] != c; j++ {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+				}
+				sb.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			toks = append(toks, token{kind: 's', text: sb.String()})
+			i = j + 1
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			text := src[i:j]
+			f, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				// 3.10.1 这类多段版本号不是合法浮点数，仅用于版本比较
+				if _, ok := versionSegments(text); !ok {
+					return nil, fmt.Errorf("invalid number %q", text)
+				}
+				f = math.NaN()
+			}
+			toks = append(toks, token{kind: 'n', text: text, num: numLit{f: f, text: text}})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] == '.' || src[j] == '-' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			toks = append(toks, token{kind: 'i', text: src[i:j]})
+			i = j
+		default:
+			if i+1 < len(src) {
+				switch two := src[i : i+2]; two {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					toks = append(toks, token{kind: 'o', text: two})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("<>!()[],", rune(c)) {
+				return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+			}
+			toks = append(toks, token{kind: 'o', text: string(c)})
+			i++
+		}
+	}
+	return toks, nil
+}
+
+// parser 递归下降语法分析
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos], true
+	}
+	return token{}, false
+}
+
+// accept 下一个单元是运算符 op 时消费并返回 true
+func (p *parser) accept(op string) bool {
+	if t, ok := p.peek(); ok && t.kind == 'o' && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = logical{op: "||", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = logical{op: "&&", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("!") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x: x}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	l, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.peek(); ok && t.kind == 'i' && t.text == "in" {
+		p.pos++
+		items, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return inList{x: l, items: items}, nil
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			r, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return compare{op: op, l: l, r: r}, nil
+		}
+	}
+	return l, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of rule")
+	}
+	if p.accept("(") {
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		return n, nil
+	}
+	p.pos++
+	switch t.kind {
+	case 's':
+		return literal{v: t.text}, nil
+	case 'n':
+		return literal{v: t.num}, nil
+	case 'i':
+		switch t.text {
+		case "true":
+			return literal{v: true}, nil
+		case "false":
+			return literal{v: false}, nil
+		}
+		return ident{name: t.text}, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+func (p *parser) parseList() ([]interface{}, error) {
+	if !p.accept("[") {
+		return nil, fmt.Errorf("expected [ after in")
+	}
+	var items []interface{}
+	for !p.accept("]") {
+		if len(items) > 0 && !p.accept(",") {
+			return nil, fmt.Errorf("expected , in list")
+		}
+		t, ok := p.peek()
+		if !ok {
+			return nil, fmt.Errorf("missing ]")
+		}
+		p.pos++
+		switch t.kind {
+		case 's':
+			items = append(items, t.text)
+		case 'n':
+			items = append(items, t.num)
+		default:
+			return nil, fmt.Errorf("list items must be literals, got %q", t.text)
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,177 @@
+// Package featureflag 功能开关：按配置启用功能，支持百分比灰度、用户/租户白名单与表达式规则，可随配置文件热更新
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync/atomic"
+
+	"github.com/qingfeng-studio/go-utils/config"
+)
+
+// Flag 单个开关的配置
+// 判定顺序：Enabled 为 false 时关闭；命中 Users/Tenants 白名单或 Rule 为真时开启；
+// 否则按 Percentage 灰度；Users/Tenants/Rule/Percentage 均未配置时对所有人开启
+type Flag struct {
+	Enabled    bool     `yaml:"enabled" json:"enabled"`       // 总开关
+	Percentage float64  `yaml:"percentage" json:"percentage"` // 灰度百分比 0~100，按用户 ID（无用户时按租户 ID）哈希分桶，同一用户结果稳定
+	Users      []string `yaml:"users" json:"users"`           // 用户白名单
+	Tenants    []string `yaml:"tenants" json:"tenants"`       // 租户白名单
+	Rule       string   `yaml:"rule" json:"rule"`             // 表达式规则，如 `region == "cn" && app_version >= 3.2`
+}
+
+// Config 开关配置，通常作为应用配置的一个小节：
+//
+//	featureflags:
+//	  flags:
+//	    new_checkout:
+//	      enabled: true
+//	      percentage: 20
+//	      tenants: ["t_internal"]
+type Config struct {
+	Flags map[string]Flag `yaml:"flags" json:"flags"`
+}
+
+// compiledFlag 预处理后的开关
+type compiledFlag struct {
+	Flag
+	users   map[string]bool
+	tenants map[string]bool
+	rule    node
+}
+
+// Option Manager 选项
+type Option func(*Manager)
+
+// WithUserResolver 自定义从 context 读取用户 ID 的方式（如从 JWT claims 中读取），默认读取 WithUser 写入的值
+func WithUserResolver(fn func(ctx context.Context) string) Option {
+	return func(m *Manager) { m.userFn = fn }
+}
+
+// WithTenantResolver 自定义从 context 读取租户 ID 的方式，默认读取 WithTenant 写入的值
+func WithTenantResolver(fn func(ctx context.Context) string) Option {
+	return func(m *Manager) { m.tenantFn = fn }
+}
+
+// Manager 功能开关管理器，并发安全，Update 可在运行期间原子替换全部开关
+type Manager struct {
+	flags    atomic.Pointer[map[string]*compiledFlag]
+	userFn   func(ctx context.Context) string
+	tenantFn func(ctx context.Context) string
+}
+
+// New 创建开关管理器，规则表达式有语法错误时返回错误
+func New(cfg Config, opts ...Option) (*Manager, error) {
+	m := &Manager{userFn: UserFromContext, tenantFn: TenantFromContext}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if err := m.Update(cfg); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Update 替换全部开关配置；任一规则解析失败时返回错误并保留原配置
+func (m *Manager) Update(cfg Config) error {
+	flags := make(map[string]*compiledFlag, len(cfg.Flags))
+	for name, f := range cfg.Flags {
+		cf := &compiledFlag{Flag: f, users: toSet(f.Users), tenants: toSet(f.Tenants)}
+		if f.Rule != "" {
+			rule, err := compileRule(f.Rule)
+			if err != nil {
+				return fmt.Errorf("featureflag: flag %q: %w", name, err)
+			}
+			cf.rule = rule
+		}
+		flags[name] = cf
+	}
+	m.flags.Store(&flags)
+	return nil
+}
+
+// Names 返回已配置的开关名称（已排序）
+func (m *Manager) Names() []string {
+	flags := *m.flags.Load()
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsEnabled 判断开关对当前请求是否开启，用户/租户 ID 与规则属性从 ctx 读取，未配置的开关返回 false
+func (m *Manager) IsEnabled(ctx context.Context, name string) bool {
+	f, ok := (*m.flags.Load())[name]
+	if !ok || !f.Enabled {
+		return false
+	}
+	e := &env{user: m.userFn(ctx), tenant: m.tenantFn(ctx), attrs: attributesFromContext(ctx)}
+	if e.user != "" && f.users[e.user] {
+		return true
+	}
+	if e.tenant != "" && f.tenants[e.tenant] {
+		return true
+	}
+	if f.rule != nil && truthy(f.rule.eval(e)) {
+		return true
+	}
+	if f.Percentage > 0 {
+		key := e.user
+		if key == "" {
+			key = e.tenant
+		}
+		return f.Percentage >= 100 || (key != "" && bucket(name, key) < f.Percentage*100)
+	}
+	return len(f.users) == 0 && len(f.tenants) == 0 && f.rule == nil
+}
+
+// Watch 将开关与配置 Watcher 绑定：立即应用当前配置，之后配置文件变化时自动更新；
+// pick 从应用配置中取出开关小节，更新失败（规则语法错误）时保留原配置并调用 onError（可为 nil）
+func Watch[T any](m *Manager, w *config.Watcher[T], pick func(cfg *T) Config, onError func(error)) error {
+	if err := m.Update(pick(w.Current())); err != nil {
+		return err
+	}
+	w.OnReload(func(_, cfg *T) {
+		if err := m.Update(pick(cfg)); err != nil && onError != nil {
+			onError(err)
+		}
+	})
+	return nil
+}
+
+// bucket 计算用户在某开关下的分桶（0~9999），不同开关的分桶相互独立
+func bucket(flag, key string) float64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(flag))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(key))
+	return float64(h.Sum32() % 10000)
+}
+
+// toSet 切片转集合
+func toSet(items []string) map[string]bool {
+	if len(items) == 0 {
+		return nil
+	}
+	s := make(map[string]bool, len(items))
+	for _, v := range items {
+		s[v] = true
+	}
+	return s
+}
+
+// std 包级默认管理器
+var std atomic.Pointer[Manager]
+
+// SetDefault 设置包级默认管理器，供 IsEnabled 使用
+func SetDefault(m *Manager) { std.Store(m) }
+
+// IsEnabled 使用默认管理器判断开关是否开启，未设置默认管理器时返回 false
+func IsEnabled(ctx context.Context, name string) bool {
+	m := std.Load()
+	return m != nil && m.IsEnabled(ctx, name)
+}
@@ -0,0 +1,120 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qingfeng-studio/go-utils/config"
+	"github.com/qingfeng-studio/go-utils/utils/ctxx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsEnabled(t *testing.T) {
+	m, err := New(Config{Flags: map[string]Flag{
+		"on":       {Enabled: true},
+		"off":      {Enabled: false, Users: []string{"u1"}},
+		"allow":    {Enabled: true, Users: []string{"u1"}, Tenants: []string{"t1"}},
+		"rule":     {Enabled: true, Rule: `region == "cn" && (app_version >= 3.2 || tenant in ["t9"])`},
+		"rollout":  {Enabled: true, Percentage: 30},
+		"full":     {Enabled: true, Percentage: 100},
+		"notenant": {Enabled: true, Rule: `!tenant`},
+	}})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	u1 := WithUser(ctx, "u1")
+	assert.True(t, m.IsEnabled(ctx, "on"))
+	assert.False(t, m.IsEnabled(u1, "off"))
+	assert.False(t, m.IsEnabled(ctx, "missing"))
+
+	assert.True(t, m.IsEnabled(u1, "allow"))
+	assert.True(t, m.IsEnabled(WithTenant(ctx, "t1"), "allow"))
+	assert.False(t, m.IsEnabled(WithUser(ctx, "u2"), "allow"))
+	// 与 ctxx 共用 context key
+	assert.True(t, m.IsEnabled(ctxx.WithUserID(ctx, "u1"), "allow"))
+	assert.True(t, m.IsEnabled(ctxx.WithTenantID(ctx, "t1"), "allow"))
+
+	cn := WithAttributes(ctx, map[string]string{"region": "cn"})
+	assert.False(t, m.IsEnabled(cn, "rule"))
+	assert.True(t, m.IsEnabled(WithAttributes(cn, map[string]string{"app_version": "3.5"}), "rule"))
+	assert.True(t, m.IsEnabled(WithTenant(cn, "t9"), "rule"))
+	assert.True(t, m.IsEnabled(ctx, "notenant"))
+	assert.False(t, m.IsEnabled(WithTenant(ctx, "t1"), "notenant"))
+
+	// 灰度比例大致符合，且同一用户结果稳定
+	hit := 0
+	for i := 0; i < 10000; i++ {
+		uctx := WithUser(ctx, fmt.Sprintf("user-%d", i))
+		if m.IsEnabled(uctx, "rollout") {
+			hit++
+			assert.True(t, m.IsEnabled(uctx, "rollout"))
+		}
+	}
+	assert.InDelta(t, 3000, hit, 300)
+	assert.False(t, m.IsEnabled(ctx, "rollout"))
+	assert.True(t, m.IsEnabled(ctx, "full"))
+
+	SetDefault(m)
+	assert.True(t, IsEnabled(ctx, "on"))
+}
+
+func TestRuleCompare(t *testing.T) {
+	e := &env{attrs: map[string]string{"v": "3.10", "code": "001", "n": "1.5"}}
+	for rule, want := range map[string]bool{
+		`v > 3.2`:        true,
+		`v >= 3.10.0`:    true,
+		`v < 3.10.1`:     true,
+		`v == 3.10`:      true,
+		`v == 3.1`:       false,
+		`code == 1`:      false,
+		`code == "001"`:  true,
+		`code in [1, 2]`: false,
+		`1 == 1.0`:       true,
+		`n < 2`:          true,
+		`n >= 1.05`:      true,
+		`missing > 1`:    false,
+	} {
+		n, err := compileRule(rule)
+		require.NoError(t, err, rule)
+		assert.Equal(t, want, n.eval(e), rule)
+	}
+}
+
+func TestRuleErrors(t *testing.T) {
+	for _, rule := range []string{`region ==`, `(a == "b"`, `a in "x"`, `a == "x`, `a # b`, `a b`} {
+		_, err := New(Config{Flags: map[string]Flag{"f": {Enabled: true, Rule: rule}}})
+		assert.Error(t, err, rule)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	type appConfig struct {
+		FeatureFlags Config `yaml:"featureflags"`
+	}
+	p := filepath.Join(t.TempDir(), "app.yaml")
+	require.NoError(t, os.WriteFile(p, []byte("featureflags:\n  flags:\n    a:\n      enabled: true\n"), 0o644))
+	w, err := config.NewWatcher[appConfig](p)
+	require.NoError(t, err)
+
+	m, err := New(Config{})
+	require.NoError(t, err)
+	var updateErr error
+	require.NoError(t, Watch(m, w, func(c *appConfig) Config { return c.FeatureFlags }, func(err error) { updateErr = err }))
+	assert.True(t, m.IsEnabled(context.Background(), "a"))
+
+	require.NoError(t, os.WriteFile(p, []byte("featureflags:\n  flags:\n    a:\n      enabled: false\n    b:\n      enabled: true\n"), 0o644))
+	_, err = w.Reload()
+	require.NoError(t, err)
+	assert.False(t, m.IsEnabled(context.Background(), "a"))
+	assert.Equal(t, []string{"a", "b"}, m.Names())
+
+	require.NoError(t, os.WriteFile(p, []byte("featureflags:\n  flags:\n    a:\n      enabled: true\n      rule: \"x ==\"\n"), 0o644))
+	_, err = w.Reload()
+	require.NoError(t, err)
+	assert.Error(t, updateErr)
+	assert.Equal(t, []string{"a", "b"}, m.Names())
+}
@@ -0,0 +1,236 @@
+package filex
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsafePath 归档中的路径试图写到目标目录之外（zip slip）
+var ErrUnsafePath = errors.New("filex: unsafe path in archive")
+
+// DefaultMaxUnpackSize 解压总大小的默认上限（1GB），防止解压炸弹
+const DefaultMaxUnpackSize int64 = 1 << 30
+
+// Zip 将 srcDir 下的所有文件打包为 zip 写入 w，归档内路径相对 srcDir
+func Zip(w io.Writer, srcDir string) error {
+	zw := zip.NewWriter(w)
+	err := walkFiles(srcDir, func(path, rel string, info fs.FileInfo) error {
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if info.IsDir() {
+			hdr.Name += "/"
+			_, err = zw.CreateHeader(hdr)
+			return err
+		}
+		hdr.Method = zip.Deflate
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		return copyFrom(fw, path)
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Unzip 解压 zip 文件到 dstDir，拒绝越界路径与符号链接，maxSize 为解压总大小上限（<=0 时使用默认值）
+func Unzip(zipPath, dstDir string, maxSize int64) error {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = zr.Close() }()
+
+	limit := newSizeLimit(maxSize)
+	for _, f := range zr.File {
+		target, err := SafeJoin(dstDir, f.Name)
+		if err != nil {
+			return err
+		}
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case mode&fs.ModeSymlink != 0:
+			return fmt.Errorf("%w: symlink %s", ErrUnsafePath, f.Name)
+		default:
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = writeFile(target, rc, mode.Perm(), limit)
+			_ = rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TarGz 将 srcDir 下的所有文件打包为 tar.gz 写入 w
+func TarGz(w io.Writer, srcDir string) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	err := walkFiles(srcDir, func(path, rel string, info fs.FileInfo) error {
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		return copyFrom(tw, path)
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// UntarGz 解压 tar.gz 到 dstDir，仅处理目录与普通文件，拒绝越界路径、符号链接与硬链接
+func UntarGz(r io.Reader, dstDir string, maxSize int64) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = gr.Close() }()
+
+	limit := newSizeLimit(maxSize)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, err := SafeJoin(dstDir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, fs.FileMode(hdr.Mode).Perm(), limit); err != nil {
+				return err
+			}
+		case tar.TypeSymlink, tar.TypeLink:
+			return fmt.Errorf("%w: link %s", ErrUnsafePath, hdr.Name)
+		default:
+			// 设备文件、FIFO 等直接跳过
+		}
+	}
+}
+
+// SafeJoin 拼接 base 与归档内的相对路径，结果不在 base 目录内时返回 ErrUnsafePath
+func SafeJoin(base, name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") || strings.HasPrefix(name, `\`) {
+		return "", fmt.Errorf("%w: %s", ErrUnsafePath, name)
+	}
+	target := filepath.Join(base, filepath.FromSlash(name))
+	rel, err := filepath.Rel(filepath.Clean(base), target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s", ErrUnsafePath, name)
+	}
+	return target, nil
+}
+
+// sizeLimit 解压总大小计数
+type sizeLimit struct {
+	remain int64
+}
+
+func newSizeLimit(max int64) *sizeLimit {
+	if max <= 0 {
+		max = DefaultMaxUnpackSize
+	}
+	return &sizeLimit{remain: max}
+}
+
+// writeFile 写入解压出的文件，超过总大小上限时返回错误
+func writeFile(target string, r io.Reader, perm fs.FileMode, limit *sizeLimit) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if perm == 0 {
+		perm = 0o644
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, io.LimitReader(r, limit.remain+1))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	limit.remain -= n
+	if limit.remain < 0 {
+		return errors.New("filex: archive exceeds max unpack size")
+	}
+	return nil
+}
+
+// walkFiles 遍历 srcDir，回调参数 rel 为使用 / 分隔的相对路径，跳过根目录与符号链接
+func walkFiles(srcDir string, fn func(path, rel string, info fs.FileInfo) error) error {
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(path, filepath.ToSlash(rel), info)
+	})
+}
+
+// copyFrom 将文件内容写入 w
+func copyFrom(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,165 @@
+// Package filex 文件工具：原子写入、带进度的拷贝、目录大小统计与校验和计算
+package filex
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic 原子写入文件：先写入同目录下的临时文件并 fsync，再 rename 覆盖目标，
+// 读取方不会看到写了一半的内容；perm 为最终文件权限
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return WriteAtomic(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// WriteAtomic 与 WriteFileAtomic 相同，但由 fn 流式写入内容，fn 返回错误时不会替换目标文件
+func WriteAtomic(path string, perm os.FileMode, fn func(w io.Writer) error) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if err = fn(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	// 同步目录，确保 rename 落盘（部分平台不支持，忽略错误）
+	if d, derr := os.Open(dir); derr == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
+
+// ProgressFunc 拷贝进度回调，written 为已写入字节数，total 为总字节数（未知时为 -1）
+type ProgressFunc func(written, total int64)
+
+// progressWriter 统计写入字节数并回调
+type progressWriter struct {
+	w       io.Writer
+	written int64
+	total   int64
+	fn      ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if p.fn != nil {
+		p.fn(p.written, p.total)
+	}
+	return n, err
+}
+
+// CopyWithProgress 从 src 拷贝到 dst，每次写入后回调 fn；total 为总字节数（未知时传 -1）
+func CopyWithProgress(dst io.Writer, src io.Reader, total int64, fn ProgressFunc) (int64, error) {
+	return io.Copy(&progressWriter{w: dst, total: total, fn: fn}, src)
+}
+
+// CopyFile 拷贝文件（原子写入目标文件并保留权限），fn 可为 nil
+func CopyFile(dst, src string, fn ProgressFunc) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	return WriteAtomic(dst, info.Mode().Perm(), func(w io.Writer) error {
+		_, err := CopyWithProgress(w, in, info.Size(), fn)
+		return err
+	})
+}
+
+// DirSize 统计目录下所有普通文件的总大小（不跟随符号链接）
+func DirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// Exists 判断文件或目录是否存在
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// MD5 计算 reader 内容的 MD5（十六进制小写）
+func MD5(r io.Reader) (string, error) {
+	return sum(md5.New(), r)
+}
+
+// SHA256 计算 reader 内容的 SHA-256（十六进制小写）
+func SHA256(r io.Reader) (string, error) {
+	return sum(sha256.New(), r)
+}
+
+// FileMD5 计算文件的 MD5
+func FileMD5(path string) (string, error) {
+	return fileSum(path, md5.New())
+}
+
+// FileSHA256 计算文件的 SHA-256
+func FileSHA256(path string) (string, error) {
+	return fileSum(path, sha256.New())
+}
+
+// fileSum 计算文件的摘要
+func fileSum(path string, h hash.Hash) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	return sum(h, f)
+}
+
+// sum 流式计算摘要
+func sum(h hash.Hash, r io.Reader) (string, error) {
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
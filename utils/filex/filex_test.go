@@ -0,0 +1,88 @@
+package filex
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteAtomicAndChecksum(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "a.txt")
+	assert.NoError(t, WriteFileAtomic(p, []byte("hello"), 0o600))
+
+	info, err := os.Stat(p)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	sum, err := FileMD5(p)
+	assert.NoError(t, err)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", sum)
+	sum, err = SHA256(strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sum)
+
+	var last int64
+	assert.NoError(t, CopyFile(filepath.Join(dir, "b.txt"), p, func(written, total int64) {
+		last = written
+		assert.Equal(t, int64(5), total)
+	}))
+	assert.Equal(t, int64(5), last)
+
+	size, err := DirSize(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), size)
+
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 2, "no temp files left behind")
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(src, "sub"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "sub", "b.txt"), []byte("bb"), 0o644))
+
+	var tgz bytes.Buffer
+	assert.NoError(t, TarGz(&tgz, src))
+	out := t.TempDir()
+	assert.NoError(t, UntarGz(bytes.NewReader(tgz.Bytes()), out, 0))
+	b, _ := os.ReadFile(filepath.Join(out, "sub", "b.txt"))
+	assert.Equal(t, "bb", string(b))
+
+	zipPath := filepath.Join(t.TempDir(), "x.zip")
+	assert.NoError(t, WriteAtomic(zipPath, 0o644, func(w io.Writer) error { return Zip(w, src) }))
+	out = t.TempDir()
+	assert.NoError(t, Unzip(zipPath, out, 0))
+	b, _ = os.ReadFile(filepath.Join(out, "a.txt"))
+	assert.Equal(t, "a", string(b))
+
+	// 超过解压上限
+	assert.Error(t, Unzip(zipPath, t.TempDir(), 2))
+}
+
+func TestUnzipRejectsTraversal(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "evil.zip")
+	f, _ := os.Create(zipPath)
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create("../../evil.txt")
+	_, _ = w.Write([]byte("x"))
+	_ = zw.Close()
+	_ = f.Close()
+
+	assert.ErrorIs(t, Unzip(zipPath, t.TempDir(), 0), ErrUnsafePath)
+
+	for _, name := range []string{"../x", "/etc/passwd", "a/../../x"} {
+		_, err := SafeJoin("/data", name)
+		assert.ErrorIs(t, err, ErrUnsafePath, name)
+	}
+	p, err := SafeJoin("/data", "a/./b/../c.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/data/a/c.txt"), p)
+}
@@ -0,0 +1,207 @@
+// Package fsm 有限状态机：定义状态、迁移、守卫与钩子，实例并发安全，并可通过 Store 持久化状态，
+// 适用于订单、工单等在各服务中反复出现的状态流转
+package fsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrInvalidTransition 当前状态下不允许该事件
+	ErrInvalidTransition = errors.New("fsm: invalid transition")
+	// ErrStateConflict 持久化时发现状态已被其它请求修改（乐观锁冲突）
+	ErrStateConflict = errors.New("fsm: state conflict")
+)
+
+// State 状态
+type State string
+
+// Event 触发迁移的事件
+type Event string
+
+// TransitionInfo 一次迁移的上下文，传给守卫与钩子
+type TransitionInfo struct {
+	ID    string      // 实例 ID
+	Event Event       // 事件
+	From  State       // 迁移前状态
+	To    State       // 迁移后状态
+	Args  interface{} // Fire 传入的业务参数
+}
+
+// Hook 迁移钩子，返回错误时（迁移生效前的钩子）会中止迁移
+type Hook func(ctx context.Context, t TransitionInfo) error
+
+// Transition 迁移定义：在 From 中任一状态下收到 Event 时迁移到 To
+type Transition struct {
+	Event Event
+	From  []State
+	To    State
+	Guard Hook // 可选，返回错误时拒绝迁移，如“金额为 0 的订单不能进入支付”
+}
+
+// Definition 状态机定义，创建后注册钩子，之后可被多个实例并发使用（注册钩子需在使用前完成）
+// 使用示例：
+//
+//	def, err := fsm.NewDefinition(
+//		fsm.Transition{Event: "pay", From: []fsm.State{"created"}, To: "paid"},
+//		fsm.Transition{Event: "ship", From: []fsm.State{"paid"}, To: "shipped"},
+//		fsm.Transition{Event: "cancel", From: []fsm.State{"created", "paid"}, To: "canceled"},
+//	)
+//	def.OnEnter("paid", notifyWarehouse)
+//	order := def.Instance("order-1", "created")
+//	err = order.Fire(ctx, "pay", nil)
+type Definition struct {
+	transitions map[State]map[Event]Transition
+	before      []Hook
+	after       []Hook
+	enter       map[State][]Hook
+	leave       map[State][]Hook
+}
+
+// NewDefinition 创建状态机定义，同一状态下同一事件重复定义时返回错误
+func NewDefinition(transitions ...Transition) (*Definition, error) {
+	d := &Definition{
+		transitions: make(map[State]map[Event]Transition),
+		enter:       make(map[State][]Hook),
+		leave:       make(map[State][]Hook),
+	}
+	for _, t := range transitions {
+		if t.Event == "" || t.To == "" || len(t.From) == 0 {
+			return nil, fmt.Errorf("fsm: transition %q must have event, from and to", t.Event)
+		}
+		for _, from := range t.From {
+			events := d.transitions[from]
+			if events == nil {
+				events = make(map[Event]Transition)
+				d.transitions[from] = events
+			}
+			if _, dup := events[t.Event]; dup {
+				return nil, fmt.Errorf("fsm: duplicate transition %q from %q", t.Event, from)
+			}
+			events[t.Event] = t
+		}
+	}
+	return d, nil
+}
+
+// BeforeTransition 注册迁移前钩子（守卫通过之后、状态变更之前），返回错误中止迁移
+func (d *Definition) BeforeTransition(h Hook) { d.before = append(d.before, h) }
+
+// AfterTransition 注册迁移后钩子（状态已变更并持久化），返回的错误会透传给 Fire，但不会回滚状态
+func (d *Definition) AfterTransition(h Hook) { d.after = append(d.after, h) }
+
+// OnLeave 注册离开某状态时的钩子，在 BeforeTransition 之后执行，返回错误中止迁移
+func (d *Definition) OnLeave(s State, h Hook) { d.leave[s] = append(d.leave[s], h) }
+
+// OnEnter 注册进入某状态时的钩子，在状态变更后、AfterTransition 之前执行
+func (d *Definition) OnEnter(s State, h Hook) { d.enter[s] = append(d.enter[s], h) }
+
+// Can 判断 from 状态下是否定义了该事件（不执行守卫）
+func (d *Definition) Can(from State, e Event) bool {
+	_, ok := d.transitions[from][e]
+	return ok
+}
+
+// Events 返回 from 状态下可触发的事件（已排序）
+func (d *Definition) Events(from State) []Event {
+	events := make([]Event, 0, len(d.transitions[from]))
+	for e := range d.transitions[from] {
+		events = append(events, e)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
+	return events
+}
+
+// Instance 创建内存中的状态机实例
+func (d *Definition) Instance(id string, current State) *Instance {
+	return &Instance{def: d, id: id, state: current}
+}
+
+// Load 从 Store 读取当前状态并创建实例，之后每次迁移都会通过 Store 以乐观锁方式持久化
+func (d *Definition) Load(ctx context.Context, store Store, id string) (*Instance, error) {
+	s, err := store.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &Instance{def: d, id: id, state: s, store: store}, nil
+}
+
+// Instance 状态机实例，并发安全：同一实例上的 Fire 串行执行
+type Instance struct {
+	def   *Definition
+	id    string
+	store Store
+
+	mu    sync.Mutex
+	state State
+}
+
+// ID 实例 ID
+func (in *Instance) ID() string { return in.id }
+
+// State 当前状态
+func (in *Instance) State() State {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.state
+}
+
+// Can 判断当前状态下是否可以触发事件（不执行守卫）
+func (in *Instance) Can(e Event) bool {
+	return in.def.Can(in.State(), e)
+}
+
+// Fire 触发事件，执行顺序：Guard -> BeforeTransition -> OnLeave -> 持久化 -> 更新状态 -> OnEnter -> AfterTransition
+// 事件不可用时返回 ErrInvalidTransition；持久化时状态已被修改返回 ErrStateConflict，实例会同步为最新状态
+func (in *Instance) Fire(ctx context.Context, e Event, args interface{}) error {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	t, ok := in.def.transitions[in.state][e]
+	if !ok {
+		return fmt.Errorf("%w: %q from %q", ErrInvalidTransition, e, in.state)
+	}
+	info := TransitionInfo{ID: in.id, Event: e, From: in.state, To: t.To, Args: args}
+
+	if t.Guard != nil {
+		if err := t.Guard(ctx, info); err != nil {
+			return err
+		}
+	}
+	if err := runHooks(ctx, info, in.def.before); err != nil {
+		return err
+	}
+	if err := runHooks(ctx, info, in.def.leave[info.From]); err != nil {
+		return err
+	}
+	if in.store != nil {
+		if err := in.store.Save(ctx, in.id, info.From, info.To); err != nil {
+			if errors.Is(err, ErrStateConflict) {
+				if s, lerr := in.store.Load(ctx, in.id); lerr == nil {
+					in.state = s
+				}
+			}
+			return err
+		}
+	}
+	in.state = info.To
+
+	if err := runHooks(ctx, info, in.def.enter[info.To]); err != nil {
+		return err
+	}
+	return runHooks(ctx, info, in.def.after)
+}
+
+// runHooks 依次执行钩子，遇到错误即停止
+func runHooks(ctx context.Context, info TransitionInfo, hooks []Hook) error {
+	for _, h := range hooks {
+		if err := h(ctx, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrderDef(t *testing.T) *Definition {
+	def, err := NewDefinition(
+		Transition{Event: "pay", From: []State{"created"}, To: "paid", Guard: func(ctx context.Context, tr TransitionInfo) error {
+			if amount, _ := tr.Args.(int); amount <= 0 {
+				return errors.New("amount must be positive")
+			}
+			return nil
+		}},
+		Transition{Event: "ship", From: []State{"paid"}, To: "shipped"},
+		Transition{Event: "cancel", From: []State{"created", "paid"}, To: "canceled"},
+	)
+	require.NoError(t, err)
+	return def
+}
+
+func TestFire(t *testing.T) {
+	def := newOrderDef(t)
+	var trace []string
+	def.BeforeTransition(func(ctx context.Context, tr TransitionInfo) error {
+		trace = append(trace, "before:"+string(tr.Event))
+		return nil
+	})
+	def.OnLeave("created", func(ctx context.Context, tr TransitionInfo) error {
+		trace = append(trace, "leave:created")
+		return nil
+	})
+	def.OnEnter("paid", func(ctx context.Context, tr TransitionInfo) error {
+		trace = append(trace, "enter:paid")
+		return nil
+	})
+	def.AfterTransition(func(ctx context.Context, tr TransitionInfo) error {
+		trace = append(trace, "after:"+string(tr.From)+"->"+string(tr.To))
+		return nil
+	})
+
+	order := def.Instance("o1", "created")
+	assert.Equal(t, []Event{"cancel", "pay"}, def.Events("created"))
+	assert.False(t, order.Can("ship"))
+	assert.ErrorIs(t, order.Fire(context.Background(), "ship", nil), ErrInvalidTransition)
+
+	assert.EqualError(t, order.Fire(context.Background(), "pay", 0), "amount must be positive")
+	assert.Equal(t, State("created"), order.State())
+
+	require.NoError(t, order.Fire(context.Background(), "pay", 100))
+	assert.Equal(t, State("paid"), order.State())
+	assert.Equal(t, []string{"before:pay", "leave:created", "enter:paid", "after:created->paid"}, trace)
+}
+
+func TestDefinitionErrors(t *testing.T) {
+	_, err := NewDefinition(Transition{Event: "a", From: []State{"x"}, To: "y"}, Transition{Event: "a", From: []State{"x"}, To: "z"})
+	assert.Error(t, err)
+	_, err = NewDefinition(Transition{Event: "a", To: "y"})
+	assert.Error(t, err)
+}
+
+func TestStoreConflict(t *testing.T) {
+	def := newOrderDef(t)
+	store := NewMemoryStore()
+	store.Put("o1", "created")
+	ctx := context.Background()
+
+	a, err := def.Load(ctx, store, "o1")
+	require.NoError(t, err)
+	b, err := def.Load(ctx, store, "o1")
+	require.NoError(t, err)
+
+	require.NoError(t, a.Fire(ctx, "cancel", nil))
+	assert.ErrorIs(t, b.Fire(ctx, "pay", 10), ErrStateConflict)
+	assert.Equal(t, State("canceled"), b.State())
+
+	_, err = def.Load(ctx, store, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestConcurrentFire(t *testing.T) {
+	def := newOrderDef(t)
+	order := def.Instance("o1", "created")
+	var wg sync.WaitGroup
+	var ok, invalid int32
+	var mu sync.Mutex
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := order.Fire(context.Background(), "cancel", nil)
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				ok++
+			} else if errors.Is(err, ErrInvalidTransition) {
+				invalid++
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), ok)
+	assert.Equal(t, int32(19), invalid)
+}
+
+func TestSQLStore_QualifiedTable(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	store := &SQLStore{DB: db, Table: "shop.orders", StateColumn: "status"}
+
+	mock.ExpectQuery("SELECT `status` FROM `shop`.`orders` WHERE `id` = ?").WithArgs("o1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("created"))
+	mock.ExpectExec("UPDATE `shop`.`orders` SET `status` = ? WHERE `id` = ? AND `status` = ?").WithArgs("paid", "o1", "created").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	st, err := store.Load(context.Background(), "o1")
+	require.NoError(t, err)
+	assert.Equal(t, State("created"), st)
+	require.NoError(t, store.Save(context.Background(), "o1", "created", "paid"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,125 @@
+package fsm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/qingfeng-studio/go-utils/drivers/mysqlx"
+)
+
+// ErrNotFound Store 中不存在该实例
+var ErrNotFound = errors.New("fsm: instance not found")
+
+// Store 状态持久化适配器
+type Store interface {
+	// Load 读取实例的当前状态，不存在时返回 ErrNotFound
+	Load(ctx context.Context, id string) (State, error)
+	// Save 仅当当前状态仍为 from 时更新为 to（比较并交换），否则返回 ErrStateConflict
+	Save(ctx context.Context, id string, from, to State) error
+}
+
+// MemoryStore 内存实现，适合测试与单进程场景
+type MemoryStore struct {
+	mu     sync.Mutex
+	states map[string]State
+}
+
+// NewMemoryStore 创建内存 Store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{states: make(map[string]State)}
+}
+
+// Put 写入初始状态
+func (m *MemoryStore) Put(id string, s State) {
+	m.mu.Lock()
+	m.states[id] = s
+	m.mu.Unlock()
+}
+
+// Load 实现 Store
+func (m *MemoryStore) Load(_ context.Context, id string) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.states[id]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return s, nil
+}
+
+// Save 实现 Store
+func (m *MemoryStore) Save(_ context.Context, id string, from, to State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cur, ok := m.states[id]; !ok || cur != from {
+		return ErrStateConflict
+	}
+	m.states[id] = to
+	return nil
+}
+
+// SQLStore 基于业务表状态列的实现，Save 使用 UPDATE ... WHERE state = from 实现乐观锁，
+// 可传入 *sql.DB 或 *sql.Tx（与业务修改放在同一事务中）
+//
+//	store := &fsm.SQLStore{DB: db, Table: "orders", IDColumn: "id", StateColumn: "status"}
+type SQLStore struct {
+	DB interface {
+		QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+		ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	}
+	Table       string // 支持 db.table 形式
+	IDColumn    string // 默认 id
+	StateColumn string // 默认 state
+}
+
+// Load 实现 Store
+func (s *SQLStore) Load(ctx context.Context, id string) (State, error) {
+	var st string
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", mysqlx.QuoteIdent(s.stateColumn()), mysqlx.QuoteIdent(s.Table), mysqlx.QuoteIdent(s.idColumn()))
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(&st)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	return State(st), err
+}
+
+// Save 实现 Store
+func (s *SQLStore) Save(ctx context.Context, id string, from, to State) error {
+	col := mysqlx.QuoteIdent(s.stateColumn())
+	query := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ? AND %s = ?", mysqlx.QuoteIdent(s.Table), col, mysqlx.QuoteIdent(s.idColumn()), col)
+	res, err := s.DB.ExecContext(ctx, query, string(to), id, string(from))
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		// 自迁移（from == to）时 MySQL 默认返回实际变更行数 0，需要回查确认
+		if from == to {
+			if cur, err := s.Load(ctx, id); err == nil && cur == from {
+				return nil
+			}
+		}
+		return ErrStateConflict
+	}
+	return nil
+}
+
+func (s *SQLStore) idColumn() string {
+	if s.IDColumn == "" {
+		return "id"
+	}
+	return s.IDColumn
+}
+
+func (s *SQLStore) stateColumn() string {
+	if s.StateColumn == "" {
+		return "state"
+	}
+	return s.StateColumn
+}
@@ -0,0 +1,142 @@
+// Package geox 地理坐标计算：球面距离、外接矩形、点是否在多边形内，
+// 以及国内地图常用的 WGS-84（GPS）、GCJ-02（高德/腾讯）、BD-09（百度）坐标系互转
+package geox
+
+import "math"
+
+// EarthRadius 地球平均半径（米）
+const EarthRadius = 6371000.0
+
+// Point 经纬度坐标（度）
+type Point struct {
+	Lng float64
+	Lat float64
+}
+
+// Distance 使用 haversine 公式计算两点间的球面距离（米）
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(b.Lng - a.Lng)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Bearing 计算从 a 到 b 的初始方位角（度，正北为 0，顺时针 0~360）
+func Bearing(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLng := radians(b.Lng - a.Lng)
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// Box 经纬度矩形；MinLng > MaxLng 表示跨越 180° 经线，
+// 经度范围为 [MinLng, 180] ∪ [-180, MaxLng]
+type Box struct {
+	MinLng float64
+	MinLat float64
+	MaxLng float64
+	MaxLat float64
+}
+
+// BoundingBox 返回以 center 为中心、radius（米）为半径的圆的外接矩形，
+// 常用于先按矩形范围查询数据库（如 lat BETWEEN ? AND ?），再用 Distance 精确过滤；
+// 靠近极点时经度范围扩展为 [-180, 180]；跨越 180° 经线时经度回绕到 [-180, 180]，
+// 此时 MinLng > MaxLng，按经度查询需拆成两段（见 CrossesAntimeridian）
+func BoundingBox(center Point, radius float64) Box {
+	dLat := degrees(radius / EarthRadius)
+	box := Box{
+		MinLat: math.Max(center.Lat-dLat, -90),
+		MaxLat: math.Min(center.Lat+dLat, 90),
+		MinLng: -180,
+		MaxLng: 180,
+	}
+	if box.MinLat > -90 && box.MaxLat < 90 {
+		dLng := degrees(math.Asin(math.Min(1, math.Sin(radius/EarthRadius)/math.Cos(radians(center.Lat)))))
+		if dLng < 180 {
+			box.MinLng = wrapLng(center.Lng - dLng)
+			box.MaxLng = wrapLng(center.Lng + dLng)
+		}
+	}
+	return box
+}
+
+// CrossesAntimeridian 矩形是否跨越 180° 经线
+func (b Box) CrossesAntimeridian() bool {
+	return b.MinLng > b.MaxLng
+}
+
+// Contains 判断点是否在矩形内（含边界），支持跨越 180° 经线的矩形
+func (b Box) Contains(p Point) bool {
+	if p.Lat < b.MinLat || p.Lat > b.MaxLat {
+		return false
+	}
+	if b.CrossesAntimeridian() {
+		return p.Lng >= b.MinLng || p.Lng <= b.MaxLng
+	}
+	return p.Lng >= b.MinLng && p.Lng <= b.MaxLng
+}
+
+// wrapLng 把经度规范到 [-180, 180]
+func wrapLng(lng float64) float64 {
+	if lng >= -180 && lng <= 180 {
+		return lng
+	}
+	lng = math.Mod(lng+180, 360)
+	if lng < 0 {
+		lng += 360
+	}
+	return lng - 180
+}
+
+// Bounds 返回一组点的最小外接矩形
+func Bounds(points []Point) Box {
+	if len(points) == 0 {
+		return Box{}
+	}
+	b := Box{MinLng: points[0].Lng, MaxLng: points[0].Lng, MinLat: points[0].Lat, MaxLat: points[0].Lat}
+	for _, p := range points[1:] {
+		b.MinLng = math.Min(b.MinLng, p.Lng)
+		b.MaxLng = math.Max(b.MaxLng, p.Lng)
+		b.MinLat = math.Min(b.MinLat, p.Lat)
+		b.MaxLat = math.Max(b.MaxLat, p.Lat)
+	}
+	return b
+}
+
+// InPolygon 使用射线法判断点是否在多边形内，边界上的点视为在内；
+// polygon 为顶点序列，首尾无需重复，适用于城市级别的电子围栏（按平面坐标计算）
+func InPolygon(p Point, polygon []Point) bool {
+	n := len(polygon)
+	if n < 3 {
+		return false
+	}
+	inside := false
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if onSegment(p, a, b) {
+			return true
+		}
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lng < (b.Lng-a.Lng)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// onSegment 判断 p 是否在线段 ab 上
+func onSegment(p, a, b Point) bool {
+	const eps = 1e-12
+	cross := (b.Lng-a.Lng)*(p.Lat-a.Lat) - (b.Lat-a.Lat)*(p.Lng-a.Lng)
+	if math.Abs(cross) > eps {
+		return false
+	}
+	return p.Lng >= math.Min(a.Lng, b.Lng)-eps && p.Lng <= math.Max(a.Lng, b.Lng)+eps &&
+		p.Lat >= math.Min(a.Lat, b.Lat)-eps && p.Lat <= math.Max(a.Lat, b.Lat)+eps
+}
+
+func radians(d float64) float64 { return d * math.Pi / 180 }
+
+func degrees(r float64) float64 { return r * 180 / math.Pi }
@@ -0,0 +1,105 @@
+package geox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	beijing  = Point{Lng: 116.4074, Lat: 39.9042}
+	shanghai = Point{Lng: 121.4737, Lat: 31.2304}
+)
+
+func TestDistance(t *testing.T) {
+	assert.InDelta(t, 1067000, Distance(beijing, shanghai), 2000)
+	assert.InDelta(t, Distance(beijing, shanghai), Distance(shanghai, beijing), 1e-6)
+	assert.Zero(t, Distance(beijing, beijing))
+	// 赤道上经度相差 1 度约 111.2 公里
+	assert.InDelta(t, 111195, Distance(Point{0, 0}, Point{1, 0}), 1)
+}
+
+func TestBearing(t *testing.T) {
+	assert.InDelta(t, 0, Bearing(Point{0, 0}, Point{0, 1}), 1e-9)
+	assert.InDelta(t, 90, Bearing(Point{0, 0}, Point{1, 0}), 1e-9)
+	assert.InDelta(t, 180, Bearing(Point{0, 1}, Point{0, 0}), 1e-9)
+	assert.InDelta(t, 270, Bearing(Point{1, 0}, Point{0, 0}), 1e-9)
+}
+
+func TestBoundingBox(t *testing.T) {
+	box := BoundingBox(beijing, 5000)
+	assert.True(t, box.Contains(beijing))
+	for _, p := range []Point{
+		{Lng: box.MinLng, Lat: beijing.Lat},
+		{Lng: box.MaxLng, Lat: beijing.Lat},
+		{Lng: beijing.Lng, Lat: box.MinLat},
+		{Lng: beijing.Lng, Lat: box.MaxLat},
+	} {
+		assert.InDelta(t, 5000, Distance(beijing, p), 5)
+	}
+	assert.False(t, box.Contains(shanghai))
+
+	polar := BoundingBox(Point{Lng: 10, Lat: 89.99}, 5000)
+	assert.Equal(t, 90.0, polar.MaxLat)
+	assert.Equal(t, -180.0, polar.MinLng)
+	assert.Equal(t, 180.0, polar.MaxLng)
+
+	fiji := Point{Lng: 179.99, Lat: -17.7}
+	cross := BoundingBox(fiji, 5000)
+	assert.True(t, cross.CrossesAntimeridian())
+	assert.Greater(t, cross.MinLng, 179.9)
+	assert.Less(t, cross.MaxLng, -179.9)
+	assert.True(t, cross.Contains(fiji))
+	assert.True(t, cross.Contains(Point{Lng: -179.99, Lat: -17.7}))
+	assert.False(t, cross.Contains(Point{Lng: 0, Lat: -17.7}))
+	assert.False(t, box.CrossesAntimeridian())
+}
+
+func TestBounds(t *testing.T) {
+	assert.Equal(t, Box{MinLng: 116.4074, MinLat: 31.2304, MaxLng: 121.4737, MaxLat: 39.9042}, Bounds([]Point{beijing, shanghai}))
+	assert.Equal(t, Box{}, Bounds(nil))
+}
+
+func TestInPolygon(t *testing.T) {
+	square := []Point{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	assert.True(t, InPolygon(Point{5, 5}, square))
+	assert.False(t, InPolygon(Point{15, 5}, square))
+	assert.True(t, InPolygon(Point{10, 5}, square))
+	assert.True(t, InPolygon(Point{0, 0}, square))
+
+	// 凹多边形
+	concave := []Point{{0, 0}, {10, 0}, {10, 10}, {5, 3}, {0, 10}}
+	assert.True(t, InPolygon(Point{2, 2}, concave))
+	assert.False(t, InPolygon(Point{5, 8}, concave))
+
+	assert.False(t, InPolygon(Point{0, 0}, square[:2]))
+}
+
+func TestTransform(t *testing.T) {
+	p := Point{Lng: 116.404, Lat: 39.915}
+
+	gcj := WGS84ToGCJ02(p)
+	assert.InDelta(t, 116.41024449916938, gcj.Lng, 1e-9)
+	assert.InDelta(t, 39.91640428150164, gcj.Lat, 1e-9)
+
+	bd := GCJ02ToBD09(p)
+	assert.InDelta(t, 116.41036949371029, bd.Lng, 1e-9)
+	assert.InDelta(t, 39.92133699351021, bd.Lat, 1e-9)
+
+	back := BD09ToGCJ02(p)
+	assert.InDelta(t, 116.39762729119315, back.Lng, 1e-9)
+	assert.InDelta(t, 39.90865673957631, back.Lat, 1e-9)
+
+	// 往返误差
+	w := GCJ02ToWGS84(WGS84ToGCJ02(p))
+	assert.InDelta(t, p.Lng, w.Lng, 1e-9)
+	assert.InDelta(t, p.Lat, w.Lat, 1e-9)
+	w = BD09ToWGS84(WGS84ToBD09(p))
+	assert.Less(t, Distance(p, w), 1.0)
+
+	// 国外坐标不偏移
+	london := Point{Lng: -0.1278, Lat: 51.5074}
+	assert.True(t, OutOfChina(london))
+	assert.Equal(t, london, WGS84ToGCJ02(london))
+	assert.Equal(t, london, GCJ02ToWGS84(london))
+}
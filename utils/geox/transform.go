@@ -0,0 +1,97 @@
+package geox
+
+import "math"
+
+// GCJ-02 使用的克拉索夫斯基椭球参数
+const (
+	krasovskyA  = 6378245.0
+	krasovskyEE = 0.00669342162296594323
+	bdXPi       = math.Pi * 3000.0 / 180.0
+)
+
+// OutOfChina 粗略判断坐标是否在国内，国外坐标不做 GCJ-02 偏移
+func OutOfChina(p Point) bool {
+	return p.Lng < 72.004 || p.Lng > 137.8347 || p.Lat < 0.8293 || p.Lat > 55.8271
+}
+
+// WGS84ToGCJ02 GPS 坐标转换为高德/腾讯坐标
+func WGS84ToGCJ02(p Point) Point {
+	if OutOfChina(p) {
+		return p
+	}
+	dLng, dLat := gcjOffset(p)
+	return Point{Lng: p.Lng + dLng, Lat: p.Lat + dLat}
+}
+
+// GCJ02ToWGS84 高德/腾讯坐标转换为 GPS 坐标，迭代求逆，精度优于 1e-9 度
+func GCJ02ToWGS84(p Point) Point {
+	if OutOfChina(p) {
+		return p
+	}
+	w := p
+	for i := 0; i < 30; i++ {
+		g := WGS84ToGCJ02(w)
+		dLng, dLat := g.Lng-p.Lng, g.Lat-p.Lat
+		w.Lng -= dLng
+		w.Lat -= dLat
+		if math.Abs(dLng) < 1e-10 && math.Abs(dLat) < 1e-10 {
+			break
+		}
+	}
+	return w
+}
+
+// GCJ02ToBD09 高德/腾讯坐标转换为百度坐标
+func GCJ02ToBD09(p Point) Point {
+	z := math.Hypot(p.Lng, p.Lat) + 0.00002*math.Sin(p.Lat*bdXPi)
+	theta := math.Atan2(p.Lat, p.Lng) + 0.000003*math.Cos(p.Lng*bdXPi)
+	return Point{Lng: z*math.Cos(theta) + 0.0065, Lat: z*math.Sin(theta) + 0.006}
+}
+
+// BD09ToGCJ02 百度坐标转换为高德/腾讯坐标，使用通行的近似逆公式，误差在亚米级
+func BD09ToGCJ02(p Point) Point {
+	x, y := p.Lng-0.0065, p.Lat-0.006
+	z := math.Hypot(x, y) - 0.00002*math.Sin(y*bdXPi)
+	theta := math.Atan2(y, x) - 0.000003*math.Cos(x*bdXPi)
+	return Point{Lng: z * math.Cos(theta), Lat: z * math.Sin(theta)}
+}
+
+// WGS84ToBD09 GPS 坐标转换为百度坐标
+func WGS84ToBD09(p Point) Point {
+	return GCJ02ToBD09(WGS84ToGCJ02(p))
+}
+
+// BD09ToWGS84 百度坐标转换为 GPS 坐标
+func BD09ToWGS84(p Point) Point {
+	return GCJ02ToWGS84(BD09ToGCJ02(p))
+}
+
+// gcjOffset 计算 WGS-84 到 GCJ-02 的经纬度偏移量
+func gcjOffset(p Point) (dLng, dLat float64) {
+	x, y := p.Lng-105.0, p.Lat-35.0
+	dLat = transformLat(x, y)
+	dLng = transformLng(x, y)
+	radLat := radians(p.Lat)
+	magic := math.Sin(radLat)
+	magic = 1 - krasovskyEE*magic*magic
+	sqrtMagic := math.Sqrt(magic)
+	dLat = (dLat * 180.0) / ((krasovskyA * (1 - krasovskyEE)) / (magic * sqrtMagic) * math.Pi)
+	dLng = (dLng * 180.0) / (krasovskyA / sqrtMagic * math.Cos(radLat) * math.Pi)
+	return dLng, dLat
+}
+
+func transformLat(x, y float64) float64 {
+	ret := -100.0 + 2.0*x + 3.0*y + 0.2*y*y + 0.1*x*y + 0.2*math.Sqrt(math.Abs(x))
+	ret += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	ret += (20.0*math.Sin(y*math.Pi) + 40.0*math.Sin(y/3.0*math.Pi)) * 2.0 / 3.0
+	ret += (160.0*math.Sin(y/12.0*math.Pi) + 320*math.Sin(y*math.Pi/30.0)) * 2.0 / 3.0
+	return ret
+}
+
+func transformLng(x, y float64) float64 {
+	ret := 300.0 + x + 2.0*y + 0.1*x*x + 0.1*x*y + 0.1*math.Sqrt(math.Abs(x))
+	ret += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	ret += (20.0*math.Sin(x*math.Pi) + 40.0*math.Sin(x/3.0*math.Pi)) * 2.0 / 3.0
+	ret += (150.0*math.Sin(x/12.0*math.Pi) + 300.0*math.Sin(x/30.0*math.Pi)) * 2.0 / 3.0
+	return ret
+}
@@ -0,0 +1,196 @@
+// Package grpcx gRPC 客户端与服务端的初始化封装：客户端默认开启 keepalive，可选重试策略、链路追踪、
+// 日志与服务间 token；服务端默认开启 panic 恢复与访问日志，可选服务间 token 认证与健康检查服务，
+// 日志统一使用本仓库的 logger
+// 使用示例：
+//
+//	conn, err := grpcx.Dial("dns:///inventory-svc:9090", grpcx.WithInsecure(), grpcx.WithRetry(3),
+//		grpcx.WithTracing(), grpcx.WithServiceToken(st, "inventory-svc"))
+//
+//	srv := grpcx.NewServer(grpcx.WithAuth(st), grpcx.WithHealth(checker), grpcx.WithServerTracing())
+//	pb.RegisterInventoryServer(srv, impl)
+package grpcx
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+	"github.com/qingfeng-studio/go-utils/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// 客户端默认值
+const (
+	defaultKeepaliveTime    = 30 * time.Second
+	defaultKeepaliveTimeout = 10 * time.Second
+	defaultRetryBackoff     = 100 * time.Millisecond
+	defaultRetryMaxBackoff  = time.Second
+)
+
+// dialOptions Dial 选项
+type dialOptions struct {
+	log              *logger.Logger
+	insecure         bool
+	tlsConfig        *tls.Config
+	keepaliveTime    time.Duration
+	keepaliveTimeout time.Duration
+	retryAttempts    int
+	retryCodes       []codes.Code
+	tracing          bool
+	tokens           *utils.ServiceTokenIssuer
+	audience         string
+	extra            []grpc.DialOption
+}
+
+// DialOption Dial 的函数式选项
+type DialOption func(*dialOptions)
+
+// WithInsecure 不使用 TLS，只应用于集群内部网络
+func WithInsecure() DialOption {
+	return func(o *dialOptions) { o.insecure = true }
+}
+
+// WithTLS 使用指定的 TLS 配置，未设置 WithInsecure 与 WithTLS 时使用系统根证书
+func WithTLS(cfg *tls.Config) DialOption {
+	return func(o *dialOptions) { o.tlsConfig = cfg }
+}
+
+// WithKeepalive 设置 keepalive 探测间隔与超时，默认 30 秒 / 10 秒；
+// 间隔不应小于服务端允许的最小值（grpc-go 默认 5 分钟，NewServer 调整为 10 秒），否则连接会被服务端关闭
+func WithKeepalive(interval, timeout time.Duration) DialOption {
+	return func(o *dialOptions) { o.keepaliveTime, o.keepaliveTimeout = interval, timeout }
+}
+
+// WithRetry 开启 gRPC 内置的重试策略，maxAttempts 包含首次调用（gRPC 上限为 5），
+// 退避从 100ms 指数增长到 1s；retryable 为空时只重试 Unavailable
+func WithRetry(maxAttempts int, retryable ...codes.Code) DialOption {
+	return func(o *dialOptions) { o.retryAttempts, o.retryCodes = maxAttempts, retryable }
+}
+
+// WithTracing 为调用创建客户端 span 并透传链路信息，需先调用 otelx.Init
+func WithTracing() DialOption {
+	return func(o *dialOptions) { o.tracing = true }
+}
+
+// WithLogger 指定调用日志使用的 logger，默认 logger.Default()
+func WithLogger(l *logger.Logger) DialOption {
+	return func(o *dialOptions) { o.log = l }
+}
+
+// WithServiceToken 每次调用在 authorization 元数据中携带发给 audience 服务的 token，
+// 与服务端的 WithAuth 配合使用
+func WithServiceToken(st *utils.ServiceTokenIssuer, audience string) DialOption {
+	return func(o *dialOptions) { o.tokens, o.audience = st, audience }
+}
+
+// WithDialOptions 追加原生的 grpc.DialOption，如 grpc.WithContextDialer
+func WithDialOptions(opts ...grpc.DialOption) DialOption {
+	return func(o *dialOptions) { o.extra = append(o.extra, opts...) }
+}
+
+// Dial 创建客户端连接（grpc.NewClient），连接在首次调用时建立
+func Dial(target string, opts ...DialOption) (*grpc.ClientConn, error) {
+	o := dialOptions{keepaliveTime: defaultKeepaliveTime, keepaliveTimeout: defaultKeepaliveTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.log == nil {
+		o.log = logger.Default()
+	}
+
+	creds := credentials.NewTLS(o.tlsConfig)
+	if o.insecure {
+		creds = insecure.NewCredentials()
+	}
+	dopts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                o.keepaliveTime,
+			Timeout:             o.keepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+	}
+	if o.retryAttempts > 1 {
+		dopts = append(dopts, grpc.WithDefaultServiceConfig(retryServiceConfig(o.retryAttempts, o.retryCodes)))
+	}
+	if o.tokens != nil {
+		dopts = append(dopts, grpc.WithPerRPCCredentials(&serviceTokenCreds{st: o.tokens, audience: o.audience}))
+	}
+	unary := []grpc.UnaryClientInterceptor{UnaryClientLogging(o.log)}
+	var stream []grpc.StreamClientInterceptor
+	if o.tracing {
+		unary = append([]grpc.UnaryClientInterceptor{UnaryClientTracing()}, unary...)
+		stream = append(stream, StreamClientTracing())
+	}
+	dopts = append(dopts, grpc.WithChainUnaryInterceptor(unary...), grpc.WithChainStreamInterceptor(stream...))
+	return grpc.NewClient(target, append(dopts, o.extra...)...)
+}
+
+// retryServiceConfig 生成对所有方法生效的重试策略
+func retryServiceConfig(attempts int, retryable []codes.Code) string {
+	if len(retryable) == 0 {
+		retryable = []codes.Code{codes.Unavailable}
+	}
+	names := make([]string, len(retryable))
+	for i, c := range retryable {
+		names[i] = codeName(c)
+	}
+	cfg := map[string]any{
+		"methodConfig": []any{map[string]any{
+			"name": []any{map[string]any{}},
+			"retryPolicy": map[string]any{
+				"maxAttempts":          attempts,
+				"initialBackoff":       protoDuration(defaultRetryBackoff),
+				"maxBackoff":           protoDuration(defaultRetryMaxBackoff),
+				"backoffMultiplier":    2,
+				"retryableStatusCodes": names,
+			},
+		}},
+	}
+	data, _ := json.Marshal(cfg)
+	return string(data)
+}
+
+// protoDuration 服务配置要求的时长格式，以秒为单位，如 "0.1s"
+func protoDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// codeName 将状态码转换为服务配置使用的名称，如 DeadlineExceeded -> DEADLINE_EXCEEDED
+func codeName(c codes.Code) string {
+	var b strings.Builder
+	for i, r := range c.String() {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// serviceTokenCreds 以服务间 token 实现 PerRPCCredentials
+type serviceTokenCreds struct {
+	st       *utils.ServiceTokenIssuer
+	audience string
+}
+
+// GetRequestMetadata 实现 credentials.PerRPCCredentials
+func (c *serviceTokenCreds) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := c.st.Token(c.audience)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+// RequireTransportSecurity 集群内部调用允许不使用 TLS
+func (c *serviceTokenCreds) RequireTransportSecurity() bool { return false }
@@ -0,0 +1,111 @@
+package grpcx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+	"github.com/qingfeng-studio/go-utils/utils"
+	"github.com/qingfeng-studio/go-utils/utils/health"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testLogger 日志写入测试临时目录
+func testLogger(t *testing.T) *logger.Logger {
+	return logger.New(&logger.Config{FileName: filepath.Join(t.TempDir(), "app.log")})
+}
+
+func TestHealthService(t *testing.T) {
+	l := testLogger(t)
+	checker := health.New()
+	checker.AddLiveness("loop", func(ctx context.Context) error { return nil })
+	st := utils.NewServiceTokenIssuer(utils.ServiceTokenConfig{Service: "inventory-svc", Secret: []byte("secret")})
+
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(WithServerLogger(l), WithHealth(checker), WithAuth(st))
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := Dial("passthrough:///bufnet", WithInsecure(), WithLogger(l), WithRetry(3), WithTracing(),
+		WithServiceToken(st, "inventory-svc"),
+		WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) })))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "liveness"})
+	if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("liveness = %v %v", resp, err)
+	}
+	checker.MarkShuttingDown()
+	resp, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil || resp.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("readiness after shutdown = %v %v", resp, err)
+	}
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "orders"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown service = %v", err)
+	}
+}
+
+func TestUnaryServerAuth(t *testing.T) {
+	server := utils.NewServiceTokenIssuer(utils.ServiceTokenConfig{Service: "inventory-svc", Secret: []byte("secret"), Callers: []string{"order-svc"}})
+	caller := utils.NewServiceTokenIssuer(utils.ServiceTokenConfig{Service: "order-svc", Secret: []byte("secret")})
+	stranger := utils.NewServiceTokenIssuer(utils.ServiceTokenConfig{Service: "unknown-svc", Secret: []byte("secret")})
+	auth := UnaryServerAuth(server, "/inventory.Public/")
+	info := &grpc.UnaryServerInfo{FullMethod: "/inventory.Stock/Get"}
+
+	var got string
+	handler := func(ctx context.Context, req any) (any, error) {
+		if c, ok := utils.ClaimsFromContext[*utils.ServiceClaims](ctx); ok {
+			got = c.Caller()
+		}
+		return "ok", nil
+	}
+	withToken := func(st *utils.ServiceTokenIssuer) context.Context {
+		token, _ := st.Token("inventory-svc")
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	if _, err := auth(withToken(caller), nil, info, handler); err != nil || got != "order-svc" {
+		t.Errorf("valid token: caller=%q err=%v", got, err)
+	}
+	if _, err := auth(context.Background(), nil, info, handler); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("missing token = %v", err)
+	}
+	if _, err := auth(withToken(stranger), nil, info, handler); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("caller not allowed = %v", err)
+	}
+	if _, err := auth(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/inventory.Public/List"}, handler); err != nil {
+		t.Errorf("skipped method = %v", err)
+	}
+}
+
+func TestUnaryServerRecovery(t *testing.T) {
+	rec := UnaryServerRecovery(testLogger(t))
+	_, err := rec(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/a.B/C"}, func(ctx context.Context, req any) (any, error) {
+		panic(errors.New("boom"))
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRetryServiceConfig(t *testing.T) {
+	cfg := retryServiceConfig(3, []codes.Code{codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted})
+	for _, want := range []string{`"maxAttempts":3`, `"UNAVAILABLE"`, `"DEADLINE_EXCEEDED"`, `"RESOURCE_EXHAUSTED"`} {
+		if !strings.Contains(cfg, want) {
+			t.Errorf("service config missing %s: %s", want, cfg)
+		}
+	}
+}
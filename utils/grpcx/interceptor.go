@@ -0,0 +1,255 @@
+package grpcx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+	"github.com/qingfeng-studio/go-utils/utils"
+	"github.com/qingfeng-studio/go-utils/utils/otelx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// logRPC 记录一次调用，服务端错误类的状态码使用 error 级别
+func logRPC(ctx context.Context, l *logger.Logger, msg, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("code", code.String()),
+		zap.Duration("latency", time.Since(start)),
+	}
+	switch code {
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unavailable, codes.DeadlineExceeded:
+		l.Error(ctx, msg, append(fields, zap.Error(err))...)
+	default:
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+		l.Info(ctx, msg, fields...)
+	}
+}
+
+// UnaryClientLogging 记录每次客户端调用的方法、状态码与耗时
+func UnaryClientLogging(l *logger.Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logRPC(ctx, l, "grpc client call", method, start, err)
+		return err
+	}
+}
+
+// mdCarrier 以 gRPC 元数据实现 propagation.TextMapCarrier
+type mdCarrier metadata.MD
+
+func (c mdCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c mdCarrier) Set(key, value string) { metadata.MD(c).Set(key, value) }
+
+func (c mdCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// rpcAttrs span 的 rpc.* 属性，method 形如 /package.Service/Method
+func rpcAttrs(method string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("rpc.system", "grpc")}
+	if svc, m, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/"); ok {
+		attrs = append(attrs, attribute.String("rpc.service", svc), attribute.String("rpc.method", m))
+	}
+	return attrs
+}
+
+// startClientSpan 开启客户端 span 并将链路信息写入出站元数据
+func startClientSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	ctx, span := otelx.StartClient(ctx, "grpcx", method, rpcAttrs(method)...)
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	otel.GetTextMapPropagator().Inject(ctx, mdCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// endSpan 记录状态码并结束 span
+func endSpan(span trace.Span, err error) {
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(status.Code(err))))
+	otelx.End(span, err)
+}
+
+// UnaryClientTracing 为客户端调用创建 span，并通过元数据透传链路信息
+func UnaryClientTracing() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := startClientSpan(ctx, method)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		endSpan(span, err)
+		return err
+	}
+}
+
+// StreamClientTracing 为客户端流创建 span，span 覆盖流的建立过程
+func StreamClientTracing() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := startClientSpan(ctx, method)
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		endSpan(span, err)
+		return cs, err
+	}
+}
+
+// wrappedStream 替换 ServerStream 的 context
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context { return w.ctx }
+
+// startServerSpan 从入站元数据中恢复上游链路并开启服务端 span，同时写入 logger 使用的 traceId
+func startServerSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, mdCarrier(md))
+	ctx, span := otelx.Tracer("grpcx").Start(ctx, method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(rpcAttrs(method)...))
+	return otelx.WithLogTraceID(ctx), span
+}
+
+// UnaryServerTracing 为服务端调用创建 span，延续上游透传的链路
+func UnaryServerTracing() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, span := startServerSpan(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		endSpan(span, err)
+		return resp, err
+	}
+}
+
+// StreamServerTracing 为服务端流创建 span
+func StreamServerTracing() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startServerSpan(ss.Context(), info.FullMethod)
+		err := handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+		endSpan(span, err)
+		return err
+	}
+}
+
+// recoverErr 记录 panic 并转换为 Internal 错误
+func recoverErr(ctx context.Context, l *logger.Logger, method string, p any) error {
+	l.Error(ctx, "grpcx: panic recovered",
+		zap.String("method", method),
+		zap.String("panic", fmt.Sprint(p)),
+		zap.ByteString("stack", debug.Stack()))
+	return status.Error(codes.Internal, "internal error")
+}
+
+// UnaryServerRecovery 捕获 handler 的 panic，记录日志与调用栈并返回 Internal
+func UnaryServerRecovery(l *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recoverErr(ctx, l, info.FullMethod, p)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerRecovery 捕获流式 handler 的 panic
+func StreamServerRecovery(l *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recoverErr(ss.Context(), l, info.FullMethod, p)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// UnaryServerLogging 记录每次服务端调用的方法、状态码与耗时
+func UnaryServerLogging(l *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logRPC(ctx, l, "grpc request", info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerLogging 流结束时记录方法、状态码与持续时间
+func StreamServerLogging(l *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logRPC(ss.Context(), l, "grpc stream", info.FullMethod, start, err)
+		return err
+	}
+}
+
+// healthMethodPrefix 健康检查服务的方法前缀，探针不携带 token，始终跳过认证
+const healthMethodPrefix = "/grpc.health.v1.Health/"
+
+// authenticate 校验 authorization 元数据中的服务间 token，成功后 *utils.ServiceClaims 写入 context
+func authenticate(ctx context.Context, st *utils.ServiceTokenIssuer, method string, skip []string) (context.Context, error) {
+	if strings.HasPrefix(method, healthMethodPrefix) {
+		return ctx, nil
+	}
+	for _, s := range skip {
+		if method == s || (strings.HasSuffix(s, "/") && strings.HasPrefix(method, s)) {
+			return ctx, nil
+		}
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	token, ok := strings.CutPrefix(mdCarrier(md).Get("authorization"), "Bearer ")
+	if !ok || token == "" {
+		return nil, status.Error(codes.Unauthenticated, utils.ErrTokenMissing.Error())
+	}
+	claims, err := st.Verify(token)
+	if errors.Is(err, utils.ErrServiceNotAllowed) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid service token")
+	}
+	return utils.ContextWithClaims(ctx, claims), nil
+}
+
+// UnaryServerAuth 校验服务间 token，skip 中的完整方法名或以 "/" 结尾的服务前缀不校验；
+// 缺少或无效的 token 返回 Unauthenticated，调用方不在允许列表返回 PermissionDenied
+func UnaryServerAuth(st *utils.ServiceTokenIssuer, skip ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, st, info.FullMethod, skip)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerAuth 流式调用的服务间 token 校验，规则同 UnaryServerAuth
+func StreamServerAuth(st *utils.ServiceTokenIssuer, skip ...string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), st, info.FullMethod, skip)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
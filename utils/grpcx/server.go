@@ -0,0 +1,115 @@
+package grpcx
+
+import (
+	"context"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+	"github.com/qingfeng-studio/go-utils/utils"
+	"github.com/qingfeng-studio/go-utils/utils/health"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+// serverOptions NewServer 选项
+type serverOptions struct {
+	log      *logger.Logger
+	tokens   *utils.ServiceTokenIssuer
+	authSkip []string
+	health   *health.Checker
+	tracing  bool
+	extra    []grpc.ServerOption
+}
+
+// ServerOption NewServer 的函数式选项
+type ServerOption func(*serverOptions)
+
+// WithServerLogger 指定访问日志与 panic 日志使用的 logger，默认 logger.Default()
+func WithServerLogger(l *logger.Logger) ServerOption {
+	return func(o *serverOptions) { o.log = l }
+}
+
+// WithAuth 要求调用携带服务间 token（见客户端的 WithServiceToken），健康检查服务始终跳过；
+// skip 为不校验的完整方法名或以 "/" 结尾的服务前缀，handler 中可用 utils.ClaimsFromContext[*utils.ServiceClaims] 读取调用方
+func WithAuth(st *utils.ServiceTokenIssuer, skip ...string) ServerOption {
+	return func(o *serverOptions) { o.tokens, o.authSkip = st, skip }
+}
+
+// WithHealth 注册标准健康检查服务 grpc.health.v1.Health：服务名为空或 "readiness" 时执行就绪检查，
+// "liveness" 时执行存活检查，degraded 视为 SERVING
+func WithHealth(h *health.Checker) ServerOption {
+	return func(o *serverOptions) { o.health = h }
+}
+
+// WithServerTracing 为每次调用创建服务端 span，需先调用 otelx.Init
+func WithServerTracing() ServerOption {
+	return func(o *serverOptions) { o.tracing = true }
+}
+
+// WithServerOptions 追加原生的 grpc.ServerOption，如 grpc.Creds、grpc.MaxRecvMsgSize
+func WithServerOptions(opts ...grpc.ServerOption) ServerOption {
+	return func(o *serverOptions) { o.extra = append(o.extra, opts...) }
+}
+
+// NewServer 创建 gRPC 服务，拦截器由外到内依次为：链路追踪、访问日志、panic 恢复、认证；
+// 允许客户端最短 10 秒一次的 keepalive 探测，与 Dial 的默认值兼容
+func NewServer(opts ...ServerOption) *grpc.Server {
+	o := serverOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.log == nil {
+		o.log = logger.Default()
+	}
+
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	if o.tracing {
+		unary = append(unary, UnaryServerTracing())
+		stream = append(stream, StreamServerTracing())
+	}
+	unary = append(unary, UnaryServerLogging(o.log), UnaryServerRecovery(o.log))
+	stream = append(stream, StreamServerLogging(o.log), StreamServerRecovery(o.log))
+	if o.tokens != nil {
+		unary = append(unary, UnaryServerAuth(o.tokens, o.authSkip...))
+		stream = append(stream, StreamServerAuth(o.tokens, o.authSkip...))
+	}
+
+	sopts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{MinTime: 10 * time.Second, PermitWithoutStream: true}),
+	}
+	srv := grpc.NewServer(append(sopts, o.extra...)...)
+	if o.health != nil {
+		healthpb.RegisterHealthServer(srv, &healthServer{h: o.health})
+	}
+	return srv
+}
+
+// healthServer 以 health.Checker 实现 grpc.health.v1.Health
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	h *health.Checker
+}
+
+// Check 执行对应的检查，未知的服务名返回 NotFound
+func (s *healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	var report health.Report
+	switch req.GetService() {
+	case "", "readiness":
+		report = s.h.Readiness(ctx)
+	case "liveness":
+		report = s.h.Liveness(ctx)
+	default:
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	st := healthpb.HealthCheckResponse_SERVING
+	if report.Status == health.StatusDown {
+		st = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
@@ -0,0 +1,61 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PingDB 数据库连通性检查，*sql.DB 满足该接口
+func PingDB(db interface {
+	PingContext(ctx context.Context) error
+}) CheckFunc {
+	return db.PingContext
+}
+
+// PingRedis Redis 连通性检查，*redis.Client、*redis.ClusterClient 均满足该接口
+func PingRedis(c interface {
+	Ping(ctx context.Context) *redis.StatusCmd
+}) CheckFunc {
+	return func(ctx context.Context) error {
+		return c.Ping(ctx).Err()
+	}
+}
+
+// HTTPGet 请求 url，状态码不是 2xx 时视为失败，适合检查强依赖的下游服务
+func HTTPGet(client *http.Client, url string) CheckFunc {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("health: %s returned %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// DiskSpace 检查 path 所在文件系统的可用空间不少于 minFree 字节
+func DiskSpace(path string, minFree uint64) CheckFunc {
+	return func(ctx context.Context) error {
+		free, err := diskFree(path)
+		if err != nil {
+			return err
+		}
+		if free < minFree {
+			return fmt.Errorf("health: %s has %d bytes free, want at least %d", path, free, minFree)
+		}
+		return nil
+	}
+}
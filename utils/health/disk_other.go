@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd)
+
+package health
+
+import "errors"
+
+// diskFree 当前平台不支持
+func diskFree(path string) (uint64, error) {
+	return 0, errors.New("health: disk space check is not supported on this platform")
+}
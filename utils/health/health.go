@@ -0,0 +1,297 @@
+// Package health 健康检查聚合：各组件注册存活（liveness）与就绪（readiness）探针，
+// 处理器并发执行检查并输出整体状态与各项耗时，供 Kubernetes 探针与负载均衡使用
+// 使用示例：
+//
+//	h := health.New()
+//	h.AddReadiness("mysql", health.PingDB(db))
+//	h.AddReadiness("redis", health.PingRedis(rdb))
+//	h.AddReadiness("disk", health.DiskSpace("/data", 1<<30), health.NonCritical())
+//	mux.Handle("/healthz", h.LivenessHandler())
+//	mux.Handle("/readyz", h.ReadinessHandler())
+//	// 优雅退出开始时先摘除流量
+//	h.MarkShuttingDown()
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 检查状态
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded" // 只有非关键检查失败，仍可接收流量
+)
+
+const (
+	// defaultCheckTimeout 单项检查的默认超时
+	defaultCheckTimeout = 2 * time.Second
+)
+
+var (
+	// ErrShuttingDown 服务正在退出，就绪检查失败以摘除流量
+	ErrShuttingDown = errors.New("health: shutting down")
+	// ErrNotReady 服务尚未完成启动
+	ErrNotReady = errors.New("health: not ready")
+)
+
+// CheckFunc 检查函数，返回 nil 表示健康；应遵守 ctx 的超时
+type CheckFunc func(ctx context.Context) error
+
+// checkOptions 单项检查选项
+type checkOptions struct {
+	timeout     time.Duration
+	nonCritical bool
+}
+
+// CheckOption 单项检查的函数式选项
+type CheckOption func(*checkOptions)
+
+// WithCheckTimeout 设置该项检查的超时，默认使用 New 的超时
+func WithCheckTimeout(d time.Duration) CheckOption {
+	return func(o *checkOptions) { o.timeout = d }
+}
+
+// NonCritical 该项失败时整体状态为 degraded，HTTP 状态码仍为 200，适合磁盘余量等预警类检查
+func NonCritical() CheckOption {
+	return func(o *checkOptions) { o.nonCritical = true }
+}
+
+// check 已注册的检查
+type check struct {
+	name string
+	fn   CheckFunc
+	opts checkOptions
+}
+
+// CheckResult 单项检查结果
+type CheckResult struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Critical bool          `json:"critical"`
+	Latency  time.Duration `json:"-"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// MarshalJSON 耗时以毫秒输出
+func (r CheckResult) MarshalJSON() ([]byte, error) {
+	type alias CheckResult
+	return json.Marshal(struct {
+		alias
+		LatencyMs float64 `json:"latencyMs"`
+	}{alias(r), float64(r.Latency.Microseconds()) / 1000})
+}
+
+// Report 聚合结果
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Option Checker 选项
+type Option func(*Checker)
+
+// WithTimeout 设置单项检查的默认超时，默认 2 秒
+func WithTimeout(d time.Duration) Option {
+	return func(c *Checker) { c.timeout = d }
+}
+
+// WithCacheTTL 在 ttl 内复用上一次的检查结果，避免探针频繁访问数据库，默认不缓存
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *Checker) { c.cacheTTL = ttl }
+}
+
+// WithStartupGate 启动完成前就绪检查返回 ErrNotReady，需在启动完成后调用 SetReady(true)
+func WithStartupGate() Option {
+	return func(c *Checker) { c.ready.Store(false) }
+}
+
+// cachedReport 缓存的检查结果
+type cachedReport struct {
+	report Report
+	at     time.Time
+}
+
+// Checker 健康检查注册表，并发安全
+type Checker struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	ready        atomic.Bool
+	shuttingDown atomic.Bool
+
+	mu        sync.RWMutex
+	liveness  []check
+	readiness []check
+	cache     map[bool]cachedReport // readiness -> 结果
+}
+
+// New 创建健康检查注册表
+func New(opts ...Option) *Checker {
+	c := &Checker{timeout: defaultCheckTimeout, cache: make(map[bool]cachedReport)}
+	c.ready.Store(true)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// AddLiveness 注册存活检查，失败时编排系统会重启进程，只应检查进程自身（如死锁、事件循环卡住），不要检查外部依赖
+func (c *Checker) AddLiveness(name string, fn CheckFunc, opts ...CheckOption) {
+	c.add(&c.liveness, name, fn, opts)
+}
+
+// AddReadiness 注册就绪检查，失败时摘除流量但不重启，适合数据库、缓存等依赖
+func (c *Checker) AddReadiness(name string, fn CheckFunc, opts ...CheckOption) {
+	c.add(&c.readiness, name, fn, opts)
+}
+
+// add 注册检查，同名检查会被替换
+func (c *Checker) add(list *[]check, name string, fn CheckFunc, opts []CheckOption) {
+	o := checkOptions{timeout: c.timeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range *list {
+		if (*list)[i].name == name {
+			(*list)[i] = check{name: name, fn: fn, opts: o}
+			return
+		}
+	}
+	*list = append(*list, check{name: name, fn: fn, opts: o})
+}
+
+// SetReady 设置启动是否完成，配合 WithStartupGate 使用
+func (c *Checker) SetReady(ready bool) { c.ready.Store(ready) }
+
+// MarkShuttingDown 标记服务正在退出，之后就绪检查始终失败；优雅退出时应先调用，
+// 等待负载均衡摘除流量后再关闭监听
+func (c *Checker) MarkShuttingDown() { c.shuttingDown.Store(true) }
+
+// Liveness 执行存活检查
+func (c *Checker) Liveness(ctx context.Context) Report {
+	return c.run(ctx, false)
+}
+
+// Readiness 执行就绪检查，退出中或启动未完成时直接返回 down
+func (c *Checker) Readiness(ctx context.Context) Report {
+	var gate error
+	switch {
+	case c.shuttingDown.Load():
+		gate = ErrShuttingDown
+	case !c.ready.Load():
+		gate = ErrNotReady
+	}
+	if gate != nil {
+		return Report{Status: StatusDown, Checks: []CheckResult{{Name: "lifecycle", Status: StatusDown, Critical: true, Error: gate.Error()}}}
+	}
+	return c.run(ctx, true)
+}
+
+// run 并发执行检查并聚合结果
+func (c *Checker) run(ctx context.Context, readiness bool) Report {
+	c.mu.RLock()
+	checks := c.liveness
+	if readiness {
+		checks = c.readiness
+	}
+	checks = append([]check(nil), checks...)
+	cached, ok := c.cache[readiness]
+	c.mu.RUnlock()
+	if ok && c.cacheTTL > 0 && time.Since(cached.at) < c.cacheTTL {
+		return cached.report
+	}
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, ch := range checks {
+		wg.Add(1)
+		go func(i int, ch check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, ch)
+		}(i, ch)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, r := range results {
+		if r.Status == StatusUp {
+			continue
+		}
+		if r.Critical {
+			report.Status = StatusDown
+			break
+		}
+		report.Status = StatusDegraded
+	}
+	if c.cacheTTL > 0 {
+		c.mu.Lock()
+		c.cache[readiness] = cachedReport{report: report, at: time.Now()}
+		c.mu.Unlock()
+	}
+	return report
+}
+
+// runCheck 带超时执行单项检查，检查函数 panic 时视为失败
+func runCheck(ctx context.Context, ch check) (res CheckResult) {
+	res = CheckResult{Name: ch.name, Status: StatusUp, Critical: !ch.opts.nonCritical}
+	if ch.opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ch.opts.timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	defer func() { res.Latency = time.Since(start) }()
+
+	errc := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				errc <- errors.New("panic during check")
+			}
+		}()
+		errc <- ch.fn(ctx)
+	}()
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		// 检查函数未遵守 ctx 时不再等待
+		err = ctx.Err()
+	}
+	if err != nil {
+		res.Status, res.Error = StatusDown, err.Error()
+	}
+	return res
+}
+
+// LivenessHandler 输出存活检查结果，down 时返回 503
+func (c *Checker) LivenessHandler() http.Handler {
+	return reportHandler(c.Liveness)
+}
+
+// ReadinessHandler 输出就绪检查结果，down 时返回 503
+func (c *Checker) ReadinessHandler() http.Handler {
+	return reportHandler(c.Readiness)
+}
+
+// reportHandler 以 JSON 输出检查结果
+func reportHandler(run func(ctx context.Context) Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := run(r.Context())
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Status == StatusDown {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChecker_Readiness(t *testing.T) {
+	h := New(WithTimeout(50 * time.Millisecond))
+	h.AddReadiness("db", func(ctx context.Context) error { return nil })
+	h.AddReadiness("disk", func(ctx context.Context) error { return errors.New("low") }, NonCritical())
+	if r := h.Readiness(context.Background()); r.Status != StatusDegraded {
+		t.Fatalf("status = %s, want degraded", r.Status)
+	}
+
+	h.AddReadiness("redis", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	r := h.Readiness(context.Background())
+	if r.Status != StatusDown || len(r.Checks) != 3 {
+		t.Fatalf("report = %+v", r)
+	}
+	if c := r.Checks[2]; c.Name != "redis" || c.Error == "" || c.Latency < 50*time.Millisecond {
+		t.Errorf("redis check = %+v", c)
+	}
+
+	h.AddReadiness("panic", func(ctx context.Context) error { panic("boom") })
+	if r := h.Readiness(context.Background()); r.Checks[3].Status != StatusDown {
+		t.Errorf("panicking check should be down: %+v", r.Checks[3])
+	}
+}
+
+func TestChecker_Lifecycle(t *testing.T) {
+	h := New(WithStartupGate())
+	h.AddLiveness("loop", func(ctx context.Context) error { return nil })
+	if r := h.Readiness(context.Background()); r.Status != StatusDown {
+		t.Error("readiness should fail before SetReady")
+	}
+	h.SetReady(true)
+	if r := h.Readiness(context.Background()); r.Status != StatusUp {
+		t.Errorf("status = %s, want up", r.Status)
+	}
+	h.MarkShuttingDown()
+	if r := h.Readiness(context.Background()); r.Status != StatusDown || r.Checks[0].Error != ErrShuttingDown.Error() {
+		t.Errorf("report = %+v", r)
+	}
+	if r := h.Liveness(context.Background()); r.Status != StatusUp {
+		t.Error("liveness should not depend on shutdown")
+	}
+}
+
+func TestChecker_Handler(t *testing.T) {
+	h := New()
+	h.AddReadiness("db", func(ctx context.Context) error { return errors.New("refused") })
+	rec := httptest.NewRecorder()
+	h.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("code = %d", rec.Code)
+	}
+	var body struct {
+		Status string `json:"status"`
+		Checks []struct {
+			Name      string   `json:"name"`
+			Error     string   `json:"error"`
+			LatencyMs *float64 `json:"latencyMs"`
+		} `json:"checks"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != StatusDown || body.Checks[0].Error != "refused" || body.Checks[0].LatencyMs == nil {
+		t.Errorf("body = %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("liveness code = %d", rec.Code)
+	}
+}
+
+func TestDiskSpace(t *testing.T) {
+	if err := DiskSpace(t.TempDir(), 1)(context.Background()); err != nil {
+		t.Skip(err)
+	}
+	if err := DiskSpace(t.TempDir(), 1<<62)(context.Background()); err == nil {
+		t.Error("expected insufficient space error")
+	}
+}
@@ -0,0 +1,246 @@
+// Package humanize 面向展示的数值格式化：字节大小、相对时间与时长、大数缩写与百分比，
+// 中英文两种风格（locale 以 "zh" 开头时为中文，其余为英文），用于看板与通知文案
+package humanize
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSize 无法解析的字节大小
+var ErrInvalidSize = errors.New("humanize: invalid size")
+
+// 十进制（SI）与二进制（IEC）的单位
+var (
+	siUnits  = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+	iecUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+)
+
+// Bytes 按 1000 进制格式化字节数，如 1200000 -> "1.2 MB"，适合磁盘容量与网络流量
+func Bytes(n uint64) string {
+	return formatSize(n, 1000, siUnits)
+}
+
+// IBytes 按 1024 进制格式化字节数，如 1073741824 -> "1 GiB"，适合内存与文件大小
+func IBytes(n uint64) string {
+	return formatSize(n, 1024, iecUnits)
+}
+
+// formatSize 保留一位小数，整数时省略小数部分
+func formatSize(n uint64, base float64, units []string) string {
+	if float64(n) < base {
+		return strconv.FormatUint(n, 10) + " B"
+	}
+	v := float64(n)
+	i := 0
+	for v >= base && i < len(units)-1 {
+		v /= base
+		i++
+	}
+	// 四舍五入后进位到下一个单位，如 999.96 kB -> 1 MB
+	if math.Round(v*10)/10 >= base && i < len(units)-1 {
+		v /= base
+		i++
+	}
+	return trimFloat(v, 1) + " " + units[i]
+}
+
+// ParseBytes 解析带单位的字节大小，单位不区分大小写：k/kb 为 1000 进制，ki/kib 为 1024 进制，
+// 无单位时为字节，如 "10MB"、"1.5 GiB"、"512k"
+func ParseBytes(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	num, unit := s, ""
+	if i >= 0 {
+		num, unit = s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSize, s)
+	}
+	unit = strings.TrimSuffix(unit, "b")
+	base := 1000.0
+	if strings.HasSuffix(unit, "i") {
+		base = 1024
+		unit = strings.TrimSuffix(unit, "i")
+	}
+	exp := strings.Index("kmgtpe", unit) + 1
+	if unit != "" && (len(unit) != 1 || exp == 0) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSize, s)
+	}
+	if unit == "" {
+		exp = 0
+	}
+	v *= math.Pow(base, float64(exp))
+	if v >= math.MaxUint64 {
+		return 0, fmt.Errorf("%w: %q overflows", ErrInvalidSize, s)
+	}
+	return uint64(v), nil
+}
+
+// isZh 判断是否使用中文风格
+func isZh(locale string) bool {
+	return strings.HasPrefix(strings.ToLower(locale), "zh")
+}
+
+// relUnit 相对时间与时长的单位
+type relUnit struct {
+	d      time.Duration
+	zh, en string
+}
+
+var relUnits = []relUnit{
+	{365 * 24 * time.Hour, "年", "y"},
+	{30 * 24 * time.Hour, "个月", "mo"},
+	{24 * time.Hour, "天", "d"},
+	{time.Hour, "小时", "h"},
+	{time.Minute, "分钟", "m"},
+	{time.Second, "秒", "s"},
+}
+
+// Ago 返回 t 相对当前时间的描述，见 RelTime
+func Ago(t time.Time, locale string) string {
+	return RelTime(t, time.Now(), locale)
+}
+
+// RelTime 返回 t 相对 now 的描述，取最大的整数单位：中文如 "刚刚"、"3分钟前"、"2天后"，
+// 英文如 "just now"、"3m ago"、"in 2d"；10 秒以内视为刚刚，月按 30 天、年按 365 天计算
+func RelTime(t, now time.Time, locale string) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	zh := isZh(locale)
+	if d < 10*time.Second {
+		if zh {
+			return "刚刚"
+		}
+		return "just now"
+	}
+	for _, u := range relUnits {
+		if d < u.d {
+			continue
+		}
+		n := strconv.FormatInt(int64(d/u.d), 10)
+		switch {
+		case zh && future:
+			return n + u.zh + "后"
+		case zh:
+			return n + u.zh + "前"
+		case future:
+			return "in " + n + u.en
+		default:
+			return n + u.en + " ago"
+		}
+	}
+	return ""
+}
+
+// Duration 以最大的两个单位描述时长，如中文 "1小时5分钟"、英文 "1h 5m"，不足 1 秒时为 "0秒"/"0s"
+func Duration(d time.Duration, locale string) string {
+	zh := isZh(locale)
+	neg := d < 0
+	if neg {
+		d = -d
+	}
+	var parts []string
+	for _, u := range relUnits {
+		if d < u.d {
+			if len(parts) > 0 {
+				break // 只保留相邻的两个单位，如 "1天3小时" 而非 "1天3秒"
+			}
+			continue
+		}
+		n := strconv.FormatInt(int64(d/u.d), 10)
+		d %= u.d
+		if zh {
+			parts = append(parts, n+u.zh)
+		} else {
+			parts = append(parts, n+u.en)
+		}
+		if len(parts) == 2 || d == 0 {
+			break
+		}
+	}
+	if len(parts) == 0 {
+		if zh {
+			return "0秒"
+		}
+		return "0s"
+	}
+	sep := " "
+	if zh {
+		sep = ""
+	}
+	s := strings.Join(parts, sep)
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// compactUnit 大数缩写的单位
+type compactUnit struct {
+	v      float64
+	suffix string
+}
+
+var (
+	zhCompact = []compactUnit{{1e8, "亿"}, {1e4, "w"}}
+	enCompact = []compactUnit{{1e12, "T"}, {1e9, "B"}, {1e6, "M"}, {1e3, "k"}}
+)
+
+// Compact 缩写大数并保留一位小数：中文按万/亿，如 12345 -> "1.2w"、320000000 -> "3.2亿"；
+// 英文按千进制，如 1234 -> "1.2k"、5600000 -> "5.6M"；小于单位的数原样输出（保留一位小数）
+func Compact(n float64, locale string) string {
+	units := enCompact
+	if isZh(locale) {
+		units = zhCompact
+	}
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	for i, u := range units {
+		if n < u.v {
+			continue
+		}
+		v := n / u.v
+		// 四舍五入后达到上一级单位时进位，如 9999.96w -> 1亿
+		if i > 0 && math.Round(v*10)/10*u.v >= units[i-1].v {
+			return sign + trimFloat(n/units[i-1].v, 1) + units[i-1].suffix
+		}
+		return sign + trimFloat(v, 1) + u.suffix
+	}
+	return sign + trimFloat(n, 1)
+}
+
+// Percent 将比例格式化为百分比，如 Percent(0.1234, 1) -> "12.3%"；NaN 与无穷大返回 "-"
+func Percent(ratio float64, decimals int) string {
+	if math.IsNaN(ratio) || math.IsInf(ratio, 0) {
+		return "-"
+	}
+	return strconv.FormatFloat(ratio*100, 'f', decimals, 64) + "%"
+}
+
+// PercentOf 计算 part 占 total 的百分比，total 为 0 时返回 "-"
+func PercentOf(part, total float64, decimals int) string {
+	if total == 0 {
+		return "-"
+	}
+	return Percent(part/total, decimals)
+}
+
+// trimFloat 保留 decimals 位小数并去掉末尾的 0
+func trimFloat(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
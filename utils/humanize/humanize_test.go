@@ -0,0 +1,126 @@
+package humanize
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{Bytes(0), "0 B"},
+		{Bytes(999), "999 B"},
+		{Bytes(1200000), "1.2 MB"},
+		{Bytes(999960), "1 MB"},
+		{IBytes(1536), "1.5 KiB"},
+		{IBytes(1 << 30), "1 GiB"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint64
+	}{
+		{"512", 512},
+		{"10MB", 10_000_000},
+		{"1.5 GiB", 1536 << 20},
+		{"512k", 512_000},
+		{"2kib", 2048},
+	}
+	for _, tt := range tests {
+		if got, err := ParseBytes(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseBytes(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "abc", "10 XB", "-1MB", "1mbb"} {
+		if _, err := ParseBytes(in); err == nil {
+			t.Errorf("ParseBytes(%q) expected error", in)
+		}
+	}
+}
+
+func TestRelTime(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t      time.Time
+		locale string
+		want   string
+	}{
+		{now.Add(-5 * time.Second), "zh-CN", "刚刚"},
+		{now.Add(-3 * time.Minute), "zh-CN", "3分钟前"},
+		{now.Add(-3 * time.Minute), "en", "3m ago"},
+		{now.Add(49 * time.Hour), "zh", "2天后"},
+		{now.Add(49 * time.Hour), "en-US", "in 2d"},
+		{now.AddDate(-1, 0, -1), "en", "1y ago"},
+		{now.AddDate(0, -2, 0), "zh", "2个月前"},
+	}
+	for _, tt := range tests {
+		if got := RelTime(tt.t, now, tt.locale); got != tt.want {
+			t.Errorf("RelTime(%v, %s) = %q, want %q", now.Sub(tt.t), tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d      time.Duration
+		locale string
+		want   string
+	}{
+		{65 * time.Minute, "zh", "1小时5分钟"},
+		{65 * time.Minute, "en", "1h 5m"},
+		{time.Hour, "en", "1h"},
+		{27*time.Hour + 30*time.Second, "zh", "1天3小时"},
+		{24*time.Hour + 5*time.Second, "en", "1d"},
+		{500 * time.Millisecond, "en", "0s"},
+		{-90 * time.Second, "en", "-1m 30s"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.d, tt.locale); got != tt.want {
+			t.Errorf("Duration(%v, %s) = %q, want %q", tt.d, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		n      float64
+		locale string
+		want   string
+	}{
+		{999, "en", "999"},
+		{1234, "en", "1.2k"},
+		{5600000, "en", "5.6M"},
+		{999960, "en", "1M"},
+		{-1500, "en", "-1.5k"},
+		{12345, "zh", "1.2w"},
+		{320000000, "zh-CN", "3.2亿"},
+		{99999999, "zh", "1亿"},
+		{12.34, "zh", "12.3"},
+	}
+	for _, tt := range tests {
+		if got := Compact(tt.n, tt.locale); got != tt.want {
+			t.Errorf("Compact(%v, %s) = %q, want %q", tt.n, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	if got := Percent(0.1234, 1); got != "12.3%" {
+		t.Errorf("Percent = %q", got)
+	}
+	if got := PercentOf(1, 3, 2); got != "33.33%" {
+		t.Errorf("PercentOf = %q", got)
+	}
+	if Percent(math.NaN(), 1) != "-" || PercentOf(1, 0, 1) != "-" {
+		t.Error("invalid ratio should render as -")
+	}
+}
@@ -0,0 +1,324 @@
+// Package i18n 多语言消息：从 YAML 加载消息包，支持复数形式（CLDR 规则）、模板变量，
+// 以及按 Accept-Language 或 context 协商语言，适用于 API 错误提示与通知文案
+package i18n
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/qingfeng-studio/go-utils/config"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// pluralForms 复数形式名称 -> CLDR 形式
+var pluralForms = map[string]plural.Form{
+	"zero":  plural.Zero,
+	"one":   plural.One,
+	"two":   plural.Two,
+	"few":   plural.Few,
+	"many":  plural.Many,
+	"other": plural.Other,
+}
+
+// message 解析后的消息，plural 不为空时按数量选择形式
+type message struct {
+	text   *template.Template
+	plural map[plural.Form]*template.Template
+}
+
+// Bundle 消息包，加载完成后并发安全
+// 消息文件按语言命名（如 zh-CN.yaml、en.yaml，也可拆分为 en.errors.yaml），嵌套的 key 以 "." 连接；
+// 值为模板字符串（text/template 语法），只包含 zero/one/two/few/many/other 且含 other 的映射视为复数消息：
+//
+//	# en.yaml
+//	order:
+//	  not_found: "Order {{.ID}} not found"
+//	  items:
+//	    one: "{{.Count}} item"
+//	    other: "{{.Count}} items"
+//
+// 使用示例：
+//
+//	b := i18n.NewBundle("zh-CN")
+//	err := b.LoadFS(localesFS, "locales")
+//	mux.Handle("/", b.Middleware(handler))
+//	msg := b.T(ctx, "order.not_found", map[string]any{"ID": id})
+//	msg = b.Tn(ctx, "order.items", n, nil)
+type Bundle struct {
+	def language.Tag
+
+	mu       sync.RWMutex
+	messages map[language.Tag]map[string]*message
+	tags     []language.Tag // 已加载的语言，第一个为默认语言
+	matcher  language.Matcher
+}
+
+// NewBundle 创建消息包，defaultLocale 为协商失败或缺少消息时回退的语言（如 "zh-CN"），无法解析时使用 en
+func NewBundle(defaultLocale string) *Bundle {
+	def, err := language.Parse(defaultLocale)
+	if err != nil {
+		def = language.English
+	}
+	b := &Bundle{def: def, messages: make(map[language.Tag]map[string]*message)}
+	b.tags = []language.Tag{def}
+	b.matcher = language.NewMatcher(b.tags)
+	return b
+}
+
+// AddMessages 解析一份 YAML 消息并合并到指定语言，同名 key 覆盖之前加载的内容
+func (b *Bundle) AddMessages(locale string, data []byte) error {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return fmt.Errorf("i18n: invalid locale %q: %w", locale, err)
+	}
+	var raw map[string]interface{}
+	if err := config.ParseYAML(data, &raw); err != nil {
+		return fmt.Errorf("i18n: parse %s messages: %w", locale, err)
+	}
+	msgs := make(map[string]*message)
+	if err := flatten("", raw, msgs); err != nil {
+		return fmt.Errorf("i18n: %s: %w", locale, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.messages[tag] == nil {
+		b.messages[tag] = make(map[string]*message)
+		if tag != b.def {
+			b.tags = append(b.tags, tag)
+			b.matcher = language.NewMatcher(b.tags)
+		}
+	}
+	for k, m := range msgs {
+		b.messages[tag][k] = m
+	}
+	return nil
+}
+
+// LoadFS 加载 dir 目录下所有 .yaml/.yml 消息文件，文件名中第一个 "." 之前的部分为语言；
+// 从磁盘加载时使用 os.DirFS
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("i18n: read dir: %w", err)
+	}
+	for _, e := range entries {
+		ext := path.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return fmt.Errorf("i18n: read %s: %w", e.Name(), err)
+		}
+		locale, _, _ := strings.Cut(e.Name(), ".")
+		if err := b.AddMessages(locale, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Locales 已加载的语言，默认语言在前
+func (b *Bundle) Locales() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	out := make([]string, len(b.tags))
+	for i, t := range b.tags {
+		out[i] = t.String()
+	}
+	return out
+}
+
+// Match 按优先级依次尝试 Accept-Language 形式的语言偏好（如 "en-US,en;q=0.9"、"zh-TW"），
+// 返回已加载语言中最匹配的一个，都不匹配时返回默认语言
+func (b *Bundle) Match(prefs ...string) string {
+	var tags []language.Tag
+	for _, p := range prefs {
+		if t, _, err := language.ParseAcceptLanguage(p); err == nil {
+			tags = append(tags, t...)
+		}
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if len(tags) == 0 {
+		return b.def.String()
+	}
+	_, idx, conf := b.matcher.Match(tags...)
+	if conf == language.No {
+		return b.def.String()
+	}
+	return b.tags[idx].String()
+}
+
+// T 按 context 中的语言（见 WithLocale、Middleware）翻译消息，data 为模板变量；
+// 依次在该语言、其父语言（zh-Hant-TW -> zh-Hant -> zh）与默认语言中查找，都没有时返回 key
+func (b *Bundle) T(ctx context.Context, key string, data map[string]interface{}) string {
+	return b.Localize(LocaleFromContext(ctx), key, -1, data)
+}
+
+// Tn 翻译复数消息，按 count 与语言的复数规则选择形式，模板中可用 {{.Count}} 引用数量；
+// count 为 0 且消息定义了 zero 时优先使用 zero
+func (b *Bundle) Tn(ctx context.Context, key string, count int, data map[string]interface{}) string {
+	return b.Localize(LocaleFromContext(ctx), key, count, data)
+}
+
+// Localize 按指定语言翻译，count < 0 表示非复数消息，用于给离线用户发送通知等没有请求上下文的场景
+func (b *Bundle) Localize(locale, key string, count int, data map[string]interface{}) string {
+	if count >= 0 {
+		vars := make(map[string]interface{}, len(data)+1)
+		for k, v := range data {
+			vars[k] = v
+		}
+		vars["Count"] = count
+		data = vars
+	}
+	return b.translate(locale, key, count, data)
+}
+
+// translate 查找消息并渲染
+func (b *Bundle) translate(locale, key string, count int, data map[string]interface{}) string {
+	tag, m := b.lookup(locale, key)
+	if m == nil {
+		return key
+	}
+	tmpl := m.text
+	if m.plural != nil {
+		form := plural.Other
+		if count >= 0 {
+			form = plural.Cardinal.MatchPlural(tag, count, 0, 0, 0, 0)
+			if _, ok := m.plural[plural.Zero]; ok && count == 0 {
+				form = plural.Zero
+			}
+		}
+		if tmpl = m.plural[form]; tmpl == nil {
+			tmpl = m.plural[plural.Other]
+		}
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return key
+	}
+	return buf.String()
+}
+
+// lookup 按回退顺序查找消息，返回消息所属的语言
+func (b *Bundle) lookup(locale, key string) (language.Tag, *message) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	tag := b.def
+	if locale != "" {
+		if t, err := language.Parse(locale); err == nil {
+			tag = t
+		}
+	}
+	for t := tag; ; t = t.Parent() {
+		if m := b.messages[t][key]; m != nil {
+			return t, m
+		}
+		if t.IsRoot() {
+			break
+		}
+	}
+	return b.def, b.messages[b.def][key]
+}
+
+// localeKey context 中存放语言的 key
+type localeKey struct{}
+
+// WithLocale 将语言写入 context
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext 读取 context 中的语言，未设置时返回空字符串（翻译时使用默认语言）
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// Middleware 按查询参数 lang 与 Accept-Language 请求头协商语言并写入 request context，
+// 同时设置 Content-Language 响应头
+func (b *Bundle) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var prefs []string
+		if lang := r.URL.Query().Get("lang"); lang != "" {
+			prefs = append(prefs, lang)
+		}
+		prefs = append(prefs, r.Header.Values("Accept-Language")...)
+		locale := b.Match(prefs...)
+		w.Header().Set("Content-Language", locale)
+		next.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), locale)))
+	})
+}
+
+// flatten 将嵌套的 YAML 映射展开为 "a.b.c" 形式的 key 并解析模板
+func flatten(prefix string, raw map[string]interface{}, out map[string]*message) error {
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch v := raw[k].(type) {
+		case map[string]interface{}:
+			if isPlural(v) {
+				m := &message{plural: make(map[plural.Form]*template.Template, len(v))}
+				for name, text := range v {
+					s, ok := text.(string)
+					if !ok {
+						return fmt.Errorf("plural form %s.%s must be a string", key, name)
+					}
+					tmpl, err := template.New(key).Parse(s)
+					if err != nil {
+						return fmt.Errorf("parse %s.%s: %w", key, name, err)
+					}
+					m.plural[pluralForms[name]] = tmpl
+				}
+				out[key] = m
+				continue
+			}
+			if err := flatten(key, v, out); err != nil {
+				return err
+			}
+		case nil:
+			return fmt.Errorf("message %s is empty", key)
+		default:
+			tmpl, err := template.New(key).Parse(fmt.Sprint(v))
+			if err != nil {
+				return fmt.Errorf("parse %s: %w", key, err)
+			}
+			out[key] = &message{text: tmpl}
+		}
+	}
+	return nil
+}
+
+// isPlural 判断映射是否为复数消息：包含 other，且所有 key 都是复数形式名称
+func isPlural(m map[string]interface{}) bool {
+	if _, ok := m["other"]; !ok {
+		return false
+	}
+	for k := range m {
+		if _, ok := pluralForms[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
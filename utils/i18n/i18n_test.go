@@ -0,0 +1,122 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func newTestBundle(t *testing.T) *Bundle {
+	t.Helper()
+	fsys := fstest.MapFS{
+		"locales/zh-CN.yaml": {Data: []byte(`
+order:
+  not_found: "订单 {{.ID}} 不存在"
+  items:
+    other: "{{.Count}} 件商品"
+greeting: 你好
+`)},
+		"locales/en.yaml": {Data: []byte(`
+order:
+  not_found: "Order {{.ID}} not found"
+  items:
+    zero: "No items"
+    one: "{{.Count}} item"
+    other: "{{.Count}} items"
+`)},
+		"locales/ru.yaml": {Data: []byte(`
+files:
+  one: "{{.Count}} файл"
+  few: "{{.Count}} файла"
+  many: "{{.Count}} файлов"
+  other: "{{.Count}} файла"
+`)},
+		"locales/README.md": {Data: []byte("ignored")},
+	}
+	b := NewBundle("zh-CN")
+	if err := b.LoadFS(fsys, "locales"); err != nil {
+		t.Fatalf("LoadFS: %v", err)
+	}
+	return b
+}
+
+func TestBundle_Translate(t *testing.T) {
+	b := newTestBundle(t)
+	en := WithLocale(context.Background(), "en-US")
+
+	tests := []struct {
+		got, want string
+	}{
+		{b.T(en, "order.not_found", map[string]interface{}{"ID": 42}), "Order 42 not found"},
+		{b.T(context.Background(), "order.not_found", map[string]interface{}{"ID": 42}), "订单 42 不存在"},
+		{b.T(en, "greeting", nil), "你好"}, // 缺少时回退默认语言
+		{b.T(en, "missing.key", nil), "missing.key"},
+		{b.Tn(en, "order.items", 0, nil), "No items"},
+		{b.Tn(en, "order.items", 1, nil), "1 item"},
+		{b.Tn(en, "order.items", 5, nil), "5 items"},
+		{b.Tn(context.Background(), "order.items", 1, nil), "1 件商品"},
+		{b.Localize("ru", "files", 1, nil), "1 файл"},
+		{b.Localize("ru", "files", 3, nil), "3 файла"},
+		{b.Localize("ru", "files", 5, nil), "5 файлов"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestBundle_Match(t *testing.T) {
+	b := newTestBundle(t)
+	tests := []struct {
+		prefs []string
+		want  string
+	}{
+		{nil, "zh-CN"},
+		{[]string{"en-GB,en;q=0.9"}, "en"},
+		{[]string{"fr-FR, ru;q=0.8, en;q=0.5"}, "ru"},
+		{[]string{"ja"}, "zh-CN"},
+		{[]string{"", "en"}, "en"},
+	}
+	for _, tt := range tests {
+		if got := b.Match(tt.prefs...); got != tt.want {
+			t.Errorf("Match(%q) = %q, want %q", tt.prefs, got, tt.want)
+		}
+	}
+}
+
+func TestBundle_Middleware(t *testing.T) {
+	b := newTestBundle(t)
+	var msg string
+	h := b.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		msg = b.T(r.Context(), "order.not_found", map[string]interface{}{"ID": 1})
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if msg != "Order 1 not found" || rec.Header().Get("Content-Language") != "en" {
+		t.Errorf("unexpected message %q, Content-Language %q", msg, rec.Header().Get("Content-Language"))
+	}
+
+	// 查询参数优先于请求头
+	req = httptest.NewRequest(http.MethodGet, "/?lang=zh-CN", nil)
+	req.Header.Set("Accept-Language", "en")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if msg != "订单 1 不存在" {
+		t.Errorf("lang query should win, got %q", msg)
+	}
+}
+
+func TestBundle_AddMessagesError(t *testing.T) {
+	b := NewBundle("en")
+	if err := b.AddMessages("en", []byte(`bad: "{{.Name"`)); err == nil {
+		t.Error("expected template parse error")
+	}
+	if err := b.AddMessages("not a locale!", []byte(`a: b`)); err == nil {
+		t.Error("expected invalid locale error")
+	}
+}
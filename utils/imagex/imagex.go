@@ -0,0 +1,221 @@
+// Package imagex 图片处理：解码时自动按 EXIF 方向摆正、等比缩放与缩略图、格式转换（jpeg/png/gif，webp 仅支持读取）
+// 以及水印；输入输出均为 io.Reader/io.Writer，可与 storage 包直接串联
+// 使用示例：
+//
+//	src, _, err := store.Get(ctx, "avatar/raw/1.jpg")
+//	defer src.Close()
+//	img, _, err := imagex.Decode(src)
+//	thumb := imagex.Thumbnail(img, 200, 200)
+//	rc := imagex.EncodeReader(thumb, imagex.WithFormat(imagex.FormatJPEG))
+//	defer rc.Close()
+//	err = store.Put(ctx, "avatar/200/1.jpg", rc, -1, storage.WithContentType(imagex.FormatJPEG.ContentType()))
+package imagex
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	_ "golang.org/x/image/webp" // 注册 WebP 解码
+)
+
+const (
+	// defaultMaxPixels 默认允许解码的最大像素数，防止解压炸弹耗尽内存
+	defaultMaxPixels = 50_000_000
+	// defaultMaxBytes 默认允许读取的最大输入字节数
+	defaultMaxBytes = 32 << 20
+	// defaultQuality JPEG 默认质量
+	defaultQuality = 85
+)
+
+var (
+	// ErrUnsupportedFormat 不支持的图片格式；写出不支持的格式时返回的 *UnsupportedFormatError 也匹配该错误
+	ErrUnsupportedFormat = errors.New("imagex: unsupported format")
+	// ErrTooLarge 图片像素数或输入字节数超过限制
+	ErrTooLarge = errors.New("imagex: image too large")
+)
+
+// UnsupportedFormatError 编码时指定了不支持写出的格式（如 webp），errors.Is(err, ErrUnsupportedFormat) 为 true
+type UnsupportedFormatError struct {
+	Format Format
+}
+
+func (e *UnsupportedFormatError) Error() string {
+	return fmt.Sprintf("imagex: unsupported output format %q", e.Format)
+}
+
+// Is 使 errors.Is(err, ErrUnsupportedFormat) 成立
+func (e *UnsupportedFormatError) Is(target error) bool {
+	return target == ErrUnsupportedFormat
+}
+
+// Format 图片格式
+type Format string
+
+// 支持的格式
+const (
+	FormatJPEG Format = "jpeg"
+	FormatPNG  Format = "png"
+	FormatGIF  Format = "gif"
+	FormatWebP Format = "webp" // 仅支持读取，写出时返回 *UnsupportedFormatError
+)
+
+// ContentType 格式对应的 MIME 类型
+func (f Format) ContentType() string {
+	return "image/" + string(f)
+}
+
+// decodeOptions 解码选项
+type decodeOptions struct {
+	maxPixels     int
+	maxBytes      int64
+	noOrientation bool
+}
+
+// DecodeOption 解码选项
+type DecodeOption func(*decodeOptions)
+
+// WithMaxPixels 设置允许的最大像素数（宽×高），默认 5000 万
+func WithMaxPixels(n int) DecodeOption {
+	return func(o *decodeOptions) { o.maxPixels = n }
+}
+
+// WithMaxBytes 设置允许读取的最大输入字节数，默认 32 MiB，<= 0 表示不限制
+func WithMaxBytes(n int64) DecodeOption {
+	return func(o *decodeOptions) { o.maxBytes = n }
+}
+
+// WithoutOrientation 不按 EXIF 方向摆正，保留原始像素方向
+func WithoutOrientation() DecodeOption {
+	return func(o *decodeOptions) { o.noOrientation = true }
+}
+
+// Decode 解码图片并返回格式；JPEG 按 EXIF Orientation 自动旋转/翻转为正常方向，GIF 只取第一帧；
+// 输入会整体读入内存（需要读取 EXIF 并解码两遍），超过 WithMaxBytes 的限制时返回 ErrTooLarge；
+// 先读取尺寸校验像素数，超过限制时返回 ErrTooLarge 而不分配像素内存
+func Decode(r io.Reader, opts ...DecodeOption) (image.Image, Format, error) {
+	o := decodeOptions{maxPixels: defaultMaxPixels, maxBytes: defaultMaxBytes}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxBytes > 0 {
+		r = io.LimitReader(r, o.maxBytes+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("imagex: read: %w", err)
+	}
+	if o.maxBytes > 0 && int64(len(data)) > o.maxBytes {
+		return nil, "", fmt.Errorf("%w: more than %d bytes", ErrTooLarge, o.maxBytes)
+	}
+	cfg, name, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return nil, "", ErrUnsupportedFormat
+		}
+		return nil, "", fmt.Errorf("imagex: decode: %w", err)
+	}
+	if o.maxPixels > 0 && cfg.Width*cfg.Height > o.maxPixels {
+		return nil, "", fmt.Errorf("%w: %dx%d", ErrTooLarge, cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("imagex: decode: %w", err)
+	}
+	format := Format(name)
+	if format == FormatJPEG && !o.noOrientation {
+		img = FixOrientation(img, exifOrientation(data))
+	}
+	return img, format, nil
+}
+
+// encodeOptions 编码选项
+type encodeOptions struct {
+	format  Format
+	quality int
+}
+
+// EncodeOption 编码选项
+type EncodeOption func(*encodeOptions)
+
+// WithFormat 设置输出格式，Encode 默认 JPEG，Transform 默认与输入相同（输入为 webp 时输出 JPEG）
+func WithFormat(f Format) EncodeOption {
+	return func(o *encodeOptions) { o.format = f }
+}
+
+// WithQuality 设置 JPEG 质量（1-100），默认 85
+func WithQuality(q int) EncodeOption {
+	return func(o *encodeOptions) { o.quality = q }
+}
+
+// newEncodeOptions 应用编码选项，def 为未指定格式时的默认格式
+func newEncodeOptions(def Format, opts []EncodeOption) encodeOptions {
+	o := encodeOptions{format: def, quality: defaultQuality}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Encode 按选项编码图片写入 w，支持 jpeg、png、gif；其它格式（包括 webp）返回 *UnsupportedFormatError
+func Encode(w io.Writer, img image.Image, opts ...EncodeOption) error {
+	return encode(w, img, newEncodeOptions(FormatJPEG, opts))
+}
+
+// encode 按格式编码
+func encode(w io.Writer, img image.Image, o encodeOptions) error {
+	var err error
+	switch o.format {
+	case FormatJPEG:
+		err = jpeg.Encode(w, img, &jpeg.Options{Quality: o.quality})
+	case FormatPNG:
+		err = (&png.Encoder{CompressionLevel: png.BestSpeed}).Encode(w, img)
+	case FormatGIF:
+		err = gif.Encode(w, img, nil)
+	default:
+		return &UnsupportedFormatError{Format: o.format}
+	}
+	if err != nil {
+		return fmt.Errorf("imagex: encode %s: %w", o.format, err)
+	}
+	return nil
+}
+
+// EncodeReader 返回边编码边读取的 io.ReadCloser，可直接作为 storage.Put 的输入（size 传 -1），
+// 无需先编码到内存；编码错误在 Read 时返回，提前放弃读取时必须 Close 以结束编码协程
+func EncodeReader(img image.Image, opts ...EncodeOption) io.ReadCloser {
+	o := newEncodeOptions(FormatJPEG, opts)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(encode(pw, img, o))
+	}()
+	return pr
+}
+
+// Transform 解码 r，经 fn 处理（如缩放、加水印，nil 时只做格式转换与方向摆正）后编码写入 w，返回输出格式
+//
+//	format, err := imagex.Transform(src, dst, func(img image.Image) image.Image {
+//		return imagex.Fit(img, 1920, 1080)
+//	}, imagex.WithQuality(80))
+func Transform(r io.Reader, w io.Writer, fn func(image.Image) image.Image, opts ...EncodeOption) (Format, error) {
+	img, format, err := Decode(r)
+	if err != nil {
+		return "", err
+	}
+	if fn != nil {
+		img = fn(img)
+	}
+	if format == FormatWebP {
+		format = FormatJPEG
+	}
+	o := newEncodeOptions(format, opts)
+	if err := encode(w, img, o); err != nil {
+		return "", err
+	}
+	return o.format, nil
+}
@@ -0,0 +1,161 @@
+package imagex
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	red  = color.NRGBA{R: 255, A: 255}
+	blue = color.NRGBA{B: 255, A: 255}
+)
+
+// halves 左半红、右半蓝的测试图
+func halves(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, image.Rect(0, 0, w/2, h), image.NewUniform(red), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(w/2, 0, w, h), image.NewUniform(blue), image.Point{}, draw.Src)
+	return img
+}
+
+// withOrientation 在 JPEG 的 SOI 之后插入只含 Orientation 的 EXIF 段
+func withOrientation(t *testing.T, img image.Image, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}))
+	tiff := []byte("II*\x00\x08\x00\x00\x00\x01\x00")
+	entry := make([]byte, 12)
+	binary.LittleEndian.PutUint16(entry[0:], 0x0112)
+	binary.LittleEndian.PutUint16(entry[2:], 3)
+	binary.LittleEndian.PutUint32(entry[4:], 1)
+	binary.LittleEndian.PutUint16(entry[8:], orientation)
+	seg := append(append(append([]byte("Exif\x00\x00"), tiff...), entry...), 0, 0, 0, 0)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(seg)+2))
+	data := buf.Bytes()
+	return append(append(append([]byte{}, data[:2]...), append(app1, seg...)...), data[2:]...)
+}
+
+// isRed、isBlue 容忍 JPEG 压缩误差的颜色判断
+func isRed(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r > 0xc000 && g < 0x4000 && b < 0x4000
+}
+
+func isBlue(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return b > 0xc000 && r < 0x4000 && g < 0x4000
+}
+
+func TestDecodeOrientation(t *testing.T) {
+	data := withOrientation(t, halves(40, 20), 6)
+	assert.Equal(t, 6, exifOrientation(data))
+
+	img, format, err := Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, FormatJPEG, format)
+	// 顺时针旋转 90° 后原图左侧（红）在上方
+	assert.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds())
+	assert.True(t, isRed(img.At(10, 5)))
+	assert.True(t, isBlue(img.At(10, 35)))
+
+	raw, _, err := Decode(bytes.NewReader(data), WithoutOrientation())
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 40, 20), raw.Bounds())
+
+	src := halves(4, 2)
+	for o, want := range map[int]image.Rectangle{1: src.Bounds(), 3: src.Bounds(), 5: image.Rect(0, 0, 2, 4), 8: image.Rect(0, 0, 2, 4)} {
+		assert.Equal(t, want, FixOrientation(src, o).Bounds(), "orientation %d", o)
+	}
+	assert.True(t, isBlue(FixOrientation(src, 2).At(0, 0)))
+	assert.True(t, isRed(FixOrientation(src, 8).At(0, 3)))
+}
+
+func TestDecodeLimits(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, halves(100, 100)))
+	_, _, err := Decode(bytes.NewReader(buf.Bytes()), WithMaxPixels(5000))
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	_, _, err = Decode(bytes.NewReader(buf.Bytes()), WithMaxBytes(int64(buf.Len()-1)))
+	assert.ErrorIs(t, err, ErrTooLarge)
+	_, _, err = Decode(bytes.NewReader(buf.Bytes()), WithMaxBytes(int64(buf.Len())))
+	assert.NoError(t, err)
+
+	_, _, err = Decode(bytes.NewReader([]byte("not an image")))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	// IFD0 偏移超出范围（0xFFFFFFFF）时忽略 EXIF
+	assert.Equal(t, 1, tiffOrientation([]byte("II*\x00\xff\xff\xff\xff")))
+}
+
+func TestResize(t *testing.T) {
+	src := halves(400, 200)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), Resize(src, 100, 0).Bounds())
+	assert.Equal(t, image.Rect(0, 0, 30, 60), Resize(src, 30, 60).Bounds())
+	assert.Equal(t, image.Rect(0, 0, 200, 100), Fit(src, 200, 200).Bounds())
+	assert.Equal(t, image.Rect(0, 0, 100, 50), Fit(src, 0, 50).Bounds())
+	assert.Same(t, src, Fit(src, 1000, 1000), "no upscaling")
+
+	thumb := Thumbnail(src, 50, 50)
+	assert.Equal(t, image.Rect(0, 0, 50, 50), thumb.Bounds())
+	// 居中裁剪后左右仍各为一种颜色
+	assert.True(t, isRed(thumb.At(5, 25)))
+	assert.True(t, isBlue(thumb.At(45, 25)))
+}
+
+func TestWatermark(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 100, 100))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	mark := image.NewNRGBA(image.Rect(0, 0, 20, 10))
+	draw.Draw(mark, mark.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+	out := Watermark(src, mark, WithOpacity(1))
+	assert.Equal(t, color.NRGBA{A: 255}, out.At(85, 85))
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, out.At(50, 50))
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, src.At(85, 85), "source unchanged")
+
+	out = Watermark(src, mark, WithPosition(TopLeft), WithMargin(0), WithOpacity(0.5), WithWatermarkRatio(0.4))
+	r, _, _, _ := out.At(30, 5).RGBA()
+	assert.InDelta(t, 0x8000, r, 0x1000, "half opacity blends with white")
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, out.At(30, 25), "scaled mark is 40x20")
+}
+
+func TestTransformAndEncodeReader(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, halves(400, 200)))
+
+	var out bytes.Buffer
+	format, err := Transform(&buf, &out, func(img image.Image) image.Image { return Fit(img, 100, 100) }, WithFormat(FormatJPEG), WithQuality(70))
+	require.NoError(t, err)
+	assert.Equal(t, FormatJPEG, format)
+	assert.Equal(t, "image/jpeg", format.ContentType())
+	cfg, name, err := image.DecodeConfig(&out)
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", name)
+	assert.Equal(t, 100, cfg.Width)
+
+	rc := EncodeReader(halves(10, 10), WithFormat(FormatPNG))
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	_, format, err = Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, FormatPNG, format)
+
+	_, err = io.ReadAll(EncodeReader(halves(10, 10), WithFormat(FormatWebP)))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	var ue *UnsupportedFormatError
+	require.ErrorAs(t, Encode(io.Discard, halves(10, 10), WithFormat(FormatWebP)), &ue)
+	assert.Equal(t, FormatWebP, ue.Format)
+}
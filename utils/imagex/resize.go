@@ -0,0 +1,66 @@
+package imagex
+
+import (
+	"image"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Resize 缩放到 width×height；其中一个为 0 时按原图比例计算，都为 0 时返回原图
+func Resize(img image.Image, width, height int) image.Image {
+	b := img.Bounds()
+	if b.Empty() || (width <= 0 && height <= 0) {
+		return img
+	}
+	if width <= 0 {
+		width = max(1, b.Dx()*height/b.Dy())
+	}
+	if height <= 0 {
+		height = max(1, b.Dy()*width/b.Dx())
+	}
+	return scale(img, b, width, height)
+}
+
+// Fit 等比缩小到 maxWidth×maxHeight 以内（0 表示该方向不限制），不会放大，已在范围内时返回原图
+func Fit(img image.Image, maxWidth, maxHeight int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 || ((maxWidth <= 0 || w <= maxWidth) && (maxHeight <= 0 || h <= maxHeight)) {
+		return img
+	}
+	ratio := 1.0
+	if maxWidth > 0 {
+		ratio = float64(maxWidth) / float64(w)
+	}
+	if maxHeight > 0 {
+		ratio = min(ratio, float64(maxHeight)/float64(h))
+	}
+	return scale(img, b, max(1, int(float64(w)*ratio+0.5)), max(1, int(float64(h)*ratio+0.5)))
+}
+
+// Thumbnail 生成恰好 width×height 的缩略图：等比缩放至覆盖目标尺寸后居中裁剪，适合头像、列表封面
+func Thumbnail(img image.Image, width, height int) image.Image {
+	b := img.Bounds()
+	if b.Empty() || width <= 0 || height <= 0 {
+		return img
+	}
+	// 在原图中取与目标宽高比一致的居中区域，再整体缩放，避免先放大再裁剪
+	crop := b
+	if b.Dx()*height > b.Dy()*width {
+		w := b.Dy() * width / height
+		crop.Min.X += (b.Dx() - w) / 2
+		crop.Max.X = crop.Min.X + w
+	} else {
+		h := b.Dx() * height / width
+		crop.Min.Y += (b.Dy() - h) / 2
+		crop.Max.Y = crop.Min.Y + h
+	}
+	return scale(img, crop, width, height)
+}
+
+// scale 将 img 的 src 区域缩放到 width×height
+func scale(img image.Image, src image.Rectangle, width, height int) image.Image {
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, src, xdraw.Src, nil)
+	return dst
+}
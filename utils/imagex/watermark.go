@@ -0,0 +1,86 @@
+package imagex
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Position 水印位置
+type Position int
+
+const (
+	BottomRight Position = iota // 右下角，默认
+	BottomLeft
+	TopRight
+	TopLeft
+	Center
+)
+
+// watermarkOptions 水印选项
+type watermarkOptions struct {
+	position Position
+	margin   int
+	opacity  float64
+	ratio    float64
+}
+
+// WatermarkOption 水印选项
+type WatermarkOption func(*watermarkOptions)
+
+// WithPosition 设置水印位置，默认右下角
+func WithPosition(p Position) WatermarkOption {
+	return func(o *watermarkOptions) { o.position = p }
+}
+
+// WithMargin 设置水印与边缘的距离（像素），默认 10
+func WithMargin(px int) WatermarkOption {
+	return func(o *watermarkOptions) { o.margin = px }
+}
+
+// WithOpacity 设置水印不透明度（0-1），默认 0.6
+func WithOpacity(a float64) WatermarkOption {
+	return func(o *watermarkOptions) { o.opacity = a }
+}
+
+// WithWatermarkRatio 按原图宽度的比例缩放水印（如 0.2），默认使用水印原尺寸
+func WithWatermarkRatio(r float64) WatermarkOption {
+	return func(o *watermarkOptions) { o.ratio = r }
+}
+
+// Watermark 将 mark 按位置与不透明度叠加到 img 上，返回新图片，原图不变；
+// 文字水印可先渲染为带透明背景的 PNG 再传入
+func Watermark(img, mark image.Image, opts ...WatermarkOption) image.Image {
+	o := watermarkOptions{position: BottomRight, margin: 10, opacity: 0.6}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Bounds(), img, b.Min, draw.Src)
+
+	if o.ratio > 0 {
+		mark = Resize(mark, max(1, int(float64(b.Dx())*o.ratio)), 0)
+	}
+	mb := mark.Bounds()
+	mw, mh := mb.Dx(), mb.Dy()
+	var at image.Point
+	switch o.position {
+	case TopLeft:
+		at = image.Pt(o.margin, o.margin)
+	case TopRight:
+		at = image.Pt(b.Dx()-mw-o.margin, o.margin)
+	case BottomLeft:
+		at = image.Pt(o.margin, b.Dy()-mh-o.margin)
+	case Center:
+		at = image.Pt((b.Dx()-mw)/2, (b.Dy()-mh)/2)
+	default:
+		at = image.Pt(b.Dx()-mw-o.margin, b.Dy()-mh-o.margin)
+	}
+	alpha := uint8(min(max(o.opacity, 0), 1) * 255)
+	xdraw.DrawMask(out, image.Rectangle{Min: at, Max: at.Add(image.Pt(mw, mh))}, mark, mb.Min,
+		image.NewUniform(color.Alpha{A: alpha}), image.Point{}, xdraw.Over)
+	return out
+}
@@ -0,0 +1,150 @@
+// Package ipregion 离线 IP 归属地查询，兼容 ip2region xdb 格式（IPv4），数据文件可通过 go:embed 打包进二进制
+//
+// 使用示例：
+//
+//	//go:embed ip2region.xdb
+//	var xdb []byte
+//
+//	searcher, err := ipregion.New(xdb)
+//	region, err := searcher.Search("1.2.3.4")
+package ipregion
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// xdb 文件布局：256 字节头部 + 256*256 个向量索引（每个 8 字节）+ 数据区与 14 字节的段索引
+const (
+	headerLength    = 256
+	vectorIndexCols = 256
+	vectorIndexSize = 8
+	segmentIndexLen = 14
+	vectorIndexLen  = vectorIndexCols * vectorIndexCols * vectorIndexSize
+)
+
+var (
+	// ErrInvalidIP IP 格式错误或不是 IPv4
+	ErrInvalidIP = errors.New("ipregion: invalid ipv4 address")
+	// ErrInvalidDB 数据文件损坏或格式不匹配
+	ErrInvalidDB = errors.New("ipregion: invalid xdb data")
+)
+
+// Region 归属地信息，缺失的字段为空串
+type Region struct {
+	Country  string
+	Area     string // 旧版 5 字段格式中的“区域”，新版数据为空
+	Province string
+	City     string
+	ISP      string
+	Raw      string // 原始记录，如 "中国|0|广东省|深圳市|电信"
+}
+
+// String 拼接非空字段
+func (r Region) String() string {
+	parts := make([]string, 0, 5)
+	for _, s := range []string{r.Country, r.Area, r.Province, r.City, r.ISP} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Searcher 基于内存中完整 xdb 数据的查询器，并发安全
+type Searcher struct {
+	data []byte
+}
+
+// New 使用内存中的 xdb 数据创建查询器（数据不会被复制，调用方不应再修改）
+func New(data []byte) (*Searcher, error) {
+	if len(data) < headerLength+vectorIndexLen {
+		return nil, ErrInvalidDB
+	}
+	return &Searcher{data: data}, nil
+}
+
+// Open 读取 xdb 文件到内存并创建查询器
+func Open(path string) (*Searcher, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(data)
+}
+
+// Search 查询 IPv4 地址的归属地
+func (s *Searcher) Search(ip string) (Region, error) {
+	p := net.ParseIP(strings.TrimSpace(ip)).To4()
+	if p == nil {
+		return Region{}, ErrInvalidIP
+	}
+	raw, err := s.SearchUint32(binary.BigEndian.Uint32(p))
+	if err != nil {
+		return Region{}, err
+	}
+	return ParseRegion(raw), nil
+}
+
+// SearchUint32 按整数形式的 IP 查询，返回原始记录
+func (s *Searcher) SearchUint32(ip uint32) (string, error) {
+	il0, il1 := ip>>24&0xFF, ip>>16&0xFF
+	idx := headerLength + int(il0*vectorIndexCols*vectorIndexSize+il1*vectorIndexSize)
+	sPtr := binary.LittleEndian.Uint32(s.data[idx:])
+	ePtr := binary.LittleEndian.Uint32(s.data[idx+4:])
+	if sPtr == 0 && ePtr == 0 {
+		return "", nil
+	}
+	if int(ePtr)+segmentIndexLen > len(s.data) || ePtr < sPtr {
+		return "", ErrInvalidDB
+	}
+
+	l, h := 0, int(ePtr-sPtr)/segmentIndexLen
+	for l <= h {
+		m := (l + h) >> 1
+		p := int(sPtr) + m*segmentIndexLen
+		seg := s.data[p : p+segmentIndexLen]
+		start := binary.LittleEndian.Uint32(seg)
+		end := binary.LittleEndian.Uint32(seg[4:])
+		switch {
+		case ip < start:
+			h = m - 1
+		case ip > end:
+			l = m + 1
+		default:
+			dataLen := int(binary.LittleEndian.Uint16(seg[8:]))
+			dataPtr := int(binary.LittleEndian.Uint32(seg[10:]))
+			if dataPtr+dataLen > len(s.data) {
+				return "", fmt.Errorf("%w: data pointer out of range", ErrInvalidDB)
+			}
+			return string(s.data[dataPtr : dataPtr+dataLen]), nil
+		}
+	}
+	return "", nil
+}
+
+// ParseRegion 解析原始记录，兼容 5 字段（国家|区域|省份|城市|ISP）与 4 字段（国家|省份|城市|ISP）格式，"0" 视为空
+func ParseRegion(raw string) Region {
+	fields := strings.Split(raw, "|")
+	for i, f := range fields {
+		if f == "0" {
+			fields[i] = ""
+		}
+	}
+	r := Region{Raw: raw}
+	switch len(fields) {
+	case 5:
+		r.Country, r.Area, r.Province, r.City, r.ISP = fields[0], fields[1], fields[2], fields[3], fields[4]
+	case 4:
+		r.Country, r.Province, r.City, r.ISP = fields[0], fields[1], fields[2], fields[3]
+	default:
+		if len(fields) > 0 {
+			r.Country = fields[0]
+		}
+	}
+	return r
+}
@@ -0,0 +1,92 @@
+package ipregion
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type segment struct {
+	start, end string
+	region     string
+}
+
+// buildXDB 按 xdb 格式生成测试数据，跨越 /16 的段会按向量索引拆分
+func buildXDB(segs []segment) []byte {
+	data := make([]byte, headerLength+vectorIndexLen)
+	type block struct {
+		start, end uint32
+		ptr        uint32
+		n          uint16
+	}
+	var blocks []block
+	for _, s := range segs {
+		ptr := uint32(len(data))
+		data = append(data, s.region...)
+		blocks = append(blocks, block{ip(s.start), ip(s.end), ptr, uint16(len(s.region))})
+	}
+	// 写入段索引并记录向量索引
+	for _, b := range blocks {
+		for cur := b.start; ; {
+			next := cur | 0xFFFF
+			end := b.end
+			if next < end {
+				end = next
+			}
+			p := uint32(len(data))
+			seg := make([]byte, segmentIndexLen)
+			binary.LittleEndian.PutUint32(seg, cur)
+			binary.LittleEndian.PutUint32(seg[4:], end)
+			binary.LittleEndian.PutUint16(seg[8:], b.n)
+			binary.LittleEndian.PutUint32(seg[10:], b.ptr)
+			data = append(data, seg...)
+
+			idx := headerLength + int((cur>>24&0xFF)*vectorIndexCols*vectorIndexSize+(cur>>16&0xFF)*vectorIndexSize)
+			if binary.LittleEndian.Uint32(data[idx:]) == 0 {
+				binary.LittleEndian.PutUint32(data[idx:], p)
+			}
+			binary.LittleEndian.PutUint32(data[idx+4:], p)
+			if end == b.end {
+				break
+			}
+			cur = end + 1
+		}
+	}
+	return data
+}
+
+func ip(s string) uint32 {
+	return binary.BigEndian.Uint32(net.ParseIP(s).To4())
+}
+
+func TestSearch(t *testing.T) {
+	s, err := New(buildXDB([]segment{
+		{"1.0.0.0", "1.0.0.255", "澳大利亚|0|0|0|0"},
+		{"1.0.1.0", "1.0.3.255", "中国|0|福建省|福州市|电信"},
+		{"36.0.0.0", "36.1.255.255", "中国|北京|北京市|移动"},
+	}))
+	assert.NoError(t, err)
+
+	r, err := s.Search("1.0.2.8")
+	assert.NoError(t, err)
+	assert.Equal(t, Region{Country: "中国", Province: "福建省", City: "福州市", ISP: "电信", Raw: "中国|0|福建省|福州市|电信"}, r)
+	assert.Equal(t, "中国 福建省 福州市 电信", r.String())
+
+	r, _ = s.Search("1.0.0.1")
+	assert.Equal(t, "澳大利亚", r.String())
+
+	r, _ = s.Search("36.1.0.9")
+	assert.Equal(t, "北京", r.Province)
+	assert.Equal(t, "移动", r.ISP)
+
+	r, err = s.Search("8.8.8.8")
+	assert.NoError(t, err)
+	assert.Empty(t, r.Raw)
+
+	_, err = s.Search("::1")
+	assert.ErrorIs(t, err, ErrInvalidIP)
+	_, err = New([]byte("short"))
+	assert.ErrorIs(t, err, ErrInvalidDB)
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -12,36 +13,58 @@ type JWTConfig struct {
 	Secret     []byte        // 签名秘钥
 	Issuer     string        // 签发者
 	ExpireTime time.Duration // 默认过期时间（如 2 * time.Hour）
+
+	Introspection *IntrospectionConfig // 可选，配置后非 JWT 格式的不透明令牌通过 RFC 7662 内省端点校验
+
+	AuditHook func(TokenEvent) // 可选，每次签发/校验（无论成功失败）后同步调用，可接入审计日志或 SIEM，回调应尽快返回
+}
+
+// TokenService 令牌签发与校验的通用接口，JWTService 与 PasetoService 均实现该接口
+type TokenService interface {
+	GenerateToken(payload jwt.Claims) (string, error)
+	ParseToken(token string, claims jwt.Claims) error
 }
 
 // JWTService 封装 jwt 操作
 type JWTService struct {
-	cfg JWTConfig
+	cfg          JWTConfig
+	introspector *Introspector
 }
 
 // NewJwt 创建实例
 func NewJWT(cfg JWTConfig) *JWTService {
-	return &JWTService{cfg: cfg}
+	j := &JWTService{cfg: cfg}
+	if cfg.Introspection != nil {
+		j.introspector = NewIntrospector(*cfg.Introspection)
+	}
+	return j
 }
 
 // GenerateToken 生成 token
 func (j *JWTService) GenerateToken(payload jwt.Claims) (string, error) {
-	switch claims := payload.(type) {
-	case *jwt.RegisteredClaims:
-		// 如果业务方直接传 RegisteredClaims，就认为完全由他控制，不补默认值
-	case interface{ GetRegistered() *jwt.RegisteredClaims }:
-		// 嵌套结构体（例如 MyClaims），则只补未设置的字段
-		j.fillMissingDefaults(claims.GetRegistered())
-	default:
-		// 无法识别类型，不处理默认字段
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
-	return token.SignedString(j.cfg.Secret)
+	fillDefaults(j.cfg, payload)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, payload).SignedString(j.cfg.Secret)
+	auditToken(j.cfg, TokenOpGenerate, payload, err)
+	return token, err
 }
 
 // ParseToken 验证 token
 func (j *JWTService) ParseToken(tokenString string, claims jwt.Claims) error {
+	return j.ParseTokenContext(context.Background(), tokenString, claims)
+}
+
+// ParseTokenContext 验证 token，配置了内省时不透明令牌会携带 ctx 调用内省端点
+func (j *JWTService) ParseTokenContext(ctx context.Context, tokenString string, claims jwt.Claims) error {
+	err := j.parse(ctx, tokenString, claims)
+	auditToken(j.cfg, TokenOpParse, claims, err)
+	return err
+}
+
+// parse 校验 token，不触发审计回调
+func (j *JWTService) parse(ctx context.Context, tokenString string, claims jwt.Claims) error {
+	if j.introspector != nil && !isJWT(tokenString) {
+		return j.introspector.Introspect(ctx, tokenString, claims)
+	}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
 		return j.cfg.Secret, nil
 	})
@@ -54,16 +77,29 @@ func (j *JWTService) ParseToken(tokenString string, claims jwt.Claims) error {
 	return nil
 }
 
+// fillDefaults 按 claims 类型决定是否补默认字段，JWT 与 PASETO 共用
+func fillDefaults(cfg JWTConfig, payload jwt.Claims) {
+	switch claims := payload.(type) {
+	case *jwt.RegisteredClaims:
+		// 如果业务方直接传 RegisteredClaims，就认为完全由他控制，不补默认值
+	case interface{ GetRegistered() *jwt.RegisteredClaims }:
+		// 嵌套结构体（例如 MyClaims），则只补未设置的字段
+		fillMissingDefaults(cfg, claims.GetRegistered())
+	default:
+		// 无法识别类型，不处理默认字段
+	}
+}
+
 // 只补未设置的字段（区别于“全覆盖”）
-func (j *JWTService) fillMissingDefaults(c *jwt.RegisteredClaims) {
+func fillMissingDefaults(cfg JWTConfig, c *jwt.RegisteredClaims) {
 	now := time.Now()
-	if c.Issuer == "" && j.cfg.Issuer != "" {
-		c.Issuer = j.cfg.Issuer
+	if c.Issuer == "" && cfg.Issuer != "" {
+		c.Issuer = cfg.Issuer
 	}
 	if c.IssuedAt == nil {
 		c.IssuedAt = jwt.NewNumericDate(now)
 	}
-	if c.ExpiresAt == nil && j.cfg.ExpireTime > 0 {
-		c.ExpiresAt = jwt.NewNumericDate(now.Add(j.cfg.ExpireTime))
+	if c.ExpiresAt == nil && cfg.ExpireTime > 0 {
+		c.ExpiresAt = jwt.NewNumericDate(now.Add(cfg.ExpireTime))
 	}
 }
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenAuditHook(t *testing.T) {
+	var events []TokenEvent
+	j := NewJWT(JWTConfig{Secret: []byte("test_secret"), AuditHook: func(ev TokenEvent) { events = append(events, ev) }})
+
+	token, err := j.GenerateToken(&MyClaims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1", ID: "jti-1"}})
+	assert.NoError(t, err)
+	assert.NoError(t, j.ParseToken(token, &MyClaims{}))
+
+	expired, _ := j.GenerateToken(&jwt.RegisteredClaims{Subject: "user-2", ID: "jti-2", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))})
+	assert.Error(t, j.ParseToken(expired, &jwt.RegisteredClaims{}))
+	assert.Error(t, j.ParseToken("not-a-token", &jwt.RegisteredClaims{}))
+
+	assert.Len(t, events, 5)
+	assert.Equal(t, TokenEvent{Op: TokenOpGenerate, Subject: "user-1", ID: "jti-1", Success: true}, stripTime(events[0]))
+	assert.Equal(t, TokenEvent{Op: TokenOpParse, Subject: "user-1", ID: "jti-1", Success: true}, stripTime(events[1]))
+	assert.Equal(t, TokenOpParse, events[3].Op)
+	assert.False(t, events[3].Success)
+	assert.Equal(t, "expired", events[3].Reason)
+	assert.Equal(t, "user-2", events[3].Subject)
+	assert.Equal(t, "malformed", events[4].Reason)
+}
+
+func TestTokenAuditHook_Middleware(t *testing.T) {
+	var events []TokenEvent
+	j := NewJWT(JWTConfig{Secret: []byte("test_secret"), AuditHook: func(ev TokenEvent) { events = append(events, ev) }})
+	token, _ := j.GenerateBoundToken(&boundClaims{UserID: 1}, WithDeviceFingerprint("device-a"))
+	events = nil
+
+	h := j.Middleware(func() jwt.Claims { return &boundClaims{} }, WithTokenBinding(func(r *http.Request) []BindOption {
+		return []BindOption{WithDeviceFingerprint(r.Header.Get("X-Device-Id"))}
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, device := range []string{"device-a", "device-b", ""} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if device != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("X-Device-Id", device)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// 每个请求按最终结果审计一次，绑定失败与缺少 token 的请求也会上报
+	if assert.Len(t, events, 3) {
+		assert.True(t, events[0].Success)
+		assert.Equal(t, "binding_mismatch", events[1].Reason)
+		assert.Equal(t, "missing", events[2].Reason)
+	}
+}
+
+func stripTime(ev TokenEvent) TokenEvent {
+	ev.Time = time.Time{}
+	return ev
+}
@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrBindingMismatch token 绑定的设备或 IP 段与当前请求不一致
+	ErrBindingMismatch = errors.New("token binding mismatch")
+	// ErrBindingUnsupported claims 未实现 BindingClaims，无法写入绑定信息
+	ErrBindingUnsupported = errors.New("claims do not support token binding")
+)
+
+// 按客户端 IP 绑定时默认放行的网段长度，避免移动网络下 IP 小范围变化导致 token 失效
+const (
+	defaultIPv4BindBits = 24
+	defaultIPv6BindBits = 64
+)
+
+// TokenBinding token 绑定信息，签发时写入 claims，解析时与当前请求比对
+type TokenBinding struct {
+	Device string `json:"dfp,omitempty"`  // 设备指纹的 SHA-256 摘要（base64url），不保存指纹原文
+	IPNet  string `json:"cidr,omitempty"` // 允许使用 token 的客户端 IP 段
+}
+
+// BindingClaims 自定义 Claims 实现该接口后支持设备/IP 绑定，通常内嵌 TokenBinding：
+//
+//	type MyClaims struct {
+//		UserID int64 `json:"user_id"`
+//		utils.TokenBinding
+//		jwt.RegisteredClaims
+//	}
+//
+//	func (c *MyClaims) GetBinding() *utils.TokenBinding { return &c.TokenBinding }
+type BindingClaims interface {
+	GetBinding() *TokenBinding
+}
+
+// BindOption 绑定参数，签发时决定写入哪些绑定，解析时提供当前请求的设备与 IP
+type BindOption func(*bindParams)
+
+type bindParams struct {
+	device string
+	ip     net.IP
+	ipNet  *net.IPNet
+	err    error
+}
+
+// WithDeviceFingerprint 设备指纹（如客户端生成并持久化的设备 ID、证书指纹）
+func WithDeviceFingerprint(fp string) BindOption {
+	return func(p *bindParams) { p.device = fp }
+}
+
+// WithClientIP 客户端 IP；签发时绑定到其所在的 /24（IPv4）或 /64（IPv6）网段，解析时校验是否在绑定网段内，
+// 为空时忽略
+func WithClientIP(ip string) BindOption {
+	return func(p *bindParams) {
+		if ip == "" {
+			return
+		}
+		p.ip = net.ParseIP(ip)
+		if p.ip == nil {
+			p.err = fmt.Errorf("invalid client ip %q", ip)
+		}
+	}
+}
+
+// WithIPRange 签发时显式指定允许的 IP 段（CIDR），优先于 WithClientIP 的默认网段
+func WithIPRange(cidr string) BindOption {
+	return func(p *bindParams) {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			p.err = err
+			return
+		}
+		p.ipNet = n
+	}
+}
+
+// newBindParams 应用绑定参数
+func newBindParams(opts []BindOption) (*bindParams, error) {
+	p := &bindParams{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, p.err
+}
+
+// ApplyBinding 按参数把设备指纹摘要与 IP 段写入 claims，需在签发前调用；
+// 也可直接使用 GenerateBoundToken，PASETO 等其它 TokenService 同样适用
+func ApplyBinding(claims jwt.Claims, opts ...BindOption) error {
+	bc, ok := claims.(BindingClaims)
+	if !ok {
+		return ErrBindingUnsupported
+	}
+	p, err := newBindParams(opts)
+	if err != nil {
+		return err
+	}
+	b := bc.GetBinding()
+	if p.device != "" {
+		b.Device = deviceDigest(p.device)
+	}
+	switch {
+	case p.ipNet != nil:
+		b.IPNet = p.ipNet.String()
+	case p.ip != nil:
+		b.IPNet = defaultIPNet(p.ip).String()
+	}
+	return nil
+}
+
+// VerifyBinding 校验 claims 中的绑定信息与当前请求是否一致：
+// token 绑定了设备或 IP 段时，必须提供对应的参数且匹配；未绑定的项不做校验
+func VerifyBinding(claims jwt.Claims, opts ...BindOption) error {
+	bc, ok := claims.(BindingClaims)
+	if !ok {
+		return nil
+	}
+	b := bc.GetBinding()
+	if b == nil || (b.Device == "" && b.IPNet == "") {
+		return nil
+	}
+	p, err := newBindParams(opts)
+	if err != nil {
+		return err
+	}
+	if b.Device != "" {
+		if p.device == "" || subtle.ConstantTimeCompare([]byte(b.Device), []byte(deviceDigest(p.device))) != 1 {
+			return fmt.Errorf("%w: device", ErrBindingMismatch)
+		}
+	}
+	if b.IPNet != "" {
+		_, n, err := net.ParseCIDR(b.IPNet)
+		if err != nil || p.ip == nil || !n.Contains(p.ip) {
+			return fmt.Errorf("%w: client ip", ErrBindingMismatch)
+		}
+	}
+	return nil
+}
+
+// GenerateBoundToken 签发绑定设备/IP 段的 token，payload 需实现 BindingClaims
+func (j *JWTService) GenerateBoundToken(payload jwt.Claims, opts ...BindOption) (string, error) {
+	if err := ApplyBinding(payload, opts...); err != nil {
+		return "", err
+	}
+	return j.GenerateToken(payload)
+}
+
+// ParseBoundToken 解析 token 并校验绑定信息，opts 提供当前请求的设备指纹与客户端 IP
+func (j *JWTService) ParseBoundToken(tokenString string, claims jwt.Claims, opts ...BindOption) error {
+	err := j.parse(context.Background(), tokenString, claims)
+	if err == nil {
+		err = VerifyBinding(claims, opts...)
+	}
+	auditToken(j.cfg, TokenOpParse, claims, err)
+	return err
+}
+
+// WithTokenBinding 中间件解析 token 后校验绑定信息，fn 从请求中取设备指纹与客户端 IP，例如：
+//
+//	utils.WithTokenBinding(func(r *http.Request) []utils.BindOption {
+//		ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+//		return []utils.BindOption{utils.WithDeviceFingerprint(r.Header.Get("X-Device-Id")), utils.WithClientIP(ip)}
+//	})
+func WithTokenBinding(fn func(r *http.Request) []BindOption) MiddlewareOption {
+	return func(c *middlewareConfig) { c.binding = fn }
+}
+
+// deviceDigest 设备指纹摘要
+func deviceDigest(fp string) string {
+	sum := sha256.Sum256([]byte(fp))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// defaultIPNet 客户端 IP 所在的默认绑定网段
+func defaultIPNet(ip net.IP) *net.IPNet {
+	if v4 := ip.To4(); v4 != nil {
+		mask := net.CIDRMask(defaultIPv4BindBits, 32)
+		return &net.IPNet{IP: v4.Mask(mask), Mask: mask}
+	}
+	mask := net.CIDRMask(defaultIPv6BindBits, 128)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
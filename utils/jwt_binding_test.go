@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type boundClaims struct {
+	UserID int64 `json:"user_id"`
+	TokenBinding
+	jwt.RegisteredClaims
+}
+
+func (c *boundClaims) GetBinding() *TokenBinding { return &c.TokenBinding }
+
+func TestTokenBinding(t *testing.T) {
+	j := NewJWT(JWTConfig{Secret: []byte("test_secret")})
+
+	token, err := j.GenerateBoundToken(&boundClaims{UserID: 1}, WithDeviceFingerprint("device-a"), WithClientIP("10.1.2.3"))
+	assert.NoError(t, err)
+
+	var c boundClaims
+	assert.NoError(t, j.ParseBoundToken(token, &c, WithDeviceFingerprint("device-a"), WithClientIP("10.1.2.200")))
+	assert.Equal(t, "10.1.2.0/24", c.IPNet)
+	assert.NotEqual(t, "device-a", c.Device)
+
+	err = j.ParseBoundToken(token, &boundClaims{}, WithDeviceFingerprint("device-b"), WithClientIP("10.1.2.3"))
+	assert.True(t, errors.Is(err, ErrBindingMismatch))
+	err = j.ParseBoundToken(token, &boundClaims{}, WithDeviceFingerprint("device-a"), WithClientIP("10.1.3.3"))
+	assert.True(t, errors.Is(err, ErrBindingMismatch))
+	err = j.ParseBoundToken(token, &boundClaims{}, WithClientIP("10.1.2.3"))
+	assert.True(t, errors.Is(err, ErrBindingMismatch))
+
+	// 未绑定的 token 不做校验
+	plain, err := j.GenerateToken(&boundClaims{UserID: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, j.ParseBoundToken(plain, &boundClaims{}, WithClientIP("192.168.0.1")))
+
+	// 显式 IP 段
+	ranged, err := j.GenerateBoundToken(&boundClaims{}, WithIPRange("2001:db8::/32"))
+	assert.NoError(t, err)
+	assert.NoError(t, j.ParseBoundToken(ranged, &boundClaims{}, WithClientIP("2001:db8:1::1")))
+
+	_, err = j.GenerateBoundToken(&jwt.RegisteredClaims{}, WithDeviceFingerprint("x"))
+	assert.ErrorIs(t, err, ErrBindingUnsupported)
+}
+
+func TestMiddlewareTokenBinding(t *testing.T) {
+	j := NewJWT(JWTConfig{Secret: []byte("test_secret")})
+	token, _ := j.GenerateBoundToken(&boundClaims{UserID: 1}, WithDeviceFingerprint("device-a"))
+
+	h := j.Middleware(func() jwt.Claims { return &boundClaims{} }, WithTokenBinding(func(r *http.Request) []BindOption {
+		return []BindOption{WithDeviceFingerprint(r.Header.Get("X-Device-Id"))}
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for device, code := range map[string]int{"device-a": http.StatusOK, "device-b": http.StatusUnauthorized} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-Device-Id", device)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, code, rec.Code, device)
+	}
+}
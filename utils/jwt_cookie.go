@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrCSRFTokenInvalid 请求未携带 CSRF token 或与 cookie 中的值不一致
+var ErrCSRFTokenInvalid = errors.New("csrf token invalid")
+
+// 默认的 cookie 与请求头名称
+const (
+	DefaultTokenCookie = "access_token"
+	DefaultCSRFCookie  = "__Host-csrf_token" // __Host- 前缀：浏览器只接受当前 host 经 HTTPS 写入、Path=/ 且不带 Domain 的该 cookie
+	DefaultCSRFHeader  = "X-CSRF-Token"
+)
+
+// plainCSRFCookie 无法使用 __Host- 前缀（设置了 Domain、Insecure 或非 / 的 Path）时的默认 CSRF cookie 名
+const plainCSRFCookie = "csrf_token"
+
+// CookieConfig token cookie 的属性，零值即为安全的默认配置：Secure、HttpOnly、SameSite=Lax、Path=/
+type CookieConfig struct {
+	Name     string        // 默认 DefaultTokenCookie
+	Domain   string        // 为空时只对当前域名有效
+	Path     string        // 默认 "/"
+	MaxAge   time.Duration // 0 表示会话 cookie，通常与 token 有效期一致
+	SameSite http.SameSite // 默认 http.SameSiteLaxMode
+	Insecure bool          // 为 true 时去掉 Secure 属性，仅用于本地 HTTP 调试
+}
+
+// withDefaults 填充默认值
+func (c CookieConfig) withDefaults(name string) CookieConfig {
+	if c.Name == "" {
+		c.Name = name
+	}
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	if c.SameSite == 0 {
+		c.SameSite = http.SameSiteLaxMode
+	}
+	return c
+}
+
+// cookie 按配置生成 cookie
+func (c CookieConfig) cookie(value string, httpOnly bool) *http.Cookie {
+	ck := &http.Cookie{
+		Name:     c.Name,
+		Value:    value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		Secure:   !c.Insecure,
+		HttpOnly: httpOnly,
+		SameSite: c.SameSite,
+	}
+	if c.MaxAge > 0 {
+		ck.MaxAge = int(c.MaxAge / time.Second)
+		ck.Expires = time.Now().Add(c.MaxAge)
+	}
+	return ck
+}
+
+// SetTokenCookie 将 token 写入 HttpOnly cookie，浏览器端脚本无法读取，降低 XSS 窃取 token 的风险
+// 使用示例：
+//
+//	token, _ := j.GenerateToken(claims)
+//	utils.SetTokenCookie(w, utils.CookieConfig{MaxAge: 2 * time.Hour}, token)
+func SetTokenCookie(w http.ResponseWriter, cfg CookieConfig, token string) {
+	http.SetCookie(w, cfg.withDefaults(DefaultTokenCookie).cookie(token, true))
+}
+
+// ClearTokenCookie 删除 token cookie（退出登录），cfg 的 Name、Domain、Path 需与写入时一致
+func ClearTokenCookie(w http.ResponseWriter, cfg CookieConfig) {
+	ck := cfg.withDefaults(DefaultTokenCookie).cookie("", true)
+	ck.MaxAge = -1
+	ck.Expires = time.Unix(0, 0)
+	http.SetCookie(w, ck)
+}
+
+// CookieToken 返回从指定 cookie 读取 token 的提取函数，name 为空时使用 DefaultTokenCookie，可配合 WithTokenExtractor：
+//
+//	auth := j.Middleware(newClaims, utils.WithTokenExtractor(utils.CookieToken("")))
+//	mux.Handle("/api/", auth(utils.CSRFMiddleware(utils.CSRFConfig{})(api)))
+func CookieToken(name string) func(r *http.Request) string {
+	if name == "" {
+		name = DefaultTokenCookie
+	}
+	return func(r *http.Request) string {
+		if ck, err := r.Cookie(name); err == nil {
+			return ck.Value
+		}
+		return ""
+	}
+}
+
+// CSRFConfig 双重提交（double-submit）CSRF 防护配置：token 同时存放在脚本可读的 cookie 中，
+// 前端在写请求中通过请求头（或表单字段）回传，跨站页面无法读取 cookie，因此无法伪造一致的值。
+// cookie 默认使用 __Host- 前缀，兄弟子域名无法通过 Domain 属性植入 cookie 来同时伪造 cookie 与回传值；
+// 设置了 Domain、Insecure 或非 / 的 Path 时浏览器不接受该前缀，默认名退回 csrf_token，失去这层保护
+type CSRFConfig struct {
+	Cookie    CookieConfig // CSRF cookie 的属性，Name 默认 DefaultCSRFCookie（见上）；该 cookie 不设置 HttpOnly
+	Header    string       // 回传 token 的请求头，默认 DefaultCSRFHeader
+	FormField string       // 为空时不读取表单字段，传统表单提交时可设置为如 "_csrf"
+	// OnError 校验失败时的响应，默认返回 403
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// withDefaults 填充默认值
+func (c CSRFConfig) withDefaults() CSRFConfig {
+	name := DefaultCSRFCookie
+	if c.Cookie.Domain != "" || c.Cookie.Insecure || (c.Cookie.Path != "" && c.Cookie.Path != "/") {
+		name = plainCSRFCookie
+	}
+	c.Cookie = c.Cookie.withDefaults(name)
+	if c.Header == "" {
+		c.Header = DefaultCSRFHeader
+	}
+	if c.OnError == nil {
+		c.OnError = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		}
+	}
+	return c
+}
+
+// SetCSRFCookie 生成新的 CSRF token 并写入 cookie，返回 token（可同时下发给页面模板）；登录成功后应调用一次以轮换 token
+func SetCSRFCookie(w http.ResponseWriter, cfg CSRFConfig) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	http.SetCookie(w, cfg.withDefaults().Cookie.cookie(token, false))
+	return token, nil
+}
+
+// VerifyCSRF 校验请求回传的 CSRF token 与 cookie 中的值一致
+func VerifyCSRF(r *http.Request, cfg CSRFConfig) error {
+	cfg = cfg.withDefaults()
+	ck, err := r.Cookie(cfg.Cookie.Name)
+	if err != nil || ck.Value == "" {
+		return ErrCSRFTokenInvalid
+	}
+	sent := r.Header.Get(cfg.Header)
+	if sent == "" && cfg.FormField != "" {
+		sent = r.PostFormValue(cfg.FormField)
+	}
+	if sent == "" || subtle.ConstantTimeCompare([]byte(sent), []byte(ck.Value)) != 1 {
+		return ErrCSRFTokenInvalid
+	}
+	return nil
+}
+
+// CSRFMiddleware 双重提交 CSRF 防护中间件：GET、HEAD、OPTIONS、TRACE 请求直接放行，
+// 并在请求没有 CSRF cookie 时下发一个；其它方法要求回传的 token 与 cookie 一致，否则调用 OnError
+func CSRFMiddleware(cfg CSRFConfig) func(http.Handler) http.Handler {
+	cfg = cfg.withDefaults()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+				if ck, err := r.Cookie(cfg.Cookie.Name); err != nil || ck.Value == "" {
+					if _, err := SetCSRFCookie(w, cfg); err != nil {
+						cfg.OnError(w, r, err)
+						return
+					}
+				}
+			default:
+				if err := VerifyCSRF(r, cfg); err != nil {
+					cfg.OnError(w, r, err)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
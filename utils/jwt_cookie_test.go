@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenCookie(t *testing.T) {
+	j := NewJWT(JWTConfig{Secret: []byte("test_secret"), ExpireTime: time.Hour})
+	token, err := j.GenerateToken(&MyClaims{UserID: 1001})
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	SetTokenCookie(rec, CookieConfig{MaxAge: time.Hour}, token)
+	ck := rec.Result().Cookies()[0]
+	assert.Equal(t, DefaultTokenCookie, ck.Name)
+	assert.True(t, ck.Secure)
+	assert.True(t, ck.HttpOnly)
+	assert.Equal(t, http.SameSiteLaxMode, ck.SameSite)
+	assert.Equal(t, 3600, ck.MaxAge)
+
+	var got *MyClaims
+	auth := j.Middleware(func() jwt.Claims { return &MyClaims{} }, WithTokenExtractor(CookieToken("")))
+	h := auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = ClaimsFromContext[*MyClaims](r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(ck)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if assert.NotNil(t, got) {
+		assert.Equal(t, int64(1001), got.UserID)
+	}
+
+	rec = httptest.NewRecorder()
+	ClearTokenCookie(rec, CookieConfig{})
+	assert.Equal(t, -1, rec.Result().Cookies()[0].MaxAge)
+}
+
+func TestCSRFMiddleware(t *testing.T) {
+	h := CSRFMiddleware(CSRFConfig{FormField: "_csrf"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// GET 请求下发 CSRF cookie，脚本可读
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	cookies := rec.Result().Cookies()
+	if !assert.Len(t, cookies, 1) {
+		return
+	}
+	csrf := cookies[0]
+	assert.Equal(t, DefaultCSRFCookie, csrf.Name)
+	assert.True(t, csrf.Secure)
+	assert.Equal(t, "/", csrf.Path)
+	assert.Empty(t, csrf.Domain)
+	assert.False(t, csrf.HttpOnly)
+
+	post := func(header, form string, withCookie bool) int {
+		body := url.Values{}
+		if form != "" {
+			body.Set("_csrf", form)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if header != "" {
+			req.Header.Set(DefaultCSRFHeader, header)
+		}
+		if withCookie {
+			req.AddCookie(csrf)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, post(csrf.Value, "", true))
+	assert.Equal(t, http.StatusOK, post("", csrf.Value, true))
+	assert.Equal(t, http.StatusForbidden, post("", "", true))
+	assert.Equal(t, http.StatusForbidden, post("forged", "", true))
+	assert.Equal(t, http.StatusForbidden, post(csrf.Value, "", false))
+
+	// 设置了 Domain 时浏览器不接受 __Host- 前缀，退回普通名称
+	rec = httptest.NewRecorder()
+	_, err := SetCSRFCookie(rec, CSRFConfig{Cookie: CookieConfig{Domain: "example.com"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "csrf_token", rec.Result().Cookies()[0].Name)
+}
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/qingfeng-studio/go-utils/httpx"
+)
+
+// ErrTokenInactive 内省端点返回 active=false
+var ErrTokenInactive = errors.New("token inactive")
+
+// IntrospectionConfig RFC 7662 令牌内省配置
+type IntrospectionConfig struct {
+	Endpoint     string        // 内省端点完整地址
+	ClientID     string        // 资源服务的 client_id，使用 HTTP Basic 认证
+	ClientSecret string        // 资源服务的 client_secret
+	CacheTTL     time.Duration // 内省结果缓存时间，默认 1 分钟；不会超过令牌本身的 exp
+	Timeout      time.Duration // 单次内省请求超时，默认 5 秒
+	Client       *httpx.Client // 自定义 HTTP 客户端，为空时按 Timeout 创建
+}
+
+// maxIntrospectCache 触发过期清理的缓存条数
+const maxIntrospectCache = 10000
+
+// introspectResult 缓存的内省结果
+type introspectResult struct {
+	active  bool
+	raw     []byte
+	expires time.Time
+}
+
+// Introspector 调用内省端点校验不透明令牌，结果按 TTL 缓存在进程内
+type Introspector struct {
+	cfg    IntrospectionConfig
+	client *httpx.Client
+
+	mu    sync.Mutex
+	cache map[[32]byte]introspectResult
+}
+
+// NewIntrospector 创建内省客户端
+func NewIntrospector(cfg IntrospectionConfig) *Introspector {
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = time.Minute
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	client := cfg.Client
+	if client == nil {
+		client = httpx.NewClient(httpx.WithTimeout(cfg.Timeout))
+	}
+	return &Introspector{cfg: cfg, client: client, cache: make(map[[32]byte]introspectResult)}
+}
+
+// Introspect 查询令牌状态，令牌有效时将响应 JSON 解码到 claims（sub/exp/iss/scope 等标准字段）
+// 令牌无效或已过期时返回 ErrTokenInactive
+func (i *Introspector) Introspect(ctx context.Context, token string, claims jwt.Claims) error {
+	key := sha256.Sum256([]byte(token))
+	res, ok := i.cached(key)
+	if !ok {
+		var err error
+		if res, err = i.fetch(ctx, token); err != nil {
+			return err
+		}
+		i.store(key, res)
+	}
+	if !res.active {
+		return ErrTokenInactive
+	}
+	if err := decodeClaims(res.raw, claims); err != nil {
+		return fmt.Errorf("decode introspection response: %w", err)
+	}
+	// 校验 exp/nbf 等时间字段，防止缓存期间令牌已过期
+	if err := jwt.NewValidator().Validate(claims); err != nil {
+		return err
+	}
+	return nil
+}
+
+// cached 读取未过期的缓存结果，顺带清理过期项
+func (i *Introspector) cached(key [32]byte) (introspectResult, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	res, ok := i.cache[key]
+	if !ok {
+		return res, false
+	}
+	if time.Now().After(res.expires) {
+		delete(i.cache, key)
+		return res, false
+	}
+	return res, true
+}
+
+// store 写入缓存，缓存项过多时先清理已过期的结果
+func (i *Introspector) store(key [32]byte, res introspectResult) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if len(i.cache) >= maxIntrospectCache {
+		now := time.Now()
+		for k, v := range i.cache {
+			if now.After(v.expires) {
+				delete(i.cache, k)
+			}
+		}
+	}
+	i.cache[key] = res
+}
+
+// fetch 调用内省端点
+func (i *Introspector) fetch(ctx context.Context, token string) (introspectResult, error) {
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	headers := http.Header{"Accept": {"application/json"}}
+	if i.cfg.ClientID != "" {
+		// RFC 6749 2.3.1：client_id/client_secret 先做 form 编码再 Basic 认证
+		cred := url.QueryEscape(i.cfg.ClientID) + ":" + url.QueryEscape(i.cfg.ClientSecret)
+		headers.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(cred)))
+	}
+
+	_, body, err := i.client.Post(ctx, i.cfg.Endpoint, []byte(form.Encode()), "application/x-www-form-urlencoded", headers, nil)
+	if err != nil {
+		return introspectResult{}, fmt.Errorf("introspect token: %w", err)
+	}
+
+	var meta struct {
+		Active bool  `json:"active"`
+		Exp    int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return introspectResult{}, fmt.Errorf("decode introspection response: %w", err)
+	}
+
+	expires := time.Now().Add(i.cfg.CacheTTL)
+	if meta.Exp > 0 && time.Unix(meta.Exp, 0).Before(expires) {
+		expires = time.Unix(meta.Exp, 0)
+	}
+	return introspectResult{active: meta.Active, raw: body, expires: expires}, nil
+}
+
+// decodeClaims 解码到 claims，jwt.MapClaims 为非指针的 map，需要逐项拷贝
+func decodeClaims(raw []byte, claims jwt.Claims) error {
+	m, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return json.Unmarshal(raw, claims)
+	}
+	var tmp map[string]interface{}
+	if err := json.Unmarshal(raw, &tmp); err != nil {
+		return err
+	}
+	for k, v := range tmp {
+		m[k] = v
+	}
+	return nil
+}
+
+// isJWT 粗略判断 token 是否为 JWS 紧凑格式（三段 base64url）
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
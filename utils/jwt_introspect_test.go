@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTokenIntrospection(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		user, pass, _ := r.BasicAuth()
+		assert.Equal(t, "api", user)
+		assert.Equal(t, "secret", pass)
+		_ = r.ParseForm()
+		if r.PostForm.Get("token") != "opaque-ok" {
+			fmt.Fprint(w, `{"active":false}`)
+			return
+		}
+		fmt.Fprintf(w, `{"active":true,"sub":"u-1","scope":"orders:read","exp":%d}`, time.Now().Add(time.Hour).Unix())
+	}))
+	defer srv.Close()
+
+	j := NewJWT(JWTConfig{
+		Secret:        []byte("test_secret"),
+		Introspection: &IntrospectionConfig{Endpoint: srv.URL, ClientID: "api", ClientSecret: "secret"},
+	})
+
+	for i := 0; i < 3; i++ {
+		claims := jwt.MapClaims{}
+		assert.NoError(t, j.ParseToken("opaque-ok", claims))
+		assert.Equal(t, "u-1", claims["sub"])
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "result should be cached")
+
+	assert.ErrorIs(t, j.ParseToken("opaque-bad", &jwt.RegisteredClaims{}), ErrTokenInactive)
+
+	// JWT 仍走本地校验，不调用内省端点
+	token, err := j.GenerateToken(&jwt.RegisteredClaims{Subject: "u-2"})
+	assert.NoError(t, err)
+	var rc jwt.RegisteredClaims
+	assert.NoError(t, j.ParseToken(token, &rc))
+	assert.Equal(t, "u-2", rc.Subject)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
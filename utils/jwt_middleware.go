@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrTokenMissing 请求中没有携带 token
+var ErrTokenMissing = errors.New("token missing")
+
+// claimsCtxKey context 中存放 claims 的 key
+type claimsCtxKey struct{}
+
+// RoleClaims 自定义 Claims 实现该接口后可配合 RequireRole 使用
+type RoleClaims interface {
+	GetRoles() []string
+}
+
+// ScopeClaims 自定义 Claims 实现该接口后可配合 RequireScope 使用
+type ScopeClaims interface {
+	GetScopes() []string
+}
+
+// MiddlewareOption JWT 中间件选项
+type MiddlewareOption func(*middlewareConfig)
+
+type middlewareConfig struct {
+	extract  func(r *http.Request) string
+	onError  func(w http.ResponseWriter, r *http.Request, err error)
+	optional bool
+	binding  func(r *http.Request) []BindOption
+}
+
+// WithTokenExtractor 自定义 token 的获取方式，默认读取 Authorization: Bearer <token>
+func WithTokenExtractor(fn func(r *http.Request) string) MiddlewareOption {
+	return func(c *middlewareConfig) { c.extract = fn }
+}
+
+// WithAuthErrorHandler 自定义认证失败时的响应，默认返回 401
+func WithAuthErrorHandler(fn func(w http.ResponseWriter, r *http.Request, err error)) MiddlewareOption {
+	return func(c *middlewareConfig) { c.onError = fn }
+}
+
+// WithOptionalAuth 未携带 token 时放行（不写入 claims），携带了无效 token 仍返回错误
+func WithOptionalAuth() MiddlewareOption {
+	return func(c *middlewareConfig) { c.optional = true }
+}
+
+// BearerToken 从 Authorization 头中读取 Bearer token
+func BearerToken(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	if len(h) > 7 && strings.EqualFold(h[:7], "Bearer ") {
+		return strings.TrimSpace(h[7:])
+	}
+	return ""
+}
+
+// Middleware 返回校验 JWT 的 HTTP 中间件，解析成功后 claims 写入 request context
+// newClaims 每次请求返回一个新的 claims 指针（如 func() jwt.Claims { return &MyClaims{} }）
+// 使用示例：
+//
+//	auth := j.Middleware(func() jwt.Claims { return &MyClaims{} })
+//	mux.Handle("/admin", auth(utils.RequireRole("admin")(handler)))
+func (j *JWTService) Middleware(newClaims func() jwt.Claims, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	return AuthMiddleware(j, newClaims, opts...)
+}
+
+// AuthMiddleware 基于任意 TokenService（JWT、PASETO）的认证中间件，行为同 JWTService.Middleware
+func AuthMiddleware(svc TokenService, newClaims func() jwt.Claims, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	cfg := &middlewareConfig{
+		extract: BearerToken,
+		onError: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := cfg.extract(r)
+			if token == "" && cfg.optional {
+				next.ServeHTTP(w, r)
+				return
+			}
+			claims := newClaims()
+			if err := authenticate(r, svc, cfg, token, claims); err != nil {
+				cfg.onError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ContextWithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// auditedService 配置了审计回调的令牌服务（JWTService、PasetoService）
+type auditedService interface {
+	parseUnaudited(ctx context.Context, token string, claims jwt.Claims) error
+	audit(op string, claims jwt.Claims, err error)
+}
+
+// authenticate 解析 token 并校验绑定信息；svc 支持审计时在 defer 中按最终结果上报一次，
+// 缺少 token、绑定校验失败等被拒绝的请求同样会被审计
+func authenticate(r *http.Request, svc TokenService, cfg *middlewareConfig, token string, claims jwt.Claims) (err error) {
+	parse := parseWithContext
+	if as, ok := svc.(auditedService); ok {
+		parse = func(ctx context.Context, _ TokenService, token string, claims jwt.Claims) error {
+			return as.parseUnaudited(ctx, token, claims)
+		}
+		defer func() { as.audit(TokenOpParse, claims, err) }()
+	}
+	if token == "" {
+		return ErrTokenMissing
+	}
+	if err = parse(r.Context(), svc, token, claims); err != nil {
+		return err
+	}
+	if cfg.binding != nil {
+		return VerifyBinding(claims, cfg.binding(r)...)
+	}
+	return nil
+}
+
+// parseWithContext 实现了 ParseTokenContext 的服务优先携带 ctx 解析
+func parseWithContext(ctx context.Context, svc TokenService, token string, claims jwt.Claims) error {
+	if cs, ok := svc.(interface {
+		ParseTokenContext(ctx context.Context, token string, claims jwt.Claims) error
+	}); ok {
+		return cs.ParseTokenContext(ctx, token, claims)
+	}
+	return svc.ParseToken(token, claims)
+}
+
+// ContextWithClaims 将 claims 写入 context
+func ContextWithClaims(ctx context.Context, claims jwt.Claims) context.Context {
+	return context.WithValue(ctx, claimsCtxKey{}, claims)
+}
+
+// ClaimsFromContext 从 context 中取出 claims 并断言为 T（通常为自定义 Claims 的指针类型）
+func ClaimsFromContext[T any](ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(claimsCtxKey{}).(T)
+	return v, ok
+}
+
+// RequireRole 要求 claims 至少具有其中一个角色，否则返回 403；未认证时返回 401
+// claims 需实现 RoleClaims，或为 jwt.MapClaims（读取 role/roles 字段）
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return requireAny(roles, func(c jwt.Claims) []string {
+		if rc, ok := c.(RoleClaims); ok {
+			return rc.GetRoles()
+		}
+		if m, ok := c.(jwt.MapClaims); ok {
+			return append(mapStrings(m["role"]), mapStrings(m["roles"])...)
+		}
+		return nil
+	})
+}
+
+// RequireScope 要求 claims 至少具有其中一个 scope，否则返回 403；未认证时返回 401
+// claims 需实现 ScopeClaims，或为 jwt.MapClaims（读取空格分隔的 scope 或数组形式的 scp 字段）
+func RequireScope(scopes ...string) func(http.Handler) http.Handler {
+	return requireAny(scopes, func(c jwt.Claims) []string {
+		if sc, ok := c.(ScopeClaims); ok {
+			return sc.GetScopes()
+		}
+		if m, ok := c.(jwt.MapClaims); ok {
+			return append(mapStrings(m["scope"]), mapStrings(m["scp"])...)
+		}
+		return nil
+	})
+}
+
+// requireAny 校验 claims 中提取的值与 want 是否有交集
+func requireAny(want []string, values func(jwt.Claims) []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext[jwt.Claims](r.Context())
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			if !containsAny(values(claims), want) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// containsAny 判断 have 与 want 是否有交集
+func containsAny(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mapStrings 将 MapClaims 中的字符串（空格分隔）或数组值转换为字符串切片
+func mapStrings(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return strings.Fields(t)
+	case []string:
+		return t
+	case []interface{}:
+		out := make([]string, 0, len(t))
+		for _, s := range t {
+			if str, ok := s.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func (c *MyClaims) GetRoles() []string {
+	return []string{c.Role}
+}
+
+func TestJWTMiddleware(t *testing.T) {
+	j := NewJWT(JWTConfig{Secret: []byte("test_secret"), ExpireTime: time.Hour})
+	token, err := j.GenerateToken(&MyClaims{UserID: 1001, Role: "editor"})
+	assert.NoError(t, err)
+
+	var got *MyClaims
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = ClaimsFromContext[*MyClaims](r.Context())
+	})
+	auth := j.Middleware(func() jwt.Claims { return &MyClaims{} })
+
+	cases := []struct {
+		name  string
+		token string
+		h     http.Handler
+		code  int
+	}{
+		{"missing token", "", auth(final), http.StatusUnauthorized},
+		{"invalid token", "bad", auth(final), http.StatusUnauthorized},
+		{"ok", token, auth(final), http.StatusOK},
+		{"role ok", token, auth(RequireRole("admin", "editor")(final)), http.StatusOK},
+		{"role denied", token, auth(RequireRole("admin")(final)), http.StatusForbidden},
+		{"no claims", "", RequireRole("admin")(final), http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got = nil
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.token != "" {
+				req.Header.Set("Authorization", "Bearer "+c.token)
+			}
+			rec := httptest.NewRecorder()
+			c.h.ServeHTTP(rec, req)
+			assert.Equal(t, c.code, rec.Code)
+			if c.code == http.StatusOK {
+				assert.NotNil(t, got)
+				assert.Equal(t, int64(1001), got.UserID)
+			}
+		})
+	}
+}
+
+func TestRequireScopeMapClaims(t *testing.T) {
+	j := NewJWT(JWTConfig{Secret: []byte("test_secret")})
+	token, err := j.GenerateToken(jwt.MapClaims{"scope": "orders:read orders:write"})
+	assert.NoError(t, err)
+
+	auth := j.Middleware(func() jwt.Claims { return jwt.MapClaims{} })
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	for scope, code := range map[string]int{"orders:write": http.StatusOK, "users:read": http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		auth(RequireScope(scope)(ok)).ServeHTTP(rec, req)
+		assert.Equal(t, code, rec.Code, scope)
+	}
+}
@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrServiceNotAllowed 调用方服务不在允许列表中
+var ErrServiceNotAllowed = errors.New("service not allowed")
+
+const (
+	// defaultServiceTokenTTL 服务间 token 的默认有效期
+	defaultServiceTokenTTL = 5 * time.Minute
+	// defaultServiceVerifyCache 校验结果的默认缓存时间
+	defaultServiceVerifyCache = 30 * time.Second
+	// maxServiceVerifyCache 触发过期清理的缓存条数
+	maxServiceVerifyCache = 10000
+)
+
+// ServiceTokenConfig 服务间认证配置，调用方与被调用方使用相同的共享秘钥
+type ServiceTokenConfig struct {
+	Service  string        // 本服务名，签发时作为 iss，校验时要求 aud 包含该名称
+	Secret   []byte        // 共享签名秘钥（HS256）
+	TTL      time.Duration // 签发的 token 有效期，默认 5 分钟
+	CacheTTL time.Duration // 校验结果按 token 摘要缓存的时间，默认 30 秒；不会超过 token 本身的 exp
+	Callers  []string      // 允许调用本服务的服务名（iss），为空表示不限制
+}
+
+// ServiceClaims 服务间 token 的 claims，iss 为调用方服务，aud 为目标服务
+type ServiceClaims struct {
+	jwt.RegisteredClaims
+}
+
+// Caller 调用方服务名
+func (c *ServiceClaims) Caller() string { return c.Issuer }
+
+// issuedToken 按目标服务缓存的已签发 token
+type issuedToken struct {
+	token   string
+	refresh time.Time // 过了有效期的一半后重新签发
+}
+
+// verifiedToken 校验结果缓存
+type verifiedToken struct {
+	claims  ServiceClaims
+	expires time.Time
+}
+
+// ServiceTokenIssuer 微服务之间的短期 token：调用时按目标服务签发 aud 不同的 token（并复用到有效期过半），
+// 被调用方通过 Middleware 校验，校验结果按 token 摘要短暂缓存，内部调用的认证开销接近于零
+// 使用示例：
+//
+//	st := utils.NewServiceTokenIssuer(utils.ServiceTokenConfig{Service: "order-svc", Secret: secret, Callers: []string{"gateway"}})
+//	// 调用方
+//	token, _ := st.Token("inventory-svc")
+//	req.Header.Set("Authorization", "Bearer "+token)
+//	// 被调用方
+//	mux.Handle("/internal/", st.Middleware()(internalHandler))
+type ServiceTokenIssuer struct {
+	cfg ServiceTokenConfig
+
+	mu       sync.Mutex
+	issued   map[string]issuedToken
+	verified map[[32]byte]verifiedToken
+}
+
+// NewServiceTokenIssuer 创建服务间 token 的签发与校验器
+func NewServiceTokenIssuer(cfg ServiceTokenConfig) *ServiceTokenIssuer {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultServiceTokenTTL
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = defaultServiceVerifyCache
+	}
+	return &ServiceTokenIssuer{
+		cfg:      cfg,
+		issued:   make(map[string]issuedToken),
+		verified: make(map[[32]byte]verifiedToken),
+	}
+}
+
+// Token 返回调用 audience 服务使用的 token，有效期过半前复用同一个 token
+func (s *ServiceTokenIssuer) Token(audience string) (string, error) {
+	now := time.Now()
+	s.mu.Lock()
+	if t, ok := s.issued[audience]; ok && now.Before(t.refresh) {
+		s.mu.Unlock()
+		return t.token, nil
+	}
+	s.mu.Unlock()
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	claims := ServiceClaims{RegisteredClaims: jwt.RegisteredClaims{
+		Issuer:    s.cfg.Service,
+		Audience:  jwt.ClaimStrings{audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(s.cfg.TTL)),
+		ID:        base64.RawURLEncoding.EncodeToString(id),
+	}}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims).SignedString(s.cfg.Secret)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	s.issued[audience] = issuedToken{token: token, refresh: now.Add(s.cfg.TTL / 2)}
+	s.mu.Unlock()
+	return token, nil
+}
+
+// Verify 校验发给本服务的 token：签名、有效期、aud 为本服务、iss 在 Callers 中；成功的结果按 token 摘要缓存
+func (s *ServiceTokenIssuer) Verify(token string) (*ServiceClaims, error) {
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+	s.mu.Lock()
+	if v, ok := s.verified[key]; ok {
+		if now.Before(v.expires) {
+			s.mu.Unlock()
+			c := v.claims
+			return &c, nil
+		}
+		delete(s.verified, key)
+	}
+	s.mu.Unlock()
+
+	claims := &ServiceClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return s.cfg.Secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(s.cfg.Service), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	if len(s.cfg.Callers) > 0 && !slices.Contains(s.cfg.Callers, claims.Issuer) {
+		return nil, ErrServiceNotAllowed
+	}
+
+	expires := now.Add(s.cfg.CacheTTL)
+	if exp := claims.ExpiresAt.Time; exp.Before(expires) {
+		expires = exp
+	}
+	s.mu.Lock()
+	if len(s.verified) >= maxServiceVerifyCache {
+		for k, v := range s.verified {
+			if now.After(v.expires) {
+				delete(s.verified, k)
+			}
+		}
+	}
+	s.verified[key] = verifiedToken{claims: *claims, expires: expires}
+	s.mu.Unlock()
+	return claims, nil
+}
+
+// ServiceMiddlewareOption 服务间认证中间件的选项；与 MiddlewareOption 是不同的类型，
+// 服务间 token 不支持的 WithTokenBinding 等用户 token 选项在编译期即被拒绝
+type ServiceMiddlewareOption func(*middlewareConfig)
+
+// WithServiceTokenExtractor 自定义服务间 token 的获取方式，默认读取 Authorization: Bearer <token>
+func WithServiceTokenExtractor(fn func(r *http.Request) string) ServiceMiddlewareOption {
+	return func(c *middlewareConfig) { c.extract = fn }
+}
+
+// WithServiceAuthErrorHandler 自定义服务间认证失败时的响应，默认返回 401
+func WithServiceAuthErrorHandler(fn func(w http.ResponseWriter, r *http.Request, err error)) ServiceMiddlewareOption {
+	return func(c *middlewareConfig) { c.onError = fn }
+}
+
+// WithServiceOptionalAuth 未携带 token 时放行（不写入 claims），携带了无效 token 仍返回错误
+func WithServiceOptionalAuth() ServiceMiddlewareOption {
+	return func(c *middlewareConfig) { c.optional = true }
+}
+
+// Middleware 校验服务间 token 的中间件，成功后 *ServiceClaims 写入 request context（可用 ClaimsFromContext 读取）
+func (s *ServiceTokenIssuer) Middleware(opts ...ServiceMiddlewareOption) func(http.Handler) http.Handler {
+	cfg := &middlewareConfig{
+		extract: BearerToken,
+		onError: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := cfg.extract(r)
+			if token == "" {
+				if cfg.optional {
+					next.ServeHTTP(w, r)
+					return
+				}
+				cfg.onError(w, r, ErrTokenMissing)
+				return
+			}
+			claims, err := s.Verify(token)
+			if err != nil {
+				cfg.onError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ContextWithClaims(r.Context(), claims)))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceTokenIssuer(t *testing.T) {
+	secret := []byte("internal_secret")
+	gateway := NewServiceTokenIssuer(ServiceTokenConfig{Service: "gateway", Secret: secret})
+	orders := NewServiceTokenIssuer(ServiceTokenConfig{Service: "order-svc", Secret: secret, Callers: []string{"gateway"}})
+
+	token, err := gateway.Token("order-svc")
+	assert.NoError(t, err)
+	again, _ := gateway.Token("order-svc")
+	assert.Equal(t, token, again, "token should be reused before half of its ttl")
+
+	claims, err := orders.Verify(token)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "gateway", claims.Caller())
+	}
+	// 缓存命中返回副本
+	cached, err := orders.Verify(token)
+	assert.NoError(t, err)
+	assert.NotSame(t, claims, cached)
+
+	// aud 不是本服务
+	other, _ := gateway.Token("inventory-svc")
+	_, err = orders.Verify(other)
+	assert.Error(t, err)
+
+	// 调用方不在允许列表中
+	billing := NewServiceTokenIssuer(ServiceTokenConfig{Service: "billing", Secret: secret})
+	bt, _ := billing.Token("order-svc")
+	_, err = orders.Verify(bt)
+	assert.ErrorIs(t, err, ErrServiceNotAllowed)
+}
+
+func TestServiceTokenIssuer_Expired(t *testing.T) {
+	s := NewServiceTokenIssuer(ServiceTokenConfig{Service: "svc", Secret: []byte("k"), TTL: time.Second, CacheTTL: time.Minute})
+	token, _ := s.Token("svc")
+	_, err := s.Verify(token)
+	assert.NoError(t, err)
+	time.Sleep(1100 * time.Millisecond)
+	// 缓存不会超过 token 本身的有效期
+	_, err = s.Verify(token)
+	assert.Error(t, err)
+}
+
+func TestServiceTokenIssuer_Middleware(t *testing.T) {
+	s := NewServiceTokenIssuer(ServiceTokenConfig{Service: "svc", Secret: []byte("k")})
+	token, _ := s.Token("svc")
+
+	var caller string
+	h := s.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, ok := ClaimsFromContext[*ServiceClaims](r.Context()); ok {
+			caller = c.Caller()
+		}
+	}))
+	for _, tc := range []struct {
+		token string
+		code  int
+	}{{"", http.StatusUnauthorized}, {"bad", http.StatusUnauthorized}, {token, http.StatusOK}} {
+		req := httptest.NewRequest(http.MethodGet, "/internal", nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, tc.code, rec.Code)
+	}
+	assert.Equal(t, "svc", caller)
+}
+
+func TestServiceTokenIssuer_MiddlewareOptions(t *testing.T) {
+	s := NewServiceTokenIssuer(ServiceTokenConfig{Service: "svc", Secret: []byte("k")})
+	token, _ := s.Token("svc")
+
+	var errs []error
+	h := s.Middleware(
+		WithServiceTokenExtractor(func(r *http.Request) string { return r.Header.Get("X-Service-Token") }),
+		WithServiceAuthErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+			errs = append(errs, err)
+			w.WriteHeader(http.StatusForbidden)
+		}),
+		WithServiceOptionalAuth(),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, tc := range []struct {
+		token string
+		code  int
+	}{{"", http.StatusOK}, {"bad", http.StatusForbidden}, {token, http.StatusOK}} {
+		req := httptest.NewRequest(http.MethodGet, "/internal", nil)
+		req.Header.Set("X-Service-Token", tc.token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, tc.code, rec.Code, "token %q", tc.token)
+	}
+	assert.Len(t, errs, 1)
+}
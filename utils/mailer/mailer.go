@@ -0,0 +1,284 @@
+// Package mailer 邮件发送：SMTP（隐式 TLS / STARTTLS）、HTML 与纯文本双正文、附件与内嵌资源、
+// 结合 templatex 渲染正文、临时错误自动重试，并提供用于测试的 MockSender
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/utils/backoff"
+)
+
+// Sender 邮件发送接口，业务代码依赖该接口，测试时替换为 MockSender
+type Sender interface {
+	Send(ctx context.Context, m *Message) error
+}
+
+// TLSMode SMTP 连接的加密方式
+type TLSMode int
+
+const (
+	// TLSAuto 服务器支持时使用 STARTTLS，否则明文（默认）
+	TLSAuto TLSMode = iota
+	// TLSImplicit 连接建立即使用 TLS，常用于 465 端口
+	TLSImplicit
+	// TLSStartTLS 强制 STARTTLS，服务器不支持时返回错误，常用于 587 端口
+	TLSStartTLS
+	// TLSNone 不加密，仅用于本地测试
+	TLSNone
+)
+
+// Config SMTP 配置
+type Config struct {
+	Host     string
+	Port     int // 默认：TLSImplicit 为 465，其它为 587
+	Username string
+	Password string
+	From     string // 默认发件人，Message.From 为空时使用
+	TLS      TLSMode
+
+	TLSConfig *tls.Config   // 可选，ServerName 默认取 Host
+	Timeout   time.Duration // 单次投递（连接到 QUIT）的超时，默认 30s
+	LocalName string        // HELO/EHLO 使用的主机名，默认 localhost
+}
+
+// Option SMTP 发送器选项
+type Option func(*SMTPSender)
+
+// WithRetry 设置失败重试：最多重试 attempts 次，第 n 次重试前等待 backoff * 2^(n-1)；
+// 仅重试临时错误（网络错误与 4xx 响应），5xx 等永久错误立即返回；
+// 服务器接受 DATA 之后的错误不重试，此时邮件可能已被接收，重试会导致重复投递。默认不重试
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(s *SMTPSender) {
+		s.retries = attempts
+		s.backoff = backoff
+	}
+}
+
+// SMTPSender 基于 SMTP 的发送器，每次发送建立新连接，可并发使用
+type SMTPSender struct {
+	cfg     Config
+	retries int
+	backoff time.Duration
+}
+
+// NewSMTP 创建 SMTP 发送器
+//
+//	s := mailer.NewSMTP(mailer.Config{Host: "smtp.exmail.qq.com", TLS: mailer.TLSImplicit,
+//		Username: "noreply@example.com", Password: pwd, From: "青枫 <noreply@example.com>"},
+//		mailer.WithRetry(2, time.Second))
+//	err := s.Send(ctx, &mailer.Message{To: []string{"user@example.com"}, Subject: "欢迎", HTML: body})
+func NewSMTP(cfg Config, opts ...Option) *SMTPSender {
+	if cfg.Port == 0 {
+		cfg.Port = 587
+		if cfg.TLS == TLSImplicit {
+			cfg.Port = 465
+		}
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.LocalName == "" {
+		cfg.LocalName = "localhost"
+	}
+	s := &SMTPSender{cfg: cfg}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Send 发送邮件，临时错误按 WithRetry 的设置重试
+func (s *SMTPSender) Send(ctx context.Context, m *Message) error {
+	if m.From == "" {
+		msg := *m
+		msg.From = s.cfg.From
+		m = &msg
+	}
+	data, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+	rcpts, err := m.Recipients()
+	if err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = s.send(ctx, from.Address, rcpts, data)
+		var de *dataError
+		if err == nil || attempt >= s.retries || !IsTemporary(err) || errors.As(err, &de) {
+			return err
+		}
+		if backoff.Sleep(ctx, backoff.Exponential(s.backoff, 0).Delay(attempt, 0)) != nil {
+			return err
+		}
+	}
+}
+
+// send 完成一次 SMTP 投递
+func (s *SMTPSender) send(ctx context.Context, from string, rcpts []string, data []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("mailer: dial %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	// ctx 提前取消时关闭连接，使阻塞的读写立即返回
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if s.cfg.TLS == TLSImplicit {
+		conn = tls.Client(conn, s.tlsConfig())
+	}
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("mailer: connect: %w", err)
+	}
+	defer c.Close()
+
+	if err := c.Hello(s.cfg.LocalName); err != nil {
+		return fmt.Errorf("mailer: hello: %w", err)
+	}
+	if s.cfg.TLS == TLSStartTLS || s.cfg.TLS == TLSAuto {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(s.tlsConfig()); err != nil {
+				return fmt.Errorf("mailer: starttls: %w", err)
+			}
+		} else if s.cfg.TLS == TLSStartTLS {
+			return errors.New("mailer: server does not support STARTTLS")
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := c.Auth(s.auth()); err != nil {
+			return fmt.Errorf("mailer: auth: %w", err)
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return fmt.Errorf("mailer: mail from: %w", err)
+	}
+	for _, rcpt := range rcpts {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("mailer: rcpt %s: %w", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("mailer: data: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return &dataError{err: err}
+	}
+	if err := w.Close(); err != nil {
+		return &dataError{err: err}
+	}
+	// 邮件已被接收，QUIT 失败不影响投递结果
+	_ = c.Quit()
+	return nil
+}
+
+// dataError 服务器接受 DATA 之后发生的错误，邮件可能已被接收，Send 不再重试
+type dataError struct {
+	err error
+}
+
+// Error 实现 error 接口
+func (e *dataError) Error() string { return "mailer: data: " + e.err.Error() }
+
+// Unwrap 返回底层错误
+func (e *dataError) Unwrap() error { return e.err }
+
+// auth 使用 PLAIN 认证；TLSNone 时 net/smtp 仅允许对 localhost 明文认证
+func (s *SMTPSender) auth() smtp.Auth {
+	return smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+}
+
+// tlsConfig 返回 TLS 配置，ServerName 默认取 Host
+func (s *SMTPSender) tlsConfig() *tls.Config {
+	cfg := &tls.Config{}
+	if s.cfg.TLSConfig != nil {
+		cfg = s.cfg.TLSConfig.Clone()
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = s.cfg.Host
+	}
+	return cfg
+}
+
+// IsTemporary 判断错误是否可重试：网络错误、连接被关闭与 4xx 响应
+func IsTemporary(err error) bool {
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) {
+		return tpErr.Code >= 400 && tpErr.Code < 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// MockSender 记录发送的邮件，用于测试
+type MockSender struct {
+	// Err 非 nil 时 Send 返回该错误且不记录邮件
+	Err error
+
+	mu   sync.Mutex
+	sent []Message
+}
+
+// Send 实现 Sender，会校验邮件并生成内容，以便在测试中发现格式问题
+func (s *MockSender) Send(_ context.Context, m *Message) error {
+	if _, err := m.Bytes(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return s.Err
+	}
+	s.sent = append(s.sent, *m)
+	return nil
+}
+
+// Sent 返回已发送的邮件
+func (s *MockSender) Sent() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.sent...)
+}
+
+// Last 返回最后一封邮件，没有时返回 false
+func (s *MockSender) Last() (Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.sent) == 0 {
+		return Message{}, false
+	}
+	return s.sent[len(s.sent)-1], true
+}
+
+// Reset 清空记录
+func (s *MockSender) Reset() {
+	s.mu.Lock()
+	s.sent = nil
+	s.mu.Unlock()
+}
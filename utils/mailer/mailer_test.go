@@ -0,0 +1,237 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qingfeng-studio/go-utils/utils/templatex"
+)
+
+// fakeSMTP 极简 SMTP 服务器，rejectMail 次数内对 MAIL FROM 返回 421；dropData 为 true 时收到邮件内容后不回复直接断开
+type fakeSMTP struct {
+	ln         net.Listener
+	mu         sync.Mutex
+	rejectMail int
+	dropData   bool
+	attempts   int
+	rcpts      []string
+	data       []string
+}
+
+func newFakeSMTP(t *testing.T, rejectMail int) *fakeSMTP {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeSMTP{ln: ln, rejectMail: rejectMail}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *fakeSMTP) port() int { return s.ln.Addr().(*net.TCPAddr).Port }
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 fake")
+		case "MAIL":
+			s.mu.Lock()
+			s.attempts++
+			reject := s.attempts <= s.rejectMail
+			s.mu.Unlock()
+			if reject {
+				tp.PrintfLine("421 try again later")
+				continue
+			}
+			tp.PrintfLine("250 ok")
+		case "RCPT":
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, line[len("RCPT TO:"):])
+			s.mu.Unlock()
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			b, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = append(s.data, string(b))
+			drop := s.dropData
+			s.mu.Unlock()
+			if drop {
+				return
+			}
+			tp.PrintfLine("250 queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPSendWithRetry(t *testing.T) {
+	srv := newFakeSMTP(t, 1)
+	s := NewSMTP(Config{Host: "127.0.0.1", Port: srv.port(), TLS: TLSNone, From: "青枫 <noreply@example.com>"},
+		WithRetry(2, time.Millisecond))
+
+	err := s.Send(context.Background(), &Message{
+		To:      []string{"张三 <zhangsan@example.com>"},
+		Bcc:     []string{"audit@example.com"},
+		Subject: "欢迎",
+		Text:    "hello",
+	})
+	require.NoError(t, err)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	assert.Equal(t, 2, srv.attempts)
+	assert.Equal(t, []string{"<zhangsan@example.com>", "<audit@example.com>"}, srv.rcpts)
+	require.Len(t, srv.data, 1)
+	msg, err := mail.ReadMessage(strings.NewReader(srv.data[0]))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "欢迎", subject)
+	assert.Empty(t, msg.Header.Get("Bcc"))
+	assert.Contains(t, msg.Header.Get("From"), "<noreply@example.com>")
+}
+
+func TestSMTPNoRetryAfterData(t *testing.T) {
+	srv := newFakeSMTP(t, 0)
+	srv.dropData = true
+	s := NewSMTP(Config{Host: "127.0.0.1", Port: srv.port(), TLS: TLSNone}, WithRetry(3, time.Millisecond))
+	err := s.Send(context.Background(), &Message{From: "a@example.com", To: []string{"b@example.com"}, Text: "x"})
+	require.Error(t, err)
+	assert.True(t, IsTemporary(err))
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	assert.Equal(t, 1, srv.attempts)
+	assert.Len(t, srv.data, 1)
+}
+
+func TestSMTPPermanentError(t *testing.T) {
+	srv := newFakeSMTP(t, 0)
+	s := NewSMTP(Config{Host: "127.0.0.1", Port: srv.port(), TLS: TLSStartTLS}, WithRetry(3, time.Millisecond))
+	err := s.Send(context.Background(), &Message{From: "a@example.com", To: []string{"b@example.com"}, Text: "x"})
+	assert.EqualError(t, err, "mailer: server does not support STARTTLS")
+
+	assert.True(t, IsTemporary(&textproto.Error{Code: 451}))
+	assert.False(t, IsTemporary(&textproto.Error{Code: 550}))
+}
+
+func TestMessageMultipart(t *testing.T) {
+	m := &Message{From: "a@example.com", To: []string{"b@example.com"}, Subject: "账单", Text: "纯文本", HTML: `<img src="cid:logo">`}
+	m.Embed("logo", "logo.png", []byte{0x89, 'P', 'N', 'G'})
+	m.Attach("账单.pdf", []byte("%PDF-1.4"))
+	raw, err := m.Bytes()
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	mixed := readParts(t, msg.Header.Get("Content-Type"), msg.Body)
+	require.Len(t, mixed, 2)
+	assert.Equal(t, "multipart/related", mixed[0].mediaType)
+	assert.Equal(t, "application/pdf", mixed[1].mediaType)
+
+	related := readParts(t, mixed[0].header.Get("Content-Type"), strings.NewReader(mixed[0].body))
+	require.Len(t, related, 2)
+	assert.Equal(t, "multipart/alternative", related[0].mediaType)
+	assert.Equal(t, "<logo>", related[1].header.Get("Content-ID"))
+
+	alt := readParts(t, related[0].header.Get("Content-Type"), strings.NewReader(related[0].body))
+	require.Len(t, alt, 2)
+	assert.Equal(t, "纯文本", alt[0].body)
+	assert.Equal(t, `<img src="cid:logo">`, alt[1].body)
+}
+
+type part struct {
+	mediaType string
+	header    textproto.MIMEHeader
+	body      string
+}
+
+func readParts(t *testing.T, contentType string, r io.Reader) []part {
+	_, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+	mr := multipart.NewReader(r, params["boundary"])
+	var parts []part
+	for {
+		p, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return parts
+		}
+		require.NoError(t, err)
+		// multipart.Reader 会自动解码 quoted-printable
+		b, err := io.ReadAll(p)
+		require.NoError(t, err)
+		mt, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		parts = append(parts, part{mediaType: mt, header: p.Header, body: string(b)})
+	}
+}
+
+func TestValidateAndMock(t *testing.T) {
+	assert.ErrorIs(t, (&Message{To: []string{"b@example.com"}}).Validate(), ErrNoSender)
+	assert.ErrorIs(t, (&Message{From: "a@example.com"}).Validate(), ErrNoRecipients)
+	assert.ErrorIs(t, (&Message{From: "a@example.com", To: []string{"b@example.com"}, Subject: "x\r\nBcc: evil@example.com"}).Validate(), ErrInvalidHeader)
+	for _, a := range []Attachment{
+		{Filename: "a.txt\r\nX-Evil: 1"},
+		{Filename: "a.png", ContentType: "image/png\nX-Evil: 1"},
+		{Filename: "a.png", Inline: true, ContentID: "logo>\r\nX-Evil: 1"},
+	} {
+		m := &Message{From: "a@example.com", To: []string{"b@example.com"}, Attachments: []Attachment{a}}
+		assert.ErrorIs(t, m.Validate(), ErrInvalidHeader)
+	}
+
+	e := templatex.New()
+	require.NoError(t, e.Parse("welcome", `你好 {{.Name}}，余额 {{.Balance | money "¥"}}`))
+	m := &Message{From: "a@example.com", To: []string{"b@example.com"}, Subject: "欢迎"}
+	require.NoError(t, m.RenderHTML(e, "", "welcome", map[string]interface{}{"Name": "<b>", "Balance": 12}))
+	assert.Equal(t, "你好 &lt;b&gt;，余额 ¥12.00", m.HTML)
+
+	var mock MockSender
+	var sender Sender = &mock
+	require.NoError(t, sender.Send(context.Background(), m))
+	last, ok := mock.Last()
+	require.True(t, ok)
+	assert.Equal(t, "欢迎", last.Subject)
+
+	mock.Err = errors.New("boom")
+	assert.Error(t, sender.Send(context.Background(), m))
+	assert.Len(t, mock.Sent(), 1)
+	mock.Reset()
+	assert.Empty(t, mock.Sent())
+}
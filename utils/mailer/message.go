@@ -0,0 +1,367 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/utils/templatex"
+)
+
+var (
+	// ErrNoSender 未设置发件人
+	ErrNoSender = errors.New("mailer: missing sender")
+	// ErrNoRecipients 未设置收件人
+	ErrNoRecipients = errors.New("mailer: missing recipients")
+	// ErrInvalidHeader 头部包含换行符（防止头部注入）
+	ErrInvalidHeader = errors.New("mailer: invalid header value")
+)
+
+// Attachment 附件，Inline 为 true 时作为内嵌资源，可在 HTML 中通过 cid:ContentID 引用
+type Attachment struct {
+	Filename    string
+	ContentType string // 为空时按扩展名推断
+	Data        []byte
+	Inline      bool
+	ContentID   string // 内嵌资源 ID，为空时使用 Filename
+}
+
+// Message 邮件，HTML 与 Text 同时设置时生成 multipart/alternative，客户端优先展示 HTML
+type Message struct {
+	From        string // 支持 "名称 <addr@example.com>" 格式，为空时使用 Sender 的默认发件人
+	To          []string
+	Cc          []string
+	Bcc         []string // 仅用于投递，不出现在邮件头中
+	ReplyTo     string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+	Headers     map[string]string // 额外的邮件头
+}
+
+// Attach 添加附件
+func (m *Message) Attach(filename string, data []byte) *Message {
+	m.Attachments = append(m.Attachments, Attachment{Filename: filename, Data: data})
+	return m
+}
+
+// AttachFile 从文件读取并添加附件
+func (m *Message) AttachFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("mailer: read attachment: %w", err)
+	}
+	m.Attach(filepath.Base(path), data)
+	return nil
+}
+
+// Embed 添加内嵌资源（如 logo 图片），HTML 中以 <img src="cid:{cid}"> 引用
+func (m *Message) Embed(cid, filename string, data []byte) *Message {
+	m.Attachments = append(m.Attachments, Attachment{Filename: filename, Data: data, Inline: true, ContentID: cid})
+	return m
+}
+
+// RenderHTML 使用模板渲染 HTML 正文，layout 为空时直接渲染页面
+func (m *Message) RenderHTML(e *templatex.Engine, layout, name string, data interface{}) error {
+	s, err := e.RenderLayout(layout, name, data)
+	if err != nil {
+		return err
+	}
+	m.HTML = s
+	return nil
+}
+
+// RenderText 使用模板渲染纯文本正文，通常配合 templatex.WithMode(templatex.Text) 创建的引擎
+func (m *Message) RenderText(e *templatex.Engine, layout, name string, data interface{}) error {
+	s, err := e.RenderLayout(layout, name, data)
+	if err != nil {
+		return err
+	}
+	m.Text = s
+	return nil
+}
+
+// Recipients 返回投递的全部收件人地址（To、Cc、Bcc，已去重）
+func (m *Message) Recipients() ([]string, error) {
+	seen := make(map[string]bool)
+	var rcpts []string
+	for _, list := range [][]string{m.To, m.Cc, m.Bcc} {
+		for _, s := range list {
+			addr, err := mail.ParseAddress(s)
+			if err != nil {
+				return nil, fmt.Errorf("mailer: invalid recipient %q: %w", s, err)
+			}
+			if !seen[addr.Address] {
+				seen[addr.Address] = true
+				rcpts = append(rcpts, addr.Address)
+			}
+		}
+	}
+	if len(rcpts) == 0 {
+		return nil, ErrNoRecipients
+	}
+	return rcpts, nil
+}
+
+// Validate 校验发件人、收件人与头部（含附件的 Filename、ContentType、ContentID）
+func (m *Message) Validate() error {
+	if m.From == "" {
+		return ErrNoSender
+	}
+	if _, err := mail.ParseAddress(m.From); err != nil {
+		return fmt.Errorf("mailer: invalid sender %q: %w", m.From, err)
+	}
+	if _, err := m.Recipients(); err != nil {
+		return err
+	}
+	values := []string{m.From, m.ReplyTo, m.Subject}
+	values = append(values, m.To...)
+	values = append(values, m.Cc...)
+	for k, v := range m.Headers {
+		values = append(values, k, v)
+	}
+	// 附件的文件名、类型与 Content-ID 同样写入（子部分）头部
+	for _, a := range m.Attachments {
+		values = append(values, a.Filename, a.ContentType, a.ContentID)
+	}
+	for _, v := range values {
+		if strings.ContainsAny(v, "\r\n") {
+			return fmt.Errorf("%w: %q", ErrInvalidHeader, v)
+		}
+	}
+	return nil
+}
+
+// Bytes 生成 RFC 5322 格式的完整邮件内容
+func (m *Message) Bytes() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	h := make(textproto.MIMEHeader)
+	h.Set("From", encodeAddress(m.From))
+	h.Set("To", encodeAddressList(m.To))
+	if len(m.Cc) > 0 {
+		h.Set("Cc", encodeAddressList(m.Cc))
+	}
+	if m.ReplyTo != "" {
+		h.Set("Reply-To", encodeAddress(m.ReplyTo))
+	}
+	h.Set("Subject", mime.BEncoding.Encode("utf-8", m.Subject))
+	h.Set("Date", time.Now().Format(time.RFC1123Z))
+	h.Set("Message-ID", messageID(m.From))
+	h.Set("MIME-Version", "1.0")
+	for k, v := range m.Headers {
+		h.Set(k, v)
+	}
+
+	var regular, inline []Attachment
+	for _, a := range m.Attachments {
+		if a.Inline {
+			inline = append(inline, a)
+		} else {
+			regular = append(regular, a)
+		}
+	}
+
+	ph, body, err := m.body(inline)
+	if err != nil {
+		return nil, err
+	}
+	if len(regular) > 0 {
+		var mixed bytes.Buffer
+		mw := multipart.NewWriter(&mixed)
+		if err := writePart(mw, ph, body); err != nil {
+			return nil, err
+		}
+		for _, a := range regular {
+			if err := writeAttachment(mw, a); err != nil {
+				return nil, err
+			}
+		}
+		if err := mw.Close(); err != nil {
+			return nil, err
+		}
+		ph = textproto.MIMEHeader{"Content-Type": {"multipart/mixed; boundary=" + mw.Boundary()}}
+		body = mixed.Bytes()
+	}
+	for k, v := range ph {
+		h[k] = v
+	}
+	writeHeader(&buf, h)
+	buf.Write(body)
+	return buf.Bytes(), nil
+}
+
+// body 生成正文部分的头部与内容：有内嵌资源时为 multipart/related，HTML 与 Text 同时存在时为 multipart/alternative
+func (m *Message) body(inline []Attachment) (textproto.MIMEHeader, []byte, error) {
+	ph, body, err := m.alternative()
+	if err != nil || len(inline) == 0 || m.HTML == "" {
+		return ph, body, err
+	}
+	var related bytes.Buffer
+	mw := multipart.NewWriter(&related)
+	if err := writePart(mw, ph, body); err != nil {
+		return nil, nil, err
+	}
+	for _, a := range inline {
+		if err := writeAttachment(mw, a); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, nil, err
+	}
+	return textproto.MIMEHeader{"Content-Type": {`multipart/related; type="text/html"; boundary=` + mw.Boundary()}}, related.Bytes(), nil
+}
+
+// alternative 生成 HTML/Text 正文
+func (m *Message) alternative() (textproto.MIMEHeader, []byte, error) {
+	if m.HTML == "" || m.Text == "" {
+		typ, text := "text/plain", m.Text
+		if m.HTML != "" {
+			typ, text = "text/html", m.HTML
+		}
+		return textPart(typ, text)
+	}
+	var alt bytes.Buffer
+	mw := multipart.NewWriter(&alt)
+	for _, p := range []struct{ typ, text string }{{"text/plain", m.Text}, {"text/html", m.HTML}} {
+		ph, body, err := textPart(p.typ, p.text)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := writePart(mw, ph, body); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, nil, err
+	}
+	return textproto.MIMEHeader{"Content-Type": {"multipart/alternative; boundary=" + mw.Boundary()}}, alt.Bytes(), nil
+}
+
+// textPart 以 quoted-printable 编码文本正文
+func textPart(typ, text string) (textproto.MIMEHeader, []byte, error) {
+	var buf bytes.Buffer
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := io.WriteString(qp, text); err != nil {
+		return nil, nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, nil, err
+	}
+	return textproto.MIMEHeader{
+		"Content-Type":              {typ + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	}, buf.Bytes(), nil
+}
+
+// writePart 写出 multipart 子部分
+func writePart(mw *multipart.Writer, h textproto.MIMEHeader, body []byte) error {
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(body)
+	return err
+}
+
+// writeAttachment 写出 base64 编码的附件
+func writeAttachment(mw *multipart.Writer, a Attachment) error {
+	ct := a.ContentType
+	if ct == "" {
+		ct = mime.TypeByExtension(filepath.Ext(a.Filename))
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+	}
+	name := mime.BEncoding.Encode("utf-8", a.Filename)
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Type", ct+`; name="`+name+`"`)
+	h.Set("Content-Transfer-Encoding", "base64")
+	if a.Inline {
+		cid := a.ContentID
+		if cid == "" {
+			cid = a.Filename
+		}
+		h.Set("Content-ID", "<"+cid+">")
+		h.Set("Content-Disposition", `inline; filename="`+name+`"`)
+	} else {
+		h.Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	}
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	enc := base64.StdEncoding.EncodeToString(a.Data)
+	for len(enc) > 76 {
+		if _, err := io.WriteString(part, enc[:76]+"\r\n"); err != nil {
+			return err
+		}
+		enc = enc[76:]
+	}
+	_, err = io.WriteString(part, enc+"\r\n")
+	return err
+}
+
+// writeHeader 按键排序写出头部并以空行结束
+func writeHeader(w io.Writer, h textproto.MIMEHeader) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range h[k] {
+			fmt.Fprintf(w, "%s: %s\r\n", k, v)
+		}
+	}
+	io.WriteString(w, "\r\n")
+}
+
+// encodeAddress 编码地址中的非 ASCII 名称
+func encodeAddress(s string) string {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return s
+	}
+	return addr.String()
+}
+
+// encodeAddressList 编码地址列表
+func encodeAddressList(list []string) string {
+	out := make([]string, len(list))
+	for i, s := range list {
+		out[i] = encodeAddress(s)
+	}
+	return strings.Join(out, ", ")
+}
+
+// messageID 生成 Message-ID，域名取自发件人地址
+func messageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndexByte(addr.Address, '@'); i >= 0 {
+			domain = addr.Address[i+1:]
+		}
+	}
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return "<" + hex.EncodeToString(buf) + "@" + domain + ">"
+}
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// 客户端调用结果，作为 result 标签的取值
+const (
+	ResultOK      = "ok"
+	ResultError   = "error"
+	ResultTimeout = "timeout"
+)
+
+// Result 按错误返回 result 标签的取值，超时（context.DeadlineExceeded 或实现 Timeout() bool 的错误）为 ResultTimeout
+func Result(err error) string {
+	if err == nil {
+		return ResultOK
+	}
+	var te interface{ Timeout() bool }
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &te) && te.Timeout()) {
+		return ResultTimeout
+	}
+	return ResultError
+}
+
+// ClientMetrics httpx、mysqlx、rediscluster 等客户端共用的调用指标，同一注册表中按 subsystem 区分：
+//   - <subsystem>_client_requests_total{client,operation,result}：调用次数
+//   - <subsystem>_client_request_duration_seconds{client,operation}：调用耗时
+//
+// client 为客户端实例名（如下游服务名、库名），operation 为操作（如 HTTP 方法、SQL 类型、Redis 命令）
+type ClientMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewClientMetrics 创建（或返回已注册的）subsystem 的客户端指标，r 为 nil 时使用 Default，buckets 为空时使用 DefBuckets；
+// 多个客户端实例重复调用时共享同一组指标，同名指标已以不同定义注册时返回错误
+func NewClientMetrics(r *Registry, subsystem string, buckets []float64) (*ClientMetrics, error) {
+	if r == nil {
+		r = Default
+	}
+	requests, err := r.NewCounterVec(subsystem+"_client_requests_total", "Total number of client calls by result.", "client", "operation", "result")
+	if err != nil {
+		return nil, err
+	}
+	duration, err := r.NewHistogramVec(subsystem+"_client_request_duration_seconds", "Client call latency in seconds.", buckets, "client", "operation")
+	if err != nil {
+		return nil, err
+	}
+	return &ClientMetrics{requests: requests, duration: duration}, nil
+}
+
+// Observe 记录一次调用
+func (m *ClientMetrics) Observe(client, operation, result string, d time.Duration) {
+	m.requests.WithLabelValues(client, operation, result).Inc()
+	m.duration.WithLabelValues(client, operation).Observe(d.Seconds())
+}
+
+// PoolStats 连接池状态，可由 sql.DBStats 或 redis.PoolStats 转换
+type PoolStats struct {
+	Open         int           // 当前连接数
+	InUse        int           // 使用中的连接数
+	Idle         int           // 空闲连接数
+	WaitCount    int64         // 累计等待连接的次数
+	WaitDuration time.Duration // 累计等待连接的时间
+	Timeouts     int64         // 累计获取连接超时的次数
+}
+
+// poolCollector 同一 subsystem 下所有连接池的状态采集，实现 prometheus.Collector
+type poolCollector struct {
+	open, inUse, idle, waits, waitSeconds, timeouts *prometheus.Desc
+
+	mu    sync.RWMutex
+	pools map[string]func() PoolStats
+}
+
+// newPoolCollector 创建 <subsystem>_pool_* 指标的采集器
+func newPoolCollector(subsystem string) *poolCollector {
+	desc := func(suffix, help string) *prometheus.Desc {
+		return prometheus.NewDesc(subsystem+"_pool_"+suffix, help, []string{"pool"}, nil)
+	}
+	return &poolCollector{
+		open:        desc("open_connections", "Number of open connections."),
+		inUse:       desc("in_use_connections", "Number of connections currently in use."),
+		idle:        desc("idle_connections", "Number of idle connections."),
+		waits:       desc("wait_total", "Total number of waits for a connection."),
+		waitSeconds: desc("wait_seconds_total", "Total time spent waiting for a connection."),
+		timeouts:    desc("timeouts_total", "Total number of connection acquisition timeouts."),
+		pools:       make(map[string]func() PoolStats),
+	}
+}
+
+// Describe 实现 prometheus.Collector
+func (p *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{p.open, p.inUse, p.idle, p.waits, p.waitSeconds, p.timeouts} {
+		ch <- d
+	}
+}
+
+// Collect 实现 prometheus.Collector，抓取时读取各连接池状态
+func (p *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	p.mu.RLock()
+	names := make([]string, 0, len(p.pools))
+	for name := range p.pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	stats := make([]PoolStats, len(names))
+	for i, name := range names {
+		stats[i] = p.pools[name]()
+	}
+	p.mu.RUnlock()
+
+	for i, name := range names {
+		s := stats[i]
+		ch <- prometheus.MustNewConstMetric(p.open, prometheus.GaugeValue, float64(s.Open), name)
+		ch <- prometheus.MustNewConstMetric(p.inUse, prometheus.GaugeValue, float64(s.InUse), name)
+		ch <- prometheus.MustNewConstMetric(p.idle, prometheus.GaugeValue, float64(s.Idle), name)
+		ch <- prometheus.MustNewConstMetric(p.waits, prometheus.CounterValue, float64(s.WaitCount), name)
+		ch <- prometheus.MustNewConstMetric(p.waitSeconds, prometheus.CounterValue, s.WaitDuration.Seconds(), name)
+		ch <- prometheus.MustNewConstMetric(p.timeouts, prometheus.CounterValue, float64(s.Timeouts), name)
+	}
+}
+
+// RegisterPoolStats 注册连接池状态，抓取时调用 fn 读取，输出 <subsystem>_pool_* 指标（pool 标签为 name）；
+// r 为 nil 时使用 Default，同名连接池重复注册时替换之前的 fn
+// 使用示例：
+//
+//	err := metrics.RegisterPoolStats(nil, "mysql", "orders", func() metrics.PoolStats {
+//		s := db.Stats()
+//		return metrics.PoolStats{Open: s.OpenConnections, InUse: s.InUse, Idle: s.Idle, WaitCount: s.WaitCount, WaitDuration: s.WaitDuration}
+//	})
+func RegisterPoolStats(r *Registry, subsystem, name string, fn func() PoolStats) error {
+	if r == nil {
+		r = Default
+	}
+	p, err := getOrRegister(r, subsystem+"_pool_open_connections", "pool", func() *poolCollector {
+		return newPoolCollector(subsystem)
+	})
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.pools[name] = fn
+	p.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,122 @@
+// Package metrics 进程级 Prometheus 指标：基于 prometheus/client_golang 的统一注册表与 /metrics 处理器（promhttp），
+// 以及 httpx/mysqlx/rediscluster 共用的客户端调用指标与连接池指标，使所有模块的指标注册到同一处。
+// Registry 内嵌 *prometheus.Registry，可直接注册任意 prometheus.Collector
+// 使用示例：
+//
+//	orders := metrics.Must(metrics.Default.NewCounterVec("app_orders_total", "Orders created.", "channel"))
+//	orders.WithLabelValues("web").Inc()
+//	mux.Handle("/metrics", metrics.Handler())
+//
+//	cli := httpx.NewClient(httpx.WithClientName("billing"), httpx.WithMetrics(nil))
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefBuckets 默认的耗时直方图分桶（秒）
+var DefBuckets = prometheus.DefBuckets
+
+// Registry 指标注册表，并发安全。除 prometheus.Registry 的能力外，记录 NewXxxVec 创建的指标，
+// 使多个模块、多个客户端实例可以重复调用同一个构造函数
+type Registry struct {
+	*prometheus.Registry
+
+	mu    sync.Mutex
+	named map[string]namedCollector // 指标名 -> NewXxxVec 创建的采集器
+}
+
+// namedCollector 已注册的采集器及其定义（类型、标签、分桶），重复注册时比较定义是否一致
+type namedCollector struct {
+	c   prometheus.Collector
+	def string
+}
+
+// NewRegistry 创建空的注册表
+func NewRegistry() *Registry {
+	return &Registry{Registry: prometheus.NewRegistry(), named: make(map[string]namedCollector)}
+}
+
+// Default 默认注册表，已注册 Go 运行时与进程指标
+var Default = func() *Registry {
+	r := NewRegistry()
+	r.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return r
+}()
+
+// Handler 输出默认注册表的处理器
+func Handler() http.Handler { return Default.Handler() }
+
+// Handler 以 Prometheus 格式输出注册表中的所有指标（promhttp），抓取时发现的重名或不一致的指标作为错误返回
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r, promhttp.HandlerOpts{})
+}
+
+// Must 构造函数返回错误时 panic，用于启动阶段
+//
+//	reqs := metrics.Must(metrics.Default.NewCounterVec("app_requests_total", "Requests.", "path"))
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// getOrRegister 指标名已由 NewXxxVec 注册且定义一致时返回已有的采集器，定义（类型、帮助、标签、分桶）不一致时返回错误；
+// 未注册时注册 create 的结果，与 Register 注册的其它采集器冲突时同样返回错误
+func getOrRegister[T prometheus.Collector](r *Registry, name, def string, create func() T) (T, error) {
+	var zero T
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.named[name]; ok {
+		c, same := existing.c.(T)
+		if !same || existing.def != def {
+			return zero, fmt.Errorf("metrics: metric %s already registered with a different definition", name)
+		}
+		return c, nil
+	}
+	c := create()
+	if err := r.Register(c); err != nil {
+		return zero, fmt.Errorf("metrics: register %s: %w", name, err)
+	}
+	r.named[name] = namedCollector{c: c, def: def}
+	return c, nil
+}
+
+// definition 拼接指标定义，用于判断重复注册是否一致
+func definition(typ, help string, labels []string, buckets []float64) string {
+	return fmt.Sprintf("%s|%s|%s|%v", typ, help, strings.Join(labels, ","), buckets)
+}
+
+// NewCounterVec 创建（或返回已注册的同定义）计数器
+func (r *Registry) NewCounterVec(name, help string, labels ...string) (*prometheus.CounterVec, error) {
+	return getOrRegister(r, name, definition("counter", help, labels, nil), func() *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+	})
+}
+
+// NewGaugeVec 创建（或返回已注册的同定义）仪表盘
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) (*prometheus.GaugeVec, error) {
+	return getOrRegister(r, name, definition("gauge", help, labels, nil), func() *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labels)
+	})
+}
+
+// NewHistogramVec 创建（或返回已注册的同定义）直方图，buckets 为空时使用 DefBuckets
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) (*prometheus.HistogramVec, error) {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	buckets = slices.Clone(buckets)
+	return getOrRegister(r, name, definition("histogram", help, labels, buckets), func() *prometheus.HistogramVec {
+		return prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels)
+	})
+}
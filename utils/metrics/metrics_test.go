@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// scrape 抓取注册表的文本输出
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestRegistry_Exposition(t *testing.T) {
+	r := NewRegistry()
+	c := Must(r.NewCounterVec("app_orders_total", "Orders created.", "channel"))
+	c.WithLabelValues("web").Add(2)
+	c.WithLabelValues(`a"b`).Inc()
+	Must(r.NewGaugeVec("app_queue_depth", "Queue depth.")).WithLabelValues().Set(7)
+	h := Must(r.NewHistogramVec("app_latency_seconds", "Latency.", []float64{0.1, 1}))
+	h.WithLabelValues().Observe(0.05)
+	h.WithLabelValues().Observe(0.5)
+	h.WithLabelValues().Observe(3)
+
+	out := scrape(t, r)
+	for _, want := range []string{
+		"# HELP app_orders_total Orders created.\n# TYPE app_orders_total counter\n",
+		`app_orders_total{channel="a\"b"} 1`,
+		`app_orders_total{channel="web"} 2`,
+		"app_queue_depth 7\n",
+		`app_latency_seconds_bucket{le="0.1"} 1`,
+		`app_latency_seconds_bucket{le="1"} 2`,
+		`app_latency_seconds_bucket{le="+Inf"} 3`,
+		"app_latency_seconds_sum 3.55\n",
+		"app_latency_seconds_count 3\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRegistry_GetOrRegister(t *testing.T) {
+	r := NewRegistry()
+	a := Must(r.NewCounterVec("x_total", "x", "l"))
+	if b := Must(r.NewCounterVec("x_total", "x", "l")); a != b {
+		t.Error("same definition should return the registered collector")
+	}
+	if _, err := r.NewGaugeVec("x_total", "x", "l"); err == nil {
+		t.Error("expected error on type conflict")
+	}
+	if _, err := r.NewCounterVec("x_total", "x", "other"); err == nil {
+		t.Error("expected error on label conflict")
+	}
+	Must(r.NewHistogramVec("h_seconds", "h", []float64{1, 2}))
+	if _, err := r.NewHistogramVec("h_seconds", "h", []float64{1, 5}); err == nil {
+		t.Error("expected error on bucket conflict")
+	}
+}
+
+func TestRegistry_Collector(t *testing.T) {
+	r := NewRegistry()
+	g := prometheus.NewGauge(prometheus.GaugeOpts{Name: "custom_value", Help: "Custom."})
+	g.Set(3)
+	if err := r.Register(g); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(prometheus.NewGauge(prometheus.GaugeOpts{Name: "custom_value", Help: "Custom."})); err == nil {
+		t.Error("expected duplicate error")
+	}
+	if _, err := r.NewCounterVec("custom_value", "Custom."); err == nil {
+		t.Error("expected conflict with a collector registered directly")
+	}
+	if out := scrape(t, r); !strings.Contains(out, "custom_value 3\n") {
+		t.Errorf("collector not exposed:\n%s", out)
+	}
+	if !strings.Contains(scrape(t, Default), "go_goroutines ") {
+		t.Error("default registry should include runtime metrics")
+	}
+}
+
+func TestClientMetrics(t *testing.T) {
+	r := NewRegistry()
+	m := Must(NewClientMetrics(r, "http", nil))
+	m.Observe("billing", "GET", ResultOK, 20*time.Millisecond)
+	Must(NewClientMetrics(r, "http", nil)).Observe("billing", "GET", ResultError, time.Second)
+	if _, err := NewClientMetrics(r, "http", []float64{1}); err == nil {
+		t.Error("expected error for different buckets")
+	}
+
+	if err := RegisterPoolStats(r, "mysql", "orders", func() PoolStats { return PoolStats{Open: 5, InUse: 2, Idle: 3} }); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterPoolStats(r, "mysql", "users", func() PoolStats { return PoolStats{Open: 1} }); err != nil {
+		t.Fatal(err)
+	}
+
+	out := scrape(t, r)
+	for _, want := range []string{
+		`http_client_requests_total{client="billing",operation="GET",result="error"} 1`,
+		`http_client_requests_total{client="billing",operation="GET",result="ok"} 1`,
+		`http_client_request_duration_seconds_count{client="billing",operation="GET"} 2`,
+		`mysql_pool_in_use_connections{pool="orders"} 2`,
+		`mysql_pool_open_connections{pool="users"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestResult(t *testing.T) {
+	if Result(nil) != ResultOK || Result(io.EOF) != ResultError {
+		t.Error("unexpected result for nil/EOF")
+	}
+	if Result(fmt.Errorf("wrap: %w", context.DeadlineExceeded)) != ResultTimeout {
+		t.Error("deadline exceeded should be timeout")
+	}
+}
@@ -0,0 +1,174 @@
+// Package netx 机器与网络信息：本机 IP、出口 IP、空闲端口、容器环境检测与 CIDR 匹配
+package netx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// ErrNoIP 未找到可用的 IP 地址
+var ErrNoIP = errors.New("netx: no usable ip address")
+
+// LocalIP 返回本机第一个非回环、已启用网卡上的 IPv4 地址，优先返回内网地址
+func LocalIP() (net.IP, error) {
+	ips, err := LocalIPs()
+	if err != nil {
+		return nil, err
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil && ip.IsPrivate() {
+			return ip, nil
+		}
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip, nil
+		}
+	}
+	return nil, ErrNoIP
+}
+
+// LocalIPs 返回本机所有已启用网卡上的非回环、非链路本地地址（IPv4 与 IPv6）
+func LocalIPs() ([]net.IP, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var out []net.IP
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			ipNet, ok := a.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip := ipNet.IP
+			if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+				continue
+			}
+			out = append(out, ip)
+		}
+	}
+	if len(out) == 0 {
+		return nil, ErrNoIP
+	}
+	return out, nil
+}
+
+// OutboundIP 返回访问外网时使用的源地址（通过 UDP "连接" 路由选择，不会真正发送数据）
+// target 为空时使用 8.8.8.8:53
+func OutboundIP(target string) (net.IP, error) {
+	if target == "" {
+		target = "8.8.8.8:53"
+	}
+	conn, err := net.Dial("udp", target)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// FreePort 向系统申请一个当前空闲的 TCP 端口
+// 注意：端口在返回后才会被使用，期间可能被其他进程占用，仅适合测试或端口探测
+func FreePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = l.Close() }()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// InContainer 检测当前进程是否运行在容器（Docker、containerd、Kubernetes 等）中
+func InContainer() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return true
+	}
+	for _, f := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(f); err == nil {
+			return true
+		}
+	}
+	data, err := os.ReadFile("/proc/1/cgroup")
+	if err != nil {
+		return false
+	}
+	for _, marker := range []string{"docker", "kubepods", "containerd", "lxc"} {
+		if bytes.Contains(data, []byte(marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CIDRSet 一组网段，用于 IP 白名单/内网判断
+type CIDRSet struct {
+	nets []*net.IPNet
+}
+
+// ParseCIDRs 解析网段列表，单个 IP（不带掩码）按 /32 或 /128 处理
+func ParseCIDRs(cidrs ...string) (*CIDRSet, error) {
+	s := &CIDRSet{}
+	for _, c := range cidrs {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if !strings.Contains(c, "/") {
+			ip := net.ParseIP(c)
+			if ip == nil {
+				return nil, fmt.Errorf("netx: invalid ip %q", c)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				bits = 32
+			}
+			s.nets = append(s.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("netx: invalid cidr %q: %w", c, err)
+		}
+		s.nets = append(s.nets, n)
+	}
+	return s, nil
+}
+
+// Contains 判断 ip 是否属于任一网段
+func (s *CIDRSet) Contains(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range s.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsString 同 Contains，ip 为字符串形式（可带端口，如 RemoteAddr）
+func (s *CIDRSet) ContainsString(ip string) bool {
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return s.Contains(net.ParseIP(ip))
+}
+
+// IsPrivateIP 判断是否为内网地址（RFC 1918 / RFC 4193）或回环地址
+func IsPrivateIP(ip string) bool {
+	p := net.ParseIP(ip)
+	return p != nil && (p.IsPrivate() || p.IsLoopback())
+}
@@ -0,0 +1,43 @@
+package netx
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCIDRSet(t *testing.T) {
+	s, err := ParseCIDRs("10.0.0.0/8", "192.168.1.10", " ", "fd00::/8")
+	assert.NoError(t, err)
+	assert.True(t, s.ContainsString("10.1.2.3"))
+	assert.True(t, s.ContainsString("192.168.1.10:8080"))
+	assert.False(t, s.ContainsString("192.168.1.11"))
+	assert.True(t, s.ContainsString("[fd00::1]:443"))
+	assert.False(t, s.ContainsString("not-an-ip"))
+
+	_, err = ParseCIDRs("10.0.0.0/33")
+	assert.Error(t, err)
+
+	assert.True(t, IsPrivateIP("172.16.0.1"))
+	assert.False(t, IsPrivateIP("8.8.8.8"))
+}
+
+func TestFreePort(t *testing.T) {
+	port, err := FreePort()
+	assert.NoError(t, err)
+	l, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port))
+	assert.NoError(t, err)
+	_ = l.Close()
+}
+
+func TestLocalIP(t *testing.T) {
+	ip, err := LocalIP()
+	if err == ErrNoIP {
+		t.Skip("no non-loopback interface")
+	}
+	assert.NoError(t, err)
+	assert.False(t, ip.IsLoopback())
+	t.Logf("local ip: %s, in container: %v", ip, InContainer())
+}
@@ -0,0 +1,243 @@
+// Package notify 短信与群机器人通知：统一的 Provider 接口，内置阿里云/腾讯云短信与飞书/钉钉/企业微信机器人，
+// 支持模板渲染、按接收方限流与多通道失败降级
+//
+//	sms := notify.RateLimited(
+//		notify.Fallback(notify.NewAliyunSMS(aliyunCfg), notify.NewTencentSMS(tencentCfg)),
+//		notify.NewMemoryLimiter(1, time.Minute),
+//	)
+//	err := sms.Send(ctx, &notify.Message{To: []string{"13800138000"}, Template: "SMS_1234", Params: map[string]string{"code": "8888"}})
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/utils/templatex"
+)
+
+var (
+	// ErrRateLimited 接收方超过发送频率限制
+	ErrRateLimited = errors.New("notify: rate limited")
+	// ErrNoRecipients 短信未指定手机号
+	ErrNoRecipients = errors.New("notify: missing recipients")
+	// ErrNoProviders Fallback 未配置任何通道
+	ErrNoProviders = errors.New("notify: no providers")
+)
+
+// Message 通知内容，不同通道使用其中不同的字段
+type Message struct {
+	To       []string          // 短信为手机号；群机器人为需要 @ 的成员手机号，可为空
+	Template string            // 短信模板编号（阿里云 TemplateCode / 腾讯云 TemplateId）
+	Params   map[string]string // 按名称的模板参数（阿里云）
+	Args     []string          // 按位置的模板参数（腾讯云）
+	Title    string            // 群机器人卡片标题，为空时发送纯文本
+	Content  string            // 群机器人正文，设置 Title 时按 markdown 渲染
+}
+
+// Render 使用模板渲染 Content，通常配合 templatex.WithMode(templatex.Text) 创建的引擎
+func (m *Message) Render(e *templatex.Engine, name string, data interface{}) error {
+	s, err := e.Render(name, data)
+	if err != nil {
+		return err
+	}
+	m.Content = s
+	return nil
+}
+
+// Provider 通知通道
+type Provider interface {
+	// Name 通道名，用于错误信息与限流 key
+	Name() string
+	// Send 发送通知
+	Send(ctx context.Context, m *Message) error
+}
+
+// ProviderError 通道返回的业务错误
+type ProviderError struct {
+	Provider string
+	Code     string
+	Message  string
+}
+
+// Error 实现 error 接口
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("notify: %s: %s: %s", e.Provider, e.Code, e.Message)
+}
+
+// PartialError 多个接收方中部分发送失败，Failed 为失败的接收方（与 Message.To 中的写法一致），
+// Fallback 只向这些接收方重试，避免成功的接收方重复收到短信
+type PartialError struct {
+	Failed []string
+	Err    error
+}
+
+// Error 实现 error 接口
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("%v (failed recipients: %s)", e.Err, strings.Join(e.Failed, ","))
+}
+
+// Unwrap 返回各接收方的错误
+func (e *PartialError) Unwrap() error { return e.Err }
+
+// fallbackProvider 依次尝试多个通道
+type fallbackProvider struct {
+	providers []Provider
+}
+
+// Fallback 组合多个通道：按顺序发送，前一个失败时尝试下一个，全部失败时返回合并后的错误；
+// 前一个通道返回 PartialError 时只向其中失败的接收方重试；ctx 结束时不再尝试后续通道
+func Fallback(providers ...Provider) Provider {
+	return &fallbackProvider{providers: providers}
+}
+
+// Name 实现 Provider
+func (f *fallbackProvider) Name() string {
+	names := make([]string, len(f.providers))
+	for i, p := range f.providers {
+		names[i] = p.Name()
+	}
+	return "fallback(" + strings.Join(names, ",") + ")"
+}
+
+// Send 实现 Provider
+func (f *fallbackProvider) Send(ctx context.Context, m *Message) error {
+	if len(f.providers) == 0 {
+		return ErrNoProviders
+	}
+	var errs []error
+	for _, p := range f.providers {
+		err := p.Send(ctx, m)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+		var pe *PartialError
+		if errors.As(err, &pe) && len(pe.Failed) > 0 {
+			retry := *m
+			retry.To = pe.Failed
+			m = &retry
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Limiter 限流器，返回 false 表示该 key 超出频率限制；可基于 Redis 实现以在多实例间共享
+type Limiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
+
+// BatchLimiter 可选接口：先检查全部 key，都未超限时才各计数一次，返回第一个超限的 key（都未超限时为空）；
+// 多接收方的消息由 RateLimited 使用它保证被拒绝的消息不消耗其它接收方的额度
+type BatchLimiter interface {
+	AllowAll(ctx context.Context, keys []string) (string, error)
+}
+
+// limitedProvider 按接收方限流的通道
+type limitedProvider struct {
+	Provider
+	limiter Limiter
+}
+
+// RateLimited 为通道增加按接收方的限流：每个手机号各自计数，key 为 通道名:手机号；
+// 没有接收方时（群机器人）以通道名计数。任一接收方超限时整条消息返回 ErrRateLimited，
+// 限流器实现 BatchLimiter（如 MemoryLimiter）时此时不消耗任何接收方的额度，否则前面的接收方已被计数
+func RateLimited(p Provider, l Limiter) Provider {
+	return &limitedProvider{Provider: p, limiter: l}
+}
+
+// Send 实现 Provider
+func (l *limitedProvider) Send(ctx context.Context, m *Message) error {
+	keys := []string{l.Name()}
+	if len(m.To) > 0 {
+		keys = keys[:0]
+		for _, to := range m.To {
+			keys = append(keys, l.Name()+":"+to)
+		}
+	}
+	if bl, ok := l.limiter.(BatchLimiter); ok {
+		rejected, err := bl.AllowAll(ctx, keys)
+		if err != nil {
+			return err
+		}
+		if rejected != "" {
+			return fmt.Errorf("%w: %s", ErrRateLimited, rejected)
+		}
+		return l.Provider.Send(ctx, m)
+	}
+	for _, key := range keys {
+		ok, err := l.limiter.Allow(ctx, key)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrRateLimited, key)
+		}
+	}
+	return l.Provider.Send(ctx, m)
+}
+
+// memoryWindow 固定窗口计数
+type memoryWindow struct {
+	start time.Time
+	count int
+}
+
+// MemoryLimiter 进程内固定窗口限流器
+type MemoryLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	windows map[string]*memoryWindow
+}
+
+// NewMemoryLimiter 创建限流器：每个 key 在 window 内最多允许 limit 次
+func NewMemoryLimiter(limit int, window time.Duration) *MemoryLimiter {
+	return &MemoryLimiter{limit: limit, window: window, windows: make(map[string]*memoryWindow)}
+}
+
+// maxLimiterKeys 触发过期清理的 key 数量
+const maxLimiterKeys = 10000
+
+// Allow 实现 Limiter
+func (l *MemoryLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	rejected, err := l.AllowAll(ctx, []string{key})
+	return rejected == "", err
+}
+
+// AllowAll 实现 BatchLimiter
+func (l *MemoryLimiter) AllowAll(_ context.Context, keys []string) (string, error) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.windows) >= maxLimiterKeys {
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+	}
+	windows := make([]*memoryWindow, len(keys))
+	for i, key := range keys {
+		w, ok := l.windows[key]
+		if !ok || now.Sub(w.start) >= l.window {
+			w = &memoryWindow{start: now}
+			l.windows[key] = w
+		}
+		if w.count >= l.limit {
+			return key, nil
+		}
+		windows[i] = w
+	}
+	for _, w := range windows {
+		w.count++
+	}
+	return "", nil
+}
@@ -0,0 +1,188 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qingfeng-studio/go-utils/utils/templatex"
+)
+
+func TestAliyunSMS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		params := make(map[string]string)
+		for k := range q {
+			if k != "Signature" {
+				params[k] = q.Get(k)
+			}
+		}
+		if aliyunSign(http.MethodGet, params, "secret") != q.Get("Signature") {
+			w.Write([]byte(`{"Code":"SignatureDoesNotMatch","Message":"bad signature"}`))
+			return
+		}
+		if q.Get("PhoneNumbers") == "13900000000" {
+			w.Write([]byte(`{"Code":"isv.BUSINESS_LIMIT_CONTROL","Message":"limited"}`))
+			return
+		}
+		assert.Equal(t, `{"code":"8888"}`, q.Get("TemplateParam"))
+		w.Write([]byte(`{"Code":"OK","Message":"OK"}`))
+	}))
+	defer srv.Close()
+
+	p := NewAliyunSMS(AliyunSMSConfig{AccessKeyID: "id", AccessKeySecret: "secret", SignName: "青枫", Endpoint: srv.URL})
+	msg := &Message{To: []string{"13800138000"}, Template: "SMS_1", Params: map[string]string{"code": "8888"}}
+	require.NoError(t, p.Send(context.Background(), msg))
+
+	err := p.Send(context.Background(), &Message{To: []string{"13900000000"}, Template: "SMS_1"})
+	var pe *ProviderError
+	require.True(t, errors.As(err, &pe))
+	assert.Equal(t, "isv.BUSINESS_LIMIT_CONTROL", pe.Code)
+	assert.ErrorIs(t, p.Send(context.Background(), &Message{}), ErrNoRecipients)
+}
+
+func TestTencentSMS(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "SendSms", r.Header.Get("X-TC-Action"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "TC3-HMAC-SHA256 Credential=sid/"))
+		b, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(b, &body))
+		w.Write([]byte(`{"Response":{"SendStatusSet":[{"Code":"Ok","PhoneNumber":"+8613800138000"}],"RequestId":"x"}}`))
+	}))
+	defer srv.Close()
+
+	p := NewTencentSMS(TencentSMSConfig{SecretID: "sid", SecretKey: "skey", SdkAppID: "1400", SignName: "青枫", Endpoint: srv.URL})
+	require.NoError(t, p.Send(context.Background(), &Message{To: []string{"13800138000"}, Template: "100", Args: []string{"8888", "5"}}))
+	assert.Equal(t, []interface{}{"+8613800138000"}, body["PhoneNumberSet"])
+	assert.Equal(t, []interface{}{"8888", "5"}, body["TemplateParamSet"])
+}
+
+func TestWebhooks(t *testing.T) {
+	var got map[string]interface{}
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		b, _ := io.ReadAll(r.Body)
+		got = nil
+		require.NoError(t, json.Unmarshal(b, &got))
+		switch r.URL.Path {
+		case "/feishu":
+			w.Write([]byte(`{"code":0,"msg":"success"}`))
+		case "/dingtalk":
+			w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		default:
+			w.Write([]byte(`{"errcode":93000,"errmsg":"invalid webhook url"}`))
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	feishu := NewFeishu(WebhookConfig{URL: srv.URL + "/feishu", Secret: "s"})
+	require.NoError(t, feishu.Send(ctx, &Message{Title: "告警", Content: "**CPU** 90%"}))
+	assert.Equal(t, "interactive", got["msg_type"])
+	assert.NotEmpty(t, got["sign"])
+
+	ding := NewDingTalk(WebhookConfig{URL: srv.URL + "/dingtalk", Secret: "s"})
+	require.NoError(t, ding.Send(ctx, &Message{To: []string{"13800138000"}, Title: "告警", Content: "磁盘已满"}))
+	assert.Contains(t, query, "sign=")
+	assert.Equal(t, "磁盘已满 @13800138000", got["markdown"].(map[string]interface{})["text"])
+
+	wecom := NewWeCom(WebhookConfig{URL: srv.URL + "/wecom"})
+	err := wecom.Send(ctx, &Message{Content: "hi"})
+	var pe *ProviderError
+	require.True(t, errors.As(err, &pe))
+	assert.Equal(t, "93000", pe.Code)
+}
+
+// stubProvider 测试用通道
+type stubProvider struct {
+	name  string
+	err   error
+	calls int
+	to    [][]string
+}
+
+func (s *stubProvider) Name() string { return s.name }
+
+func (s *stubProvider) Send(_ context.Context, m *Message) error {
+	s.calls++
+	s.to = append(s.to, m.To)
+	return s.err
+}
+
+func TestFallbackAndRateLimit(t *testing.T) {
+	ctx := context.Background()
+	primary := &stubProvider{name: "a", err: errors.New("down")}
+	backup := &stubProvider{name: "b"}
+	p := RateLimited(Fallback(primary, backup), NewMemoryLimiter(1, time.Minute))
+	assert.Equal(t, "fallback(a,b)", p.Name())
+
+	msg := &Message{To: []string{"13800138000"}}
+	require.NoError(t, p.Send(ctx, msg))
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 1, backup.calls)
+
+	assert.ErrorIs(t, p.Send(ctx, msg), ErrRateLimited)
+	require.NoError(t, p.Send(ctx, &Message{To: []string{"13900000000"}}))
+
+	backup.err = errors.New("also down")
+	err := Fallback(primary, backup).Send(ctx, msg)
+	assert.ErrorContains(t, err, "down")
+	assert.ErrorContains(t, err, "also down")
+	assert.ErrorIs(t, Fallback().Send(ctx, msg), ErrNoProviders)
+}
+
+// TestFallback_PartialFailure 腾讯云部分号码失败时，备用通道只发送失败的号码
+func TestFallback_PartialFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Response":{"SendStatusSet":[` +
+			`{"Code":"Ok","PhoneNumber":"+8613800138000"},` +
+			`{"Code":"LimitExceeded.PhoneNumberDailyLimit","Message":"limited","PhoneNumber":"+8613900139000"}]}}`))
+	}))
+	defer srv.Close()
+
+	tencent := NewTencentSMS(TencentSMSConfig{SecretID: "sid", SecretKey: "skey", SdkAppID: "1400", Endpoint: srv.URL})
+	msg := &Message{To: []string{"13800138000", "13900139000"}, Template: "100"}
+	err := tencent.Send(context.Background(), msg)
+	var partial *PartialError
+	require.ErrorAs(t, err, &partial)
+	assert.Equal(t, []string{"13900139000"}, partial.Failed)
+	var pe *ProviderError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "LimitExceeded.PhoneNumberDailyLimit", pe.Code)
+
+	backup := &stubProvider{name: "b"}
+	require.NoError(t, Fallback(tencent, backup).Send(context.Background(), msg))
+	assert.Equal(t, [][]string{{"13900139000"}}, backup.to)
+	assert.Equal(t, []string{"13800138000", "13900139000"}, msg.To, "caller's message must not be modified")
+}
+
+// TestRateLimited_AllOrNothing 任一接收方超限时不消耗其它接收方的额度
+func TestRateLimited_AllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubProvider{name: "s"}
+	p := RateLimited(stub, NewMemoryLimiter(1, time.Minute))
+
+	require.NoError(t, p.Send(ctx, &Message{To: []string{"2"}}))
+	assert.ErrorIs(t, p.Send(ctx, &Message{To: []string{"1", "2"}}), ErrRateLimited)
+	require.NoError(t, p.Send(ctx, &Message{To: []string{"1"}}), "1 must not be counted by the rejected message")
+	assert.Equal(t, 2, stub.calls)
+}
+
+func TestRender(t *testing.T) {
+	e := templatex.New(templatex.WithMode(templatex.Text))
+	require.NoError(t, e.Parse("alert", `{{.Service}} 错误率 {{.Rate | number 1}}%`))
+	m := &Message{Title: "告警"}
+	require.NoError(t, m.Render(e, "alert", map[string]interface{}{"Service": "<order>", "Rate": 12.34}))
+	assert.Equal(t, "<order> 错误率 12.3%", m.Content)
+}
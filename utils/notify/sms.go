@@ -0,0 +1,304 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/httpx"
+)
+
+// defaultTimeout 通道请求默认超时
+const defaultTimeout = 5 * time.Second
+
+// newClient 按配置返回 HTTP 客户端
+func newClient(c *httpx.Client, timeout time.Duration) *httpx.Client {
+	if c != nil {
+		return c
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return httpx.NewClient(httpx.WithTimeout(timeout))
+}
+
+// AliyunSMSConfig 阿里云短信配置
+type AliyunSMSConfig struct {
+	AccessKeyID     string
+	AccessKeySecret string
+	SignName        string        // 短信签名
+	Endpoint        string        // 默认 https://dysmsapi.aliyuncs.com/
+	RegionID        string        // 默认 cn-hangzhou
+	Timeout         time.Duration // 默认 5 秒
+	Client          *httpx.Client // 自定义 HTTP 客户端，为空时按 Timeout 创建
+}
+
+// AliyunSMS 阿里云短信通道（SendSms，RPC 签名 v1）
+type AliyunSMS struct {
+	cfg    AliyunSMSConfig
+	client *httpx.Client
+}
+
+// NewAliyunSMS 创建阿里云短信通道
+func NewAliyunSMS(cfg AliyunSMSConfig) *AliyunSMS {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://dysmsapi.aliyuncs.com/"
+	}
+	if cfg.RegionID == "" {
+		cfg.RegionID = "cn-hangzhou"
+	}
+	return &AliyunSMS{cfg: cfg, client: newClient(cfg.Client, cfg.Timeout)}
+}
+
+// Name 实现 Provider
+func (a *AliyunSMS) Name() string { return "aliyun" }
+
+// Send 实现 Provider，多个手机号在一次请求中发送
+func (a *AliyunSMS) Send(ctx context.Context, m *Message) error {
+	if len(m.To) == 0 {
+		return ErrNoRecipients
+	}
+	params := map[string]string{
+		"AccessKeyId":      a.cfg.AccessKeyID,
+		"Action":           "SendSms",
+		"Format":           "JSON",
+		"PhoneNumbers":     strings.Join(m.To, ","),
+		"RegionId":         a.cfg.RegionID,
+		"SignName":         a.cfg.SignName,
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureNonce":   nonce(),
+		"SignatureVersion": "1.0",
+		"TemplateCode":     m.Template,
+		"Timestamp":        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		"Version":          "2017-05-25",
+	}
+	if len(m.Params) > 0 {
+		b, err := json.Marshal(m.Params)
+		if err != nil {
+			return err
+		}
+		params["TemplateParam"] = string(b)
+	}
+	params["Signature"] = aliyunSign(http.MethodGet, params, a.cfg.AccessKeySecret)
+
+	_, body, err := a.client.Get(ctx, a.cfg.Endpoint, params, nil)
+	if err != nil && body == nil {
+		return fmt.Errorf("notify: aliyun: %w", err)
+	}
+	var resp struct {
+		Code    string `json:"Code"`
+		Message string `json:"Message"`
+	}
+	if jerr := json.Unmarshal(body, &resp); jerr != nil {
+		if err != nil {
+			return fmt.Errorf("notify: aliyun: %w", err)
+		}
+		return fmt.Errorf("notify: aliyun: decode response: %w", jerr)
+	}
+	if resp.Code != "OK" {
+		return &ProviderError{Provider: a.Name(), Code: resp.Code, Message: resp.Message}
+	}
+	return nil
+}
+
+// aliyunSign 计算阿里云 RPC 风格签名：HMAC-SHA1(secret&, METHOD&%2F&encode(canonicalQuery))
+func aliyunSign(method string, params map[string]string, secret string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = aliyunEncode(k) + "=" + aliyunEncode(params[k])
+	}
+	stringToSign := method + "&" + aliyunEncode("/") + "&" + aliyunEncode(strings.Join(pairs, "&"))
+	mac := hmac.New(sha1.New, []byte(secret+"&"))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// aliyunEncode 阿里云要求的 RFC 3986 百分号编码
+func aliyunEncode(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.ReplaceAll(s, "+", "%20")
+	s = strings.ReplaceAll(s, "*", "%2A")
+	return strings.ReplaceAll(s, "%7E", "~")
+}
+
+// TencentSMSConfig 腾讯云短信配置
+type TencentSMSConfig struct {
+	SecretID  string
+	SecretKey string
+	SdkAppID  string        // 短信应用 SdkAppId
+	SignName  string        // 短信签名
+	Region    string        // 默认 ap-guangzhou
+	Endpoint  string        // 默认 https://sms.tencentcloudapi.com
+	Timeout   time.Duration // 默认 5 秒
+	Client    *httpx.Client // 自定义 HTTP 客户端，为空时按 Timeout 创建
+}
+
+// TencentSMS 腾讯云短信通道（SendSms 2021-01-11，TC3-HMAC-SHA256 签名），模板参数使用 Message.Args
+type TencentSMS struct {
+	cfg    TencentSMSConfig
+	host   string
+	client *httpx.Client
+}
+
+// NewTencentSMS 创建腾讯云短信通道
+func NewTencentSMS(cfg TencentSMSConfig) *TencentSMS {
+	if cfg.Region == "" {
+		cfg.Region = "ap-guangzhou"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://sms.tencentcloudapi.com"
+	}
+	host := cfg.Endpoint
+	if u, err := url.Parse(cfg.Endpoint); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return &TencentSMS{cfg: cfg, host: host, client: newClient(cfg.Client, cfg.Timeout)}
+}
+
+// Name 实现 Provider
+func (t *TencentSMS) Name() string { return "tencent" }
+
+// Send 实现 Provider，未带国家码的手机号默认按 +86 处理
+func (t *TencentSMS) Send(ctx context.Context, m *Message) error {
+	if len(m.To) == 0 {
+		return ErrNoRecipients
+	}
+	phones := make([]string, len(m.To))
+	for i, to := range m.To {
+		if !strings.HasPrefix(to, "+") {
+			to = "+86" + to
+		}
+		phones[i] = to
+	}
+	args := m.Args
+	if args == nil {
+		args = []string{}
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"PhoneNumberSet":   phones,
+		"SmsSdkAppId":      t.cfg.SdkAppID,
+		"SignName":         t.cfg.SignName,
+		"TemplateId":       m.Template,
+		"TemplateParamSet": args,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	headers := http.Header{
+		"Authorization":  {t.authorization(payload, now)},
+		"X-TC-Action":    {"SendSms"},
+		"X-TC-Version":   {"2021-01-11"},
+		"X-TC-Timestamp": {strconv.FormatInt(now.Unix(), 10)},
+		"X-TC-Region":    {t.cfg.Region},
+	}
+	_, body, err := t.client.Post(ctx, t.cfg.Endpoint, payload, tencentContentType, headers, nil)
+	if err != nil && body == nil {
+		return fmt.Errorf("notify: tencent: %w", err)
+	}
+	var resp struct {
+		Response struct {
+			Error *struct {
+				Code    string `json:"Code"`
+				Message string `json:"Message"`
+			} `json:"Error"`
+			SendStatusSet []struct {
+				Code        string `json:"Code"`
+				Message     string `json:"Message"`
+				PhoneNumber string `json:"PhoneNumber"`
+			} `json:"SendStatusSet"`
+		} `json:"Response"`
+	}
+	if jerr := json.Unmarshal(body, &resp); jerr != nil {
+		if err != nil {
+			return fmt.Errorf("notify: tencent: %w", err)
+		}
+		return fmt.Errorf("notify: tencent: decode response: %w", jerr)
+	}
+	if e := resp.Response.Error; e != nil {
+		return &ProviderError{Provider: t.Name(), Code: e.Code, Message: e.Message}
+	}
+	// 按号码返回发送结果，部分失败时返回 PartialError，Fallback 只向失败的号码重试
+	original := make(map[string]string, len(phones))
+	for i, phone := range phones {
+		original[phone] = m.To[i]
+	}
+	var (
+		failed []string
+		errs   []error
+	)
+	for _, s := range resp.Response.SendStatusSet {
+		if s.Code != "Ok" {
+			to, ok := original[s.PhoneNumber]
+			if !ok {
+				to = s.PhoneNumber
+			}
+			failed = append(failed, to)
+			errs = append(errs, &ProviderError{Provider: t.Name(), Code: s.Code, Message: s.PhoneNumber + " " + s.Message})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	if len(failed) == len(phones) {
+		if len(errs) == 1 {
+			return errs[0]
+		}
+		return errors.Join(errs...)
+	}
+	return &PartialError{Failed: failed, Err: errors.Join(errs...)}
+}
+
+// tencentContentType 签名与请求使用的 Content-Type，二者必须一致
+const tencentContentType = "application/json; charset=utf-8"
+
+// authorization 计算 TC3-HMAC-SHA256 Authorization 头
+func (t *TencentSMS) authorization(payload []byte, now time.Time) string {
+	date := now.UTC().Format("2006-01-02")
+	canonical := "POST\n/\n\ncontent-type:" + tencentContentType + "\nhost:" + t.host + "\n\ncontent-type;host\n" + sha256Hex(payload)
+	scope := date + "/sms/tc3_request"
+	stringToSign := "TC3-HMAC-SHA256\n" + strconv.FormatInt(now.Unix(), 10) + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := hmacSHA256([]byte("TC3"+t.cfg.SecretKey), date)
+	key = hmacSHA256(key, "sms")
+	key = hmacSHA256(key, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	return "TC3-HMAC-SHA256 Credential=" + t.cfg.SecretID + "/" + scope + ", SignedHeaders=content-type;host, Signature=" + signature
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, msg string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(msg))
+	return mac.Sum(nil)
+}
+
+// nonce 生成随机串，用于防重放
+func nonce() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/httpx"
+)
+
+// WebhookConfig 群机器人配置
+type WebhookConfig struct {
+	URL     string        // 机器人 Webhook 地址
+	Secret  string        // 加签密钥，未开启加签时留空
+	Timeout time.Duration // 默认 5 秒
+	Client  *httpx.Client // 自定义 HTTP 客户端，为空时按 Timeout 创建
+}
+
+// webhook 群机器人的公共部分
+type webhook struct {
+	cfg    WebhookConfig
+	client *httpx.Client
+}
+
+func newWebhook(cfg WebhookConfig) webhook {
+	return webhook{cfg: cfg, client: newClient(cfg.Client, cfg.Timeout)}
+}
+
+// post 发送 JSON 并将响应解码到 out
+func (w webhook) post(ctx context.Context, name string, query map[string]string, payload, out interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, body, err := w.client.Post(ctx, w.cfg.URL, b, "application/json", nil, query)
+	if err != nil {
+		return fmt.Errorf("notify: %s: %w", name, err)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("notify: %s: decode response: %w", name, err)
+	}
+	return nil
+}
+
+// Feishu 飞书群机器人：设置 Title 时发送消息卡片（markdown 正文），否则发送文本
+type Feishu struct{ webhook }
+
+// NewFeishu 创建飞书群机器人通道
+func NewFeishu(cfg WebhookConfig) *Feishu { return &Feishu{newWebhook(cfg)} }
+
+// Name 实现 Provider
+func (f *Feishu) Name() string { return "feishu" }
+
+// Send 实现 Provider
+func (f *Feishu) Send(ctx context.Context, m *Message) error {
+	payload := map[string]interface{}{}
+	if m.Title != "" {
+		payload["msg_type"] = "interactive"
+		payload["card"] = map[string]interface{}{
+			"header": map[string]interface{}{
+				"title":    map[string]string{"tag": "plain_text", "content": m.Title},
+				"template": "blue",
+			},
+			"elements": []interface{}{
+				map[string]string{"tag": "markdown", "content": m.Content},
+			},
+		}
+	} else {
+		payload["msg_type"] = "text"
+		payload["content"] = map[string]string{"text": m.Content}
+	}
+	if f.cfg.Secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		// 飞书以 timestamp + "\n" + secret 为密钥对空串签名
+		mac := hmac.New(sha256.New, []byte(ts+"\n"+f.cfg.Secret))
+		payload["timestamp"] = ts
+		payload["sign"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+	var resp struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := f.post(ctx, f.Name(), nil, payload, &resp); err != nil {
+		return err
+	}
+	if resp.Code != 0 {
+		return &ProviderError{Provider: f.Name(), Code: strconv.Itoa(resp.Code), Message: resp.Msg}
+	}
+	return nil
+}
+
+// DingTalk 钉钉群机器人：设置 Title 时发送 markdown，否则发送文本；Message.To 中的手机号会被 @
+type DingTalk struct{ webhook }
+
+// NewDingTalk 创建钉钉群机器人通道
+func NewDingTalk(cfg WebhookConfig) *DingTalk { return &DingTalk{newWebhook(cfg)} }
+
+// Name 实现 Provider
+func (d *DingTalk) Name() string { return "dingtalk" }
+
+// Send 实现 Provider
+func (d *DingTalk) Send(ctx context.Context, m *Message) error {
+	payload := map[string]interface{}{
+		"at": map[string]interface{}{"atMobiles": m.To},
+	}
+	if m.Title != "" {
+		// markdown 消息需要在正文中出现 @手机号 才会高亮提醒
+		text := m.Content
+		for _, to := range m.To {
+			text += " @" + to
+		}
+		payload["msgtype"] = "markdown"
+		payload["markdown"] = map[string]string{"title": m.Title, "text": text}
+	} else {
+		payload["msgtype"] = "text"
+		payload["text"] = map[string]string{"content": m.Content}
+	}
+	var query map[string]string
+	if d.cfg.Secret != "" {
+		ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		mac := hmac.New(sha256.New, []byte(d.cfg.Secret))
+		mac.Write([]byte(ts + "\n" + d.cfg.Secret))
+		query = map[string]string{"timestamp": ts, "sign": base64.StdEncoding.EncodeToString(mac.Sum(nil))}
+	}
+	var resp struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := d.post(ctx, d.Name(), query, payload, &resp); err != nil {
+		return err
+	}
+	if resp.ErrCode != 0 {
+		return &ProviderError{Provider: d.Name(), Code: strconv.Itoa(resp.ErrCode), Message: resp.ErrMsg}
+	}
+	return nil
+}
+
+// WeCom 企业微信群机器人：设置 Title 时发送 markdown（标题作为二级标题），否则发送文本；
+// 文本消息会 @ Message.To 中的手机号（markdown 消息不支持按手机号 @）
+type WeCom struct{ webhook }
+
+// NewWeCom 创建企业微信群机器人通道
+func NewWeCom(cfg WebhookConfig) *WeCom { return &WeCom{newWebhook(cfg)} }
+
+// Name 实现 Provider
+func (w *WeCom) Name() string { return "wecom" }
+
+// Send 实现 Provider
+func (w *WeCom) Send(ctx context.Context, m *Message) error {
+	var payload map[string]interface{}
+	if m.Title != "" {
+		payload = map[string]interface{}{
+			"msgtype":  "markdown",
+			"markdown": map[string]string{"content": "## " + strings.TrimSpace(m.Title) + "\n" + m.Content},
+		}
+	} else {
+		payload = map[string]interface{}{
+			"msgtype": "text",
+			"text":    map[string]interface{}{"content": m.Content, "mentioned_mobile_list": m.To},
+		}
+	}
+	var resp struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := w.post(ctx, w.Name(), nil, payload, &resp); err != nil {
+		return err
+	}
+	if resp.ErrCode != 0 {
+		return &ProviderError{Provider: w.Name(), Code: strconv.Itoa(resp.ErrCode), Message: resp.ErrMsg}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package otelx
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/qingfeng-studio/go-utils/utils/ctxx"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName 本仓库埋点使用的 instrumentation scope 前缀
+const instrumentationName = "github.com/qingfeng-studio/go-utils/"
+
+// Tracer 返回全局 TracerProvider 中 component（如 "httpx"、"mysqlx"）对应的 tracer，
+// Init 之前调用也可以，之后会自动切换到真正的实现
+func Tracer(component string) trace.Tracer {
+	return otel.Tracer(instrumentationName + component)
+}
+
+// StartClient 为一次客户端调用开启 span（SpanKindClient），供各客户端的 tracing 选项使用
+// 使用示例：
+//
+//	ctx, span := otelx.StartClient(ctx, "rediscluster", "GET", attribute.String("db.system", "redis"))
+//	err := do(ctx)
+//	otelx.End(span, err)
+func StartClient(ctx context.Context, component, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer(component).Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// End 结束 span，err 不为 nil 时记录错误并将状态设为 Error
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID 返回 ctx 中 span 的 trace id，没有有效 span 时返回空串
+func TraceID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+// WithLogTraceID 将 span 的 trace id 写入 logger 读取的 traceId，使日志与链路可以互相跳转；
+// ctx 中已有 traceId 或没有有效 span 时原样返回
+func WithLogTraceID(ctx context.Context) context.Context {
+	if ctxx.TraceID(ctx) != "" {
+		return ctx
+	}
+	if id := TraceID(ctx); id != "" {
+		return ctxx.WithTraceID(ctx, id)
+	}
+	return ctx
+}
+
+// Inject 将 ctx 中的链路信息写入请求头，供 HTTP 客户端透传
+func Inject(ctx context.Context, h http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(h))
+}
+
+// Extract 从请求头中读取上游的链路信息，返回的 ctx 可作为服务端 span 的父级
+func Extract(ctx context.Context, h http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(h))
+}
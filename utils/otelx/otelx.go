@@ -0,0 +1,168 @@
+// Package otelx 链路追踪初始化：根据 config.Tracing 配置 OTLP/HTTP 导出器、资源属性与采样器，
+// 注册为全局 TracerProvider 与 W3C 传播器，并提供 httpx、mysqlx、rediscluster 等客户端共用的埋点桥接函数，
+// 各客户端通过各自的 WithTracing 选项启用埋点
+// 使用示例：
+//
+//	shutdown, err := otelx.Init(ctx, cfg.Tracing)
+//	if err != nil {
+//		return err
+//	}
+//	defer shutdown(context.Background())
+package otelx
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/qingfeng-studio/go-utils/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ShutdownFunc 刷新尚未导出的 span 并关闭导出器，应在进程退出前调用
+type ShutdownFunc func(ctx context.Context) error
+
+// options Init 选项
+type options struct {
+	exporter sdktrace.SpanExporter
+	sync     bool
+}
+
+// Option Init 的函数式选项
+type Option func(*options)
+
+// WithExporter 使用自定义导出器替代 OTLP/HTTP，如测试中的 tracetest.InMemoryExporter
+func WithExporter(exp sdktrace.SpanExporter) Option {
+	return func(o *options) { o.exporter = exp }
+}
+
+// WithSyncExport 每个 span 结束时同步导出而非批量导出，仅用于测试与调试
+func WithSyncExport() Option {
+	return func(o *options) { o.sync = true }
+}
+
+// Init 按配置初始化全局 TracerProvider 与传播器（W3C TraceContext + Baggage）；
+// 未启用时只设置传播器，埋点函数仍可调用但不产生 span，返回的 ShutdownFunc 为空操作
+func Init(ctx context.Context, cfg config.Tracing, opts ...Option) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	exp := o.exporter
+	if exp == nil {
+		var err error
+		if exp, err = newExporter(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+	res, err := newResource(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	processor := sdktrace.NewBatchSpanProcessor(exp)
+	if o.sync {
+		processor = sdktrace.NewSimpleSpanProcessor(exp)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(NewSampler(cfg.Sampler, cfg.Ratio())),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// newExporter 创建 OTLP/HTTP 导出器，导出器延迟连接，collector 暂不可用不影响启动
+func newExporter(ctx context.Context, cfg config.Tracing) (sdktrace.SpanExporter, error) {
+	var opts []otlptracehttp.Option
+	switch {
+	case strings.Contains(cfg.Endpoint, "://"):
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	case cfg.Endpoint != "":
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		headers := make(map[string]string, len(cfg.Headers))
+		for k, v := range cfg.Headers {
+			headers[k] = v.Reveal()
+		}
+		opts = append(opts, otlptracehttp.WithHeaders(headers))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, otlptracehttp.WithTimeout(cfg.Timeout))
+	}
+	tlsCfg, err := cfg.TLS.Build()
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsCfg))
+	}
+	exp, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("otelx: create otlp exporter: %w", err)
+	}
+	return exp, nil
+}
+
+// newResource 资源属性：配置项优先，其次为 OTEL_RESOURCE_ATTRIBUTES 等环境变量与 SDK 默认值
+func newResource(ctx context.Context, cfg config.Tracing) (*resource.Resource, error) {
+	var attrs []attribute.KeyValue
+	for k, v := range cfg.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	if cfg.ServiceName != "" {
+		attrs = append(attrs, attribute.String("service.name", cfg.ServiceName))
+	}
+	if cfg.ServiceVersion != "" {
+		attrs = append(attrs, attribute.String("service.version", cfg.ServiceVersion))
+	}
+	if cfg.Environment != "" {
+		attrs = append(attrs, attribute.String("deployment.environment", cfg.Environment))
+	}
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+		resource.WithHost(),
+		resource.WithAttributes(attrs...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("otelx: build resource: %w", err)
+	}
+	return res, nil
+}
+
+// NewSampler 按名称创建采样器，名称见 config.Sampler* 常量，空名称为 parentbased_traceidratio；
+// ratio 超出 [0, 1] 时截断（0 表示不采样），未知名称按默认值处理（配置校验由 config.Tracing.Validate 负责）
+func NewSampler(name string, ratio float64) sdktrace.Sampler {
+	ratio = math.Min(math.Max(ratio, 0), 1)
+	switch name {
+	case config.SamplerAlwaysOn:
+		return sdktrace.AlwaysSample()
+	case config.SamplerAlwaysOff:
+		return sdktrace.NeverSample()
+	case config.SamplerTraceIDRatio:
+		return sdktrace.TraceIDRatioBased(ratio)
+	case config.SamplerParentBasedAlwaysOn:
+		return sdktrace.ParentBased(sdktrace.AlwaysSample())
+	default:
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+	}
+}
@@ -0,0 +1,89 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/qingfeng-studio/go-utils/config"
+	"github.com/qingfeng-studio/go-utils/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInit_Disabled(t *testing.T) {
+	shutdown, err := Init(context.Background(), config.Tracing{})
+	if err != nil || shutdown(context.Background()) != nil {
+		t.Fatalf("disabled Init should be a no-op: %v", err)
+	}
+	if _, err := Init(context.Background(), config.Tracing{Enabled: true, Sampler: "sometimes"}); err == nil {
+		t.Error("expected error for invalid sampler")
+	}
+}
+
+func TestInit_Spans(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	shutdown, err := Init(context.Background(), config.Tracing{
+		Enabled:     true,
+		ServiceName: "order-svc",
+		Environment: "test",
+		Sampler:     config.SamplerAlwaysOn,
+	}, WithExporter(exp), WithSyncExport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(context.Background())
+
+	ctx, span := StartClient(context.Background(), "mysqlx", "SELECT", attribute.String("db.system", "mysql"))
+	ctx = WithLogTraceID(ctx)
+	if id := logger.TraceIDFromContext(ctx); id == "" || id != TraceID(ctx) {
+		t.Errorf("log trace id = %q, span trace id = %q", id, TraceID(ctx))
+	}
+	h := http.Header{}
+	Inject(ctx, h)
+	if got := trace.SpanContextFromContext(Extract(context.Background(), h)).TraceID().String(); got != TraceID(ctx) {
+		t.Errorf("propagated trace id = %q", got)
+	}
+	End(span, errors.New("deadlock"))
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans", len(spans))
+	}
+	s := spans[0]
+	if s.Name != "SELECT" || s.SpanKind != trace.SpanKindClient || s.Status.Code != codes.Error {
+		t.Errorf("unexpected span: %+v", s)
+	}
+	if s.InstrumentationScope.Name != instrumentationName+"mysqlx" {
+		t.Errorf("scope = %q", s.InstrumentationScope.Name)
+	}
+	var service string
+	for _, kv := range s.Resource.Attributes() {
+		if kv.Key == "service.name" {
+			service = kv.Value.AsString()
+		}
+	}
+	if service != "order-svc" {
+		t.Errorf("service.name = %q", service)
+	}
+}
+
+func TestNewSampler(t *testing.T) {
+	for name, want := range map[string]string{
+		config.SamplerAlwaysOff:    "AlwaysOffSampler",
+		config.SamplerTraceIDRatio: "TraceIDRatioBased{0.5}",
+		"":                         "ParentBased{root:TraceIDRatioBased{0.5}",
+	} {
+		got := NewSampler(name, 0.5).Description()
+		if len(got) < len(want) || got[:len(want)] != want {
+			t.Errorf("%q: description = %q, want prefix %q", name, got, want)
+		}
+	}
+	if d := NewSampler(config.SamplerTraceIDRatio, 0).Description(); d != sdktrace.TraceIDRatioBased(0).Description() {
+		t.Errorf("zero ratio should sample nothing, got %q", d)
+	}
+}
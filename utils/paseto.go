@@ -0,0 +1,301 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20"
+)
+
+// PASETO v4 的两种用途
+const (
+	PasetoLocal  = "local"  // v4.local：对称加密（XChaCha20 + BLAKE2b-MAC）
+	PasetoPublic = "public" // v4.public：Ed25519 签名，载荷明文可读
+)
+
+const (
+	pasetoLocalHeader  = "v4.local."
+	pasetoPublicHeader = "v4.public."
+)
+
+// ErrInvalidPaseto token 格式错误、校验失败或用途不匹配
+var ErrInvalidPaseto = errors.New("invalid paseto token")
+
+// PasetoConfig PASETO v4 配置，与 JWT 共用 Issuer/ExpireTime 等字段
+type PasetoConfig struct {
+	JWTConfig // Secret 作为 v4.local 的 32 字节密钥；Issuer、ExpireTime 与 JWT 语义一致
+
+	Purpose    string             // PasetoLocal 或 PasetoPublic，默认 PasetoLocal
+	PrivateKey ed25519.PrivateKey // v4.public 签名私钥（仅签发方需要）
+	PublicKey  ed25519.PublicKey  // v4.public 验签公钥，为空时从 PrivateKey 推导
+	Footer     []byte             // 可选 footer（明文，受完整性保护），常用于放 kid
+	Implicit   []byte             // 可选隐式断言，不出现在 token 中，签发与校验双方需一致
+}
+
+// PasetoService 以 PASETO v4 格式实现 TokenService，可替换 JWTService 使用
+// claims 沿用 jwt.Claims（如 MyClaims），exp/nbf/iat 在载荷中按 PASETO 规范编码为 RFC 3339 时间
+type PasetoService struct {
+	cfg PasetoConfig
+}
+
+// NewPaseto 创建 PASETO 服务，校验密钥长度
+func NewPaseto(cfg PasetoConfig) (*PasetoService, error) {
+	if cfg.Purpose == "" {
+		cfg.Purpose = PasetoLocal
+	}
+	switch cfg.Purpose {
+	case PasetoLocal:
+		if len(cfg.Secret) != 32 {
+			return nil, errors.New("paseto v4.local requires a 32-byte secret")
+		}
+	case PasetoPublic:
+		if cfg.PublicKey == nil && cfg.PrivateKey != nil {
+			cfg.PublicKey = cfg.PrivateKey.Public().(ed25519.PublicKey)
+		}
+		if len(cfg.PublicKey) != ed25519.PublicKeySize {
+			return nil, errors.New("paseto v4.public requires an ed25519 key")
+		}
+	default:
+		return nil, fmt.Errorf("unknown paseto purpose %q", cfg.Purpose)
+	}
+	return &PasetoService{cfg: cfg}, nil
+}
+
+// GenerateToken 生成 token，默认字段的补全规则与 JWTService 相同
+func (p *PasetoService) GenerateToken(payload jwt.Claims) (string, error) {
+	token, err := p.generate(payload)
+	auditToken(p.cfg.JWTConfig, TokenOpGenerate, payload, err)
+	return token, err
+}
+
+// generate 生成 token，不触发审计回调
+func (p *PasetoService) generate(payload jwt.Claims) (string, error) {
+	fillDefaults(p.cfg.JWTConfig, payload)
+	msg, err := encodePasetoClaims(payload)
+	if err != nil {
+		return "", err
+	}
+	if p.cfg.Purpose == PasetoPublic {
+		if p.cfg.PrivateKey == nil {
+			return "", errors.New("paseto v4.public requires a private key to sign")
+		}
+		return p.sign(msg), nil
+	}
+	return p.encrypt(msg)
+}
+
+// ParseToken 校验 token 并将载荷解码到 claims，同时校验 exp/nbf
+func (p *PasetoService) ParseToken(token string, claims jwt.Claims) error {
+	err := p.parse(token, claims)
+	auditToken(p.cfg.JWTConfig, TokenOpParse, claims, err)
+	return err
+}
+
+// parse 校验 token，不触发审计回调
+func (p *PasetoService) parse(token string, claims jwt.Claims) error {
+	var (
+		msg []byte
+		err error
+	)
+	if p.cfg.Purpose == PasetoPublic {
+		msg, err = p.verify(token)
+	} else {
+		msg, err = p.decrypt(token)
+	}
+	if err != nil {
+		return err
+	}
+	if err := decodePasetoClaims(msg, claims); err != nil {
+		return err
+	}
+	return jwt.NewValidator().Validate(claims)
+}
+
+// encrypt v4.local 加密
+func (p *PasetoService) encrypt(msg []byte) (string, error) {
+	n := make([]byte, 32)
+	if _, err := rand.Read(n); err != nil {
+		return "", err
+	}
+	ek, n2, ak := p.localKeys(n)
+	c := make([]byte, len(msg))
+	stream, err := chacha20.NewUnauthenticatedCipher(ek, n2)
+	if err != nil {
+		return "", err
+	}
+	stream.XORKeyStream(c, msg)
+	t := blake2bMAC(ak, pae([]byte(pasetoLocalHeader), n, c, p.cfg.Footer, p.cfg.Implicit))
+
+	body := make([]byte, 0, len(n)+len(c)+len(t))
+	body = append(append(append(body, n...), c...), t...)
+	return pasetoJoin(pasetoLocalHeader, body, p.cfg.Footer), nil
+}
+
+// decrypt v4.local 解密，先校验 MAC 再解密
+func (p *PasetoService) decrypt(token string) ([]byte, error) {
+	body, err := p.split(token, pasetoLocalHeader)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < 64 {
+		return nil, ErrInvalidPaseto
+	}
+	n, c, t := body[:32], body[32:len(body)-32], body[len(body)-32:]
+	ek, n2, ak := p.localKeys(n)
+	if !hmac.Equal(t, blake2bMAC(ak, pae([]byte(pasetoLocalHeader), n, c, p.cfg.Footer, p.cfg.Implicit))) {
+		return nil, ErrInvalidPaseto
+	}
+	stream, err := chacha20.NewUnauthenticatedCipher(ek, n2)
+	if err != nil {
+		return nil, err
+	}
+	msg := make([]byte, len(c))
+	stream.XORKeyStream(msg, c)
+	return msg, nil
+}
+
+// localKeys 派生加密密钥 Ek、XChaCha20 nonce n2 与认证密钥 Ak
+func (p *PasetoService) localKeys(n []byte) (ek, n2, ak []byte) {
+	tmp := blake2bMAC(p.cfg.Secret, append([]byte("paseto-encryption-key"), n...), 56)
+	ak = blake2bMAC(p.cfg.Secret, append([]byte("paseto-auth-key-for-aead"), n...))
+	return tmp[:32], tmp[32:], ak
+}
+
+// sign v4.public 签名
+func (p *PasetoService) sign(msg []byte) string {
+	sig := ed25519.Sign(p.cfg.PrivateKey, pae([]byte(pasetoPublicHeader), msg, p.cfg.Footer, p.cfg.Implicit))
+	return pasetoJoin(pasetoPublicHeader, append(msg, sig...), p.cfg.Footer)
+}
+
+// verify v4.public 验签
+func (p *PasetoService) verify(token string) ([]byte, error) {
+	body, err := p.split(token, pasetoPublicHeader)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < ed25519.SignatureSize {
+		return nil, ErrInvalidPaseto
+	}
+	msg, sig := body[:len(body)-ed25519.SignatureSize], body[len(body)-ed25519.SignatureSize:]
+	if !ed25519.Verify(p.cfg.PublicKey, pae([]byte(pasetoPublicHeader), msg, p.cfg.Footer, p.cfg.Implicit), sig) {
+		return nil, ErrInvalidPaseto
+	}
+	return msg, nil
+}
+
+// split 校验头部与 footer，返回解码后的主体
+func (p *PasetoService) split(token, header string) ([]byte, error) {
+	if !strings.HasPrefix(token, header) {
+		return nil, ErrInvalidPaseto
+	}
+	parts := strings.Split(token[len(header):], ".")
+	if len(parts) > 2 {
+		return nil, ErrInvalidPaseto
+	}
+	var footer []byte
+	if len(parts) == 2 {
+		var err error
+		if footer, err = base64.RawURLEncoding.DecodeString(parts[1]); err != nil {
+			return nil, ErrInvalidPaseto
+		}
+	}
+	if !hmac.Equal(footer, p.cfg.Footer) {
+		return nil, ErrInvalidPaseto
+	}
+	body, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidPaseto
+	}
+	return body, nil
+}
+
+// pasetoJoin 拼接 header、主体与可选 footer
+func pasetoJoin(header string, body, footer []byte) string {
+	token := header + base64.RawURLEncoding.EncodeToString(body)
+	if len(footer) > 0 {
+		token += "." + base64.RawURLEncoding.EncodeToString(footer)
+	}
+	return token
+}
+
+// pae PASETO 预认证编码（Pre-Authentication Encoding）
+func pae(pieces ...[]byte) []byte {
+	var buf bytes.Buffer
+	le64 := func(n int) {
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], uint64(n)&^(1<<63))
+		buf.Write(b[:])
+	}
+	le64(len(pieces))
+	for _, p := range pieces {
+		le64(len(p))
+		buf.Write(p)
+	}
+	return buf.Bytes()
+}
+
+// blake2bMAC 带密钥的 BLAKE2b，size 默认 32 字节
+func blake2bMAC(key, msg []byte, size ...int) []byte {
+	n := 32
+	if len(size) > 0 {
+		n = size[0]
+	}
+	h, _ := blake2b.New(n, key) // n 与 key 长度均在合法范围内，不会出错
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+// pasetoTimeClaims PASETO 规范中以 RFC 3339 字符串表示的时间字段
+var pasetoTimeClaims = []string{"exp", "nbf", "iat"}
+
+// encodePasetoClaims 将 jwt.Claims 编码为 JSON，并把数值时间转换为 RFC 3339
+func encodePasetoClaims(claims jwt.Claims) ([]byte, error) {
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		// 非对象载荷原样返回
+		return raw, nil
+	}
+	for _, k := range pasetoTimeClaims {
+		if v, ok := m[k].(float64); ok {
+			m[k] = time.Unix(int64(v), 0).UTC().Format(time.RFC3339)
+		}
+	}
+	return json.Marshal(m)
+}
+
+// decodePasetoClaims 将 RFC 3339 时间转换回数值后解码到 claims
+func decodePasetoClaims(msg []byte, claims jwt.Claims) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(msg, &m); err != nil {
+		return ErrInvalidPaseto
+	}
+	for _, k := range pasetoTimeClaims {
+		if s, ok := m[k].(string); ok {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return fmt.Errorf("paseto claim %s: %w", k, err)
+			}
+			m[k] = t.Unix()
+		}
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return decodeClaims(raw, claims)
+}
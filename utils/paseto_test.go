@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasetoLocal(t *testing.T) {
+	p, err := NewPaseto(PasetoConfig{
+		JWTConfig: JWTConfig{Secret: []byte("0123456789abcdef0123456789abcdef"), Issuer: "test-service", ExpireTime: time.Hour},
+		Footer:    []byte(`{"kid":"k1"}`),
+	})
+	assert.NoError(t, err)
+
+	token, err := p.GenerateToken(&MyClaims{UserID: 1001, Role: "admin"})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, "v4.local."))
+	assert.NotContains(t, token, base64.RawURLEncoding.EncodeToString([]byte("admin")))
+
+	var parsed MyClaims
+	assert.NoError(t, p.ParseToken(token, &parsed))
+	assert.Equal(t, int64(1001), parsed.UserID)
+	assert.Equal(t, "test-service", parsed.Issuer)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), parsed.ExpiresAt.Time, time.Minute)
+
+	// 篡改任意一个字符都应校验失败
+	tampered := []byte(token)
+	tampered[20] ^= 1
+	assert.Error(t, p.ParseToken(string(tampered), &MyClaims{}))
+
+	other, _ := NewPaseto(PasetoConfig{JWTConfig: JWTConfig{Secret: []byte("0123456789abcdef0123456789abcdef")}})
+	assert.ErrorIs(t, other.ParseToken(token, &MyClaims{}), ErrInvalidPaseto, "footer mismatch")
+
+	_, err = NewPaseto(PasetoConfig{JWTConfig: JWTConfig{Secret: []byte("short")}})
+	assert.Error(t, err)
+}
+
+func TestPasetoPublic(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	signer, err := NewPaseto(PasetoConfig{Purpose: PasetoPublic, PrivateKey: priv})
+	assert.NoError(t, err)
+	verifier, err := NewPaseto(PasetoConfig{Purpose: PasetoPublic, PublicKey: pub})
+	assert.NoError(t, err)
+
+	token, err := signer.GenerateToken(&jwt.RegisteredClaims{
+		Subject:   "u-1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, "v4.public."))
+
+	var rc jwt.RegisteredClaims
+	assert.NoError(t, verifier.ParseToken(token, &rc))
+	assert.Equal(t, "u-1", rc.Subject)
+
+	_, err = verifier.GenerateToken(&jwt.RegisteredClaims{})
+	assert.Error(t, err, "verifier has no private key")
+
+	expired, _ := signer.GenerateToken(&jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))})
+	assert.ErrorIs(t, verifier.ParseToken(expired, &jwt.RegisteredClaims{}), jwt.ErrTokenExpired)
+
+	// 同一中间件可直接用于 PASETO
+	auth := AuthMiddleware(verifier, func() jwt.Claims { return &jwt.RegisteredClaims{} })
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, ok := ClaimsFromContext[*jwt.RegisteredClaims](r.Context())
+		assert.True(t, ok)
+		assert.Equal(t, "u-1", c.Subject)
+	})).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// 官方测试向量 4-S-1
+func TestPasetoPublicVector(t *testing.T) {
+	pk, _ := hex.DecodeString("1eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2")
+	p, err := NewPaseto(PasetoConfig{Purpose: PasetoPublic, PublicKey: ed25519.PublicKey(pk)})
+	assert.NoError(t, err)
+	msg, err := p.verify("v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"data":"this is a signed message","exp":"2022-01-01T00:00:00+00:00"}`, string(msg))
+}
@@ -0,0 +1,588 @@
+package qrx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // 注册 GIF 解码
+	_ "image/jpeg" // 注册 JPEG 解码
+	_ "image/png"  // 注册 PNG 解码
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	_ "golang.org/x/image/webp" // 注册 WebP 解码
+	"golang.org/x/text/encoding/japanese"
+)
+
+var (
+	// ErrNotFound 图片中没有找到二维码
+	ErrNotFound = errors.New("qrx: qr code not found")
+	// ErrUnreadable 找到二维码但污损超出纠错能力或格式不受支持
+	ErrUnreadable = errors.New("qrx: qr code unreadable")
+	// ErrTooLarge 图片像素数超过 maxDecodePixels
+	ErrTooLarge = errors.New("qrx: image too large")
+)
+
+// maxDecodePixels 允许识别的最大像素数（约 4000×4000），防止很小的压缩文件解码后耗尽内存
+const maxDecodePixels = 16_000_000
+
+// Decode 识别 PNG/JPEG/GIF/WebP 图片中的二维码并返回内容，限制同 DecodeImage；
+// 先读取图片尺寸，像素数超过约 1600 万时返回 ErrTooLarge 而不解码
+func Decode(data []byte) (string, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("qrx: decode image: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxDecodePixels {
+		return "", fmt.Errorf("%w: %dx%d", ErrTooLarge, cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("qrx: decode image: %w", err)
+	}
+	return DecodeImage(img)
+}
+
+// DecodeImage 识别图片中的二维码；只支持图片中仅有一个与图片边缘对齐（未旋转、未透视变形）的二维码，
+// 如本包生成的码、截图、裁剪后的扫描件，倾斜的码返回 ErrNotFound 或 ErrUnreadable；
+// 透明像素按白色处理，拍照识别请在客户端完成
+func DecodeImage(img image.Image) (string, error) {
+	bm := binarize(img)
+	if bm == nil {
+		return "", ErrNotFound
+	}
+	g, err := bm.sample()
+	if err != nil {
+		return "", err
+	}
+	return g.decode()
+}
+
+// bitmap 二值化后的图片，true 为深色
+type bitmap struct {
+	w, h int
+	dark []bool
+}
+
+// at 读取像素，越界时视为浅色
+func (b *bitmap) at(x, y int) bool {
+	if x < 0 || y < 0 || x >= b.w || y >= b.h {
+		return false
+	}
+	return b.dark[y*b.w+x]
+}
+
+// binarize 按亮度的中值阈值二值化，对比度过低时返回 nil
+func binarize(img image.Image) *bitmap {
+	r := img.Bounds()
+	w, h := r.Dx(), r.Dy()
+	if w == 0 || h == 0 {
+		return nil
+	}
+	lum := make([]uint8, w*h)
+	lo, hi := uint8(255), uint8(0)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			cr, cg, cb, ca := img.At(r.Min.X+x, r.Min.Y+y).RGBA()
+			// 预乘 alpha 的颜色叠加在白色背景上
+			bg := 0xffff - ca
+			v := uint8(((cr+bg)*299 + (cg+bg)*587 + (cb+bg)*114) / 1000 >> 8)
+			lum[y*w+x] = v
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	if hi-lo < 32 {
+		return nil
+	}
+	threshold := (int(lo) + int(hi)) / 2
+	b := &bitmap{w: w, h: h, dark: make([]bool, w*h)}
+	for i, v := range lum {
+		b.dark[i] = int(v) < threshold
+	}
+	return b
+}
+
+// grid 采样得到的模块矩阵，modules[row][col] 为 true 表示深色
+type grid struct {
+	version int
+	size    int
+	modules [][]bool
+}
+
+// sample 定位二维码并按模块采样：以深色像素的外接矩形为码区，沿左上角定位图形的对角线估算模块尺寸，
+// 再由码区宽度推算版本
+func (b *bitmap) sample() (*grid, error) {
+	minX, minY, maxX, maxY := b.w, b.h, -1, -1
+	for y := 0; y < b.h; y++ {
+		for x := 0; x < b.w; x++ {
+			if b.dark[y*b.w+x] {
+				minX, minY, maxX, maxY = min(minX, x), min(minY, y), max(maxX, x), max(maxY, y)
+			}
+		}
+	}
+	if maxX < 0 {
+		return nil, ErrNotFound
+	}
+	width, height := float64(maxX-minX+1), float64(maxY-minY+1)
+	if width < 21 || math.Abs(width-height) > width/10 {
+		return nil, ErrNotFound
+	}
+
+	// 左上角定位图形的对角线依次为 深1:浅1:深3:浅1:深1
+	var runs []int
+	for t, cur := 0, true; minX+t <= maxX && minY+t <= maxY && len(runs) <= 5; t++ {
+		d := b.at(minX+t, minY+t)
+		if len(runs) == 0 || d != cur {
+			if len(runs) == 0 && !d {
+				continue
+			}
+			runs, cur = append(runs, 0), d
+		}
+		runs[len(runs)-1]++
+	}
+	if len(runs) < 5 {
+		return nil, ErrNotFound
+	}
+	total := 0
+	for _, n := range runs[:5] {
+		total += n
+	}
+	unit := float64(total) / 7
+	for i, n := range runs[:5] {
+		want := unit
+		if i == 2 {
+			want = 3 * unit
+		}
+		if float64(n) < want/2 || float64(n) > want*2 {
+			return nil, ErrNotFound
+		}
+	}
+
+	// 模块较小时估算误差可达一个版本，取附近版本中定位与时序图形吻合度最高的一个
+	est := int(math.Round((width/unit - 17) / 4))
+	var best *grid
+	bestMiss := 0
+	for v := max(est-2, 1); v <= min(est+2, 40); v++ {
+		n := 17 + 4*v
+		g := &grid{version: v, size: n, modules: make([][]bool, n)}
+		mw, mh := width/float64(n), height/float64(n)
+		for r := 0; r < n; r++ {
+			g.modules[r] = make([]bool, n)
+			for c := 0; c < n; c++ {
+				g.modules[r][c] = b.at(minX+int((float64(c)+0.5)*mw), minY+int((float64(r)+0.5)*mh))
+			}
+		}
+		if miss, ok := g.patternMisses(); ok && (best == nil || miss < bestMiss) {
+			best, bestMiss = g, miss
+		}
+	}
+	if best == nil {
+		return nil, ErrNotFound
+	}
+	return best, nil
+}
+
+// patternMisses 统计定位图形与时序图形中采样不符的模块数，任一定位图形偏差过大或时序图形错位过多时 ok 为 false
+func (g *grid) patternMisses() (miss int, ok bool) {
+	for _, o := range [][2]int{{0, 0}, {0, g.size - 7}, {g.size - 7, 0}} {
+		m := 0
+		for r := 0; r < 7; r++ {
+			for c := 0; c < 7; c++ {
+				ring := min(r, c, 6-r, 6-c)
+				if g.modules[o[0]+r][o[1]+c] != (ring != 1) {
+					m++
+				}
+			}
+		}
+		if m > 4 {
+			return 0, false
+		}
+		miss += m
+	}
+	timing := 0
+	for i := 8; i < g.size-8; i++ {
+		if g.modules[6][i] != (i%2 == 0) {
+			timing++
+		}
+		if g.modules[i][6] != (i%2 == 0) {
+			timing++
+		}
+	}
+	return miss + timing, timing <= (g.size-16)/4
+}
+
+// decode 读取格式信息、解掩码、纠错并解析数据段
+func (g *grid) decode() (string, error) {
+	level, mask, ok := g.formatInfo()
+	if !ok {
+		return "", fmt.Errorf("%w: bad format information", ErrUnreadable)
+	}
+	codewords := g.readCodewords(mask)
+	data, err := deinterleave(codewords, ecBlocks[g.version-1][level])
+	if err != nil {
+		return "", err
+	}
+	return parseSegments(data, g.version)
+}
+
+// formatInfo 读取两份格式信息并取与合法码字汉明距离最近的一个，返回纠错级别（L/M/Q/H 下标）与掩码
+func (g *grid) formatInfo() (level, mask int, ok bool) {
+	n := g.size
+	var a, b uint32
+	bit := func(v *uint32, r, c int) {
+		*v <<= 1
+		if g.modules[r][c] {
+			*v |= 1
+		}
+	}
+	// 第一份：第 8 行的 0-5、7、8 列，再沿第 8 列向上
+	for c := 0; c <= 5; c++ {
+		bit(&a, 8, c)
+	}
+	bit(&a, 8, 7)
+	bit(&a, 8, 8)
+	bit(&a, 7, 8)
+	for r := 5; r >= 0; r-- {
+		bit(&a, r, 8)
+	}
+	// 第二份：第 8 列自底向上 7 位，再第 8 行右侧 8 位
+	for r := n - 1; r >= n-7; r-- {
+		bit(&b, r, 8)
+	}
+	for c := n - 8; c < n; c++ {
+		bit(&b, 8, c)
+	}
+
+	best, bestDist := 0, 16
+	for info := 0; info < 32; info++ {
+		code := formatCode(info)
+		if d := min(bits.OnesCount32(a^code), bits.OnesCount32(b^code)); d < bestDist {
+			best, bestDist = info, d
+		}
+	}
+	if bestDist > 3 {
+		return 0, 0, false
+	}
+	// 纠错级别编码：L=01 M=00 Q=11 H=10
+	level = [4]int{1, 0, 3, 2}[best>>3]
+	return level, best & 7, true
+}
+
+// formatCode 5 位格式数据的 BCH(15,5) 编码（已异或掩码 0x5412）
+func formatCode(info int) uint32 {
+	v := uint32(info) << 10
+	for i := 14; i >= 10; i-- {
+		if v&(1<<i) != 0 {
+			v ^= 0x537 << (i - 10)
+		}
+	}
+	return (uint32(info)<<10 | v) ^ 0x5412
+}
+
+// isFunction 是否为功能图形（定位、分隔、格式、时序、校正、版本信息）所占的模块
+func (g *grid) isFunction(r, c int) bool {
+	n := g.size
+	switch {
+	case r <= 8 && (c <= 8 || c >= n-8), r >= n-8 && c <= 8:
+		return true
+	case r == 6 || c == 6:
+		return true
+	case g.version >= 7 && ((r <= 5 && c >= n-11 && c <= n-9) || (c <= 5 && r >= n-11 && r <= n-9)):
+		return true
+	}
+	centers := alignmentCenters[g.version]
+	last := len(centers) - 1
+	for i, cy := range centers {
+		for j, cx := range centers {
+			// 与定位图形重叠的三个位置没有校正图形
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			if r >= cy-2 && r <= cy+2 && c >= cx-2 && c <= cx+2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// masked 掩码图形在 (r, c) 处是否翻转
+func masked(mask, r, c int) bool {
+	switch mask {
+	case 0:
+		return (r+c)%2 == 0
+	case 1:
+		return r%2 == 0
+	case 2:
+		return c%3 == 0
+	case 3:
+		return (r+c)%3 == 0
+	case 4:
+		return (r/2+c/3)%2 == 0
+	case 5:
+		return r*c%2+r*c%3 == 0
+	case 6:
+		return (r*c%2+r*c%3)%2 == 0
+	default:
+		return ((r+c)%2+r*c%3)%2 == 0
+	}
+}
+
+// readCodewords 自右下角起每两列一组蛇形读取数据模块并解掩码
+func (g *grid) readCodewords(mask int) []byte {
+	n := g.size
+	var out []byte
+	var cur byte
+	nbits := 0
+	up := true
+	for right := n - 1; right > 0; right -= 2 {
+		if right == 6 {
+			right--
+		}
+		for i := 0; i < n; i++ {
+			r := i
+			if up {
+				r = n - 1 - i
+			}
+			for j := 0; j < 2; j++ {
+				c := right - j
+				if g.isFunction(r, c) {
+					continue
+				}
+				cur <<= 1
+				if g.modules[r][c] != masked(mask, r, c) {
+					cur |= 1
+				}
+				if nbits++; nbits%8 == 0 {
+					out = append(out, cur)
+					cur = 0
+				}
+			}
+		}
+		up = !up
+	}
+	return out
+}
+
+// deinterleave 按分块表拆分交织的码字，逐块纠错后拼接数据码字
+func deinterleave(codewords []byte, eb ecBlock) ([]byte, error) {
+	nblocks := eb.blocks1 + eb.blocks2
+	dataLen := func(i int) int {
+		if i < eb.blocks1 {
+			return eb.data1
+		}
+		return eb.data2
+	}
+	blocks := make([][]byte, nblocks)
+	for i := range blocks {
+		blocks[i] = make([]byte, dataLen(i)+eb.ecPerBlock)
+	}
+	pos := 0
+	next := func() (byte, bool) {
+		if pos >= len(codewords) {
+			return 0, false
+		}
+		pos++
+		return codewords[pos-1], true
+	}
+	for k := 0; k < max(eb.data1, eb.data2); k++ {
+		for i := range blocks {
+			if k < dataLen(i) {
+				v, ok := next()
+				if !ok {
+					return nil, fmt.Errorf("%w: truncated data", ErrUnreadable)
+				}
+				blocks[i][k] = v
+			}
+		}
+	}
+	for k := 0; k < eb.ecPerBlock; k++ {
+		for i := range blocks {
+			v, ok := next()
+			if !ok {
+				return nil, fmt.Errorf("%w: truncated data", ErrUnreadable)
+			}
+			blocks[i][dataLen(i)+k] = v
+		}
+	}
+
+	var data []byte
+	for i, blk := range blocks {
+		if !rsCorrect(blk, eb.ecPerBlock) {
+			return nil, fmt.Errorf("%w: too many errors", ErrUnreadable)
+		}
+		data = append(data, blk[:dataLen(i)]...)
+	}
+	return data, nil
+}
+
+// bitReader 按位读取
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+// read 读取 n 位，剩余不足时 ok 为 false
+func (br *bitReader) read(n int) (v int, ok bool) {
+	if br.pos+n > len(br.data)*8 {
+		return 0, false
+	}
+	for i := 0; i < n; i++ {
+		v <<= 1
+		if br.data[(br.pos+i)/8]&(0x80>>((br.pos+i)%8)) != 0 {
+			v |= 1
+		}
+	}
+	br.pos += n
+	return v, true
+}
+
+// 数据段模式
+const (
+	modeTerminator      = 0x0
+	modeNumeric         = 0x1
+	modeAlphanumeric    = 0x2
+	modeStructured      = 0x3
+	modeByte            = 0x4
+	modeFNC1First       = 0x5
+	modeECI             = 0x7
+	modeKanji           = 0x8
+	modeFNC1Second      = 0x9
+	alphanumericCharset = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+)
+
+// countBits 字符数指示符的位数，随版本与模式变化
+func countBits(mode, version int) int {
+	col := 0
+	switch {
+	case version >= 27:
+		col = 2
+	case version >= 10:
+		col = 1
+	}
+	switch mode {
+	case modeNumeric:
+		return [3]int{10, 12, 14}[col]
+	case modeAlphanumeric:
+		return [3]int{9, 11, 13}[col]
+	case modeByte:
+		return [3]int{8, 16, 16}[col]
+	default:
+		return [3]int{8, 10, 12}[col]
+	}
+}
+
+// parseSegments 解析数据码字中的各数据段；字节模式按 UTF-8 处理，汉字模式按 Shift_JIS 转换
+func parseSegments(data []byte, version int) (string, error) {
+	br := &bitReader{data: data}
+	var sb strings.Builder
+	bad := fmt.Errorf("%w: malformed data", ErrUnreadable)
+	for {
+		mode, ok := br.read(4)
+		if !ok || mode == modeTerminator {
+			return sb.String(), nil
+		}
+		switch mode {
+		case modeFNC1First:
+			continue
+		case modeFNC1Second:
+			if _, ok := br.read(8); !ok {
+				return "", bad
+			}
+			continue
+		case modeStructured:
+			if _, ok := br.read(16); !ok {
+				return "", bad
+			}
+			continue
+		case modeECI:
+			// 只跳过指定符，内容一律按 UTF-8 输出
+			first, ok := br.read(8)
+			if !ok {
+				return "", bad
+			}
+			extra := 0
+			switch {
+			case first&0x80 == 0:
+			case first&0xC0 == 0x80:
+				extra = 8
+			case first&0xE0 == 0xC0:
+				extra = 16
+			default:
+				return "", bad
+			}
+			if _, ok := br.read(extra); !ok {
+				return "", bad
+			}
+			continue
+		case modeNumeric, modeAlphanumeric, modeByte, modeKanji:
+		default:
+			return "", bad
+		}
+
+		count, ok := br.read(countBits(mode, version))
+		if !ok {
+			return "", bad
+		}
+		switch mode {
+		case modeNumeric:
+			for count > 0 {
+				digits := min(count, 3)
+				v, ok := br.read([4]int{0, 4, 7, 10}[digits])
+				if !ok || v >= [4]int{0, 10, 100, 1000}[digits] {
+					return "", bad
+				}
+				s := strconv.Itoa(v)
+				sb.WriteString(strings.Repeat("0", digits-len(s)) + s)
+				count -= digits
+			}
+		case modeAlphanumeric:
+			for ; count >= 2; count -= 2 {
+				v, ok := br.read(11)
+				if !ok || v >= 45*45 {
+					return "", bad
+				}
+				sb.WriteByte(alphanumericCharset[v/45])
+				sb.WriteByte(alphanumericCharset[v%45])
+			}
+			if count == 1 {
+				v, ok := br.read(6)
+				if !ok || v >= 45 {
+					return "", bad
+				}
+				sb.WriteByte(alphanumericCharset[v])
+			}
+		case modeByte:
+			for ; count > 0; count-- {
+				v, ok := br.read(8)
+				if !ok {
+					return "", bad
+				}
+				sb.WriteByte(byte(v))
+			}
+		case modeKanji:
+			sjis := make([]byte, 0, count*2)
+			for ; count > 0; count-- {
+				v, ok := br.read(13)
+				if !ok {
+					return "", bad
+				}
+				code := (v/0xC0)<<8 | v%0xC0
+				if code < 0x1F00 {
+					code += 0x8140
+				} else {
+					code += 0xC140
+				}
+				sjis = append(sjis, byte(code>>8), byte(code))
+			}
+			s, err := japanese.ShiftJIS.NewDecoder().Bytes(sjis)
+			if err != nil {
+				return "", bad
+			}
+			sb.Write(s)
+		}
+	}
+}
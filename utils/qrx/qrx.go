@@ -0,0 +1,151 @@
+// Package qrx 二维码生成与识别：生成 PNG（可嵌入 logo、设置纠错级别与颜色），以及从图片中识别二维码内容
+// （仅支持与图片边缘对齐的码，见 DecodeImage），用于营销海报、支付码等场景
+// 使用示例：
+//
+//	png, err := qrx.Generate("https://example.com/pay?id=1", 512, qrx.WithLogo(logo))
+//	content, err := qrx.Decode(png)
+package qrx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/skip2/go-qrcode"
+	xdraw "golang.org/x/image/draw"
+)
+
+const (
+	// defaultSize 默认边长（像素）
+	defaultSize = 256
+	// defaultLogoRatio logo 默认占二维码边长的比例
+	defaultLogoRatio = 0.2
+	// maxLogoRatio logo 占比上限，超过后遮挡的模块超出纠错能力
+	maxLogoRatio = 0.3
+)
+
+// ErrEmptyContent 内容为空
+var ErrEmptyContent = errors.New("qrx: empty content")
+
+// Level 纠错级别，级别越高可容忍的污损越多，但相同内容生成的码越密
+type Level int
+
+const (
+	LevelL Level = iota // 约 7% 纠错
+	LevelM              // 约 15% 纠错，默认
+	LevelQ              // 约 25% 纠错
+	LevelH              // 约 30% 纠错，嵌入 logo 时的默认级别
+)
+
+// options 生成选项
+type options struct {
+	level     Level
+	levelSet  bool
+	logo      image.Image
+	logoRatio float64
+	fg, bg    color.Color
+	noBorder  bool
+}
+
+// Option 生成选项
+type Option func(*options)
+
+// WithLevel 设置纠错级别，默认 LevelM，嵌入 logo 时默认 LevelH
+func WithLevel(l Level) Option {
+	return func(o *options) { o.level, o.levelSet = l, true }
+}
+
+// WithLogo 在二维码中央嵌入 logo，logo 按比例缩放并带背景色衬底
+func WithLogo(img image.Image) Option {
+	return func(o *options) { o.logo = img }
+}
+
+// WithLogoRatio 设置 logo 占二维码边长的比例，默认 0.2，最大 0.3
+func WithLogoRatio(r float64) Option {
+	return func(o *options) { o.logoRatio = r }
+}
+
+// WithColors 设置前景色与背景色，默认黑码白底；前景色应明显深于背景色，否则无法识别
+func WithColors(fg, bg color.Color) Option {
+	return func(o *options) { o.fg, o.bg = fg, bg }
+}
+
+// WithoutBorder 去掉四周的静区（quiet zone），由调用方自行留白
+func WithoutBorder() Option {
+	return func(o *options) { o.noBorder = true }
+}
+
+// Generate 生成边长为 size 像素（<= 0 时为 256）的二维码 PNG；内容过长时返回错误
+func Generate(content string, size int, opts ...Option) ([]byte, error) {
+	img, err := GenerateImage(content, size, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("qrx: encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// GenerateImage 同 Generate，返回 image.Image，便于合成到海报等图片中
+func GenerateImage(content string, size int, opts ...Option) (image.Image, error) {
+	if content == "" {
+		return nil, ErrEmptyContent
+	}
+	o := options{level: LevelM, logoRatio: defaultLogoRatio, fg: color.Black, bg: color.White}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.logo != nil && !o.levelSet {
+		o.level = LevelH
+	}
+	if size <= 0 {
+		size = defaultSize
+	}
+
+	q, err := qrcode.New(content, qrcode.RecoveryLevel(o.level))
+	if err != nil {
+		return nil, fmt.Errorf("qrx: %w", err)
+	}
+	q.ForegroundColor, q.BackgroundColor, q.DisableBorder = o.fg, o.bg, o.noBorder
+	img := q.Image(size)
+	if o.logo == nil {
+		return img, nil
+	}
+	return embedLogo(img, o), nil
+}
+
+// embedLogo 将 logo 等比缩放后绘制在中央，四周留出背景色衬底
+func embedLogo(qr image.Image, o options) image.Image {
+	b := qr.Bounds()
+	out := image.NewRGBA(b)
+	draw.Draw(out, b, qr, b.Min, draw.Src)
+
+	ratio := o.logoRatio
+	if ratio <= 0 || ratio > maxLogoRatio {
+		ratio = maxLogoRatio
+	}
+	box := int(float64(b.Dx()) * ratio)
+	lb := o.logo.Bounds()
+	if box <= 0 || lb.Empty() {
+		return out
+	}
+	w, h := box, box
+	if lb.Dx() > lb.Dy() {
+		h = box * lb.Dy() / lb.Dx()
+	} else {
+		w = box * lb.Dx() / lb.Dy()
+	}
+	center := image.Pt(b.Min.X+b.Dx()/2, b.Min.Y+b.Dy()/2)
+	dst := image.Rect(center.X-w/2, center.Y-h/2, center.X-w/2+w, center.Y-h/2+h)
+
+	pad := box / 10
+	draw.Draw(out, dst.Inset(-pad), image.NewUniform(o.bg), image.Point{}, draw.Src)
+	xdraw.CatmullRom.Scale(out, dst, o.logo, lb, draw.Over, nil)
+	return out
+}
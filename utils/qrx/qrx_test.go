@@ -0,0 +1,118 @@
+package qrx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDecode(t *testing.T) {
+	cases := []string{
+		"1234567890",
+		"HELLO WORLD $%*+-./:",
+		"https://example.com/pay?orderId=20240101000001&amount=99.00",
+		"扫码领取优惠券，活动截止 12 月 31 日",
+		strings.Repeat("长内容 long content 0123456789 ", 20),
+	}
+	for _, content := range cases {
+		for _, level := range []Level{LevelL, LevelM, LevelQ, LevelH} {
+			data, err := Generate(content, 0, WithLevel(level))
+			require.NoError(t, err)
+			img, err := png.Decode(bytes.NewReader(data))
+			require.NoError(t, err)
+			size := img.Bounds().Dx()
+			assert.Equal(t, size, img.Bounds().Dy())
+
+			got, err := Decode(data)
+			require.NoError(t, err, "level %d: %q", level, content)
+			assert.Equal(t, content, got)
+		}
+	}
+}
+
+func TestGenerateOptions(t *testing.T) {
+	_, err := Generate("", 256)
+	assert.ErrorIs(t, err, ErrEmptyContent)
+	_, err = Generate(strings.Repeat("x", 4000), 256)
+	assert.Error(t, err)
+
+	content := "https://example.com/activity/2024?channel=poster"
+	logo := image.NewRGBA(image.Rect(0, 0, 120, 80))
+	draw.Draw(logo, logo.Bounds(), image.NewUniform(color.RGBA{R: 200, A: 255}), image.Point{}, draw.Src)
+
+	img, err := GenerateImage(content, 400,
+		WithLogo(logo),
+		WithColors(color.RGBA{B: 120, A: 255}, color.RGBA{R: 255, G: 250, B: 230, A: 255}))
+	require.NoError(t, err)
+	got, err := DecodeImage(img)
+	require.NoError(t, err, "logo should be recovered by error correction")
+	assert.Equal(t, content, got)
+
+	img, err = GenerateImage(content, 300, WithoutBorder())
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 75}))
+	got, err = Decode(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	_, err := Decode([]byte("not an image"))
+	assert.Error(t, err)
+
+	blank := image.NewGray(image.Rect(0, 0, 100, 100))
+	_, err = DecodeImage(blank)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	img, err := GenerateImage("damaged", 210, WithLevel(LevelL))
+	require.NoError(t, err)
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), img, image.Point{}, draw.Src)
+	draw.Draw(rgba, image.Rect(90, 90, 150, 150), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	_, err = DecodeImage(rgba)
+	assert.ErrorIs(t, err, ErrUnreadable)
+}
+
+// TestDecodeTooLarge 只读取尺寸即拒绝像素数过大的图片
+func TestDecodeTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))))
+	data := buf.Bytes()
+	// 改写 IHDR 中的宽高为 50000×50000 并重算 CRC
+	binary.BigEndian.PutUint32(data[16:], 50000)
+	binary.BigEndian.PutUint32(data[20:], 50000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	_, err := Decode(data)
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestRSCorrect(t *testing.T) {
+	// 版本 1-M 的 "01234567" 码块（ISO/IEC 18004 附录 I 示例）
+	block := []byte{
+		0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11,
+		0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55,
+	}
+	want := append([]byte(nil), block...)
+	for _, i := range []int{0, 5, 9, 17, 25} {
+		block[i] ^= 0x5A
+	}
+	require.True(t, rsCorrect(block, 10))
+	assert.Equal(t, want, block)
+
+	for i := 0; i < 6; i++ {
+		block[i] ^= 0xFF
+	}
+	assert.False(t, rsCorrect(block, 10))
+}
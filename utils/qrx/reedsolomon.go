@@ -0,0 +1,137 @@
+package qrx
+
+// GF(256) 运算表，本原多项式 x^8+x^4+x^3+x^2+1（0x11D），与 QR 码规范一致
+var (
+	gfExp [512]byte
+	gfLog [256]int
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = i
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	for i := 255; i < len(gfExp); i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+// gfMul 乘法
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[gfLog[a]+gfLog[b]]
+}
+
+// gfDiv 除法，b 不能为 0
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[(gfLog[a]-gfLog[b]+255)%255]
+}
+
+// polyEval 计算升幂系数多项式 p 在 x 处的值
+func polyEval(p []byte, x byte) byte {
+	var y byte
+	for i := len(p) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ p[i]
+	}
+	return y
+}
+
+// rsCorrect 就地纠正一个码块（数据码字在前、nec 个纠错码字在后），最多纠正 nec/2 个错误码字，超出时返回 false
+func rsCorrect(block []byte, nec int) bool {
+	n := len(block)
+	// 伴随式 S_j = C(α^j)，C 的最高次项为 block[0]
+	synd := make([]byte, nec)
+	clean := true
+	for j := 0; j < nec; j++ {
+		var s byte
+		for _, c := range block {
+			s = gfMul(s, gfExp[j]) ^ c
+		}
+		synd[j] = s
+		if s != 0 {
+			clean = false
+		}
+	}
+	if clean {
+		return true
+	}
+
+	// Berlekamp-Massey 求错误位置多项式
+	lambda, prev := []byte{1}, []byte{1}
+	errs, shift, last := 0, 1, byte(1)
+	for k := 0; k < nec; k++ {
+		d := synd[k]
+		for i := 1; i <= errs && i < len(lambda); i++ {
+			d ^= gfMul(lambda[i], synd[k-i])
+		}
+		if d == 0 {
+			shift++
+			continue
+		}
+		next := make([]byte, max(len(lambda), len(prev)+shift))
+		copy(next, lambda)
+		coef := gfDiv(d, last)
+		for i, p := range prev {
+			next[i+shift] ^= gfMul(coef, p)
+		}
+		if 2*errs <= k {
+			prev, errs, last, shift = lambda, k+1-errs, d, 1
+		} else {
+			shift++
+		}
+		lambda = next
+	}
+	if errs == 0 || 2*errs > nec {
+		return false
+	}
+
+	// 错误值多项式 Ω = S·Λ mod x^nec，以及 Λ 的形式导数
+	omega := make([]byte, nec)
+	for k := range omega {
+		for i := 0; i <= k && i < len(lambda); i++ {
+			omega[k] ^= gfMul(lambda[i], synd[k-i])
+		}
+	}
+	deriv := make([]byte, len(lambda))
+	for i := 1; i < len(lambda); i += 2 {
+		deriv[i-1] = lambda[i]
+	}
+
+	// Chien 搜索错误位置，Forney 公式求错误值
+	found := 0
+	for i := 0; i < n; i++ {
+		xinv := gfExp[(255-i)%255]
+		if polyEval(lambda, xinv) != 0 {
+			continue
+		}
+		den := polyEval(deriv, xinv)
+		if den == 0 {
+			return false
+		}
+		block[n-1-i] ^= gfMul(gfExp[i], gfDiv(polyEval(omega, xinv), den))
+		found++
+	}
+	if found != errs {
+		return false
+	}
+	for j := 0; j < nec; j++ {
+		var s byte
+		for _, c := range block {
+			s = gfMul(s, gfExp[j]) ^ c
+		}
+		if s != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+// Package response 统一的分页参数、分页结果与 API 响应结构 {code, msg, data, traceId}
+package response
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// 分页默认值
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// PageRequest 分页请求参数，页码从 1 开始
+type PageRequest struct {
+	Page     int `json:"page" form:"page"`
+	PageSize int `json:"pageSize" form:"pageSize"`
+}
+
+// Normalize 修正非法参数：页码小于 1 时取 1，每页条数为 0 时取 DefaultPageSize，超过 MaxPageSize 时截断
+func (p PageRequest) Normalize() PageRequest {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.PageSize <= 0 {
+		p.PageSize = DefaultPageSize
+	}
+	if p.PageSize > MaxPageSize {
+		p.PageSize = MaxPageSize
+	}
+	return p
+}
+
+// Offset 对应 SQL 的 OFFSET
+func (p PageRequest) Offset() int {
+	n := p.Normalize()
+	return (n.Page - 1) * n.PageSize
+}
+
+// Limit 对应 SQL 的 LIMIT
+func (p PageRequest) Limit() int {
+	return p.Normalize().PageSize
+}
+
+// ParsePageRequest 从 query 参数解析分页（page、pageSize，兼容 page_size/size），结果已 Normalize
+func ParsePageRequest(q url.Values) PageRequest {
+	var p PageRequest
+	p.Page, _ = strconv.Atoi(q.Get("page"))
+	for _, k := range []string{"pageSize", "page_size", "size"} {
+		if v := q.Get(k); v != "" {
+			p.PageSize, _ = strconv.Atoi(v)
+			break
+		}
+	}
+	return p.Normalize()
+}
+
+// PageResult 分页结果
+type PageResult[T any] struct {
+	List     []T   `json:"list"`
+	Total    int64 `json:"total"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"pageSize"`
+	Pages    int64 `json:"pages"` // 总页数
+}
+
+// NewPageResult 根据分页请求、当前页数据与总数构造分页结果，list 为 nil 时输出 []
+func NewPageResult[T any](req PageRequest, list []T, total int64) *PageResult[T] {
+	req = req.Normalize()
+	if list == nil {
+		list = []T{}
+	}
+	size := int64(req.PageSize)
+	return &PageResult[T]{
+		List:     list,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Pages:    (total + size - 1) / size,
+	}
+}
+
+// HasMore 是否还有下一页
+func (r *PageResult[T]) HasMore() bool {
+	return int64(r.Page) < r.Pages
+}
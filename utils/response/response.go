@@ -0,0 +1,86 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+)
+
+// 通用业务码，业务方可在此基础上自定义
+const (
+	CodeOK           = 0
+	CodeInvalidParam = 400
+	CodeUnauthorized = 401
+	CodeForbidden    = 403
+	CodeNotFound     = 404
+	CodeInternal     = 500
+)
+
+// Response 统一响应结构，traceId 自动取自 context（与 logger 输出的 traceId 一致）
+type Response struct {
+	Code    int         `json:"code"`
+	Msg     string      `json:"msg"`
+	Data    interface{} `json:"data"`
+	TraceID string      `json:"traceId,omitempty"`
+}
+
+// CodeError 携带业务码的错误，FromError 会将其转换为对应的响应
+type CodeError struct {
+	Code       int
+	Msg        string
+	HTTPStatus int // 写回时的 HTTP 状态码，默认 200
+}
+
+// Error 实现 error 接口
+func (e *CodeError) Error() string {
+	return e.Msg
+}
+
+// NewError 创建业务错误
+func NewError(code int, msg string) *CodeError {
+	return &CodeError{Code: code, Msg: msg}
+}
+
+// OK 成功响应
+func OK(ctx context.Context, data interface{}) *Response {
+	return &Response{Code: CodeOK, Msg: "ok", Data: data, TraceID: logger.TraceIDFromContext(ctx)}
+}
+
+// Fail 失败响应
+func Fail(ctx context.Context, code int, msg string) *Response {
+	return &Response{Code: code, Msg: msg, TraceID: logger.TraceIDFromContext(ctx)}
+}
+
+// FromError 将 error 转换为失败响应，非 *CodeError 的错误统一返回 CodeInternal，避免泄露内部信息
+func FromError(ctx context.Context, err error) *Response {
+	var ce *CodeError
+	if errors.As(err, &ce) {
+		return Fail(ctx, ce.Code, ce.Msg)
+	}
+	return Fail(ctx, CodeInternal, "internal error")
+}
+
+// WriteOK 写回成功响应
+func WriteOK(w http.ResponseWriter, r *http.Request, data interface{}) {
+	Write(w, http.StatusOK, OK(r.Context(), data))
+}
+
+// WriteError 写回失败响应，HTTP 状态码取自 CodeError.HTTPStatus，默认 200
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusOK
+	var ce *CodeError
+	if errors.As(err, &ce) && ce.HTTPStatus != 0 {
+		status = ce.HTTPStatus
+	}
+	Write(w, status, FromError(r.Context(), err))
+}
+
+// Write 以 JSON 写回响应
+func Write(w http.ResponseWriter, status int, resp *Response) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,48 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPage(t *testing.T) {
+	p := ParsePageRequest(url.Values{"page": {"3"}, "page_size": {"500"}})
+	assert.Equal(t, PageRequest{Page: 3, PageSize: MaxPageSize}, p)
+	assert.Equal(t, 200, p.Offset())
+	assert.Equal(t, 100, p.Limit())
+	assert.Equal(t, PageRequest{Page: 1, PageSize: DefaultPageSize}, ParsePageRequest(url.Values{"page": {"-1"}}))
+
+	r := NewPageResult[string](PageRequest{Page: 2, PageSize: 10}, nil, 21)
+	assert.Equal(t, int64(3), r.Pages)
+	assert.True(t, r.HasMore())
+	assert.NotNil(t, r.List)
+}
+
+func TestResponse(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "traceId", "t-123")
+
+	resp := OK(ctx, map[string]int{"id": 1})
+	assert.Equal(t, CodeOK, resp.Code)
+	assert.Equal(t, "t-123", resp.TraceID)
+
+	wrapped := fmt.Errorf("load user: %w", NewError(CodeNotFound, "user not found"))
+	assert.Equal(t, &Response{Code: CodeNotFound, Msg: "user not found", TraceID: "t-123"}, FromError(ctx, wrapped))
+	assert.Equal(t, CodeInternal, FromError(ctx, errors.New("db down")).Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	WriteError(rec, req, &CodeError{Code: CodeUnauthorized, Msg: "login required", HTTPStatus: http.StatusUnauthorized})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.JSONEq(t, `{"code":401,"msg":"login required","data":null,"traceId":"t-123"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	WriteOK(rec, req, NewPageResult(PageRequest{}, []int{1, 2}, 2))
+	assert.JSONEq(t, `{"code":0,"msg":"ok","data":{"list":[1,2],"total":2,"page":1,"pageSize":20,"pages":1},"traceId":"t-123"}`, rec.Body.String())
+}
@@ -0,0 +1,75 @@
+package sensitive
+
+// node 自动机节点，边为归一化后的字符（开启拼音容错时为一个音节）
+type node struct {
+	children map[string]int
+	fail     int
+	out      []int // 在该节点结束的词条下标，构建时合并了失配链上的输出
+}
+
+// automaton Aho-Corasick 自动机，构建完成后只读
+type automaton struct {
+	nodes   []node
+	words   []string
+	lengths []int // 词条归一化后的字符数
+}
+
+// newAutomaton 创建只有根节点的自动机
+func newAutomaton() *automaton {
+	return &automaton{nodes: []node{{}}}
+}
+
+// add 插入词条，归一化后相同的词条只保留第一个
+func (a *automaton) add(word string, toks []token) {
+	cur := 0
+	for _, t := range toks {
+		next, ok := a.nodes[cur].children[t.key]
+		if !ok {
+			if a.nodes[cur].children == nil {
+				a.nodes[cur].children = make(map[string]int)
+			}
+			a.nodes = append(a.nodes, node{})
+			next = len(a.nodes) - 1
+			a.nodes[cur].children[t.key] = next
+		}
+		cur = next
+	}
+	if len(a.nodes[cur].out) > 0 {
+		return
+	}
+	a.nodes[cur].out = []int{len(a.words)}
+	a.words = append(a.words, word)
+	a.lengths = append(a.lengths, len(toks))
+}
+
+// build 按层序计算失配指针并合并输出
+func (a *automaton) build() {
+	queue := make([]int, 0, len(a.nodes))
+	for _, child := range a.nodes[0].children {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for key, child := range a.nodes[cur].children {
+			a.nodes[child].fail = a.next(a.nodes[cur].fail, key)
+			if out := a.nodes[a.nodes[child].fail].out; len(out) > 0 {
+				a.nodes[child].out = append(append([]int(nil), a.nodes[child].out...), out...)
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// next 从 state 读入 key 后的状态
+func (a *automaton) next(state int, key string) int {
+	for {
+		if child, ok := a.nodes[state].children[key]; ok {
+			return child
+		}
+		if state == 0 {
+			return 0
+		}
+		state = a.nodes[state].fail
+	}
+}
@@ -0,0 +1,216 @@
+// Package sensitive 基于 Aho-Corasick 自动机的敏感词过滤，一次扫描即可匹配全部词条，用于评论、昵称等 UGC 审核；
+// 匹配前统一去除零宽字符、全角转半角并忽略大小写，可选忽略穿插的符号、形近字映射与同音字（拼音）容错，
+// 词库可通过 config.Watcher 热加载，替换词库期间不影响正在进行的匹配
+// 使用示例：
+//
+//	f := sensitive.New(words, sensitive.WithIgnoreSymbols())
+//	if f.Contains(comment) {
+//		comment = f.Replace(comment)
+//	}
+package sensitive
+
+import (
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/qingfeng-studio/go-utils/utils/textx"
+)
+
+// defaultMask Replace 默认使用的替换字符
+const defaultMask = '*'
+
+// Option 过滤器选项
+type Option func(*options)
+
+type options struct {
+	mask          rune
+	ignoreSymbols bool
+	variants      map[rune]rune
+	pinyin        func(r rune) string
+}
+
+// WithMask 设置 Replace 的替换字符，默认 *
+func WithMask(r rune) Option {
+	return func(o *options) { o.mask = r }
+}
+
+// WithIgnoreSymbols 匹配时跳过空白、标点与符号（含表情），使 "敏 感"、"敏*感" 也能命中 "敏感"
+func WithIgnoreSymbols() Option {
+	return func(o *options) { o.ignoreSymbols = true }
+}
+
+// WithVariants 形近字容错：匹配前把 key 映射为 value（如 '氵' -> '水'、'0' -> 'o'），
+// 词库与待检测文本使用同一映射，多次调用时合并
+func WithVariants(m map[rune]rune) Option {
+	return func(o *options) {
+		if o.variants == nil {
+			o.variants = make(map[rune]rune, len(m))
+		}
+		for k, v := range m {
+			o.variants[unicode.ToLower(k)] = unicode.ToLower(v)
+		}
+	}
+}
+
+// WithPinyin 同音字容错：fn 返回汉字的拼音（不带声调，如接入 go-pinyin），非汉字返回空串；
+// 开启后按读音匹配，"民感" 也能命中 "敏感"；多音字应固定返回同一个读音
+func WithPinyin(fn func(r rune) string) Option {
+	return func(o *options) { o.pinyin = fn }
+}
+
+// Match 一次命中
+type Match struct {
+	Word  string // 命中的词库词条
+	Text  string // 原文中命中的片段（可能包含被忽略的符号）
+	Start int    // 片段在原文中的起始字节偏移
+	End   int    // 片段在原文中的结束字节偏移（不含）
+}
+
+// Filter 敏感词过滤器，并发安全
+type Filter struct {
+	opts options
+	ac   atomic.Pointer[automaton]
+}
+
+// New 创建过滤器并加载词库，空白词条会被忽略
+func New(words []string, opts ...Option) *Filter {
+	f := &Filter{opts: options{mask: defaultMask}}
+	for _, opt := range opts {
+		opt(&f.opts)
+	}
+	f.Load(words)
+	return f
+}
+
+// Load 以新词库整体替换旧词库，构建完成后原子切换，可在运行中调用
+func (f *Filter) Load(words []string) {
+	ac := newAutomaton()
+	for _, w := range words {
+		w = strings.TrimSpace(w)
+		if toks := f.tokenize(w); len(toks) > 0 {
+			ac.add(w, toks)
+		}
+	}
+	ac.build()
+	f.ac.Store(ac)
+}
+
+// Len 当前词库的词条数
+func (f *Filter) Len() int {
+	return len(f.ac.Load().words)
+}
+
+// Contains 是否包含敏感词，命中第一个即返回
+func (f *Filter) Contains(text string) bool {
+	found := false
+	f.scan(text, func(Match) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// FindFirst 返回第一个命中（按结束位置），未命中时 ok 为 false
+func (f *Filter) FindFirst(text string) (m Match, ok bool) {
+	f.scan(text, func(hit Match) bool {
+		m, ok = hit, true
+		return false
+	})
+	return m, ok
+}
+
+// FindAll 返回全部命中，按结束位置排序；词条相互重叠时（如 "敏感" 与 "敏感词"）都会返回
+func (f *Filter) FindAll(text string) []Match {
+	var out []Match
+	f.scan(text, func(m Match) bool {
+		out = append(out, m)
+		return true
+	})
+	return out
+}
+
+// Replace 把命中的片段逐字替换为掩码字符，未命中时原样返回
+func (f *Filter) Replace(text string) string {
+	matches := f.FindAll(text)
+	if len(matches) == 0 {
+		return text
+	}
+	masked := make([]bool, len(text))
+	for _, m := range matches {
+		for i := m.Start; i < m.End; i++ {
+			masked[i] = true
+		}
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	for i, r := range text {
+		if masked[i] {
+			b.WriteRune(f.opts.mask)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// token 归一化后的单个字符及其在原文中的位置
+type token struct {
+	key        string
+	start, end int
+}
+
+// normalize 单个字符的归一化结果，ok 为 false 表示匹配时跳过该字符
+func (f *Filter) normalize(r rune) (string, bool) {
+	if textx.IsZeroWidth(r) || unicode.IsControl(r) {
+		return "", false
+	}
+	if f.opts.ignoreSymbols && (unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) || textx.IsEmoji(r)) {
+		return "", false
+	}
+	r = unicode.ToLower(textx.HalfWidthRune(r))
+	if v, ok := f.opts.variants[r]; ok {
+		r = v
+	}
+	if f.opts.pinyin != nil {
+		if p := f.opts.pinyin(r); p != "" {
+			return strings.ToLower(p), true
+		}
+	}
+	return string(r), true
+}
+
+// tokenize 将文本切分为归一化后的字符序列；位置按源文本的字节宽度计算，
+// 非法 UTF-8 字节解码为 U+FFFD 但只占 1 字节
+func (f *Filter) tokenize(s string) []token {
+	toks := make([]token, 0, len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if key, ok := f.normalize(r); ok {
+			toks = append(toks, token{key: key, start: i, end: i + size})
+		}
+		i += size
+	}
+	return toks
+}
+
+// scan 扫描文本，每次命中调用 fn，fn 返回 false 时停止
+func (f *Filter) scan(text string, fn func(Match) bool) {
+	ac := f.ac.Load()
+	if len(ac.words) == 0 || text == "" {
+		return
+	}
+	toks := f.tokenize(text)
+	state := 0
+	for i, t := range toks {
+		state = ac.next(state, t.key)
+		for _, w := range ac.nodes[state].out {
+			first := toks[i-ac.lengths[w]+1]
+			m := Match{Word: ac.words[w], Text: text[first.start:t.end], Start: first.start, End: t.end}
+			if !fn(m) {
+				return
+			}
+		}
+	}
+}
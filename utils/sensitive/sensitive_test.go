@@ -0,0 +1,82 @@
+package sensitive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qingfeng-studio/go-utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Match(t *testing.T) {
+	f := New([]string{"敏感", "敏感词", "感冒", "Bad", "  ", "BAD"})
+	assert.Equal(t, 4, f.Len(), "blank and normalized duplicates are ignored")
+
+	assert.False(t, f.Contains("正常内容"))
+	assert.True(t, f.Contains("这是ｂａｄ内容"), "full-width and case folded")
+	assert.True(t, f.Contains("敏\u200B感"), "zero-width chars skipped")
+
+	matches := f.FindAll("一个敏感词，别感冒")
+	require.Len(t, matches, 3)
+	assert.Equal(t, "敏感", matches[0].Word)
+	assert.Equal(t, "敏感词", matches[1].Word)
+	assert.Equal(t, "感冒", matches[2].Word)
+	assert.Equal(t, "感冒", "一个敏感词，别感冒"[matches[2].Start:matches[2].End])
+
+	m, ok := f.FindFirst("bad敏感")
+	assert.True(t, ok)
+	assert.Equal(t, "Bad", m.Word)
+	assert.Equal(t, "bad", m.Text)
+
+	assert.Equal(t, "一个***，别**", f.Replace("一个敏感词，别感冒"))
+	assert.Equal(t, "无", f.Replace("无"))
+}
+
+// TestFilter_InvalidUTF8 非法字节按 1 字节定位，不能越界
+func TestFilter_InvalidUTF8(t *testing.T) {
+	f := New([]string{"坏\uFFFD"})
+	assert.Equal(t, "**", f.Replace("坏\xff"))
+	m, ok := f.FindFirst("好坏\xff")
+	require.True(t, ok)
+	assert.Equal(t, "坏\xff", m.Text)
+}
+
+func TestFilter_Options(t *testing.T) {
+	f := New([]string{"敏感"}, WithIgnoreSymbols(), WithMask('#'))
+	m, ok := f.FindFirst("说敏 * 感了")
+	require.True(t, ok)
+	assert.Equal(t, "敏 * 感", m.Text)
+	assert.Equal(t, "说#####了", f.Replace("说敏 * 感了"))
+	assert.False(t, New([]string{"敏感"}).Contains("敏 感"), "symbols are significant by default")
+
+	f = New([]string{"水果"}, WithVariants(map[rune]rune{'氺': '水', '菓': '果'}))
+	assert.True(t, f.Contains("氺菓"))
+
+	py := map[rune]string{'敏': "min", '民': "min", '感': "gan", '赶': "gan"}
+	f = New([]string{"敏感"}, WithPinyin(func(r rune) string { return py[r] }))
+	assert.True(t, f.Contains("民赶"))
+	assert.False(t, f.Contains("敏锐"))
+}
+
+func TestBind(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "words.txt")
+	require.NoError(t, os.WriteFile(p, []byte("# 词库\n敏感\n\n违禁\n"), 0o644))
+	w, err := config.NewWatcher[Dictionary](p, config.WithLoader(LineLoader))
+	require.NoError(t, err)
+
+	f := New(nil)
+	Bind(w, f, func(d *Dictionary) []string { return d.Words })
+	assert.Equal(t, 2, f.Len())
+	assert.True(t, f.Contains("违禁品"))
+
+	require.NoError(t, os.WriteFile(p, []byte("新词\n"), 0o644))
+	changed, err := w.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	assert.False(t, f.Contains("违禁品"))
+	assert.True(t, f.Contains("一个新词"))
+
+	assert.Error(t, LineLoader(p, &struct{}{}))
+}
@@ -0,0 +1,59 @@
+package sensitive
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/qingfeng-studio/go-utils/config"
+)
+
+// Dictionary 词库文件的结构，YAML 词库格式为 words: [...]；纯文本词库配合 LineLoader 使用
+type Dictionary struct {
+	Words []string `json:"words" yaml:"words"`
+}
+
+// LineLoader 按行读取纯文本词库的 config.Loader：每行一个词条，忽略空行与 # 开头的注释行，out 须为 *Dictionary
+//
+//	w, err := config.NewWatcher[sensitive.Dictionary]("words.txt", config.WithLoader(sensitive.LineLoader))
+func LineLoader(path string, out interface{}) error {
+	dict, ok := out.(*Dictionary)
+	if !ok {
+		return fmt.Errorf("sensitive: LineLoader expects *Dictionary, got %T", out)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+	dict.Words = dict.Words[:0]
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		dict.Words = append(dict.Words, line)
+	}
+	return sc.Err()
+}
+
+// Bind 用 Watcher 的当前配置加载词库，并在配置变更时热加载；words 从配置中取出词条，
+// 返回 nil 时保持原词库不变（如词库字段未改动）
+// 使用示例：
+//
+//	w, _ := config.NewWatcher[sensitive.Dictionary]("words.yaml")
+//	f := sensitive.New(nil, sensitive.WithIgnoreSymbols())
+//	sensitive.Bind(w, f, func(d *sensitive.Dictionary) []string { return d.Words })
+//	go w.Run(ctx)
+func Bind[T any](w *config.Watcher[T], f *Filter, words func(cfg *T) []string) {
+	if list := words(w.Current()); list != nil {
+		f.Load(list)
+	}
+	w.OnReload(func(_, cfg *T) {
+		if list := words(cfg); list != nil {
+			f.Load(list)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+	"go.uber.org/zap"
+)
+
+// Chain 按传入顺序由外到内包裹中间件，即 mws[0] 最先处理请求
+func Chain(h http.Handler, mws ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// Recovery 捕获 handler 的 panic，记录错误日志与调用栈并返回 500；
+// http.ErrAbortHandler 按标准库约定继续向上抛出以中断连接
+func Recovery(l *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+				l.Error(r.Context(), "server: panic recovered",
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("panic", fmt.Sprint(p)),
+					zap.ByteString("stack", debug.Stack()))
+				if rw, ok := w.(*responseRecorder); !ok || !rw.wroteHeader {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AccessLog 每个请求结束后记录一条访问日志：方法、路径、状态码、响应字节数、耗时、客户端 IP 与 User-Agent；
+// 查询参数只记录参数名，值替换为 ***，避免 token、签名等敏感值写入日志；5xx 使用 error 级别，其余为 info
+func AccessLog(l *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			ip := r.RemoteAddr
+			if host, _, err := net.SplitHostPort(ip); err == nil {
+				ip = host
+			}
+			fields := []zap.Field{
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", rec.status),
+				zap.Int64("bytes", rec.bytes),
+				zap.Duration("latency", time.Since(start)),
+				zap.String("clientIp", ip),
+				zap.String("userAgent", r.UserAgent()),
+			}
+			if r.URL.RawQuery != "" {
+				fields = append(fields, zap.String("query", redactQuery(r.URL.RawQuery)))
+			}
+			if rec.status >= http.StatusInternalServerError {
+				l.Error(r.Context(), "http request", fields...)
+				return
+			}
+			l.Info(r.Context(), "http request", fields...)
+		})
+	}
+}
+
+// redactQuery 保留查询参数名（按原始顺序），值替换为 ***
+func redactQuery(raw string) string {
+	parts := strings.Split(raw, "&")
+	for i, part := range parts {
+		key, _, _ := strings.Cut(part, "=")
+		parts[i] = key + "=***"
+	}
+	return strings.Join(parts, "&")
+}
+
+// responseRecorder 记录状态码与响应字节数，Unwrap 使 http.ResponseController 仍可使用 Flush 等能力
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = code, true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush 实现 http.Flusher，兼容直接做类型断言的流式 handler
+func (r *responseRecorder) Flush() {
+	r.wroteHeader = true
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap 返回原始 ResponseWriter
+func (r *responseRecorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
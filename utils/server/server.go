@@ -0,0 +1,344 @@
+// Package server http.Server 的封装：默认的超时配置、TLS、HTTP/HTTPS 双监听、
+// 与健康检查联动的优雅退出，以及基于 logger 的 panic 恢复与访问日志中间件
+// 使用示例：
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+//	defer stop()
+//	srv := server.New(server.Config{Addr: ":8080", DrainDelay: 5 * time.Second}, mux, server.WithHealth(checker))
+//	if err := srv.Run(ctx); err != nil {
+//		logger.Error(ctx, "server exited", zap.Error(err))
+//	}
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+	"github.com/qingfeng-studio/go-utils/utils/health"
+	"go.uber.org/zap"
+)
+
+// 默认值
+const (
+	defaultAddr              = ":8080"
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultReadTimeout       = 30 * time.Second
+	defaultWriteTimeout      = 30 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+	defaultShutdownTimeout   = 15 * time.Second
+	defaultMaxHeaderBytes    = 1 << 20
+)
+
+// ErrNoTLSCertificate 配置了 HTTPS 监听但没有证书
+var ErrNoTLSCertificate = errors.New("server: tls_addr requires cert_file/key_file or WithTLSConfig")
+
+// Config 服务配置，时长为 0 时使用默认值
+type Config struct {
+	Addr     string `json:"addr" yaml:"addr"`           // HTTP 监听地址，默认 :8080；只开 HTTPS 时设为 "-"
+	TLSAddr  string `json:"tls_addr" yaml:"tls_addr"`   // HTTPS 监听地址，为空表示不开启
+	CertFile string `json:"cert_file" yaml:"cert_file"` // PEM 证书，也可通过 WithTLSConfig 提供
+	KeyFile  string `json:"key_file" yaml:"key_file"`
+
+	RedirectHTTP bool `json:"redirect_http" yaml:"redirect_http"` // 开启 HTTPS 时，HTTP 监听只做 301 跳转
+
+	ReadHeaderTimeout time.Duration `json:"read_header_timeout" yaml:"read_header_timeout"` // 默认 5 秒，防止慢速攻击
+	ReadTimeout       time.Duration `json:"read_timeout" yaml:"read_timeout"`               // 默认 30 秒
+	WriteTimeout      time.Duration `json:"write_timeout" yaml:"write_timeout"`             // 默认 30 秒，流式接口需调大或设为负数关闭
+	IdleTimeout       time.Duration `json:"idle_timeout" yaml:"idle_timeout"`               // 默认 120 秒
+	MaxHeaderBytes    int           `json:"max_header_bytes" yaml:"max_header_bytes"`       // 默认 1MB
+
+	DrainDelay      time.Duration `json:"drain_delay" yaml:"drain_delay"`           // 退出时标记未就绪后等待的时间，让负载均衡先摘除流量
+	ShutdownTimeout time.Duration `json:"shutdown_timeout" yaml:"shutdown_timeout"` // 等待进行中请求完成的最长时间，默认 15 秒
+}
+
+// options Server 选项
+type options struct {
+	log         *logger.Logger
+	tlsConfig   *tls.Config
+	health      *health.Checker
+	middlewares []func(http.Handler) http.Handler
+	noDefault   bool
+}
+
+// Option 函数式选项
+type Option func(*options)
+
+// WithLogger 指定访问日志与错误日志使用的 logger，默认 logger.Default()
+func WithLogger(l *logger.Logger) Option {
+	return func(o *options) { o.log = l }
+}
+
+// WithTLSConfig 使用自定义 TLS 配置（如证书热更新的 GetCertificate），优先于 cert_file/key_file
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *options) { o.tlsConfig = cfg }
+}
+
+// WithHealth 关联健康检查，退出开始时 MarkShuttingDown；
+// 监听成功不代表服务就绪，使用 health.WithStartupGate 时由调用方在预热等启动工作完成后调用 SetReady(true)
+func WithHealth(h *health.Checker) Option {
+	return func(o *options) { o.health = h }
+}
+
+// WithMiddleware 追加中间件，按传入顺序由外到内包裹，位于默认的访问日志与恢复中间件之内
+func WithMiddleware(mws ...func(http.Handler) http.Handler) Option {
+	return func(o *options) { o.middlewares = append(o.middlewares, mws...) }
+}
+
+// WithoutDefaultMiddleware 不使用默认的访问日志与 panic 恢复中间件
+func WithoutDefaultMiddleware() Option {
+	return func(o *options) { o.noDefault = true }
+}
+
+// Server 支持 HTTP/HTTPS 双监听与优雅退出的 HTTP 服务
+type Server struct {
+	cfg  Config
+	opts options
+
+	http  *http.Server // Addr 为 "-" 时为 nil
+	https *http.Server // 未开启 HTTPS 时为 nil
+
+	mu        sync.Mutex
+	listeners []net.Listener
+	tlsPort   string // HTTPS 实际监听的端口，用于跳转
+	started   bool
+	errc      chan error
+
+	onShutdown []func(ctx context.Context)
+}
+
+// New 创建服务，handler 按 WithMiddleware 与默认中间件包裹
+func New(cfg Config, handler http.Handler, opts ...Option) *Server {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.log == nil {
+		o.log = logger.Default()
+	}
+	applyDefaults(&cfg)
+
+	h := Chain(handler, o.middlewares...)
+	if !o.noDefault {
+		h = Chain(h, AccessLog(o.log), Recovery(o.log))
+	}
+	s := &Server{cfg: cfg, opts: o}
+	if cfg.TLSAddr != "" {
+		s.https = s.newHTTPServer(h)
+	}
+	if cfg.Addr != "-" {
+		plain := h
+		if s.https != nil && cfg.RedirectHTTP {
+			plain = http.HandlerFunc(s.redirectHTTPS)
+		}
+		s.http = s.newHTTPServer(plain)
+	}
+	return s
+}
+
+// applyDefaults 填充默认值，负数的超时表示不限制
+func applyDefaults(cfg *Config) {
+	if cfg.Addr == "" {
+		cfg.Addr = defaultAddr
+	}
+	fill := func(d *time.Duration, def time.Duration) {
+		switch {
+		case *d == 0:
+			*d = def
+		case *d < 0:
+			*d = 0
+		}
+	}
+	fill(&cfg.ReadHeaderTimeout, defaultReadHeaderTimeout)
+	fill(&cfg.ReadTimeout, defaultReadTimeout)
+	fill(&cfg.WriteTimeout, defaultWriteTimeout)
+	fill(&cfg.IdleTimeout, defaultIdleTimeout)
+	fill(&cfg.ShutdownTimeout, defaultShutdownTimeout)
+	if cfg.MaxHeaderBytes <= 0 {
+		cfg.MaxHeaderBytes = defaultMaxHeaderBytes
+	}
+}
+
+// newHTTPServer 按配置创建 http.Server，内部错误日志写入 logger
+func (s *Server) newHTTPServer(h http.Handler) *http.Server {
+	return &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: s.cfg.ReadHeaderTimeout,
+		ReadTimeout:       s.cfg.ReadTimeout,
+		WriteTimeout:      s.cfg.WriteTimeout,
+		IdleTimeout:       s.cfg.IdleTimeout,
+		MaxHeaderBytes:    s.cfg.MaxHeaderBytes,
+		ErrorLog:          logger.NewStdLog(s.opts.log, "warn"),
+	}
+}
+
+// OnShutdown 注册退出回调，在所有请求处理完成后按注册顺序执行，适合关闭数据库连接池等资源
+func (s *Server) OnShutdown(fn func(ctx context.Context)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onShutdown = append(s.onShutdown, fn)
+}
+
+// Start 绑定监听端口并在后台处理请求，端口占用等错误同步返回；重复调用返回错误
+func (s *Server) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return errors.New("server: already started")
+	}
+	var tlsLn, plainLn net.Listener
+	if s.https != nil {
+		tlsCfg, err := s.tlsConfig()
+		if err != nil {
+			return err
+		}
+		ln, err := net.Listen("tcp", s.cfg.TLSAddr)
+		if err != nil {
+			return err
+		}
+		tlsLn = tls.NewListener(ln, tlsCfg)
+		_, s.tlsPort, _ = net.SplitHostPort(ln.Addr().String())
+	}
+	if s.http != nil {
+		ln, err := net.Listen("tcp", s.cfg.Addr)
+		if err != nil {
+			if tlsLn != nil {
+				tlsLn.Close()
+			}
+			return err
+		}
+		plainLn = ln
+	}
+
+	s.started = true
+	s.errc = make(chan error, 2)
+	serve := func(srv *http.Server, ln net.Listener, scheme string) {
+		s.listeners = append(s.listeners, ln)
+		s.opts.log.Info(context.Background(), "server: listening", zap.String("scheme", scheme), zap.String("addr", ln.Addr().String()))
+		go func() {
+			if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.errc <- err
+			}
+		}()
+	}
+	if plainLn != nil {
+		serve(s.http, plainLn, "http")
+	}
+	if tlsLn != nil {
+		serve(s.https, tlsLn, "https")
+	}
+	return nil
+}
+
+// tlsConfig 构造 HTTPS 使用的 TLS 配置，最低 TLS 1.2
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.opts.tlsConfig != nil {
+		cfg = s.opts.tlsConfig.Clone()
+		if cfg.MinVersion == 0 {
+			cfg.MinVersion = tls.VersionTLS12
+		}
+	}
+	if len(cfg.Certificates) == 0 && cfg.GetCertificate == nil {
+		if s.cfg.CertFile == "" || s.cfg.KeyFile == "" {
+			return nil, ErrNoTLSCertificate
+		}
+		cert, err := tls.LoadX509KeyPair(s.cfg.CertFile, s.cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if len(cfg.NextProtos) == 0 {
+		cfg.NextProtos = []string{"h2", "http/1.1"}
+	}
+	return cfg, nil
+}
+
+// Addrs 返回实际监听的地址（HTTP 在前），Start 之前为空；监听 ":0" 时可用于获取分配的端口
+func (s *Server) Addrs() []net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	addrs := make([]net.Addr, len(s.listeners))
+	for i, ln := range s.listeners {
+		addrs[i] = ln.Addr()
+	}
+	return addrs
+}
+
+// Run 启动服务并阻塞，直到 ctx 结束（通常由 signal.NotifyContext 触发）或监听出错，随后优雅退出；
+// 正常退出返回 nil
+func (s *Server) Run(ctx context.Context) error {
+	if err := s.Start(); err != nil {
+		return err
+	}
+	var serveErr error
+	select {
+	case <-ctx.Done():
+	case serveErr = <-s.errc:
+		s.opts.log.Error(ctx, "server: serve failed", zap.Error(serveErr))
+	}
+	sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.cfg.DrainDelay+s.cfg.ShutdownTimeout)
+	defer cancel()
+	if err := s.Shutdown(sctx); err != nil {
+		return errors.Join(serveErr, err)
+	}
+	return serveErr
+}
+
+// Shutdown 优雅退出：标记未就绪并等待 DrainDelay，停止接受新连接并等待进行中的请求完成，
+// 最后执行 OnShutdown 回调；ctx 到期时强制关闭剩余连接
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.opts.log.Info(ctx, "server: shutting down", zap.Duration("drainDelay", s.cfg.DrainDelay))
+	if s.opts.health != nil {
+		s.opts.health.MarkShuttingDown()
+	}
+	if s.cfg.DrainDelay > 0 {
+		select {
+		case <-time.After(s.cfg.DrainDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, srv := range []*http.Server{s.http, s.https} {
+		if srv == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, srv *http.Server) {
+			defer wg.Done()
+			if err := srv.Shutdown(ctx); err != nil {
+				errs[i] = err
+				_ = srv.Close()
+			}
+		}(i, srv)
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	hooks := append([]func(context.Context){}, s.onShutdown...)
+	s.mu.Unlock()
+	for _, fn := range hooks {
+		fn(ctx)
+	}
+	return errors.Join(errs...)
+}
+
+// redirectHTTPS 将请求 301 跳转到 HTTPS 端口，保留主机名、路径与查询参数
+func (s *Server) redirectHTTPS(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if s.tlsPort != "" && s.tlsPort != "443" {
+		host = net.JoinHostPort(host, s.tlsPort)
+	}
+	http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/logger"
+	"github.com/qingfeng-studio/go-utils/utils/health"
+)
+
+// testLogger 日志写入测试临时目录，返回 logger 与日志文件路径
+func testLogger(t *testing.T) (*logger.Logger, string) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	return logger.New(&logger.Config{FileName: path}), path
+}
+
+func TestServer_RunAndShutdown(t *testing.T) {
+	l, logFile := testLogger(t)
+	h := health.New(health.WithStartupGate())
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		io.WriteString(w, "done")
+	})
+	mux.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) { panic("boom") })
+
+	srv := New(Config{Addr: "127.0.0.1:0"}, mux, WithLogger(l), WithHealth(h))
+	closed := make(chan struct{})
+	srv.OnShutdown(func(ctx context.Context) { close(closed) })
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- srv.Run(ctx) }()
+
+	var base string
+	for i := 0; i < 100 && base == ""; i++ {
+		if addrs := srv.Addrs(); len(addrs) > 0 {
+			base = "http://" + addrs[0].String()
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if base == "" {
+		t.Fatal("server did not start")
+	}
+	// 启动门控由调用方释放，监听成功后仍未就绪
+	if r := h.Readiness(context.Background()); r.Status != health.StatusDown {
+		t.Errorf("readiness before SetReady = %s", r.Status)
+	}
+	h.SetReady(true)
+	if r := h.Readiness(context.Background()); r.Status != health.StatusUp {
+		t.Errorf("readiness after SetReady = %s", r.Status)
+	}
+
+	resp, err := http.Get(base + "/panic?token=s3cret&page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("panic status = %d", resp.StatusCode)
+	}
+
+	// 退出时等待进行中的请求完成
+	slow := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(base + "/slow")
+		if err != nil {
+			slow <- err.Error()
+			return
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		slow <- string(body)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	if r := h.Readiness(context.Background()); r.Status != health.StatusDown {
+		t.Error("readiness should fail once shutdown begins")
+	}
+	close(release)
+	if got := <-slow; got != "done" {
+		t.Errorf("in-flight request = %q", got)
+	}
+	if err := <-runErr; err != nil {
+		t.Errorf("Run = %v", err)
+	}
+	<-closed
+
+	data, _ := os.ReadFile(logFile)
+	if strings.Contains(string(data), "s3cret") {
+		t.Error("query values must not be logged")
+	}
+	for _, want := range []string{`"msg":"http request"`, `"path":"/panic"`, `"query":"token=***&page=***"`, `"status":500`, `"msg":"server: panic recovered"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("log missing %s", want)
+		}
+	}
+}
+
+func TestServer_DualListeners(t *testing.T) {
+	l, _ := testLogger(t)
+	ts := httptest.NewUnstartedServer(nil)
+	ts.StartTLS()
+	defer ts.Close()
+
+	srv := New(Config{Addr: "127.0.0.1:0", TLSAddr: "127.0.0.1:0", RedirectHTTP: true},
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "secure") }),
+		WithLogger(l), WithTLSConfig(ts.TLS))
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+	addrs := srv.Addrs()
+	if len(addrs) != 2 {
+		t.Fatalf("addrs = %v", addrs)
+	}
+
+	client := ts.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := client.Get("http://" + addrs[0].String() + "/a?b=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	_, port, _ := strings.Cut(addrs[1].String(), ":")
+	if loc := resp.Header.Get("Location"); resp.StatusCode != http.StatusMovedPermanently || loc != "https://127.0.0.1:"+port+"/a?b=1" {
+		t.Errorf("redirect = %d %s", resp.StatusCode, loc)
+	}
+
+	resp, err = client.Get("https://" + addrs[1].String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "secure" {
+		t.Errorf("https body = %q", body)
+	}
+
+	if err := New(Config{Addr: "-", TLSAddr: "127.0.0.1:0"}, http.NotFoundHandler(), WithLogger(l)).Start(); err != ErrNoTLSCertificate {
+		t.Errorf("Start without certificate = %v", err)
+	}
+}
@@ -0,0 +1,404 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultPartSize 默认分片大小
+	defaultPartSize = 8 << 20
+	// minPartSize S3/OSS 要求除最后一片外每片至少 5MiB
+	minPartSize = 5 << 20
+)
+
+// Error 服务端返回的错误
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	return fmt.Sprintf("storage: status=%d code=%s message=%s", e.StatusCode, e.Code, e.Message)
+}
+
+// Is 404 错误匹配 ErrNotFound
+func (e *Error) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// signer 请求签名算法
+type signer interface {
+	// sign 为请求添加签名头
+	sign(req *http.Request, bucket, key string, now time.Time)
+	// presign 为 URL 添加签名参数
+	presign(method string, u *url.URL, bucket, key string, expires time.Duration, now time.Time)
+}
+
+// bucket S3 与 OSS 共用的 REST 客户端，两者的对象、列举与分片上传接口一致，仅签名不同
+type bucket struct {
+	scheme    string
+	host      string
+	name      string
+	pathStyle bool
+	client    *http.Client
+	partSize  int64
+	signer    signer
+}
+
+// newBucket 解析 endpoint 并创建客户端
+func newBucket(endpoint, name string, pathStyle bool, client *http.Client, partSize int64, s signer) (*bucket, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("storage: invalid endpoint %q", endpoint)
+	}
+	if name == "" {
+		return nil, errors.New("storage: bucket is required")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if partSize <= 0 {
+		partSize = defaultPartSize
+	}
+	if partSize < minPartSize {
+		partSize = minPartSize
+	}
+	return &bucket{scheme: u.Scheme, host: u.Host, name: name, pathStyle: pathStyle, client: client, partSize: partSize, signer: s}, nil
+}
+
+// objectURL 构造对象 URL，key 为空表示 bucket 本身
+func (b *bucket) objectURL(key string, query url.Values) *url.URL {
+	u := &url.URL{Scheme: b.scheme, Host: b.host}
+	p := "/" + key
+	if b.pathStyle {
+		p = "/" + b.name
+		if key != "" {
+			p += "/" + key
+		}
+	} else {
+		u.Host = b.name + "." + b.host
+	}
+	u.Path = p
+	u.RawPath = escapePath(p)
+	u.RawQuery = encodeQuery(query)
+	return u
+}
+
+// do 发送签名请求，状态码 >= 300 时返回 *Error，成功时由调用方关闭响应体
+func (b *bucket) do(ctx context.Context, method, key string, query url.Values, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, b.objectURL(key, query).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	b.signer.sign(req, b.name, key, time.Now())
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, parseError(resp)
+	}
+	return resp, nil
+}
+
+// parseError 解析 XML 错误响应
+func parseError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	e := &Error{StatusCode: resp.StatusCode}
+	var x struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(data, &x) == nil {
+		e.Code, e.Message = x.Code, x.Message
+	}
+	if e.Code == "" {
+		e.Code = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
+
+// Put 实现 Storage
+func (b *bucket) Put(ctx context.Context, key string, r io.Reader, size int64, opts ...PutOption) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	o := newPutOptions(opts)
+	if o.contentType == "" {
+		o.contentType, r = DetectContentType(key, r)
+	}
+	header := o.headers()
+	if size >= 0 && size <= b.partSize {
+		return b.putObject(ctx, key, header, r, size)
+	}
+
+	// 长度未知或超过分片大小：先读一片，不足一片时直接普通上传
+	buf := make([]byte, b.partSize)
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return b.putObject(ctx, key, header, bytes.NewReader(buf[:n]), int64(n))
+	}
+	if err != nil {
+		return err
+	}
+	return b.multipartUpload(ctx, key, header, r, buf)
+}
+
+// putObject 普通上传
+func (b *bucket) putObject(ctx context.Context, key string, header http.Header, r io.Reader, size int64) error {
+	if size == 0 {
+		r = http.NoBody
+	}
+	resp, err := b.do(ctx, http.MethodPut, key, nil, header, r, size)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// completePart 完成分片上传时提交的分片信息
+type completePart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// multipartUpload 分片上传，first 为已读取的第一片；失败时中止上传以释放已上传的分片
+func (b *bucket) multipartUpload(ctx context.Context, key string, header http.Header, r io.Reader, first []byte) (err error) {
+	resp, err := b.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, header, nil, 0)
+	if err != nil {
+		return err
+	}
+	var initResult struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err = xml.NewDecoder(resp.Body).Decode(&initResult)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("storage: initiate multipart upload: %w", err)
+	}
+	uploadID := initResult.UploadID
+	defer func() {
+		if err != nil {
+			if resp, aerr := b.do(context.WithoutCancel(ctx), http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, nil, 0); aerr == nil {
+				resp.Body.Close()
+			}
+		}
+	}()
+
+	var parts []completePart
+	buf := first
+	for n := 1; ; n++ {
+		query := url.Values{"partNumber": {strconv.Itoa(n)}, "uploadId": {uploadID}}
+		resp, err := b.do(ctx, http.MethodPut, key, query, nil, bytes.NewReader(buf), int64(len(buf)))
+		if err != nil {
+			return fmt.Errorf("storage: upload part %d: %w", n, err)
+		}
+		resp.Body.Close()
+		parts = append(parts, completePart{PartNumber: n, ETag: resp.Header.Get("ETag")})
+
+		if len(buf) < int(b.partSize) {
+			break
+		}
+		buf = buf[:cap(buf)]
+		m, rerr := io.ReadFull(r, buf)
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil && rerr != io.ErrUnexpectedEOF {
+			return rerr
+		}
+		buf = buf[:m]
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name       `xml:"CompleteMultipartUpload"`
+		Parts   []completePart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+	resp, err = b.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}},
+		http.Header{"Content-Type": {"application/xml"}}, bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// S3 在处理过程中出错时可能返回 200 但响应体为 Error
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(data, []byte("<Error>")) {
+		return parseError(&http.Response{StatusCode: http.StatusInternalServerError, Body: io.NopCloser(bytes.NewReader(data))})
+	}
+	return nil
+}
+
+// Get 实现 Storage
+func (b *bucket) Get(ctx context.Context, key string) (io.ReadCloser, *Object, error) {
+	if err := validateKey(key); err != nil {
+		return nil, nil, err
+	}
+	resp, err := b.do(ctx, http.MethodGet, key, nil, nil, nil, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Body, objectFromHeader(key, resp), nil
+}
+
+// Stat 实现 Storage
+func (b *bucket) Stat(ctx context.Context, key string) (*Object, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	resp, err := b.do(ctx, http.MethodHead, key, nil, nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return objectFromHeader(key, resp), nil
+}
+
+// Delete 实现 Storage
+func (b *bucket) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	resp, err := b.do(ctx, http.MethodDelete, key, nil, nil, nil, 0)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// List 实现 Storage（ListObjects v1，S3 与 OSS 均支持）
+func (b *bucket) List(ctx context.Context, prefix, marker string, limit int) ([]Object, string, error) {
+	query := url.Values{"prefix": {prefix}}
+	if marker != "" {
+		query.Set("marker", marker)
+	}
+	if limit > 0 {
+		query.Set("max-keys", strconv.Itoa(limit))
+	}
+	resp, err := b.do(ctx, http.MethodGet, "", query, nil, nil, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		IsTruncated bool   `xml:"IsTruncated"`
+		NextMarker  string `xml:"NextMarker"`
+		Contents    []struct {
+			Key          string    `xml:"Key"`
+			LastModified time.Time `xml:"LastModified"`
+			ETag         string    `xml:"ETag"`
+			Size         int64     `xml:"Size"`
+		} `xml:"Contents"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, "", fmt.Errorf("storage: decode list result: %w", err)
+	}
+	objects := make([]Object, len(result.Contents))
+	for i, c := range result.Contents {
+		objects[i] = Object{Key: c.Key, Size: c.Size, ETag: strings.Trim(c.ETag, `"`), LastModified: c.LastModified}
+	}
+	next := ""
+	if result.IsTruncated {
+		next = result.NextMarker
+		if next == "" && len(objects) > 0 {
+			next = objects[len(objects)-1].Key
+		}
+	}
+	return objects, next, nil
+}
+
+// Presign 实现 Storage
+func (b *bucket) Presign(_ context.Context, method, key string, expires time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	if method != http.MethodGet && method != http.MethodPut {
+		return "", fmt.Errorf("%w: presign %s", ErrNotSupported, method)
+	}
+	u := b.objectURL(key, nil)
+	b.signer.presign(method, u, b.name, key, expires, time.Now())
+	return u.String(), nil
+}
+
+// objectFromHeader 从响应头提取对象元信息
+func objectFromHeader(key string, resp *http.Response) *Object {
+	obj := &Object{
+		Key:         key,
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+		ETag:        strings.Trim(resp.Header.Get("ETag"), `"`),
+	}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		obj.LastModified = t
+	}
+	return obj
+}
+
+// escapePath 按 RFC 3986 编码路径，保留 /
+func escapePath(p string) string {
+	segs := strings.Split(p, "/")
+	for i, s := range segs {
+		segs[i] = uriEncode(s)
+	}
+	return strings.Join(segs, "/")
+}
+
+// uriEncode RFC 3986 编码：仅保留非保留字符
+func uriEncode(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.ReplaceAll(s, "+", "%20")
+	s = strings.ReplaceAll(s, "*", "%2A")
+	return strings.ReplaceAll(s, "%7E", "~")
+}
+
+// encodeQuery 按 key 排序并以 RFC 3986 编码查询参数，签名与实际请求使用相同的编码
+func encodeQuery(q url.Values) string {
+	if len(q) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		vs := append([]string(nil), q[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			pairs = append(pairs, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
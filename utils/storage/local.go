@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignature 本地存储的预签名 URL 校验失败或已过期
+var ErrInvalidSignature = errors.New("storage: invalid signature")
+
+// LocalConfig 本地文件系统存储配置
+type LocalConfig struct {
+	Root    string // 存储根目录，不存在时自动创建
+	BaseURL string // Handler 对外的访问地址（如 https://files.example.com/storage），用于生成预签名 URL
+	Secret  string // 预签名 URL 的 HMAC 密钥，为空时 Presign 返回 ErrNotSupported
+
+	FileMode os.FileMode // 写入文件的权限，默认 0644
+}
+
+// Local 本地文件系统驱动，适合开发环境与单机部署；配合 Handler 可支持预签名上传下载。
+// 本地文件没有对象元信息，Put 的 PutOption（Content-Type、Content-Disposition、Cache-Control）会被忽略，
+// Content-Type 始终按扩展名推断
+type Local struct {
+	cfg LocalConfig
+}
+
+// NewLocal 创建本地存储驱动
+func NewLocal(cfg LocalConfig) (*Local, error) {
+	if cfg.Root == "" {
+		return nil, errors.New("storage: root is required")
+	}
+	if err := os.MkdirAll(cfg.Root, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: create root: %w", err)
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.FileMode == 0 {
+		cfg.FileMode = 0o644
+	}
+	return &Local{cfg: cfg}, nil
+}
+
+// path 返回对象的文件路径
+func (l *Local) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.cfg.Root, filepath.FromSlash(key)), nil
+}
+
+// Put 实现 Storage，先写入临时文件再重命名，读取方不会看到写了一半的文件；
+// 文件权限为 LocalConfig.FileMode，opts 不生效（见 Local）
+func (l *Local) Put(_ context.Context, key string, r io.Reader, _ int64, _ ...PutOption) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("storage: write %s: %w", key, err)
+	}
+	// CreateTemp 创建的文件权限为 0600
+	if err := tmp.Chmod(l.cfg.FileMode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("storage: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("storage: write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+	return nil
+}
+
+// Get 实现 Storage
+func (l *Local) Get(_ context.Context, key string) (io.ReadCloser, *Object, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, nil, localError(err)
+	}
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		f.Close()
+		return nil, nil, ErrNotFound
+	}
+	return f, localObject(key, fi), nil
+}
+
+// Stat 实现 Storage
+func (l *Local) Stat(_ context.Context, key string) (*Object, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		return nil, localError(err)
+	}
+	if fi.IsDir() {
+		return nil, ErrNotFound
+	}
+	return localObject(key, fi), nil
+}
+
+// Delete 实现 Storage
+func (l *Local) Delete(_ context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("storage: %w", err)
+	}
+	return nil
+}
+
+// List 实现 Storage，遍历根目录下的全部文件，适合文件数量不多的场景
+func (l *Local) List(_ context.Context, prefix, marker string, limit int) ([]Object, string, error) {
+	var objects []Object
+	err := filepath.WalkDir(l.cfg.Root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(l.cfg.Root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) || key <= marker {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, *localObject(key, fi))
+		return nil
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("storage: %w", err)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	next := ""
+	if limit > 0 && len(objects) > limit {
+		objects = objects[:limit]
+		next = objects[limit-1].Key
+	}
+	return objects, next, nil
+}
+
+// Presign 实现 Storage，生成由 Handler 校验的签名 URL
+func (l *Local) Presign(_ context.Context, method, key string, expires time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	if l.cfg.Secret == "" || l.cfg.BaseURL == "" {
+		return "", fmt.Errorf("%w: presign requires BaseURL and Secret", ErrNotSupported)
+	}
+	if method != http.MethodGet && method != http.MethodPut {
+		return "", fmt.Errorf("%w: presign %s", ErrNotSupported, method)
+	}
+	exp := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	q := url.Values{"expires": {exp}, "signature": {l.signature(method, key, exp)}}
+	return l.cfg.BaseURL + escapePath("/"+key) + "?" + q.Encode(), nil
+}
+
+// signature 计算预签名 URL 的签名
+func (l *Local) signature(method, key, expires string) string {
+	return hex.EncodeToString(hmacSHA256([]byte(l.cfg.Secret), method+"\n"+key+"\n"+expires))
+}
+
+// verify 校验预签名参数
+func (l *Local) verify(method, key string, q url.Values) error {
+	exp, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > exp || l.cfg.Secret == "" {
+		return ErrInvalidSignature
+	}
+	want := l.signature(method, key, q.Get("expires"))
+	if !hmac.Equal([]byte(want), []byte(q.Get("signature"))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Handler 处理预签名 URL 的下载（GET/HEAD）与上传（PUT），需挂载在 BaseURL 对应的路径下，
+// 如 mux.Handle("/storage/", http.StripPrefix("/storage", local.Handler()))
+func (l *Local) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		method := r.Method
+		if method == http.MethodHead {
+			method = http.MethodGet
+		}
+		if err := l.verify(method, key, r.URL.Query()); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			rc, obj, err := l.Get(r.Context(), key)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			defer rc.Close()
+			w.Header().Set("Content-Type", obj.ContentType)
+			http.ServeContent(w, r, path.Base(key), obj.LastModified, rc.(io.ReadSeeker))
+		case http.MethodPut:
+			if err := l.Put(r.Context(), key, r.Body, r.ContentLength); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// localObject 从文件信息构造对象元信息，Content-Type 按扩展名推断
+func localObject(key string, fi fs.FileInfo) *Object {
+	ct := mime.TypeByExtension(path.Ext(key))
+	if ct == "" {
+		ct = "application/octet-stream"
+	}
+	return &Object{Key: key, Size: fi.Size(), ContentType: ct, LastModified: fi.ModTime()}
+}
+
+// localError 转换文件系统错误
+func localError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	return fmt.Errorf("storage: %w", err)
+}
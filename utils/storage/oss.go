@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OSSConfig 阿里云 OSS 配置
+type OSSConfig struct {
+	Endpoint        string // 地域节点，如 oss-cn-hangzhou.aliyuncs.com，省略协议时默认 https
+	Bucket          string
+	AccessKeyID     string
+	AccessKeySecret string
+	PartSize        int64        // 分片大小，默认 8MiB，最小 5MiB
+	HTTPClient      *http.Client // 默认 http.DefaultClient
+}
+
+// OSS 阿里云 OSS 驱动（签名 V1），始终使用 bucket.endpoint 形式的地址
+type OSS struct {
+	*bucket
+}
+
+// NewOSS 创建阿里云 OSS 驱动
+func NewOSS(cfg OSSConfig) (*OSS, error) {
+	s := &ossV1{accessKey: cfg.AccessKeyID, secretKey: cfg.AccessKeySecret}
+	b, err := newBucket(cfg.Endpoint, cfg.Bucket, false, cfg.HTTPClient, cfg.PartSize, s)
+	if err != nil {
+		return nil, err
+	}
+	return &OSS{bucket: b}, nil
+}
+
+// ossSubResources 参与签名的子资源（本包用到的部分）
+var ossSubResources = map[string]bool{
+	"uploads": true, "uploadId": true, "partNumber": true, "acl": true, "security-token": true, "x-oss-process": true,
+	"response-content-type": true, "response-content-disposition": true, "response-cache-control": true,
+}
+
+// ossV1 OSS 签名 V1：HMAC-SHA1(secret, VERB\nContent-MD5\nContent-Type\nDate\nOSSHeaders+Resource)
+type ossV1 struct {
+	accessKey string
+	secretKey string
+}
+
+// sign 实现 signer
+func (s *ossV1) sign(req *http.Request, bucket, key string, now time.Time) {
+	date := now.UTC().Format(http.TimeFormat)
+	req.Header.Set("Date", date)
+
+	var ossHeaders []string
+	for k, v := range req.Header {
+		lk := strings.ToLower(k)
+		if strings.HasPrefix(lk, "x-oss-") {
+			ossHeaders = append(ossHeaders, lk+":"+strings.TrimSpace(strings.Join(v, ","))+"\n")
+		}
+	}
+	sort.Strings(ossHeaders)
+
+	stringToSign := req.Method + "\n" + req.Header.Get("Content-MD5") + "\n" + req.Header.Get("Content-Type") + "\n" +
+		date + "\n" + strings.Join(ossHeaders, "") + ossResource(bucket, key, req.URL.Query())
+	req.Header.Set("Authorization", "OSS "+s.accessKey+":"+s.signature(stringToSign))
+}
+
+// presign 实现 signer；PUT 上传时客户端不应设置 Content-Type（签名中为空）
+func (s *ossV1) presign(method string, u *url.URL, bucket, key string, expires time.Duration, now time.Time) {
+	exp := strconv.FormatInt(now.Add(expires).Unix(), 10)
+	q := u.Query()
+	stringToSign := method + "\n\n\n" + exp + "\n" + ossResource(bucket, key, q)
+	q.Set("OSSAccessKeyId", s.accessKey)
+	q.Set("Expires", exp)
+	q.Set("Signature", s.signature(stringToSign))
+	u.RawQuery = encodeQuery(q)
+}
+
+func (s *ossV1) signature(stringToSign string) string {
+	mac := hmac.New(sha1.New, []byte(s.secretKey))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ossResource 规范化资源：/bucket/key 加上按名称排序的子资源
+func ossResource(bucket, key string, q url.Values) string {
+	res := "/" + bucket + "/" + key
+	var subs []string
+	for k, vs := range q {
+		if !ossSubResources[k] {
+			continue
+		}
+		if len(vs) == 0 || vs[0] == "" {
+			subs = append(subs, k)
+		} else {
+			subs = append(subs, k+"="+vs[0])
+		}
+	}
+	if len(subs) > 0 {
+		sort.Strings(subs)
+		res += "?" + strings.Join(subs, "&")
+	}
+	return res
+}
@@ -0,0 +1,123 @@
+// Package storage 对象存储统一接口：Put/Get/Stat/Delete/List/Presign，内置 S3 兼容（AWS、MinIO、COS 等）、
+// 阿里云 OSS 与本地文件系统驱动；大文件与未知长度的流自动使用分片上传，未指定 Content-Type 时自动识别。
+// 预签名 URL 可直接交给前端下载，或通过 httpx 的 PutReader 流式上传，无需经过业务服务转发
+package storage
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound 对象不存在
+	ErrNotFound = errors.New("storage: object not found")
+	// ErrInvalidKey 对象 key 非法（为空、以 / 开头或包含 ..）
+	ErrInvalidKey = errors.New("storage: invalid key")
+	// ErrNotSupported 驱动不支持该操作
+	ErrNotSupported = errors.New("storage: operation not supported")
+)
+
+// Object 对象元信息
+type Object struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// Storage 对象存储接口
+type Storage interface {
+	// Put 上传对象，size 为内容长度，< 0 表示未知（将按分片读取上传）
+	Put(ctx context.Context, key string, r io.Reader, size int64, opts ...PutOption) error
+	// Get 下载对象，调用方负责关闭返回的 ReadCloser；不存在时返回 ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	// Stat 获取对象元信息，不存在时返回 ErrNotFound
+	Stat(ctx context.Context, key string) (*Object, error)
+	// Delete 删除对象，对象不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+	// List 按前缀分页列出对象（按 key 排序），marker 为上一页返回的 next，next 为空表示没有更多
+	List(ctx context.Context, prefix, marker string, limit int) (objects []Object, next string, err error)
+	// Presign 生成有效期为 expires 的预签名 URL，method 为 GET 或 PUT
+	Presign(ctx context.Context, method, key string, expires time.Duration) (string, error)
+}
+
+// putOptions 上传选项
+type putOptions struct {
+	contentType        string
+	contentDisposition string
+	cacheControl       string
+}
+
+// PutOption 上传选项
+type PutOption func(*putOptions)
+
+// WithContentType 指定 Content-Type，默认按扩展名推断，无法推断时嗅探内容
+func WithContentType(ct string) PutOption {
+	return func(o *putOptions) { o.contentType = ct }
+}
+
+// WithContentDisposition 指定 Content-Disposition，如 attachment; filename="report.xlsx"
+func WithContentDisposition(cd string) PutOption {
+	return func(o *putOptions) { o.contentDisposition = cd }
+}
+
+// WithCacheControl 指定 Cache-Control
+func WithCacheControl(cc string) PutOption {
+	return func(o *putOptions) { o.cacheControl = cc }
+}
+
+// newPutOptions 应用上传选项
+func newPutOptions(opts []PutOption) putOptions {
+	var o putOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// headers 转换为请求头
+func (o putOptions) headers() http.Header {
+	h := make(http.Header)
+	if o.contentType != "" {
+		h.Set("Content-Type", o.contentType)
+	}
+	if o.contentDisposition != "" {
+		h.Set("Content-Disposition", o.contentDisposition)
+	}
+	if o.cacheControl != "" {
+		h.Set("Cache-Control", o.cacheControl)
+	}
+	return h
+}
+
+// DetectContentType 识别内容类型：优先按 key 的扩展名，否则嗅探 r 的前 512 字节；
+// 返回的 Reader 包含被读取的部分，应替代 r 继续使用
+func DetectContentType(key string, r io.Reader) (string, io.Reader) {
+	if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+		return ct, r
+	}
+	br := bufio.NewReaderSize(r, 512)
+	head, _ := br.Peek(512)
+	return http.DetectContentType(head), br
+}
+
+// validateKey 校验对象 key，防止路径穿越
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if seg == ".." || seg == "." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
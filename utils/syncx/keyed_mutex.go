@@ -0,0 +1,98 @@
+// Package syncx 进程内并发原语：按 key 加锁的 KeyedMutex 与支持 context 的加权信号量
+package syncx
+
+import (
+	"context"
+	"sync"
+)
+
+// keyedEntry 单个 key 的锁，refs 为持有或等待该锁的数量，归零时回收
+type keyedEntry struct {
+	ch   chan struct{}
+	refs int
+}
+
+// KeyedMutex 按字符串 key 加锁，不同 key 互不阻塞，空闲 key 自动回收，零值可直接使用
+// 实用场景: 同一用户/订单的操作串行化，例如：
+//
+//	unlock := km.Lock("order:" + id)
+//	defer unlock()
+type KeyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedEntry
+}
+
+// acquireEntry 获取 key 对应的锁并增加引用
+func (k *KeyedMutex) acquireEntry(key string) *keyedEntry {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyedEntry)
+	}
+	e, ok := k.locks[key]
+	if !ok {
+		e = &keyedEntry{ch: make(chan struct{}, 1)}
+		k.locks[key] = e
+	}
+	e.refs++
+	return e
+}
+
+// releaseEntry 减少引用，无人使用时删除
+func (k *KeyedMutex) releaseEntry(key string, e *keyedEntry) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	e.refs--
+	if e.refs == 0 {
+		delete(k.locks, key)
+	}
+}
+
+// Lock 阻塞直到获得 key 的锁，返回解锁函数
+func (k *KeyedMutex) Lock(key string) (unlock func()) {
+	e := k.acquireEntry(key)
+	e.ch <- struct{}{}
+	return k.unlocker(key, e)
+}
+
+// LockContext 获取 key 的锁，ctx 取消时放弃等待并返回 ctx.Err()
+func (k *KeyedMutex) LockContext(ctx context.Context, key string) (unlock func(), err error) {
+	e := k.acquireEntry(key)
+	select {
+	case e.ch <- struct{}{}:
+		return k.unlocker(key, e), nil
+	case <-ctx.Done():
+		k.releaseEntry(key, e)
+		return nil, ctx.Err()
+	}
+}
+
+// TryLock 尝试获取 key 的锁，失败时立即返回 false
+func (k *KeyedMutex) TryLock(key string) (unlock func(), ok bool) {
+	e := k.acquireEntry(key)
+	select {
+	case e.ch <- struct{}{}:
+		return k.unlocker(key, e), true
+	default:
+		k.releaseEntry(key, e)
+		return nil, false
+	}
+}
+
+// Len 当前被持有或等待中的 key 数量
+func (k *KeyedMutex) Len() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return len(k.locks)
+}
+
+// unlocker 返回只会生效一次的解锁函数
+func (k *KeyedMutex) unlocker(key string, e *keyedEntry) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-e.ch
+			k.releaseEntry(key, e)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package syncx
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// semWaiter 等待中的获取请求
+type semWaiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// Semaphore 加权信号量，等待者按 FIFO 顺序获得资源（大请求不会被小请求饿死）
+// 实用场景: 限制并发下载的总字节数、按任务成本限制并发等
+type Semaphore struct {
+	size    int64
+	cur     int64
+	mu      sync.Mutex
+	waiters list.List
+}
+
+// NewSemaphore 创建总权重为 n 的信号量
+func NewSemaphore(n int64) *Semaphore {
+	return &Semaphore{size: n}
+}
+
+// Acquire 获取权重 n，资源不足时阻塞；ctx 取消时返回 ctx.Err() 且不占用资源
+// n 大于总权重时只会等到 ctx 取消
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	if n > s.size {
+		s.mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	w := semWaiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-w.ready:
+			// 取消与获得资源同时发生，视为获取成功后立即归还
+			s.cur -= n
+			s.notifyWaiters()
+		default:
+			isFront := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			// 队首被移除后，后面的等待者可能已经可以获取
+			if isFront && s.size > s.cur {
+				s.notifyWaiters()
+			}
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// TryAcquire 尝试获取权重 n，不阻塞
+func (s *Semaphore) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release 归还权重 n，归还超过已获取的权重会 panic
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("syncx: semaphore released more than held")
+	}
+	s.notifyWaiters()
+}
+
+// notifyWaiters 按 FIFO 唤醒可以获取资源的等待者（调用方需持有锁）
+func (s *Semaphore) notifyWaiters() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+		w := front.Value.(semWaiter)
+		if s.size-s.cur < w.n {
+			// 队首资源不足时不再唤醒后面的等待者，保证公平
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
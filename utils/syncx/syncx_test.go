@@ -0,0 +1,78 @@
+package syncx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	var km KeyedMutex
+	var counter, maxSeen int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := km.Lock("order:1")
+			defer unlock()
+			n := atomic.AddInt32(&counter, 1)
+			if n > atomic.LoadInt32(&maxSeen) {
+				atomic.StoreInt32(&maxSeen, n)
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&counter, -1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), maxSeen, "same key must be serialized")
+	assert.Equal(t, 0, km.Len(), "idle keys are released")
+
+	unlock := km.Lock("a")
+	_, ok := km.TryLock("a")
+	assert.False(t, ok)
+	unlockB, ok := km.TryLock("b")
+	assert.True(t, ok, "different keys do not block")
+	unlockB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := km.LockContext(ctx, "a")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	unlock()
+	unlock() // 重复调用无副作用
+	assert.Equal(t, 0, km.Len())
+}
+
+func TestSemaphore(t *testing.T) {
+	s := NewSemaphore(3)
+	ctx := context.Background()
+	assert.NoError(t, s.Acquire(ctx, 2))
+	assert.False(t, s.TryAcquire(2))
+
+	done := make(chan struct{})
+	go func() {
+		assert.NoError(t, s.Acquire(ctx, 3))
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, s.TryAcquire(1), "FIFO: waiter in front blocks new acquirers")
+
+	s.Release(2)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("waiter not woken")
+	}
+
+	cctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.Acquire(cctx, 1), context.DeadlineExceeded)
+	s.Release(3)
+	assert.True(t, s.TryAcquire(3))
+	assert.Panics(t, func() { s.Release(4) })
+}
@@ -0,0 +1,203 @@
+package templatex
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/qingfeng-studio/go-utils/utils/cnvalid"
+)
+
+// Funcs 返回预置的模板函数，参数顺序便于管道调用：
+//
+//	{{.CreatedAt | date "2006-01-02 15:04"}}  时间格式化，支持 time.Time、*time.Time、Unix 秒
+//	{{.Count | number 0}}                      千分位数字，参数为小数位数：1,234
+//	{{.Amount | money "¥"}}                    金额，两位小数：¥1,234.50
+//	{{.AmountFen | cents | money "¥"}}         以分为单位的整数金额转换为元
+//	{{.Mobile | maskMobile}}                   138****8000
+//	{{.IDCard | maskIDCard}}                   110101********1234
+//	{{.Email | maskEmail}}                     z***@example.com
+//	{{.Name | mask 1 0}}                       保留前 1 个、后 0 个字符：张**
+//	{{.Nickname | default "匿名"}}              空值时使用默认值
+//	{{.Title | truncate 20}}                   按字符截断并追加 …
+func Funcs() map[string]interface{} {
+	return map[string]interface{}{
+		"date":       formatDate,
+		"number":     formatNumber,
+		"money":      formatMoney,
+		"cents":      cents,
+		"maskMobile": cnvalid.MaskMobile,
+		"maskIDCard": cnvalid.MaskIDCard,
+		"maskEmail":  MaskEmail,
+		"mask":       Mask,
+		"default":    defaultValue,
+		"truncate":   truncate,
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"join":       func(sep string, items []string) string { return strings.Join(items, sep) },
+	}
+}
+
+// Mask 保留前 keepPrefix 个与后 keepSuffix 个字符，中间替换为 *（按字符计算，支持中文）
+func Mask(keepPrefix, keepSuffix int, s string) string {
+	runes := []rune(s)
+	if keepPrefix < 0 {
+		keepPrefix = 0
+	}
+	if keepSuffix < 0 {
+		keepSuffix = 0
+	}
+	if keepPrefix+keepSuffix >= len(runes) {
+		return s
+	}
+	return string(runes[:keepPrefix]) + strings.Repeat("*", len(runes)-keepPrefix-keepSuffix) + string(runes[len(runes)-keepSuffix:])
+}
+
+// MaskEmail 邮箱脱敏，保留用户名首字符与域名：z***@example.com
+func MaskEmail(s string) string {
+	at := strings.LastIndexByte(s, '@')
+	if at <= 0 {
+		return s
+	}
+	_, size := utf8.DecodeRuneInString(s)
+	return s[:size] + "***" + s[at:]
+}
+
+// FormatNumber 千分位格式化数字，decimals 为保留的小数位数
+func FormatNumber(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i:]
+	}
+	var b strings.Builder
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return sign + b.String() + frac
+}
+
+// formatDate 模板函数 date
+func formatDate(layout string, v interface{}) (string, error) {
+	switch t := v.(type) {
+	case time.Time:
+		if t.IsZero() {
+			return "", nil
+		}
+		return t.Format(layout), nil
+	case *time.Time:
+		if t == nil || t.IsZero() {
+			return "", nil
+		}
+		return t.Format(layout), nil
+	case nil:
+		return "", nil
+	}
+	sec, err := toFloat(v)
+	if err != nil {
+		return "", fmt.Errorf("date: %w", err)
+	}
+	return time.Unix(int64(sec), 0).Format(layout), nil
+}
+
+// formatNumber 模板函数 number
+func formatNumber(decimals int, v interface{}) (string, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return "", fmt.Errorf("number: %w", err)
+	}
+	return FormatNumber(f, decimals), nil
+}
+
+// formatMoney 模板函数 money
+func formatMoney(symbol string, v interface{}) (string, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return "", fmt.Errorf("money: %w", err)
+	}
+	s := FormatNumber(f, 2)
+	if strings.HasPrefix(s, "-") {
+		return "-" + symbol + s[1:], nil
+	}
+	return symbol + s, nil
+}
+
+// cents 模板函数 cents，分转元
+func cents(v interface{}) (float64, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return 0, fmt.Errorf("cents: %w", err)
+	}
+	return f / 100, nil
+}
+
+// defaultValue 模板函数 default
+func defaultValue(def, v interface{}) interface{} {
+	switch t := v.(type) {
+	case nil:
+		return def
+	case string:
+		if t == "" {
+			return def
+		}
+	}
+	return v
+}
+
+// truncate 模板函数 truncate，n 为负数时按 0 处理
+func truncate(n int, s string) string {
+	if n < 0 {
+		n = 0
+	}
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
+
+// toFloat 将常见数值类型转换为 float64
+func toFloat(v interface{}) (float64, error) {
+	switch t := v.(type) {
+	case int:
+		return float64(t), nil
+	case int8:
+		return float64(t), nil
+	case int16:
+		return float64(t), nil
+	case int32:
+		return float64(t), nil
+	case int64:
+		return float64(t), nil
+	case uint:
+		return float64(t), nil
+	case uint8:
+		return float64(t), nil
+	case uint16:
+		return float64(t), nil
+	case uint32:
+		return float64(t), nil
+	case uint64:
+		return float64(t), nil
+	case float32:
+		return float64(t), nil
+	case float64:
+		return t, nil
+	case json.Number:
+		return t.Float64()
+	case string:
+		return strconv.ParseFloat(t, 64)
+	case fmt.Stringer:
+		return strconv.ParseFloat(t.String(), 64)
+	}
+	return 0, fmt.Errorf("unsupported number type %T", v)
+}
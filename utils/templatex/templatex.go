@@ -0,0 +1,314 @@
+// Package templatex 模板渲染：预置日期/数字/金额格式化与脱敏函数，支持从 embed.FS 加载、
+// 布局（layout）与片段（partial），并提供 HTML 安全与纯文本两种模式，适用于邮件与通知内容
+package templatex
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+)
+
+// ErrTemplateNotFound 模板不存在
+var ErrTemplateNotFound = errors.New("templatex: template not found")
+
+// Mode 渲染模式
+type Mode int
+
+const (
+	// HTML 使用 html/template，输出按上下文自动转义，适合邮件正文
+	HTML Mode = iota
+	// Text 使用 text/template，不转义，适合短信、推送与纯文本邮件
+	Text
+)
+
+// Option 引擎选项
+type Option func(*Engine)
+
+// WithMode 设置渲染模式，默认 HTML
+func WithMode(m Mode) Option {
+	return func(e *Engine) { e.mode = m }
+}
+
+// WithFuncs 追加模板函数，同名时覆盖预置函数
+func WithFuncs(funcs map[string]interface{}) Option {
+	return func(e *Engine) {
+		for k, v := range funcs {
+			e.funcs[k] = v
+		}
+	}
+}
+
+// WithLayoutDir 设置布局目录，默认 layouts
+func WithLayoutDir(dir string) Option {
+	return func(e *Engine) { e.layoutDir = strings.Trim(dir, "/") }
+}
+
+// WithPartialDir 设置片段目录，默认 partials
+func WithPartialDir(dir string) Option {
+	return func(e *Engine) { e.partialDir = strings.Trim(dir, "/") }
+}
+
+// executor html/template 与 text/template 的公共执行接口
+type executor interface {
+	ExecuteTemplate(w io.Writer, name string, data interface{}) error
+}
+
+// Engine 模板引擎，加载后并发安全
+//
+// 约定：布局目录与片段目录下的文件对所有页面可见，以文件路径（如 layouts/base.html）为模板名；
+// 其余文件为页面，每个页面独立解析，因此不同页面可以各自 define 同名块而互不影响。
+// 布局通过 block 声明可被页面覆盖的区域：
+//
+//	layouts/base.html:  <html><body>{{block "content" .}}{{end}}{{template "partials/footer.html" .}}</body></html>
+//	emails/welcome.html: {{define "content"}}你好 {{.Name}}，订单金额 {{.Amount | money "¥"}}{{end}}
+//
+//	//go:embed templates
+//	var files embed.FS
+//	sub, _ := fs.Sub(files, "templates")
+//	e := templatex.New()
+//	err := e.ParseFS(sub, "**/*.html")
+//	html, err := e.RenderLayout("layouts/base.html", "emails/welcome.html", data)
+type Engine struct {
+	mode       Mode
+	funcs      map[string]interface{}
+	layoutDir  string
+	partialDir string
+
+	mu     sync.RWMutex
+	shared map[string]string // 布局与片段的源码，供后续添加的页面使用
+	pages  map[string]executor
+}
+
+// New 创建模板引擎
+func New(opts ...Option) *Engine {
+	e := &Engine{
+		funcs:      Funcs(),
+		layoutDir:  "layouts",
+		partialDir: "partials",
+		shared:     make(map[string]string),
+		pages:      make(map[string]executor),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// ParseFS 从文件系统加载模板，patterns 为 fs.Glob 模式，另支持 dir/**/*.ext 递归匹配；
+// 重复调用会替换同名页面
+func (e *Engine) ParseFS(fsys fs.FS, patterns ...string) error {
+	files, err := matchFiles(fsys, patterns)
+	if err != nil {
+		return err
+	}
+	shared := make(map[string]string)
+	pages := make(map[string]string)
+	for _, name := range files {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("templatex: read %s: %w", name, err)
+		}
+		if e.isShared(name) {
+			shared[name] = string(b)
+		} else {
+			pages[name] = string(b)
+		}
+	}
+	return e.add(shared, pages)
+}
+
+// Parse 直接添加页面模板，name 为模板名，常用于存放在数据库中的通知模板；
+// 页面可以使用此前 ParseFS 加载的布局与片段
+func (e *Engine) Parse(name, text string) error {
+	return e.add(nil, map[string]string{name: text})
+}
+
+// add 为每个页面解析一套独立的模板集（共享模板 + 页面自身），共享模板包含已加载的与新增的；
+// 任一模板解析失败时不修改引擎状态
+func (e *Engine) add(shared, pages map[string]string) error {
+	e.mu.RLock()
+	all := make(map[string]string, len(e.shared)+len(shared))
+	for name, src := range e.shared {
+		all[name] = src
+	}
+	e.mu.RUnlock()
+	for name, src := range shared {
+		all[name] = src
+	}
+
+	compiled := make(map[string]executor, len(pages))
+	for _, name := range sortedKeys(pages) {
+		t, err := e.compile(all, name, pages[name])
+		if err != nil {
+			return err
+		}
+		compiled[name] = t
+	}
+
+	e.mu.Lock()
+	e.shared = all
+	for name, t := range compiled {
+		e.pages[name] = t
+	}
+	e.mu.Unlock()
+	return nil
+}
+
+// compile 按模式解析共享模板与单个页面
+func (e *Engine) compile(shared map[string]string, name, text string) (executor, error) {
+	if e.mode == Text {
+		t := texttemplate.New(name).Funcs(e.funcs)
+		for _, s := range sortedKeys(shared) {
+			if _, err := t.New(s).Parse(shared[s]); err != nil {
+				return nil, fmt.Errorf("templatex: parse %s: %w", s, err)
+			}
+		}
+		if _, err := t.Parse(text); err != nil {
+			return nil, fmt.Errorf("templatex: parse %s: %w", name, err)
+		}
+		return t, nil
+	}
+	t := htmltemplate.New(name).Funcs(e.funcs)
+	for _, s := range sortedKeys(shared) {
+		if _, err := t.New(s).Parse(shared[s]); err != nil {
+			return nil, fmt.Errorf("templatex: parse %s: %w", s, err)
+		}
+	}
+	if _, err := t.Parse(text); err != nil {
+		return nil, fmt.Errorf("templatex: parse %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// Has 判断页面是否已加载
+func (e *Engine) Has(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, ok := e.pages[name]
+	return ok
+}
+
+// Names 返回已加载的页面名（已排序）
+func (e *Engine) Names() []string {
+	e.mu.RLock()
+	names := make([]string, 0, len(e.pages))
+	for name := range e.pages {
+		names = append(names, name)
+	}
+	e.mu.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// Execute 渲染页面并写入 w
+func (e *Engine) Execute(w io.Writer, name string, data interface{}) error {
+	return e.ExecuteLayout(w, "", name, data)
+}
+
+// ExecuteLayout 以 layout 为入口渲染页面，页面中 define 的块会替换布局中的同名 block；layout 为空时直接渲染页面
+func (e *Engine) ExecuteLayout(w io.Writer, layout, name string, data interface{}) error {
+	e.mu.RLock()
+	t, ok := e.pages[name]
+	e.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	entry := name
+	if layout != "" {
+		entry = layout
+	}
+	// 先渲染到缓冲区，避免出错时写出半截内容
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, entry, data); err != nil {
+		return fmt.Errorf("templatex: render %s: %w", name, err)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// Render 渲染页面并返回字符串
+func (e *Engine) Render(name string, data interface{}) (string, error) {
+	return e.RenderLayout("", name, data)
+}
+
+// RenderLayout 以 layout 为入口渲染页面并返回字符串
+func (e *Engine) RenderLayout(layout, name string, data interface{}) (string, error) {
+	var b strings.Builder
+	if err := e.ExecuteLayout(&b, layout, name, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// isShared 判断文件是否位于布局或片段目录
+func (e *Engine) isShared(name string) bool {
+	for _, dir := range []string{e.layoutDir, e.partialDir} {
+		if dir != "" && strings.HasPrefix(name, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys 返回排序后的键，保证解析顺序稳定
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// matchFiles 按模式匹配文件，结果去重并排序
+func matchFiles(fsys fs.FS, patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			files = append(files, name)
+		}
+	}
+	for _, pattern := range patterns {
+		if dir, rest, ok := strings.Cut(pattern, "**/"); ok {
+			root := strings.TrimSuffix(dir, "/")
+			if root == "" {
+				root = "."
+			}
+			err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				if ok, _ := path.Match(rest, path.Base(p)); ok {
+					add(p)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("templatex: walk %s: %w", root, err)
+			}
+			continue
+		}
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("templatex: glob %s: %w", pattern, err)
+		}
+		for _, m := range matches {
+			add(m)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("templatex: no files match %v", patterns)
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,84 @@
+package templatex
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"layouts/base.html":    {Data: []byte(`<h1>{{block "title" .}}默认标题{{end}}</h1>{{block "content" .}}{{end}}{{template "partials/footer.html" .}}`)},
+		"partials/footer.html": {Data: []byte(`<p>{{.Mobile | maskMobile}}</p>`)},
+		"emails/welcome.html":  {Data: []byte(`{{define "title"}}欢迎{{end}}{{define "content"}}<b>{{.Name}}</b> {{.Amount | money "¥"}}{{end}}`)},
+		"emails/bill.html":     {Data: []byte(`{{define "content"}}账单 {{.Fen | cents | money "¥"}}{{end}}`)},
+		"readme.txt":           {Data: []byte(`ignored`)},
+	}
+}
+
+func TestRenderLayout(t *testing.T) {
+	e := New()
+	require.NoError(t, e.ParseFS(testFS(), "**/*.html"))
+	assert.Equal(t, []string{"emails/bill.html", "emails/welcome.html"}, e.Names())
+
+	data := map[string]interface{}{"Name": "<script>", "Amount": 1234.5, "Mobile": "13800138000", "Fen": 99}
+	out, err := e.RenderLayout("layouts/base.html", "emails/welcome.html", data)
+	require.NoError(t, err)
+	assert.Equal(t, `<h1>欢迎</h1><b>&lt;script&gt;</b> ¥1,234.50<p>138****8000</p>`, out)
+
+	// 不同页面的同名块互不影响，未覆盖的块使用布局中的默认内容
+	out, err = e.RenderLayout("layouts/base.html", "emails/bill.html", data)
+	require.NoError(t, err)
+	assert.Equal(t, `<h1>默认标题</h1>账单 ¥0.99<p>138****8000</p>`, out)
+
+	_, err = e.Render("emails/missing.html", data)
+	assert.True(t, errors.Is(err, ErrTemplateNotFound))
+}
+
+func TestTextModeAndParse(t *testing.T) {
+	e := New(WithMode(Text), WithFuncs(map[string]interface{}{"shout": func(s string) string { return s + "!" }}))
+	require.NoError(t, e.ParseFS(testFS(), "partials/*.html"))
+	require.NoError(t, e.Parse("sms", `【青枫】{{.Name | shout}} 验证码已发送至 {{template "partials/footer.html" .}}`))
+
+	out, err := e.Render("sms", map[string]interface{}{"Name": "<张三>", "Mobile": "13800138000"})
+	require.NoError(t, err)
+	assert.Equal(t, `【青枫】<张三>! 验证码已发送至 <p>138****8000</p>`, out)
+
+	assert.Error(t, e.Parse("bad", `{{.Name`))
+	assert.False(t, e.Has("bad"))
+}
+
+func TestFuncs(t *testing.T) {
+	assert.Equal(t, "1,234,567", FormatNumber(1234567, 0))
+	assert.Equal(t, "-1,234.57", FormatNumber(-1234.567, 2))
+	assert.Equal(t, "999", FormatNumber(999, 0))
+
+	s, err := formatMoney("$", -12.5)
+	require.NoError(t, err)
+	assert.Equal(t, "-$12.50", s)
+	_, err = formatNumber(0, []int{1})
+	assert.Error(t, err)
+
+	ts := time.Date(2024, 5, 1, 8, 30, 0, 0, time.Local)
+	s, err = formatDate("2006-01-02 15:04", ts)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-05-01 08:30", s)
+	s, err = formatDate("2006-01-02", ts.Unix())
+	require.NoError(t, err)
+	assert.Equal(t, "2024-05-01", s)
+	s, err = formatDate("2006-01-02", time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "", s)
+
+	assert.Equal(t, "z***@example.com", MaskEmail("zhangsan@example.com"))
+	assert.Equal(t, "invalid", MaskEmail("invalid"))
+	assert.Equal(t, "张**", Mask(1, 0, "张三丰"))
+	assert.Equal(t, "ab", Mask(1, 1, "ab"))
+	assert.Equal(t, "匿名", defaultValue("匿名", ""))
+	assert.Equal(t, "你好…", truncate(2, "你好世界"))
+	assert.Equal(t, "…", truncate(-1, "你好世界"))
+}
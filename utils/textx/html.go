@@ -0,0 +1,100 @@
+// Package textx 内容入口的文本清洗：去除/转义 HTML、全角转半角、去除零宽字符、表情检测与按字符计长，
+// 供评论、昵称、工单等用户输入在落库前统一处理
+// 使用示例：
+//
+//	content := textx.Normalize(textx.UnescapeHTML(textx.StripHTML(req.Content))) // 落库纯文本，展示时由模板转义
+//	if textx.RuneLen(content) > 500 || textx.ContainsEmoji(req.Nickname) {
+//		return errInvalidContent
+//	}
+package textx
+
+import (
+	"html"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// EscapeHTML 转义 < > & ' "，适合原样展示用户输入
+func EscapeHTML(s string) string {
+	return html.EscapeString(s)
+}
+
+// UnescapeHTML 还原 HTML 实体，如 &lt; 还原为 <
+func UnescapeHTML(s string) string {
+	return html.UnescapeString(s)
+}
+
+// StripHTML 去除所有标签与注释，只保留文本内容；script、style、textarea 等元素的内容一并丢弃，
+// 块级元素与 <br> 处以换行分隔，避免相邻段落的文字粘连；结果去除首尾空白。
+// 返回值中的 < > & ' " 会重新转义为实体，因此 "&lt;script&gt;" 或被标签拆开的 "<<b>script>" 都不会还原成标签；
+// 结果是转义后的纯文本而不是白名单清洗过的富文本。需要原始纯文本（计长、检索、落库）时用 UnescapeHTML 还原，
+// 还原后的文本渲染时必须再次转义（html/template 会自动处理），在属性、JS、URL 等上下文中同样需要按上下文转义
+func StripHTML(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return html.EscapeString(strings.TrimSpace(s))
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	z := xhtml.NewTokenizer(strings.NewReader(s))
+	skip := 0 // 处于 script/style 等元素内部的层数
+	for {
+		tt := z.Next()
+		switch tt {
+		case xhtml.ErrorToken:
+			// 输入读完（strings.Reader 不会返回其他错误）
+			return html.EscapeString(strings.TrimSpace(b.String()))
+		case xhtml.TextToken:
+			if skip == 0 {
+				b.Write(z.Text())
+			}
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			name, _ := z.TagName()
+			a := atom.Lookup(name)
+			if dropContent(a) && tt == xhtml.StartTagToken {
+				skip++
+			} else if skip == 0 && isBreak(a) {
+				newline(&b)
+			}
+		case xhtml.EndTagToken:
+			name, _ := z.TagName()
+			a := atom.Lookup(name)
+			if dropContent(a) {
+				if skip > 0 {
+					skip--
+				}
+			} else if skip == 0 && isBreak(a) {
+				newline(&b)
+			}
+		}
+	}
+}
+
+// dropContent 内容不可展示或有执行风险的元素；textarea、noembed 等元素的内容按原始文本解析，
+// 其中的 "<script>" 会作为文本出现，必须整体丢弃
+func dropContent(a atom.Atom) bool {
+	switch a {
+	case atom.Script, atom.Style, atom.Iframe, atom.Object, atom.Noscript, atom.Template, atom.Title,
+		atom.Textarea, atom.Noembed, atom.Noframes, atom.Xmp, atom.Plaintext:
+		return true
+	}
+	return false
+}
+
+// isBreak 文本需要换行分隔的元素
+func isBreak(a atom.Atom) bool {
+	switch a {
+	case atom.Br, atom.P, atom.Div, atom.Li, atom.Tr, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Blockquote, atom.Pre, atom.Section, atom.Article, atom.Header, atom.Footer, atom.Hr:
+		return true
+	}
+	return false
+}
+
+// newline 追加换行，已以换行结尾或为空时不重复追加
+func newline(b *strings.Builder) {
+	if s := b.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		b.WriteByte('\n')
+	}
+}
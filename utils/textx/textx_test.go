@@ -0,0 +1,68 @@
+package textx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripHTML(t *testing.T) {
+	cases := map[string]string{
+		"plain text":                            "plain text",
+		"  a &amp; b ":                          "a &amp; b",
+		`<p>hello <b>world</b></p><p>bye</p>`:   "hello world\nbye",
+		`x<script>alert("1")</script>y`:         "xy",
+		`<style>p{color:red}</style>正文<br/>第二行`: "正文\n第二行",
+		`<img src=x onerror=alert(1)>图`:         "图",
+		`<!-- 注释 -->&lt;tag&gt;`:                "&lt;tag&gt;",
+		`<a href="javascript:alert(1)">链接</a>`:  "链接",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, StripHTML(in), in)
+	}
+	// 输出不能含有可执行的标签：实体还原、原始文本元素、被标签拆开的标签名
+	for in, want := range map[string]string{
+		`&lt;script&gt;alert(1)&lt;/script&gt;`:             "&lt;script&gt;alert(1)&lt;/script&gt;",
+		`<textarea><script>alert(1)</script></textarea>文本`:  "文本",
+		`<noembed><img src=x onerror=alert(1)></noembed>文本`: "文本",
+		`<xmp><b>x</b></xmp><plaintext><script>alert(1)`:    "",
+		`<<b>script>alert(1)<</b>/script>`:                  "&lt;script&gt;alert(1)&lt;/script&gt;",
+	} {
+		out := StripHTML(in)
+		assert.Equal(t, want, out, in)
+		assert.NotContains(t, out, "<", in)
+	}
+	assert.Equal(t, "a & b", UnescapeHTML(StripHTML("<p>a &amp; b</p>")))
+	assert.Equal(t, "&lt;b&gt;&#34;x&#34;", EscapeHTML(`<b>"x"`))
+	assert.Equal(t, `<b>"x"`, UnescapeHTML(EscapeHTML(`<b>"x"`)))
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "ABC 123!@", ToHalfWidth("ＡＢＣ\u3000１２３！＠"))
+	assert.Equal(t, "中文,标点?句号。、カタカナ", ToHalfWidth("中文，标点？句号。、カタカナ"))
+
+	assert.Equal(t, "敏感词", RemoveZeroWidth("敏\u200B感\u200D词\uFEFF"))
+	family := "\U0001F468\u200D\U0001F469\u200D\U0001F467"
+	assert.Equal(t, family, RemoveZeroWidth(family))
+	assert.Equal(t, "admin", RemoveZeroWidth("\u202Eadmin\u202C"))
+
+	assert.Equal(t, "Hello 世界\n第二行", Normalize(" \u200BＨｅｌｌｏ\u3000世界\r\n第二行\x00 "))
+	assert.Equal(t, "é", Normalize("e\u0301"))
+}
+
+func TestRuneLenAndTruncate(t *testing.T) {
+	assert.Equal(t, 4, RuneLen("中文ab"))
+	assert.Equal(t, "中文", Truncate("中文ab", 2))
+	assert.Equal(t, "中文ab", Truncate("中文ab", 10))
+	assert.Equal(t, "", Truncate("中文ab", 0))
+}
+
+func TestEmoji(t *testing.T) {
+	for _, s := range []string{"hi😀", "❤\uFE0F", "🇨🇳", "⭐", "👍🏻", "1\uFE0F\u20E3"} {
+		assert.True(t, ContainsEmoji(s), s)
+	}
+	for _, s := range []string{"普通昵称", "abc_123", "©®™", "【公告】"} {
+		assert.False(t, ContainsEmoji(s), s)
+	}
+	assert.Equal(t, "好评", RemoveEmoji("好评👍🏻\U0001F468\u200D\U0001F469"))
+}
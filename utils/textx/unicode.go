@@ -0,0 +1,156 @@
+package textx
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ToHalfWidth 全角 ASCII 字符（ＡＢＣ１２３！等，U+FF01–U+FF5E）与全角空格转为半角；
+// 全角逗号、问号等也会转为半角，而句号（。）、顿号（、）等中文标点与中日韩文字保持不变
+func ToHalfWidth(s string) string {
+	i := strings.IndexFunc(s, isFullWidth)
+	if i < 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	b.WriteString(s[:i])
+	for _, r := range s[i:] {
+		b.WriteRune(HalfWidthRune(r))
+	}
+	return b.String()
+}
+
+// HalfWidthRune 单个字符的全角转半角，规则同 ToHalfWidth
+func HalfWidthRune(r rune) rune {
+	switch {
+	case r == '\u3000':
+		return ' '
+	case r >= '！' && r <= '～':
+		return r - 0xFEE0
+	}
+	return r
+}
+
+// isFullWidth 是否为需要转换的全角字符
+func isFullWidth(r rune) bool {
+	return r == '\u3000' || (r >= '！' && r <= '～')
+}
+
+// zwj 零宽连接符，表情序列依赖它组合
+const zwj = '\u200D'
+
+// IsZeroWidth 是否为不可见的零宽/格式字符，常被用来绕过关键词过滤或伪造相同的昵称
+func IsZeroWidth(r rune) bool {
+	switch r {
+	case '\u200B', // 零宽空格
+		'\u200C',           // 零宽非连接符
+		zwj,                // 零宽连接符
+		'\u200E', '\u200F', // 从左到右/从右到左标记
+		'\u2060',                                         // 单词连接符
+		'\uFEFF',                                         // BOM / 零宽不换行空格
+		'\u00AD',                                         // 软连字符
+		'\u180E',                                         // 蒙古文元音分隔符
+		'\u202A', '\u202B', '\u202C', '\u202D', '\u202E', // 双向文本嵌入与覆盖
+		'\u2066', '\u2067', '\u2068', '\u2069': // 双向文本隔离
+		return true
+	}
+	return false
+}
+
+// RemoveZeroWidth 去除零宽与双向控制字符；位于两个表情之间的零宽连接符（如家庭、职业等组合表情）予以保留
+func RemoveZeroWidth(s string) string {
+	if strings.IndexFunc(s, IsZeroWidth) < 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	var prev rune
+	for i, r := range s {
+		if IsZeroWidth(r) {
+			if r == zwj && IsEmoji(prev) {
+				if next, _ := utf8.DecodeRuneInString(s[i+utf8.RuneLen(r):]); IsEmoji(next) {
+					b.WriteRune(r)
+				}
+			}
+			continue
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// Normalize 入库前的统一规范化：NFC 组合、全角转半角、去除零宽字符、去除其他控制字符（保留换行与制表符）并去除首尾空白
+func Normalize(s string) string {
+	s = ToHalfWidth(RemoveZeroWidth(norm.NFC.String(s)))
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
+
+// RuneLen 按字符（而非字节）计算长度，一个汉字或一个表情码点计为 1
+func RuneLen(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// Truncate 截取前 n 个字符，不会截断多字节字符；不足 n 个字符时原样返回
+func Truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos]
+		}
+		i++
+	}
+	return s
+}
+
+// IsEmoji 是否为表情符号码点，包括国旗区域指示符、肤色修饰符与表情变体选择符
+func IsEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // 麻将牌、扑克、带圈字母、国旗、各类表情与符号
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // 杂项符号与装饰符号（☀ ✂ ❤ 等）
+		return true
+	case r >= 0x2300 && r <= 0x23FF: // 技术符号中的 ⌚ ⏰ ⏳ 等
+		return r == 0x231A || r == 0x231B || r == 0x2328 || r == 0x23CF || (r >= 0x23E9 && r <= 0x23FA)
+	case r == 0x2B50, r == 0x2B55, r == 0x2B1B, r == 0x2B1C, r >= 0x2B05 && r <= 0x2B07: // ⭐ ⭕ ⬛ ⬜ ⬅ ⬆ ⬇
+		return true
+	case r == 0x3030, r == 0x303D, r == 0x3297, r == 0x3299: // 〰 〽 ㊗ ㊙
+		return true
+	case r == 0xFE0F, r == 0x20E3: // 表情变体选择符、组合用键帽
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // 旗帜标签序列
+		return true
+	}
+	return false
+}
+
+// ContainsEmoji 是否包含表情符号，常用于昵称、用户名等不允许表情的字段
+func ContainsEmoji(s string) bool {
+	return strings.IndexFunc(s, IsEmoji) >= 0
+}
+
+// RemoveEmoji 去除所有表情符号及其连接符
+func RemoveEmoji(s string) string {
+	if !ContainsEmoji(s) {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if IsEmoji(r) || r == zwj {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,261 @@
+// Package timewheel 哈希时间轮：以固定精度（tick）批量调度大量进程内延迟回调（超时检测、延迟重试等），
+// 相比每项一个 time.AfterFunc，调度与取消都是 O(1) 且只占用一个后台协程；精度为一个 tick，不适合需要毫秒级精确的场景
+// 使用示例：
+//
+//	tw := timewheel.New(timewheel.WithTick(50 * time.Millisecond))
+//	tw.Start()
+//	defer tw.Stop()
+//	t := tw.AfterFunc(3*time.Second, func() { conn.Close() })
+//	// 收到响应后取消
+//	t.Stop()
+package timewheel
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/qingfeng-studio/go-utils/utils/metrics"
+)
+
+// 默认值
+const (
+	defaultTick  = 100 * time.Millisecond
+	defaultSlots = 512
+)
+
+// ErrStopped 时间轮已停止
+var ErrStopped = errors.New("timewheel: stopped")
+
+// Option 函数式选项
+type Option func(*Wheel)
+
+// WithTick 设置精度，回调最多延迟一个 tick 执行，默认 100ms
+func WithTick(d time.Duration) Option {
+	return func(w *Wheel) {
+		if d > 0 {
+			w.tick = d
+		}
+	}
+}
+
+// WithSlots 设置槽位数，延迟超过 tick*slots 的任务需要多轮才会到期，默认 512
+func WithSlots(n int) Option {
+	return func(w *Wheel) {
+		if n > 0 {
+			w.slots = n
+		}
+	}
+}
+
+// Timer 时间轮中的一个延迟任务
+type Timer struct {
+	w        *Wheel
+	fn       func()
+	deadline int64 // 到期的 tick 序号
+
+	b          atomic.Pointer[bucket] // 所在槽位，到期或取消后为 nil
+	prev, next *Timer                 // 槽位内的双向链表，由槽位锁保护
+}
+
+// Stop 取消任务，任务尚未执行时返回 true；与 time.Timer.Stop 一样，返回 false 时回调可能已经开始执行
+func (t *Timer) Stop() bool {
+	b := t.b.Load()
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// 任务不会在槽位间移动，加锁后仍在原槽位说明尚未到期或被取消
+	if t.b.Load() != b {
+		return false
+	}
+	b.remove(t)
+	t.w.pending.Add(-1)
+	t.w.canceled.Add(1)
+	return true
+}
+
+// bucket 一个槽位
+type bucket struct {
+	mu   sync.Mutex
+	head *Timer
+}
+
+// add 在链表头部插入，调用方持有锁
+func (b *bucket) add(t *Timer) {
+	t.b.Store(b)
+	t.prev, t.next = nil, b.head
+	if b.head != nil {
+		b.head.prev = t
+	}
+	b.head = t
+}
+
+// remove 从链表中移除，调用方持有锁
+func (b *bucket) remove(t *Timer) {
+	if t.prev != nil {
+		t.prev.next = t.next
+	} else {
+		b.head = t.next
+	}
+	if t.next != nil {
+		t.next.prev = t.prev
+	}
+	t.b.Store(nil)
+	t.prev, t.next = nil, nil
+}
+
+// Stats 运行统计
+type Stats struct {
+	Pending  int64 // 等待执行的任务数
+	Fired    int64 // 已执行的任务数
+	Canceled int64 // 已取消的任务数
+}
+
+// Wheel 哈希时间轮，并发安全
+type Wheel struct {
+	tick  time.Duration
+	slots int
+
+	buckets []*bucket
+	start   time.Time
+	current atomic.Int64 // 已处理到的 tick 序号
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopped   atomic.Bool
+	done      chan struct{}
+	exited    chan struct{}
+
+	pending, fired, canceled atomic.Int64
+}
+
+// New 创建时间轮，需调用 Start 后才会执行到期的任务
+func New(opts ...Option) *Wheel {
+	w := &Wheel{tick: defaultTick, slots: defaultSlots, done: make(chan struct{}), exited: make(chan struct{})}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.buckets = make([]*bucket, w.slots)
+	for i := range w.buckets {
+		w.buckets[i] = &bucket{}
+	}
+	w.start = time.Now()
+	return w
+}
+
+// Start 启动后台协程，重复调用无副作用
+func (w *Wheel) Start() {
+	w.startOnce.Do(func() { go w.run() })
+}
+
+// Stop 停止时间轮，未到期的任务不再执行；Stop 之后 AfterFunc 返回 nil
+func (w *Wheel) Stop() {
+	w.stopOnce.Do(func() {
+		w.stopped.Store(true)
+		close(w.done)
+	})
+	w.startOnce.Do(func() { close(w.exited) }) // 未启动时没有后台协程需要等待
+	<-w.exited
+}
+
+// AfterFunc 在 d 之后（按 tick 向上取整）于独立协程中执行 fn，时间轮已停止时返回 nil
+func (w *Wheel) AfterFunc(d time.Duration, fn func()) *Timer {
+	if w.stopped.Load() {
+		return nil
+	}
+	deadline := int64((time.Since(w.start) + d + w.tick - 1) / w.tick)
+	t := &Timer{w: w, fn: fn, deadline: deadline}
+	w.pending.Add(1)
+	for {
+		// 不早于下一个待处理的 tick，避免落入刚处理过的槽位而多等一轮
+		if cur := w.current.Load(); t.deadline <= cur {
+			t.deadline = cur + 1
+		}
+		b := w.buckets[t.deadline%int64(w.slots)]
+		b.mu.Lock()
+		if t.deadline <= w.current.Load() {
+			b.mu.Unlock()
+			continue
+		}
+		b.add(t)
+		b.mu.Unlock()
+		return t
+	}
+}
+
+// Stats 返回运行统计
+func (w *Wheel) Stats() Stats {
+	return Stats{Pending: w.pending.Load(), Fired: w.fired.Load(), Canceled: w.canceled.Load()}
+}
+
+// RegisterMetrics 将运行统计注册到 r（nil 时为 metrics.Default），指标名为 <prefix>_timers_pending、
+// <prefix>_timers_fired_total、<prefix>_timers_canceled_total
+func (w *Wheel) RegisterMetrics(r *metrics.Registry, prefix string) error {
+	if r == nil {
+		r = metrics.Default
+	}
+	// 三个指标一起注册，任一失败时回滚已注册的部分，避免留下不完整的一组
+	cs := []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: prefix + "_timers_pending", Help: "Number of timers waiting to fire."},
+			func() float64 { return float64(w.pending.Load()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{Name: prefix + "_timers_fired_total", Help: "Total number of timers fired."},
+			func() float64 { return float64(w.fired.Load()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{Name: prefix + "_timers_canceled_total", Help: "Total number of timers canceled."},
+			func() float64 { return float64(w.canceled.Load()) }),
+	}
+	for i, c := range cs {
+		if err := r.Register(c); err != nil {
+			for _, done := range cs[:i] {
+				r.Unregister(done)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// run 每个 tick 处理一个槽位，处理落后时（如 GC 停顿）连续追赶
+func (w *Wheel) run() {
+	defer close(w.exited)
+	ticker := time.NewTicker(w.tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+		target := int64(time.Since(w.start) / w.tick)
+		for cur := w.current.Load(); cur < target; cur++ {
+			w.advance(cur + 1)
+		}
+	}
+}
+
+// advance 处理第 tick 个槽位：到期的任务移出并执行，未到期（多轮之后）的保留
+func (w *Wheel) advance(tick int64) {
+	b := w.buckets[tick%int64(w.slots)]
+	var due []*Timer
+	b.mu.Lock()
+	w.current.Store(tick)
+	for t := b.head; t != nil; {
+		next := t.next
+		if t.deadline <= tick {
+			b.remove(t)
+			due = append(due, t)
+		}
+		t = next
+	}
+	b.mu.Unlock()
+
+	for _, t := range due {
+		w.pending.Add(-1)
+		w.fired.Add(1)
+		go t.fn()
+	}
+}
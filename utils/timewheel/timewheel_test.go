@@ -0,0 +1,91 @@
+package timewheel
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/qingfeng-studio/go-utils/utils/metrics"
+)
+
+func TestWheel_AfterFunc(t *testing.T) {
+	w := New(WithTick(5*time.Millisecond), WithSlots(8))
+	w.Start()
+	defer w.Stop()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	var shortAt, longAt time.Duration
+	w.AfterFunc(20*time.Millisecond, func() { shortAt = time.Since(start); wg.Done() })
+	// 超过一轮（8 * 5ms）的任务
+	w.AfterFunc(70*time.Millisecond, func() { longAt = time.Since(start); wg.Done() })
+	wg.Wait()
+	if shortAt < 20*time.Millisecond || longAt < 70*time.Millisecond {
+		t.Errorf("fired too early: %v %v", shortAt, longAt)
+	}
+	if s := w.Stats(); s.Fired != 2 || s.Pending != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestWheel_Stop(t *testing.T) {
+	w := New(WithTick(5 * time.Millisecond))
+	w.Start()
+
+	var fired atomic.Int32
+	timers := make([]*Timer, 1000)
+	for i := range timers {
+		timers[i] = w.AfterFunc(30*time.Millisecond, func() { fired.Add(1) })
+	}
+	for i := 0; i < len(timers); i += 2 {
+		if !timers[i].Stop() {
+			t.Fatal("Stop should succeed before the deadline")
+		}
+	}
+	if timers[0].Stop() {
+		t.Error("second Stop should return false")
+	}
+	time.Sleep(80 * time.Millisecond)
+	if got := fired.Load(); got != 500 {
+		t.Errorf("fired = %d, want 500", got)
+	}
+	if timers[1].Stop() {
+		t.Error("Stop after firing should return false")
+	}
+	if s := w.Stats(); s.Canceled != 500 || s.Fired != 500 || s.Pending != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+
+	w.Stop()
+	if w.AfterFunc(time.Millisecond, func() {}) != nil {
+		t.Error("AfterFunc after Stop should return nil")
+	}
+	New().Stop() // 未启动时 Stop 不应阻塞
+}
+
+func TestWheel_RegisterMetrics(t *testing.T) {
+	w := New()
+	w.AfterFunc(time.Hour, func() {})
+	r := metrics.NewRegistry()
+	if err := w.RegisterMetrics(r, "retry"); err != nil {
+		t.Fatal(err)
+	}
+	families, err := r.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, f := range families {
+		if f.GetName() == "retry_timers_pending" && f.GetMetric()[0].GetGauge().GetValue() == 1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("pending gauge should report 1")
+	}
+	if err := w.RegisterMetrics(r, "retry"); err == nil {
+		t.Error("expected duplicate registration error")
+	}
+}
@@ -0,0 +1,153 @@
+// Package useragent 轻量级 User-Agent 解析：操作系统、浏览器（含微信/QQ/UC 等国内常见应用）、设备类型与爬虫识别
+// 规则覆盖主流客户端，不追求完整性；需要精确识别设备型号时请使用专门的 UA 库
+package useragent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 设备类型
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// UserAgent 解析结果
+type UserAgent struct {
+	OS             string // Windows、macOS、iOS、Android、HarmonyOS、Linux、ChromeOS
+	OSVersion      string
+	Browser        string // Chrome、Safari、Firefox、Edge、WeChat、QQBrowser 等
+	BrowserVersion string
+	Device         string // desktop、mobile、tablet、bot、unknown
+	Bot            bool
+	BotName        string
+}
+
+// IsMobile 是否为手机或平板
+func (u UserAgent) IsMobile() bool {
+	return u.Device == DeviceMobile || u.Device == DeviceTablet
+}
+
+// botPattern 常见爬虫、监控与命令行客户端。名称须是完整单词，避免误伤含 bot、curl 等片段的普通产品名（如 Cubot 手机）；
+// 未列出的爬虫须带版本号（如 ExampleBot/1.0），或以 bot、spider、crawler 为独立单词出现
+var botPattern = regexp.MustCompile(`(?i)\b(?:(googlebot|bingbot|baiduspider|yandexbot|sogou (?:web )?spider|360spider|bytespider|yisouspider|petalbot|applebot|duckduckbot|slurp|facebookexternalhit|twitterbot|semrushbot|ahrefsbot|mj12bot|headlesschrome|phantomjs|python-requests|python-urllib|go-http-client|okhttp|curl|wget|httpclient|uptimerobot|pingdom)\b|(java|[a-z][\w-]*(?:bot|spider|crawler))/|(bot|spider|crawler)\b)`)
+
+// rule 识别规则，按顺序匹配，version 用于规范化版本号
+type rule struct {
+	name    string
+	re      *regexp.Regexp
+	version func(string) string
+}
+
+// 操作系统规则，iPad 需在 iPhone 之前，iOS 需在 macOS 之前（iOS UA 含 "like Mac OS X"）
+var osRules = []rule{
+	{name: "HarmonyOS", re: regexp.MustCompile(`HarmonyOS[ /]?([\d.]+)?`)},
+	{name: "iPadOS", re: regexp.MustCompile(`iPad.*?OS ([\d_]+)`), version: underscoreToDot},
+	{name: "iOS", re: regexp.MustCompile(`(?:iPhone|CPU) OS ([\d_]+)`), version: underscoreToDot},
+	{name: "Android", re: regexp.MustCompile(`Android[ /]?([\d.]+)?`)},
+	{name: "Windows", re: regexp.MustCompile(`Windows NT ([\d.]+)`), version: windowsVersion},
+	{name: "macOS", re: regexp.MustCompile(`Mac OS X ([\d_.]+)`), version: underscoreToDot},
+	{name: "ChromeOS", re: regexp.MustCompile(`CrOS \S+ ([\d.]+)`)},
+	{name: "Linux", re: regexp.MustCompile(`Linux`)},
+}
+
+// 浏览器规则，App 内置浏览器与国产浏览器需放在 Chrome/Safari 之前
+var browserRules = []rule{
+	{name: "WeChat", re: regexp.MustCompile(`MicroMessenger/([\d.]+)`)},
+	{name: "DingTalk", re: regexp.MustCompile(`DingTalk/([\d.]+)`)},
+	{name: "Alipay", re: regexp.MustCompile(`AlipayClient/([\d.]+)`)},
+	{name: "QQ", re: regexp.MustCompile(`\bQQ/([\d.]+)`)},
+	{name: "QQBrowser", re: regexp.MustCompile(`M?QQBrowser/([\d.]+)`)},
+	{name: "UCBrowser", re: regexp.MustCompile(`UC ?Browser/([\d.]+)`)},
+	{name: "Quark", re: regexp.MustCompile(`Quark/([\d.]+)`)},
+	{name: "Baidu", re: regexp.MustCompile(`baiduboxapp/([\d.]+)`)},
+	{name: "Edge", re: regexp.MustCompile(`Edg(?:e|A|iOS)?/([\d.]+)`)},
+	{name: "Opera", re: regexp.MustCompile(`(?:OPR|Opera)/([\d.]+)`)},
+	{name: "SamsungBrowser", re: regexp.MustCompile(`SamsungBrowser/([\d.]+)`)},
+	{name: "Firefox", re: regexp.MustCompile(`(?:Firefox|FxiOS)/([\d.]+)`)},
+	{name: "Chrome", re: regexp.MustCompile(`(?:Chrome|CriOS)/([\d.]+)`)},
+	{name: "Safari", re: regexp.MustCompile(`Version/([\d.]+).*Safari/`)},
+	{name: "IE", re: regexp.MustCompile(`(?:MSIE |Trident/.*rv:)([\d.]+)`)},
+}
+
+// Parse 解析 User-Agent 字符串
+func Parse(ua string) UserAgent {
+	var u UserAgent
+	if ua == "" {
+		u.Device = DeviceUnknown
+		return u
+	}
+	if m := botPattern.FindStringSubmatch(ua); m != nil {
+		u.Bot = true
+		u.BotName = strings.ToLower(m[1] + m[2] + m[3])
+	}
+
+	u.OS, u.OSVersion = match(osRules, ua)
+	u.Browser, u.BrowserVersion = match(browserRules, ua)
+
+	switch {
+	case u.Bot:
+		u.Device = DeviceBot
+	case strings.Contains(ua, "iPad") || (strings.Contains(ua, "Android") && !strings.Contains(ua, "Mobile")) || strings.Contains(ua, "Tablet"):
+		u.Device = DeviceTablet
+	case strings.Contains(ua, "Mobile") || strings.Contains(ua, "iPhone") || u.OS == "Android" || u.OS == "HarmonyOS":
+		u.Device = DeviceMobile
+	case u.OS != "":
+		u.Device = DeviceDesktop
+	default:
+		u.Device = DeviceUnknown
+	}
+	return u
+}
+
+// IsBot 快速判断是否为爬虫或脚本客户端
+func IsBot(ua string) bool {
+	return ua == "" || botPattern.MatchString(ua)
+}
+
+// match 按顺序匹配规则，返回名称与版本
+func match(rules []rule, ua string) (string, string) {
+	for _, r := range rules {
+		m := r.re.FindStringSubmatch(ua)
+		if m == nil {
+			continue
+		}
+		var v string
+		if len(m) > 1 {
+			v = m[1]
+		}
+		if r.version != nil {
+			v = r.version(v)
+		}
+		return r.name, v
+	}
+	return "", ""
+}
+
+// underscoreToDot iOS/macOS 版本号中的下划线转为点号
+func underscoreToDot(v string) string {
+	return strings.ReplaceAll(v, "_", ".")
+}
+
+// windowsVersion Windows NT 内核版本映射为产品版本
+func windowsVersion(nt string) string {
+	switch nt {
+	case "10.0":
+		return "10" // Windows 11 的 UA 同样为 NT 10.0
+	case "6.3":
+		return "8.1"
+	case "6.2":
+		return "8"
+	case "6.1":
+		return "7"
+	case "6.0":
+		return "Vista"
+	case "5.1", "5.2":
+		return "XP"
+	}
+	return nt
+}
@@ -0,0 +1,67 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		ua      string
+		os      string
+		osVer   string
+		browser string
+		bVer    string
+		device  string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			"Windows", "10", "Chrome", "120.0.0.0", DeviceDesktop},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			"macOS", "10.15.7", "Safari", "17.1", DeviceDesktop},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 MicroMessenger/8.0.42(0x18002a2a) NetType/WIFI Language/zh_CN",
+			"iOS", "17.0", "WeChat", "8.0.42", DeviceMobile},
+		{"Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1",
+			"iPadOS", "16.6", "Safari", "16.6", DeviceTablet},
+		{"Mozilla/5.0 (Linux; Android 13; SM-S9180) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0.0.0 Mobile Safari/537.36 EdgA/116.0.1938.72",
+			"Android", "13", "Edge", "116.0.1938.72", DeviceMobile},
+		{"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			"Linux", "", "Firefox", "121.0", DeviceDesktop},
+	}
+	for _, c := range cases {
+		u := Parse(c.ua)
+		assert.Equal(t, c.os, u.OS, c.ua)
+		assert.Equal(t, c.osVer, u.OSVersion, c.ua)
+		assert.Equal(t, c.browser, u.Browser, c.ua)
+		assert.Equal(t, c.bVer, u.BrowserVersion, c.ua)
+		assert.Equal(t, c.device, u.Device, c.ua)
+		assert.False(t, u.Bot, c.ua)
+	}
+}
+
+func TestBot(t *testing.T) {
+	for _, ua := range []string{
+		"Mozilla/5.0 (compatible; Baiduspider/2.0; +http://www.baidu.com/search/spider.html)",
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		"curl/8.4.0",
+		"Go-http-client/1.1",
+		"Mozilla/5.0 (compatible; ExampleBot/1.0; +https://example.com)",
+		"Java/17.0.2",
+		"",
+	} {
+		assert.True(t, IsBot(ua), ua)
+	}
+	for _, ua := range []string{
+		"Mozilla/5.0 (Linux; Android 10; CUBOT X30) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+		"Mozilla/5.0 (Linux; Android 12; Robotics Tab) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 curly-toolbar",
+	} {
+		assert.False(t, IsBot(ua), ua)
+	}
+	assert.Equal(t, "examplebot", Parse("Mozilla/5.0 (compatible; ExampleBot/1.0)").BotName)
+	assert.Equal(t, "java", Parse("Java/17.0.2").BotName)
+	u := Parse("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+	assert.Equal(t, DeviceBot, u.Device)
+	assert.Equal(t, "googlebot", u.BotName)
+	assert.False(t, IsBot("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"))
+}